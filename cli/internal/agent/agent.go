@@ -11,6 +11,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/philjestin/boatman-ecosystem/harness/autofix"
+	"github.com/philjestin/boatman-ecosystem/harness/codegen"
+	"github.com/philjestin/boatman-ecosystem/harness/review/lint"
+	"github.com/philjestin/boatman-ecosystem/harness/scope"
 	"github.com/philjestin/boatmanmode/internal/brain"
 	"github.com/philjestin/boatmanmode/internal/config"
 	"github.com/philjestin/boatmanmode/internal/contextpin"
@@ -21,6 +25,7 @@ import (
 	"github.com/philjestin/boatmanmode/internal/executor"
 	"github.com/philjestin/boatmanmode/internal/github"
 	"github.com/philjestin/boatmanmode/internal/handoff"
+	"github.com/philjestin/boatmanmode/internal/issuetracker"
 	"github.com/philjestin/boatmanmode/internal/linear"
 	"github.com/philjestin/boatmanmode/internal/planner"
 	"github.com/philjestin/boatmanmode/internal/preflight"
@@ -39,6 +44,26 @@ type Agent struct {
 	// PreloadedPlan, if set, is used instead of running the planning agent.
 	// This allows triage-generated plans to be reused during execution.
 	PreloadedPlan *planner.Plan
+
+	// Budget, if set, caps tokens and time for a run. When it is exceeded the
+	// run stops, keeping any draft PR.
+	Budget *Budget
+
+	// RequestedChanges, if set, are review issues raised by a person (for
+	// example from the desktop diff view). ResumeWork uses them as the first
+	// review instead of running ScottBott, so the next refactor addresses them.
+	RequestedChanges []scottbott.Issue
+
+	// PostReview posts the final review to the pull request as a GitHub
+	// review with inline comments.
+	PostReview bool
+
+	// SARIFPath, if set, is where the run's review issues are written as a
+	// SARIF log for code-scanning upload.
+	SARIFPath string
+
+	// costTracker holds usage from the most recent Work or ResumeWork call.
+	costTracker *cost.Tracker
 }
 
 // WorkResult represents the outcome of the work command.
@@ -47,8 +72,12 @@ type WorkResult struct {
 	PRURL        string
 	Message      string
 	Iterations   int
+	ReviewPassed bool
 	TestsPassed  bool
 	TestCoverage float64
+
+	// BudgetExceeded is set when the run stopped early at its cost ceiling.
+	BudgetExceeded bool
 }
 
 // workContext holds state shared between workflow steps.
@@ -67,7 +96,18 @@ type workContext struct {
 	iterations   int
 	startTime    time.Time
 	costTracker  *cost.Tracker
-	draftPRURL   string // URL of draft PR created as safety checkpoint
+	draftPRURL   string           // URL of draft PR created as safety checkpoint
+	autofixes    []autofix.Change // formatter and autofixer changes across the run
+	codegenRuns  []codegen.Run    // generator runs across the run
+
+	scopeReport   *scope.Report // the changed files checked against the plan before the latest review
+	scopeReverted []string      // unplanned files reverted during the run
+
+	reviewHistory *issuetracker.IssueHistoryAdapter // issues across every review of the run
+
+	budgetWarned map[string]bool // budget limits that have already warned
+
+	staleCheckout bool // the worktree doesn't hold the reviewed change, so static analysis is skipped
 }
 
 // New creates a new Agent.
@@ -79,6 +119,15 @@ func New(cfg *config.Config) (*Agent, error) {
 	}, nil
 }
 
+// Usage returns the Claude usage accumulated by the most recent Work or
+// ResumeWork call, including runs that ended in an error.
+func (a *Agent) Usage() cost.Usage {
+	if a.costTracker == nil {
+		return cost.Usage{}
+	}
+	return a.costTracker.Total()
+}
+
 // Work executes the complete workflow for a task.
 // Orchestrates 9 steps: prepare → worktree → plan → validate → execute → test → review → commit → PR
 func (a *Agent) Work(ctx context.Context, t task.Task) (*WorkResult, error) {
@@ -87,11 +136,16 @@ func (a *Agent) Work(ctx context.Context, t task.Task) (*WorkResult, error) {
 		startTime:   time.Now(),
 		costTracker: cost.NewTracker(),
 	}
+	a.costTracker = wc.costTracker
 
 	// Start the coordinator
 	a.coordinator.Start(ctx)
 	defer a.coordinator.Stop()
 
+	// The time budget cancels whichever call is running when it runs out
+	ctx, cancel := a.budgetContext(ctx, wc)
+	defer cancel()
+
 	// Step 1: Prepare task (already received as parameter)
 	if err := a.stepPrepareTask(ctx, wc); err != nil {
 		return nil, err
@@ -113,17 +167,29 @@ func (a *Agent) Work(ctx context.Context, t task.Task) (*WorkResult, error) {
 
 	// Step 3: Planning (also loads matching brains)
 	if err := a.stepPlanning(ctx, wc); err != nil {
-		return nil, err
+		return a.stepFailed(ctx, wc, "planning", err)
+	}
+	if err := a.checkBudget(wc, "planning"); err != nil {
+		return a.budgetStop(wc, err)
 	}
 
 	// Step 4: Pre-flight validation
 	if err := a.stepPreflightValidation(ctx, wc); err != nil {
-		return nil, err
+		return a.stepFailed(ctx, wc, "validation", err)
 	}
 
 	// Step 5: Execute development task
 	if err := a.stepExecute(ctx, wc); err != nil {
-		return nil, err
+		return a.stepFailed(ctx, wc, "execution", err)
+	}
+
+	// Step 5a: Regenerate code from changed sources, then format and autofix
+	// the changed files
+	if err := a.runCodegen(ctx, wc, 0); err != nil {
+		return a.stepFailed(ctx, wc, "codegen", err)
+	}
+	if err := a.runAutofix(ctx, wc, 0); err != nil {
+		return a.stepFailed(ctx, wc, "autofix", err)
 	}
 
 	// Step 5b: Safety checkpoint — commit, push, and create a draft PR so work
@@ -132,15 +198,24 @@ func (a *Agent) Work(ctx context.Context, t task.Task) (*WorkResult, error) {
 		// Draft PR failure is non-fatal — log and continue
 		fmt.Printf("   ⚠️  Draft PR checkpoint failed: %v\n", err)
 	}
+	if err := a.checkBudget(wc, "execution"); err != nil {
+		return a.budgetStop(wc, err)
+	}
 
-	// Step 6: Run tests and initial review (parallel)
-	if err := a.stepTestAndReview(ctx, wc); err != nil {
-		return nil, err
+	// Step 5c: Check scope, then compile and repair; reverting unplanned files
+	// can break the build, so it happens first. Only code that compiles is
+	// tested and reviewed.
+	scopeIssues := a.checkScope(ctx, wc)
+	if a.stepCompile(ctx, wc) {
+		// Step 6: Run tests and initial review (parallel)
+		if err := a.stepTestAndReview(ctx, wc, scopeIssues); err != nil {
+			return a.stepFailed(ctx, wc, "test & review", err)
+		}
 	}
 
 	// Step 7: Review & refactor loop
 	if err := a.stepRefactorLoop(ctx, wc); err != nil {
-		return nil, err
+		return a.stepFailed(ctx, wc, "review & refactor", err)
 	}
 
 	// Post-workflow: auto-distill brains from accumulated signals
@@ -166,6 +241,7 @@ func (a *Agent) Work(ctx context.Context, t task.Task) (*WorkResult, error) {
 
 	// Check if review passed
 	if !wc.reviewResult.Passed {
+		a.writeSARIF(wc)
 		result := &WorkResult{
 			PRCreated:  false,
 			Message:    "Review did not pass after max iterations",
@@ -181,11 +257,15 @@ func (a *Agent) Work(ctx context.Context, t task.Task) (*WorkResult, error) {
 
 	// Step 8: Commit and push final reviewed changes
 	if err := a.stepCommitAndPush(ctx, wc); err != nil {
-		return nil, err
+		return a.stepFailed(ctx, wc, "commit", err)
 	}
 
 	// Step 9: Finalize PR (update body with review info, mark ready)
-	return a.stepFinalizePR(ctx, wc)
+	result, err := a.stepFinalizePR(ctx, wc)
+	if err != nil {
+		return a.stepFailed(ctx, wc, "pull request", err)
+	}
+	return result, nil
 }
 
 // ResumeWork resumes a previously failed execution from the review/refactor stage.
@@ -197,10 +277,15 @@ func (a *Agent) ResumeWork(ctx context.Context, t task.Task) (*WorkResult, error
 		startTime:   time.Now(),
 		costTracker: cost.NewTracker(),
 	}
+	a.costTracker = wc.costTracker
 
 	a.coordinator.Start(ctx)
 	defer a.coordinator.Stop()
 
+	// The time budget cancels whichever call is running when it runs out
+	ctx, cancel := a.budgetContext(ctx, wc)
+	defer cancel()
+
 	// Step 1: Display task info
 	if err := a.stepPrepareTask(ctx, wc); err != nil {
 		return nil, err
@@ -249,19 +334,36 @@ func (a *Agent) ResumeWork(ctx context.Context, t task.Task) (*WorkResult, error
 		return nil, fmt.Errorf("failed to stage changes: %w", err)
 	}
 
+	if err := a.runCodegen(ctx, wc, 0); err != nil {
+		return a.stepFailed(ctx, wc, "codegen", err)
+	}
+	if err := a.runAutofix(ctx, wc, 0); err != nil {
+		return a.stepFailed(ctx, wc, "autofix", err)
+	}
+
 	// Safety checkpoint — ensure draft PR exists for resumed runs too
 	if err := a.stepDraftPR(ctx, wc); err != nil {
 		fmt.Printf("   ⚠️  Draft PR checkpoint failed: %v\n", err)
 	}
 
-	// Step 6: Run tests and review
-	if err := a.stepTestAndReview(ctx, wc); err != nil {
-		return nil, err
+	// Step 6: Run tests and review, or start from the requested changes.
+	// Scope is checked before compiling, since reverting unplanned files can
+	// break the build; code that doesn't compile after repair isn't tested or
+	// reviewed.
+	if len(a.RequestedChanges) > 0 {
+		a.stepRequestedChanges(wc)
+	} else {
+		scopeIssues := a.checkScope(ctx, wc)
+		if a.stepCompile(ctx, wc) {
+			if err := a.stepTestAndReview(ctx, wc, scopeIssues); err != nil {
+				return a.stepFailed(ctx, wc, "test & review", err)
+			}
+		}
 	}
 
 	// Step 7: Review & refactor loop
 	if err := a.stepRefactorLoop(ctx, wc); err != nil {
-		return nil, err
+		return a.stepFailed(ctx, wc, "review & refactor", err)
 	}
 
 	// Release context pins
@@ -269,6 +371,7 @@ func (a *Agent) ResumeWork(ctx context.Context, t task.Task) (*WorkResult, error
 
 	// Check if review passed
 	if !wc.reviewResult.Passed {
+		a.writeSARIF(wc)
 		result := &WorkResult{
 			PRCreated:  false,
 			Message:    "Review did not pass after max iterations",
@@ -284,11 +387,15 @@ func (a *Agent) ResumeWork(ctx context.Context, t task.Task) (*WorkResult, error
 
 	// Step 8: Commit and push
 	if err := a.stepCommitAndPush(ctx, wc); err != nil {
-		return nil, err
+		return a.stepFailed(ctx, wc, "commit", err)
 	}
 
 	// Step 9: Finalize PR
-	return a.stepFinalizePR(ctx, wc)
+	result, err := a.stepFinalizePR(ctx, wc)
+	if err != nil {
+		return a.stepFailed(ctx, wc, "pull request", err)
+	}
+	return result, nil
 }
 
 // stepResumeWorktree finds an existing worktree for the task's branch.
@@ -585,8 +692,9 @@ func (a *Agent) stepExecute(ctx context.Context, wc *workContext) error {
 	return nil
 }
 
-// stepTestAndReview runs tests and initial review in parallel (Step 6).
-func (a *Agent) stepTestAndReview(ctx context.Context, wc *workContext) error {
+// stepTestAndReview runs tests and initial review in parallel (Step 6),
+// adding scopeIssues from the scope check to the review.
+func (a *Agent) stepTestAndReview(ctx context.Context, wc *workContext, scopeIssues []scottbott.Issue) error {
 	testAgentID := fmt.Sprintf("test-%s", wc.task.GetID())
 	reviewAgentID := fmt.Sprintf("review-1-%s", wc.task.GetID())
 
@@ -625,30 +733,38 @@ func (a *Agent) stepTestAndReview(ctx context.Context, wc *workContext) error {
 	go func() {
 		defer wg.Done()
 		events.AgentStarted(reviewAgentID, "Code Review #1", "Reviewing code quality and best practices")
-		reviewHandoff := handoff.NewReviewHandoff(wc.task, initialDiff, wc.execResult.FilesChanged)
-		reviewer := scottbott.NewWithSkill(wc.worktree.Path, 1, a.config.ReviewSkill, a.config)
-		reviewResult, usage, _ := reviewer.Review(ctx, reviewHandoff.Concise(), initialDiff)
-		wc.reviewResult = reviewResult
+		if lintResult := a.staticReview(ctx, wc, initialDiff); lintResult != nil {
+			wc.setReview(withScopeIssues(lintResult, scopeIssues))
+			events.AgentCompletedWithData(reviewAgentID, "Code Review #1", "failed", reviewEventData(1, nil, map[string]any{
+				"feedback": lintResult.Summary,
+				"issues":   lintResult.Issues,
+			}))
+			return
+		}
+
+		reviewHandoff := a.newReviewHandoff(wc, initialDiff)
+		reviewResult, usage, _ := a.scottBottReview(ctx, wc, 1, reviewHandoff.Concise(), initialDiff)
+		wc.setReview(withScopeIssues(reviewResult, scopeIssues))
 		if usage != nil {
-			wc.costTracker.Add("Review #1", *usage)
+			wc.costTracker.Add(reviewStep(1), *usage)
 		}
 		if reviewResult != nil && reviewResult.Passed {
 			feedback := reviewResult.Summary
 			if feedback == "" && len(reviewResult.Issues) > 0 {
 				feedback = fmt.Sprintf("Found %d issues", len(reviewResult.Issues))
 			}
-			events.AgentCompletedWithData(reviewAgentID, "Code Review #1", "success", map[string]any{
+			events.AgentCompletedWithData(reviewAgentID, "Code Review #1", "success", reviewEventData(1, usage, map[string]any{
 				"feedback": feedback,
 				"issues":   reviewResult.Issues,
-			})
+			}))
 		} else {
 			feedback := ""
 			if reviewResult != nil {
 				feedback = reviewResult.Summary
 			}
-			events.AgentCompletedWithData(reviewAgentID, "Code Review #1", "failed", map[string]any{
+			events.AgentCompletedWithData(reviewAgentID, "Code Review #1", "failed", reviewEventData(1, usage, map[string]any{
 				"feedback": feedback,
-			})
+			}))
 		}
 	}()
 
@@ -668,6 +784,20 @@ func (a *Agent) stepTestAndReview(ctx context.Context, wc *workContext) error {
 	return nil
 }
 
+// stepRequestedChanges uses the requested changes as a failed first review
+// (Step 6 of a resumed run).
+func (a *Agent) stepRequestedChanges(wc *workContext) {
+	printStep(6, 9, "Loading requested changes")
+
+	wc.setReview(&scottbott.ReviewResult{
+		Passed:  false,
+		Summary: fmt.Sprintf("%d changes requested in review", len(a.RequestedChanges)),
+		Issues:  a.RequestedChanges,
+	})
+	fmt.Println(wc.reviewResult.FormatReview())
+	fmt.Println()
+}
+
 // stepRefactorLoop runs the review/refactor loop until passing or max iterations (Step 7).
 func (a *Agent) stepRefactorLoop(ctx context.Context, wc *workContext) error {
 	printStep(7, 9, "Review & refactor loop")
@@ -683,6 +813,9 @@ func (a *Agent) stepRefactorLoop(ctx context.Context, wc *workContext) error {
 
 		// Use existing review for first iteration, get fresh review for subsequent
 		if wc.iterations > 1 || wc.reviewResult == nil {
+			if err := a.checkBudget(wc, fmt.Sprintf("refactor #%d", wc.iterations-1)); err != nil {
+				return err
+			}
 			if err := a.doReview(ctx, wc, &previousDiff); err != nil {
 				return err
 			}
@@ -724,6 +857,12 @@ func (a *Agent) stepRefactorLoop(ctx context.Context, wc *workContext) error {
 		}
 
 		// Refactor based on feedback
+		// Stop before another Claude call if the budget is spent; a run that
+		// already passed review above is allowed to finish.
+		if err := a.checkBudget(wc, fmt.Sprintf("review #%d", wc.iterations)); err != nil {
+			return err
+		}
+
 		if err := a.doRefactor(ctx, wc, previousDiff); err != nil {
 			return err
 		}
@@ -741,32 +880,108 @@ func (a *Agent) stepRefactorLoop(ctx context.Context, wc *workContext) error {
 	return nil
 }
 
-// doReview gets a fresh diff and runs the review.
+// doReview checks scope, compiles the changes, gets a fresh diff and runs
+// the review. Scope comes first so any unplanned files it reverts are
+// compiled without.
 func (a *Agent) doReview(ctx context.Context, wc *workContext, previousDiff *string) error {
+	scopeIssues := a.checkScope(ctx, wc)
+	compileResult := a.compileAndRepair(ctx, wc, wc.iterations)
+
 	diff, err := wc.exec.GetDiff()
 	if err != nil {
 		return fmt.Errorf("failed to get diff: %w", err)
 	}
 	fmt.Printf("   📏 Diff size: %d lines\n", strings.Count(diff, "\n"))
 
-	reviewHandoff := handoff.NewReviewHandoff(wc.task, diff, wc.execResult.FilesChanged)
-	reviewer := scottbott.NewWithSkill(wc.worktree.Path, wc.iterations, a.config.ReviewSkill, a.config)
-	reviewResult, usage, err := reviewer.Review(ctx, reviewHandoff.ForTokenBudget(handoff.DefaultBudget.Context), diff)
-	if err != nil {
-		return fmt.Errorf("review failed: %w", err)
+	reviewAgentID := fmt.Sprintf("review-%d-%s", wc.iterations, wc.task.GetID())
+	reviewName := fmt.Sprintf("Code Review #%d", wc.iterations)
+	events.AgentStarted(reviewAgentID, reviewName, "Reviewing the refactored changes")
+
+	if compileResult != nil {
+		fmt.Println(compileResult.FormatReview())
+		wc.setReview(compileResult)
+		*previousDiff = diff
+		events.AgentCompletedWithData(reviewAgentID, reviewName, "failed", reviewEventData(wc.iterations, nil, map[string]any{
+			"feedback": compileResult.Summary,
+			"issues":   compileResult.Issues,
+		}))
+		return nil
+	}
+
+	if lintResult := a.staticReview(ctx, wc, diff); lintResult != nil {
+		withScopeIssues(lintResult, scopeIssues)
+		fmt.Println(lintResult.FormatReview())
+		wc.setReview(lintResult)
+		*previousDiff = diff
+		events.AgentCompletedWithData(reviewAgentID, reviewName, "failed", reviewEventData(wc.iterations, nil, map[string]any{
+			"feedback": lintResult.Summary,
+			"issues":   lintResult.Issues,
+		}))
+		return nil
 	}
 
+	reviewHandoff := a.newReviewHandoff(wc, diff)
+	reviewResult, usage, err := a.scottBottReview(ctx, wc, wc.iterations, reviewHandoff.ForTokenBudget(handoff.DefaultBudget.Context), diff)
 	if usage != nil {
-		wc.costTracker.Add(fmt.Sprintf("Review #%d", wc.iterations), *usage)
+		wc.costTracker.Add(reviewStep(wc.iterations), *usage)
+	}
+	if err != nil {
+		events.AgentCompletedWithData(reviewAgentID, reviewName, "failed", reviewEventData(wc.iterations, usage, map[string]any{}))
+		return fmt.Errorf("review failed: %w", err)
 	}
 
+	withScopeIssues(reviewResult, scopeIssues)
 	fmt.Println(reviewResult.FormatReview())
-	wc.reviewResult = reviewResult
+	wc.setReview(reviewResult)
 	*previousDiff = diff
 
+	status := "failed"
+	if reviewResult.Passed {
+		status = "success"
+	}
+	events.AgentCompletedWithData(reviewAgentID, reviewName, status, reviewEventData(wc.iterations, usage, map[string]any{
+		"feedback": reviewResult.Summary,
+		"issues":   reviewResult.Issues,
+	}))
+
 	return nil
 }
 
+// staticReview runs the repository's configured linters on the diff. It
+// returns a failed review when they report errors, so the refactor can fix
+// them without spending a review call; otherwise it returns nil.
+func (a *Agent) staticReview(ctx context.Context, wc *workContext, diff string) *scottbott.ReviewResult {
+	if !a.config.Review.StaticAnalysis || wc.staleCheckout {
+		return nil
+	}
+	linter := lint.New(wc.worktree.Path)
+	if len(linter.Linters()) == 0 {
+		return nil
+	}
+
+	result, err := linter.Review(ctx, diff, "")
+	if err != nil {
+		fmt.Printf("   ⚠️  Static analysis error: %v\n", err)
+		return nil
+	}
+	fmt.Printf("   🔎 Static analysis: %s\n", result.Summary)
+	if result.Passed {
+		return nil
+	}
+
+	issues := make([]scottbott.Issue, len(result.Issues))
+	for i, issue := range result.Issues {
+		issues[i] = scottbott.ReviewIssueToIssue(issue)
+	}
+	return &scottbott.ReviewResult{
+		Passed:   false,
+		Score:    result.Score,
+		Summary:  result.Summary,
+		Issues:   issues,
+		Guidance: result.Guidance,
+	}
+}
+
 // doRefactor performs refactoring based on review feedback.
 func (a *Agent) doRefactor(ctx context.Context, wc *workContext, previousDiff string) error {
 	refactorAgentID := fmt.Sprintf("refactor-%d-%s", wc.iterations, wc.task.GetID())
@@ -826,6 +1041,14 @@ func (a *Agent) doRefactor(ctx context.Context, wc *workContext, previousDiff st
 		events.AgentCompleted(refactorAgentID, fmt.Sprintf("Refactoring #%d", wc.iterations), "failed")
 		return fmt.Errorf("failed to stage changes: %w", err)
 	}
+	if err := a.runCodegen(ctx, wc, wc.iterations); err != nil {
+		events.AgentCompleted(refactorAgentID, fmt.Sprintf("Refactoring #%d", wc.iterations), "failed")
+		return err
+	}
+	if err := a.runAutofix(ctx, wc, wc.iterations); err != nil {
+		events.AgentCompleted(refactorAgentID, fmt.Sprintf("Refactoring #%d", wc.iterations), "failed")
+		return err
+	}
 
 	// Get refactored diff for metadata
 	refactorDiff, _ := wc.exec.GetDiff()
@@ -916,6 +1139,9 @@ func (a *Agent) stepFinalizePR(ctx context.Context, wc *workContext) (*WorkResul
 		return nil, fmt.Errorf("failed to mark PR ready: %w", err)
 	}
 
+	a.postReview(ctx, wc, wc.draftPRURL)
+	a.writeSARIF(wc)
+
 	events.AgentCompleted(agentID, "Finalize PR", "success")
 	a.printWorkflowSummary(wc, wc.draftPRURL)
 
@@ -924,6 +1150,7 @@ func (a *Agent) stepFinalizePR(ctx context.Context, wc *workContext) (*WorkResul
 		PRURL:        wc.draftPRURL,
 		Message:      "Successfully finalized PR",
 		Iterations:   wc.iterations,
+		ReviewPassed: true,
 		TestsPassed:  wc.testResult == nil || wc.testResult.Passed,
 		TestCoverage: getTestCoverage(wc.testResult),
 	}, nil
@@ -949,7 +1176,7 @@ func (a *Agent) buildPRBody(wc *workContext) string {
 - Review iterations: %d
 - Tests: %s
 - Coverage: %.1f%%
-
+%s
 ---
 *Automated by BoatmanMode*
 `,
@@ -960,6 +1187,7 @@ func (a *Agent) buildPRBody(wc *workContext) string {
 			wc.iterations,
 			formatTestStatus(wc.testResult),
 			getTestCoverage(wc.testResult),
+			formatAutofixes(wc.autofixes)+formatCodegen(wc.codegenRuns)+formatScope(wc.scopeReport),
 		)
 	}
 
@@ -982,7 +1210,7 @@ func (a *Agent) buildPRBody(wc *workContext) string {
 - Review iterations: %d
 - Tests: %s
 - Coverage: %.1f%%
-
+%s
 ---
 *Automated by BoatmanMode*
 `,
@@ -993,6 +1221,7 @@ func (a *Agent) buildPRBody(wc *workContext) string {
 		wc.iterations,
 		formatTestStatus(wc.testResult),
 		getTestCoverage(wc.testResult),
+		formatAutofixes(wc.autofixes)+formatCodegen(wc.codegenRuns)+formatScope(wc.scopeReport),
 	)
 }
 
@@ -1060,7 +1289,7 @@ func (a *Agent) stepCreatePR(ctx context.Context, wc *workContext) (*WorkResult,
 - Review iterations: %d
 - Tests: %s
 - Coverage: %.1f%%
-
+%s
 ---
 *Automated by BoatmanMode 🚣*
 `,
@@ -1072,6 +1301,7 @@ func (a *Agent) stepCreatePR(ctx context.Context, wc *workContext) (*WorkResult,
 			wc.iterations,
 			formatTestStatus(wc.testResult),
 			getTestCoverage(wc.testResult),
+			formatAutofixes(wc.autofixes)+formatCodegen(wc.codegenRuns)+formatScope(wc.scopeReport),
 		)
 	} else {
 		// Prompt/File mode - no ticket link
@@ -1095,7 +1325,7 @@ func (a *Agent) stepCreatePR(ctx context.Context, wc *workContext) (*WorkResult,
 - Review iterations: %d
 - Tests: %s
 - Coverage: %.1f%%
-
+%s
 ---
 *Automated by BoatmanMode 🚣*
 `,
@@ -1107,6 +1337,7 @@ func (a *Agent) stepCreatePR(ctx context.Context, wc *workContext) (*WorkResult,
 			wc.iterations,
 			formatTestStatus(wc.testResult),
 			getTestCoverage(wc.testResult),
+			formatAutofixes(wc.autofixes)+formatCodegen(wc.codegenRuns)+formatScope(wc.scopeReport),
 		)
 	}
 
@@ -1117,6 +1348,9 @@ func (a *Agent) stepCreatePR(ctx context.Context, wc *workContext) (*WorkResult,
 		return nil, fmt.Errorf("failed to create PR: %w", err)
 	}
 
+	a.postReview(ctx, wc, prResult.URL)
+	a.writeSARIF(wc)
+
 	events.AgentCompleted(agentID, "Create PR", "success")
 	a.printWorkflowSummary(wc, prResult.URL)
 
@@ -1125,6 +1359,7 @@ func (a *Agent) stepCreatePR(ctx context.Context, wc *workContext) (*WorkResult,
 		PRURL:        prResult.URL,
 		Message:      "Successfully created PR",
 		Iterations:   wc.iterations,
+		ReviewPassed: true,
 		TestsPassed:  wc.testResult == nil || wc.testResult.Passed,
 		TestCoverage: getTestCoverage(wc.testResult),
 	}, nil
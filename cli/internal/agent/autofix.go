@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/philjestin/boatman-ecosystem/harness/autofix"
+	"github.com/philjestin/boatmanmode/internal/events"
+)
+
+// runAutofix formats and autofixes the changed files and stages the result,
+// so the diff that is reviewed includes the fixes. Iteration 0 is the pass
+// after execution; later passes follow each refactor. Fixers that fail are
+// reported and skipped.
+func (a *Agent) runAutofix(ctx context.Context, wc *workContext, iteration int) error {
+	if !a.config.Autofix.Enabled {
+		return nil
+	}
+	fixer := autofix.New(wc.worktree.Path, a.config.Autofix.Tools...)
+	if len(fixer.Fixers()) == 0 {
+		return nil
+	}
+
+	agentID := fmt.Sprintf("autofix-%d-%s", iteration, wc.task.GetID())
+	events.AgentStarted(agentID, "Autofix", "Running formatters and autofixers on changed files")
+
+	result, err := fixer.Fix(ctx, wc.execResult.FilesChanged)
+	if err != nil {
+		events.AgentCompleted(agentID, "Autofix", "failed")
+		return fmt.Errorf("autofix failed: %w", err)
+	}
+	fmt.Printf("   🧹 Autofix: %s\n", result.Summary())
+	for _, c := range result.Changes {
+		fmt.Printf("      • %s: %s\n", c.Fixer, strings.Join(c.Files, ", "))
+	}
+
+	if len(result.Changes) > 0 {
+		wc.autofixes = append(wc.autofixes, result.Changes...)
+		wc.execResult.FilesChanged = mergeFiles(wc.execResult.FilesChanged, result.FilesChanged())
+		if err := wc.exec.StageChanges(); err != nil {
+			events.AgentCompleted(agentID, "Autofix", "failed")
+			return fmt.Errorf("failed to stage autofixes: %w", err)
+		}
+	}
+
+	events.AgentCompletedWithData(agentID, "Autofix", "success", map[string]any{
+		"changes": result.Changes,
+	})
+	return nil
+}
+
+// formatAutofixes lists which tools changed which files across the run, for
+// the PR body. It returns "" when nothing was fixed.
+func formatAutofixes(changes []autofix.Change) string {
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var order []string
+	files := map[string][]string{}
+	seen := map[string]bool{}
+	for _, c := range changes {
+		if _, ok := files[c.Fixer]; !ok {
+			order = append(order, c.Fixer)
+			files[c.Fixer] = nil
+		}
+		for _, f := range c.Files {
+			if !seen[c.Fixer+"\x00"+f] {
+				seen[c.Fixer+"\x00"+f] = true
+				files[c.Fixer] = append(files[c.Fixer], f)
+			}
+		}
+	}
+
+	var sb strings.Builder
+	for _, fixer := range order {
+		fmt.Fprintf(&sb, "- Autofix (%s): %s\n", fixer, strings.Join(files[fixer], ", "))
+	}
+	return sb.String()
+}
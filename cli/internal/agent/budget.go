@@ -0,0 +1,180 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/philjestin/boatmanmode/internal/cost"
+	"github.com/philjestin/boatmanmode/internal/events"
+)
+
+// defaultBudgetWarnAt is the fraction of a limit at which a warning is printed.
+const defaultBudgetWarnAt = 0.8
+
+// Budget caps the Claude tokens and wall-clock time of a single Work or
+// ResumeWork run. The token limit is checked between Claude calls, so a run
+// stops at the next step boundary after crossing it; the time limit is also a
+// deadline on the run's context, so it cuts off a Claude or executor call that
+// is still running. Zero disables a limit.
+type Budget struct {
+	MaxTokens   int
+	MaxDuration time.Duration
+
+	// WarnAt is the fraction of either limit that triggers a warning
+	// (default 0.8).
+	WarnAt float64
+}
+
+// BudgetExceededError is returned when a run crosses its Budget.
+type BudgetExceededError struct {
+	// Step is the workflow step that had just finished, or was cut off, when
+	// the limit was hit.
+	Step        string
+	Limit       string // "tokens" or "time"
+	TokensUsed  int
+	MaxTokens   int
+	Elapsed     time.Duration
+	MaxDuration time.Duration
+}
+
+func (e *BudgetExceededError) Error() string {
+	if e.Limit == "time" {
+		return fmt.Sprintf("cost ceiling exceeded after %s: ran %s of %s allowed",
+			e.Step, e.Elapsed.Round(time.Second), e.MaxDuration)
+	}
+	return fmt.Sprintf("cost ceiling exceeded after %s: used %d of %d tokens",
+		e.Step, e.TokensUsed, e.MaxTokens)
+}
+
+// IsBudgetExceeded reports whether err is, or wraps, a BudgetExceededError.
+func IsBudgetExceeded(err error) bool {
+	var be *BudgetExceededError
+	return errors.As(err, &be)
+}
+
+// budgetTokens counts the tokens a budget is charged for.
+func budgetTokens(u cost.Usage) int {
+	return u.InputTokens + u.OutputTokens
+}
+
+// check returns a BudgetExceededError if usage or elapsed time is over the
+// budget, and otherwise the warnings newly due (each limit warns once, as
+// tracked by warned).
+func (b *Budget) check(step string, tokens int, elapsed time.Duration, warned map[string]bool) (warnings []string, err error) {
+	if b == nil {
+		return nil, nil
+	}
+
+	if b.MaxTokens > 0 && tokens > b.MaxTokens {
+		return nil, &BudgetExceededError{Step: step, Limit: "tokens", TokensUsed: tokens, MaxTokens: b.MaxTokens, Elapsed: elapsed, MaxDuration: b.MaxDuration}
+	}
+	if b.MaxDuration > 0 && elapsed > b.MaxDuration {
+		return nil, &BudgetExceededError{Step: step, Limit: "time", TokensUsed: tokens, MaxTokens: b.MaxTokens, Elapsed: elapsed, MaxDuration: b.MaxDuration}
+	}
+
+	warnAt := b.WarnAt
+	if warnAt <= 0 || warnAt >= 1 {
+		warnAt = defaultBudgetWarnAt
+	}
+	if b.MaxTokens > 0 && !warned["tokens"] && float64(tokens) >= warnAt*float64(b.MaxTokens) {
+		warned["tokens"] = true
+		warnings = append(warnings, fmt.Sprintf("%d of %d tokens used (%.0f%%)",
+			tokens, b.MaxTokens, 100*float64(tokens)/float64(b.MaxTokens)))
+	}
+	if b.MaxDuration > 0 && !warned["time"] && elapsed.Seconds() >= warnAt*b.MaxDuration.Seconds() {
+		warned["time"] = true
+		warnings = append(warnings, fmt.Sprintf("%s of %s elapsed (%.0f%%)",
+			elapsed.Round(time.Second), b.MaxDuration, 100*elapsed.Seconds()/b.MaxDuration.Seconds()))
+	}
+	return warnings, nil
+}
+
+// checkBudget compares the run's usage so far against the agent's Budget,
+// printing a warning when a limit is approached.
+func (a *Agent) checkBudget(wc *workContext, step string) error {
+	if a.Budget == nil {
+		return nil
+	}
+	if wc.budgetWarned == nil {
+		wc.budgetWarned = make(map[string]bool)
+	}
+
+	tokens := budgetTokens(wc.costTracker.Total())
+	warnings, err := a.Budget.check(step, tokens, time.Since(wc.startTime), wc.budgetWarned)
+	if err != nil {
+		fmt.Printf("   🛑 %v\n", err)
+		events.Progress("Cost ceiling exceeded: " + err.Error())
+		return err
+	}
+	if len(warnings) > 0 {
+		msg := "Approaching cost ceiling: " + strings.Join(warnings, ", ")
+		fmt.Printf("   ⚠️  %s\n", msg)
+		events.Progress(msg)
+	}
+	return nil
+}
+
+// budgetContext returns ctx with the budget's time limit as its deadline, so
+// a call still running when the limit is reached is cancelled.
+func (a *Agent) budgetContext(ctx context.Context, wc *workContext) (context.Context, context.CancelFunc) {
+	if a.Budget == nil || a.Budget.MaxDuration <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithDeadline(ctx, wc.startTime.Add(a.Budget.MaxDuration))
+}
+
+// budgetError converts err, returned by a step whose budget context (from
+// budgetContext) ran out, into a time BudgetExceededError. Other errors,
+// including cancellation of the caller's own context, are returned as is.
+func (a *Agent) budgetError(ctx context.Context, wc *workContext, step string, err error) error {
+	if err == nil || IsBudgetExceeded(err) || a.Budget == nil || a.Budget.MaxDuration <= 0 {
+		return err
+	}
+	elapsed := time.Since(wc.startTime)
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) || elapsed < a.Budget.MaxDuration {
+		return err
+	}
+
+	be := &BudgetExceededError{
+		Step:        step,
+		Limit:       "time",
+		TokensUsed:  budgetTokens(wc.costTracker.Total()),
+		MaxTokens:   a.Budget.MaxTokens,
+		Elapsed:     elapsed,
+		MaxDuration: a.Budget.MaxDuration,
+	}
+	fmt.Printf("   🛑 %v\n", be)
+	events.Progress("Cost ceiling exceeded: " + be.Error())
+	return be
+}
+
+// stepFailed ends a run whose step returned err, stopping it as over budget
+// when err is, or was caused by, the budget running out.
+func (a *Agent) stepFailed(ctx context.Context, wc *workContext, step string, err error) (*WorkResult, error) {
+	if err = a.budgetError(ctx, wc, step, err); IsBudgetExceeded(err) {
+		return a.budgetStop(wc, err)
+	}
+	return nil, err
+}
+
+// budgetStop ends a run that crossed its budget, preserving any draft PR.
+func (a *Agent) budgetStop(wc *workContext, err error) (*WorkResult, error) {
+	result := &WorkResult{
+		Message:        err.Error(),
+		Iterations:     wc.iterations,
+		BudgetExceeded: true,
+	}
+	if wc.draftPRURL != "" {
+		result.PRURL = wc.draftPRURL
+		result.PRCreated = true
+		result.Message = "Cost ceiling exceeded — draft PR preserved: " + wc.draftPRURL
+		fmt.Printf("   📋 Draft PR preserved: %s\n", wc.draftPRURL)
+	}
+	if wc.costTracker.HasUsage() {
+		fmt.Print(wc.costTracker.Summary())
+	}
+	return result, err
+}
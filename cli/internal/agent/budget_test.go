@@ -0,0 +1,118 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/philjestin/boatmanmode/internal/cost"
+)
+
+func TestBudgetCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		budget    *Budget
+		tokens    int
+		elapsed   time.Duration
+		wantLimit string
+		wantWarns int
+	}{
+		{"nil budget", nil, 1 << 30, time.Hour, "", 0},
+		{"under limits", &Budget{MaxTokens: 1000, MaxDuration: 10 * time.Minute}, 100, time.Minute, "", 0},
+		{"token warning", &Budget{MaxTokens: 1000}, 850, time.Minute, "", 1},
+		{"both warnings", &Budget{MaxTokens: 1000, MaxDuration: 10 * time.Minute}, 900, 9 * time.Minute, "", 2},
+		{"custom warn threshold", &Budget{MaxTokens: 1000, WarnAt: 0.5}, 600, 0, "", 1},
+		{"tokens exceeded", &Budget{MaxTokens: 1000, MaxDuration: 10 * time.Minute}, 1001, time.Minute, "tokens", 0},
+		{"time exceeded", &Budget{MaxTokens: 1000, MaxDuration: 10 * time.Minute}, 10, 11 * time.Minute, "time", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := tt.budget.check("execution", tt.tokens, tt.elapsed, map[string]bool{})
+			if tt.wantLimit == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			} else {
+				var be *BudgetExceededError
+				if !errors.As(err, &be) {
+					t.Fatalf("expected BudgetExceededError, got %v", err)
+				}
+				if be.Limit != tt.wantLimit || be.Step != "execution" {
+					t.Errorf("expected %s limit at execution, got %+v", tt.wantLimit, be)
+				}
+			}
+			if len(warnings) != tt.wantWarns {
+				t.Errorf("expected %d warnings, got %v", tt.wantWarns, warnings)
+			}
+		})
+	}
+}
+
+func TestBudgetCheck_WarnsOnce(t *testing.T) {
+	b := &Budget{MaxTokens: 1000}
+	warned := map[string]bool{}
+
+	if w, _ := b.check("planning", 850, 0, warned); len(w) != 1 {
+		t.Fatalf("expected first check to warn, got %v", w)
+	}
+	if w, _ := b.check("execution", 950, 0, warned); len(w) != 0 {
+		t.Errorf("expected no repeat warning, got %v", w)
+	}
+}
+
+func TestIsBudgetExceeded(t *testing.T) {
+	err := fmt.Errorf("work failed: %w", &BudgetExceededError{Limit: "tokens"})
+	if !IsBudgetExceeded(err) {
+		t.Error("expected wrapped BudgetExceededError to be detected")
+	}
+	if IsBudgetExceeded(errors.New("other")) {
+		t.Error("expected unrelated error not to be detected")
+	}
+}
+
+func TestBudgetError_Deadline(t *testing.T) {
+	a := &Agent{Budget: &Budget{MaxDuration: time.Minute}}
+	wc := &workContext{startTime: time.Now().Add(-2 * time.Minute), costTracker: cost.NewTracker()}
+
+	ctx, cancel := a.budgetContext(context.Background(), wc)
+	defer cancel()
+	<-ctx.Done()
+
+	err := a.budgetError(ctx, wc, "execution", fmt.Errorf("claude: %w", ctx.Err()))
+	var be *BudgetExceededError
+	if !errors.As(err, &be) {
+		t.Fatalf("expected BudgetExceededError, got %v", err)
+	}
+	if be.Limit != "time" || be.Step != "execution" {
+		t.Errorf("expected time limit at execution, got %+v", be)
+	}
+
+	result, err := a.stepFailed(ctx, wc, "execution", ctx.Err())
+	if !IsBudgetExceeded(err) || result == nil || !result.BudgetExceeded {
+		t.Errorf("expected budget stop, got %+v, %v", result, err)
+	}
+}
+
+func TestBudgetError_PassesOtherErrors(t *testing.T) {
+	a := &Agent{Budget: &Budget{MaxDuration: time.Minute}}
+	wc := &workContext{startTime: time.Now(), costTracker: cost.NewTracker()}
+
+	ctx, cancel := a.budgetContext(context.Background(), wc)
+	defer cancel()
+	other := errors.New("execution failed")
+	if err := a.budgetError(ctx, wc, "execution", other); err != other {
+		t.Errorf("expected error within budget unchanged, got %v", err)
+	}
+
+	// Cancelling the caller's context is not a budget stop.
+	parent, cancelParent := context.WithCancel(context.Background())
+	ctx, cancel = a.budgetContext(parent, wc)
+	defer cancel()
+	cancelParent()
+	if err := a.budgetError(ctx, wc, "execution", ctx.Err()); IsBudgetExceeded(err) {
+		t.Errorf("expected cancellation to pass through, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/philjestin/boatman-ecosystem/harness/codegen"
+	"github.com/philjestin/boatmanmode/internal/events"
+	"github.com/philjestin/boatmanmode/internal/executor"
+)
+
+// runCodegen keeps generated code in step with its sources after the agent
+// edits: it restores generated files the agent changed by hand, reruns the
+// generators whose sources changed and stages the result. The regenerated
+// files are committed, but left out of the reviewed diff. Iteration 0 is the
+// pass after execution; later passes follow each refactor. Generators that
+// fail are reported and skipped.
+func (a *Agent) runCodegen(ctx context.Context, wc *workContext, iteration int) error {
+	if !a.config.Codegen.Enabled {
+		return nil
+	}
+	restored := a.restoreGenerated(ctx, wc)
+
+	regen := executor.NewRegenerator(wc.worktree.Path, a.config)
+	affected := regen.Affected(wc.execResult.FilesChanged)
+	if len(affected) == 0 {
+		if restored {
+			return wc.exec.StageChanges()
+		}
+		return nil
+	}
+
+	agentID := fmt.Sprintf("codegen-%d-%s", iteration, wc.task.GetID())
+	events.AgentStarted(agentID, "Codegen", "Regenerating code with "+strings.Join(affected, ", "))
+
+	result, err := regen.Regenerate(ctx, wc.execResult.FilesChanged)
+	if err != nil {
+		events.AgentCompleted(agentID, "Codegen", "failed")
+		return fmt.Errorf("codegen failed: %w", err)
+	}
+	fmt.Printf("   ⚙️  Codegen: %s\n", result.Summary())
+	for _, run := range result.Runs {
+		if len(run.Files) > 0 {
+			fmt.Printf("      • %s: %s\n", run.Generator, strings.Join(run.Files, ", "))
+		}
+	}
+
+	wc.codegenRuns = append(wc.codegenRuns, result.Runs...)
+	if err := wc.exec.StageChanges(); err != nil {
+		events.AgentCompleted(agentID, "Codegen", "failed")
+		return fmt.Errorf("failed to stage generated code: %w", err)
+	}
+
+	status := "success"
+	if len(result.Failed) > 0 {
+		status = "failed"
+	}
+	events.AgentCompletedWithData(agentID, "Codegen", status, map[string]any{
+		"runs":   result.Runs,
+		"failed": result.Failed,
+	})
+	return nil
+}
+
+// restoreGenerated discards the agent's changes to generated files, if
+// configured, and reports whether there were any. The generators rewrite
+// them from their sources.
+func (a *Agent) restoreGenerated(ctx context.Context, wc *workContext) bool {
+	if !a.config.Codegen.Enabled || !a.config.Codegen.RestoreGenerated {
+		return false
+	}
+	generated, err := wc.exec.DetectGeneratedFiles()
+	if err != nil || len(generated) == 0 {
+		return false
+	}
+	// Files the generators wrote earlier in the run are not hand edits
+	generated = removeFiles(generated, codegenFiles(wc.codegenRuns))
+	if len(generated) == 0 {
+		return false
+	}
+
+	if err := codegen.Restore(ctx, wc.worktree.Path, generated); err != nil {
+		fmt.Printf("   ⚠️  Failed to restore generated files: %v\n", err)
+		return false
+	}
+	fmt.Printf("   ↩️  Restored %d hand-edited generated files: %s\n", len(generated), strings.Join(generated, ", "))
+	return true
+}
+
+// codegenFiles returns every file the generators changed across the run.
+func codegenFiles(runs []codegen.Run) []string {
+	var files []string
+	for _, run := range runs {
+		files = mergeFiles(files, run.Files)
+	}
+	return files
+}
+
+// formatCodegen lists which generators regenerated which files across the
+// run, for the PR body. It returns "" when nothing was regenerated.
+func formatCodegen(runs []codegen.Run) string {
+	var order []string
+	files := map[string][]string{}
+	for _, run := range runs {
+		if len(run.Files) == 0 {
+			continue
+		}
+		if _, ok := files[run.Generator]; !ok {
+			order = append(order, run.Generator)
+		}
+		files[run.Generator] = mergeFiles(files[run.Generator], run.Files)
+	}
+
+	var sb strings.Builder
+	for _, g := range order {
+		fmt.Fprintf(&sb, "- Regenerated (%s, not reviewed): %s\n", g, strings.Join(files[g], ", "))
+	}
+	return sb.String()
+}
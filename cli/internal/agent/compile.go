@@ -0,0 +1,153 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/philjestin/boatman-ecosystem/harness/compilecheck"
+	"github.com/philjestin/boatmanmode/internal/events"
+	"github.com/philjestin/boatmanmode/internal/executor"
+	"github.com/philjestin/boatmanmode/internal/scottbott"
+)
+
+// maxCompileErrors is how many compiler errors a repair prompt carries.
+const maxCompileErrors = 30
+
+// stepCompile builds or typechecks the changes before they are first tested
+// and reviewed (Step 5c). It reports whether they compile; when they don't,
+// the compile errors become the first review.
+func (a *Agent) stepCompile(ctx context.Context, wc *workContext) bool {
+	failure := a.compileAndRepair(ctx, wc, 1)
+	if failure == nil {
+		return true
+	}
+
+	wc.setReview(failure)
+	fmt.Println(failure.FormatReview())
+	fmt.Println()
+	return false
+}
+
+// compileAndRepair builds or typechecks the changed files and, while they
+// don't compile, sends the compiler errors back to the executor in a short
+// repair prompt, up to Review.CompileRepairRounds times. It returns a failed
+// review holding the remaining errors if the changes still don't compile, or
+// nil if they compile or no compiler applies.
+func (a *Agent) compileAndRepair(ctx context.Context, wc *workContext, iteration int) *scottbott.ReviewResult {
+	if !a.config.Review.Compile {
+		return nil
+	}
+	checker := compilecheck.New(wc.worktree.Path)
+	if len(checker.Compilers()) == 0 {
+		return nil
+	}
+
+	agentID := fmt.Sprintf("compile-%d-%s", iteration, wc.task.GetID())
+	name := fmt.Sprintf("Compile Check #%d", iteration)
+	events.AgentStarted(agentID, name, "Building and typechecking the changed files")
+
+	for round := 0; ; round++ {
+		result, err := checker.Check(ctx, wc.execResult.FilesChanged)
+		if err != nil {
+			fmt.Printf("   ⚠️  Compile check error: %v\n", err)
+			events.AgentCompleted(agentID, name, "failed")
+			return nil
+		}
+		fmt.Printf("   🔨 Compile: %s\n", result.Summary())
+		if result.Passed {
+			events.AgentCompletedWithData(agentID, name, "success", map[string]any{
+				"repair_rounds": round,
+			})
+			return nil
+		}
+
+		if round >= a.config.Review.CompileRepairRounds {
+			events.AgentCompletedWithData(agentID, name, "failed", map[string]any{
+				"repair_rounds": round,
+				"errors":        len(result.Errors),
+			})
+			return compileFailure(result)
+		}
+		if err := a.repair(ctx, wc, iteration, round+1, result); err != nil {
+			fmt.Printf("   ⚠️  Repair failed: %v\n", err)
+			events.AgentCompletedWithData(agentID, name, "failed", map[string]any{
+				"repair_rounds": round + 1,
+				"errors":        len(result.Errors),
+			})
+			return compileFailure(result)
+		}
+	}
+}
+
+// repair sends one round of compile errors to a fresh executor and stages
+// its fixes, less any edits to generated files.
+func (a *Agent) repair(ctx context.Context, wc *workContext, iteration, round int, result *compilecheck.Result) error {
+	repairExec := executor.NewRepairExecutor(wc.worktree.Path, iteration, round, a.config)
+	repairResult, usage, err := repairExec.Repair(ctx, result.Format(maxCompileErrors), repairFiles(wc, result))
+	if usage != nil {
+		wc.costTracker.Add(fmt.Sprintf("Repair #%d.%d", iteration, round), *usage)
+	}
+	if err != nil {
+		return err
+	}
+	if !repairResult.Success {
+		return repairResult.Error
+	}
+
+	wc.execResult.FilesChanged = mergeFiles(wc.execResult.FilesChanged, repairResult.FilesChanged)
+	a.restoreGenerated(ctx, wc)
+	return wc.exec.StageChanges()
+}
+
+// repairFiles returns the files the compiler reported errors in, falling
+// back to the changed files when the errors have no location in the worktree.
+func repairFiles(wc *workContext, result *compilecheck.Result) []string {
+	seen := map[string]bool{}
+	var files []string
+	for _, e := range result.Errors {
+		if e.File == "" || seen[e.File] {
+			continue
+		}
+		seen[e.File] = true
+		if _, err := os.Stat(filepath.Join(wc.worktree.Path, e.File)); err == nil {
+			files = append(files, e.File)
+		}
+	}
+	if len(files) == 0 {
+		return wc.execResult.FilesChanged
+	}
+	return files
+}
+
+// compileFailure converts compile errors to a failed review, so the refactor
+// fixes them before anything is reviewed.
+func compileFailure(result *compilecheck.Result) *scottbott.ReviewResult {
+	issues := make([]scottbott.Issue, 0, len(result.Errors))
+	for _, issue := range result.Issues() {
+		issues = append(issues, scottbott.ReviewIssueToIssue(issue))
+	}
+	return &scottbott.ReviewResult{
+		Passed:   false,
+		Summary:  "The changes do not compile: " + result.Summary(),
+		Issues:   issues,
+		Guidance: "Fix these compile errors before anything else:\n\n" + result.Format(maxCompileErrors),
+	}
+}
+
+// mergeFiles returns the files in a followed by those in b that aren't in a.
+func mergeFiles(a, b []string) []string {
+	seen := make(map[string]bool, len(a))
+	merged := append([]string(nil), a...)
+	for _, f := range a {
+		seen[f] = true
+	}
+	for _, f := range b {
+		if !seen[f] {
+			seen[f] = true
+			merged = append(merged, f)
+		}
+	}
+	return merged
+}
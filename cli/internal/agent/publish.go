@@ -0,0 +1,97 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/philjestin/boatman-ecosystem/harness/review/report"
+	"github.com/philjestin/boatmanmode/internal/github"
+	"github.com/philjestin/boatmanmode/internal/issuetracker"
+	"github.com/philjestin/boatmanmode/internal/scottbott"
+)
+
+// setReview records a review as the current one and adds its issues to the
+// run's review history.
+func (wc *workContext) setReview(result *scottbott.ReviewResult) {
+	wc.reviewResult = result
+	if result == nil {
+		return
+	}
+	if wc.reviewHistory == nil {
+		wc.reviewHistory = issuetracker.NewIssueHistory()
+	}
+	wc.reviewHistory.RecordIteration(result.Issues)
+}
+
+// writeSARIF writes the run's review history to a.SARIFPath. Issues fixed in
+// later iterations are kept with baselineState "absent".
+func (a *Agent) writeSARIF(wc *workContext) {
+	if a.SARIFPath == "" || wc.reviewHistory == nil {
+		return
+	}
+
+	opts := report.Options{ToolName: "ScottBott"}
+	if diff, err := branchDiff(wc.worktree.Path, a.config.BaseBranch); err == nil {
+		opts.Diff = diff
+	}
+	data, err := report.SARIFFromHistory(wc.reviewHistory.Issues(), opts).JSON()
+	if err == nil {
+		if dir := filepath.Dir(a.SARIFPath); dir != "." {
+			err = os.MkdirAll(dir, 0755)
+		}
+	}
+	if err == nil {
+		err = os.WriteFile(a.SARIFPath, data, 0644)
+	}
+	if err != nil {
+		fmt.Printf("   ⚠️  Failed to write SARIF report: %v\n", err)
+		return
+	}
+	fmt.Printf("   📄 SARIF report: %s\n", a.SARIFPath)
+}
+
+// postReview posts the final review to the pull request, with issues as
+// inline comments on the lines of the pull request's diff.
+func (a *Agent) postReview(ctx context.Context, wc *workContext, prURL string) {
+	if !a.PostReview || wc.reviewResult == nil {
+		return
+	}
+	if err := PostPRReview(ctx, wc.worktree.Path, prURL, wc.reviewResult); err != nil {
+		fmt.Printf("   ⚠️  Failed to post review: %v\n", err)
+		return
+	}
+	fmt.Printf("   💬 Posted review with %d issues to the PR\n", len(wc.reviewResult.Issues))
+}
+
+// PostPRReview posts a review result to a pull request (by URL, number, or
+// branch) as a GitHub review. Comment positions are mapped through the diff
+// GitHub shows for the pull request, so they land on the reviewed lines.
+func PostPRReview(ctx context.Context, workDir, prRef string, result *scottbott.ReviewResult) error {
+	pr, err := github.GetPRInfo(ctx, workDir, prRef)
+	if err != nil {
+		return err
+	}
+	diff, err := github.GetPRDiff(ctx, workDir, pr.Number)
+	if err != nil {
+		return err
+	}
+
+	payload, err := report.NewPullRequestReview(result.ToReviewResult(), diff, report.GitHubOptions{
+		CommitID: pr.HeadSHA,
+	}).JSON()
+	if err != nil {
+		return fmt.Errorf("encode review: %w", err)
+	}
+	return github.PostReview(ctx, workDir, pr.Number, payload)
+}
+
+// branchDiff returns the changes on the worktree's branch since it left base.
+func branchDiff(workDir, base string) (string, error) {
+	cmd := exec.Command("git", "diff", base+"...HEAD")
+	cmd.Dir = workDir
+	out, err := cmd.Output()
+	return string(out), err
+}
@@ -0,0 +1,398 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/philjestin/boatman-ecosystem/harness/review"
+	"github.com/philjestin/boatman-ecosystem/harness/review/chunk"
+	"github.com/philjestin/boatman-ecosystem/harness/review/lint"
+	"github.com/philjestin/boatman-ecosystem/harness/review/vote"
+	"github.com/philjestin/boatmanmode/internal/brain"
+	"github.com/philjestin/boatmanmode/internal/contextpin"
+	"github.com/philjestin/boatmanmode/internal/cost"
+	"github.com/philjestin/boatmanmode/internal/executor"
+	"github.com/philjestin/boatmanmode/internal/handoff"
+	"github.com/philjestin/boatmanmode/internal/scottbott"
+	"github.com/philjestin/boatmanmode/internal/task"
+	"github.com/philjestin/boatmanmode/internal/worktree"
+)
+
+// maxRelatedFiles caps the unchanged files listed as review context.
+const maxRelatedFiles = 20
+
+// ReviewSource is a change to review outside of a work run: a branch, a
+// commit range, a pull request, or a patch.
+type ReviewSource struct {
+	// Title names the change in the review, e.g. "feature/login" or "PR #42".
+	Title string
+
+	// Description is what the change is meant to do (a PR body, for example).
+	// Empty means the reviewer judges the diff on its own.
+	Description string
+
+	// Diff is the unified diff to review.
+	Diff string
+
+	// StartPoint is the commit the diff applies to. Review and ReviewAndFix
+	// check it out in a fresh worktree and apply the diff on top.
+	StartPoint string
+
+	// FixBranch names the branch ReviewAndFix commits the fixes to.
+	FixBranch string
+}
+
+// ReviewReport is the outcome of Review or ReviewAndFix.
+type ReviewReport struct {
+	Result *scottbott.ReviewResult
+
+	// Diff is the reviewed diff: the source's diff, or after ReviewAndFix the
+	// fixed change relative to the start point.
+	Diff string
+
+	// FilesChanged are the files the diff touches.
+	FilesChanged []string
+
+	// Set by ReviewAndFix.
+	Iterations   int
+	WorktreePath string
+	FixBranch    string
+}
+
+// Review reviews a change in the current repository with the configured
+// linters and ScottBott. The review handoff includes matching brains and the
+// files the changed files depend on. The change is reviewed in a temporary
+// worktree with the diff applied to its start point; if the diff doesn't
+// apply, it is reviewed in the current checkout without static analysis.
+func (a *Agent) Review(ctx context.Context, src ReviewSource) (*ReviewReport, error) {
+	if strings.TrimSpace(src.Diff) == "" {
+		return nil, fmt.Errorf("nothing to review: the diff is empty")
+	}
+	repoPath, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	dir := repoPath
+	checkout, removeCheckout, err := reviewCheckout(repoPath, src)
+	if err != nil {
+		fmt.Printf("   ⚠️  Reviewing without static analysis: %v\n", err)
+	} else {
+		defer removeCheckout()
+		dir = checkout.Path
+	}
+
+	wc := a.newReviewContext(src, dir)
+	if checkout == nil {
+		// The current checkout's files aren't the ones under review
+		wc.pinner = nil
+		wc.staleCheckout = true
+	}
+	a.costTracker = wc.costTracker
+	wc.execResult = &executor.ExecutionResult{Success: true, FilesChanged: lint.ChangedFiles(src.Diff)}
+	a.loadReviewContext(wc)
+
+	result, err := a.reviewDiff(ctx, wc, src.Diff)
+	if err != nil {
+		return nil, err
+	}
+	wc.setReview(result)
+
+	return &ReviewReport{
+		Result:       result,
+		Diff:         src.Diff,
+		FilesChanged: wc.execResult.FilesChanged,
+	}, nil
+}
+
+// ReviewAndFix reviews a change, then applies it in a fresh worktree and runs
+// the refactor loop (with diff verification) until the review passes or
+// MaxIterations is reached. The fixed change is committed to src.FixBranch;
+// nothing is pushed.
+func (a *Agent) ReviewAndFix(ctx context.Context, src ReviewSource) (*ReviewReport, error) {
+	initial, err := a.Review(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	if initial.Result.Passed {
+		fmt.Println("   ✅ Review passed, nothing to fix")
+		return initial, nil
+	}
+
+	repoPath, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	wtManager, err := worktree.New(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create worktree manager: %w", err)
+	}
+	wt, err := wtManager.CreateAt(src.FixBranch, src.StartPoint)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("   📁 Fix worktree: %s (%s)\n", wt.Path, wt.BranchName)
+
+	if err := applyDiff(wt.Path, src.Diff); err != nil {
+		return nil, err
+	}
+
+	wc := a.newReviewContext(src, wt.Path)
+	wc.worktree = wt
+	wc.branchName = wt.BranchName
+	wc.costTracker = a.costTracker
+	wc.exec = executor.New(wt.Path, a.config)
+	wc.execResult = &executor.ExecutionResult{Success: true, FilesChanged: initial.FilesChanged}
+	a.loadReviewContext(wc)
+	wc.setReview(initial.Result)
+
+	a.coordinator.Start(ctx)
+	defer a.coordinator.Stop()
+
+	if err := a.stepRefactorLoop(ctx, wc); err != nil {
+		return nil, err
+	}
+
+	fixedDiff, _ := wc.exec.GetDiff()
+	if err := wc.exec.StageChanges(); err != nil {
+		return nil, fmt.Errorf("failed to stage changes: %w", err)
+	}
+	if err := wc.exec.Commit(fmt.Sprintf("%s (review fixes)", src.Title)); err != nil {
+		return nil, fmt.Errorf("failed to commit fixes: %w", err)
+	}
+
+	return &ReviewReport{
+		Result:       wc.reviewResult,
+		Diff:         fixedDiff,
+		FilesChanged: wc.execResult.FilesChanged,
+		Iterations:   wc.iterations,
+		WorktreePath: wt.Path,
+		FixBranch:    wt.BranchName,
+	}, nil
+}
+
+// reviewCheckout checks out src's start point in a temporary worktree and
+// applies its diff. The returned func removes the worktree.
+func reviewCheckout(repoPath string, src ReviewSource) (*worktree.Worktree, func(), error) {
+	wtManager, err := worktree.New(repoPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create worktree manager: %w", err)
+	}
+	wt, err := wtManager.CreateDetached(src.StartPoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	remove := func() {
+		if err := wtManager.Remove(wt); err != nil {
+			fmt.Printf("   ⚠️  Failed to remove review worktree: %v\n", err)
+		}
+	}
+	if err := applyDiff(wt.Path, src.Diff); err != nil {
+		remove()
+		return nil, nil, err
+	}
+	return wt, remove, nil
+}
+
+// newReviewContext builds a work context for reviewing src in dir.
+func (a *Agent) newReviewContext(src ReviewSource, dir string) *workContext {
+	description := src.Description
+	if strings.TrimSpace(description) == "" {
+		description = fmt.Sprintf("Review the changes in %s. No requirements were given: judge correctness, "+
+			"safety, tests and consistency with the surrounding code.", src.Title)
+	}
+	t := task.NewPromptTask(description, "Review "+src.Title, src.FixBranch)
+
+	pinner := contextpin.New(dir)
+	pinner.SetCoordinator(a.coordinator)
+	return &workContext{
+		task:        t,
+		worktree:    &worktree.Worktree{Path: dir},
+		pinner:      pinner,
+		startTime:   time.Now(),
+		costTracker: cost.NewTracker(),
+	}
+}
+
+// loadReviewContext loads the brains matching the change into wc.
+func (a *Agent) loadReviewContext(wc *workContext) {
+	if !a.config.Brain.Enabled {
+		return
+	}
+	keywords := brain.ExtractKeywords(wc.task.GetTitle() + " " + wc.task.GetDescription())
+	injector := brain.NewInjector(wc.worktree.Path, a.config.Brain.MaxBrains)
+	brainHandoff, err := injector.ForContext(keywords, wc.execResult.FilesChanged, nil)
+	if err != nil {
+		fmt.Printf("   ⚠️  Brain loading failed: %v\n", err)
+		return
+	}
+	if brainHandoff != nil {
+		wc.brainHandoff = brainHandoff
+		fmt.Printf("   🧠 Loaded brain context: %s\n", brainHandoff.Concise())
+	}
+}
+
+// reviewDiff runs the linters and ScottBott on diff. Unlike the work loop,
+// ScottBott runs even when the linters fail, so the report covers both.
+func (a *Agent) reviewDiff(ctx context.Context, wc *workContext, diff string) (*scottbott.ReviewResult, error) {
+	var lintIssues []scottbott.Issue
+	lintPassed := true
+	if lintResult := a.staticReview(ctx, wc, diff); lintResult != nil {
+		lintIssues = lintResult.Issues
+		lintPassed = false
+	}
+
+	result, usage, err := a.scottBottReview(ctx, wc, 1, a.newReviewHandoff(wc, diff).ForTokenBudget(handoff.DefaultBudget.Context), diff)
+	if usage != nil {
+		wc.costTracker.Add(reviewStep(1), *usage)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("review failed: %w", err)
+	}
+
+	if !lintPassed {
+		result.Passed = false
+		result.Issues = append(lintIssues, result.Issues...)
+		result.Summary = strings.TrimSpace(fmt.Sprintf("Static analysis: %d issues. %s", len(lintIssues), result.Summary))
+	}
+	return result, nil
+}
+
+// scottBottReview reviews diff with ScottBott. With review.votes above one,
+// several independent reviews decide by majority. Diffs longer than
+// review.chunk_lines are split into chunks of related files that are reviewed
+// concurrently, followed by a summary pass for cross-cutting problems.
+func (a *Agent) scottBottReview(ctx context.Context, wc *workContext, iteration int, reviewContext, diff string) (*scottbott.ReviewResult, *cost.Usage, error) {
+	chunkLines := a.config.Review.ChunkLines
+	chunked := chunkLines > 0 && strings.Count(diff, "\n") > chunkLines
+	votes := a.config.Review.Votes
+	if !chunked && votes <= 1 {
+		reviewer := scottbott.NewWithSkill(wc.worktree.Path, iteration, a.config.ReviewSkill, a.config)
+		return reviewer.Review(ctx, reviewContext, diff)
+	}
+
+	var reviewers []*scottbott.Reviewer
+	newReviewer := func(model string) *scottbott.Reviewer {
+		r := scottbott.NewReviewer(wc.worktree.Path, iteration, a.config.ReviewSkill, a.config)
+		if model != "" {
+			r.SetModel(model)
+		}
+		reviewers = append(reviewers, r)
+		return r
+	}
+
+	var reviewer review.Reviewer
+	if votes > 1 {
+		fmt.Printf("   🗳️  Deciding by vote across %d reviews\n", votes)
+		voters := make([]review.Reviewer, votes)
+		for i := range voters {
+			model := ""
+			if models := a.config.Review.VoteModels; len(models) > 0 {
+				model = models[i%len(models)]
+			}
+			voters[i] = newReviewer(model)
+		}
+		reviewer = vote.New(voters, vote.Options{Quorum: a.config.Review.VoteQuorum})
+	} else {
+		reviewer = newReviewer("")
+	}
+
+	if chunked {
+		fmt.Printf("   🧩 Large diff: reviewing in chunks of up to %d lines\n", chunkLines)
+		opts := chunk.Options{MaxLines: chunkLines}
+		if wc.pinner != nil {
+			// The review handoff has already analyzed the changed files' imports
+			opts.Graph = wc.pinner
+		}
+		reviewer = chunk.New(reviewer, opts)
+	}
+
+	result, err := reviewer.Review(ctx, diff, reviewContext)
+
+	var usage *cost.Usage
+	var total cost.Usage
+	for _, r := range reviewers {
+		total = total.Add(r.Usage())
+	}
+	if !total.IsEmpty() {
+		usage = &total
+	}
+	if err != nil {
+		return nil, usage, err
+	}
+	return scottbott.FromReviewResult(result), usage, nil
+}
+
+// reviewStep names a review iteration's step in the cost tracker and events.
+func reviewStep(iteration int) string {
+	return fmt.Sprintf("review_%d", iteration)
+}
+
+// reviewEventData adds the review's step and usage to a completion event's data.
+func reviewEventData(iteration int, usage *cost.Usage, data map[string]any) map[string]any {
+	data["step"] = reviewStep(iteration)
+	if usage != nil {
+		data["usage"] = *usage
+	}
+	return data
+}
+
+// newReviewHandoff builds the review handoff for diff, with the unchanged
+// files the change depends on and any loaded brain knowledge.
+func (a *Agent) newReviewHandoff(wc *workContext, diff string) *handoff.ReviewHandoff {
+	h := handoff.NewReviewHandoff(wc.task, diff, wc.execResult.FilesChanged)
+	if wc.pinner != nil {
+		h.RelatedFiles = relatedFiles(wc.pinner, wc.execResult.FilesChanged)
+	}
+	if wc.brainHandoff != nil {
+		h.Knowledge = wc.brainHandoff.ForTokenBudget(a.config.Brain.TokenBudget)
+	}
+	return h
+}
+
+// relatedFiles returns the unchanged files that the changed files depend on
+// or are depended on by, as far as the pinner can tell from imports.
+func relatedFiles(pinner *contextpin.ContextPinner, changed []string) []string {
+	pinner.AnalyzeFiles(changed)
+
+	isChanged := make(map[string]bool, len(changed))
+	for _, f := range changed {
+		isChanged[f] = true
+	}
+	seen := map[string]bool{}
+	var related []string
+	for _, f := range changed {
+		for _, r := range append(pinner.GetDependencies(f), pinner.GetDependents(f)...) {
+			if isChanged[r] || seen[r] {
+				continue
+			}
+			seen[r] = true
+			related = append(related, r)
+		}
+	}
+	sort.Strings(related)
+	if len(related) > maxRelatedFiles {
+		related = related[:maxRelatedFiles]
+	}
+	return related
+}
+
+// applyDiff applies a unified diff to the worktree and stages it, falling
+// back to a three-way merge when the start point has drifted.
+func applyDiff(dir, diff string) error {
+	cmd := exec.Command("git", "apply", "--index", "--3way", "--whitespace=nowarn", "-")
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(diff)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to apply the diff in %s: %w\n%s", dir, err, stderr.String())
+	}
+	return nil
+}
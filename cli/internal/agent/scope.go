@@ -0,0 +1,108 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/philjestin/boatman-ecosystem/harness/review/lint"
+	"github.com/philjestin/boatman-ecosystem/harness/scope"
+	"github.com/philjestin/boatmanmode/internal/scottbott"
+)
+
+// checkScope classifies the changed files against the plan before a review,
+// reverting changes to unplanned files if configured. It keeps the report for
+// the PR body and returns review issues for the unplanned files when there
+// are more than Scope.MaxUnplannedFiles of them.
+func (a *Agent) checkScope(ctx context.Context, wc *workContext) []scottbott.Issue {
+	if !a.config.Scope.Enabled || wc.plan == nil {
+		return nil
+	}
+	plan := scope.Plan{Dirs: wc.plan.RelevantDirs}
+	plan.Files = append(plan.Files, wc.plan.RelevantFiles...)
+	plan.Files = append(plan.Files, wc.plan.NewFiles...)
+	plan.Files = append(plan.Files, wc.plan.DeletedFiles...)
+	if plan.IsEmpty() {
+		return nil
+	}
+
+	diff, err := wc.exec.GetDiff()
+	if err != nil || diff == "" {
+		return nil
+	}
+
+	var graph scope.Graph
+	if wc.pinner != nil {
+		wc.pinner.AnalyzeFiles(append(lint.ChangedFiles(diff), plan.Files...))
+		graph = wc.pinner
+	}
+	report := scope.Classify(diff, plan, graph)
+
+	if a.config.Scope.RevertUnplanned && len(report.Unplanned()) > 0 {
+		reverted, err := report.Revert(ctx, wc.worktree.Path, diff)
+		if err != nil {
+			fmt.Printf("   ⚠️  %v\n", err)
+		} else {
+			fmt.Printf("   ↩️  Reverted %d unplanned files\n", len(reverted))
+			wc.scopeReverted = mergeFiles(wc.scopeReverted, reverted)
+			wc.execResult.FilesChanged = removeFiles(wc.execResult.FilesChanged, reverted)
+		}
+	}
+
+	// Files reverted before earlier reviews are no longer in the diff
+	inReport := map[string]bool{}
+	for _, f := range report.Files {
+		inReport[f.Path] = true
+	}
+	for _, f := range wc.scopeReverted {
+		if !inReport[f] {
+			report.Files = append(report.Files, scope.File{Path: f, Class: scope.Unplanned, Reverted: true})
+		}
+	}
+
+	wc.scopeReport = report
+	fmt.Printf("   🎯 Scope: %s\n", report.Summary())
+
+	var issues []scottbott.Issue
+	for _, issue := range report.Issues(a.config.Scope.MaxUnplannedFiles) {
+		issues = append(issues, scottbott.ReviewIssueToIssue(issue))
+	}
+	return issues
+}
+
+// withScopeIssues adds the scope issues to a review, failing it if there are
+// any.
+func withScopeIssues(result *scottbott.ReviewResult, issues []scottbott.Issue) *scottbott.ReviewResult {
+	if result == nil || len(issues) == 0 {
+		return result
+	}
+	result.Passed = false
+	result.Issues = append(result.Issues, issues...)
+	return result
+}
+
+// formatScope is the PR body's scope section, or "" if there is no report.
+func formatScope(report *scope.Report) string {
+	if report == nil {
+		return ""
+	}
+	md := report.Markdown()
+	if md == "" {
+		return ""
+	}
+	return "\n### Scope\n" + md
+}
+
+// removeFiles returns files without those in remove.
+func removeFiles(files, remove []string) []string {
+	drop := make(map[string]bool, len(remove))
+	for _, f := range remove {
+		drop[f] = true
+	}
+	var kept []string
+	for _, f := range files {
+		if !drop[f] {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,418 @@
+// Package batch executes triaged tickets from a triage output directory.
+//
+// It reuses the artifacts 'boatman triage' writes — classifications from the
+// decision log, the execution schedule, generated plans, and cluster context
+// docs — and runs the selected tickets wave by wave with bounded concurrency.
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/philjestin/boatmanmode/internal/plan"
+	"github.com/philjestin/boatmanmode/internal/planner"
+	"github.com/philjestin/boatmanmode/internal/triage"
+)
+
+const batchDir = "batches"
+
+// Status is the terminal state of one ticket in a batch.
+type Status string
+
+const (
+	StatusPRCreated Status = "pr_created"
+	StatusNoPR      Status = "no_pr"
+	StatusFailed    Status = "failed"
+	StatusSkipped   Status = "skipped"
+)
+
+// Options selects which triaged tickets to run.
+type Options struct {
+	// TriageDir is the triage output directory to read from.
+	TriageDir string
+
+	// Categories limits the batch to these triage categories.
+	// Default: AI_DEFINITE only.
+	Categories []triage.Category
+
+	// TicketIDs, if set, further limits the batch to these tickets.
+	TicketIDs []string
+
+	// Concurrency is the maximum number of tickets run at once (default 1).
+	Concurrency int
+}
+
+// Job is one ticket to execute, with the triage artifacts that apply to it.
+type Job struct {
+	TicketID  string
+	Category  triage.Category
+	ClusterID string
+
+	// Wave is the schedule wave the ticket runs in. Jobs in later waves only
+	// start after every job in earlier waves has finished.
+	Wave int
+
+	// DependsOn lists in-batch tickets that must succeed first.
+	DependsOn []string
+
+	// Plan is the triage-generated plan, or nil to let the agent plan.
+	Plan *plan.TicketPlan
+
+	// ContextDoc is the ticket's cluster context, if any.
+	ContextDoc *triage.ContextDoc
+
+	// Ceiling is the cluster's per-ticket cost ceiling (zero when unknown).
+	Ceiling triage.CostCeiling
+}
+
+// TicketResult is the outcome of one job.
+type TicketResult struct {
+	TicketID     string          `json:"ticketId"`
+	Category     triage.Category `json:"category"`
+	ClusterID    string          `json:"clusterId,omitempty"`
+	Wave         int             `json:"wave"`
+	Status       Status          `json:"status"`
+	PRURL        string          `json:"prUrl,omitempty"`
+	Iterations   int             `json:"iterations,omitempty"`
+	ReviewPassed bool            `json:"reviewPassed"`
+	TokensUsed   int             `json:"tokensUsed,omitempty"`
+	CostUSD      float64         `json:"costUsd,omitempty"`
+	Duration     time.Duration   `json:"duration,omitempty"`
+
+	// OverCeiling is set when the run exceeded its cost ceiling.
+	OverCeiling bool   `json:"overCeiling,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Summary is the aggregate output of a batch run.
+type Summary struct {
+	StartedAt    time.Time      `json:"startedAt"`
+	FinishedAt   time.Time      `json:"finishedAt"`
+	TriageDir    string         `json:"triageDir"`
+	Total        int            `json:"total"`
+	PRsCreated   int            `json:"prsCreated"`
+	Failed       int            `json:"failed"`
+	Skipped      int            `json:"skipped"`
+	TotalTokens  int            `json:"totalTokens"`
+	TotalCostUSD float64        `json:"totalCostUsd"`
+	Results      []TicketResult `json:"results"`
+}
+
+// RunFunc executes a single job. Errors are reported through
+// TicketResult.Status and TicketResult.Error; a panic is recovered and
+// recorded as a failure.
+type RunFunc func(ctx context.Context, job Job) TicketResult
+
+// LoadJobs reads the triage output directory and returns the jobs selected
+// by opts, in execution order. Tickets the schedule marked blocked, or whose
+// plan failed validation, are returned as skipped results instead.
+func LoadJobs(opts Options) ([]Job, []TicketResult, error) {
+	dl, err := triage.NewDecisionLog(opts.TriageDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	classifications, err := dl.ReadClassifications()
+	if err != nil {
+		return nil, nil, fmt.Errorf("read classifications: %w", err)
+	}
+	if len(classifications) == 0 {
+		return nil, nil, fmt.Errorf("no triage classifications found in %s", opts.TriageDir)
+	}
+
+	plans, err := plan.LoadPlanResults(opts.TriageDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	docs, err := dl.ReadContextDocs()
+	if err != nil {
+		return nil, nil, fmt.Errorf("read context docs: %w", err)
+	}
+	docByTicket := make(map[string]*triage.ContextDoc)
+	for i := range docs {
+		for _, tid := range docs[i].TicketIDs {
+			docByTicket[tid] = &docs[i]
+		}
+	}
+
+	// The schedule is optional: without one every ticket runs in wave 0.
+	sched, err := triage.LoadSchedule(opts.TriageDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, nil, err
+	}
+	blocked := make(map[string]string)
+	if sched != nil {
+		for _, b := range sched.Blocked {
+			blocked[b.TicketID] = b.Reason
+		}
+	}
+
+	categories := opts.Categories
+	if len(categories) == 0 {
+		categories = []triage.Category{triage.CategoryAIDefinite}
+	}
+	wantCategory := make(map[triage.Category]bool, len(categories))
+	for _, c := range categories {
+		wantCategory[c] = true
+	}
+	wantTicket := make(map[string]bool, len(opts.TicketIDs))
+	for _, id := range opts.TicketIDs {
+		wantTicket[id] = true
+	}
+
+	var jobs []Job
+	var skipped []TicketResult
+	lastWave := 0
+	if sched != nil && len(sched.Waves) > 0 {
+		lastWave = sched.Waves[len(sched.Waves)-1].Index + 1
+	}
+
+	for id, c := range classifications {
+		if !wantCategory[c.Category] {
+			continue
+		}
+		if len(wantTicket) > 0 && !wantTicket[id] {
+			continue
+		}
+
+		job := Job{TicketID: id, Category: c.Category, ContextDoc: docByTicket[id]}
+		if job.ContextDoc != nil {
+			job.ClusterID = job.ContextDoc.ClusterID
+			job.Ceiling = job.ContextDoc.CostCeiling
+		}
+
+		if reason, ok := blocked[id]; ok {
+			skipped = append(skipped, skippedResult(job, "blocked by schedule: "+reason))
+			continue
+		}
+
+		if sched != nil {
+			if st := sched.Ticket(id); st != nil {
+				job.Wave = st.Wave
+				job.DependsOn = st.DependsOn
+				if job.ClusterID == "" {
+					job.ClusterID = st.ClusterID
+				}
+			} else {
+				// Not scheduled (e.g. a HUMAN_REVIEW_REQUIRED ticket selected
+				// explicitly): run after every scheduled wave.
+				job.Wave = lastWave
+			}
+		}
+
+		if pr, ok := plans[id]; ok && pr.Plan != nil {
+			if pr.Validation != nil && !pr.Validation.Passed {
+				skipped = append(skipped, skippedResult(job, "plan failed validation"))
+				continue
+			}
+			job.Plan = pr.Plan
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].Wave != jobs[j].Wave {
+			return jobs[i].Wave < jobs[j].Wave
+		}
+		return jobs[i].TicketID < jobs[j].TicketID
+	})
+	sort.Slice(skipped, func(i, j int) bool { return skipped[i].TicketID < skipped[j].TicketID })
+
+	return jobs, skipped, nil
+}
+
+// Run executes jobs wave by wave, running up to concurrency jobs of a wave
+// at once. A job whose in-batch dependency did not succeed is skipped; any
+// other failure is recorded and the batch continues. A job succeeds when it
+// created a PR.
+func Run(ctx context.Context, jobs []Job, concurrency int, run RunFunc) []TicketResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]TicketResult, len(jobs))
+	succeeded := make(map[string]bool)
+	inBatch := make(map[string]bool, len(jobs))
+	for _, j := range jobs {
+		inBatch[j.TicketID] = true
+	}
+
+	for start := 0; start < len(jobs); {
+		end := start
+		for end < len(jobs) && jobs[end].Wave == jobs[start].Wave {
+			end++
+		}
+
+		sem := make(chan struct{}, concurrency)
+		var wg sync.WaitGroup
+		for i := start; i < end; i++ {
+			job := jobs[i]
+
+			if dep := failedDependency(job, inBatch, succeeded); dep != "" {
+				results[i] = skippedResult(job, "dependency "+dep+" did not succeed")
+				continue
+			}
+			if ctx.Err() != nil {
+				results[i] = skippedResult(job, "batch cancelled")
+				continue
+			}
+
+			wg.Add(1)
+			go func(i int, job Job) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				defer func() {
+					if p := recover(); p != nil {
+						results[i] = TicketResult{
+							TicketID:  job.TicketID,
+							Category:  job.Category,
+							ClusterID: job.ClusterID,
+							Wave:      job.Wave,
+							Status:    StatusFailed,
+							Error:     fmt.Sprintf("panic: %v", p),
+						}
+					}
+				}()
+
+				r := run(ctx, job)
+				r.TicketID = job.TicketID
+				r.Category = job.Category
+				r.ClusterID = job.ClusterID
+				r.Wave = job.Wave
+				results[i] = r
+			}(i, job)
+		}
+		wg.Wait()
+
+		for i := start; i < end; i++ {
+			if results[i].Status == StatusPRCreated {
+				succeeded[results[i].TicketID] = true
+			}
+		}
+		start = end
+	}
+
+	return results
+}
+
+// Summarize aggregates results (including skipped ones) into a Summary.
+func Summarize(triageDir string, startedAt time.Time, results []TicketResult) Summary {
+	s := Summary{
+		StartedAt:  startedAt,
+		FinishedAt: time.Now().UTC(),
+		TriageDir:  triageDir,
+		Total:      len(results),
+		Results:    results,
+	}
+	for _, r := range results {
+		switch r.Status {
+		case StatusPRCreated:
+			s.PRsCreated++
+		case StatusFailed:
+			s.Failed++
+		case StatusSkipped:
+			s.Skipped++
+		}
+		s.TotalTokens += r.TokensUsed
+		s.TotalCostUSD += r.CostUSD
+	}
+	return s
+}
+
+// WriteSummary writes the summary to batches/batch-<timestamp>.json in the
+// triage output directory and returns the file path.
+func WriteSummary(s Summary) (string, error) {
+	dir := filepath.Join(s.TriageDir, batchDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating batch directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling batch summary: %w", err)
+	}
+
+	path := filepath.Join(dir, "batch-"+s.StartedAt.Format("20060102-150405")+".json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("writing batch summary: %w", err)
+	}
+	return path, nil
+}
+
+// PlannerPlan converts a triage TicketPlan and its cluster ContextDoc into
+// the planner.Plan the agent executes. Either argument may be nil.
+func PlannerPlan(tp *plan.TicketPlan, doc *triage.ContextDoc) *planner.Plan {
+	p := &planner.Plan{}
+
+	if tp != nil {
+		p.Summary = tp.Approach
+		p.Approach = []string{tp.Approach}
+		p.RelevantFiles = tp.CandidateFiles
+		p.NewFiles = tp.NewFiles
+		p.DeletedFiles = tp.DeletedFiles
+		p.TestStrategy = strings.Join(tp.Validation, "\n")
+
+		// Merge stop conditions and uncertainties into warnings.
+		for _, sc := range tp.StopConditions {
+			p.Warnings = append(p.Warnings, "STOP: "+sc)
+		}
+		for _, u := range tp.Uncertainties {
+			p.Warnings = append(p.Warnings, "UNCERTAIN: "+u)
+		}
+		if tp.Rollback != "" {
+			p.Warnings = append(p.Warnings, "ROLLBACK: "+tp.Rollback)
+		}
+	}
+
+	if doc != nil {
+		p.RelevantDirs = doc.RepoAreas
+		p.ExistingPatterns = doc.KnownPatterns
+		if len(doc.ValidationPlan) > 0 {
+			cluster := strings.Join(doc.ValidationPlan, "\n")
+			if p.TestStrategy == "" {
+				p.TestStrategy = cluster
+			} else {
+				p.TestStrategy += "\n" + cluster
+			}
+		}
+		for _, r := range doc.Risks {
+			p.Warnings = append(p.Warnings, "RISK: "+r)
+		}
+	}
+
+	return p
+}
+
+// failedDependency returns the first in-batch dependency of job that has
+// not succeeded, or "" if all have.
+func failedDependency(job Job, inBatch, succeeded map[string]bool) string {
+	for _, dep := range job.DependsOn {
+		if inBatch[dep] && !succeeded[dep] {
+			return dep
+		}
+	}
+	return ""
+}
+
+// skippedResult builds a skipped TicketResult for job.
+func skippedResult(job Job, reason string) TicketResult {
+	return TicketResult{
+		TicketID:  job.TicketID,
+		Category:  job.Category,
+		ClusterID: job.ClusterID,
+		Wave:      job.Wave,
+		Status:    StatusSkipped,
+		Error:     reason,
+	}
+}
@@ -0,0 +1,297 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/philjestin/boatmanmode/internal/plan"
+	"github.com/philjestin/boatmanmode/internal/triage"
+)
+
+// writeTriageDir populates dir with classifications, context docs, plans,
+// and a schedule the way 'boatman triage' would.
+func writeTriageDir(t *testing.T, dir string) {
+	t.Helper()
+
+	dl, err := triage.NewDecisionLog(dir)
+	if err != nil {
+		t.Fatalf("NewDecisionLog failed: %v", err)
+	}
+
+	classifications := []triage.Classification{
+		{TicketID: "ENG-1", Category: triage.CategoryAIDefinite},
+		{TicketID: "ENG-2", Category: triage.CategoryAIDefinite},
+		{TicketID: "ENG-3", Category: triage.CategoryAIDefinite},
+		{TicketID: "ENG-4", Category: triage.CategoryAILikely},
+		{TicketID: "ENG-5", Category: triage.CategoryAIDefinite},
+		{TicketID: "ENG-6", Category: triage.CategoryHumanOnly},
+	}
+	for _, c := range classifications {
+		details, _ := json.Marshal(c)
+		if err := dl.Append(triage.DecisionLogEntry{TicketID: c.TicketID, Stage: triage.StageScore, Verdict: string(c.Category), Timestamp: time.Now().UTC(), Details: details}); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	if err := dl.WriteContextDoc(triage.ContextDoc{
+		ClusterID:   "cluster-api-1",
+		TicketIDs:   []string{"ENG-1", "ENG-2"},
+		RepoAreas:   []string{"app/api/"},
+		CostCeiling: triage.CostCeiling{MaxTokensPerTicket: 200000, MaxAgentMinutesPerTicket: 15},
+	}); err != nil {
+		t.Fatalf("WriteContextDoc failed: %v", err)
+	}
+
+	if err := plan.WritePlanResults(dir, []plan.PlanResult{
+		{TicketID: "ENG-1", Plan: &plan.TicketPlan{TicketID: "ENG-1", Approach: "Fix handler"}, Validation: &plan.PlanValidation{Passed: true}},
+		{TicketID: "ENG-3", Plan: &plan.TicketPlan{TicketID: "ENG-3", Approach: "Bad plan"}, Validation: &plan.PlanValidation{Passed: false}},
+	}); err != nil {
+		t.Fatalf("WritePlanResults failed: %v", err)
+	}
+
+	sched := &triage.Schedule{
+		Waves: []triage.ScheduleWave{{Index: 0, TicketIDs: []string{"ENG-1", "ENG-3"}}, {Index: 1, TicketIDs: []string{"ENG-2", "ENG-4"}}},
+		Tickets: []triage.ScheduledTicket{
+			{TicketID: "ENG-1", ClusterID: "cluster-api-1", Wave: 0},
+			{TicketID: "ENG-3", Wave: 0},
+			{TicketID: "ENG-2", ClusterID: "cluster-api-1", Wave: 1, DependsOn: []string{"ENG-1"}},
+			{TicketID: "ENG-4", Wave: 1},
+		},
+		Blocked: []triage.BlockedTicket{{TicketID: "ENG-5", Reason: "dependency cycle"}},
+	}
+	if err := dl.WriteSchedule(sched); err != nil {
+		t.Fatalf("WriteSchedule failed: %v", err)
+	}
+}
+
+func TestLoadJobs(t *testing.T) {
+	dir := t.TempDir()
+	writeTriageDir(t, dir)
+
+	jobs, skipped, err := LoadJobs(Options{TriageDir: dir})
+	if err != nil {
+		t.Fatalf("LoadJobs failed: %v", err)
+	}
+
+	if len(jobs) != 2 || jobs[0].TicketID != "ENG-1" || jobs[1].TicketID != "ENG-2" {
+		t.Fatalf("expected jobs [ENG-1 ENG-2], got %+v", jobs)
+	}
+	if jobs[0].Plan == nil || jobs[0].Plan.Approach != "Fix handler" {
+		t.Errorf("expected ENG-1 to reuse its plan, got %+v", jobs[0].Plan)
+	}
+	if jobs[1].Plan != nil {
+		t.Errorf("expected ENG-2 to have no plan, got %+v", jobs[1].Plan)
+	}
+	if jobs[0].ContextDoc == nil || jobs[0].Ceiling.MaxAgentMinutesPerTicket != 15 {
+		t.Errorf("expected ENG-1 to carry its cluster context and ceiling, got %+v", jobs[0])
+	}
+	if jobs[1].Wave != 1 || len(jobs[1].DependsOn) != 1 {
+		t.Errorf("expected ENG-2 in wave 1 depending on ENG-1, got %+v", jobs[1])
+	}
+
+	if len(skipped) != 2 || skipped[0].TicketID != "ENG-3" || skipped[1].TicketID != "ENG-5" {
+		t.Fatalf("expected ENG-3 and ENG-5 skipped, got %+v", skipped)
+	}
+	for _, s := range skipped {
+		if s.Status != StatusSkipped || s.Error == "" {
+			t.Errorf("expected skipped result with reason, got %+v", s)
+		}
+	}
+}
+
+func TestLoadJobs_CategoriesAndTickets(t *testing.T) {
+	dir := t.TempDir()
+	writeTriageDir(t, dir)
+
+	jobs, _, err := LoadJobs(Options{
+		TriageDir:  dir,
+		Categories: []triage.Category{triage.CategoryAIDefinite, triage.CategoryAILikely, triage.CategoryHumanOnly},
+		TicketIDs:  []string{"ENG-4", "ENG-6"},
+	})
+	if err != nil {
+		t.Fatalf("LoadJobs failed: %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].TicketID != "ENG-4" || jobs[1].TicketID != "ENG-6" {
+		t.Fatalf("expected jobs [ENG-4 ENG-6], got %+v", jobs)
+	}
+	if jobs[1].Wave != 2 {
+		t.Errorf("expected unscheduled ticket after the last wave, got wave %d", jobs[1].Wave)
+	}
+}
+
+func TestLoadJobs_NoTriage(t *testing.T) {
+	if _, _, err := LoadJobs(Options{TriageDir: t.TempDir()}); err == nil {
+		t.Fatal("expected error for empty triage directory")
+	}
+}
+
+func TestRun_ContinuesAfterFailureAndSkipsDependents(t *testing.T) {
+	jobs := []Job{
+		{TicketID: "ENG-1", Wave: 0},
+		{TicketID: "ENG-2", Wave: 0},
+		{TicketID: "ENG-3", Wave: 1, DependsOn: []string{"ENG-1"}},
+		{TicketID: "ENG-4", Wave: 1, DependsOn: []string{"ENG-2"}},
+		{TicketID: "ENG-5", Wave: 1, DependsOn: []string{"ENG-99"}},
+	}
+
+	var mu sync.Mutex
+	var ran []string
+	run := func(ctx context.Context, job Job) TicketResult {
+		mu.Lock()
+		ran = append(ran, job.TicketID)
+		mu.Unlock()
+		if job.TicketID == "ENG-1" {
+			return TicketResult{Status: StatusFailed, Error: "boom"}
+		}
+		return TicketResult{Status: StatusPRCreated, PRURL: "https://example.com/" + job.TicketID, TokensUsed: 100, CostUSD: 0.5}
+	}
+
+	results := Run(context.Background(), jobs, 2, run)
+
+	want := map[string]Status{
+		"ENG-1": StatusFailed,
+		"ENG-2": StatusPRCreated,
+		"ENG-3": StatusSkipped,
+		"ENG-4": StatusPRCreated,
+		"ENG-5": StatusPRCreated,
+	}
+	for _, r := range results {
+		if r.Status != want[r.TicketID] {
+			t.Errorf("%s: expected status %s, got %s", r.TicketID, want[r.TicketID], r.Status)
+		}
+	}
+	if len(ran) != 4 {
+		t.Errorf("expected 4 jobs to run, got %v", ran)
+	}
+
+	s := Summarize("dir", time.Now().UTC(), results)
+	if s.PRsCreated != 3 || s.Failed != 1 || s.Skipped != 1 {
+		t.Errorf("unexpected summary counts: %+v", s)
+	}
+	if s.TotalTokens != 300 || s.TotalCostUSD != 1.5 {
+		t.Errorf("unexpected summary totals: tokens=%d cost=%.2f", s.TotalTokens, s.TotalCostUSD)
+	}
+}
+
+func TestRun_RecoversPanic(t *testing.T) {
+	jobs := []Job{
+		{TicketID: "ENG-1", Wave: 0},
+		{TicketID: "ENG-2", Wave: 0},
+		{TicketID: "ENG-3", Wave: 1, DependsOn: []string{"ENG-1"}},
+	}
+	run := func(ctx context.Context, job Job) TicketResult {
+		if job.TicketID == "ENG-1" {
+			panic("nil map")
+		}
+		return TicketResult{Status: StatusPRCreated}
+	}
+
+	results := Run(context.Background(), jobs, 2, run)
+
+	if r := results[0]; r.TicketID != "ENG-1" || r.Status != StatusFailed || !strings.Contains(r.Error, "nil map") {
+		t.Errorf("expected the panic recorded as a failure, got %+v", r)
+	}
+	if results[1].Status != StatusPRCreated {
+		t.Errorf("expected ENG-2 to succeed, got %+v", results[1])
+	}
+	if results[2].Status != StatusSkipped {
+		t.Errorf("expected ENG-3 to be skipped, got %+v", results[2])
+	}
+}
+
+func TestRun_RespectsConcurrency(t *testing.T) {
+	var jobs []Job
+	for _, id := range []string{"A", "B", "C", "D", "E", "F"} {
+		jobs = append(jobs, Job{TicketID: id})
+	}
+
+	var mu sync.Mutex
+	active, peak := 0, 0
+	run := func(ctx context.Context, job Job) TicketResult {
+		mu.Lock()
+		active++
+		if active > peak {
+			peak = active
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		active--
+		mu.Unlock()
+		return TicketResult{Status: StatusNoPR}
+	}
+
+	Run(context.Background(), jobs, 2, run)
+	if peak > 2 {
+		t.Errorf("expected at most 2 concurrent jobs, saw %d", peak)
+	}
+}
+
+func TestWriteSummary(t *testing.T) {
+	dir := t.TempDir()
+	s := Summarize(dir, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), []TicketResult{{TicketID: "ENG-1", Status: StatusPRCreated}})
+
+	path, err := WriteSummary(s)
+	if err != nil {
+		t.Fatalf("WriteSummary failed: %v", err)
+	}
+	if filepath.Base(path) != "batch-20260102-030405.json" {
+		t.Errorf("unexpected summary path %s", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading summary: %v", err)
+	}
+	var parsed Summary
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("parsing summary: %v", err)
+	}
+	if parsed.PRsCreated != 1 || len(parsed.Results) != 1 {
+		t.Errorf("unexpected parsed summary: %+v", parsed)
+	}
+}
+
+func TestPlannerPlan(t *testing.T) {
+	tp := &plan.TicketPlan{
+		Approach:       "Add validation",
+		CandidateFiles: []string{"app/api/user.go"},
+		NewFiles:       []string{"app/api/user_validation.go"},
+		Validation:     []string{"go test ./app/api/..."},
+		StopConditions: []string{"schema change needed"},
+		Rollback:       "revert commit",
+	}
+	doc := &triage.ContextDoc{
+		RepoAreas:      []string{"app/api/"},
+		KnownPatterns:  []string{"table-driven tests"},
+		ValidationPlan: []string{"make lint"},
+		Risks:          []string{"blastRadius"},
+	}
+
+	p := PlannerPlan(tp, doc)
+	if p.Summary != "Add validation" || len(p.RelevantFiles) != 1 {
+		t.Errorf("unexpected plan basics: %+v", p)
+	}
+	if len(p.NewFiles) != 1 {
+		t.Errorf("expected new files to be kept, got %v", p.NewFiles)
+	}
+	if p.TestStrategy != "go test ./app/api/...\nmake lint" {
+		t.Errorf("unexpected test strategy %q", p.TestStrategy)
+	}
+	if len(p.RelevantDirs) != 1 || len(p.ExistingPatterns) != 1 {
+		t.Errorf("expected context doc areas and patterns, got %+v", p)
+	}
+	if len(p.Warnings) != 3 {
+		t.Errorf("expected STOP, ROLLBACK and RISK warnings, got %v", p.Warnings)
+	}
+
+	if p := PlannerPlan(nil, doc); p.TestStrategy != "make lint" {
+		t.Errorf("expected context-only plan to use cluster validation, got %q", p.TestStrategy)
+	}
+}
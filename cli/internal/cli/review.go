@@ -0,0 +1,263 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/philjestin/boatman-ecosystem/harness/review/report"
+	"github.com/philjestin/boatmanmode/internal/agent"
+	"github.com/philjestin/boatmanmode/internal/config"
+	"github.com/philjestin/boatmanmode/internal/github"
+	"github.com/spf13/cobra"
+)
+
+// reviewCmd reviews an existing change with ScottBott.
+var reviewCmd = &cobra.Command{
+	Use:   "review <branch | commit-range | PR | patch-file>",
+	Short: "Review a branch, commit range, PR, or patch with ScottBott",
+	Long: `Review changes that weren't made by boatman work, using the same review
+as the work loop: the repo's configured linters, then ScottBott with matching
+brains and the files the change depends on as context.
+
+The target can be:
+  A branch:        boatman review feature/login          (diff against --base)
+  A commit range:  boatman review abc123..def456
+  A pull request:  boatman review 42, #42, or a PR URL   (uses gh)
+  A patch file:    boatman review ./change.patch
+
+The review is printed as Markdown, JSON, or SARIF (--format). With --fix the
+change is applied in a fresh worktree and the refactor loop runs until the
+review passes, committing the fixes to a new local branch.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReview,
+}
+
+func init() {
+	rootCmd.AddCommand(reviewCmd)
+
+	reviewCmd.Flags().String("base", "", "Base branch to diff a branch against (default: base_branch from config)")
+	reviewCmd.Flags().String("format", "markdown", "Output format: markdown, json, or sarif")
+	reviewCmd.Flags().StringP("output", "o", "", "Write the review to this file instead of stdout")
+	reviewCmd.Flags().Bool("fix", false, "Fix the issues in a fresh worktree with the refactor loop")
+	reviewCmd.Flags().Int("max-iterations", 0, "Maximum review/refactor iterations with --fix (default: max_iterations from config)")
+	reviewCmd.Flags().Bool("post-review", false, "Post the review to the PR as a GitHub review with inline comments (PR targets only)")
+}
+
+var prRefPattern = regexp.MustCompile(`^#?\d+$|/pull/\d+`)
+
+// runReview resolves the target, reviews it, and prints the review.
+func runReview(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	format, _ := cmd.Flags().GetString("format")
+	switch format {
+	case "markdown", "json", "sarif":
+	default:
+		return fmt.Errorf("unknown --format %q (use markdown, json, or sarif)", format)
+	}
+
+	base, _ := cmd.Flags().GetString("base")
+	if base == "" {
+		base = cfg.BaseBranch
+	}
+	fix, _ := cmd.Flags().GetBool("fix")
+	postReview, _ := cmd.Flags().GetBool("post-review")
+	if maxIterations, _ := cmd.Flags().GetInt("max-iterations"); maxIterations > 0 {
+		cfg.MaxIterations = maxIterations
+	}
+
+	src, pr, err := resolveReviewSource(ctx, args[0], base)
+	if err != nil {
+		return err
+	}
+	if postReview && pr == "" {
+		return fmt.Errorf("--post-review needs a pull request target")
+	}
+	if postReview && fix {
+		return fmt.Errorf("--post-review can't be combined with --fix: the fixes aren't on the PR")
+	}
+
+	// Progress goes to stderr so the report can be piped
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = stdout }()
+
+	fmt.Printf("🔍 Reviewing %s (%d lines of diff)\n", src.Title, strings.Count(src.Diff, "\n"))
+
+	a, err := agent.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create agent: %w", err)
+	}
+
+	var rep *agent.ReviewReport
+	if fix {
+		rep, err = a.ReviewAndFix(ctx, src)
+	} else {
+		rep, err = a.Review(ctx, src)
+	}
+	if err != nil {
+		return err
+	}
+
+	if rep.WorktreePath != "" {
+		fmt.Printf("🔧 Fixes committed to %s after %d iterations\n", rep.FixBranch, rep.Iterations)
+		fmt.Printf("   Worktree: %s\n", rep.WorktreePath)
+	}
+	if usage := a.Usage(); usage.InputTokens+usage.OutputTokens > 0 {
+		fmt.Printf("💰 %d tokens ($%.2f)\n", usage.InputTokens+usage.OutputTokens, usage.TotalCostUSD)
+	}
+
+	if postReview {
+		if err := agent.PostPRReview(ctx, "", pr, rep.Result); err != nil {
+			return fmt.Errorf("failed to post review: %w", err)
+		}
+		fmt.Println("💬 Posted the review to the PR")
+	}
+
+	out, err := formatReview(rep, src.Title, format)
+	if err != nil {
+		return err
+	}
+	os.Stdout = stdout
+	output, _ := cmd.Flags().GetString("output")
+	if output == "" {
+		_, err = os.Stdout.Write(out)
+		return err
+	}
+	if err := os.WriteFile(output, out, 0644); err != nil {
+		return fmt.Errorf("failed to write review: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "📄 Review written to %s\n", output)
+	return nil
+}
+
+// formatReview renders a review report in the given format.
+func formatReview(rep *agent.ReviewReport, title, format string) ([]byte, error) {
+	result := rep.Result.ToReviewResult()
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(result, "", "  ")
+		return append(data, '\n'), err
+	case "sarif":
+		data, err := report.SARIF(result.Issues, report.Options{Diff: rep.Diff}).JSON()
+		return append(data, '\n'), err
+	default:
+		return []byte(report.Markdown(result, "Review of "+title)), nil
+	}
+}
+
+// resolveReviewSource turns a review target into the diff to review. pr is
+// set when the target is a pull request.
+func resolveReviewSource(ctx context.Context, target, base string) (src agent.ReviewSource, pr string, err error) {
+	fixBranch := fmt.Sprintf("boatman/review-fix-%s", time.Now().Format("20060102-150405"))
+
+	if info, statErr := os.Stat(target); statErr == nil && !info.IsDir() {
+		data, err := os.ReadFile(target)
+		if err != nil {
+			return src, "", fmt.Errorf("failed to read patch: %w", err)
+		}
+		return agent.ReviewSource{
+			Title:      filepath.Base(target),
+			Diff:       string(data),
+			StartPoint: "HEAD",
+			FixBranch:  fixBranch,
+		}, "", nil
+	}
+
+	if prRefPattern.MatchString(target) {
+		pr = strings.TrimPrefix(target, "#")
+		info, err := github.GetPRInfo(ctx, "", pr)
+		if err != nil {
+			return src, "", err
+		}
+		diff, err := github.GetPRDiff(ctx, "", info.Number)
+		if err != nil {
+			return src, "", err
+		}
+		// The fix worktree starts from the PR's base; fetch it so it exists locally
+		if _, err := gitOutput("fetch", "origin", info.BaseBranch); err != nil {
+			return src, "", fmt.Errorf("failed to fetch base branch %s of PR #%d: %w", info.BaseBranch, info.Number, err)
+		}
+		return agent.ReviewSource{
+			Title:       fmt.Sprintf("PR #%d (%s)", info.Number, info.Title),
+			Description: info.Title + "\n\n" + info.Body,
+			Diff:        diff,
+			StartPoint:  "origin/" + info.BaseBranch,
+			FixBranch:   fmt.Sprintf("%s-review-fix-%d", info.HeadBranch, time.Now().Unix()),
+		}, strconv.Itoa(info.Number), nil
+	}
+
+	if from, to, ok := strings.Cut(target, ".."); ok {
+		threeDot := strings.HasPrefix(to, ".")
+		to = strings.TrimPrefix(to, ".")
+		if from == "" {
+			from = "HEAD"
+		}
+		if to == "" {
+			to = "HEAD"
+		}
+		start := from
+		if threeDot {
+			if start, err = gitOutput("merge-base", from, to); err != nil {
+				return src, "", err
+			}
+		}
+		diff, err := gitOutput("diff", start, to)
+		if err != nil {
+			return src, "", err
+		}
+		return agent.ReviewSource{Title: target, Diff: diff, StartPoint: start, FixBranch: fixBranch}, "", nil
+	}
+
+	if _, err := gitOutput("rev-parse", "--verify", "--quiet", target+"^{commit}"); err != nil {
+		return src, "", fmt.Errorf("%q is not a branch, commit range, PR, or patch file", target)
+	}
+	baseRef := base
+	if _, err := gitOutput("rev-parse", "--verify", "--quiet", base+"^{commit}"); err != nil {
+		baseRef = "origin/" + base
+	}
+	start, err := gitOutput("merge-base", baseRef, target)
+	if err != nil {
+		return src, "", err
+	}
+	diff, err := gitOutput("diff", start, target)
+	if err != nil {
+		return src, "", err
+	}
+	return agent.ReviewSource{
+		Title:      target,
+		Diff:       diff,
+		StartPoint: start,
+		FixBranch:  fmt.Sprintf("%s-review-fix-%d", target, time.Now().Unix()),
+	}, "", nil
+}
+
+// gitOutput runs git in the current directory and returns its trimmed output
+// (the diff itself is returned untrimmed apart from surrounding whitespace).
+func gitOutput(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %w\nstderr: %s", strings.Join(args, " "), err, stderr.String())
+	}
+	if args[0] == "diff" {
+		return stdout.String(), nil
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
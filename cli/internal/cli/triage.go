@@ -25,7 +25,9 @@ Examples:
   boatman triage --teams ENG,FE --limit 50
   boatman triage --ticket-ids ENG-123,ENG-456
   boatman triage --teams ENG --post-comments --states backlog,unstarted
-  boatman triage --ticket-ids ENG-123 --dry-run`,
+  boatman triage --ticket-ids ENG-123 --dry-run
+  boatman triage --teams ENG --max-age 24h
+  boatman triage --ticket-ids ENG-123 --rescore`,
 	RunE: runTriage,
 }
 
@@ -43,6 +45,8 @@ func init() {
 	triageCmd.Flags().Bool("emit-events", false, "Emit JSON events to stdout for desktop app integration")
 	triageCmd.Flags().Bool("generate-plans", false, "Generate execution plans for AI_DEFINITE and AI_LIKELY tickets")
 	triageCmd.Flags().String("repo-path", "", "Path to repo for plan generation (default: current directory)")
+	triageCmd.Flags().Bool("rescore", false, "Ignore cached scores and rescore every ticket")
+	triageCmd.Flags().Duration("max-age", 0, "Rescore tickets whose cached score is older than this (default: staleness TTL)")
 }
 
 func runTriage(cmd *cobra.Command, args []string) error {
@@ -64,6 +68,8 @@ func runTriage(cmd *cobra.Command, args []string) error {
 	emitEvents, _ := cmd.Flags().GetBool("emit-events")
 	generatePlans, _ := cmd.Flags().GetBool("generate-plans")
 	repoPath, _ := cmd.Flags().GetString("repo-path")
+	rescore, _ := cmd.Flags().GetBool("rescore")
+	maxAge, _ := cmd.Flags().GetDuration("max-age")
 
 	// Require either --ticket-ids or --teams.
 	if len(ticketIDs) == 0 && len(teams) == 0 {
@@ -89,6 +95,8 @@ func runTriage(cmd *cobra.Command, args []string) error {
 		EmitEvents:    emitEvents,
 		GeneratePlans: generatePlans,
 		RepoPath:      repoPath,
+		Rescore:       rescore,
+		MaxAge:        maxAge,
 	}
 
 	if dryRun {
@@ -101,6 +109,7 @@ func runTriage(cmd *cobra.Command, args []string) error {
 	}
 
 	// --- Stage 4: Plan Generation (optional) ---
+	var planResults []plan.PlanResult
 	if generatePlans && (result.Stats.AIDefiniteCount+result.Stats.AILikelyCount) > 0 {
 		repoDir := repoPath
 		if repoDir == "" {
@@ -124,7 +133,7 @@ func runTriage(cmd *cobra.Command, args []string) error {
 		}
 
 		generator := plan.NewGenerator(cfg, repoDir)
-		planResults := generator.GenerateBatch(ctx, planTickets, planClassifications, result.ContextDocs, opts.Concurrency)
+		planResults = generator.GenerateBatch(ctx, planTickets, planClassifications, result.ContextDocs, opts.Concurrency)
 
 		// Validate each plan.
 		docMap := make(map[string]*triage.ContextDoc)
@@ -163,6 +172,35 @@ func runTriage(cmd *cobra.Command, args []string) error {
 		// Store on result as json.RawMessage.
 		result.Plans, _ = json.Marshal(planResults)
 		result.PlanStats, _ = json.Marshal(planStats)
+
+		// Persist plans so 'boatman work --from-triage' can reuse them.
+		if !dryRun {
+			dir := outputDir
+			if dir == "" {
+				dir = cfg.Triage.OutputDir
+			}
+			if err := plan.WritePlanResults(dir, planResults); err != nil {
+				fmt.Printf("⚠️  Failed to write plans: %v\n", err)
+			}
+		}
+	}
+
+	// --- Schedule: order executable tickets into dependency-aware waves ---
+	if result.Stats.AIDefiniteCount+result.Stats.AILikelyCount > 0 {
+		result.Schedule = triage.BuildSchedule(result.Tickets, result.Classifications, result.Clusters, scheduleFiles(result.Tickets, planResults))
+		if !dryRun {
+			dir := outputDir
+			if dir == "" {
+				dir = cfg.Triage.OutputDir
+			}
+			dl, err := triage.NewDecisionLog(dir)
+			if err == nil {
+				err = dl.WriteSchedule(result.Schedule)
+			}
+			if err != nil {
+				fmt.Printf("⚠️  Failed to write schedule: %v\n", err)
+			}
+		}
 	}
 
 	if !emitEvents {
@@ -216,9 +254,41 @@ func printTriageResult(result *triage.TriageResult) {
 		fmt.Printf("  Tokens used:          %d\n", result.Stats.TotalTokensUsed)
 		fmt.Printf("  Cost:                 $%.4f\n", result.Stats.TotalCostUSD)
 	}
+	if s := result.Schedule; s != nil {
+		fmt.Printf("  Schedule:             %d tickets in %d waves", len(s.Order), len(s.Waves))
+		if len(s.Blocked) > 0 {
+			fmt.Printf(" (%d blocked)", len(s.Blocked))
+		}
+		fmt.Println()
+	}
+	if result.Stats.CacheHits > 0 {
+		fmt.Printf("  Cache hits:           %d\n", result.Stats.CacheHits)
+		fmt.Printf("  Saved:                $%.4f\n", result.Stats.CacheSavedUSD)
+	}
 	fmt.Println()
 }
 
+// scheduleFiles maps each ticket to the files it is expected to touch: the
+// plan's candidate, new, and deleted files when a plan was generated,
+// otherwise the files mentioned in the ticket.
+func scheduleFiles(tickets []triage.NormalizedTicket, planResults []plan.PlanResult) map[string][]string {
+	files := make(map[string][]string, len(tickets))
+	for _, t := range tickets {
+		files[t.TicketID] = t.Signals.MentionsFiles
+	}
+	for _, pr := range planResults {
+		if pr.Plan == nil {
+			continue
+		}
+		var f []string
+		f = append(f, pr.Plan.CandidateFiles...)
+		f = append(f, pr.Plan.NewFiles...)
+		f = append(f, pr.Plan.DeletedFiles...)
+		files[pr.TicketID] = f
+	}
+	return files
+}
+
 // ticketTitle finds the title for a ticket ID from the normalized tickets list.
 func ticketTitle(tickets []triage.NormalizedTicket, ticketID string) string {
 	for _, t := range tickets {
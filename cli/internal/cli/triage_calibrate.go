@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/philjestin/boatmanmode/internal/github"
+	"github.com/philjestin/boatmanmode/internal/triage"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var triageCalibrateCmd = &cobra.Command{
+	Use:   "calibrate",
+	Short: "Compare triage predictions against execution outcomes",
+	Long: `Report how well triage categories predicted real execution outcomes.
+
+Outcomes are recorded automatically when 'boatman work' runs a ticket that has
+a classification in the triage decision log. With --refresh, open PRs are
+re-checked with gh so merges, closes, and human edits after merge are captured.
+
+The report shows per-category precision, how each rubric dimension correlates
+with success, suggested changes to the ADR-004 gate thresholds, and suggested
+cost ceilings for categories whose runs were stopped at their ceiling.
+
+Examples:
+  boatman triage calibrate
+  boatman triage calibrate --refresh --repo-path .
+  boatman triage calibrate --json`,
+	RunE: runTriageCalibrate,
+}
+
+func init() {
+	triageCmd.AddCommand(triageCalibrateCmd)
+
+	triageCalibrateCmd.Flags().String("output-dir", "", "Triage output directory containing the decision log (default: .boatman-triage)")
+	triageCalibrateCmd.Flags().Bool("refresh", false, "Re-check open PRs with gh and record merge state and human edits")
+	triageCalibrateCmd.Flags().String("repo-path", ".", "Repository used to count human edits after merge")
+	triageCalibrateCmd.Flags().Bool("json", false, "Print the report as JSON")
+}
+
+func runTriageCalibrate(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	outputDir, _ := cmd.Flags().GetString("output-dir")
+	refresh, _ := cmd.Flags().GetBool("refresh")
+	repoPath, _ := cmd.Flags().GetString("repo-path")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	if outputDir == "" {
+		outputDir = viper.GetString("triage.output_dir")
+	}
+	if outputDir == "" {
+		outputDir = ".boatman-triage"
+	}
+
+	dl, err := triage.NewDecisionLog(outputDir)
+	if err != nil {
+		return err
+	}
+
+	if refresh {
+		if err := refreshOutcomes(ctx, dl, repoPath); err != nil {
+			return fmt.Errorf("refresh outcomes: %w", err)
+		}
+	}
+
+	classifications, err := dl.ReadClassifications()
+	if err != nil {
+		return fmt.Errorf("read classifications: %w", err)
+	}
+	outcomes, err := dl.ReadOutcomes()
+	if err != nil {
+		return fmt.Errorf("read outcomes: %w", err)
+	}
+
+	overruns, err := dl.ReadOverruns()
+	if err != nil {
+		return fmt.Errorf("read overruns: %w", err)
+	}
+
+	report := triage.Calibrate(classifications, outcomes)
+	report.Ceilings = triage.SuggestCeilings(classifications, overruns)
+
+	if asJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	printCalibrationReport(report)
+	return nil
+}
+
+// refreshOutcomes re-checks every outcome whose PR is still open and records
+// a new outcome entry when the PR has been merged or closed.
+func refreshOutcomes(ctx context.Context, dl *triage.DecisionLog, repoPath string) error {
+	outcomes, err := dl.ReadOutcomes()
+	if err != nil {
+		return err
+	}
+
+	for _, o := range outcomes {
+		if o.PRURL == "" || o.PRState == triage.PRStateMerged || o.PRState == triage.PRStateClosed {
+			continue
+		}
+
+		status, err := github.GetPRStatus(ctx, repoPath, o.PRURL)
+		if err != nil {
+			fmt.Printf("⚠️  %s: %v\n", o.TicketID, err)
+			continue
+		}
+
+		switch status.State {
+		case "MERGED":
+			o.PRState = triage.PRStateMerged
+			o.HumanEditsAfterMerge = countEditsAfterMerge(ctx, repoPath, status)
+		case "CLOSED":
+			o.PRState = triage.PRStateClosed
+		default:
+			continue
+		}
+
+		o.RecordedAt = time.Now().UTC()
+		if err := dl.RecordOutcome(o); err != nil {
+			return err
+		}
+		fmt.Printf("🔄 %s: PR %s\n", o.TicketID, o.PRState)
+	}
+
+	return nil
+}
+
+// countEditsAfterMerge counts commits on the base branch after the merge
+// commit that touched any of the PR's files.
+func countEditsAfterMerge(ctx context.Context, repoPath string, status *github.PRStatus) int {
+	if status.MergeCommit == "" || len(status.Files) == 0 {
+		return 0
+	}
+
+	base := "origin/" + status.BaseBranch
+	fetch := exec.CommandContext(ctx, "git", "fetch", "--quiet", "origin", status.BaseBranch)
+	fetch.Dir = repoPath
+	_ = fetch.Run()
+
+	gitArgs := append([]string{"log", "--format=%H", status.MergeCommit + ".." + base, "--"}, status.Files...)
+	logCmd := exec.CommandContext(ctx, "git", gitArgs...)
+	logCmd.Dir = repoPath
+	output, err := logCmd.Output()
+	if err != nil {
+		return 0
+	}
+
+	out := strings.TrimSpace(string(output))
+	if out == "" {
+		return 0
+	}
+	return len(strings.Split(out, "\n"))
+}
+
+// printCalibrationReport displays the calibration report as tables.
+func printCalibrationReport(report triage.CalibrationReport) {
+	if report.TotalOutcomes == 0 {
+		fmt.Println("No execution outcomes recorded yet. Run 'boatman work' on triaged tickets first.")
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("%-24s %6s %9s %8s %8s %8s %10s %10s\n", "CATEGORY", "RUNS", "RESOLVED", "PASSED", "MERGED", "EDITED", "AVG ITERS", "PRECISION")
+	fmt.Println(strings.Repeat("-", 92))
+	for _, c := range report.Categories {
+		precision := "-"
+		if c.Resolved > 0 {
+			precision = fmt.Sprintf("%.0f%%", c.Precision*100)
+		}
+		fmt.Printf("%-24s %6d %9d %8d %8d %8d %10.1f %10s\n",
+			c.Category, c.Runs, c.Resolved, c.PassedReview, c.Merged, c.HumanEdited, c.AvgIterations, precision)
+	}
+
+	fmt.Println()
+	fmt.Printf("%-20s %12s %14s %14s\n", "DIMENSION", "CORRELATION", "MEAN SUCCESS", "MEAN FAILURE")
+	fmt.Println(strings.Repeat("-", 64))
+	for _, d := range report.Dimensions {
+		fmt.Printf("%-20s %12.2f %14.2f %14.2f\n", d.Dimension, d.Correlation, d.MeanSuccess, d.MeanFailure)
+	}
+
+	fmt.Println()
+	if len(report.Suggestions) == 0 {
+		fmt.Printf("No gate threshold changes suggested (%d resolved outcomes).\n", report.Resolved)
+	} else {
+		fmt.Println("Suggested gate changes:")
+		for _, s := range report.Suggestions {
+			fmt.Printf("  • %s: %d → %d — %s\n", s.Gate, s.Current, s.Suggested, s.Rationale)
+		}
+	}
+
+	if len(report.Ceilings) > 0 {
+		fmt.Println("\nSuggested cost ceiling changes:")
+		for _, c := range report.Ceilings {
+			unit := "tokens"
+			if c.Limit == "time" {
+				unit = "minutes"
+			}
+			fmt.Printf("  • %s %s: %d → %d (%d overruns, max used %d)\n", c.Category, unit, c.Current, c.Suggested, c.Overruns, c.MaxUsed)
+		}
+	}
+
+	if len(report.Unmatched) > 0 {
+		fmt.Printf("\n%d outcomes had no triage classification: %s\n", len(report.Unmatched), strings.Join(report.Unmatched, ", "))
+	}
+	fmt.Println()
+}
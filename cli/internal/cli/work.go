@@ -3,16 +3,21 @@ package cli
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"strings"
+	"time"
 
+	"github.com/philjestin/boatman-ecosystem/harness/review"
 	"github.com/philjestin/boatmanmode/internal/agent"
+	"github.com/philjestin/boatmanmode/internal/batch"
 	"github.com/philjestin/boatmanmode/internal/config"
 	"github.com/philjestin/boatmanmode/internal/linear"
 	"github.com/philjestin/boatmanmode/internal/plan"
 	"github.com/philjestin/boatmanmode/internal/planner"
+	"github.com/philjestin/boatmanmode/internal/scottbott"
 	"github.com/philjestin/boatmanmode/internal/task"
+	"github.com/philjestin/boatmanmode/internal/triage"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -27,6 +32,7 @@ Input modes:
   1. Linear ticket (default):    boatman work ENG-123
   2. Inline prompt:              boatman work --prompt "Add authentication"
   3. File-based prompt:          boatman work --file ./task.txt
+  4. Triage batch:               boatman work --from-triage .boatman-triage
 
 The agent will:
   1. Prepare the task
@@ -36,8 +42,13 @@ The agent will:
   5. Refactor if needed until review passes
   6. Create a pull request
 
-Flags like --title and --branch-name can override auto-generated values for prompt/file mode.`,
-	Args: cobra.ExactArgs(1),
+Flags like --title and --branch-name can override auto-generated values for prompt/file mode.
+
+With --from-triage, every selected ticket in a triage output directory is run
+in its own worktree, reusing its generated plan and cluster context. Tickets
+run in schedule order with up to --concurrency at once; one ticket failing
+does not stop the batch. A summary is written to <dir>/batches/.`,
+	Args: cobra.RangeArgs(0, 1),
 	RunE: runWork,
 }
 
@@ -63,6 +74,24 @@ func init() {
 
 	// Resume a failed execution from review/refactor stage
 	workCmd.Flags().Bool("resume", false, "Resume a failed execution from the review/refactor stage using the existing worktree")
+	workCmd.Flags().String("review-issues", "", "JSON file of review issues to address instead of the initial review (requires --resume)")
+
+	// Triage decision log to record execution outcomes into for calibration
+	workCmd.Flags().String("triage-dir", "", "Triage output directory to record the run outcome in (default: from config)")
+
+	// Batch execution of triaged tickets
+	workCmd.Flags().String("from-triage", "", "Run triaged tickets from this triage output directory")
+	workCmd.Flags().StringSlice("categories", []string{string(triage.CategoryAIDefinite)}, "Triage categories to run with --from-triage")
+	workCmd.Flags().StringSlice("tickets", nil, "Limit --from-triage to these ticket IDs")
+	workCmd.Flags().Int("concurrency", 2, "Maximum tickets to run at once with --from-triage")
+
+	// Cost ceiling (defaults to the ticket's triage cluster ceiling when triaged)
+	workCmd.Flags().Int("max-tokens", 0, "Stop the run after this many Claude tokens (default: triage cost ceiling)")
+	workCmd.Flags().Int("max-minutes", 0, "Stop the run after this many minutes (default: triage cost ceiling)")
+
+	// Review exports
+	workCmd.Flags().Bool("post-review", false, "Post the final review to the PR as a GitHub review with inline comments")
+	workCmd.Flags().String("sarif", "", "Write the run's review issues to this file as SARIF 2.1.0")
 
 	viper.BindPFlag("max_iterations", workCmd.Flags().Lookup("max-iterations"))
 	viper.BindPFlag("base_branch", workCmd.Flags().Lookup("base-branch"))
@@ -80,6 +109,16 @@ func runWork(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	if fromTriage, _ := cmd.Flags().GetString("from-triage"); fromTriage != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("--from-triage does not take a ticket argument")
+		}
+		return runWorkBatch(cmd, cfg, fromTriage)
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("requires a ticket ID, prompt, or file argument (or --from-triage)")
+	}
+
 	// Validate and parse input mode
 	t, err := parseTaskInput(cmd, args, cfg)
 	if err != nil {
@@ -96,11 +135,51 @@ func runWork(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create agent: %w", err)
 	}
 
+	a.PostReview, _ = cmd.Flags().GetBool("post-review")
+	a.SARIFPath, _ = cmd.Flags().GetString("sarif")
+
+	triageDir, _ := cmd.Flags().GetString("triage-dir")
+	if triageDir == "" {
+		triageDir = cfg.Triage.OutputDir
+	}
+
+	ceiling := triageCostCeiling(triageDir, t.GetID())
+	if maxTokens, _ := cmd.Flags().GetInt("max-tokens"); maxTokens > 0 {
+		ceiling.MaxTokensPerTicket = maxTokens
+	}
+	if maxMinutes, _ := cmd.Flags().GetInt("max-minutes"); maxMinutes > 0 {
+		ceiling.MaxAgentMinutesPerTicket = maxMinutes
+	}
+	if budget := budgetFromCeiling(ceiling); budget != nil {
+		a.Budget = budget
+		fmt.Printf("💰 Cost ceiling: %d tokens, %d minutes\n", ceiling.MaxTokensPerTicket, ceiling.MaxAgentMinutesPerTicket)
+	}
+
 	// Check for resume mode
 	resume, _ := cmd.Flags().GetBool("resume")
+	reviewIssuesFile, _ := cmd.Flags().GetString("review-issues")
+	if reviewIssuesFile != "" && !resume {
+		return fmt.Errorf("--review-issues can only be used with --resume")
+	}
 	if resume {
 		fmt.Println("♻️  Resume mode — skipping to review/refactor using existing worktree")
+		if reviewIssuesFile != "" {
+			issues, err := loadReviewIssues(reviewIssuesFile)
+			if err != nil {
+				return err
+			}
+			a.RequestedChanges = issues
+			fmt.Printf("📝 Addressing %d requested changes\n", len(issues))
+		}
 		result, err := a.ResumeWork(ctx, t)
+		if !dryRun {
+			recordTriageOutcome(triageDir, t.GetID(), result, err)
+			recordTriageOverrun(triageDir, t.GetID(), a, ceiling, result, err)
+		}
+		if agent.IsBudgetExceeded(err) {
+			fmt.Printf("🛑 %s\n", result.Message)
+			return fmt.Errorf("resume stopped: %w", err)
+		}
 		if err != nil {
 			return fmt.Errorf("resume failed: %w", err)
 		}
@@ -125,6 +204,14 @@ func runWork(cmd *cobra.Command, args []string) error {
 	}
 
 	result, err := a.Work(ctx, t)
+	if !dryRun {
+		recordTriageOutcome(triageDir, t.GetID(), result, err)
+		recordTriageOverrun(triageDir, t.GetID(), a, ceiling, result, err)
+	}
+	if agent.IsBudgetExceeded(err) {
+		fmt.Printf("🛑 %s\n", result.Message)
+		return fmt.Errorf("work stopped: %w", err)
+	}
 	if err != nil {
 		return fmt.Errorf("work failed: %w", err)
 	}
@@ -203,26 +290,146 @@ func loadTriagePlan(path string) (*planner.Plan, error) {
 		return nil, fmt.Errorf("parse plan JSON: %w", err)
 	}
 
-	// Convert triage TicketPlan → planner.Plan
-	p := &planner.Plan{
-		Summary:       tp.Approach,
-		Approach:      []string{tp.Approach},
-		RelevantFiles: tp.CandidateFiles,
-		TestStrategy:  strings.Join(tp.Validation, "\n"),
+	return batch.PlannerPlan(&tp, nil), nil
+}
+
+// loadReviewIssues reads a JSON array of review issues, as written by the
+// desktop app when changes are requested from its diff view.
+func loadReviewIssues(path string) ([]scottbott.Issue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read review issues: %w", err)
 	}
 
-	// Merge stop conditions and uncertainties into warnings.
-	for _, sc := range tp.StopConditions {
-		p.Warnings = append(p.Warnings, "STOP: "+sc)
+	var issues []review.Issue
+	if err := json.Unmarshal(data, &issues); err != nil {
+		return nil, fmt.Errorf("parse review issues JSON: %w", err)
 	}
-	for _, u := range tp.Uncertainties {
-		p.Warnings = append(p.Warnings, "UNCERTAIN: "+u)
+	if len(issues) == 0 {
+		return nil, fmt.Errorf("review issues file %s has no issues", path)
 	}
-	if tp.Rollback != "" {
-		p.Warnings = append(p.Warnings, "ROLLBACK: "+tp.Rollback)
+
+	converted := make([]scottbott.Issue, len(issues))
+	for i, issue := range issues {
+		converted[i] = scottbott.ReviewIssueToIssue(issue)
+	}
+	return converted, nil
+}
+
+// recordTriageOutcome appends the run result to the triage decision log so
+// predictions can be calibrated against what happened. Tickets that were
+// never triaged are skipped; logging failures only produce a warning.
+func recordTriageOutcome(triageDir, ticketID string, result *agent.WorkResult, runErr error) {
+	if triageDir == "" {
+		return
+	}
+	if _, err := os.Stat(triageDir); err != nil {
+		return
 	}
 
-	return p, nil
+	dl, err := triage.NewDecisionLog(triageDir)
+	if err != nil {
+		return
+	}
+	classifications, err := dl.ReadClassifications()
+	if err != nil {
+		fmt.Printf("⚠️  Could not read triage decision log: %v\n", err)
+		return
+	}
+	if _, ok := classifications[ticketID]; !ok {
+		return
+	}
+
+	outcome := triage.Outcome{TicketID: ticketID}
+	if runErr != nil {
+		outcome.Error = runErr.Error()
+	}
+	if result != nil {
+		outcome.PassedReview = result.ReviewPassed
+		outcome.Iterations = result.Iterations
+		outcome.PRURL = result.PRURL
+		if result.PRURL != "" {
+			outcome.PRState = triage.PRStateOpen
+		}
+	}
+
+	if err := dl.RecordOutcome(outcome); err != nil {
+		fmt.Printf("⚠️  Could not record triage outcome: %v\n", err)
+	}
+}
+
+// triageCostCeiling returns the cost ceiling of the triage cluster that
+// contains ticketID, or a zero ceiling if the ticket was not triaged.
+func triageCostCeiling(triageDir, ticketID string) triage.CostCeiling {
+	if triageDir == "" {
+		return triage.CostCeiling{}
+	}
+	if _, err := os.Stat(triageDir); err != nil {
+		return triage.CostCeiling{}
+	}
+
+	dl, err := triage.NewDecisionLog(triageDir)
+	if err != nil {
+		return triage.CostCeiling{}
+	}
+	docs, err := dl.ReadContextDocs()
+	if err != nil {
+		fmt.Printf("⚠️  Could not read triage context docs: %v\n", err)
+		return triage.CostCeiling{}
+	}
+	for _, doc := range docs {
+		for _, id := range doc.TicketIDs {
+			if id == ticketID {
+				return doc.CostCeiling
+			}
+		}
+	}
+	return triage.CostCeiling{}
+}
+
+// budgetFromCeiling converts a triage cost ceiling into an agent budget, or
+// nil when the ceiling sets no limits.
+func budgetFromCeiling(c triage.CostCeiling) *agent.Budget {
+	if c.MaxTokensPerTicket <= 0 && c.MaxAgentMinutesPerTicket <= 0 {
+		return nil
+	}
+	return &agent.Budget{
+		MaxTokens:   c.MaxTokensPerTicket,
+		MaxDuration: time.Duration(c.MaxAgentMinutesPerTicket) * time.Minute,
+	}
+}
+
+// recordTriageOverrun appends a cost ceiling overrun to the triage decision
+// log when the run stopped at its budget, so ceilings can be re-tuned.
+func recordTriageOverrun(triageDir, ticketID string, a *agent.Agent, ceiling triage.CostCeiling, result *agent.WorkResult, runErr error) {
+	var be *agent.BudgetExceededError
+	if triageDir == "" || !errors.As(runErr, &be) {
+		return
+	}
+
+	dl, err := triage.NewDecisionLog(triageDir)
+	if err != nil {
+		fmt.Printf("⚠️  Could not open triage decision log: %v\n", err)
+		return
+	}
+
+	overrun := triage.Overrun{
+		TicketID:       ticketID,
+		Limit:          be.Limit,
+		Step:           be.Step,
+		MaxTokens:      ceiling.MaxTokensPerTicket,
+		TokensUsed:     be.TokensUsed,
+		MaxMinutes:     ceiling.MaxAgentMinutesPerTicket,
+		ElapsedMinutes: be.Elapsed.Minutes(),
+		CostUSD:        a.Usage().TotalCostUSD,
+	}
+	if result != nil {
+		overrun.PRURL = result.PRURL
+	}
+
+	if err := dl.RecordOverrun(overrun); err != nil {
+		fmt.Printf("⚠️  Could not record cost ceiling overrun: %v\n", err)
+	}
 }
 
 // ctx is needed for CreateFromLinear
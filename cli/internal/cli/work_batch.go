@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/philjestin/boatmanmode/internal/agent"
+	"github.com/philjestin/boatmanmode/internal/batch"
+	"github.com/philjestin/boatmanmode/internal/config"
+	"github.com/philjestin/boatmanmode/internal/linear"
+	"github.com/philjestin/boatmanmode/internal/task"
+	"github.com/philjestin/boatmanmode/internal/triage"
+	"github.com/spf13/cobra"
+)
+
+// runWorkBatch runs every selected ticket from a triage output directory and
+// writes a batch summary. Individual ticket failures never abort the batch.
+func runWorkBatch(cmd *cobra.Command, cfg *config.Config, triageDir string) error {
+	ctx := context.Background()
+
+	categoryNames, _ := cmd.Flags().GetStringSlice("categories")
+	ticketIDs, _ := cmd.Flags().GetStringSlice("tickets")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	var categories []triage.Category
+	for _, name := range categoryNames {
+		categories = append(categories, triage.Category(strings.ToUpper(strings.TrimSpace(name))))
+	}
+
+	jobs, skipped, err := batch.LoadJobs(batch.Options{
+		TriageDir:   triageDir,
+		Categories:  categories,
+		TicketIDs:   ticketIDs,
+		Concurrency: concurrency,
+	})
+	if err != nil {
+		return fmt.Errorf("load triage batch: %w", err)
+	}
+
+	fmt.Printf("📦 Triage batch: %d tickets to run, %d skipped (concurrency %d)\n", len(jobs), len(skipped), concurrency)
+	for _, j := range jobs {
+		planNote := "agent will plan"
+		if j.Plan != nil {
+			planNote = "triage plan"
+		}
+		fmt.Printf("   wave %d  %-12s %-20s %s\n", j.Wave, j.TicketID, j.Category, planNote)
+	}
+	for _, s := range skipped {
+		fmt.Printf("   skip    %-12s %s\n", s.TicketID, s.Error)
+	}
+
+	if dryRun {
+		fmt.Println("🏃 Dry run mode - no tickets will be executed")
+		return nil
+	}
+
+	startedAt := time.Now().UTC()
+	results := batch.Run(ctx, jobs, concurrency, func(ctx context.Context, job batch.Job) batch.TicketResult {
+		return runTriagedTicket(ctx, cfg, triageDir, job)
+	})
+
+	summary := batch.Summarize(triageDir, startedAt, append(results, skipped...))
+	path, err := batch.WriteSummary(summary)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to write batch summary: %v\n", err)
+	}
+
+	printBatchSummary(summary)
+	if path != "" {
+		fmt.Printf("📝 Batch summary: %s\n", path)
+	}
+	return nil
+}
+
+// runTriagedTicket executes one triaged ticket with its preloaded plan,
+// stopping at the cluster's cost ceiling.
+func runTriagedTicket(ctx context.Context, cfg *config.Config, triageDir string, job batch.Job) batch.TicketResult {
+	start := time.Now()
+	res := batch.TicketResult{Status: batch.StatusFailed}
+
+	t, err := task.CreateFromLinear(ctx, linear.New(cfg.LinearKey), job.TicketID)
+	if err != nil {
+		res.Error = fmt.Sprintf("fetch ticket: %v", err)
+		return res
+	}
+
+	a, err := agent.New(cfg)
+	if err != nil {
+		res.Error = fmt.Sprintf("create agent: %v", err)
+		return res
+	}
+	if job.Plan != nil || job.ContextDoc != nil {
+		a.PreloadedPlan = batch.PlannerPlan(job.Plan, job.ContextDoc)
+	}
+	a.Budget = budgetFromCeiling(job.Ceiling)
+
+	result, runErr := a.Work(ctx, t)
+	recordTriageOutcome(triageDir, job.TicketID, result, runErr)
+	recordTriageOverrun(triageDir, job.TicketID, a, job.Ceiling, result, runErr)
+
+	usage := a.Usage()
+	res.TokensUsed = usage.InputTokens + usage.OutputTokens
+	res.CostUSD = usage.TotalCostUSD
+	res.Duration = time.Since(start)
+	res.OverCeiling = agent.IsBudgetExceeded(runErr)
+
+	if result != nil {
+		res.PRURL = result.PRURL
+		res.Iterations = result.Iterations
+		res.ReviewPassed = result.ReviewPassed
+	}
+	switch {
+	case res.OverCeiling && result != nil && result.PRCreated:
+		// Stopped at the ceiling with the draft PR preserved.
+		res.Status = batch.StatusPRCreated
+		res.Error = runErr.Error()
+	case runErr != nil:
+		res.Error = runErr.Error()
+	case result != nil && result.PRCreated:
+		res.Status = batch.StatusPRCreated
+	default:
+		res.Status = batch.StatusNoPR
+		if result != nil {
+			res.Error = result.Message
+		}
+	}
+	return res
+}
+
+// printBatchSummary displays per-ticket results and batch totals.
+func printBatchSummary(s batch.Summary) {
+	fmt.Println()
+	fmt.Printf("%-12s %-20s %5s %-11s %10s %9s  %s\n", "TICKET", "CATEGORY", "WAVE", "STATUS", "TOKENS", "COST", "DETAIL")
+	fmt.Println(strings.Repeat("-", 96))
+	for _, r := range s.Results {
+		detail := r.PRURL
+		if r.Error != "" && (r.PRURL == "" || !r.OverCeiling) {
+			detail = truncateDetail(r.Error, 60)
+		}
+		if r.OverCeiling {
+			detail = "⚠️ over ceiling " + detail
+		}
+		fmt.Printf("%-12s %-20s %5d %-11s %10d %9s  %s\n",
+			r.TicketID, r.Category, r.Wave, r.Status, r.TokensUsed, fmt.Sprintf("$%.2f", r.CostUSD), detail)
+	}
+	fmt.Println()
+	fmt.Printf("PRs created: %d | Failed: %d | Skipped: %d | Tokens: %d | Cost: $%.2f\n",
+		s.PRsCreated, s.Failed, s.Skipped, s.TotalTokens, s.TotalCostUSD)
+}
+
+// truncateDetail shortens s to at most n runes for table display.
+func truncateDetail(s string, n int) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -3,6 +3,7 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"time"
 
@@ -38,6 +39,15 @@ type Config struct {
 	// Brain settings
 	Brain BrainConfig
 
+	// Autofix settings
+	Autofix AutofixConfig
+
+	// Scope settings
+	Scope ScopeConfig
+
+	// Codegen settings
+	Codegen CodegenConfig
+
 	// Triage settings
 	Triage TriageConfig
 
@@ -63,6 +73,73 @@ type BrainConfig struct {
 	TokenBudget int
 }
 
+// AutofixConfig holds settings for the formatter and autofix pass that runs
+// on changed files after execution and each refactor.
+type AutofixConfig struct {
+	// Enabled controls whether the autofix pass runs.
+	Enabled bool
+
+	// Tools limits the pass to these fixers (gofmt, goimports, eslint,
+	// prettier, rubocop, ruff). Empty runs every fixer the repository is
+	// configured for.
+	Tools []string
+}
+
+// ScopeConfig holds settings for the check that compares the changed files
+// with the plan.
+type ScopeConfig struct {
+	// Enabled controls whether changed files are checked against the plan.
+	Enabled bool
+
+	// MaxUnplannedFiles is how many unplanned files are allowed before each
+	// of them becomes a review issue.
+	MaxUnplannedFiles int
+
+	// RevertUnplanned reverts changes to unplanned files before review.
+	RevertUnplanned bool
+}
+
+// CodegenConfig holds settings for generated files: which files are
+// generated, and the generators that regenerate them after their sources
+// change.
+type CodegenConfig struct {
+	// Enabled controls whether generators run after the agent edits their
+	// sources, and whether hand edits to generated files are restored.
+	Enabled bool
+
+	// Generated lists patterns of generated files, in .gitattributes syntax,
+	// in addition to those marked by a "Code generated ... DO NOT EDIT."
+	// header, a linguist-generated attribute or a common generated name.
+	Generated []string
+
+	// RestoreGenerated discards the agent's edits to generated files before
+	// regenerating them.
+	RestoreGenerated bool
+
+	// Generators are the repository's generators, in addition to the
+	// detected buf, GraphQL Code Generator and Wails ones.
+	Generators []GeneratorConfig
+}
+
+// GeneratorConfig maps source-of-truth files to the command that
+// regenerates the code generated from them.
+type GeneratorConfig struct {
+	// Name identifies the generator in output and the PR body.
+	Name string
+
+	// Sources are patterns of the files the code is generated from.
+	Sources []string
+
+	// Outputs are patterns of the files the generator writes.
+	Outputs []string
+
+	// Dir is the directory the command runs in, relative to the repository.
+	Dir string
+
+	// Command is the shell command that regenerates the code.
+	Command string
+}
+
 // ReviewConfig holds review pass criteria settings.
 type ReviewConfig struct {
 	// MaxCriticalIssues is the maximum number of critical issues allowed to pass.
@@ -76,6 +153,36 @@ type ReviewConfig struct {
 
 	// StrictParsing enables strict keyword matching in natural language review parsing.
 	StrictParsing bool
+
+	// StaticAnalysis runs the repository's configured linters before each
+	// review; errors they report fail the review without calling the reviewer.
+	StaticAnalysis bool
+
+	// Compile builds or typechecks the changed files before each review.
+	// Compile errors are sent back to the executor in a short repair prompt,
+	// and changes that still don't compile fail without a review.
+	Compile bool
+
+	// CompileRepairRounds is how many repair prompts are sent per review
+	// while the changes don't compile.
+	CompileRepairRounds int
+
+	// ChunkLines is the diff size, in lines, above which the review is split
+	// into chunks of related files that are reviewed concurrently. Zero
+	// disables chunking.
+	ChunkLines int
+
+	// Votes is how many independent reviews decide each review by majority.
+	// One (or less) runs a single review.
+	Votes int
+
+	// VoteQuorum is how many of the voting reviews must raise an issue for
+	// it to be kept. Zero means a majority.
+	VoteQuorum int
+
+	// VoteModels are the models the voting reviews use, in turn. Empty uses
+	// the reviewer model for every vote.
+	VoteModels []string
 }
 
 // CoordinatorConfig holds coordinator-specific settings.
@@ -198,6 +305,13 @@ func Load() (*Config, error) {
 			MaxMajorIssues:            getIntOrDefault("review.max_major_issues", 3),       // Allow 3 major (was 2)
 			MinVerificationConfidence: getIntOrDefault("review.min_verification_confidence", 50), // 50% confidence threshold
 			StrictParsing:             getBoolOrDefault("review.strict_parsing", false),    // Relaxed by default
+			StaticAnalysis:            getBoolOrDefault("review.static_analysis", true),
+			Compile:                   getBoolOrDefault("review.compile", true),
+			CompileRepairRounds:       getIntOrDefault("review.compile_repair_rounds", 3),
+			ChunkLines:                getIntOrDefault("review.chunk_lines", 1500),
+			Votes:                     getIntOrDefault("review.votes", 1),
+			VoteQuorum:                getIntOrDefault("review.vote_quorum", 0),
+			VoteModels:                getStringSliceOrDefault("review.vote_models", nil),
 		},
 
 		Coordinator: CoordinatorConfig{
@@ -241,6 +355,23 @@ func Load() (*Config, error) {
 			TokenBudget: getIntOrDefault("brain.token_budget", 2000),
 		},
 
+		Autofix: AutofixConfig{
+			Enabled: getBoolOrDefault("autofix.enabled", true),
+			Tools:   getStringSliceOrDefault("autofix.tools", nil),
+		},
+
+		Scope: ScopeConfig{
+			Enabled:           getBoolOrDefault("scope.enabled", true),
+			MaxUnplannedFiles: getIntOrDefault("scope.max_unplanned_files", 2),
+			RevertUnplanned:   getBoolOrDefault("scope.revert_unplanned", false),
+		},
+
+		Codegen: CodegenConfig{
+			Enabled:          getBoolOrDefault("codegen.enabled", true),
+			Generated:        getStringSliceOrDefault("codegen.generated", nil),
+			RestoreGenerated: getBoolOrDefault("codegen.restore_generated", true),
+		},
+
 		Triage: TriageConfig{
 			StalenessHours: getIntOrDefault("triage.staleness_hours", 168),
 			DefaultTeams:   viper.GetStringSlice("triage.default_teams"),
@@ -250,6 +381,10 @@ func Load() (*Config, error) {
 		},
 	}
 
+	if err := viper.UnmarshalKey("codegen.generators", &cfg.Codegen.Generators); err != nil {
+		return nil, fmt.Errorf("invalid codegen.generators: %w", err)
+	}
+
 	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
@@ -289,6 +424,14 @@ func getStringOrDefault(key string, defaultVal string) string {
 	return defaultVal
 }
 
+// getStringSliceOrDefault returns viper string slice value or default if not set.
+func getStringSliceOrDefault(key string, defaultVal []string) []string {
+	if viper.IsSet(key) {
+		return viper.GetStringSlice(key)
+	}
+	return defaultVal
+}
+
 // getDurationOrDefault returns viper duration value or default if not set.
 func getDurationOrDefault(key string, defaultVal time.Duration) time.Duration {
 	if viper.IsSet(key) {
@@ -43,6 +43,23 @@ func TestGetStringOrDefault(t *testing.T) {
 	}
 }
 
+func TestGetStringSliceOrDefault(t *testing.T) {
+	viper.Reset()
+
+	// Test default value
+	result := getStringSliceOrDefault("test.slice", []string{"default"})
+	if len(result) != 1 || result[0] != "default" {
+		t.Errorf("Expected [default], got %v", result)
+	}
+
+	// Test with key set
+	viper.Set("test.slice", []string{"opus", "sonnet"})
+	result = getStringSliceOrDefault("test.slice", nil)
+	if len(result) != 2 || result[1] != "sonnet" {
+		t.Errorf("Expected [opus sonnet], got %v", result)
+	}
+}
+
 func TestGetDurationOrDefault(t *testing.T) {
 	viper.Reset()
 
@@ -127,6 +144,33 @@ func TestConfigDefaultValues(t *testing.T) {
 	if cfg.Review.StrictParsing != false {
 		t.Errorf("Expected StrictParsing false, got %v", cfg.Review.StrictParsing)
 	}
+	if !cfg.Review.StaticAnalysis {
+		t.Error("Expected StaticAnalysis true")
+	}
+	if !cfg.Review.Compile || cfg.Review.CompileRepairRounds != 3 {
+		t.Errorf("Expected Compile true with 3 repair rounds, got %v with %d", cfg.Review.Compile, cfg.Review.CompileRepairRounds)
+	}
+	if cfg.Review.ChunkLines != 1500 {
+		t.Errorf("Expected ChunkLines 1500, got %d", cfg.Review.ChunkLines)
+	}
+	if cfg.Review.Votes != 1 || cfg.Review.VoteQuorum != 0 || len(cfg.Review.VoteModels) != 0 {
+		t.Errorf("Expected voting off by default, got %d votes, quorum %d, models %v", cfg.Review.Votes, cfg.Review.VoteQuorum, cfg.Review.VoteModels)
+	}
+
+	// Autofix defaults
+	if !cfg.Autofix.Enabled || len(cfg.Autofix.Tools) != 0 {
+		t.Errorf("Expected Autofix enabled with every detected tool, got %v with %v", cfg.Autofix.Enabled, cfg.Autofix.Tools)
+	}
+
+	// Scope defaults
+	if !cfg.Scope.Enabled || cfg.Scope.MaxUnplannedFiles != 2 || cfg.Scope.RevertUnplanned {
+		t.Errorf("Expected scope check on with 2 unplanned files allowed and no revert, got %+v", cfg.Scope)
+	}
+
+	// Codegen defaults
+	if !cfg.Codegen.Enabled || !cfg.Codegen.RestoreGenerated || len(cfg.Codegen.Generated) != 0 || len(cfg.Codegen.Generators) != 0 {
+		t.Errorf("Expected codegen on with only detected generators and hand edits restored, got %+v", cfg.Codegen)
+	}
 
 	// Coordinator defaults
 	if cfg.Coordinator.MessageBufferSize != 1000 {
@@ -173,6 +217,12 @@ func TestConfigCustomValues(t *testing.T) {
 	viper.Set("retry.max_attempts", 5)
 	viper.Set("claude.command", "custom-claude")
 	viper.Set("token_budget.context", 16000)
+	viper.Set("codegen.generators", []map[string]any{{
+		"name":    "sqlc",
+		"sources": []string{"db/queries/*.sql"},
+		"dir":     "db",
+		"command": "sqlc generate",
+	}})
 
 	cfg, err := Load()
 	if err != nil {
@@ -197,6 +247,12 @@ func TestConfigCustomValues(t *testing.T) {
 	if cfg.TokenBudget.Context != 16000 {
 		t.Errorf("Expected TokenBudget.Context 16000, got %d", cfg.TokenBudget.Context)
 	}
+	if len(cfg.Codegen.Generators) != 1 {
+		t.Fatalf("Expected 1 codegen generator, got %+v", cfg.Codegen.Generators)
+	}
+	if g := cfg.Codegen.Generators[0]; g.Name != "sqlc" || g.Dir != "db" || g.Command != "sqlc generate" || len(g.Sources) != 1 || g.Sources[0] != "db/queries/*.sql" {
+		t.Errorf("Unexpected codegen generator %+v", g)
+	}
 }
 
 func TestConfigDebugFromEnv(t *testing.T) {
@@ -0,0 +1,51 @@
+package executor
+
+import (
+	"github.com/philjestin/boatman-ecosystem/harness/codegen"
+	"github.com/philjestin/boatmanmode/internal/config"
+)
+
+// generatedFilesRule tells Claude to change generated code through its
+// sources; it is appended to every system prompt that edits files.
+const generatedFilesRule = `
+
+NEVER edit generated files: files with a "Code generated ... DO NOT EDIT." or "@generated" header, protobuf, GraphQL and Wails binding output, and anything under a generated/ directory.
+Edit their source of truth instead (.proto files, GraphQL schemas, the Go methods a Wails app binds). Generated code is regenerated automatically after your changes, and hand edits to it are discarded.`
+
+// NewRegenerator creates the regenerator for a worktree: the generators the
+// repository is detected to use, followed by those in the codegen config.
+func NewRegenerator(worktreePath string, cfg *config.Config) *codegen.Regenerator {
+	var generators []codegen.Generator
+	for _, g := range cfg.Codegen.Generators {
+		if g.Command == "" {
+			continue
+		}
+		generators = append(generators, codegen.Generator{
+			Name:    g.Name,
+			Sources: g.Sources,
+			Outputs: g.Outputs,
+			Dir:     g.Dir,
+			Command: []string{"sh", "-c", g.Command},
+		})
+	}
+	return codegen.New(worktreePath, generators...)
+}
+
+// newGeneratedDetector detects a worktree's generated files from their
+// markers, the configured patterns and the outputs of its generators.
+func newGeneratedDetector(worktreePath string, cfg *config.Config) *codegen.Detector {
+	patterns := append([]string{}, cfg.Codegen.Generated...)
+	patterns = append(patterns, NewRegenerator(worktreePath, cfg).Outputs()...)
+	return codegen.NewDetector(worktreePath, patterns...)
+}
+
+// DetectGeneratedFiles returns the changed files in the worktree that are
+// generated. DetectChangedFiles leaves them out.
+func (e *Executor) DetectGeneratedFiles() ([]string, error) {
+	files, err := e.statusFiles()
+	if err != nil {
+		return nil, err
+	}
+	_, generated := e.generated.Split(files)
+	return generated, nil
+}
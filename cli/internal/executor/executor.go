@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/philjestin/boatman-ecosystem/harness/codegen"
 	"github.com/philjestin/boatmanmode/internal/claude"
 	"github.com/philjestin/boatmanmode/internal/config"
 	"github.com/philjestin/boatmanmode/internal/cost"
@@ -25,7 +26,8 @@ import (
 type Executor struct {
 	client       *claude.Client
 	worktreePath string
-	brainContext string // pre-rendered brain handoff content
+	brainContext string            // pre-rendered brain handoff content
+	generated    *codegen.Detector // generated files, left out of changes and diffs
 }
 
 // BrainHandoffer is the interface for brain handoff content.
@@ -80,12 +82,23 @@ func New(worktreePath string, cfg *config.Config) *Executor {
 	return &Executor{
 		client:       client,
 		worktreePath: worktreePath,
+		generated:    newGeneratedDetector(worktreePath, cfg),
 	}
 }
 
 // NewRefactorExecutor creates an executor for a refactor iteration.
 func NewRefactorExecutor(worktreePath string, iteration int, cfg *config.Config) *Executor {
-	sessionName := fmt.Sprintf("refactor-%d", iteration)
+	return newRefactorSession(worktreePath, fmt.Sprintf("refactor-%d", iteration), cfg)
+}
+
+// NewRepairExecutor creates an executor for a compile repair round.
+func NewRepairExecutor(worktreePath string, iteration, round int, cfg *config.Config) *Executor {
+	return newRefactorSession(worktreePath, fmt.Sprintf("repair-%d-%d", iteration, round), cfg)
+}
+
+// newRefactorSession creates an executor with the refactor model and tools
+// for a fresh Claude session.
+func newRefactorSession(worktreePath, sessionName string, cfg *config.Config) *Executor {
 	var client *claude.Client
 
 	if cfg.EnableTools {
@@ -107,14 +120,14 @@ func NewRefactorExecutor(worktreePath string, iteration int, cfg *config.Config)
 	client.SkipPermissions = true
 
 	// Forward Claude stream events for desktop app visibility
-	phaseID := fmt.Sprintf("refactor-%d", iteration)
 	client.EventForwarder = func(rawLine string) {
-		events.ClaudeStream(phaseID, rawLine)
+		events.ClaudeStream(sessionName, rawLine)
 	}
 
 	return &Executor{
 		client:       client,
 		worktreePath: worktreePath,
+		generated:    newGeneratedDetector(worktreePath, cfg),
 	}
 }
 
@@ -158,7 +171,7 @@ Your response should consist primarily of tool calls (Read, Write, Edit) with br
 Do not ask for permission - just implement the solution immediately.
 
 You have been given a plan from a planning agent. Follow the approach and read the key files first.
-If implementation already exists, add tests or make improvements as needed.`
+If implementation already exists, add tests or make improvements as needed.` + generatedFilesRule
 
 	if e.brainContext != "" {
 		systemPrompt = e.brainContext + "\n\n---\n\n" + systemPrompt
@@ -225,7 +238,19 @@ func (e *Executor) DetectChangedFiles() ([]string, error) {
 }
 
 // detectChangedFiles uses git status to find what files Claude modified.
+// Generated files are left out: they inflate the prompt without useful
+// signal, and are regenerated from their sources rather than edited.
 func (e *Executor) detectChangedFiles() ([]string, error) {
+	files, err := e.statusFiles()
+	if err != nil {
+		return nil, err
+	}
+	source, _ := e.generated.Split(files)
+	return source, nil
+}
+
+// statusFiles returns every changed file in the worktree, including untracked ones.
+func (e *Executor) statusFiles() ([]string, error) {
 	// Get list of changed files (staged, unstaged, and untracked)
 	cmd := exec.Command("git", "status", "--porcelain")
 	cmd.Dir = e.worktreePath
@@ -255,11 +280,6 @@ func (e *Executor) detectChangedFiles() ([]string, error) {
 			continue
 		}
 
-		// Skip generated files that inflate the prompt without useful signal
-		if isGeneratedFile(file) {
-			continue
-		}
-
 		// Verify it's a file, not a directory
 		fullPath := filepath.Join(e.worktreePath, file)
 		if info, err := os.Stat(fullPath); err == nil && info.IsDir() {
@@ -272,39 +292,6 @@ func (e *Executor) detectChangedFiles() ([]string, error) {
 	return files, nil
 }
 
-// isGeneratedFile returns true for auto-generated files that should be excluded
-// from review/refactor prompts (e.g. protobuf outputs, codegen artifacts).
-func isGeneratedFile(file string) bool {
-	// Protobuf generated files
-	if strings.HasSuffix(file, "_pb.ts") || strings.HasSuffix(file, "_pb.js") ||
-		strings.HasSuffix(file, ".pb.go") || strings.HasSuffix(file, "_pb2.py") {
-		return true
-	}
-
-	// Files in generated/ directories
-	if strings.Contains(file, "/generated/") || strings.HasPrefix(file, "generated/") {
-		return true
-	}
-
-	// Common codegen patterns
-	if strings.Contains(file, ".generated.") || strings.HasSuffix(file, ".gen.go") ||
-		strings.HasSuffix(file, ".gen.ts") {
-		return true
-	}
-
-	// GraphQL generated files
-	if strings.HasSuffix(file, ".graphql.ts") || strings.HasSuffix(file, ".graphql.d.ts") {
-		return true
-	}
-
-	// Wails generated bindings
-	if strings.Contains(file, "wailsjs/go/") {
-		return true
-	}
-
-	return false
-}
-
 // Refactor applies feedback from ScottBott to improve the code.
 func (e *Executor) Refactor(ctx context.Context, t task.Task, reviewFeedback string, changedFiles []string) (*ExecutionResult, *cost.Usage, error) {
 	fmt.Println("   📖 Reading changed files...")
@@ -351,7 +338,7 @@ Format your response with complete file contents:
 ### FILE: path/to/file.go
 ` + "```go" + `
 // Full updated file contents
-` + "```"
+` + "```" + generatedFilesRule
 
 	fmt.Println("   🤖 Sending refactor request to Claude...")
 	fmt.Printf("   📝 Prompt size: %d chars\n", len(prompt))
@@ -407,7 +394,7 @@ Common mistakes to avoid:
 - Ignoring project-specific patterns (e.g., authorization error handling)
 - Using errors-as-data when the rules say to raise exceptions (or vice versa)
 - Missing required fields from the schema
-- Not following the project's code organization conventions`
+- Not following the project's code organization conventions` + generatedFilesRule
 
 	fmt.Printf("   📝 Handoff: %d issues, %d files\n", len(h.Issues), len(h.FilesToUpdate))
 	if h.ProjectRules != "" {
@@ -442,6 +429,59 @@ Common mistakes to avoid:
 	}, usage, nil
 }
 
+// Repair fixes compile errors with a short, targeted prompt: the compiler
+// output and the files to fix, without the task or review context a refactor
+// carries.
+func (e *Executor) Repair(ctx context.Context, compileErrors string, files []string) (*ExecutionResult, *cost.Usage, error) {
+	currentFiles, err := e.GetSpecificFiles(files)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read files: %w", err)
+	}
+	if handoff.EstimateTokens(currentFiles) > 60000 {
+		currentFiles = handoff.TruncateToTokens(currentFiles, 60000)
+	}
+
+	prompt := fmt.Sprintf(`## Compiler Errors
+%s
+
+## Files
+%s
+
+Fix these compile errors. Change only what is needed to make the code compile. Provide complete updated files.`,
+		compileErrors,
+		currentFiles)
+
+	systemPrompt := `You are fixing compile and type errors.
+Make the smallest change that fixes each error. Do not refactor, rename, or change behavior.
+
+Format your response with complete file contents:
+
+### FILE: path/to/file.go
+` + "```go" + `
+// Full updated file contents
+` + "```" + generatedFilesRule
+
+	fmt.Printf("   🩹 Repairing compile errors in %d files...\n", len(files))
+
+	start := time.Now()
+	response, usage, err := e.client.Message(ctx, systemPrompt, prompt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to call Claude: %w", err)
+	}
+	fmt.Printf("   ⏱️  Completed in %s\n", time.Since(start).Round(time.Second))
+
+	filesChanged, err := e.parseAndApplyChanges(response)
+	if err != nil {
+		return &ExecutionResult{Success: false, Error: err}, usage, nil
+	}
+
+	return &ExecutionResult{
+		Success:      true,
+		FilesChanged: filesChanged,
+		Summary:      "Repaired compile errors",
+	}, usage, nil
+}
+
 // GetSpecificFiles reads specific files from the worktree (exported for handoff).
 func (e *Executor) GetSpecificFiles(files []string) (string, error) {
 	return e.getSpecificFiles(files)
@@ -548,8 +588,20 @@ func (e *Executor) getSpecificFiles(files []string) (string, error) {
 	return sb.String(), nil
 }
 
-// GetDiff returns the git diff for the worktree.
+// GetDiff returns the git diff for the worktree. Generated files are left
+// out: they are committed with the change, but not reviewed.
 func (e *Executor) GetDiff() (string, error) {
+	diff, err := e.gitDiff()
+	if err != nil {
+		return "", err
+	}
+	diff, _ = e.generated.FilterDiff(diff)
+	return diff, nil
+}
+
+// gitDiff returns the worktree's diff against HEAD, or failing that its
+// staged or unstaged changes.
+func (e *Executor) gitDiff() (string, error) {
 	// First try diff against HEAD
 	cmd := exec.Command("git", "diff", "HEAD")
 	cmd.Dir = e.worktreePath
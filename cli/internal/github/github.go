@@ -4,8 +4,11 @@ package github
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os/exec"
+	"strconv"
 	"strings"
 )
 
@@ -105,3 +108,148 @@ func UpdatePRBody(ctx context.Context, workDir, body string) error {
 	}
 	return nil
 }
+
+// PRStatus describes the current state of an existing pull request.
+type PRStatus struct {
+	// State is one of OPEN, CLOSED, or MERGED as reported by gh.
+	State       string
+	BaseBranch  string
+	MergeCommit string
+	Files       []string
+}
+
+// GetPRStatus looks up a pull request by URL, number, or branch using gh pr view.
+func GetPRStatus(ctx context.Context, workDir, pr string) (*PRStatus, error) {
+	cmd := exec.CommandContext(ctx, "gh", "pr", "view", pr,
+		"--json", "state,baseRefName,mergeCommit,files",
+	)
+	if workDir != "" {
+		cmd.Dir = workDir
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("gh pr view failed: %w\nstderr: %s", err, stderr.String())
+	}
+
+	var raw struct {
+		State       string `json:"state"`
+		BaseRefName string `json:"baseRefName"`
+		MergeCommit *struct {
+			OID string `json:"oid"`
+		} `json:"mergeCommit"`
+		Files []struct {
+			Path string `json:"path"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &raw); err != nil {
+		return nil, fmt.Errorf("parse gh pr view output: %w", err)
+	}
+
+	status := &PRStatus{
+		State:      raw.State,
+		BaseBranch: raw.BaseRefName,
+	}
+	if raw.MergeCommit != nil {
+		status.MergeCommit = raw.MergeCommit.OID
+	}
+	for _, f := range raw.Files {
+		status.Files = append(status.Files, f.Path)
+	}
+	return status, nil
+}
+
+// PRInfo describes a pull request and the commit at its head.
+type PRInfo struct {
+	Number     int
+	Title      string
+	Body       string
+	BaseBranch string
+	HeadBranch string
+	HeadSHA    string
+}
+
+// GetPRInfo looks up a pull request by URL, number, or branch. An empty pr
+// means the current branch's PR.
+func GetPRInfo(ctx context.Context, workDir, pr string) (*PRInfo, error) {
+	args := []string{"pr", "view"}
+	if pr != "" {
+		args = append(args, pr)
+	}
+	cmd := exec.CommandContext(ctx, "gh", append(args, "--json", "number,title,body,baseRefName,headRefName,headRefOid")...)
+	if workDir != "" {
+		cmd.Dir = workDir
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("gh pr view failed: %w\nstderr: %s", err, stderr.String())
+	}
+
+	var raw struct {
+		Number      int    `json:"number"`
+		Title       string `json:"title"`
+		Body        string `json:"body"`
+		BaseRefName string `json:"baseRefName"`
+		HeadRefName string `json:"headRefName"`
+		HeadRefOID  string `json:"headRefOid"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &raw); err != nil {
+		return nil, fmt.Errorf("parse gh pr view output: %w", err)
+	}
+	return &PRInfo{
+		Number:     raw.Number,
+		Title:      raw.Title,
+		Body:       raw.Body,
+		BaseBranch: raw.BaseRefName,
+		HeadBranch: raw.HeadRefName,
+		HeadSHA:    raw.HeadRefOID,
+	}, nil
+}
+
+// GetPRDiff returns the diff GitHub shows for a pull request. Review comment
+// positions are relative to this diff.
+func GetPRDiff(ctx context.Context, workDir string, number int) (string, error) {
+	cmd := exec.CommandContext(ctx, "gh", "pr", "diff", strconv.Itoa(number), "--color", "never")
+	if workDir != "" {
+		cmd.Dir = workDir
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("gh pr diff failed: %w\nstderr: %s", err, stderr.String())
+	}
+	return stdout.String(), nil
+}
+
+// PostReview submits a pull request review through gh api. payload is the
+// JSON body of GitHub's create-review endpoint.
+func PostReview(ctx context.Context, workDir string, number int, payload []byte) error {
+	cmd := exec.CommandContext(ctx, "gh", "api",
+		fmt.Sprintf("repos/{owner}/{repo}/pulls/%d/reviews", number),
+		"--method", "POST",
+		"--input", "-",
+	)
+	if workDir != "" {
+		cmd.Dir = workDir
+	}
+	cmd.Stdin = bytes.NewReader(payload)
+
+	var stderr bytes.Buffer
+	cmd.Stdout = io.Discard
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("gh api failed: %w\nstderr: %s", err, stderr.String())
+	}
+	return nil
+}
@@ -125,6 +125,13 @@ type ReviewHandoff struct {
 	Requirements string // Concise summary of what was requested
 	Diff         string // The actual code changes
 	FilesChanged []string
+
+	// RelatedFiles are unchanged files that depend on or are dependencies of
+	// the changed files, so the reviewer can check callers.
+	RelatedFiles []string
+
+	// Knowledge is domain knowledge from brains, already sized to its budget.
+	Knowledge string
 }
 
 // NewReviewHandoff creates a handoff for code review.
@@ -153,6 +160,7 @@ func (h *ReviewHandoff) Full() string {
 	for _, f := range h.FilesChanged {
 		sb.WriteString(fmt.Sprintf("- %s\n", f))
 	}
+	h.writeContext(&sb)
 	sb.WriteString("\n## Diff\n\n```diff\n")
 	sb.WriteString(h.Diff)
 	sb.WriteString("\n```\n")
@@ -189,6 +197,7 @@ func (h *ReviewHandoff) ForTokenBudget(maxTokens int) string {
 	for _, f := range h.FilesChanged {
 		sb.WriteString(fmt.Sprintf("- %s\n", f))
 	}
+	h.writeContext(&sb)
 
 	// Calculate remaining budget for diff
 	headerTokens := EstimateTokens(sb.String())
@@ -201,6 +210,21 @@ func (h *ReviewHandoff) ForTokenBudget(maxTokens int) string {
 	return sb.String()
 }
 
+// writeContext writes the related files and domain knowledge, if any.
+func (h *ReviewHandoff) writeContext(sb *strings.Builder) {
+	if len(h.RelatedFiles) > 0 {
+		sb.WriteString("\n## Related Files (unchanged)\n\n")
+		for _, f := range h.RelatedFiles {
+			sb.WriteString(fmt.Sprintf("- %s\n", f))
+		}
+	}
+	if h.Knowledge != "" {
+		sb.WriteString("\n")
+		sb.WriteString(strings.TrimSpace(h.Knowledge))
+		sb.WriteString("\n")
+	}
+}
+
 // Type returns the handoff type.
 func (h *ReviewHandoff) Type() string {
 	return "review"
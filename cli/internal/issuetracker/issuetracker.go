@@ -90,6 +90,12 @@ func (h *IssueHistoryAdapter) RecordIteration(issues []scottbott.Issue) []Tracke
 	return h.inner.RecordIteration(reviewIssues)
 }
 
+// Issues returns every issue recorded so far, addressed or not.
+func (h *IssueHistoryAdapter) Issues() []TrackedIssue {
+	tracker := h.inner.GetTracker()
+	return append(tracker.GetUnaddressedIssues(), tracker.GetAddressedIssues()...)
+}
+
 // FormatHistory returns formatted history.
 func (h *IssueHistoryAdapter) FormatHistory() string {
 	return h.inner.FormatHistory()
@@ -0,0 +1,51 @@
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const plansFile = "plans.json"
+
+// WritePlanResults writes plan results to plans.json in a triage output
+// directory so batch execution can reuse them.
+func WritePlanResults(dir string, results []PlanResult) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating plan directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling plan results: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, plansFile), data, 0o644); err != nil {
+		return fmt.Errorf("writing plan results: %w", err)
+	}
+	return nil
+}
+
+// LoadPlanResults reads plans.json from a triage output directory and
+// returns the results keyed by ticket ID. A missing file yields an empty map.
+func LoadPlanResults(dir string) (map[string]PlanResult, error) {
+	data, err := os.ReadFile(filepath.Join(dir, plansFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]PlanResult{}, nil
+		}
+		return nil, fmt.Errorf("reading plan results: %w", err)
+	}
+
+	var results []PlanResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("parsing plan results: %w", err)
+	}
+
+	byTicket := make(map[string]PlanResult, len(results))
+	for _, r := range results {
+		byTicket[r.TicketID] = r
+	}
+	return byTicket, nil
+}
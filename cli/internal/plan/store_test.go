@@ -0,0 +1,46 @@
+package plan
+
+import (
+	"testing"
+)
+
+func TestWriteAndLoadPlanResults(t *testing.T) {
+	dir := t.TempDir()
+
+	results := []PlanResult{
+		{
+			TicketID:   "ENG-1",
+			Plan:       &TicketPlan{TicketID: "ENG-1", Approach: "Fix the handler", CandidateFiles: []string{"app/handler.go"}},
+			Validation: &PlanValidation{Passed: true},
+		},
+		{TicketID: "ENG-2", Error: "planner timed out"},
+	}
+
+	if err := WritePlanResults(dir, results); err != nil {
+		t.Fatalf("WritePlanResults failed: %v", err)
+	}
+
+	loaded, err := LoadPlanResults(dir)
+	if err != nil {
+		t.Fatalf("LoadPlanResults failed: %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(loaded))
+	}
+	if loaded["ENG-1"].Plan == nil || loaded["ENG-1"].Plan.Approach != "Fix the handler" {
+		t.Errorf("expected ENG-1 plan to round-trip, got %+v", loaded["ENG-1"])
+	}
+	if loaded["ENG-2"].Error != "planner timed out" {
+		t.Errorf("expected ENG-2 error to round-trip, got %+v", loaded["ENG-2"])
+	}
+}
+
+func TestLoadPlanResults_Missing(t *testing.T) {
+	loaded, err := LoadPlanResults(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadPlanResults failed: %v", err)
+	}
+	if len(loaded) != 0 {
+		t.Errorf("expected empty map, got %v", loaded)
+	}
+}
@@ -32,6 +32,11 @@ type Plan struct {
 	// RelevantDirs are directories to focus on
 	RelevantDirs []string `json:"relevant_dirs"`
 
+	// NewFiles and DeletedFiles are files the plan expects to create or
+	// delete. Only triage plans set them.
+	NewFiles     []string `json:"new_files,omitempty"`
+	DeletedFiles []string `json:"deleted_files,omitempty"`
+
 	// ExistingPatterns are patterns to follow
 	ExistingPatterns []string `json:"existing_patterns"`
 
@@ -0,0 +1,66 @@
+package scottbott
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/philjestin/boatman-ecosystem/harness/review"
+	"github.com/philjestin/boatmanmode/internal/config"
+	"github.com/philjestin/boatmanmode/internal/cost"
+)
+
+// sessionSeq numbers Reviewer sessions so concurrent reviews, from one
+// Reviewer or several, don't share prompt and output files.
+var sessionSeq atomic.Int64
+
+// Reviewer adapts ScottBott to review.Reviewer so harness reviewers, such as
+// the chunking and voting reviewers, can drive it. Each call runs as its own
+// session.
+type Reviewer struct {
+	workDir   string
+	iteration int
+	skill     string
+	model     string
+	cfg       *config.Config
+
+	mu    sync.Mutex
+	usage cost.Usage
+}
+
+// NewReviewer creates a Reviewer for a review iteration.
+func NewReviewer(workDir string, iteration int, skill string, cfg *config.Config) *Reviewer {
+	return &Reviewer{workDir: workDir, iteration: iteration, skill: skill, cfg: cfg}
+}
+
+// SetModel sets the model the reviews use, overriding claude.models.reviewer.
+func (r *Reviewer) SetModel(model string) {
+	r.model = model
+}
+
+// Review reviews diff with ScottBott, using reviewContext as the ticket context.
+func (r *Reviewer) Review(ctx context.Context, diff string, reviewContext string) (*review.ReviewResult, error) {
+	s := NewWithSkill(r.workDir, r.iteration, r.skill, r.cfg)
+	s.sessionName = fmt.Sprintf("reviewer-%d-%d", r.iteration, sessionSeq.Add(1))
+	if r.model != "" {
+		s.model = r.model
+	}
+	result, usage, err := s.Review(ctx, reviewContext, diff)
+	if usage != nil {
+		r.mu.Lock()
+		r.usage = r.usage.Add(*usage)
+		r.mu.Unlock()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result.ToReviewResult(), nil
+}
+
+// Usage returns the combined usage of all reviews so far.
+func (r *Reviewer) Usage() cost.Usage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.usage
+}
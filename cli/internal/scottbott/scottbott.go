@@ -3,6 +3,7 @@
 package scottbott
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -25,6 +26,10 @@ type ReviewResult struct {
 	Issues   []Issue  `json:"issues"`
 	Praise   []string `json:"praise"`
 	Guidance string   `json:"guidance"`
+
+	// Confidence is the share of voting reviews that agreed with the
+	// verdict; zero when the review wasn't voted on.
+	Confidence float64 `json:"confidence,omitempty"`
 }
 
 // Issue represents a specific problem found during review.
@@ -47,6 +52,40 @@ func IssueToReviewIssue(issue Issue) review.Issue {
 	}
 }
 
+// ToReviewResult converts the result to a review.ReviewResult.
+func (r *ReviewResult) ToReviewResult() *review.ReviewResult {
+	issues := make([]review.Issue, len(r.Issues))
+	for i, issue := range r.Issues {
+		issues[i] = IssueToReviewIssue(issue)
+	}
+	return &review.ReviewResult{
+		Passed:     r.Passed,
+		Score:      r.Score,
+		Summary:    r.Summary,
+		Issues:     issues,
+		Praise:     r.Praise,
+		Guidance:   r.Guidance,
+		Confidence: r.Confidence,
+	}
+}
+
+// FromReviewResult converts a review.ReviewResult to a ReviewResult.
+func FromReviewResult(r *review.ReviewResult) *ReviewResult {
+	issues := make([]Issue, len(r.Issues))
+	for i, issue := range r.Issues {
+		issues[i] = ReviewIssueToIssue(issue)
+	}
+	return &ReviewResult{
+		Passed:     r.Passed,
+		Score:      r.Score,
+		Summary:    r.Summary,
+		Issues:     issues,
+		Praise:     r.Praise,
+		Guidance:   r.Guidance,
+		Confidence: r.Confidence,
+	}
+}
+
 // ReviewIssueToIssue converts a review.Issue to a scottbott Issue.
 func ReviewIssueToIssue(issue review.Issue) Issue {
 	return Issue{
@@ -123,7 +162,7 @@ func NewWithSkill(workDir string, iteration int, skill string, cfg *config.Confi
 }
 
 // Review performs a code review using the peer-review Claude skill.
-// Note: Usage data is not available when using the skill/agent mode as it uses text output.
+// The returned usage is nil when the Claude CLI doesn't report it.
 func (s *ScottBott) Review(ctx context.Context, ticketContext, diff string) (*ReviewResult, *cost.Usage, error) {
 	os.MkdirAll(s.outputDir, 0755)
 
@@ -148,7 +187,7 @@ func (s *ScottBott) Review(ctx context.Context, ticketContext, diff string) (*Re
 	args := []string{
 		"-p",
 		"--agent", s.skill,
-		"--output-format", "text",
+		"--output-format", "json",
 	}
 
 	// Add model if specified
@@ -187,10 +226,12 @@ func (s *ScottBott) Review(ctx context.Context, ticketContext, diff string) (*Re
 	os.WriteFile(outputFile, output, 0644)
 
 	// Parse the response
-	response := strings.TrimSpace(string(output))
+	response, usage, err := parseCLIOutput(output)
+	if err != nil {
+		return nil, usage, err
+	}
 	result, err := s.parseReviewResponse(response)
-	// Text output format doesn't include usage data
-	return result, nil, err
+	return result, usage, err
 }
 
 // reviewWithFallback uses a system prompt if peer-review skill isn't available.
@@ -224,7 +265,7 @@ Pass if: no critical issues, ≤2 major issues, code meets requirements.`
 
 	args := []string{
 		"-p",
-		"--output-format", "text",
+		"--output-format", "json",
 		"--system-prompt", systemPrompt,
 	}
 	if s.cfg != nil && s.cfg.Claude.Effort != "" {
@@ -237,18 +278,71 @@ Pass if: no critical issues, ≤2 major issues, code meets requirements.`
 		cmd.Dir = s.workDir
 	}
 
-	output, err := cmd.CombinedOutput()
+	// Keep stderr out of the JSON on stdout
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
 	elapsed := time.Since(start)
 
 	if err != nil {
-		return nil, nil, fmt.Errorf("review failed: %w\nOutput: %s", err, string(output))
+		return nil, nil, fmt.Errorf("review failed: %w\nOutput: %s%s", err, string(output), stderr.String())
 	}
 
 	fmt.Printf("   ⏱️  Review completed in %s\n", elapsed.Round(time.Second))
 
-	result, err := s.parseReviewResponse(strings.TrimSpace(string(output)))
-	// Text output format doesn't include usage data
-	return result, nil, err
+	response, usage, err := parseCLIOutput(output)
+	if err != nil {
+		return nil, usage, err
+	}
+	result, err := s.parseReviewResponse(response)
+	return result, usage, err
+}
+
+// cliResult is the result message printed by `claude -p --output-format json`.
+type cliResult struct {
+	Type         string     `json:"type"`
+	Subtype      string     `json:"subtype"`
+	IsError      bool       `json:"is_error"`
+	Result       string     `json:"result"`
+	Usage        cost.Usage `json:"usage"`
+	TotalCostUSD float64    `json:"total_cost_usd"`
+}
+
+// parseCLIOutput extracts the response text and usage from Claude's JSON
+// output. Some CLI versions print every message as a JSON array; the result
+// message is the last one. Output that isn't JSON (a CLI that ignores the
+// output format) is returned as the response without usage.
+func parseCLIOutput(output []byte) (string, *cost.Usage, error) {
+	trimmed := bytes.TrimSpace(output)
+
+	var res cliResult
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		var messages []cliResult
+		if err := json.Unmarshal(trimmed, &messages); err != nil {
+			return string(trimmed), nil, nil
+		}
+		for _, m := range messages {
+			if m.Type == "result" {
+				res = m
+			}
+		}
+	} else if err := json.Unmarshal(trimmed, &res); err != nil {
+		return string(trimmed), nil, nil
+	}
+	if res.Type != "result" {
+		return string(trimmed), nil, nil
+	}
+
+	usage := res.Usage
+	usage.TotalCostUSD = res.TotalCostUSD
+	var usagePtr *cost.Usage
+	if !usage.IsEmpty() {
+		usagePtr = &usage
+	}
+	if res.IsError {
+		return "", usagePtr, fmt.Errorf("claude returned an error (%s): %s", res.Subtype, res.Result)
+	}
+	return strings.TrimSpace(res.Result), usagePtr, nil
 }
 
 // formatReviewPrompt creates the prompt for code review.
@@ -480,7 +574,11 @@ func (r *ReviewResult) FormatReview() string {
 	}
 	sb.WriteString("   └─────────────────────────────────────────┘\n")
 
-	sb.WriteString(fmt.Sprintf("   📊 Score: %d/100\n\n", r.Score))
+	sb.WriteString(fmt.Sprintf("   📊 Score: %d/100\n", r.Score))
+	if r.Confidence > 0 {
+		sb.WriteString(fmt.Sprintf("   🗳️  Confidence: %.0f%% of reviews agree\n", r.Confidence*100))
+	}
+	sb.WriteString("\n")
 	sb.WriteString(fmt.Sprintf("   📝 Summary:\n      %s\n\n", r.Summary))
 
 	if len(r.Praise) > 0 {
@@ -0,0 +1,70 @@
+package scottbott
+
+import "testing"
+
+func TestParseCLIOutput(t *testing.T) {
+	tests := []struct {
+		name      string
+		output    string
+		response  string
+		wantUsage bool
+		wantErr   bool
+	}{
+		{
+			name:      "result object",
+			output:    `{"type":"result","subtype":"success","is_error":false,"result":"{\"passed\": true}","usage":{"input_tokens":1200,"output_tokens":300,"cache_read_input_tokens":50},"total_cost_usd":0.02}`,
+			response:  `{"passed": true}`,
+			wantUsage: true,
+		},
+		{
+			name:      "message array",
+			output:    `[{"type":"system","subtype":"init"},{"type":"assistant"},{"type":"result","subtype":"success","result":"Looks good","usage":{"input_tokens":10,"output_tokens":5},"total_cost_usd":0.001}]`,
+			response:  "Looks good",
+			wantUsage: true,
+		},
+		{
+			name:     "plain text",
+			output:   "  VERDICT: PASS\nNo issues.\n",
+			response: "VERDICT: PASS\nNo issues.",
+		},
+		{
+			name:     "review JSON without a result wrapper",
+			output:   `{"passed": false, "score": 40}`,
+			response: `{"passed": false, "score": 40}`,
+		},
+		{
+			name:      "error result",
+			output:    `{"type":"result","subtype":"error_max_turns","is_error":true,"result":"","usage":{"input_tokens":900,"output_tokens":10},"total_cost_usd":0.01}`,
+			wantUsage: true,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, usage, err := parseCLIOutput([]byte(tt.output))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCLIOutput() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if response != tt.response {
+				t.Errorf("response = %q, want %q", response, tt.response)
+			}
+			if (usage != nil) != tt.wantUsage {
+				t.Fatalf("usage = %+v, wantUsage %v", usage, tt.wantUsage)
+			}
+		})
+	}
+}
+
+func TestParseCLIOutputUsage(t *testing.T) {
+	_, usage, err := parseCLIOutput([]byte(`{"type":"result","result":"ok","usage":{"input_tokens":1200,"output_tokens":300,"cache_read_input_tokens":50,"cache_creation_input_tokens":7},"total_cost_usd":0.02}`))
+	if err != nil {
+		t.Fatalf("parseCLIOutput() error = %v", err)
+	}
+	if usage.InputTokens != 1200 || usage.OutputTokens != 300 || usage.CacheReadTokens != 50 || usage.CacheWriteTokens != 7 {
+		t.Errorf("Unexpected token counts: %+v", usage)
+	}
+	if usage.TotalCostUSD != 0.02 {
+		t.Errorf("Expected cost 0.02, got %f", usage.TotalCostUSD)
+	}
+}
@@ -0,0 +1,305 @@
+package triage
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// minCalibrationSamples is the fewest resolved outcomes a gate needs before
+// a threshold change is suggested.
+const minCalibrationSamples = 5
+
+// CategoryCalibration measures how often tickets in one category succeeded
+// when they were actually executed.
+type CategoryCalibration struct {
+	Category      Category `json:"category"`
+	Runs          int      `json:"runs"`
+	Resolved      int      `json:"resolved"`
+	Pending       int      `json:"pending"`
+	PassedReview  int      `json:"passedReview"`
+	Merged        int      `json:"merged"`
+	Closed        int      `json:"closed"`
+	HumanEdited   int      `json:"humanEdited"`
+	Succeeded     int      `json:"succeeded"`
+	AvgIterations float64  `json:"avgIterations"`
+
+	// Precision is Succeeded / Resolved: the fraction of resolved runs in
+	// this category that were merged without human follow-up.
+	Precision float64 `json:"precision"`
+}
+
+// DimensionCorrelation relates one rubric dimension to execution success.
+type DimensionCorrelation struct {
+	Dimension string `json:"dimension"`
+
+	// Correlation is the point-biserial correlation between the score and
+	// success (1) or failure (0). Zero when either side has no variance.
+	Correlation float64 `json:"correlation"`
+	MeanSuccess float64 `json:"meanSuccess"`
+	MeanFailure float64 `json:"meanFailure"`
+}
+
+// GateSuggestion proposes a new threshold for one ADR-004 classification gate.
+type GateSuggestion struct {
+	Gate               string  `json:"gate"`
+	Dimension          string  `json:"dimension"`
+	Current            int     `json:"current"`
+	Suggested          int     `json:"suggested"`
+	CurrentPrecision   float64 `json:"currentPrecision"`
+	SuggestedPrecision float64 `json:"suggestedPrecision"`
+	CurrentSamples     int     `json:"currentSamples"`
+	SuggestedSamples   int     `json:"suggestedSamples"`
+	Rationale          string  `json:"rationale"`
+}
+
+// CalibrationReport compares triage predictions against execution outcomes.
+type CalibrationReport struct {
+	GeneratedAt   time.Time              `json:"generatedAt"`
+	TotalOutcomes int                    `json:"totalOutcomes"`
+	Resolved      int                    `json:"resolved"`
+	Categories    []CategoryCalibration  `json:"categories"`
+	Dimensions    []DimensionCorrelation `json:"dimensions"`
+	Suggestions   []GateSuggestion       `json:"suggestions"`
+
+	// Ceilings suggests cost ceiling changes from recorded overruns.
+	Ceilings []CeilingSuggestion `json:"ceilings,omitempty"`
+
+	// Unmatched lists outcome ticket IDs with no recorded classification.
+	Unmatched []string `json:"unmatched,omitempty"`
+}
+
+// calibrationSample pairs a classification with its resolved outcome.
+type calibrationSample struct {
+	rubric  RubricScores
+	success bool
+}
+
+// rubricDimension names a rubric dimension and reads it from RubricScores.
+type rubricDimension struct {
+	name  string
+	score func(RubricScores) int
+}
+
+// rubricDimensions lists all seven rubric dimensions in display order.
+var rubricDimensions = []rubricDimension{
+	{"clarity", func(r RubricScores) int { return r.Clarity }},
+	{"codeLocality", func(r RubricScores) int { return r.CodeLocality }},
+	{"patternMatch", func(r RubricScores) int { return r.PatternMatch }},
+	{"validationStrength", func(r RubricScores) int { return r.ValidationStrength }},
+	{"dependencyRisk", func(r RubricScores) int { return r.DependencyRisk }},
+	{"productAmbiguity", func(r RubricScores) int { return r.ProductAmbiguity }},
+	{"blastRadius", func(r RubricScores) int { return r.BlastRadius }},
+}
+
+// gateSpec describes a classification gate in terms calibration can vary.
+// A gate with min=true passes when score >= threshold, otherwise when
+// score <= threshold.
+type gateSpec struct {
+	gate      string
+	dimension rubricDimension
+	threshold int
+	min       bool
+}
+
+// calibrationGates mirrors evaluateGates with the current thresholds.
+var calibrationGates = []gateSpec{
+	{"CLARITY_GATE", rubricDimensions[0], clarityGate, true},
+	{"BLAST_RADIUS_GATE", rubricDimensions[6], blastRadiusGate, false},
+	{"PRODUCT_AMBIGUITY_GATE", rubricDimensions[5], productAmbiguityGate, false},
+	{"DEPENDENCY_GATE", rubricDimensions[4], dependencyGate, false},
+}
+
+// Calibrate joins outcomes to their classifications and computes per-category
+// precision, rubric-dimension correlation with success, and gate threshold
+// suggestions. Pending outcomes count toward runs but not precision.
+func Calibrate(classifications map[string]Classification, outcomes []Outcome) CalibrationReport {
+	report := CalibrationReport{
+		GeneratedAt:   time.Now().UTC(),
+		TotalOutcomes: len(outcomes),
+	}
+
+	byCategory := make(map[Category]*CategoryCalibration)
+	iterations := make(map[Category]int)
+	var samples []calibrationSample
+
+	for _, o := range outcomes {
+		c, ok := classifications[o.TicketID]
+		if !ok {
+			report.Unmatched = append(report.Unmatched, o.TicketID)
+			continue
+		}
+
+		cc := byCategory[c.Category]
+		if cc == nil {
+			cc = &CategoryCalibration{Category: c.Category}
+			byCategory[c.Category] = cc
+		}
+		cc.Runs++
+		iterations[c.Category] += o.Iterations
+
+		if o.PassedReview {
+			cc.PassedReview++
+		}
+		switch o.PRState {
+		case PRStateMerged:
+			cc.Merged++
+			if o.HumanEditsAfterMerge > 0 {
+				cc.HumanEdited++
+			}
+		case PRStateClosed:
+			cc.Closed++
+		}
+
+		if !o.Resolved() {
+			cc.Pending++
+			continue
+		}
+		cc.Resolved++
+		report.Resolved++
+		if o.Succeeded() {
+			cc.Succeeded++
+		}
+		samples = append(samples, calibrationSample{rubric: c.Rubric, success: o.Succeeded()})
+	}
+
+	for _, cat := range []Category{CategoryAIDefinite, CategoryAILikely, CategoryHumanReviewRequired, CategoryHumanOnly} {
+		cc := byCategory[cat]
+		if cc == nil {
+			continue
+		}
+		cc.AvgIterations = float64(iterations[cat]) / float64(cc.Runs)
+		if cc.Resolved > 0 {
+			cc.Precision = float64(cc.Succeeded) / float64(cc.Resolved)
+		}
+		report.Categories = append(report.Categories, *cc)
+	}
+
+	for _, dim := range rubricDimensions {
+		report.Dimensions = append(report.Dimensions, correlateDimension(dim, samples))
+	}
+
+	if len(samples) >= minCalibrationSamples {
+		for _, g := range calibrationGates {
+			if s, ok := suggestGate(g, samples); ok {
+				report.Suggestions = append(report.Suggestions, s)
+			}
+		}
+	}
+
+	return report
+}
+
+// correlateDimension computes the point-biserial correlation between one
+// rubric dimension and success across the resolved samples.
+func correlateDimension(dim rubricDimension, samples []calibrationSample) DimensionCorrelation {
+	dc := DimensionCorrelation{Dimension: dim.name}
+	if len(samples) == 0 {
+		return dc
+	}
+
+	var sumS, sumF float64
+	var nS, nF int
+	var sumX, sumX2 float64
+	for _, s := range samples {
+		x := float64(dim.score(s.rubric))
+		sumX += x
+		sumX2 += x * x
+		if s.success {
+			sumS += x
+			nS++
+		} else {
+			sumF += x
+			nF++
+		}
+	}
+	if nS > 0 {
+		dc.MeanSuccess = sumS / float64(nS)
+	}
+	if nF > 0 {
+		dc.MeanFailure = sumF / float64(nF)
+	}
+
+	n := float64(len(samples))
+	variance := sumX2/n - (sumX/n)*(sumX/n)
+	if nS == 0 || nF == 0 || variance <= 0 {
+		return dc
+	}
+
+	p := float64(nS) / n
+	dc.Correlation = (dc.MeanSuccess - dc.MeanFailure) / math.Sqrt(variance) * math.Sqrt(p*(1-p))
+	return dc
+}
+
+// gatePrecision returns the success rate and sample count of samples that
+// would pass gate g at the given threshold.
+func gatePrecision(g gateSpec, threshold int, samples []calibrationSample) (float64, int) {
+	var passed, succeeded int
+	for _, s := range samples {
+		score := g.dimension.score(s.rubric)
+		if (g.min && score >= threshold) || (!g.min && score <= threshold) {
+			passed++
+			if s.success {
+				succeeded++
+			}
+		}
+	}
+	if passed == 0 {
+		return 0, 0
+	}
+	return float64(succeeded) / float64(passed), passed
+}
+
+// suggestGate looks for a better threshold for gate g. A stricter threshold
+// is suggested when it raises precision by at least 10 points while keeping
+// half the samples; otherwise a looser threshold is suggested when it admits
+// more samples without losing more than 5 points of precision.
+func suggestGate(g gateSpec, samples []calibrationSample) (GateSuggestion, bool) {
+	curPrec, curN := gatePrecision(g, g.threshold, samples)
+	best := GateSuggestion{
+		Gate:             g.gate,
+		Dimension:        g.dimension.name,
+		Current:          g.threshold,
+		Suggested:        g.threshold,
+		CurrentPrecision: curPrec,
+		CurrentSamples:   curN,
+	}
+
+	// stricter moves the threshold toward fewer passing tickets.
+	stricter := 1
+	if !g.min {
+		stricter = -1
+	}
+
+	// Try tightening first.
+	for t := g.threshold + stricter; t >= 0 && t <= 5; t += stricter {
+		prec, n := gatePrecision(g, t, samples)
+		if n < minCalibrationSamples || n*2 < curN {
+			break
+		}
+		if prec-curPrec >= 0.10 && prec > best.SuggestedPrecision {
+			best.Suggested, best.SuggestedPrecision, best.SuggestedSamples = t, prec, n
+		}
+	}
+	if best.Suggested != g.threshold {
+		best.Rationale = fmt.Sprintf("tightening %s from %d to %d raises precision from %.0f%% to %.0f%% (%d of %d samples retained)",
+			g.dimension.name, g.threshold, best.Suggested, curPrec*100, best.SuggestedPrecision*100, best.SuggestedSamples, curN)
+		return best, true
+	}
+
+	// Otherwise try loosening, preferring the loosest threshold that holds precision.
+	for t := g.threshold - stricter; t >= 0 && t <= 5; t -= stricter {
+		prec, n := gatePrecision(g, t, samples)
+		if n <= curN || curPrec-prec > 0.05 {
+			continue
+		}
+		best.Suggested, best.SuggestedPrecision, best.SuggestedSamples = t, prec, n
+	}
+	if best.Suggested != g.threshold {
+		best.Rationale = fmt.Sprintf("loosening %s from %d to %d admits %d more samples at %.0f%% precision (currently %.0f%%)",
+			g.dimension.name, g.threshold, best.Suggested, best.SuggestedSamples-curN, best.SuggestedPrecision*100, curPrec*100)
+		return best, true
+	}
+
+	return GateSuggestion{}, false
+}
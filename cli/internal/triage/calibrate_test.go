@@ -0,0 +1,145 @@
+package triage
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCalibrate_CategoryPrecision(t *testing.T) {
+	classifications := map[string]Classification{
+		"ENG-1": {TicketID: "ENG-1", Category: CategoryAIDefinite},
+		"ENG-2": {TicketID: "ENG-2", Category: CategoryAIDefinite},
+		"ENG-3": {TicketID: "ENG-3", Category: CategoryAIDefinite},
+		"ENG-4": {TicketID: "ENG-4", Category: CategoryAILikely},
+	}
+	outcomes := []Outcome{
+		{TicketID: "ENG-1", PassedReview: true, Iterations: 1, PRState: PRStateMerged},
+		{TicketID: "ENG-2", PassedReview: false, Iterations: 3},
+		{TicketID: "ENG-3", PassedReview: true, Iterations: 2, PRState: PRStateOpen},
+		{TicketID: "ENG-4", PassedReview: true, Iterations: 1, PRState: PRStateMerged, HumanEditsAfterMerge: 3},
+		{TicketID: "ENG-99", PassedReview: true},
+	}
+
+	report := Calibrate(classifications, outcomes)
+
+	if report.TotalOutcomes != 5 {
+		t.Errorf("expected 5 outcomes, got %d", report.TotalOutcomes)
+	}
+	if report.Resolved != 3 {
+		t.Errorf("expected 3 resolved, got %d", report.Resolved)
+	}
+	if len(report.Unmatched) != 1 || report.Unmatched[0] != "ENG-99" {
+		t.Errorf("expected ENG-99 unmatched, got %v", report.Unmatched)
+	}
+	if len(report.Categories) != 2 {
+		t.Fatalf("expected 2 categories, got %d", len(report.Categories))
+	}
+
+	def := report.Categories[0]
+	if def.Category != CategoryAIDefinite {
+		t.Fatalf("expected AI_DEFINITE first, got %s", def.Category)
+	}
+	if def.Runs != 3 || def.Resolved != 2 || def.Pending != 1 || def.Succeeded != 1 {
+		t.Errorf("unexpected AI_DEFINITE counts: %+v", def)
+	}
+	if def.Precision != 0.5 {
+		t.Errorf("expected AI_DEFINITE precision 0.5, got %f", def.Precision)
+	}
+	if def.AvgIterations != 2 {
+		t.Errorf("expected avg iterations 2, got %f", def.AvgIterations)
+	}
+
+	likely := report.Categories[1]
+	if likely.HumanEdited != 1 || likely.Precision != 0 {
+		t.Errorf("expected AI_LIKELY human-edited merge to count as failure: %+v", likely)
+	}
+}
+
+func TestCalibrate_DimensionCorrelation(t *testing.T) {
+	classifications := make(map[string]Classification)
+	var outcomes []Outcome
+	for i := 0; i < 6; i++ {
+		id := fmt.Sprintf("ENG-%d", i)
+		success := i%2 == 0
+		clarity := 2
+		if success {
+			clarity = 5
+		}
+		classifications[id] = Classification{TicketID: id, Category: CategoryAILikely, Rubric: RubricScores{Clarity: clarity, BlastRadius: 1}}
+		o := Outcome{TicketID: id, PassedReview: success}
+		if success {
+			o.PRState = PRStateMerged
+		}
+		outcomes = append(outcomes, o)
+	}
+
+	report := Calibrate(classifications, outcomes)
+
+	var clarity, blast DimensionCorrelation
+	for _, d := range report.Dimensions {
+		switch d.Dimension {
+		case "clarity":
+			clarity = d
+		case "blastRadius":
+			blast = d
+		}
+	}
+	if clarity.Correlation < 0.99 {
+		t.Errorf("expected clarity perfectly correlated with success, got %f", clarity.Correlation)
+	}
+	if clarity.MeanSuccess != 5 || clarity.MeanFailure != 2 {
+		t.Errorf("unexpected clarity means: %+v", clarity)
+	}
+	if blast.Correlation != 0 {
+		t.Errorf("expected zero correlation for constant blastRadius, got %f", blast.Correlation)
+	}
+}
+
+func TestCalibrate_SuggestsTighterGate(t *testing.T) {
+	classifications := make(map[string]Classification)
+	var outcomes []Outcome
+	// Tickets with blastRadius 3 always fail; blastRadius 0-1 always succeed.
+	for i := 0; i < 12; i++ {
+		id := fmt.Sprintf("ENG-%d", i)
+		blast := i % 2 * 3
+		classifications[id] = Classification{TicketID: id, Category: CategoryAILikely, Rubric: RubricScores{Clarity: 4, BlastRadius: blast}}
+		o := Outcome{TicketID: id, PassedReview: blast == 0}
+		if blast == 0 {
+			o.PRState = PRStateMerged
+		}
+		outcomes = append(outcomes, o)
+	}
+
+	report := Calibrate(classifications, outcomes)
+
+	var found *GateSuggestion
+	for i := range report.Suggestions {
+		if report.Suggestions[i].Gate == "BLAST_RADIUS_GATE" {
+			found = &report.Suggestions[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("expected BLAST_RADIUS_GATE suggestion, got %+v", report.Suggestions)
+	}
+	if found.Current != blastRadiusGate {
+		t.Errorf("expected current %d, got %d", blastRadiusGate, found.Current)
+	}
+	if found.Suggested >= blastRadiusGate {
+		t.Errorf("expected a stricter threshold than %d, got %d", blastRadiusGate, found.Suggested)
+	}
+	if found.SuggestedPrecision != 1 {
+		t.Errorf("expected suggested precision 1.0, got %f", found.SuggestedPrecision)
+	}
+}
+
+func TestCalibrate_NoSuggestionsBelowMinSamples(t *testing.T) {
+	classifications := map[string]Classification{
+		"ENG-1": {TicketID: "ENG-1", Category: CategoryAIDefinite, Rubric: RubricScores{BlastRadius: 3}},
+	}
+	outcomes := []Outcome{{TicketID: "ENG-1", PassedReview: false}}
+
+	report := Calibrate(classifications, outcomes)
+	if len(report.Suggestions) != 0 {
+		t.Errorf("expected no suggestions with 1 sample, got %+v", report.Suggestions)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 )
 
@@ -93,6 +94,34 @@ func (dl *DecisionLog) WriteContextDoc(doc ContextDoc) error {
 	return nil
 }
 
+// ReadContextDocs reads every ContextDoc written under contexts/, ordered by
+// cluster ID. A missing contexts directory yields no docs.
+func (dl *DecisionLog) ReadContextDocs() ([]ContextDoc, error) {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(dl.dir, contextDocDir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("listing context docs: %w", err)
+	}
+	sort.Strings(paths)
+
+	var docs []ContextDoc
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return docs, fmt.Errorf("reading context doc: %w", err)
+		}
+		var doc ContextDoc
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return docs, fmt.Errorf("parsing context doc %s: %w", filepath.Base(path), err)
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
+
 // logPath returns the full path to the decision log file.
 func (dl *DecisionLog) logPath() string {
 	return filepath.Join(dl.dir, decisionLogFile)
@@ -229,6 +229,39 @@ func TestDecisionLog_WriteContextDoc(t *testing.T) {
 	}
 }
 
+func TestDecisionLog_ReadContextDocs(t *testing.T) {
+	dir := t.TempDir()
+	dl, err := NewDecisionLog(dir)
+	if err != nil {
+		t.Fatalf("NewDecisionLog failed: %v", err)
+	}
+
+	docs, err := dl.ReadContextDocs()
+	if err != nil {
+		t.Fatalf("ReadContextDocs on empty dir failed: %v", err)
+	}
+	if len(docs) != 0 {
+		t.Fatalf("expected no docs, got %d", len(docs))
+	}
+
+	for _, id := range []string{"cluster-b", "cluster-a"} {
+		if err := dl.WriteContextDoc(ContextDoc{ClusterID: id, TicketIDs: []string{id + "-ticket"}}); err != nil {
+			t.Fatalf("WriteContextDoc failed: %v", err)
+		}
+	}
+
+	docs, err = dl.ReadContextDocs()
+	if err != nil {
+		t.Fatalf("ReadContextDocs failed: %v", err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("expected 2 docs, got %d", len(docs))
+	}
+	if docs[0].ClusterID != "cluster-a" || docs[1].ClusterID != "cluster-b" {
+		t.Errorf("expected docs ordered by cluster ID, got %s, %s", docs[0].ClusterID, docs[1].ClusterID)
+	}
+}
+
 func TestDecisionLog_NDJSONFormat(t *testing.T) {
 	dir := t.TempDir()
 	dl, err := NewDecisionLog(dir)
@@ -92,6 +92,7 @@ func emitTicketScored(st ScoredTicket, index, total int) {
 		data["dependencyRisk"] = st.Response.DependencyRisk
 		data["productAmbiguity"] = st.Response.ProductAmbiguity
 		data["blastRadius"] = st.Response.BlastRadius
+		data["cached"] = st.Cached
 	}
 
 	events.Emit(events.Event{
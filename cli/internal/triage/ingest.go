@@ -67,6 +67,7 @@ func Normalize(ticket *linear.FullTicket, stalenessHours int) NormalizedTicket {
 		Description: desc,
 		IngestedAt:  now,
 		StaleAfter:  now.Add(time.Duration(stalenessHours) * time.Hour),
+		ContentHash: contentHash(ticket),
 		Signals: Signals{
 			MentionsFiles:              files,
 			Domains:                    domains,
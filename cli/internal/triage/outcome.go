@@ -0,0 +1,157 @@
+package triage
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// PRState is the lifecycle state of a pull request opened for a triaged ticket.
+type PRState string
+
+const (
+	PRStateOpen   PRState = "open"
+	PRStateMerged PRState = "merged"
+	PRStateClosed PRState = "closed"
+)
+
+// Outcome records what actually happened when a triaged ticket was executed
+// with `boatman work`. Outcomes are appended to the decision log under
+// StageOutcome; later entries for the same ticket supersede earlier ones.
+type Outcome struct {
+	TicketID     string  `json:"ticketId"`
+	PassedReview bool    `json:"passedReview"`
+	Iterations   int     `json:"iterations"`
+	PRURL        string  `json:"prUrl,omitempty"`
+	PRState      PRState `json:"prState,omitempty"`
+
+	// HumanEditsAfterMerge counts commits that touched the PR's files on the
+	// base branch after the merge commit. Non-zero means a human had to follow up.
+	HumanEditsAfterMerge int `json:"humanEditsAfterMerge"`
+
+	// Error is set when the run itself failed before producing a result.
+	Error string `json:"error,omitempty"`
+
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// Resolved reports whether the outcome is final enough to count toward
+// calibration. Runs that failed review are resolved immediately; runs that
+// passed are resolved once their PR is merged or closed.
+func (o Outcome) Resolved() bool {
+	if !o.PassedReview {
+		return true
+	}
+	return o.PRState == PRStateMerged || o.PRState == PRStateClosed
+}
+
+// Succeeded reports whether the run passed review and was merged without
+// any follow-up human edits.
+func (o Outcome) Succeeded() bool {
+	return o.PassedReview && o.PRState == PRStateMerged && o.HumanEditsAfterMerge == 0
+}
+
+// RecordOutcome appends an execution outcome for a ticket to the decision log.
+func (dl *DecisionLog) RecordOutcome(o Outcome) error {
+	if o.RecordedAt.IsZero() {
+		o.RecordedAt = time.Now().UTC()
+	}
+
+	details, err := json.Marshal(o)
+	if err != nil {
+		return fmt.Errorf("marshaling outcome: %w", err)
+	}
+
+	return dl.Append(DecisionLogEntry{
+		TicketID:  o.TicketID,
+		Stage:     StageOutcome,
+		Verdict:   outcomeVerdict(o),
+		Agent:     "boatman-work",
+		Rationale: outcomeRationale(o),
+		Timestamp: o.RecordedAt,
+		Details:   details,
+	})
+}
+
+// ReadOutcomes returns the latest recorded outcome for each ticket, in the
+// order tickets first appeared in the log.
+func (dl *DecisionLog) ReadOutcomes() ([]Outcome, error) {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	entries, err := dl.readEntries(func(e DecisionLogEntry) bool {
+		return e.Stage == StageOutcome
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var order []string
+	latest := make(map[string]Outcome)
+	for _, e := range entries {
+		var o Outcome
+		if err := json.Unmarshal(e.Details, &o); err != nil {
+			return nil, fmt.Errorf("parsing outcome for %s: %w", e.TicketID, err)
+		}
+		if _, seen := latest[o.TicketID]; !seen {
+			order = append(order, o.TicketID)
+		}
+		latest[o.TicketID] = o
+	}
+
+	outcomes := make([]Outcome, 0, len(order))
+	for _, id := range order {
+		outcomes = append(outcomes, latest[id])
+	}
+	return outcomes, nil
+}
+
+// ReadClassifications returns the latest classification recorded for each
+// ticket, keyed by ticket ID.
+func (dl *DecisionLog) ReadClassifications() (map[string]Classification, error) {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	entries, err := dl.readEntries(func(e DecisionLogEntry) bool {
+		return e.Stage == StageScore
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	classifications := make(map[string]Classification, len(entries))
+	for _, e := range entries {
+		var c Classification
+		if err := json.Unmarshal(e.Details, &c); err != nil {
+			return nil, fmt.Errorf("parsing classification for %s: %w", e.TicketID, err)
+		}
+		classifications[c.TicketID] = c
+	}
+	return classifications, nil
+}
+
+// outcomeVerdict summarizes an outcome as a short decision log verdict.
+func outcomeVerdict(o Outcome) string {
+	switch {
+	case o.Error != "":
+		return "error"
+	case !o.PassedReview:
+		return "review_failed"
+	case o.PRState == "":
+		return "review_passed"
+	default:
+		return "pr_" + string(o.PRState)
+	}
+}
+
+// outcomeRationale generates a one-line summary of the outcome.
+func outcomeRationale(o Outcome) string {
+	if o.Error != "" {
+		return fmt.Sprintf("run failed: %s", o.Error)
+	}
+	s := fmt.Sprintf("passedReview=%t after %d iterations", o.PassedReview, o.Iterations)
+	if o.PRState == PRStateMerged {
+		s += fmt.Sprintf(", merged with %d human edits after merge", o.HumanEditsAfterMerge)
+	}
+	return s
+}
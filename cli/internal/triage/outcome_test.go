@@ -0,0 +1,114 @@
+package triage
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestOutcome_ResolvedAndSucceeded(t *testing.T) {
+	tests := []struct {
+		name      string
+		outcome   Outcome
+		resolved  bool
+		succeeded bool
+	}{
+		{"review failed", Outcome{PassedReview: false}, true, false},
+		{"passed, PR open", Outcome{PassedReview: true, PRState: PRStateOpen}, false, false},
+		{"passed, PR unknown", Outcome{PassedReview: true}, false, false},
+		{"merged clean", Outcome{PassedReview: true, PRState: PRStateMerged}, true, true},
+		{"merged with edits", Outcome{PassedReview: true, PRState: PRStateMerged, HumanEditsAfterMerge: 2}, true, false},
+		{"closed", Outcome{PassedReview: true, PRState: PRStateClosed}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.outcome.Resolved(); got != tt.resolved {
+				t.Errorf("Resolved() = %v, want %v", got, tt.resolved)
+			}
+			if got := tt.outcome.Succeeded(); got != tt.succeeded {
+				t.Errorf("Succeeded() = %v, want %v", got, tt.succeeded)
+			}
+		})
+	}
+}
+
+func TestDecisionLog_RecordAndReadOutcomes(t *testing.T) {
+	dl, err := NewDecisionLog(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewDecisionLog failed: %v", err)
+	}
+
+	// Unrelated entries must be ignored.
+	dl.Append(DecisionLogEntry{TicketID: "ENG-1", Stage: StageScore, Verdict: "AI_DEFINITE", Timestamp: time.Now().UTC()})
+
+	if err := dl.RecordOutcome(Outcome{TicketID: "ENG-1", PassedReview: true, Iterations: 2, PRURL: "https://github.com/o/r/pull/1", PRState: PRStateOpen}); err != nil {
+		t.Fatalf("RecordOutcome failed: %v", err)
+	}
+	if err := dl.RecordOutcome(Outcome{TicketID: "ENG-2", Error: "execution failed"}); err != nil {
+		t.Fatalf("RecordOutcome failed: %v", err)
+	}
+	// A later entry for ENG-1 supersedes the first.
+	if err := dl.RecordOutcome(Outcome{TicketID: "ENG-1", PassedReview: true, Iterations: 2, PRURL: "https://github.com/o/r/pull/1", PRState: PRStateMerged, HumanEditsAfterMerge: 1}); err != nil {
+		t.Fatalf("RecordOutcome failed: %v", err)
+	}
+
+	outcomes, err := dl.ReadOutcomes()
+	if err != nil {
+		t.Fatalf("ReadOutcomes failed: %v", err)
+	}
+	if len(outcomes) != 2 {
+		t.Fatalf("expected 2 outcomes, got %d", len(outcomes))
+	}
+	if outcomes[0].TicketID != "ENG-1" || outcomes[1].TicketID != "ENG-2" {
+		t.Errorf("expected first-seen order ENG-1, ENG-2, got %s, %s", outcomes[0].TicketID, outcomes[1].TicketID)
+	}
+	if outcomes[0].PRState != PRStateMerged {
+		t.Errorf("expected latest ENG-1 state merged, got %s", outcomes[0].PRState)
+	}
+	if outcomes[0].HumanEditsAfterMerge != 1 {
+		t.Errorf("expected 1 human edit, got %d", outcomes[0].HumanEditsAfterMerge)
+	}
+	if outcomes[0].RecordedAt.IsZero() {
+		t.Error("expected RecordedAt to be set")
+	}
+
+	entries, _ := dl.ReadForTicket("ENG-2")
+	var verdict string
+	for _, e := range entries {
+		if e.Stage == StageOutcome {
+			verdict = e.Verdict
+		}
+	}
+	if verdict != "error" {
+		t.Errorf("expected verdict error for ENG-2, got %q", verdict)
+	}
+}
+
+func TestDecisionLog_ReadClassifications(t *testing.T) {
+	dl, err := NewDecisionLog(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewDecisionLog failed: %v", err)
+	}
+
+	for _, c := range []Classification{
+		{TicketID: "ENG-1", Category: CategoryAILikely},
+		{TicketID: "ENG-2", Category: CategoryHumanOnly},
+		{TicketID: "ENG-1", Category: CategoryAIDefinite},
+	} {
+		details, _ := json.Marshal(c)
+		dl.Append(DecisionLogEntry{TicketID: c.TicketID, Stage: StageScore, Verdict: string(c.Category), Timestamp: time.Now().UTC(), Details: details})
+	}
+	dl.RecordOutcome(Outcome{TicketID: "ENG-1", PassedReview: true})
+
+	classifications, err := dl.ReadClassifications()
+	if err != nil {
+		t.Fatalf("ReadClassifications failed: %v", err)
+	}
+	if len(classifications) != 2 {
+		t.Fatalf("expected 2 classifications, got %d", len(classifications))
+	}
+	if classifications["ENG-1"].Category != CategoryAIDefinite {
+		t.Errorf("expected latest ENG-1 classification AI_DEFINITE, got %s", classifications["ENG-1"].Category)
+	}
+}
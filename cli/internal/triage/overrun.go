@@ -0,0 +1,143 @@
+package triage
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Overrun records a run that 'boatman work' stopped because it crossed its
+// CostCeiling. Overruns are appended to the decision log under StageOverrun
+// so ceilings can be tuned against real usage.
+type Overrun struct {
+	TicketID string `json:"ticketId"`
+
+	// Limit is the ceiling that was crossed: "tokens" or "time".
+	Limit string `json:"limit"`
+
+	// Step is the workflow step that had just finished when the run stopped.
+	Step string `json:"step"`
+
+	MaxTokens      int     `json:"maxTokens"`
+	TokensUsed     int     `json:"tokensUsed"`
+	MaxMinutes     int     `json:"maxMinutes"`
+	ElapsedMinutes float64 `json:"elapsedMinutes"`
+	CostUSD        float64 `json:"costUsd,omitempty"`
+
+	// PRURL is the draft PR preserved when the run stopped, if any.
+	PRURL string `json:"prUrl,omitempty"`
+
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// CeilingSuggestion proposes a new per-ticket limit for a category based on
+// the usage of runs that overran it.
+type CeilingSuggestion struct {
+	Category  Category `json:"category"`
+	Limit     string   `json:"limit"`
+	Overruns  int      `json:"overruns"`
+	Current   int      `json:"current"`
+	MaxUsed   int      `json:"maxUsed"`
+	Suggested int      `json:"suggested"`
+}
+
+// ceilingHeadroom is the margin added over the largest observed overrun
+// when suggesting a new ceiling.
+const ceilingHeadroom = 1.25
+
+// RecordOverrun appends a cost ceiling overrun for a ticket to the decision log.
+func (dl *DecisionLog) RecordOverrun(o Overrun) error {
+	if o.RecordedAt.IsZero() {
+		o.RecordedAt = time.Now().UTC()
+	}
+
+	details, err := json.Marshal(o)
+	if err != nil {
+		return fmt.Errorf("marshaling overrun: %w", err)
+	}
+
+	rationale := fmt.Sprintf("stopped after %s: %d/%d tokens, %.1f/%d minutes",
+		o.Step, o.TokensUsed, o.MaxTokens, o.ElapsedMinutes, o.MaxMinutes)
+
+	return dl.Append(DecisionLogEntry{
+		TicketID:   o.TicketID,
+		Stage:      StageOverrun,
+		Verdict:    "over_" + o.Limit,
+		Agent:      "boatman-work",
+		Rationale:  rationale,
+		Timestamp:  o.RecordedAt,
+		TokensUsed: o.TokensUsed,
+		CostUSD:    o.CostUSD,
+		Details:    details,
+	})
+}
+
+// ReadOverruns returns every recorded overrun in log order.
+func (dl *DecisionLog) ReadOverruns() ([]Overrun, error) {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	entries, err := dl.readEntries(func(e DecisionLogEntry) bool {
+		return e.Stage == StageOverrun
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	overruns := make([]Overrun, 0, len(entries))
+	for _, e := range entries {
+		var o Overrun
+		if err := json.Unmarshal(e.Details, &o); err != nil {
+			return nil, fmt.Errorf("parsing overrun for %s: %w", e.TicketID, err)
+		}
+		overruns = append(overruns, o)
+	}
+	return overruns, nil
+}
+
+// SuggestCeilings groups overruns by category and crossed limit and suggests
+// raising each limit to the largest observed usage plus 25% headroom.
+// Overruns for tickets without a classification are ignored.
+func SuggestCeilings(classifications map[string]Classification, overruns []Overrun) []CeilingSuggestion {
+	type key struct {
+		category Category
+		limit    string
+	}
+	byKey := make(map[key]*CeilingSuggestion)
+
+	for _, o := range overruns {
+		c, ok := classifications[o.TicketID]
+		if !ok {
+			continue
+		}
+
+		k := key{c.Category, o.Limit}
+		s := byKey[k]
+		if s == nil {
+			s = &CeilingSuggestion{Category: c.Category, Limit: o.Limit}
+			byKey[k] = s
+		}
+		s.Overruns++
+
+		current, used := o.MaxTokens, o.TokensUsed
+		if o.Limit == "time" {
+			current, used = o.MaxMinutes, int(math.Ceil(o.ElapsedMinutes))
+		}
+		s.Current = max(s.Current, current)
+		s.MaxUsed = max(s.MaxUsed, used)
+	}
+
+	var suggestions []CeilingSuggestion
+	for _, cat := range []Category{CategoryAIDefinite, CategoryAILikely, CategoryHumanReviewRequired, CategoryHumanOnly} {
+		for _, limit := range []string{"tokens", "time"} {
+			s := byKey[key{cat, limit}]
+			if s == nil {
+				continue
+			}
+			s.Suggested = int(math.Ceil(float64(s.MaxUsed) * ceilingHeadroom))
+			suggestions = append(suggestions, *s)
+		}
+	}
+	return suggestions
+}
@@ -0,0 +1,73 @@
+package triage
+
+import (
+	"testing"
+)
+
+func TestDecisionLog_RecordAndReadOverruns(t *testing.T) {
+	dl, err := NewDecisionLog(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewDecisionLog failed: %v", err)
+	}
+
+	overruns, err := dl.ReadOverruns()
+	if err != nil {
+		t.Fatalf("ReadOverruns on empty log failed: %v", err)
+	}
+	if len(overruns) != 0 {
+		t.Fatalf("expected no overruns, got %d", len(overruns))
+	}
+
+	if err := dl.RecordOverrun(Overrun{TicketID: "ENG-1", Limit: "tokens", Step: "execution", MaxTokens: 500000, TokensUsed: 612000, MaxMinutes: 30, ElapsedMinutes: 12.5, PRURL: "https://github.com/org/repo/pull/1"}); err != nil {
+		t.Fatalf("RecordOverrun failed: %v", err)
+	}
+
+	entries, err := dl.ReadForTicket("ENG-1")
+	if err != nil {
+		t.Fatalf("ReadForTicket failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Stage != StageOverrun || entries[0].Verdict != "over_tokens" {
+		t.Fatalf("unexpected decision log entries: %+v", entries)
+	}
+	if entries[0].TokensUsed != 612000 {
+		t.Errorf("expected tokens on entry, got %d", entries[0].TokensUsed)
+	}
+
+	overruns, err = dl.ReadOverruns()
+	if err != nil {
+		t.Fatalf("ReadOverruns failed: %v", err)
+	}
+	if len(overruns) != 1 || overruns[0].PRURL == "" || overruns[0].RecordedAt.IsZero() {
+		t.Errorf("unexpected overruns: %+v", overruns)
+	}
+}
+
+func TestSuggestCeilings(t *testing.T) {
+	classifications := map[string]Classification{
+		"ENG-1": {TicketID: "ENG-1", Category: CategoryAIDefinite},
+		"ENG-2": {TicketID: "ENG-2", Category: CategoryAIDefinite},
+		"ENG-3": {TicketID: "ENG-3", Category: CategoryAILikely},
+	}
+	overruns := []Overrun{
+		{TicketID: "ENG-1", Limit: "tokens", MaxTokens: 500000, TokensUsed: 600000},
+		{TicketID: "ENG-2", Limit: "tokens", MaxTokens: 500000, TokensUsed: 800000},
+		{TicketID: "ENG-3", Limit: "time", MaxMinutes: 60, ElapsedMinutes: 63.2},
+		{TicketID: "ENG-9", Limit: "tokens", MaxTokens: 1, TokensUsed: 2},
+	}
+
+	got := SuggestCeilings(classifications, overruns)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 suggestions, got %+v", got)
+	}
+
+	if got[0].Category != CategoryAIDefinite || got[0].Limit != "tokens" || got[0].Overruns != 2 {
+		t.Errorf("unexpected token suggestion: %+v", got[0])
+	}
+	if got[0].Current != 500000 || got[0].MaxUsed != 800000 || got[0].Suggested != 1000000 {
+		t.Errorf("expected 500000 → 1000000 from max 800000, got %+v", got[0])
+	}
+
+	if got[1].Category != CategoryAILikely || got[1].Limit != "time" || got[1].MaxUsed != 64 || got[1].Suggested != 80 {
+		t.Errorf("unexpected time suggestion: %+v", got[1])
+	}
+}
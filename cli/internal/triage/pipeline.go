@@ -48,6 +48,13 @@ type PipelineOptions struct {
 	// RepoPath is the path to the repo for plan generation and validation.
 	// Required when GeneratePlans is true.
 	RepoPath string
+
+	// Rescore ignores cached scores and rescores every ticket with Claude.
+	Rescore bool
+
+	// MaxAge, if positive, overrides the staleness TTL for cached scores:
+	// cached entries older than MaxAge are rescored.
+	MaxAge time.Duration
 }
 
 // Pipeline orchestrates the triage stages:
@@ -107,6 +114,16 @@ func (p *Pipeline) Run(ctx context.Context, opts PipelineOptions) (*TriageResult
 	normalized := NormalizeBatch(fullTickets, p.cfg.Triage.StalenessHours)
 
 	// --- Stage 2a: Score (Claude rubric evaluation, concurrent) ---
+	cache, err := LoadScoreCache(outputDir)
+	if err != nil {
+		p.log.Warn("failed to load score cache, scoring all tickets", "error", err)
+		cache = nil
+	} else {
+		cache.MaxAge = opts.MaxAge
+	}
+	p.scorer.Cache = cache
+	p.scorer.Rescore = opts.Rescore
+
 	p.log.Info("scoring tickets", "concurrency", concurrency)
 	if opts.EmitEvents {
 		emitScoringStarted(len(normalized), concurrency)
@@ -122,6 +139,8 @@ func (p *Pipeline) Run(ctx context.Context, opts PipelineOptions) (*TriageResult
 	var totalTokens int
 	var totalCost float64
 	var failedCount int
+	var cacheHits int
+	var cacheSaved float64
 
 	for _, st := range scored {
 		if st.Err != nil {
@@ -139,6 +158,19 @@ func (p *Pipeline) Run(ctx context.Context, opts PipelineOptions) (*TriageResult
 			totalTokens += st.Usage.InputTokens + st.Usage.OutputTokens
 			totalCost += st.Usage.TotalCostUSD
 		}
+		if st.Cached {
+			cacheHits++
+			cacheSaved += st.SavedUSD
+		}
+	}
+
+	if cacheHits > 0 {
+		p.log.Info("reused cached scores", "hits", cacheHits, "savedUsd", cacheSaved)
+	}
+	if cache != nil && !opts.DryRun {
+		if err := cache.Save(); err != nil {
+			p.log.Warn("failed to save score cache", "error", err)
+		}
 	}
 
 	if opts.EmitEvents {
@@ -172,6 +204,8 @@ func (p *Pipeline) Run(ctx context.Context, opts PipelineOptions) (*TriageResult
 
 	// --- Build result ---
 	stats := buildStats(classifications, clusters, totalTokens, totalCost)
+	stats.CacheHits = cacheHits
+	stats.CacheSavedUSD = cacheSaved
 
 	result := &TriageResult{
 		Tickets:         normalized,
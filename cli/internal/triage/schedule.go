@@ -0,0 +1,424 @@
+package triage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const scheduleFile = "schedule.json"
+
+// ScheduledTicket is one executable ticket in an execution schedule.
+type ScheduledTicket struct {
+	TicketID  string   `json:"ticketId"`
+	ClusterID string   `json:"clusterId,omitempty"`
+	Category  Category `json:"category"`
+	Wave      int      `json:"wave"`
+
+	// DependsOn lists in-batch tickets that must finish first.
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// ConflictsWith lists tickets that edit overlapping files and therefore
+	// never run in the same wave.
+	ConflictsWith []string `json:"conflictsWith,omitempty"`
+
+	// ExternalDeps lists referenced tickets that are not part of this batch.
+	ExternalDeps []string `json:"externalDeps,omitempty"`
+
+	Files []string `json:"files,omitempty"`
+}
+
+// ScheduleWave is a set of tickets that can execute in parallel.
+type ScheduleWave struct {
+	Index     int      `json:"index"`
+	TicketIDs []string `json:"tickets"`
+}
+
+// ScheduledCluster records cluster-level ordering derived from ticket dependencies.
+type ScheduledCluster struct {
+	ClusterID string   `json:"clusterId"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+	TicketIDs []string `json:"tickets"`
+}
+
+// BlockedTicket is an executable ticket that cannot be scheduled.
+type BlockedTicket struct {
+	TicketID string `json:"ticketId"`
+	Reason   string `json:"reason"`
+}
+
+// Schedule is the output of the scheduling stage: an ordered plan for
+// executing AI_DEFINITE and AI_LIKELY tickets in parallelizable waves.
+type Schedule struct {
+	GeneratedAt time.Time      `json:"generatedAt"`
+	Order       []string       `json:"order"`
+	Waves       []ScheduleWave `json:"waves"`
+
+	// Tickets holds scheduled tickets in execution order; blocked tickets
+	// are listed only in Blocked.
+	Tickets  []ScheduledTicket  `json:"tickets"`
+	Clusters []ScheduledCluster `json:"clusters,omitempty"`
+	Cycles   [][]string         `json:"cycles,omitempty"`
+	Blocked  []BlockedTicket    `json:"blocked,omitempty"`
+}
+
+// BuildSchedule orders executable tickets into waves. A ticket depends on
+// every in-batch ticket its Signals.Dependencies reference; tickets whose
+// files (from ticketFiles, usually plan CandidateFiles) overlap are placed
+// in different waves. Tickets in a dependency cycle, or depending on a cycle
+// or on a non-executable ticket, are reported as blocked.
+func BuildSchedule(tickets []NormalizedTicket, classifications []Classification, clusters []Cluster, ticketFiles map[string][]string) *Schedule {
+	sched := &Schedule{GeneratedAt: time.Now().UTC()}
+
+	category := make(map[string]Category, len(classifications))
+	for _, c := range classifications {
+		category[c.TicketID] = c.Category
+	}
+	clusterOf := make(map[string]string)
+	clusterRank := make(map[string]int, len(clusters))
+	for i, cl := range clusters {
+		clusterRank[cl.ClusterID] = i
+		for _, tid := range cl.TicketIDs {
+			clusterOf[tid] = cl.ClusterID
+		}
+	}
+	inBatch := make(map[string]bool, len(tickets))
+	for _, t := range tickets {
+		inBatch[t.TicketID] = true
+	}
+
+	// Collect executable tickets and their edges.
+	nodes := make(map[string]*ScheduledTicket)
+	var ids []string
+	for _, t := range tickets {
+		cat := category[t.TicketID]
+		if cat != CategoryAIDefinite && cat != CategoryAILikely {
+			continue
+		}
+		st := &ScheduledTicket{
+			TicketID:  t.TicketID,
+			ClusterID: clusterOf[t.TicketID],
+			Category:  cat,
+			Files:     normalizeFiles(ticketFiles[t.TicketID]),
+		}
+		for _, dep := range t.Signals.Dependencies {
+			switch {
+			case dep == t.TicketID:
+				continue
+			case inBatch[dep]:
+				st.DependsOn = append(st.DependsOn, dep)
+			default:
+				st.ExternalDeps = append(st.ExternalDeps, dep)
+			}
+		}
+		nodes[t.TicketID] = st
+		ids = append(ids, t.TicketID)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return scheduleLess(nodes[ids[i]], nodes[ids[j]], clusterRank)
+	})
+
+	// Record file conflicts between executable tickets.
+	for i, a := range ids {
+		for _, b := range ids[i+1:] {
+			if filesOverlap(nodes[a].Files, nodes[b].Files) {
+				nodes[a].ConflictsWith = append(nodes[a].ConflictsWith, b)
+				nodes[b].ConflictsWith = append(nodes[b].ConflictsWith, a)
+			}
+		}
+	}
+
+	// Block tickets on cycles and on non-executable in-batch tickets, then
+	// propagate to everything downstream of a blocked ticket.
+	blocked := make(map[string]string)
+	for _, cycle := range findCycles(ids, nodes) {
+		sched.Cycles = append(sched.Cycles, cycle)
+		for _, id := range cycle {
+			blocked[id] = fmt.Sprintf("dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	for _, id := range ids {
+		for _, dep := range nodes[id].DependsOn {
+			if _, ok := nodes[dep]; !ok && blocked[id] == "" {
+				blocked[id] = fmt.Sprintf("depends on %s (%s)", dep, category[dep])
+			}
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, id := range ids {
+			if blocked[id] != "" {
+				continue
+			}
+			for _, dep := range nodes[id].DependsOn {
+				if blocked[dep] != "" {
+					blocked[id] = fmt.Sprintf("depends on blocked ticket %s", dep)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	// Assign waves: a ticket is ready once all its dependencies ran in an
+	// earlier wave, and joins the current wave unless it conflicts with a
+	// ticket already in it.
+	done := make(map[string]bool)
+	var remaining []string
+	for _, id := range ids {
+		if reason := blocked[id]; reason != "" {
+			sched.Blocked = append(sched.Blocked, BlockedTicket{TicketID: id, Reason: reason})
+			continue
+		}
+		remaining = append(remaining, id)
+	}
+	for len(remaining) > 0 {
+		wave := ScheduleWave{Index: len(sched.Waves)}
+		inWave := make(map[string]bool)
+		var next []string
+		for _, id := range remaining {
+			if !depsDone(nodes[id], done) || conflictsWithAny(nodes[id], inWave) {
+				next = append(next, id)
+				continue
+			}
+			inWave[id] = true
+			wave.TicketIDs = append(wave.TicketIDs, id)
+		}
+		if len(wave.TicketIDs) == 0 {
+			break
+		}
+		for _, id := range wave.TicketIDs {
+			done[id] = true
+			nodes[id].Wave = wave.Index
+			sched.Order = append(sched.Order, id)
+		}
+		sched.Waves = append(sched.Waves, wave)
+		remaining = next
+	}
+
+	for _, id := range sched.Order {
+		sched.Tickets = append(sched.Tickets, *nodes[id])
+	}
+	sched.Clusters = buildClusterOrder(clusters, nodes, clusterOf)
+
+	return sched
+}
+
+// WriteSchedule writes the schedule to schedule.json and records each
+// ticket's wave (or blocking reason) in the decision log.
+func (dl *DecisionLog) WriteSchedule(sched *Schedule) error {
+	data, err := json.MarshalIndent(sched, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling schedule: %w", err)
+	}
+
+	dl.mu.Lock()
+	err = os.WriteFile(filepath.Join(dl.dir, scheduleFile), data, 0o644)
+	dl.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("writing schedule: %w", err)
+	}
+
+	now := time.Now().UTC()
+	for _, st := range sched.Tickets {
+		rationale := "no in-batch dependencies"
+		if len(st.DependsOn) > 0 {
+			rationale = fmt.Sprintf("after %s", strings.Join(st.DependsOn, ", "))
+		}
+		if err := dl.Append(DecisionLogEntry{
+			TicketID:  st.TicketID,
+			Stage:     StageSchedule,
+			Verdict:   fmt.Sprintf("wave:%d", st.Wave),
+			Agent:     "triage-pipeline",
+			Rationale: rationale,
+			Timestamp: now,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, b := range sched.Blocked {
+		if err := dl.Append(DecisionLogEntry{
+			TicketID:  b.TicketID,
+			Stage:     StageSchedule,
+			Verdict:   "blocked",
+			Agent:     "triage-pipeline",
+			Rationale: b.Reason,
+			Timestamp: now,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadSchedule reads schedule.json from a triage output directory.
+func LoadSchedule(dir string) (*Schedule, error) {
+	data, err := os.ReadFile(filepath.Join(dir, scheduleFile))
+	if err != nil {
+		return nil, fmt.Errorf("reading schedule: %w", err)
+	}
+
+	var sched Schedule
+	if err := json.Unmarshal(data, &sched); err != nil {
+		return nil, fmt.Errorf("parsing schedule: %w", err)
+	}
+	return &sched, nil
+}
+
+// Ticket returns the scheduled entry for a ticket, or nil if it is not scheduled.
+func (s *Schedule) Ticket(ticketID string) *ScheduledTicket {
+	for i := range s.Tickets {
+		if s.Tickets[i].TicketID == ticketID {
+			return &s.Tickets[i]
+		}
+	}
+	return nil
+}
+
+// scheduleLess orders tickets by cluster position, then AI_DEFINITE before
+// AI_LIKELY, then ticket ID, so waves are deterministic and clusters stay together.
+func scheduleLess(a, b *ScheduledTicket, clusterRank map[string]int) bool {
+	ra, rb := clusterRank[a.ClusterID], clusterRank[b.ClusterID]
+	if ra != rb {
+		return ra < rb
+	}
+	if a.Category != b.Category {
+		return a.Category == CategoryAIDefinite
+	}
+	return a.TicketID < b.TicketID
+}
+
+// findCycles returns every strongly connected component of the dependency
+// graph that contains a cycle, using Tarjan's algorithm.
+func findCycles(ids []string, nodes map[string]*ScheduledTicket) [][]string {
+	index := 0
+	indices := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var cycles [][]string
+
+	var visit func(id string)
+	visit = func(id string) {
+		indices[id] = index
+		lowlink[id] = index
+		index++
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, dep := range nodes[id].DependsOn {
+			if _, ok := nodes[dep]; !ok {
+				continue
+			}
+			if _, seen := indices[dep]; !seen {
+				visit(dep)
+				lowlink[id] = min(lowlink[id], lowlink[dep])
+			} else if onStack[dep] {
+				lowlink[id] = min(lowlink[id], indices[dep])
+			}
+		}
+
+		if lowlink[id] == indices[id] {
+			var scc []string
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				scc = append(scc, top)
+				if top == id {
+					break
+				}
+			}
+			if len(scc) > 1 {
+				sort.Strings(scc)
+				cycles = append(cycles, scc)
+			}
+		}
+	}
+
+	for _, id := range ids {
+		if _, seen := indices[id]; !seen {
+			visit(id)
+		}
+	}
+	return cycles
+}
+
+// buildClusterOrder derives cluster-level dependencies from ticket edges.
+func buildClusterOrder(clusters []Cluster, nodes map[string]*ScheduledTicket, clusterOf map[string]string) []ScheduledCluster {
+	var result []ScheduledCluster
+	for _, cl := range clusters {
+		deps := make(map[string]bool)
+		var tickets []string
+		for _, tid := range cl.TicketIDs {
+			st, ok := nodes[tid]
+			if !ok {
+				continue
+			}
+			tickets = append(tickets, tid)
+			for _, dep := range st.DependsOn {
+				if other := clusterOf[dep]; other != "" && other != cl.ClusterID {
+					deps[other] = true
+				}
+			}
+		}
+		if len(tickets) == 0 {
+			continue
+		}
+		result = append(result, ScheduledCluster{
+			ClusterID: cl.ClusterID,
+			DependsOn: sortedKeys(deps),
+			TicketIDs: tickets,
+		})
+	}
+	return result
+}
+
+// depsDone reports whether every in-batch dependency has been scheduled.
+func depsDone(st *ScheduledTicket, done map[string]bool) bool {
+	for _, dep := range st.DependsOn {
+		if !done[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+// conflictsWithAny reports whether st conflicts with any ticket in the set.
+func conflictsWithAny(st *ScheduledTicket, set map[string]bool) bool {
+	for _, other := range st.ConflictsWith {
+		if set[other] {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeFiles cleans and deduplicates file paths.
+func normalizeFiles(files []string) []string {
+	seen := make(map[string]bool, len(files))
+	for _, f := range files {
+		f = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(f)), "./")
+		if f != "" && f != "." {
+			seen[f] = true
+		}
+	}
+	return sortedKeys(seen)
+}
+
+// filesOverlap reports whether two file sets share a path, treating one
+// path as containing another when it is a directory prefix of it.
+func filesOverlap(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y || strings.HasPrefix(y, x+"/") || strings.HasPrefix(x, y+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
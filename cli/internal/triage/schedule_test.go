@@ -0,0 +1,194 @@
+package triage
+
+import (
+	"reflect"
+	"testing"
+)
+
+// scheduleFixture builds tickets with the given dependencies, all classified
+// AI_DEFINITE unless overridden.
+func scheduleFixture(deps map[string][]string, categories map[string]Category) ([]NormalizedTicket, []Classification) {
+	var tickets []NormalizedTicket
+	var classifications []Classification
+	for _, id := range []string{"ENG-1", "ENG-2", "ENG-3", "ENG-4"} {
+		if _, ok := deps[id]; !ok {
+			continue
+		}
+		tickets = append(tickets, NormalizedTicket{TicketID: id, Signals: Signals{Dependencies: deps[id]}})
+		cat := CategoryAIDefinite
+		if c, ok := categories[id]; ok {
+			cat = c
+		}
+		classifications = append(classifications, Classification{TicketID: id, Category: cat})
+	}
+	return tickets, classifications
+}
+
+func TestBuildSchedule_DependencyWaves(t *testing.T) {
+	tickets, classifications := scheduleFixture(map[string][]string{
+		"ENG-1": nil,
+		"ENG-2": {"ENG-1"},
+		"ENG-3": {"ENG-1", "ENG-99"},
+		"ENG-4": {"ENG-2", "ENG-3"},
+	}, nil)
+
+	sched := BuildSchedule(tickets, classifications, nil, nil)
+
+	want := [][]string{{"ENG-1"}, {"ENG-2", "ENG-3"}, {"ENG-4"}}
+	if len(sched.Waves) != len(want) {
+		t.Fatalf("expected %d waves, got %+v", len(want), sched.Waves)
+	}
+	for i, w := range want {
+		if !reflect.DeepEqual(sched.Waves[i].TicketIDs, w) {
+			t.Errorf("wave %d: expected %v, got %v", i, w, sched.Waves[i].TicketIDs)
+		}
+	}
+	if !reflect.DeepEqual(sched.Order, []string{"ENG-1", "ENG-2", "ENG-3", "ENG-4"}) {
+		t.Errorf("unexpected order: %v", sched.Order)
+	}
+
+	eng3 := sched.Ticket("ENG-3")
+	if eng3 == nil {
+		t.Fatal("expected ENG-3 to be scheduled")
+	}
+	if !reflect.DeepEqual(eng3.ExternalDeps, []string{"ENG-99"}) {
+		t.Errorf("expected ENG-99 as external dependency, got %v", eng3.ExternalDeps)
+	}
+	if eng3.Wave != 1 {
+		t.Errorf("expected ENG-3 in wave 1, got %d", eng3.Wave)
+	}
+}
+
+func TestBuildSchedule_FileConflictsSplitWaves(t *testing.T) {
+	tickets, classifications := scheduleFixture(map[string][]string{
+		"ENG-1": nil,
+		"ENG-2": nil,
+		"ENG-3": nil,
+	}, nil)
+	files := map[string][]string{
+		"ENG-1": {"app/models/user.rb"},
+		"ENG-2": {"./app/models/user.rb", "app/views/user.erb"},
+		"ENG-3": {"lib/tasks"},
+	}
+
+	sched := BuildSchedule(tickets, classifications, nil, files)
+
+	if len(sched.Waves) != 2 {
+		t.Fatalf("expected 2 waves, got %+v", sched.Waves)
+	}
+	if !reflect.DeepEqual(sched.Waves[0].TicketIDs, []string{"ENG-1", "ENG-3"}) {
+		t.Errorf("wave 0: got %v", sched.Waves[0].TicketIDs)
+	}
+	if !reflect.DeepEqual(sched.Waves[1].TicketIDs, []string{"ENG-2"}) {
+		t.Errorf("wave 1: got %v", sched.Waves[1].TicketIDs)
+	}
+	if got := sched.Ticket("ENG-1").ConflictsWith; !reflect.DeepEqual(got, []string{"ENG-2"}) {
+		t.Errorf("expected ENG-1 to conflict with ENG-2, got %v", got)
+	}
+}
+
+func TestBuildSchedule_CyclesAndBlocked(t *testing.T) {
+	tickets, classifications := scheduleFixture(map[string][]string{
+		"ENG-1": {"ENG-2"},
+		"ENG-2": {"ENG-1"},
+		"ENG-3": {"ENG-2"},
+		"ENG-4": nil,
+	}, nil)
+
+	sched := BuildSchedule(tickets, classifications, nil, nil)
+
+	if len(sched.Cycles) != 1 || !reflect.DeepEqual(sched.Cycles[0], []string{"ENG-1", "ENG-2"}) {
+		t.Errorf("expected cycle [ENG-1 ENG-2], got %v", sched.Cycles)
+	}
+	if len(sched.Blocked) != 3 {
+		t.Errorf("expected 3 blocked tickets, got %+v", sched.Blocked)
+	}
+	if !reflect.DeepEqual(sched.Order, []string{"ENG-4"}) {
+		t.Errorf("expected only ENG-4 scheduled, got %v", sched.Order)
+	}
+	if sched.Ticket("ENG-3") != nil {
+		t.Error("expected blocked ticket not to be returned by Ticket")
+	}
+}
+
+func TestBuildSchedule_NonExecutableDependency(t *testing.T) {
+	tickets, classifications := scheduleFixture(map[string][]string{
+		"ENG-1": nil,
+		"ENG-2": {"ENG-1"},
+	}, map[string]Category{"ENG-1": CategoryHumanOnly})
+
+	sched := BuildSchedule(tickets, classifications, nil, nil)
+
+	if len(sched.Order) != 0 {
+		t.Errorf("expected nothing scheduled, got %v", sched.Order)
+	}
+	if len(sched.Blocked) != 1 || sched.Blocked[0].TicketID != "ENG-2" {
+		t.Fatalf("expected ENG-2 blocked, got %+v", sched.Blocked)
+	}
+	if sched.Blocked[0].Reason != "depends on ENG-1 (HUMAN_ONLY)" {
+		t.Errorf("unexpected reason: %s", sched.Blocked[0].Reason)
+	}
+}
+
+func TestBuildSchedule_ClusterOrder(t *testing.T) {
+	tickets, classifications := scheduleFixture(map[string][]string{
+		"ENG-1": nil,
+		"ENG-2": {"ENG-1"},
+	}, nil)
+	clusters := []Cluster{
+		{ClusterID: "cluster-b", TicketIDs: []string{"ENG-2"}},
+		{ClusterID: "cluster-a", TicketIDs: []string{"ENG-1"}},
+	}
+
+	sched := BuildSchedule(tickets, classifications, clusters, nil)
+
+	if len(sched.Clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %+v", sched.Clusters)
+	}
+	if !reflect.DeepEqual(sched.Clusters[0].DependsOn, []string{"cluster-a"}) {
+		t.Errorf("expected cluster-b to depend on cluster-a, got %v", sched.Clusters[0].DependsOn)
+	}
+	if sched.Ticket("ENG-2").ClusterID != "cluster-b" {
+		t.Errorf("expected ENG-2 in cluster-b")
+	}
+}
+
+func TestDecisionLog_WriteAndLoadSchedule(t *testing.T) {
+	dir := t.TempDir()
+	dl, err := NewDecisionLog(dir)
+	if err != nil {
+		t.Fatalf("NewDecisionLog failed: %v", err)
+	}
+
+	tickets, classifications := scheduleFixture(map[string][]string{
+		"ENG-1": nil,
+		"ENG-2": {"ENG-1"},
+		"ENG-3": {"ENG-3", "ENG-4"},
+		"ENG-4": {"ENG-3"},
+	}, nil)
+	sched := BuildSchedule(tickets, classifications, nil, nil)
+
+	if err := dl.WriteSchedule(sched); err != nil {
+		t.Fatalf("WriteSchedule failed: %v", err)
+	}
+
+	loaded, err := LoadSchedule(dir)
+	if err != nil {
+		t.Fatalf("LoadSchedule failed: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Order, sched.Order) {
+		t.Errorf("expected order %v, got %v", sched.Order, loaded.Order)
+	}
+
+	entries, _ := dl.ReadAll()
+	verdicts := make(map[string]string)
+	for _, e := range entries {
+		if e.Stage == StageSchedule {
+			verdicts[e.TicketID] = e.Verdict
+		}
+	}
+	want := map[string]string{"ENG-1": "wave:0", "ENG-2": "wave:1", "ENG-3": "blocked", "ENG-4": "blocked"}
+	if !reflect.DeepEqual(verdicts, want) {
+		t.Errorf("expected verdicts %v, got %v", want, verdicts)
+	}
+}
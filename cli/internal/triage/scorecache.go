@@ -0,0 +1,156 @@
+package triage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/philjestin/boatmanmode/internal/linear"
+)
+
+const scoreCacheFile = "score-cache.json"
+
+// ScoreCacheEntry is a cached scorer result for one ticket.
+type ScoreCacheEntry struct {
+	TicketID   string         `json:"ticketId"`
+	Key        string         `json:"key"`
+	Response   ScorerResponse `json:"response"`
+	ScoredAt   time.Time      `json:"scoredAt"`
+	StaleAfter time.Time      `json:"staleAfter"`
+	TokensUsed int            `json:"tokensUsed,omitempty"`
+	CostUSD    float64        `json:"costUsd,omitempty"`
+}
+
+// ScoreCache persists ScorerResponses between triage runs so unchanged
+// tickets are not rescored. Entries are keyed by ticket ID and only reused
+// when the content key still matches and the entry is not stale.
+type ScoreCache struct {
+	path    string
+	entries map[string]ScoreCacheEntry
+	mu      sync.Mutex
+
+	// MaxAge, if positive, overrides each entry's StaleAfter: entries older
+	// than MaxAge are treated as stale regardless of the staleness TTL.
+	MaxAge time.Duration
+}
+
+// LoadScoreCache reads the score cache from dir, returning an empty cache if
+// none has been written yet.
+func LoadScoreCache(dir string) (*ScoreCache, error) {
+	c := &ScoreCache{
+		path:    filepath.Join(dir, scoreCacheFile),
+		entries: make(map[string]ScoreCacheEntry),
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, fmt.Errorf("reading score cache: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		return nil, fmt.Errorf("parsing score cache: %w", err)
+	}
+	return c, nil
+}
+
+// Lookup returns the cached entry for a ticket if its key matches and it
+// has not gone stale as of now.
+func (c *ScoreCache) Lookup(ticketID, key string, now time.Time) (ScoreCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[ticketID]
+	if !ok || e.Key != key {
+		return ScoreCacheEntry{}, false
+	}
+	if c.MaxAge > 0 {
+		if now.Sub(e.ScoredAt) > c.MaxAge {
+			return ScoreCacheEntry{}, false
+		}
+	} else if now.After(e.StaleAfter) {
+		return ScoreCacheEntry{}, false
+	}
+	return e, true
+}
+
+// Put stores or replaces the cached entry for a ticket.
+func (c *ScoreCache) Put(entry ScoreCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[entry.TicketID] = entry
+}
+
+// Save writes the cache to disk, creating the directory if needed.
+func (c *ScoreCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("creating score cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling score cache: %w", err)
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("writing score cache: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("writing score cache: %w", err)
+	}
+	return nil
+}
+
+// Len returns the number of cached entries.
+func (c *ScoreCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.entries)
+}
+
+// contentHash hashes the parts of a ticket the scorer reads: title,
+// description, labels (order-insensitive), and comment bodies.
+func contentHash(ticket *linear.FullTicket) string {
+	labels := make([]string, len(ticket.Labels))
+	copy(labels, ticket.Labels)
+	sort.Strings(labels)
+
+	h := sha256.New()
+	h.Write([]byte(ticket.Title))
+	h.Write([]byte{0})
+	h.Write([]byte(ticket.Description))
+	h.Write([]byte{0})
+	h.Write([]byte(strings.Join(labels, "\x1f")))
+	for _, c := range ticket.Comments {
+		h.Write([]byte{0})
+		h.Write([]byte(c.Body))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// scoreCacheKey combines a ticket's content hash with the scorer prompt
+// version and model so prompt or model changes invalidate cached scores.
+func scoreCacheKey(ticket NormalizedTicket, model string) string {
+	h := sha256.New()
+	h.Write([]byte(scorerPromptVersion))
+	h.Write([]byte{0})
+	h.Write([]byte(model))
+	h.Write([]byte{0})
+	h.Write([]byte(ticket.ContentHash))
+	return hex.EncodeToString(h.Sum(nil))
+}
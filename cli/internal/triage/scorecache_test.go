@@ -0,0 +1,143 @@
+package triage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/philjestin/boatmanmode/internal/linear"
+)
+
+func TestContentHash(t *testing.T) {
+	base := linear.FullTicket{
+		Ticket: linear.Ticket{
+			Title:       "Fix button",
+			Description: "The button is broken",
+			Labels:      []string{"bug", "frontend"},
+		},
+		Comments: []linear.Comment{{Body: "repro attached"}},
+	}
+
+	reordered := base
+	reordered.Labels = []string{"frontend", "bug"}
+	if contentHash(&base) != contentHash(&reordered) {
+		t.Error("expected label order not to affect the hash")
+	}
+
+	commented := base
+	commented.Comments = append([]linear.Comment{}, base.Comments...)
+	commented.Comments = append(commented.Comments, linear.Comment{Body: "actually it's the API"})
+	if contentHash(&base) == contentHash(&commented) {
+		t.Error("expected a new comment to change the hash")
+	}
+
+	retitled := base
+	retitled.Title = "Fix the button"
+	if contentHash(&base) == contentHash(&retitled) {
+		t.Error("expected a title change to change the hash")
+	}
+}
+
+func TestScoreCache_Lookup(t *testing.T) {
+	now := time.Now()
+	c, err := LoadScoreCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadScoreCache failed: %v", err)
+	}
+
+	c.Put(ScoreCacheEntry{
+		TicketID:   "ENG-1",
+		Key:        "k1",
+		Response:   ScorerResponse{RubricScores: RubricScores{Clarity: 4}},
+		ScoredAt:   now.Add(-48 * time.Hour),
+		StaleAfter: now.Add(24 * time.Hour),
+		CostUSD:    0.02,
+	})
+
+	if e, ok := c.Lookup("ENG-1", "k1", now); !ok || e.Response.Clarity != 4 {
+		t.Errorf("expected cache hit, got ok=%v entry=%+v", ok, e)
+	}
+	if _, ok := c.Lookup("ENG-1", "k2", now); ok {
+		t.Error("expected miss on key mismatch")
+	}
+	if _, ok := c.Lookup("ENG-2", "k1", now); ok {
+		t.Error("expected miss for unknown ticket")
+	}
+	if _, ok := c.Lookup("ENG-1", "k1", now.Add(48*time.Hour)); ok {
+		t.Error("expected miss after StaleAfter")
+	}
+
+	c.MaxAge = 24 * time.Hour
+	if _, ok := c.Lookup("ENG-1", "k1", now); ok {
+		t.Error("expected MaxAge to override StaleAfter")
+	}
+	c.MaxAge = 72 * time.Hour
+	if _, ok := c.Lookup("ENG-1", "k1", now.Add(12*time.Hour)); !ok {
+		t.Error("expected hit within MaxAge")
+	}
+}
+
+func TestScoreCache_SaveAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	c, err := LoadScoreCache(dir)
+	if err != nil {
+		t.Fatalf("LoadScoreCache failed: %v", err)
+	}
+	c.Put(ScoreCacheEntry{TicketID: "ENG-1", Key: "k1", StaleAfter: time.Now().Add(time.Hour)})
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadScoreCache(dir)
+	if err != nil {
+		t.Fatalf("LoadScoreCache failed: %v", err)
+	}
+	if loaded.Len() != 1 {
+		t.Fatalf("expected 1 entry, got %d", loaded.Len())
+	}
+	if _, ok := loaded.Lookup("ENG-1", "k1", time.Now()); !ok {
+		t.Error("expected loaded entry to be usable")
+	}
+}
+
+func TestScoreBatch_UsesCache(t *testing.T) {
+	c, _ := LoadScoreCache(t.TempDir())
+	ticket := NormalizedTicket{TicketID: "ENG-1", ContentHash: "abc", StaleAfter: time.Now().Add(time.Hour)}
+
+	// No Claude client: a cache miss would panic, so this proves the hit path.
+	s := &Scorer{Cache: c}
+	c.Put(ScoreCacheEntry{
+		TicketID:   "ENG-1",
+		Key:        scoreCacheKey(ticket, s.model),
+		Response:   ScorerResponse{RubricScores: RubricScores{Clarity: 5}},
+		ScoredAt:   time.Now(),
+		StaleAfter: ticket.StaleAfter,
+		CostUSD:    0.03,
+	})
+
+	results := s.ScoreBatch(context.Background(), []NormalizedTicket{ticket}, 1)
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	r := results[0]
+	if !r.Cached || r.Err != nil {
+		t.Fatalf("expected cached result, got %+v", r)
+	}
+	if r.Response.Clarity != 5 {
+		t.Errorf("expected cached clarity 5, got %d", r.Response.Clarity)
+	}
+	if r.SavedUSD != 0.03 {
+		t.Errorf("expected $0.03 saved, got %f", r.SavedUSD)
+	}
+}
+
+func TestScoreCacheKey_PromptAndModel(t *testing.T) {
+	ticket := NormalizedTicket{ContentHash: "abc"}
+	if scoreCacheKey(ticket, "sonnet") == scoreCacheKey(ticket, "opus") {
+		t.Error("expected model to be part of the cache key")
+	}
+	other := NormalizedTicket{ContentHash: "def"}
+	if scoreCacheKey(ticket, "sonnet") == scoreCacheKey(other, "sonnet") {
+		t.Error("expected content hash to be part of the cache key")
+	}
+}
@@ -8,12 +8,18 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/philjestin/boatmanmode/internal/claude"
 	"github.com/philjestin/boatmanmode/internal/config"
 	"github.com/philjestin/boatmanmode/internal/cost"
 )
 
+// scorerPromptVersion identifies the scoring prompt for the score cache.
+// Bump it whenever scorerSystemPrompt or buildUserPrompt changes so that
+// cached scores from the old prompt are not reused.
+const scorerPromptVersion = "1"
+
 const scorerSystemPrompt = `You are a ticket triage evaluator for a software engineering team. You score development tickets on seven rubric dimensions to determine if they are suitable for autonomous AI execution.
 
 Score each dimension from 0 (worst) to 5 (best):
@@ -46,6 +52,13 @@ type Scorer struct {
 	// OnTicketScored is called after each ticket is scored in ScoreBatch.
 	// The int parameters are the zero-based index and total count.
 	OnTicketScored func(result ScoredTicket, index, total int)
+
+	// Cache, if set, is consulted before calling Claude and updated with
+	// fresh scores in ScoreBatch.
+	Cache *ScoreCache
+
+	// Rescore skips cache lookups but still refreshes the cache.
+	Rescore bool
 }
 
 // NewScorer creates a new Scorer that uses Claude for rubric evaluation.
@@ -92,6 +105,11 @@ type ScoredTicket struct {
 	Response *ScorerResponse
 	Usage    *cost.Usage
 	Err      error
+
+	// Cached is true when Response came from the score cache. SavedUSD is
+	// the cost of the original Claude call that the cache hit avoided.
+	Cached   bool
+	SavedUSD float64
 }
 
 // ScoreBatch scores multiple tickets concurrently with a semaphore to limit parallelism.
@@ -107,13 +125,7 @@ func (s *Scorer) ScoreBatch(ctx context.Context, tickets []NormalizedTicket, con
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			resp, usage, err := s.Score(ctx, t)
-			results[idx] = ScoredTicket{
-				Ticket:   t,
-				Response: resp,
-				Usage:    usage,
-				Err:      err,
-			}
+			results[idx] = s.scoreCached(ctx, t)
 			if s.OnTicketScored != nil {
 				s.OnTicketScored(results[idx], idx, len(tickets))
 			}
@@ -124,6 +136,46 @@ func (s *Scorer) ScoreBatch(ctx context.Context, tickets []NormalizedTicket, con
 	return results
 }
 
+// scoreCached returns a cached score for the ticket when one is valid,
+// otherwise scores it with Claude and records the result in the cache.
+func (s *Scorer) scoreCached(ctx context.Context, t NormalizedTicket) ScoredTicket {
+	key := scoreCacheKey(t, s.model)
+	if s.Cache != nil && !s.Rescore {
+		if e, ok := s.Cache.Lookup(t.TicketID, key, time.Now()); ok {
+			resp := e.Response
+			return ScoredTicket{
+				Ticket:   t,
+				Response: &resp,
+				Cached:   true,
+				SavedUSD: e.CostUSD,
+			}
+		}
+	}
+
+	resp, usage, err := s.Score(ctx, t)
+	if err == nil && s.Cache != nil {
+		entry := ScoreCacheEntry{
+			TicketID:   t.TicketID,
+			Key:        key,
+			Response:   *resp,
+			ScoredAt:   time.Now().UTC(),
+			StaleAfter: t.StaleAfter,
+		}
+		if usage != nil {
+			entry.TokensUsed = usage.InputTokens + usage.OutputTokens
+			entry.CostUSD = usage.TotalCostUSD
+		}
+		s.Cache.Put(entry)
+	}
+
+	return ScoredTicket{
+		Ticket:   t,
+		Response: resp,
+		Usage:    usage,
+		Err:      err,
+	}
+}
+
 // buildUserPrompt constructs the user prompt from a NormalizedTicket.
 func buildUserPrompt(ticket NormalizedTicket) string {
 	description := ticket.Description
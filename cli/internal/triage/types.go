@@ -22,10 +22,13 @@ const (
 type Stage string
 
 const (
-	StageIngest  Stage = "ingest"
-	StageScore   Stage = "score"
-	StageCluster Stage = "cluster"
-	StagePlan    Stage = "plan"
+	StageIngest   Stage = "ingest"
+	StageScore    Stage = "score"
+	StageCluster  Stage = "cluster"
+	StagePlan     Stage = "plan"
+	StageOutcome  Stage = "outcome"
+	StageSchedule Stage = "schedule"
+	StageOverrun  Stage = "overrun"
 )
 
 // NormalizedTicket is the Stage 1 output — a Linear ticket with extracted signals
@@ -37,6 +40,7 @@ type NormalizedTicket struct {
 	Description string    `json:"description"`
 	IngestedAt  time.Time `json:"ingestedAt"`
 	StaleAfter  time.Time `json:"staleAfter"`
+	ContentHash string    `json:"contentHash,omitempty"`
 	Signals     Signals   `json:"signals"`
 }
 
@@ -104,7 +108,8 @@ type Cluster struct {
 	RepoAreas []string `json:"repoAreas"`
 }
 
-// CostCeiling defines token and time budgets for execution (used in future phases).
+// CostCeiling defines per-ticket token and time budgets that 'boatman work'
+// enforces during execution.
 type CostCeiling struct {
 	MaxTokensPerTicket       int `json:"maxTokensPerTicket"`
 	MaxAgentMinutesPerTicket int `json:"maxAgentMinutesPerTicket"`
@@ -147,6 +152,8 @@ type TriageResult struct {
 	// Plans holds Stage 4 plan results (json.RawMessage to avoid circular import with plan package).
 	Plans     json.RawMessage `json:"plans,omitempty"`
 	PlanStats json.RawMessage `json:"planStats,omitempty"`
+	// Schedule is the dependency-aware execution order for executable tickets.
+	Schedule *Schedule `json:"schedule,omitempty"`
 }
 
 // TriageStats summarizes a triage run.
@@ -159,4 +166,6 @@ type TriageStats struct {
 	ClusterCount      int     `json:"clusterCount"`
 	TotalTokensUsed   int     `json:"totalTokensUsed"`
 	TotalCostUSD      float64 `json:"totalCostUsd"`
+	CacheHits         int     `json:"cacheHits"`
+	CacheSavedUSD     float64 `json:"cacheSavedUsd"`
 }
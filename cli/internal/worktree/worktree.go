@@ -7,8 +7,13 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
+// createMu serializes worktree creation: concurrent git fetch and
+// worktree add calls against the same repository contend for its locks.
+var createMu sync.Mutex
+
 // Manager handles git worktree operations.
 type Manager struct {
 	repoPath     string
@@ -45,6 +50,9 @@ func New(repoPath string) (*Manager, error) {
 // Create creates a new worktree for the given branch name.
 // If the worktree/branch already exists, it reuses it.
 func (m *Manager) Create(branchName, baseBranch string) (*Worktree, error) {
+	createMu.Lock()
+	defer createMu.Unlock()
+
 	// Sanitize branch name for filesystem
 	safeBranchName := sanitizeBranchName(branchName)
 	worktreePath := filepath.Join(m.worktreeBase, safeBranchName)
@@ -91,11 +99,64 @@ func (m *Manager) Create(branchName, baseBranch string) (*Worktree, error) {
 	}, nil
 }
 
-// Remove removes a worktree and its branch.
+// CreateAt creates a worktree with a new branch starting at startPoint (a
+// branch, tag, or commit). Unlike Create it doesn't fetch, and it fails if
+// the branch already exists rather than reusing it.
+func (m *Manager) CreateAt(branchName, startPoint string) (*Worktree, error) {
+	createMu.Lock()
+	defer createMu.Unlock()
+
+	worktreePath := filepath.Join(m.worktreeBase, sanitizeBranchName(branchName))
+	if _, err := os.Stat(worktreePath); err == nil {
+		return nil, fmt.Errorf("worktree already exists: %s", worktreePath)
+	}
+	if err := os.MkdirAll(m.worktreeBase, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create worktree base: %w", err)
+	}
+	if err := m.runGit("worktree", "add", "-b", branchName, worktreePath, startPoint); err != nil {
+		return nil, fmt.Errorf("failed to create worktree at %s: %w", startPoint, err)
+	}
+
+	return &Worktree{
+		Path:       worktreePath,
+		BranchName: branchName,
+		BaseBranch: startPoint,
+	}, nil
+}
+
+// CreateDetached creates a worktree with startPoint checked out and no
+// branch, for looking at a commit without touching the repository's
+// checkout. Each call gets a new directory; Remove it when done.
+func (m *Manager) CreateDetached(startPoint string) (*Worktree, error) {
+	createMu.Lock()
+	defer createMu.Unlock()
+
+	if err := os.MkdirAll(m.worktreeBase, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create worktree base: %w", err)
+	}
+	worktreePath, err := os.MkdirTemp(m.worktreeBase, "detached-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+	if err := m.runGit("worktree", "add", "--detach", worktreePath, startPoint); err != nil {
+		os.Remove(worktreePath)
+		return nil, fmt.Errorf("failed to create worktree at %s: %w", startPoint, err)
+	}
+
+	return &Worktree{
+		Path:       worktreePath,
+		BaseBranch: startPoint,
+	}, nil
+}
+
+// Remove removes a worktree and its branch, if it has one.
 func (m *Manager) Remove(wt *Worktree) error {
 	if err := m.runGit("worktree", "remove", wt.Path, "--force"); err != nil {
 		return fmt.Errorf("failed to remove worktree: %w", err)
 	}
+	if wt.BranchName == "" {
+		return nil
+	}
 
 	// Optionally delete the branch
 	if err := m.runGit("branch", "-D", wt.BranchName); err != nil {
@@ -0,0 +1,304 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Pending action statuses
+const (
+	ActionStatusPending  = "pending"
+	ActionStatusApproved = "approved"
+	ActionStatusRejected = "rejected"
+)
+
+// PendingAction is a tool call held until the user approves or rejects it
+type PendingAction struct {
+	ID          string          `json:"id"`
+	ToolName    string          `json:"toolName"`
+	ToolUseID   string          `json:"toolUseId,omitempty"`
+	Input       json.RawMessage `json:"input"`
+	Summary     string          `json:"summary"`
+	Rule        string          `json:"rule"` // Rule remembered by "always allow"
+	Status      string          `json:"status"`
+	RequestedAt time.Time       `json:"requestedAt"`
+}
+
+// ApprovalPolicy provides per-project approval settings
+type ApprovalPolicy interface {
+	GetProjectApprovalMode(projectPath string) string
+	GetProjectAllowedTools(projectPath string) []string
+	AddProjectAllowedTool(projectPath, rule string) error
+}
+
+type approvalDecision struct {
+	allow   bool
+	message string
+}
+
+type pendingApproval struct {
+	action   PendingAction
+	decision chan approvalDecision
+}
+
+// SetApprovalHandler sets the callback for pending action changes
+func (s *Session) SetApprovalHandler(handler func(PendingAction)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onApproval = handler
+}
+
+// SetApprovalPolicy sets where the session reads and stores allow rules
+func (s *Session) SetApprovalPolicy(policy ApprovalPolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.approvalPolicy = policy
+}
+
+// SetApprovalServer registers the session with the approval server so the
+// Claude CLI can route permission prompts to it.
+func (s *Session) SetApprovalServer(server *ApprovalServer) {
+	s.mu.Lock()
+	s.approvalServer = server
+	s.mu.Unlock()
+	if server != nil {
+		server.Register(s)
+	}
+}
+
+// RequestApproval holds a tool call until the user decides on it. Calls that
+// match a project allow rule are approved immediately. It returns whether the
+// call may run and, if not, a message for Claude.
+func (s *Session) RequestApproval(ctx context.Context, toolName, toolUseID string, input json.RawMessage) (bool, string) {
+	s.mu.RLock()
+	policy := s.approvalPolicy
+	projectPath := s.ProjectPath
+	s.mu.RUnlock()
+
+	if policy != nil {
+		for _, rule := range policy.GetProjectAllowedTools(projectPath) {
+			if matchesAllowRule(rule, toolName, input) {
+				return true, ""
+			}
+		}
+	}
+
+	p := &pendingApproval{
+		action: PendingAction{
+			ID:          fmt.Sprintf("action-%d", time.Now().UnixNano()),
+			ToolName:    toolName,
+			ToolUseID:   toolUseID,
+			Input:       input,
+			Summary:     summarizeToolInput(toolName, input),
+			Rule:        allowRuleFor(toolName, input),
+			Status:      ActionStatusPending,
+			RequestedAt: time.Now(),
+		},
+		decision: make(chan approvalDecision, 1),
+	}
+
+	s.mu.Lock()
+	s.pendingActions = append(s.pendingActions, p)
+	if s.Status == SessionStatusRunning {
+		s.setStatus(SessionStatusWaiting)
+	}
+	sessionCtx := s.ctx
+	handler := s.onApproval
+	action := p.action
+	s.mu.Unlock()
+
+	if handler != nil {
+		handler(action)
+	}
+
+	var sessionDone <-chan struct{}
+	if sessionCtx != nil {
+		sessionDone = sessionCtx.Done()
+	}
+
+	select {
+	case d := <-p.decision:
+		return d.allow, d.message
+	case <-ctx.Done():
+		s.resolveAction(action.ID, approvalDecision{message: "Approval request was cancelled"})
+		return false, "Approval request was cancelled"
+	case <-sessionDone:
+		s.resolveAction(action.ID, approvalDecision{message: "Session was stopped"})
+		return false, "Session was stopped"
+	}
+}
+
+// Approve approves a pending action. An empty actionID approves the oldest one.
+func (s *Session) Approve(actionID string) error {
+	_, err := s.resolveAction(actionID, approvalDecision{allow: true})
+	return err
+}
+
+// ApproveAlways approves a pending action and remembers its rule for the
+// project so matching tool calls no longer need approval.
+func (s *Session) ApproveAlways(actionID string) error {
+	s.mu.RLock()
+	policy := s.approvalPolicy
+	projectPath := s.ProjectPath
+	s.mu.RUnlock()
+
+	if policy == nil {
+		return fmt.Errorf("always allow is not available for this session")
+	}
+
+	action, err := s.resolveAction(actionID, approvalDecision{allow: true})
+	if err != nil {
+		return err
+	}
+	return policy.AddProjectAllowedTool(projectPath, action.Rule)
+}
+
+// Reject rejects a pending action. An empty actionID rejects the oldest one.
+func (s *Session) Reject(actionID string) error {
+	_, err := s.resolveAction(actionID, approvalDecision{message: "The user rejected this tool call"})
+	return err
+}
+
+// GetPendingActions returns the actions waiting for approval, oldest first
+func (s *Session) GetPendingActions() []PendingAction {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	actions := make([]PendingAction, len(s.pendingActions))
+	for i, p := range s.pendingActions {
+		actions[i] = p.action
+	}
+	return actions
+}
+
+// resolveAction delivers a decision to a pending action and removes it. The
+// session returns to running once nothing is left waiting.
+func (s *Session) resolveAction(actionID string, d approvalDecision) (PendingAction, error) {
+	s.mu.Lock()
+	idx := -1
+	for i, p := range s.pendingActions {
+		if actionID == "" || p.action.ID == actionID {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		s.mu.Unlock()
+		if actionID == "" {
+			return PendingAction{}, fmt.Errorf("no pending actions")
+		}
+		return PendingAction{}, fmt.Errorf("pending action not found: %s", actionID)
+	}
+
+	p := s.pendingActions[idx]
+	s.pendingActions = append(s.pendingActions[:idx], s.pendingActions[idx+1:]...)
+	if d.allow {
+		p.action.Status = ActionStatusApproved
+	} else {
+		p.action.Status = ActionStatusRejected
+	}
+	p.decision <- d
+
+	if len(s.pendingActions) == 0 && s.Status == SessionStatusWaiting {
+		s.setStatus(SessionStatusRunning)
+	}
+	handler := s.onApproval
+	action := p.action
+	s.mu.Unlock()
+
+	if handler != nil {
+		handler(action)
+	}
+	return action, nil
+}
+
+// matchesAllowRule reports whether a tool call is covered by an allow rule.
+// Rules are a tool name ("Write"), or a tool name with a pattern matched
+// against the command or file path: "Bash(go test:*)" matches by prefix and
+// "Bash(make lint)" matches exactly.
+func matchesAllowRule(rule, toolName string, input json.RawMessage) bool {
+	name, pattern, hasPattern := strings.Cut(strings.TrimSpace(rule), "(")
+	if name != toolName {
+		return false
+	}
+	if !hasPattern {
+		return true
+	}
+	pattern = strings.TrimSuffix(pattern, ")")
+
+	subject := ruleSubject(toolName, input)
+	if subject == "" {
+		return false
+	}
+	// A prefix rule for one command must not approve a chained command.
+	if toolName == "Bash" && strings.ContainsAny(subject, ";&|`$<>\n") {
+		return false
+	}
+
+	if prefix, ok := strings.CutSuffix(pattern, ":*"); ok {
+		if toolName == "Bash" {
+			return subject == prefix || strings.HasPrefix(subject, prefix+" ")
+		}
+		return strings.HasPrefix(subject, prefix)
+	}
+	return subject == pattern
+}
+
+// allowRuleFor returns the rule "always allow" remembers for a tool call.
+// Bash commands are scoped to their command and subcommand (e.g.
+// "Bash(go test:*)"); other tools are allowed by name.
+func allowRuleFor(toolName string, input json.RawMessage) string {
+	if toolName != "Bash" {
+		return toolName
+	}
+
+	fields := strings.Fields(ruleSubject(toolName, input))
+	if len(fields) == 0 {
+		return toolName
+	}
+	prefix := fields[0]
+	if len(fields) > 1 && isSubcommand(fields[1]) {
+		prefix += " " + fields[1]
+	}
+	return fmt.Sprintf("Bash(%s:*)", prefix)
+}
+
+// isSubcommand reports whether a command argument looks like a subcommand
+// ("test", "run") rather than a flag or path.
+func isSubcommand(arg string) bool {
+	if arg == "" || strings.HasPrefix(arg, "-") {
+		return false
+	}
+	return !strings.ContainsAny(arg, "./~=:\"'")
+}
+
+// ruleSubject returns the part of a tool's input that allow rules match.
+func ruleSubject(toolName string, input json.RawMessage) string {
+	var fields map[string]any
+	if err := json.Unmarshal(input, &fields); err != nil {
+		return ""
+	}
+
+	keys := []string{"file_path", "notebook_path", "path", "url"}
+	if toolName == "Bash" {
+		keys = []string{"command"}
+	}
+	for _, key := range keys {
+		if v, ok := fields[key].(string); ok && v != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+// summarizeToolInput returns a one-line description of a tool call for the
+// approval prompt.
+func summarizeToolInput(toolName string, input json.RawMessage) string {
+	if subject := ruleSubject(toolName, input); subject != "" {
+		return truncateString(subject, 200)
+	}
+	return truncateString(string(input), 200)
+}
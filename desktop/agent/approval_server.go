@@ -0,0 +1,241 @@
+package agent
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	approvalServerName = "boatman-approval"
+	approvalPromptTool = "approval_prompt"
+
+	// ApprovalToolName is the permission prompt tool passed to the Claude CLI
+	ApprovalToolName = "mcp__" + approvalServerName + "__" + approvalPromptTool
+)
+
+// ApprovalServer is a local MCP server the Claude CLI calls as its permission
+// prompt tool. Each session has its own endpoint, and requests must carry the
+// server's bearer token so other local processes cannot answer prompts.
+type ApprovalServer struct {
+	listener net.Listener
+	server   *http.Server
+	token    string
+
+	mu       sync.RWMutex
+	sessions map[string]*Session
+}
+
+// NewApprovalServer starts an approval server on a random loopback port
+func NewApprovalServer() (*ApprovalServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for approvals: %w", err)
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to generate approval token: %w", err)
+	}
+
+	a := &ApprovalServer{
+		listener: listener,
+		token:    hex.EncodeToString(buf),
+		sessions: make(map[string]*Session),
+	}
+	a.server = &http.Server{Handler: a}
+
+	go a.server.Serve(listener)
+
+	return a, nil
+}
+
+// Register makes a session reachable at its approval endpoint
+func (a *ApprovalServer) Register(s *Session) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sessions[s.ID] = s
+}
+
+// Unregister removes a session's approval endpoint
+func (a *ApprovalServer) Unregister(sessionID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.sessions, sessionID)
+}
+
+// URL returns the MCP endpoint for a session
+func (a *ApprovalServer) URL(sessionID string) string {
+	return fmt.Sprintf("http://%s/mcp/%s", a.listener.Addr().String(), sessionID)
+}
+
+// MCPConfig returns the --mcp-config JSON that connects the Claude CLI to a
+// session's approval endpoint.
+func (a *ApprovalServer) MCPConfig(sessionID string) string {
+	data, _ := json.Marshal(map[string]interface{}{
+		"mcpServers": map[string]interface{}{
+			approvalServerName: map[string]interface{}{
+				"type": "http",
+				"url":  a.URL(sessionID),
+				"headers": map[string]string{
+					"Authorization": "Bearer " + a.token,
+				},
+			},
+		},
+	})
+	return string(data)
+}
+
+// Close stops the server. Prompts still waiting are cancelled.
+func (a *ApprovalServer) Close() error {
+	return a.server.Close()
+}
+
+// ServeHTTP handles MCP JSON-RPC requests for a session
+func (a *ApprovalServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := strings.CutPrefix(r.URL.Path, "/mcp/")
+	if !ok || sessionID == "" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+a.token {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodPost {
+		// No server-initiated stream; clients fall back to plain responses.
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	a.mu.RLock()
+	session := a.sessions[sessionID]
+	a.mu.RUnlock()
+	if session == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var request struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeRPC(w, nil, nil, rpcError(-32700, "parse error"))
+		return
+	}
+
+	// Notifications (no id) get no response body
+	if len(request.ID) == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	switch request.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		json.Unmarshal(request.Params, &params)
+		if params.ProtocolVersion == "" {
+			params.ProtocolVersion = "2025-03-26"
+		}
+		writeRPC(w, request.ID, map[string]interface{}{
+			"protocolVersion": params.ProtocolVersion,
+			"serverInfo": map[string]interface{}{
+				"name":    approvalServerName,
+				"version": "1.0.0",
+			},
+			"capabilities": map[string]interface{}{
+				"tools": map[string]interface{}{},
+			},
+		}, nil)
+
+	case "ping":
+		writeRPC(w, request.ID, map[string]interface{}{}, nil)
+
+	case "tools/list":
+		writeRPC(w, request.ID, map[string]interface{}{
+			"tools": []map[string]interface{}{
+				{
+					"name":        approvalPromptTool,
+					"description": "Ask the Boatman user to approve a tool call",
+					"inputSchema": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"tool_name":   map[string]interface{}{"type": "string"},
+							"input":       map[string]interface{}{"type": "object"},
+							"tool_use_id": map[string]interface{}{"type": "string"},
+						},
+						"required": []string{"tool_name", "input"},
+					},
+				},
+			},
+		}, nil)
+
+	case "tools/call":
+		var params struct {
+			Name      string `json:"name"`
+			Arguments struct {
+				ToolName  string          `json:"tool_name"`
+				Input     json.RawMessage `json:"input"`
+				ToolUseID string          `json:"tool_use_id"`
+			} `json:"arguments"`
+		}
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			writeRPC(w, request.ID, nil, rpcError(-32602, "invalid params"))
+			return
+		}
+		if params.Name != approvalPromptTool {
+			writeRPC(w, request.ID, nil, rpcError(-32602, "unknown tool: "+params.Name))
+			return
+		}
+
+		args := params.Arguments
+		if len(args.Input) == 0 {
+			args.Input = json.RawMessage("{}")
+		}
+		allow, message := session.RequestApproval(r.Context(), args.ToolName, args.ToolUseID, args.Input)
+
+		decision := map[string]interface{}{"behavior": "deny", "message": message}
+		if allow {
+			decision = map[string]interface{}{"behavior": "allow", "updatedInput": args.Input}
+		}
+		text, _ := json.Marshal(decision)
+		writeRPC(w, request.ID, map[string]interface{}{
+			"content": []map[string]interface{}{
+				{"type": "text", "text": string(text)},
+			},
+		}, nil)
+
+	default:
+		writeRPC(w, request.ID, nil, rpcError(-32601, "method not found: "+request.Method))
+	}
+}
+
+func rpcError(code int, message string) map[string]interface{} {
+	return map[string]interface{}{"code": code, "message": message}
+}
+
+func writeRPC(w http.ResponseWriter, id json.RawMessage, result interface{}, rpcErr map[string]interface{}) {
+	response := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+	}
+	if rpcErr != nil {
+		response["error"] = rpcErr
+	} else {
+		response["result"] = result
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,352 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakePolicy is an in-memory ApprovalPolicy
+type fakePolicy struct {
+	mu    sync.Mutex
+	mode  string
+	rules map[string][]string
+}
+
+func (p *fakePolicy) GetProjectApprovalMode(projectPath string) string {
+	return p.mode
+}
+
+func (p *fakePolicy) GetProjectAllowedTools(projectPath string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.rules[projectPath]...)
+}
+
+func (p *fakePolicy) AddProjectAllowedTool(projectPath, rule string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.rules == nil {
+		p.rules = make(map[string][]string)
+	}
+	p.rules[projectPath] = append(p.rules[projectPath], rule)
+	return nil
+}
+
+func bashInput(command string) json.RawMessage {
+	data, _ := json.Marshal(map[string]string{"command": command})
+	return data
+}
+
+// waitForPending waits until the session has n pending actions
+func waitForPending(t *testing.T, s *Session, n int) []PendingAction {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if actions := s.GetPendingActions(); len(actions) == n {
+			return actions
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d pending actions, have %d", n, len(s.GetPendingActions()))
+	return nil
+}
+
+func TestMatchesAllowRule(t *testing.T) {
+	writeInput := json.RawMessage(`{"file_path":"/repo/src/main.go","content":"x"}`)
+
+	tests := []struct {
+		name  string
+		rule  string
+		tool  string
+		input json.RawMessage
+		want  bool
+	}{
+		{"tool name", "Write", "Write", writeInput, true},
+		{"different tool", "Write", "Edit", writeInput, false},
+		{"bash prefix", "Bash(go test:*)", "Bash", bashInput("go test ./..."), true},
+		{"bash prefix exact command", "Bash(go test:*)", "Bash", bashInput("go test"), true},
+		{"bash prefix word boundary", "Bash(go test:*)", "Bash", bashInput("go testify"), false},
+		{"bash exact", "Bash(make lint)", "Bash", bashInput("make lint"), true},
+		{"bash exact mismatch", "Bash(make lint)", "Bash", bashInput("make lint-fix"), false},
+		{"bash chained command", "Bash(go test:*)", "Bash", bashInput("go test ./... && rm -rf /"), false},
+		{"bash piped command", "Bash(ls:*)", "Bash", bashInput("ls | sh"), false},
+		{"path prefix", "Write(/repo/src/:*)", "Write", writeInput, true},
+		{"path prefix mismatch", "Write(/repo/docs/:*)", "Write", writeInput, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesAllowRule(tt.rule, tt.tool, tt.input); got != tt.want {
+				t.Errorf("matchesAllowRule(%q) = %v, want %v", tt.rule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowRuleFor(t *testing.T) {
+	tests := []struct {
+		tool  string
+		input json.RawMessage
+		want  string
+	}{
+		{"Bash", bashInput("go test ./..."), "Bash(go test:*)"},
+		{"Bash", bashInput("npm run build"), "Bash(npm run:*)"},
+		{"Bash", bashInput("ls -la"), "Bash(ls:*)"},
+		{"Bash", bashInput("cat ./README.md"), "Bash(cat:*)"},
+		{"Bash", json.RawMessage(`{}`), "Bash"},
+		{"Write", json.RawMessage(`{"file_path":"/repo/a.go"}`), "Write"},
+	}
+
+	for _, tt := range tests {
+		if got := allowRuleFor(tt.tool, tt.input); got != tt.want {
+			t.Errorf("allowRuleFor(%s, %s) = %q, want %q", tt.tool, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestRequestApproval_ApproveAndReject(t *testing.T) {
+	session := NewSession("approval-session", "/repo")
+	session.Start("sonnet")
+	session.setStatus(SessionStatusRunning)
+
+	var events []PendingAction
+	var eventsMu sync.Mutex
+	session.SetApprovalHandler(func(action PendingAction) {
+		eventsMu.Lock()
+		events = append(events, action)
+		eventsMu.Unlock()
+	})
+
+	type result struct {
+		allow   bool
+		message string
+	}
+	results := make(chan result, 1)
+	go func() {
+		allow, message := session.RequestApproval(context.Background(), "Bash", "toolu_1", bashInput("go test ./..."))
+		results <- result{allow, message}
+	}()
+
+	pending := waitForPending(t, session, 1)
+	if pending[0].Summary != "go test ./..." {
+		t.Errorf("Summary = %q, want command", pending[0].Summary)
+	}
+	if pending[0].Rule != "Bash(go test:*)" {
+		t.Errorf("Rule = %q, want Bash(go test:*)", pending[0].Rule)
+	}
+	if session.Status != SessionStatusWaiting {
+		t.Errorf("Status = %s, want %s", session.Status, SessionStatusWaiting)
+	}
+
+	if err := session.Approve(pending[0].ID); err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	if r := <-results; !r.allow {
+		t.Errorf("Expected approved tool call, got denied: %s", r.message)
+	}
+	if session.Status != SessionStatusRunning {
+		t.Errorf("Status = %s, want %s", session.Status, SessionStatusRunning)
+	}
+
+	go func() {
+		allow, message := session.RequestApproval(context.Background(), "Write", "toolu_2", json.RawMessage(`{"file_path":"/repo/a.go"}`))
+		results <- result{allow, message}
+	}()
+	waitForPending(t, session, 1)
+
+	// An empty action ID resolves the oldest pending action
+	if err := session.Reject(""); err != nil {
+		t.Fatalf("Reject() error = %v", err)
+	}
+	if r := <-results; r.allow || r.message == "" {
+		t.Errorf("Expected denial with message, got %+v", r)
+	}
+
+	if err := session.Approve("action-unknown"); err == nil {
+		t.Error("Expected error approving unknown action")
+	}
+
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	if len(events) != 4 {
+		t.Fatalf("Expected 4 approval events, got %d", len(events))
+	}
+	if events[1].Status != ActionStatusApproved || events[3].Status != ActionStatusRejected {
+		t.Errorf("Unexpected event statuses: %s, %s", events[1].Status, events[3].Status)
+	}
+}
+
+func TestRequestApproval_AlwaysAllow(t *testing.T) {
+	session := NewSession("always-session", "/repo")
+	session.Start("sonnet")
+
+	policy := &fakePolicy{}
+	session.SetApprovalPolicy(policy)
+
+	done := make(chan bool, 1)
+	go func() {
+		allow, _ := session.RequestApproval(context.Background(), "Bash", "", bashInput("go test ./agent/..."))
+		done <- allow
+	}()
+
+	pending := waitForPending(t, session, 1)
+	if err := session.ApproveAlways(pending[0].ID); err != nil {
+		t.Fatalf("ApproveAlways() error = %v", err)
+	}
+	if !<-done {
+		t.Error("Expected approved tool call")
+	}
+
+	if rules := policy.GetProjectAllowedTools("/repo"); len(rules) != 1 || rules[0] != "Bash(go test:*)" {
+		t.Fatalf("Expected remembered rule, got %v", rules)
+	}
+
+	// Matching calls are now allowed without a prompt
+	allow, _ := session.RequestApproval(context.Background(), "Bash", "", bashInput("go test ./config/..."))
+	if !allow {
+		t.Error("Expected call matching the rule to be allowed")
+	}
+	if n := len(session.GetPendingActions()); n != 0 {
+		t.Errorf("Expected no pending actions, got %d", n)
+	}
+}
+
+func TestRequestApproval_SessionStopped(t *testing.T) {
+	session := NewSession("stopped-session", "/repo")
+	session.Start("sonnet")
+
+	done := make(chan bool, 1)
+	go func() {
+		allow, _ := session.RequestApproval(context.Background(), "Bash", "", bashInput("rm -rf build"))
+		done <- allow
+	}()
+
+	waitForPending(t, session, 1)
+	session.Stop()
+
+	select {
+	case allow := <-done:
+		if allow {
+			t.Error("Expected stopped session to deny the tool call")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("RequestApproval did not return after Stop")
+	}
+	if n := len(session.GetPendingActions()); n != 0 {
+		t.Errorf("Expected no pending actions, got %d", n)
+	}
+}
+
+func TestApprovalServer(t *testing.T) {
+	server, err := NewApprovalServer()
+	if err != nil {
+		t.Fatalf("NewApprovalServer() error = %v", err)
+	}
+	defer server.Close()
+
+	session := NewSession("server-session", "/repo")
+	session.Start("sonnet")
+	session.SetApprovalServer(server)
+
+	var cfg struct {
+		MCPServers map[string]struct {
+			Type    string            `json:"type"`
+			URL     string            `json:"url"`
+			Headers map[string]string `json:"headers"`
+		} `json:"mcpServers"`
+	}
+	if err := json.Unmarshal([]byte(server.MCPConfig(session.ID)), &cfg); err != nil {
+		t.Fatalf("MCPConfig is not valid JSON: %v", err)
+	}
+	entry, ok := cfg.MCPServers["boatman-approval"]
+	if !ok || entry.Type != "http" || entry.URL != server.URL(session.ID) {
+		t.Fatalf("Unexpected MCP config: %+v", cfg)
+	}
+
+	call := func(headers map[string]string, body string) (*http.Response, map[string]interface{}) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, entry.URL, bytes.NewBufferString(body))
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		var decoded map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&decoded)
+		return resp, decoded
+	}
+
+	t.Run("rejects missing token", func(t *testing.T) {
+		resp, _ := call(nil, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected 401, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("lists the approval tool", func(t *testing.T) {
+		_, body := call(entry.Headers, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+		tools := body["result"].(map[string]interface{})["tools"].([]interface{})
+		if len(tools) != 1 || tools[0].(map[string]interface{})["name"] != "approval_prompt" {
+			t.Errorf("Unexpected tools: %v", tools)
+		}
+	})
+
+	t.Run("notifications are accepted", func(t *testing.T) {
+		resp, _ := call(entry.Headers, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+		if resp.StatusCode != http.StatusAccepted {
+			t.Errorf("Expected 202, got %d", resp.StatusCode)
+		}
+	})
+
+	for _, tt := range []struct {
+		name     string
+		resolve  func(id string) error
+		behavior string
+	}{
+		{"approved call", session.Approve, "allow"},
+		{"rejected call", session.Reject, "deny"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			go func() {
+				actions := waitForPending(t, session, 1)
+				tt.resolve(actions[0].ID)
+			}()
+
+			_, body := call(entry.Headers, `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"approval_prompt","arguments":{"tool_name":"Bash","input":{"command":"make build"},"tool_use_id":"toolu_9"}}}`)
+			content := body["result"].(map[string]interface{})["content"].([]interface{})
+			text := content[0].(map[string]interface{})["text"].(string)
+
+			var decision map[string]interface{}
+			if err := json.Unmarshal([]byte(text), &decision); err != nil {
+				t.Fatalf("decision is not JSON: %v", err)
+			}
+			if decision["behavior"] != tt.behavior {
+				t.Errorf("behavior = %v, want %s", decision["behavior"], tt.behavior)
+			}
+			if tt.behavior == "allow" && fmt.Sprint(decision["updatedInput"]) != "map[command:make build]" {
+				t.Errorf("Expected original input to be passed through, got %v", decision["updatedInput"])
+			}
+		})
+	}
+
+	t.Run("unknown session", func(t *testing.T) {
+		server.Unregister(session.ID)
+		resp, _ := call(entry.Headers, `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected 404, got %d", resp.StatusCode)
+		}
+		if !strings.HasSuffix(entry.URL, "/mcp/"+session.ID) {
+			t.Errorf("Unexpected endpoint URL %s", entry.URL)
+		}
+	})
+}
@@ -0,0 +1,433 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/philjestin/boatman-ecosystem/harness/handoff"
+)
+
+const (
+	// DefaultContextWindow is the context window assumed until the CLI reports
+	// the model's actual window
+	DefaultContextWindow = 200_000
+
+	// DefaultCompactThreshold is the fraction of the context window at which
+	// a session compacts automatically
+	DefaultCompactThreshold = 0.8
+
+	// compactSummaryTokens is the budget for a summary built without the model
+	compactSummaryTokens = 4000
+
+	// compactRecentMessages is how many of the latest messages a summary built
+	// without the model keeps close to verbatim
+	compactRecentMessages = 6
+
+	// compactTimeout bounds how long the model may take to summarize
+	compactTimeout = 3 * time.Minute
+)
+
+// compactPrompt asks the model to summarize the conversation it is resuming
+const compactPrompt = `Summarize this conversation so it can continue in a fresh context window.
+Include: the user's goals and requirements, decisions made and why, files created or changed,
+the current state of the work, and any open questions or next steps.
+Be specific (paths, names, commands) and concise. Reply with the summary only.`
+
+// ContextUsage reports how much of the model's context window a session's
+// conversation uses
+type ContextUsage struct {
+	SessionID   string  `json:"sessionId"`
+	Tokens      int     `json:"tokens"`
+	Window      int     `json:"window"`
+	Percent     float64 `json:"percent"`
+	Threshold   float64 `json:"threshold"` // 0 when automatic compaction is off
+	Compactions int     `json:"compactions"`
+	Compacting  bool    `json:"compacting"`
+}
+
+// CompactionInfo describes a compaction, attached to the system message that
+// marks it in the history
+type CompactionInfo struct {
+	Summary      string `json:"summary"`
+	TokensBefore int    `json:"tokensBefore"`
+	Method       string `json:"method"` // "model" or "compressed"
+	Automatic    bool   `json:"automatic"`
+}
+
+// SetCompactThreshold sets the fraction of the context window at which the
+// session compacts automatically; 0 turns automatic compaction off
+func (s *Session) SetCompactThreshold(threshold float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.compactThreshold = threshold
+}
+
+// SetContextHandler sets the callback for context usage changes
+func (s *Session) SetContextHandler(handler func(ContextUsage)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onContext = handler
+}
+
+// GetContextUsage returns the session's current context usage
+func (s *Session) GetContextUsage() ContextUsage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.contextUsage()
+}
+
+// contextUsage builds the current usage report.
+// Note: This method expects the caller to hold s.mu lock
+func (s *Session) contextUsage() ContextUsage {
+	window := s.contextWindow
+	if window <= 0 {
+		window = DefaultContextWindow
+	}
+	return ContextUsage{
+		SessionID:   s.ID,
+		Tokens:      s.contextTokens,
+		Window:      window,
+		Percent:     float64(s.contextTokens) / float64(window) * 100,
+		Threshold:   s.compactThreshold,
+		Compactions: s.compactions,
+		Compacting:  s.compacting,
+	}
+}
+
+// updateContextTokens records the context size of the latest request from its
+// usage: everything sent (including cached prompt) plus what was generated.
+// The totals of a "result" event add up every request in the turn, so they
+// must not be passed here.
+func (s *Session) updateContextTokens(usage map[string]any) {
+	tokens := 0
+	for _, key := range []string{"input_tokens", "cache_creation_input_tokens", "cache_read_input_tokens", "output_tokens"} {
+		if val, ok := usage[key].(float64); ok {
+			tokens += int(val)
+		}
+	}
+	if tokens == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	s.contextTokens = tokens
+	handler := s.onContext
+	report := s.contextUsage()
+	s.mu.Unlock()
+
+	if handler != nil {
+		handler(report)
+	}
+}
+
+// updateContextWindow records the model's context window from a result
+// event's per-model usage, when the CLI reports it
+func (s *Session) updateContextWindow(event map[string]any) {
+	modelUsage, ok := event["modelUsage"].(map[string]any)
+	if !ok {
+		return
+	}
+	window := 0
+	for _, raw := range modelUsage {
+		if usage, ok := raw.(map[string]any); ok {
+			if val, ok := usage["contextWindow"].(float64); ok && int(val) > window {
+				window = int(val)
+			}
+		}
+	}
+	if window > 0 {
+		s.mu.Lock()
+		s.contextWindow = window
+		s.mu.Unlock()
+	}
+}
+
+// needsCompaction reports whether the conversation has crossed the automatic
+// compaction threshold
+func (s *Session) needsCompaction() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.compactThreshold <= 0 || s.compacting || s.conversationID == "" {
+		return false
+	}
+	usage := s.contextUsage()
+	return usage.Percent >= s.compactThreshold*100
+}
+
+// Compact summarizes the conversation so far and starts a fresh CLI
+// conversation seeded with the summary on the next message. The message
+// history is kept; a system message marks where the compaction happened.
+func (s *Session) Compact(authConfig AuthConfig) error {
+	s.mu.Lock()
+	if s.Status == SessionStatusRunning || s.Status == SessionStatusWaiting {
+		s.mu.Unlock()
+		return fmt.Errorf("cannot compact while the agent is working")
+	}
+	if s.Status == SessionStatusStopped {
+		s.mu.Unlock()
+		return fmt.Errorf("session not available")
+	}
+	statusHandler := s.onStatus
+	s.Status = SessionStatusRunning
+	s.UpdatedAt = time.Now()
+	s.mu.Unlock()
+
+	if statusHandler != nil {
+		statusHandler(SessionStatusRunning)
+	}
+
+	go func() {
+		s.compact(authConfig, false)
+
+		s.mu.Lock()
+		if s.Status == SessionStatusRunning {
+			s.setStatus(SessionStatusIdle)
+		}
+		s.mu.Unlock()
+
+		if err := SaveSession(s); err != nil {
+			fmt.Printf("Warning: failed to save session %s after compaction: %v\n", s.ID, err)
+		}
+	}()
+	return nil
+}
+
+// compact does the work of Compact. The caller keeps the session busy while
+// it runs.
+func (s *Session) compact(authConfig AuthConfig, automatic bool) {
+	s.mu.Lock()
+	if s.compacting {
+		s.mu.Unlock()
+		return
+	}
+	s.compacting = true
+	conversationID := s.conversationID
+	tokensBefore := s.contextTokens
+	messages := make([]Message, len(s.Messages))
+	copy(messages, s.Messages)
+	summarize := s.summarizer
+	contextHandler := s.onContext
+	report := s.contextUsage()
+	s.mu.Unlock()
+
+	if contextHandler != nil {
+		contextHandler(report)
+	}
+
+	if conversationID == "" && len(messages) == 0 {
+		s.mu.Lock()
+		s.compacting = false
+		s.mu.Unlock()
+		s.addSystemMessage("Nothing to compact yet.")
+		return
+	}
+
+	if summarize == nil {
+		summarize = s.summarizeWithModel
+	}
+
+	method := "model"
+	summary := ""
+	if conversationID != "" {
+		var err error
+		summary, err = summarize(conversationID, authConfig)
+		if err != nil {
+			fmt.Printf("Warning: failed to summarize session %s with the model: %v\n", s.ID, err)
+		}
+	}
+	if strings.TrimSpace(summary) == "" {
+		method = "compressed"
+		summary = compressMessages(messages, compactSummaryTokens)
+	}
+	summary = strings.TrimSpace(summary)
+
+	content := fmt.Sprintf("🗜️ Conversation compacted (%d tokens). The next message starts a fresh conversation seeded with this summary; the history above is kept.", tokensBefore)
+	if automatic {
+		content = fmt.Sprintf("🗜️ Conversation compacted automatically at %.0f%% of the context window. The next message starts a fresh conversation seeded with this summary; the history above is kept.", report.Percent)
+	}
+	msg := Message{
+		ID:        fmt.Sprintf("msg-compact-%d", time.Now().UnixNano()),
+		Role:      "system",
+		Content:   content,
+		Timestamp: time.Now(),
+		Metadata: &MessageMetadata{
+			Compaction: &CompactionInfo{
+				Summary:      summary,
+				TokensBefore: tokensBefore,
+				Method:       method,
+				Automatic:    automatic,
+			},
+		},
+	}
+
+	s.mu.Lock()
+	s.compacting = false
+	s.compactSummary = summary
+	s.conversationID = ""
+	s.compactions++
+	s.contextTokens = handoff.EstimateTokens(summary)
+	s.Messages = append(s.Messages, msg)
+	s.UpdatedAt = time.Now()
+	_ = s.TrimMessagesIfNeeded(s.maxMessages, s.archive)
+	messageHandler := s.onMessage
+	report = s.contextUsage()
+	s.mu.Unlock()
+
+	if messageHandler != nil {
+		messageHandler(msg)
+	}
+	if contextHandler != nil {
+		contextHandler(report)
+	}
+}
+
+// summarizeWithModel asks the model to summarize the conversation by resuming
+// it with the compaction prompt
+func (s *Session) summarizeWithModel(conversationID string, authConfig AuthConfig) (string, error) {
+	s.mu.RLock()
+	parent := s.ctx
+	model := s.Model
+	projectPath := s.ProjectPath
+	s.mu.RUnlock()
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	ctx, cancel := context.WithTimeout(parent, compactTimeout)
+	defer cancel()
+
+	args := []string{"-p", compactPrompt, "-r", conversationID, "--output-format", "text"}
+	if model != "" {
+		args = append(args, "--model", model)
+	}
+
+	cmd := exec.CommandContext(ctx, "claude", args...)
+	cmd.Dir = projectPath
+	applyAuthEnv(cmd, authConfig)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return string(output), nil
+}
+
+// compactSeed is prepended to the first prompt of the conversation that
+// follows a compaction
+func compactSeed(summary string) string {
+	return "This conversation continues an earlier one. " +
+		"Summary of the earlier conversation:\n\n" + summary + "\n\n---\n\n"
+}
+
+// compressMessages builds a summary of messages without the model. The user's
+// requests, the agent's replies, the files it touched and the latest messages
+// are compressed to fit the token budget, most important first.
+func compressMessages(messages []Message, targetTokens int) string {
+	var requests, replies, recent []string
+	files := map[string]bool{}
+
+	start := len(messages) - compactRecentMessages
+	if start < 0 {
+		start = 0
+	}
+	for i, msg := range messages {
+		if msg.Metadata != nil && msg.Metadata.ToolUse != nil {
+			for _, path := range toolUsePaths(msg.Metadata.ToolUse) {
+				files[path] = true
+			}
+		}
+		content := strings.TrimSpace(msg.Content)
+		if content == "" {
+			continue
+		}
+		if i >= start && msg.Role != "system" {
+			recent = append(recent, fmt.Sprintf("%s: %s", msg.Role, content))
+			continue
+		}
+		switch msg.Role {
+		case "user":
+			requests = append(requests, "- "+content)
+		case "assistant":
+			replies = append(replies, content)
+		}
+	}
+
+	var blocks []handoff.ContentBlock
+	if len(requests) > 0 {
+		blocks = append(blocks, handoff.ContentBlock{
+			Type:     "requirements",
+			Content:  "## User requests\n" + strings.Join(requests, "\n"),
+			Required: true,
+		})
+	}
+	if len(files) > 0 {
+		paths := make([]string, 0, len(files))
+		for path := range files {
+			paths = append(paths, "- "+path)
+		}
+		sort.Strings(paths)
+		blocks = append(blocks, handoff.ContentBlock{
+			Type:    "files",
+			Content: "## Files touched\n" + strings.Join(paths, "\n"),
+		})
+	}
+	if len(replies) > 0 {
+		blocks = append(blocks, handoff.ContentBlock{
+			Type:    "approach",
+			Content: "## Agent replies\n" + strings.Join(replies, "\n\n"),
+		})
+	}
+	if len(recent) > 0 {
+		blocks = append(blocks, handoff.ContentBlock{
+			Type:     "context",
+			Content:  "## Latest messages\n" + strings.Join(recent, "\n\n"),
+			Required: true,
+		})
+	}
+
+	return handoff.NewDynamicCompressor(targetTokens).Compress(blocks)
+}
+
+// toolUsePaths returns the file paths a tool call names in its input
+func toolUsePaths(toolUse *ToolUse) []string {
+	input := map[string]any{}
+	if err := json.Unmarshal(toolUse.Input, &input); err != nil {
+		return nil
+	}
+	var paths []string
+	for _, key := range []string{"file_path", "notebook_path", "path"} {
+		if path, ok := input[key].(string); ok && path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// applyAuthEnv sets the environment a Claude CLI command needs for the
+// configured authentication method
+func applyAuthEnv(cmd *exec.Cmd, authConfig AuthConfig) {
+	if authConfig.Method == "google-cloud" {
+		if authConfig.GCPProjectID != "" {
+			cmd.Env = append(cmd.Environ(), "CLOUD_ML_PROJECT_ID="+authConfig.GCPProjectID)
+		}
+		if authConfig.GCPRegion != "" {
+			cmd.Env = append(cmd.Environ(), "CLOUD_ML_REGION="+authConfig.GCPRegion)
+		}
+	} else {
+		// Use Anthropic API key authentication
+		if authConfig.APIKey != "" {
+			cmd.Env = append(cmd.Environ(), "ANTHROPIC_API_KEY="+authConfig.APIKey)
+		}
+	}
+}
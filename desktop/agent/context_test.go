@@ -0,0 +1,208 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContextUsage_FromStream(t *testing.T) {
+	session := NewSession("test-session", "/path/to/project")
+
+	var reports []ContextUsage
+	session.SetContextHandler(func(usage ContextUsage) {
+		reports = append(reports, usage)
+	})
+
+	var builder strings.Builder
+	var messageID string
+	session.parseStreamLine(`{"type":"assistant","message":{"content":[{"type":"text","text":"hi"}],"usage":{"input_tokens":10,"cache_read_input_tokens":90000,"cache_creation_input_tokens":5000,"output_tokens":4990}}}`, &builder, &messageID)
+
+	usage := session.GetContextUsage()
+	if usage.Tokens != 100000 {
+		t.Errorf("Tokens = %d, want 100000", usage.Tokens)
+	}
+	if usage.Window != DefaultContextWindow || usage.Percent != 50 {
+		t.Errorf("Window = %d, Percent = %v, want %d and 50", usage.Window, usage.Percent, DefaultContextWindow)
+	}
+	if len(reports) != 1 || reports[0].Tokens != 100000 {
+		t.Errorf("Expected one context report, got %+v", reports)
+	}
+
+	// Result totals add up the whole turn and must not replace the context size,
+	// but the reported context window is used
+	session.parseStreamLine(`{"type":"result","result":"done","usage":{"input_tokens":500000,"output_tokens":9000},"modelUsage":{"claude-sonnet":{"contextWindow":1000000}}}`, &builder, &messageID)
+
+	usage = session.GetContextUsage()
+	if usage.Tokens != 100000 || usage.Window != 1000000 {
+		t.Errorf("After result: Tokens = %d, Window = %d, want 100000 and 1000000", usage.Tokens, usage.Window)
+	}
+}
+
+func TestNeedsCompaction(t *testing.T) {
+	session := NewSession("test-session", "/path/to/project")
+	session.conversationID = "conv-1"
+	session.contextTokens = 170000
+
+	if session.needsCompaction() {
+		t.Error("Compaction should be off without a threshold")
+	}
+
+	session.SetCompactThreshold(DefaultCompactThreshold)
+	if !session.needsCompaction() {
+		t.Error("Expected compaction above the threshold")
+	}
+
+	session.contextTokens = 100000
+	if session.needsCompaction() {
+		t.Error("Expected no compaction below the threshold")
+	}
+
+	// A conversation that has not started has nothing to compact
+	session.contextTokens = 170000
+	session.conversationID = ""
+	if session.needsCompaction() {
+		t.Error("Expected no compaction without a conversation")
+	}
+}
+
+func TestCompact_WithModelSummary(t *testing.T) {
+	session := NewSession("test-session", "/path/to/project")
+	session.conversationID = "conv-1"
+	session.contextTokens = 170000
+	session.Messages = []Message{
+		{ID: "m1", Role: "user", Content: "Add a login page"},
+		{ID: "m2", Role: "assistant", Content: "Done"},
+	}
+
+	var summarizedID string
+	session.summarizer = func(conversationID string, authConfig AuthConfig) (string, error) {
+		summarizedID = conversationID
+		return "  The user asked for a login page, which was added.\n", nil
+	}
+
+	var emitted []Message
+	session.SetMessageHandler(func(msg Message) {
+		emitted = append(emitted, msg)
+	})
+
+	session.compact(AuthConfig{}, true)
+
+	if summarizedID != "conv-1" {
+		t.Errorf("Summarized conversation %q, want conv-1", summarizedID)
+	}
+	if session.conversationID != "" {
+		t.Error("Expected the conversation to be reset")
+	}
+	if session.compactSummary != "The user asked for a login page, which was added." {
+		t.Errorf("Unexpected summary: %q", session.compactSummary)
+	}
+	if session.compactions != 1 || session.contextTokens >= 170000 {
+		t.Errorf("Compactions = %d, Tokens = %d", session.compactions, session.contextTokens)
+	}
+
+	// The history is kept, with a marker at the end
+	messages := session.GetMessages()
+	if len(messages) != 3 || messages[0].ID != "m1" {
+		t.Fatalf("Expected history plus a marker, got %+v", messages)
+	}
+	marker := messages[2]
+	if marker.Metadata == nil || marker.Metadata.Compaction == nil {
+		t.Fatalf("Expected compaction metadata, got %+v", marker)
+	}
+	info := marker.Metadata.Compaction
+	if info.Method != "model" || !info.Automatic || info.TokensBefore != 170000 {
+		t.Errorf("Unexpected compaction info: %+v", info)
+	}
+	if len(emitted) != 1 || emitted[0].ID != marker.ID {
+		t.Errorf("Expected the marker to be emitted, got %+v", emitted)
+	}
+}
+
+func TestCompact_FallsBackToCompression(t *testing.T) {
+	session := NewSession("test-session", "/path/to/project")
+	session.conversationID = "conv-1"
+	session.summarizer = func(string, AuthConfig) (string, error) {
+		return "", errors.New("claude unavailable")
+	}
+
+	input, _ := json.Marshal(map[string]string{"file_path": "src/login.tsx"})
+	session.Messages = []Message{
+		{ID: "m1", Role: "user", Content: "Add a login page"},
+		{ID: "m2", Role: "assistant", Content: "Writing the page", Metadata: &MessageMetadata{
+			ToolUse: &ToolUse{ToolName: "Write", ToolID: "t1", Input: input},
+		}},
+	}
+	for i := 0; i < compactRecentMessages; i++ {
+		session.Messages = append(session.Messages, Message{ID: "r", Role: "assistant", Content: "recent reply"})
+	}
+
+	session.compact(AuthConfig{}, false)
+
+	messages := session.GetMessages()
+	info := messages[len(messages)-1].Metadata.Compaction
+	if info.Method != "compressed" || info.Automatic {
+		t.Errorf("Unexpected compaction info: %+v", info)
+	}
+	for _, want := range []string{"Add a login page", "src/login.tsx", "recent reply"} {
+		if !strings.Contains(session.compactSummary, want) {
+			t.Errorf("Summary missing %q:\n%s", want, session.compactSummary)
+		}
+	}
+}
+
+func TestCompact_RefusedWhileRunning(t *testing.T) {
+	session := NewSession("test-session", "/path/to/project")
+	session.Status = SessionStatusRunning
+
+	if err := session.Compact(AuthConfig{}); err == nil {
+		t.Error("Expected an error while the agent is working")
+	}
+}
+
+func TestCompact_SlashCommand(t *testing.T) {
+	useTempSessionsDir(t)
+
+	session := NewSession("test-session", "/path/to/project")
+	session.conversationID = "conv-1"
+	session.Messages = []Message{{ID: "m1", Role: "user", Content: "hello"}}
+	session.summarizer = func(string, AuthConfig) (string, error) {
+		return "Greeted the agent.", nil
+	}
+
+	if err := session.SendMessage("/compact", AuthConfig{}); err != nil {
+		t.Fatalf("SendMessage(/compact) error = %v", err)
+	}
+
+	// Compaction runs in the background, leaves the session idle and saves it
+	var loaded *Session
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		session.mu.RLock()
+		done := session.compactions == 1 && session.Status == SessionStatusIdle
+		session.mu.RUnlock()
+		if done {
+			saved, err := LoadSession("test-session")
+			if err == nil && saved.compactions == 1 && saved.Status == SessionStatusIdle {
+				loaded = saved
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for compaction")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Let the save finish updating the search index before the test cleans up
+	session.saveMu.Lock()
+	session.saveMu.Unlock()
+
+	// The summary survives a reload so the next conversation is still seeded
+	if loaded.compactSummary != "Greeted the agent." || loaded.compactions != 1 || loaded.conversationID != "" {
+		t.Errorf("Compaction state not persisted: summary %q, compactions %d, conversation %q",
+			loaded.compactSummary, loaded.compactions, loaded.conversationID)
+	}
+}
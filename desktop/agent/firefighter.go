@@ -13,10 +13,9 @@ type FirefighterMonitor struct {
 	session          *Session
 	isActive         bool
 	checkInterval    time.Duration
-	sessionStartTime time.Time       // When the session was created — used as the time floor
+	sessionStartTime time.Time // When the session was created — used as the time floor
 	lastCheckTime    time.Time
-	isFirstCheck     bool            // True until the first check completes
-	seenIssues       map[string]bool // Track issues we've already investigated
+	isFirstCheck     bool // True until the first check completes
 	onAlert          func(Alert)
 	onInvestigation  func(Investigation)
 	mu               sync.RWMutex
@@ -26,16 +25,18 @@ type FirefighterMonitor struct {
 
 // Alert represents a new issue detected by monitoring
 type Alert struct {
-	ID          string    `json:"id"`
+	ID          string    `json:"id"`     // The source's ID: Bugsnag error, Datadog monitor, ...
 	Source      string    `json:"source"` // "bugsnag", "datadog", "linear", "slack"
 	Severity    string    `json:"severity"`
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
 	FirstSeen   time.Time `json:"firstSeen"`
+	LastSeen    time.Time `json:"lastSeen"`
 	Count       int       `json:"count"`
 	URL         string    `json:"url"`
 	LinearID    string    `json:"linearId,omitempty"`    // Linear issue ID if from ticket
 	SlackThread string    `json:"slackThread,omitempty"` // Slack thread if from alert
+	Fingerprint string    `json:"fingerprint"`           // Identifies repeats of the alert (see AlertFingerprint)
 }
 
 // Investigation represents an ongoing investigation
@@ -62,7 +63,6 @@ func NewFirefighterMonitor(session *Session) *FirefighterMonitor {
 		checkInterval:    5 * time.Minute, // Check every 5 minutes by default
 		sessionStartTime: time.Now(),
 		isFirstCheck:     true,
-		seenIssues:       make(map[string]bool),
 		ctx:              ctx,
 		cancel:           cancel,
 	}
@@ -251,10 +251,12 @@ You are in active firefighter monitoring mode. You handle BOTH proactive monitor
 ## REPORTING
 
 6. **Report findings**:
-   - **High-priority tickets**: "🎫 [Priority] Linear ticket [ID]: [title]"
-   - **New proactive issues**: "🚨 NEW [severity]: [title]"
+   - **High-priority tickets**: "🎫 [Priority] Linear ticket [ID]: [title] (linear:[ID])"
+   - **New proactive issues**: "🚨 NEW [severity]: [title] (bugsnag:[error id])" or "(datadog:[monitor id])"
    - **All clear**: "✅ Linear queue: N tickets, Monitoring: No new issues"
 
+` + buildReportingFormat() + `
+
 7. **Auto-investigate based on priority**:
    - **Urgent/High from Linear**: Immediately investigate
    - **HIGH severity from monitoring**: Immediately investigate
@@ -268,7 +270,7 @@ You are in active firefighter monitoring mode. You handle BOTH proactive monitor
    - If tests pass, create draft PR and update Linear ticket
 
 ` + fm.buildSlackMonitoringSection() + `
-
+` + fm.buildKnownIncidents() + `
 ` + fm.buildScopeReminder() + `
 **IMPORTANT**:
 - **Prioritize Linear tickets over proactive monitoring**
@@ -283,6 +285,16 @@ Last check time: ` + fm.lastCheckTime.Format(time.RFC3339) + `
 Begin monitoring check now. Start with Linear triage queue, then proactive monitoring` + fm.slackMonitoringReminder() + `.`
 }
 
+// buildKnownIncidents lists incidents from earlier checks, including those
+// from before a restart
+func (fm *FirefighterMonitor) buildKnownIncidents() string {
+	store, err := OpenIncidentStore()
+	if err != nil {
+		return ""
+	}
+	return buildKnownIncidentsSection(store, time.Now())
+}
+
 // buildScopeReminder returns a scope reminder for the monitoring prompt if scope is configured
 func (fm *FirefighterMonitor) buildScopeReminder() string {
 	scope, _ := fm.session.ModeConfig["scope"].(string)
@@ -394,6 +406,11 @@ func (fm *FirefighterMonitor) IsActive() bool {
 
 // GetStatus returns current monitoring status
 func (fm *FirefighterMonitor) GetStatus() map[string]interface{} {
+	seenIssues := 0
+	if store, err := OpenIncidentStore(); err == nil {
+		seenIssues = store.AlertCount()
+	}
+
 	fm.mu.RLock()
 	defer fm.mu.RUnlock()
 
@@ -401,26 +418,17 @@ func (fm *FirefighterMonitor) GetStatus() map[string]interface{} {
 		"active":        fm.isActive,
 		"checkInterval": fm.checkInterval.String(),
 		"lastCheck":     fm.lastCheckTime,
-		"seenIssues":    len(fm.seenIssues),
+		"seenIssues":    seenIssues,
 	}
 }
 
-// MarkIssueSeen marks an issue as already investigated
-func (fm *FirefighterMonitor) MarkIssueSeen(issueID string) {
-	fm.mu.Lock()
-	defer fm.mu.Unlock()
-	fm.seenIssues[issueID] = true
-}
-
-// ClearSeenIssues resets the seen issues cache
-func (fm *FirefighterMonitor) ClearSeenIssues() {
-	fm.mu.Lock()
-	defer fm.mu.Unlock()
-	fm.seenIssues = make(map[string]bool)
-}
-
 // InvestigateLinearTicket triggers investigation for a specific Linear ticket
 func (fm *FirefighterMonitor) InvestigateLinearTicket(linearIssueID string) error {
+	fm.trackInvestigation(Alert{
+		Source:   "linear",
+		Title:    "Linear ticket " + linearIssueID,
+		LinearID: linearIssueID,
+	})
 	prompt := fm.buildTicketInvestigationPrompt(linearIssueID)
 
 	// Send to Claude for investigation
@@ -431,6 +439,30 @@ func (fm *FirefighterMonitor) InvestigateLinearTicket(linearIssueID string) erro
 	return nil
 }
 
+// trackInvestigation records a requested investigation in the incident store
+func (fm *FirefighterMonitor) trackInvestigation(alert Alert) {
+	store, err := OpenIncidentStore()
+	if err != nil {
+		fmt.Printf("[firefighter] Failed to open incident store: %v\n", err)
+		return
+	}
+	inc, _, err := store.RecordAlert(alert, fm.session.ID, "")
+	if err == nil && (inc.Status == IncidentStatusNew || inc.Status == IncidentStatusFailed) {
+		inc, err = store.Update(inc.ID, IncidentUpdate{Status: IncidentStatusInvestigating, Note: "Investigation requested"})
+	}
+	if err != nil {
+		fmt.Printf("[firefighter] Failed to record investigation: %v\n", err)
+		return
+	}
+
+	fm.session.mu.RLock()
+	handler := fm.session.onIncident
+	fm.session.mu.RUnlock()
+	if handler != nil {
+		handler(inc)
+	}
+}
+
 // buildTicketInvestigationPrompt creates a prompt for investigating a specific Linear ticket
 func (fm *FirefighterMonitor) buildTicketInvestigationPrompt(linearIssueID string) string {
 	return fmt.Sprintf(`🔥 FIREFIGHTER TICKET INVESTIGATION 🔥
@@ -501,6 +533,13 @@ Begin investigation now.`, linearIssueID, linearIssueID, linearIssueID)
 
 // InvestigateSlackAlert triggers investigation for a Slack alert mention
 func (fm *FirefighterMonitor) InvestigateSlackAlert(slackThreadID, alertMessage string) error {
+	title := strings.TrimSpace(strings.SplitN(strings.TrimSpace(alertMessage), "\n", 2)[0])
+	fm.trackInvestigation(Alert{
+		Source:      "slack",
+		Title:       truncateString(title, 120),
+		Description: alertMessage,
+		SlackThread: slackThreadID,
+	})
 	prompt := fm.buildSlackAlertPrompt(slackThreadID, alertMessage)
 
 	// Send to Claude for investigation
@@ -0,0 +1,97 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Forking. A fork is a new session that copies its parent's history up to a
+// message and continues from there, so two follow-ups can be tried from the
+// same point. When the fork point is the end of the parent's Claude
+// conversation, the conversation itself is forked (claude --fork-session);
+// otherwise the fork starts a new conversation seeded with a summary of the
+// copied history, as after a compaction.
+
+// Fork returns a new session with the given ID that copies the session's
+// history up to and including messageID. The fork works in projectPath, or
+// in the parent's project if projectPath is empty.
+func (s *Session) Fork(id, messageID, projectPath string) (*Session, error) {
+	if err := s.ensureMessagesLoaded(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.Mode == "boatmanmode" || s.Mode == "triage" {
+		return nil, fmt.Errorf("%s sessions cannot be forked", s.Mode)
+	}
+	if s.Status == SessionStatusRunning || s.Status == SessionStatusWaiting {
+		return nil, fmt.Errorf("wait for the agent to finish before forking the session")
+	}
+
+	at := -1
+	for i, msg := range s.Messages {
+		if msg.ID == messageID {
+			at = i
+			break
+		}
+	}
+	if at < 0 {
+		return nil, fmt.Errorf("message %s not found in session %s", messageID, s.ID)
+	}
+
+	if projectPath == "" {
+		projectPath = s.ProjectPath
+	}
+
+	fork := NewSession(id, projectPath)
+	fork.ParentID = s.ID
+	fork.ForkedFrom = messageID
+	fork.Messages = append([]Message(nil), s.Messages[:at+1]...)
+	fork.Tags = append([]string{}, s.Tags...)
+	fork.Model = s.Model
+	fork.ReasoningEffort = s.ReasoningEffort
+	fork.Mode = s.Mode
+	if s.ModeConfig != nil {
+		fork.ModeConfig = make(map[string]interface{}, len(s.ModeConfig))
+		for k, v := range s.ModeConfig {
+			fork.ModeConfig[k] = v
+		}
+	}
+	fork.systemPrompt = s.systemPrompt
+	fork.contextWindow = s.contextWindow
+
+	// Claude CLI conversations are stored per working directory, so only a
+	// fork in the same project can pick up the parent's conversation
+	endsConversation := projectPath == s.ProjectPath && conversationEndsAt(s.Messages, at)
+	switch {
+	case endsConversation && s.conversationID != "":
+		fork.conversationID = s.conversationID
+		fork.forkConversation = true
+		fork.contextTokens = s.contextTokens
+	case endsConversation && s.compactSummary != "":
+		fork.compactSummary = s.compactSummary
+	default:
+		fork.compactSummary = compressMessages(fork.Messages, compactSummaryTokens)
+	}
+
+	fork.CreatedAt = time.Now()
+	fork.UpdatedAt = fork.CreatedAt
+	fork.ctx, fork.cancel = context.WithCancel(context.Background())
+
+	return fork, nil
+}
+
+// conversationEndsAt reports whether the message at index at is the last one
+// the Claude conversation has seen. Later system messages are local notes
+// and don't count.
+func conversationEndsAt(messages []Message, at int) bool {
+	for _, msg := range messages[at+1:] {
+		if msg.Role != "system" {
+			return false
+		}
+	}
+	return true
+}
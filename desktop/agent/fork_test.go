@@ -0,0 +1,150 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+)
+
+func newForkParent() *Session {
+	session := NewSession("parent", "/path/to/project")
+	session.conversationID = "conv-1"
+	session.Model = "opus"
+	session.Tags = []string{"auth"}
+	session.Messages = []Message{
+		{ID: "m1", Role: "user", Content: "Add a login page"},
+		{ID: "m2", Role: "assistant", Content: "Added the login page"},
+		{ID: "m3", Role: "user", Content: "Now use OAuth"},
+		{ID: "m4", Role: "assistant", Content: "Switched to OAuth"},
+		{ID: "m5", Role: "system", Content: "Session saved"},
+	}
+	return session
+}
+
+func TestFork_ForksConversationAtEnd(t *testing.T) {
+	parent := newForkParent()
+
+	fork, err := parent.Fork("child", "m4", "")
+	if err != nil {
+		t.Fatalf("Fork() error = %v", err)
+	}
+
+	if fork.ParentID != "parent" || fork.ForkedFrom != "m4" || fork.ProjectPath != parent.ProjectPath {
+		t.Errorf("Unexpected fork links: parent %q, from %q, project %q", fork.ParentID, fork.ForkedFrom, fork.ProjectPath)
+	}
+	if len(fork.Messages) != 4 || fork.Messages[3].ID != "m4" {
+		t.Errorf("Expected the history up to m4, got %+v", fork.Messages)
+	}
+	if fork.conversationID != "conv-1" || !fork.forkConversation || fork.compactSummary != "" {
+		t.Errorf("Expected the conversation to be forked, got conversation %q, fork %v, summary %q",
+			fork.conversationID, fork.forkConversation, fork.compactSummary)
+	}
+	if fork.Model != "opus" || len(fork.Tags) != 1 {
+		t.Errorf("Settings not copied: model %q, tags %v", fork.Model, fork.Tags)
+	}
+
+	// The fork's history is its own
+	fork.Tags[0] = "changed"
+	if parent.Tags[0] != "auth" {
+		t.Error("Fork shares tags with its parent")
+	}
+}
+
+func TestFork_SeedsSummaryFromEarlierPoint(t *testing.T) {
+	parent := newForkParent()
+
+	fork, err := parent.Fork("child", "m2", "")
+	if err != nil {
+		t.Fatalf("Fork() error = %v", err)
+	}
+
+	if len(fork.Messages) != 2 {
+		t.Errorf("Expected the history up to m2, got %d messages", len(fork.Messages))
+	}
+	// The parent's conversation already contains later turns
+	if fork.conversationID != "" || fork.forkConversation {
+		t.Errorf("Expected a new conversation, got %q (fork %v)", fork.conversationID, fork.forkConversation)
+	}
+	if !strings.Contains(fork.compactSummary, "Add a login page") || strings.Contains(fork.compactSummary, "OAuth") {
+		t.Errorf("Summary should cover only the copied history:\n%s", fork.compactSummary)
+	}
+}
+
+func TestFork_InWorktreeStartsNewConversation(t *testing.T) {
+	parent := newForkParent()
+
+	fork, err := parent.Fork("child", "m4", "/path/to/project/.worktrees/fork-1")
+	if err != nil {
+		t.Fatalf("Fork() error = %v", err)
+	}
+
+	if fork.ProjectPath != "/path/to/project/.worktrees/fork-1" {
+		t.Errorf("ProjectPath = %q", fork.ProjectPath)
+	}
+	if fork.conversationID != "" || fork.compactSummary == "" {
+		t.Errorf("Expected a seeded new conversation, got conversation %q, summary %q", fork.conversationID, fork.compactSummary)
+	}
+}
+
+func TestFork_Errors(t *testing.T) {
+	parent := newForkParent()
+	if _, err := parent.Fork("child", "missing", ""); err == nil {
+		t.Error("Expected an error for an unknown message")
+	}
+
+	parent.Status = SessionStatusRunning
+	if _, err := parent.Fork("child", "m2", ""); err == nil {
+		t.Error("Expected an error while the agent is working")
+	}
+
+	parent.Status = SessionStatusIdle
+	parent.Mode = "boatmanmode"
+	if _, err := parent.Fork("child", "m2", ""); err == nil {
+		t.Error("Expected an error forking a boatmanmode session")
+	}
+}
+
+func TestFork_TakesNewConversationID(t *testing.T) {
+	fork, err := newForkParent().Fork("child", "m4", "")
+	if err != nil {
+		t.Fatalf("Fork() error = %v", err)
+	}
+
+	var builder strings.Builder
+	var messageID string
+	fork.parseStreamLine(`{"type":"system","subtype":"init","session_id":"conv-2"}`, &builder, &messageID)
+
+	if fork.conversationID != "conv-2" || fork.forkConversation {
+		t.Errorf("Expected the forked conversation ID, got %q (fork %v)", fork.conversationID, fork.forkConversation)
+	}
+
+	// Later runs continue the forked conversation
+	fork.parseStreamLine(`{"type":"system","subtype":"init","session_id":"conv-3"}`, &builder, &messageID)
+	if fork.conversationID != "conv-2" {
+		t.Errorf("Conversation ID changed to %q", fork.conversationID)
+	}
+}
+
+func TestManager_ForkSession(t *testing.T) {
+	useTempSessionsDir(t)
+
+	manager := NewManager()
+	parent := newForkParent()
+	manager.sessions[parent.ID] = parent
+
+	fork, err := manager.ForkSession(parent.ID, "m4", "")
+	if err != nil {
+		t.Fatalf("ForkSession() error = %v", err)
+	}
+	if _, err := manager.GetSession(fork.ID); err != nil {
+		t.Errorf("Fork not registered: %v", err)
+	}
+
+	loaded, err := LoadSession(fork.ID)
+	if err != nil {
+		t.Fatalf("LoadSession() error = %v", err)
+	}
+	if loaded.ParentID != parent.ID || loaded.ForkedFrom != "m4" || !loaded.forkConversation || len(loaded.Messages) != 4 {
+		t.Errorf("Fork not persisted: parent %q, from %q, fork %v, %d messages",
+			loaded.ParentID, loaded.ForkedFrom, loaded.forkConversation, len(loaded.Messages))
+	}
+}
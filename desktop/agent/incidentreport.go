@@ -0,0 +1,242 @@
+package agent
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Firefighter reports use one line per alert and per investigation update
+// (see buildReportingFormat), which are recorded in the incident store when
+// the agent finishes a turn.
+
+var (
+	// 🎫 [High] Linear ticket ENG-123: Checkout fails (bugsnag:5f3a9c)
+	// 🚨 NEW High: Error rate spike on payments (datadog:12345)
+	alertLinePattern = regexp.MustCompile(`(?m)^[\s>*-]*(🎫|🚨)\s*(?:\*\*)?\s*(?:NEW\s+)?\[?(Urgent|Critical|High|Medium|Low)\]?\s*:?\s*(?:\*\*)?\s*(.+?)\s*$`)
+	ticketPattern    = regexp.MustCompile(`^(?:Linear ticket\s+)?([A-Z][A-Z0-9]*-\d+)\s*:\s*(.+)$`)
+
+	// 🔧 Fixing (bugsnag:5f3a9c): worktree ../worktrees/fix-checkout branch fix/checkout
+	updateLinePattern = regexp.MustCompile(`(?m)^[\s>*-]*(🔍|🔧|🧪|🔗|✅|❌)[^(\n]*(\(\s*(?:bugsnag|datadog|linear|slack)\s*:\s*[^)\s]+\s*\))\s*:?\s*(.*?)\s*$`)
+
+	refPattern      = regexp.MustCompile(`\(\s*(bugsnag|datadog|linear|slack)\s*:\s*([^)\s]+)\s*\)`)
+	urlPattern      = regexp.MustCompile(`https?://[^\s)]+`)
+	worktreePattern = regexp.MustCompile(`(?i)worktree:?\s+(\S+)`)
+	branchPattern   = regexp.MustCompile(`(?i)branch:?\s+(\S+)`)
+	prNumberPattern = regexp.MustCompile(`(?:#|/pull/)(\d+)`)
+)
+
+// reportedUpdate is an investigation update for the incident holding the
+// alert with the given fingerprint
+type reportedUpdate struct {
+	fingerprint string
+	update      IncidentUpdate
+}
+
+// parseIncidentReport extracts the alerts and investigation updates from a
+// firefighter report
+func parseIncidentReport(content string) ([]Alert, []reportedUpdate) {
+	var alerts []Alert
+	for _, m := range alertLinePattern.FindAllStringSubmatch(content, -1) {
+		rest := m[3]
+		alert := Alert{Severity: m[2], URL: urlPattern.FindString(rest)}
+
+		if ref := refPattern.FindStringSubmatch(rest); ref != nil {
+			alert = refAlert(alert, ref[1], ref[2])
+			rest = strings.TrimSpace(strings.Replace(rest, ref[0], "", 1))
+		}
+		if m[1] == "🎫" {
+			if ticket := ticketPattern.FindStringSubmatch(rest); ticket != nil {
+				alert.LinearID = ticket[1]
+				rest = ticket[2]
+			}
+			if alert.Source == "" {
+				alert.Source = "linear"
+			}
+		}
+		if alert.Source == "" {
+			alert.Source = guessAlertSource(rest)
+		}
+		alert.Title = strings.TrimSpace(strings.Trim(rest, "*"))
+		if alert.Title == "" {
+			continue
+		}
+		alert.Description = strings.TrimSpace(m[0])
+		alerts = append(alerts, alert)
+	}
+
+	var updates []reportedUpdate
+	for _, m := range updateLinePattern.FindAllStringSubmatch(content, -1) {
+		ref := refPattern.FindStringSubmatch(m[2])
+		text := m[3]
+		var update IncidentUpdate
+		switch m[1] {
+		case "🔍":
+			update.Status = IncidentStatusInvestigating
+			update.Note = text
+		case "🔧":
+			update.Status = IncidentStatusFixing
+			if wt := worktreePattern.FindStringSubmatch(text); wt != nil {
+				update.WorktreePath = wt[1]
+			}
+			if br := branchPattern.FindStringSubmatch(text); br != nil {
+				update.BranchName = br[1]
+			}
+			if update.WorktreePath == "" {
+				update.Note = text
+			}
+		case "🧪":
+			update.Status = IncidentStatusTesting
+			update.Note = text
+		case "🔗":
+			update.PRURL = urlPattern.FindString(text)
+			if pr := prNumberPattern.FindStringSubmatch(text); pr != nil {
+				update.PRNumber = pr[1]
+			}
+			if update.PRURL == "" && update.PRNumber == "" {
+				continue
+			}
+		case "✅":
+			update.Status = IncidentStatusResolved
+			update.Summary = text
+		case "❌":
+			update.Status = IncidentStatusFailed
+			update.Note = text
+		}
+		updates = append(updates, reportedUpdate{
+			fingerprint: AlertFingerprint(refAlert(Alert{}, ref[1], ref[2])),
+			update:      update,
+		})
+	}
+
+	return alerts, updates
+}
+
+// refAlert fills in the alert's source and ID from a "(source:id)" reference
+func refAlert(alert Alert, source, id string) Alert {
+	alert.Source = source
+	switch source {
+	case "linear":
+		alert.LinearID = id
+	case "slack":
+		alert.SlackThread = id
+	default:
+		alert.ID = id
+	}
+	return alert
+}
+
+func guessAlertSource(text string) string {
+	lower := strings.ToLower(text)
+	switch {
+	case strings.Contains(lower, "bugsnag") || strings.Contains(lower, "exception") || strings.Contains(lower, "crash"):
+		return "bugsnag"
+	case strings.Contains(lower, "slack"):
+		return "slack"
+	default:
+		return "datadog"
+	}
+}
+
+// recordIncidentReports records the alerts and updates in the session's
+// assistant messages since the last scan. Returns the incidents that changed.
+func (s *Session) recordIncidentReports(store *IncidentStore) ([]Incident, error) {
+	s.incidentScanMu.Lock()
+	defer s.incidentScanMu.Unlock()
+
+	s.mu.RLock()
+	scannedAt, _ := time.Parse(time.RFC3339Nano, fmt.Sprint(s.ModeConfig["incidentsScannedAt"]))
+	s.mu.RUnlock()
+
+	changed := map[string]Incident{}
+	var order []string
+	note := func(inc Incident) {
+		if _, ok := changed[inc.ID]; !ok {
+			order = append(order, inc.ID)
+		}
+		changed[inc.ID] = inc
+	}
+
+	latest := scannedAt
+	for _, msg := range s.GetMessages() {
+		if !msg.Timestamp.After(scannedAt) {
+			continue
+		}
+		if msg.Timestamp.After(latest) {
+			latest = msg.Timestamp
+		}
+		if msg.Role != "assistant" || (msg.Metadata != nil && (msg.Metadata.ToolUse != nil || msg.Metadata.ToolResult != nil)) {
+			continue
+		}
+
+		alerts, updates := parseIncidentReport(msg.Content)
+		for _, alert := range alerts {
+			alert.FirstSeen = msg.Timestamp
+			inc, _, err := store.RecordAlert(alert, s.ID, msg.ID)
+			if err != nil {
+				return nil, err
+			}
+			note(inc)
+		}
+		for _, u := range updates {
+			inc, ok := store.FindByFingerprint(u.fingerprint)
+			if !ok {
+				continue
+			}
+			inc, err := store.Update(inc.ID, u.update)
+			if err != nil {
+				return nil, err
+			}
+			note(inc)
+		}
+	}
+
+	if latest.After(scannedAt) {
+		s.SetModeConfigValue("incidentsScannedAt", latest.Format(time.RFC3339Nano))
+	}
+
+	incidents := make([]Incident, len(order))
+	for i, id := range order {
+		incidents[i] = changed[id]
+	}
+	return incidents, nil
+}
+
+// buildKnownIncidentsSection lists the incidents already tracked so the agent
+// doesn't investigate them again
+func buildKnownIncidentsSection(store *IncidentStore, now time.Time) string {
+	var lines []string
+	for _, inc := range store.List() {
+		if inc.Status == IncidentStatusResolved && now.Sub(inc.ResolvedAt) > 7*24*time.Hour {
+			continue
+		}
+		refs := make([]string, len(inc.Alerts))
+		for i, alert := range inc.Alerts {
+			refs[i] = alert.Fingerprint
+		}
+		lines = append(lines, fmt.Sprintf("- [%s] %s (%s)", inc.Status, inc.Title, strings.Join(refs, ", ")))
+		if len(lines) == 30 {
+			break
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return `
+**KNOWN INCIDENTS** (already tracked across restarts — do NOT investigate these again; report them only if their status changes, and report a resolved one again if it recurs):
+` + strings.Join(lines, "\n") + "\n"
+}
+
+// buildReportingFormat describes the report lines recorded in the incident store
+func buildReportingFormat() string {
+	return `**REPORT FORMAT** (one line each, so incidents can be tracked):
+   - End every alert line with a reference to its source: (bugsnag:<error id>), (datadog:<monitor id>), (linear:<ticket id>) or (slack:<thread ts>)
+   - Report investigation progress with the same reference:
+     - "🔍 Investigating (bugsnag:<id>): <what you're checking>"
+     - "🔧 Fixing (bugsnag:<id>): worktree <path> branch <branch>"
+     - "🧪 Testing (bugsnag:<id>)"
+     - "🔗 PR (bugsnag:<id>): <PR URL>"
+     - "✅ Resolved (bugsnag:<id>): <one-line summary of root cause and fix>"
+     - "❌ Failed (bugsnag:<id>): <what blocked the fix>"`
+}
@@ -0,0 +1,517 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Incident statuses, in the order an investigation usually moves through them
+const (
+	IncidentStatusNew           = "new"
+	IncidentStatusInvestigating = "investigating"
+	IncidentStatusFixing        = "fixing"
+	IncidentStatusTesting       = "testing"
+	IncidentStatusResolved      = "resolved"
+	IncidentStatusFailed        = "failed"
+)
+
+// Incident groups related alerts from any source (a Bugsnag error, the
+// Datadog monitor it trips, the Linear ticket and Slack thread about it) with
+// the timeline of their investigation.
+type Incident struct {
+	ID            string          `json:"id"`
+	Title         string          `json:"title"`
+	Description   string          `json:"description"`
+	Source        string          `json:"source"` // Source of the first alert
+	Severity      string          `json:"severity"`
+	Status        string          `json:"status"`
+	Alerts        []Alert         `json:"alerts"`
+	Timeline      []TimelineEntry `json:"timeline"`
+	Investigation *Investigation  `json:"investigation,omitempty"`
+	LinearID      string          `json:"linearId,omitempty"`
+	SlackThread   string          `json:"slackThread,omitempty"`
+	URL           string          `json:"url,omitempty"`
+	PRNumber      string          `json:"prNumber,omitempty"`
+	SessionIDs    []string        `json:"sessionIds"`
+	MessageIDs    []string        `json:"messageIds"`
+	FirstSeen     time.Time       `json:"firstSeen"`
+	LastUpdated   time.Time       `json:"lastUpdated"`
+	ResolvedAt    time.Time       `json:"resolvedAt,omitempty"`
+}
+
+// TimelineEntry is one event in an incident's history
+type TimelineEntry struct {
+	Time    time.Time `json:"time"`
+	Kind    string    `json:"kind"` // "alert", "status", "worktree", "pr", "summary", "note"
+	Status  string    `json:"status,omitempty"`
+	Message string    `json:"message"`
+}
+
+// IncidentUpdate is a change to an incident's investigation. Empty fields are
+// left as they are.
+type IncidentUpdate struct {
+	Status       string
+	WorktreePath string
+	BranchName   string
+	PRNumber     string
+	PRURL        string
+	Summary      string
+	Note         string
+}
+
+// IncidentStore persists incidents across restarts so alerts that were
+// already investigated are recognized instead of being investigated again.
+type IncidentStore struct {
+	mu        sync.Mutex
+	path      string
+	incidents []*Incident
+	now       func() time.Time
+}
+
+var (
+	incidentStoreMu sync.Mutex
+	incidentStore   *IncidentStore
+)
+
+// OpenIncidentStore returns the incident store for the sessions directory,
+// loading it from disk on first use
+func OpenIncidentStore() (*IncidentStore, error) {
+	sessionsDir, err := GetSessionsDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions directory: %w", err)
+	}
+	path := filepath.Join(sessionsDir, "firefighter", "incidents.json")
+
+	incidentStoreMu.Lock()
+	defer incidentStoreMu.Unlock()
+
+	if incidentStore != nil && incidentStore.path == path {
+		return incidentStore, nil
+	}
+
+	store, err := NewIncidentStore(path)
+	if err != nil {
+		return nil, err
+	}
+	incidentStore = store
+	return store, nil
+}
+
+// NewIncidentStore returns a store backed by the file at path, loading any
+// incidents already saved there
+func NewIncidentStore(path string) (*IncidentStore, error) {
+	st := &IncidentStore{path: path, now: time.Now}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read incidents: %w", err)
+	}
+	if err := json.Unmarshal(data, &st.incidents); err != nil {
+		return nil, fmt.Errorf("failed to parse incidents: %w", err)
+	}
+	return st, nil
+}
+
+// RecordAlert adds an alert to the incident it belongs to, or opens a new
+// incident. An alert already recorded (same fingerprint) only has its count
+// and last-seen time updated; one seen again after its incident was resolved
+// reopens the incident. Returns the incident and whether the alert was new.
+func (st *IncidentStore) RecordAlert(alert Alert, sessionID, messageID string) (Incident, bool, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	now := st.now()
+	if alert.Fingerprint == "" {
+		alert.Fingerprint = AlertFingerprint(alert)
+	}
+	if alert.FirstSeen.IsZero() {
+		alert.FirstSeen = now
+	}
+	if alert.LastSeen.IsZero() {
+		alert.LastSeen = alert.FirstSeen
+	}
+	if alert.Count == 0 {
+		alert.Count = 1
+	}
+	alert.Severity = normalizeSeverity(alert.Severity)
+
+	inc := st.match(alert)
+	isNew := true
+	// The same report can be processed more than once as it streams in
+	repeated := false
+	if inc == nil {
+		inc = &Incident{
+			ID:          uuid.New().String(),
+			Title:       alert.Title,
+			Description: alert.Description,
+			Source:      alert.Source,
+			Severity:    alert.Severity,
+			Status:      IncidentStatusNew,
+			FirstSeen:   alert.FirstSeen,
+		}
+		st.incidents = append(st.incidents, inc)
+	} else {
+		repeated = messageID != "" && containsString(inc.MessageIDs, messageID)
+		if existing := inc.alert(alert.Fingerprint); existing != nil {
+			isNew = false
+			if !repeated {
+				existing.Count++
+				existing.LastSeen = now
+			}
+		}
+	}
+
+	if isNew {
+		inc.Alerts = append(inc.Alerts, alert)
+		inc.addEntry(now, TimelineEntry{Kind: "alert", Message: fmt.Sprintf("%s alert: %s", sourceLabel(alert.Source), alert.Title)})
+	}
+	if inc.Status == IncidentStatusResolved && !repeated {
+		inc.setStatus(now, IncidentStatusNew, "Reopened: the alert fired again")
+	}
+
+	if severityRank(alert.Severity) > severityRank(inc.Severity) {
+		inc.Severity = alert.Severity
+	}
+	if inc.LinearID == "" {
+		inc.LinearID = alert.LinearID
+	}
+	if inc.SlackThread == "" {
+		inc.SlackThread = alert.SlackThread
+	}
+	if inc.URL == "" {
+		inc.URL = alert.URL
+	}
+	inc.SessionIDs = appendUnique(inc.SessionIDs, sessionID)
+	inc.MessageIDs = appendUnique(inc.MessageIDs, messageID)
+	inc.LastUpdated = now
+
+	return inc.clone(), isNew, st.save()
+}
+
+// match finds the incident an alert belongs to: one with the same
+// fingerprint, Linear ticket or Slack thread, or an open one with the same
+// title. Tickets and threads are only matched by reference, since their
+// titles are written by people.
+func (st *IncidentStore) match(alert Alert) *Incident {
+	key := titleKey(alert.Title)
+	if alert.LinearID != "" || alert.SlackThread != "" || len(strings.Fields(key)) < 3 {
+		key = ""
+	}
+	var byTitle *Incident
+	for _, inc := range st.incidents {
+		if inc.alert(alert.Fingerprint) != nil ||
+			(alert.LinearID != "" && strings.EqualFold(inc.LinearID, alert.LinearID)) ||
+			(alert.SlackThread != "" && inc.SlackThread == alert.SlackThread) {
+			return inc
+		}
+		if byTitle == nil && key != "" && inc.Status != IncidentStatusResolved {
+			for _, a := range inc.Alerts {
+				if titleKey(a.Title) == key {
+					byTitle = inc
+					break
+				}
+			}
+		}
+	}
+	return byTitle
+}
+
+// Update applies an investigation update to an incident
+func (st *IncidentStore) Update(id string, update IncidentUpdate) (Incident, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	inc := st.find(id)
+	if inc == nil {
+		return Incident{}, fmt.Errorf("incident not found: %s", id)
+	}
+
+	now := st.now()
+	inv := inc.Investigation
+	if inv == nil {
+		inv = &Investigation{
+			ID:            uuid.New().String(),
+			AlertID:       inc.Alerts[0].Fingerprint,
+			LinearIssueID: inc.LinearID,
+			Status:        IncidentStatusInvestigating,
+			StartedAt:     now,
+		}
+		inc.Investigation = inv
+	}
+
+	if update.WorktreePath != "" && update.WorktreePath != inv.WorktreePath {
+		inv.WorktreePath = update.WorktreePath
+		message := "Worktree " + update.WorktreePath
+		if update.BranchName != "" {
+			message += " on branch " + update.BranchName
+		}
+		inc.addEntry(now, TimelineEntry{Kind: "worktree", Message: message})
+	}
+	if update.BranchName != "" {
+		inv.BranchName = update.BranchName
+	}
+	if (update.PRNumber != "" && update.PRNumber != inv.PRNumber) || (update.PRURL != "" && update.PRURL != inc.URL) {
+		if update.PRNumber != "" {
+			inv.PRNumber = update.PRNumber
+			inc.PRNumber = update.PRNumber
+		}
+		message := "Pull request"
+		if inv.PRNumber != "" {
+			message += " #" + inv.PRNumber
+		}
+		if update.PRURL != "" {
+			inc.URL = update.PRURL
+			message += " " + update.PRURL
+		}
+		inc.addEntry(now, TimelineEntry{Kind: "pr", Message: message})
+	}
+	if update.Summary != "" && update.Summary != inv.Summary {
+		inv.Summary = update.Summary
+		inc.addEntry(now, TimelineEntry{Kind: "summary", Message: update.Summary})
+	}
+	if update.Status != "" && update.Status != inc.Status {
+		inc.setStatus(now, update.Status, update.Note)
+	} else if update.Note != "" {
+		inc.addEntry(now, TimelineEntry{Kind: "note", Message: update.Note})
+	}
+
+	inv.Status = inc.Status
+	switch inc.Status {
+	case IncidentStatusResolved:
+		inv.Status = "done"
+		inv.CompletedAt = inc.ResolvedAt
+	case IncidentStatusFailed:
+		inv.CompletedAt = now
+	}
+	inc.LastUpdated = now
+
+	return inc.clone(), st.save()
+}
+
+// Get returns an incident by ID
+func (st *IncidentStore) Get(id string) (Incident, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	inc := st.find(id)
+	if inc == nil {
+		return Incident{}, fmt.Errorf("incident not found: %s", id)
+	}
+	return inc.clone(), nil
+}
+
+// FindByFingerprint returns the incident holding the alert with fingerprint,
+// or the one for the Linear ticket or Slack thread it refers to
+func (st *IncidentStore) FindByFingerprint(fingerprint string) (Incident, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	for _, inc := range st.incidents {
+		if inc.alert(fingerprint) != nil ||
+			(inc.LinearID != "" && strings.EqualFold(fingerprint, "linear:"+inc.LinearID)) ||
+			(inc.SlackThread != "" && fingerprint == "slack:"+inc.SlackThread) {
+			return inc.clone(), true
+		}
+	}
+	return Incident{}, false
+}
+
+// List returns all incidents, most recently updated first
+func (st *IncidentStore) List() []Incident {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	incidents := make([]Incident, len(st.incidents))
+	for i, inc := range st.incidents {
+		incidents[i] = inc.clone()
+	}
+	sort.SliceStable(incidents, func(i, j int) bool {
+		return incidents[i].LastUpdated.After(incidents[j].LastUpdated)
+	})
+	return incidents
+}
+
+// AlertCount returns the number of distinct alerts recorded
+func (st *IncidentStore) AlertCount() int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	count := 0
+	for _, inc := range st.incidents {
+		count += len(inc.Alerts)
+	}
+	return count
+}
+
+func (st *IncidentStore) find(id string) *Incident {
+	for _, inc := range st.incidents {
+		if inc.ID == id {
+			return inc
+		}
+	}
+	return nil
+}
+
+// save writes the store to disk. The caller holds st.mu.
+func (st *IncidentStore) save() error {
+	if err := os.MkdirAll(filepath.Dir(st.path), 0755); err != nil {
+		return fmt.Errorf("failed to create incidents directory: %w", err)
+	}
+	data, err := json.MarshalIndent(st.incidents, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal incidents: %w", err)
+	}
+	if err := writeFileAtomic(st.path, data); err != nil {
+		return fmt.Errorf("failed to write incidents: %w", err)
+	}
+	return nil
+}
+
+func (inc *Incident) alert(fingerprint string) *Alert {
+	for i := range inc.Alerts {
+		if inc.Alerts[i].Fingerprint == fingerprint {
+			return &inc.Alerts[i]
+		}
+	}
+	return nil
+}
+
+func (inc *Incident) setStatus(now time.Time, status, note string) {
+	message := fmt.Sprintf("Status changed from %s to %s", inc.Status, status)
+	if note != "" {
+		message += ": " + note
+	}
+	inc.Status = status
+	if status == IncidentStatusResolved {
+		inc.ResolvedAt = now
+	} else {
+		inc.ResolvedAt = time.Time{}
+	}
+	inc.addEntry(now, TimelineEntry{Kind: "status", Status: status, Message: message})
+}
+
+func (inc *Incident) addEntry(now time.Time, entry TimelineEntry) {
+	entry.Time = now
+	inc.Timeline = append(inc.Timeline, entry)
+}
+
+// clone returns a copy of the incident that shares no state with the store
+func (inc *Incident) clone() Incident {
+	c := *inc
+	c.Alerts = append([]Alert(nil), inc.Alerts...)
+	c.Timeline = append([]TimelineEntry(nil), inc.Timeline...)
+	c.SessionIDs = append([]string(nil), inc.SessionIDs...)
+	c.MessageIDs = append([]string(nil), inc.MessageIDs...)
+	if inc.Investigation != nil {
+		inv := *inc.Investigation
+		c.Investigation = &inv
+	}
+	return c
+}
+
+// AlertFingerprint identifies an alert across reports: by the source's own ID
+// (Bugsnag error, Datadog monitor, Linear ticket, Slack thread) when known,
+// otherwise by its normalized title.
+func AlertFingerprint(alert Alert) string {
+	source := strings.ToLower(alert.Source)
+	switch {
+	case source == "linear" && alert.LinearID != "":
+		return "linear:" + strings.ToUpper(alert.LinearID)
+	case source == "slack" && alert.SlackThread != "":
+		return "slack:" + alert.SlackThread
+	case alert.ID != "":
+		return source + ":" + alert.ID
+	default:
+		return source + ":" + titleKey(alert.Title)
+	}
+}
+
+var (
+	// Numbers, hex IDs and quoted values vary between occurrences of the same error
+	titleNoisePattern = regexp.MustCompile(`"[^"]*"|'[^']*'|\b[0-9a-f]{8,}\b|\d+`)
+	titleSplitPattern = regexp.MustCompile(`[^a-z]+`)
+)
+
+// titleKey normalizes an alert title for comparison
+func titleKey(title string) string {
+	key := titleNoisePattern.ReplaceAllString(strings.ToLower(title), " ")
+	return strings.Join(strings.Fields(titleSplitPattern.ReplaceAllString(key, " ")), " ")
+}
+
+var (
+	urgentSeverityPattern = regexp.MustCompile(`urgent|critical|\bp[01]\b|\bsev[- ]?[01]\b`)
+	highSeverityPattern   = regexp.MustCompile(`high|\bp2\b|\bsev[- ]?2\b`)
+	lowSeverityPattern    = regexp.MustCompile(`low|minor|\bp4\b|\bsev[- ]?4\b`)
+)
+
+// normalizeSeverity maps severities like "P1", "Critical" or "MEDIUM-HIGH"
+// onto urgent, high, medium or low
+func normalizeSeverity(raw string) string {
+	s := strings.ToLower(strings.TrimSpace(raw))
+	switch {
+	case urgentSeverityPattern.MatchString(s):
+		return "urgent"
+	case highSeverityPattern.MatchString(s):
+		return "high"
+	case lowSeverityPattern.MatchString(s):
+		return "low"
+	default:
+		return "medium"
+	}
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case "urgent":
+		return 3
+	case "high":
+		return 2
+	case "medium":
+		return 1
+	default:
+		return 0
+	}
+}
+
+func sourceLabel(source string) string {
+	switch strings.ToLower(source) {
+	case "bugsnag":
+		return "Bugsnag"
+	case "datadog":
+		return "Datadog"
+	case "linear":
+		return "Linear"
+	case "slack":
+		return "Slack"
+	default:
+		return source
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(values []string, value string) []string {
+	if value == "" || containsString(values, value) {
+		return values
+	}
+	return append(values, value)
+}
@@ -0,0 +1,262 @@
+package agent
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestIncidentStore(t *testing.T) *IncidentStore {
+	t.Helper()
+	store, err := NewIncidentStore(filepath.Join(t.TempDir(), "incidents.json"))
+	if err != nil {
+		t.Fatalf("NewIncidentStore() error = %v", err)
+	}
+	clock := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	store.now = func() time.Time {
+		clock = clock.Add(time.Minute)
+		return clock
+	}
+	return store
+}
+
+func TestIncidentStore_DedupesAlerts(t *testing.T) {
+	store := newTestIncidentStore(t)
+	alert := Alert{Source: "bugsnag", ID: "5f3a9c", Severity: "High", Title: "NoMethodError in CheckoutController"}
+
+	first, isNew, err := store.RecordAlert(alert, "s1", "m1")
+	if err != nil || !isNew {
+		t.Fatalf("RecordAlert() = new %v, error %v", isNew, err)
+	}
+	// The same report processed again is not a repeat of the alert
+	if _, _, err := store.RecordAlert(alert, "s1", "m1"); err != nil {
+		t.Fatalf("RecordAlert() error = %v", err)
+	}
+	second, isNew, err := store.RecordAlert(alert, "s1", "m2")
+	if err != nil || isNew {
+		t.Fatalf("RecordAlert() = new %v, error %v", isNew, err)
+	}
+
+	if second.ID != first.ID || len(store.List()) != 1 {
+		t.Fatalf("Expected one incident, got %d", len(store.List()))
+	}
+	if len(second.Alerts) != 1 || second.Alerts[0].Count != 2 {
+		t.Errorf("Expected one alert seen twice, got %+v", second.Alerts)
+	}
+	if second.Alerts[0].Fingerprint != "bugsnag:5f3a9c" || second.Severity != "high" {
+		t.Errorf("Unexpected fingerprint %q or severity %q", second.Alerts[0].Fingerprint, second.Severity)
+	}
+}
+
+func TestIncidentStore_GroupsRelatedAlerts(t *testing.T) {
+	store := newTestIncidentStore(t)
+
+	bugsnag, _, _ := store.RecordAlert(Alert{Source: "bugsnag", ID: "5f3a9c", Severity: "Medium", Title: "Timeout calling payments API (id 4821)"}, "s1", "m1")
+	datadog, _, _ := store.RecordAlert(Alert{Source: "datadog", ID: "99", Severity: "Critical", Title: "Timeout calling payments API (id 7733)"}, "s1", "m1")
+	ticket, _, _ := store.RecordAlert(Alert{Source: "linear", LinearID: "ENG-12", Title: "Payments are timing out"}, "s1", "m2")
+	ticketAgain, _, _ := store.RecordAlert(Alert{Source: "bugsnag", ID: "abc", LinearID: "eng-12", Title: "Payments gateway error"}, "s1", "m3")
+	otherTicket, _, _ := store.RecordAlert(Alert{Source: "linear", LinearID: "ENG-13", Title: "Payments are timing out"}, "s1", "m3")
+
+	if datadog.ID != bugsnag.ID {
+		t.Error("Expected alerts with the same title to be grouped")
+	}
+	if datadog.Severity != "urgent" {
+		t.Errorf("Expected severity to escalate to urgent, got %q", datadog.Severity)
+	}
+	if ticketAgain.ID != ticket.ID || len(ticketAgain.Alerts) != 2 {
+		t.Error("Expected alerts for the same Linear ticket to be grouped")
+	}
+	if otherTicket.ID == ticket.ID || ticket.ID == bugsnag.ID {
+		t.Error("Expected tickets to be matched only by ID")
+	}
+}
+
+func TestIncidentStore_TimelineAndReopen(t *testing.T) {
+	store := newTestIncidentStore(t)
+	inc, _, _ := store.RecordAlert(Alert{Source: "bugsnag", ID: "1", Title: "Crash on login"}, "s1", "m1")
+
+	updates := []IncidentUpdate{
+		{Status: IncidentStatusInvestigating, Note: "Checking the stack trace"},
+		{Status: IncidentStatusFixing, WorktreePath: "../worktrees/fix-login", BranchName: "fix/login"},
+		{PRNumber: "42", PRURL: "https://github.com/acme/app/pull/42"},
+		{Status: IncidentStatusResolved, Summary: "Nil session after logout"},
+	}
+	for _, u := range updates {
+		var err error
+		if inc, err = store.Update(inc.ID, u); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+
+	var kinds []string
+	for _, entry := range inc.Timeline {
+		kinds = append(kinds, entry.Kind)
+	}
+	want := "alert status worktree status pr summary status"
+	if strings.Join(kinds, " ") != want {
+		t.Errorf("Timeline kinds = %v, want %s", kinds, want)
+	}
+	inv := inc.Investigation
+	if inv == nil || inv.Status != "done" || inv.BranchName != "fix/login" || inv.PRNumber != "42" || inv.CompletedAt.IsZero() {
+		t.Errorf("Unexpected investigation: %+v", inv)
+	}
+
+	// The alert firing again reopens the incident
+	inc, _, _ = store.RecordAlert(Alert{Source: "bugsnag", ID: "1", Title: "Crash on login"}, "s2", "m9")
+	if inc.Status != IncidentStatusNew || !inc.ResolvedAt.IsZero() {
+		t.Errorf("Expected the incident to reopen, got status %q", inc.Status)
+	}
+	if last := inc.Timeline[len(inc.Timeline)-1]; !strings.Contains(last.Message, "Reopened") {
+		t.Errorf("Expected a reopen entry, got %q", last.Message)
+	}
+}
+
+func TestIncidentStore_PersistsAcrossRestarts(t *testing.T) {
+	store := newTestIncidentStore(t)
+	inc, _, _ := store.RecordAlert(Alert{Source: "datadog", ID: "77", Title: "High error rate"}, "s1", "m1")
+	store.Update(inc.ID, IncidentUpdate{Status: IncidentStatusInvestigating})
+
+	reloaded, err := NewIncidentStore(store.path)
+	if err != nil {
+		t.Fatalf("NewIncidentStore() error = %v", err)
+	}
+	got, ok := reloaded.FindByFingerprint("datadog:77")
+	if !ok || got.Status != IncidentStatusInvestigating || len(got.Timeline) != 2 {
+		t.Fatalf("Expected the incident to be reloaded, got %+v", got)
+	}
+	if reloaded.AlertCount() != 1 {
+		t.Errorf("AlertCount() = %d, want 1", reloaded.AlertCount())
+	}
+}
+
+func TestParseIncidentReport(t *testing.T) {
+	report := `## Check complete
+
+🎫 [High] Linear ticket ENG-123: Checkout fails for EU users (linear:ENG-123)
+🚨 NEW Critical: Error rate spike on payments (datadog:12345) https://app.datadoghq.com/monitors/12345
+🚨 NEW Low: Deprecation warning flood in worker logs
+🔧 Fixing (linear:ENG-123): worktree ../worktrees/fix-eu branch fix/eu-checkout
+🔗 PR (linear:ENG-123): https://github.com/acme/app/pull/88
+✅ Resolved (datadog:12345): Retry storm from the new client
+Linear queue: 3 tickets`
+
+	alerts, updates := parseIncidentReport(report)
+
+	if len(alerts) != 3 {
+		t.Fatalf("Expected 3 alerts, got %+v", alerts)
+	}
+	if a := alerts[0]; a.Source != "linear" || a.LinearID != "ENG-123" || a.Title != "Checkout fails for EU users" || a.Severity != "High" {
+		t.Errorf("Unexpected ticket alert: %+v", a)
+	}
+	if a := alerts[1]; a.Source != "datadog" || a.ID != "12345" || a.URL == "" || !strings.HasPrefix(a.Title, "Error rate spike on payments") {
+		t.Errorf("Unexpected monitoring alert: %+v", a)
+	}
+	if a := alerts[2]; a.Source != "datadog" || a.ID != "" {
+		t.Errorf("Unexpected unreferenced alert: %+v", a)
+	}
+
+	if len(updates) != 3 {
+		t.Fatalf("Expected 3 updates, got %+v", updates)
+	}
+	if u := updates[0]; u.fingerprint != "linear:ENG-123" || u.update.WorktreePath != "../worktrees/fix-eu" || u.update.BranchName != "fix/eu-checkout" {
+		t.Errorf("Unexpected worktree update: %+v", u)
+	}
+	if u := updates[1].update; u.PRNumber != "88" || u.PRURL != "https://github.com/acme/app/pull/88" {
+		t.Errorf("Unexpected PR update: %+v", u)
+	}
+	if u := updates[2]; u.fingerprint != "datadog:12345" || u.update.Status != IncidentStatusResolved || u.update.Summary != "Retry storm from the new client" {
+		t.Errorf("Unexpected resolution: %+v", u)
+	}
+}
+
+func TestRecordIncidentReports_ScansNewMessagesOnce(t *testing.T) {
+	useTempSessionsDir(t)
+	store := newTestIncidentStore(t)
+
+	start := time.Now()
+	session := NewSession("ff", "/path/to/project")
+	session.Mode = "firefighter"
+	session.Messages = []Message{
+		{ID: "m1", Role: "user", Content: "🚨 NEW High: not from the agent (bugsnag:zzz)", Timestamp: start},
+		{ID: "m2", Role: "assistant", Content: "🚨 NEW High: Null pointer in billing job (bugsnag:abc)", Timestamp: start.Add(time.Second)},
+	}
+
+	incidents, err := session.recordIncidentReports(store)
+	if err != nil || len(incidents) != 1 {
+		t.Fatalf("recordIncidentReports() = %d incidents, error %v", len(incidents), err)
+	}
+	if !incidents[0].FirstSeen.Equal(start.Add(time.Second)) {
+		t.Errorf("Expected the alert to be first seen when reported, got %v", incidents[0].FirstSeen)
+	}
+
+	// Rescanning finds nothing new
+	if incidents, _ := session.recordIncidentReports(store); len(incidents) != 0 {
+		t.Errorf("Expected no changes on rescan, got %+v", incidents)
+	}
+
+	session.Messages = append(session.Messages, Message{
+		ID: "m3", Role: "assistant", Timestamp: start.Add(2 * time.Second),
+		Content: "🔍 Investigating (bugsnag:abc): reading the job logs",
+	})
+	incidents, _ = session.recordIncidentReports(store)
+	if len(incidents) != 1 || incidents[0].Status != IncidentStatusInvestigating {
+		t.Fatalf("Expected the update to be applied, got %+v", incidents)
+	}
+	if got, _ := store.FindByFingerprint("bugsnag:abc"); got.Alerts[0].Count != 1 {
+		t.Errorf("Expected the alert to be counted once, got %d", got.Alerts[0].Count)
+	}
+}
+
+func TestBuildKnownIncidentsSection(t *testing.T) {
+	store := newTestIncidentStore(t)
+	if section := buildKnownIncidentsSection(store, time.Now()); section != "" {
+		t.Errorf("Expected no section for an empty store, got %q", section)
+	}
+
+	open, _, _ := store.RecordAlert(Alert{Source: "bugsnag", ID: "1", Title: "Crash on login"}, "s1", "")
+	old, _, _ := store.RecordAlert(Alert{Source: "datadog", ID: "2", Title: "Disk full"}, "s1", "")
+	store.Update(old.ID, IncidentUpdate{Status: IncidentStatusResolved})
+	store.Update(open.ID, IncidentUpdate{Status: IncidentStatusFixing})
+
+	section := buildKnownIncidentsSection(store, store.now().Add(8*24*time.Hour))
+	if !strings.Contains(section, "[fixing] Crash on login (bugsnag:1)") {
+		t.Errorf("Expected the open incident, got %q", section)
+	}
+	if strings.Contains(section, "Disk full") {
+		t.Errorf("Expected incidents resolved long ago to be left out, got %q", section)
+	}
+}
+
+func TestPostmortem(t *testing.T) {
+	store := newTestIncidentStore(t)
+	inc, _, _ := store.RecordAlert(Alert{Source: "bugsnag", ID: "1", Severity: "high", Title: "Crash | on login", URL: "https://bugsnag.com/e/1"}, "s1", "m1")
+	store.Update(inc.ID, IncidentUpdate{Status: IncidentStatusFixing, WorktreePath: "../wt/fix-login", BranchName: "fix/login"})
+	store.Update(inc.ID, IncidentUpdate{Status: IncidentStatusResolved, PRNumber: "42", PRURL: "https://github.com/acme/app/pull/42", Summary: "Nil session after logout"})
+
+	doc, err := store.Postmortem(inc.ID)
+	if err != nil {
+		t.Fatalf("Postmortem() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"# Postmortem: Crash | on login",
+		"## Summary\n\nNil session after logout",
+		"- **Severity:** high",
+		"(2m after first seen)",
+		"- **Alerts:** 1 Bugsnag",
+		`| Bugsnag | [Crash \| on login](https://bugsnag.com/e/1) | high |`,
+		"- **2026-03-02 09:02 UTC** Worktree ../wt/fix-login on branch fix/login",
+		"- **Branch:** `fix/login`",
+		"- **Pull request:** [#42](https://github.com/acme/app/pull/42)",
+		"## Root Cause\n\nTODO",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("Postmortem missing %q:\n%s", want, doc)
+		}
+	}
+
+	if _, err := store.Postmortem("missing"); err == nil {
+		t.Error("Expected an error for an unknown incident")
+	}
+}
@@ -27,6 +27,7 @@ type ConfigGetter interface {
 	GetAutoCleanupSessions() bool
 	GetMaxAgentsPerSession() int
 	GetKeepCompletedAgents() bool
+	GetAutoCompactThreshold() float64
 	GetMCPServerNames() []string
 }
 
@@ -39,6 +40,8 @@ type Manager struct {
 	defaultModel     string
 	authConfigGetter func() AuthConfig
 	configGetter     ConfigGetter
+	approvalServer   *ApprovalServer
+	approvalPolicy   ApprovalPolicy
 }
 
 // NewManager creates a new agent manager
@@ -96,6 +99,21 @@ func (m *Manager) SetConfigGetter(getter ConfigGetter) {
 	m.configGetter = getter
 }
 
+// SetApprovalServer sets the server that answers the Claude CLI's permission
+// prompts. Sessions created afterwards hold tool calls for approval.
+func (m *Manager) SetApprovalServer(server *ApprovalServer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.approvalServer = server
+}
+
+// SetApprovalPolicy sets the source of per-project approval modes and rules
+func (m *Manager) SetApprovalPolicy(policy ApprovalPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.approvalPolicy = policy
+}
+
 // GetConfigGetter returns the config getter
 func (m *Manager) GetConfigGetter() ConfigGetter {
 	m.mu.RLock()
@@ -124,12 +142,48 @@ func (m *Manager) CreateSession(projectPath string) (*Session, error) {
 		maxAgents := m.configGetter.GetMaxAgentsPerSession()
 		keepCompleted := m.configGetter.GetKeepCompletedAgents()
 		session.SetAgentCleanupSettings(maxAgents, keepCompleted)
+		session.SetCompactThreshold(m.configGetter.GetAutoCompactThreshold())
 	}
 
 	m.sessions[sessionID] = session
 	return session, nil
 }
 
+// ForkSession creates a session that continues sessionID from messageID (see
+// fork.go). The fork works in projectPath, or in the parent's project if
+// projectPath is empty.
+func (m *Manager) ForkSession(sessionID, messageID, projectPath string) (*Session, error) {
+	parent, err := m.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	fork, err := parent.Fork(uuid.New().String(), messageID, projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.setupSessionHandlers(fork, fork.ID)
+	if m.configGetter != nil {
+		maxMessages := m.configGetter.GetMaxMessagesPerSession()
+		archive := m.configGetter.GetArchiveOldMessages()
+		fork.SetTrimSettings(maxMessages, archive)
+
+		maxAgents := m.configGetter.GetMaxAgentsPerSession()
+		keepCompleted := m.configGetter.GetKeepCompletedAgents()
+		fork.SetAgentCleanupSettings(maxAgents, keepCompleted)
+		fork.SetCompactThreshold(m.configGetter.GetAutoCompactThreshold())
+	}
+	m.sessions[fork.ID] = fork
+	m.mu.Unlock()
+
+	if err := SaveSession(fork); err != nil {
+		fmt.Printf("Warning: failed to save fork %s of session %s: %v\n", fork.ID, sessionID, err)
+	}
+	return fork, nil
+}
+
 // CreateFirefighterSession creates a new firefighter agent session
 func (m *Manager) CreateFirefighterSession(projectPath string, scope string, slackChannels string) (*Session, error) {
 	m.mu.Lock()
@@ -163,6 +217,7 @@ func (m *Manager) CreateFirefighterSession(projectPath string, scope string, sla
 		maxAgents := m.configGetter.GetMaxAgentsPerSession()
 		keepCompleted := m.configGetter.GetKeepCompletedAgents()
 		session.SetAgentCleanupSettings(maxAgents, keepCompleted)
+		session.SetCompactThreshold(m.configGetter.GetAutoCompactThreshold())
 	}
 
 	m.sessions[sessionID] = session
@@ -202,6 +257,7 @@ func (m *Manager) CreateBoatmanModeSession(projectPath string, input string, mod
 		maxAgents := m.configGetter.GetMaxAgentsPerSession()
 		keepCompleted := m.configGetter.GetKeepCompletedAgents()
 		session.SetAgentCleanupSettings(maxAgents, keepCompleted)
+		session.SetCompactThreshold(m.configGetter.GetAutoCompactThreshold())
 	}
 
 	m.sessions[sessionID] = session
@@ -230,15 +286,47 @@ func (m *Manager) CreateTriageSession(projectPath string) (*Session, error) {
 		maxAgents := m.configGetter.GetMaxAgentsPerSession()
 		keepCompleted := m.configGetter.GetKeepCompletedAgents()
 		session.SetAgentCleanupSettings(maxAgents, keepCompleted)
+		session.SetCompactThreshold(m.configGetter.GetAutoCompactThreshold())
 	}
 
 	m.sessions[sessionID] = session
 	return session, nil
 }
 
+// recordIncidents records the alerts a firefighter session reported in the
+// incident store
+func (m *Manager) recordIncidents(session *Session) {
+	session.mu.RLock()
+	mode := session.Mode
+	session.mu.RUnlock()
+	if mode != "firefighter" {
+		return
+	}
+
+	store, err := OpenIncidentStore()
+	if err != nil {
+		fmt.Printf("[firefighter] Failed to open incident store: %v\n", err)
+		return
+	}
+	incidents, err := session.recordIncidentReports(store)
+	if err != nil {
+		fmt.Printf("[firefighter] Failed to record incidents: %v\n", err)
+	}
+	if m.wailsReady {
+		for _, incident := range incidents {
+			runtime.EventsEmit(m.ctx, "firefighter:incident", incident)
+		}
+	}
+}
+
 // setupSessionHandlers sets up event handlers for a session
 func (m *Manager) setupSessionHandlers(session *Session, sessionID string) {
 	session.SetMessageHandler(func(msg Message) {
+		// Searchable right away; written to the index when the session is saved
+		if index := loadedSearchIndex(); index != nil {
+			index.IndexMessage(sessionID, msg)
+		}
+
 		if m.wailsReady {
 			runtime.EventsEmit(m.ctx, "agent:message", map[string]interface{}{
 				"sessionId": sessionID,
@@ -263,18 +351,55 @@ func (m *Manager) setupSessionHandlers(session *Session, sessionID string) {
 				"status":    status,
 			})
 		}
+		if status == SessionStatusIdle {
+			// Status handlers may run with the session lock held
+			go m.recordIncidents(session)
+		}
+	})
+
+	session.SetContextHandler(func(usage ContextUsage) {
+		if m.wailsReady {
+			runtime.EventsEmit(m.ctx, "agent:context", usage)
+		}
+	})
+
+	session.SetIncidentHandler(func(incident Incident) {
+		if m.wailsReady {
+			runtime.EventsEmit(m.ctx, "firefighter:incident", incident)
+		}
+	})
+
+	session.SetApprovalHandler(func(action PendingAction) {
+		if m.wailsReady {
+			runtime.EventsEmit(m.ctx, "agent:approval", map[string]interface{}{
+				"sessionId": sessionID,
+				"action":    action,
+			})
+		}
 	})
+
+	// Caller holds m.mu
+	if m.approvalPolicy != nil {
+		session.SetApprovalPolicy(m.approvalPolicy)
+	}
+	if m.approvalServer != nil {
+		session.SetApprovalServer(m.approvalServer)
+	}
 }
 
-// GetSession returns a session by ID
+// GetSession returns a session by ID, reading its messages from disk if it
+// has not been used since it was loaded
 func (m *Manager) GetSession(sessionID string) (*Session, error) {
 	m.mu.RLock()
-	defer m.mu.RUnlock()
-
 	session, ok := m.sessions[sessionID]
+	m.mu.RUnlock()
+
 	if !ok {
 		return nil, fmt.Errorf("session not found: %s", sessionID)
 	}
+	if err := session.ensureMessagesLoaded(); err != nil {
+		return nil, err
+	}
 	return session, nil
 }
 
@@ -312,6 +437,7 @@ func (m *Manager) StartSession(sessionID string) error {
 		maxAgents := configGetter.GetMaxAgentsPerSession()
 		keepCompleted := configGetter.GetKeepCompletedAgents()
 		session.SetAgentCleanupSettings(maxAgents, keepCompleted)
+		session.SetCompactThreshold(configGetter.GetAutoCompactThreshold())
 	}
 
 	return session.Start(model)
@@ -338,6 +464,9 @@ func (m *Manager) DeleteSession(sessionID string) error {
 
 	session.Stop()
 	delete(m.sessions, sessionID)
+	if m.approvalServer != nil {
+		m.approvalServer.Unregister(sessionID)
+	}
 
 	// Remove persisted file from disk
 	if err := DeleteSessionFile(sessionID); err != nil {
@@ -354,7 +483,48 @@ func (m *Manager) SendMessage(sessionID, content string) error {
 		return err
 	}
 
-	// Get auth config
+	return session.SendMessage(content, m.sessionAuthConfig(session))
+}
+
+// ResumeSession continues a session that was interrupted by the app quitting
+// mid-run.
+func (m *Manager) ResumeSession(sessionID string) error {
+	session, err := m.GetSession(sessionID)
+	if err != nil {
+		return err
+	}
+
+	return session.ResumeInterrupted(m.sessionAuthConfig(session))
+}
+
+// sessionAuthConfig returns the auth config for running a session's agent
+func (m *Manager) sessionAuthConfig(session *Session) AuthConfig {
+	var authConfig AuthConfig
+	m.mu.RLock()
+	if m.authConfigGetter != nil {
+		authConfig = m.authConfigGetter()
+	}
+	policy := m.approvalPolicy
+	m.mu.RUnlock()
+
+	// Project preferences override the global approval mode
+	if policy != nil {
+		if mode := policy.GetProjectApprovalMode(session.ProjectPath); mode != "" {
+			authConfig.ApprovalMode = mode
+		}
+	}
+
+	return authConfig
+}
+
+// CompactSession summarizes a session's conversation and continues it in a
+// fresh context
+func (m *Manager) CompactSession(sessionID string) error {
+	session, err := m.GetSession(sessionID)
+	if err != nil {
+		return err
+	}
+
 	var authConfig AuthConfig
 	m.mu.RLock()
 	if m.authConfigGetter != nil {
@@ -362,7 +532,7 @@ func (m *Manager) SendMessage(sessionID, content string) error {
 	}
 	m.mu.RUnlock()
 
-	return session.SendMessage(content, authConfig)
+	return session.Compact(authConfig)
 }
 
 // ApproveAction approves a pending action
@@ -374,6 +544,25 @@ func (m *Manager) ApproveAction(sessionID, actionID string) error {
 	return session.Approve(actionID)
 }
 
+// ApproveActionAlways approves a pending action and remembers it as an
+// "always allow" rule for the session's project
+func (m *Manager) ApproveActionAlways(sessionID, actionID string) error {
+	session, err := m.GetSession(sessionID)
+	if err != nil {
+		return err
+	}
+	return session.ApproveAlways(actionID)
+}
+
+// GetPendingActions returns the actions waiting for approval in a session
+func (m *Manager) GetPendingActions(sessionID string) ([]PendingAction, error) {
+	session, err := m.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return session.GetPendingActions(), nil
+}
+
 // RejectAction rejects a pending action
 func (m *Manager) RejectAction(sessionID, actionID string) error {
 	session, err := m.GetSession(sessionID)
@@ -392,6 +581,20 @@ func (m *Manager) GetSessionMessages(sessionID string) ([]Message, error) {
 	return session.GetMessages(), nil
 }
 
+// GetSessionMessagesPage returns up to limit messages starting at offset and
+// the total message count, without reading the whole log of a session that
+// has not been used yet
+func (m *Manager) GetSessionMessagesPage(sessionID string, offset, limit int) ([]Message, int, error) {
+	m.mu.RLock()
+	session, ok := m.sessions[sessionID]
+	m.mu.RUnlock()
+
+	if !ok {
+		return nil, 0, fmt.Errorf("session not found: %s", sessionID)
+	}
+	return session.GetMessagesPage(offset, limit)
+}
+
 // GetSessionTasks returns tasks for a session
 func (m *Manager) GetSessionTasks(sessionID string) ([]Task, error) {
 	session, err := m.GetSession(sessionID)
@@ -412,9 +615,10 @@ func (m *Manager) StopAllSessions() {
 }
 
 // LoadPersistedSessions loads all sessions from disk and registers them in the manager.
+// Only metadata is read; each session's messages are read when it is first used.
 // Should be called once during startup, after SetContext/SetWailsReady/SetConfigGetter.
 func (m *Manager) LoadPersistedSessions() error {
-	sessions, err := LoadAllSessions()
+	sessions, err := LoadAllSessionsLazy()
 	if err != nil {
 		return fmt.Errorf("failed to load persisted sessions: %w", err)
 	}
@@ -435,9 +639,20 @@ func (m *Manager) LoadPersistedSessions() error {
 			maxAgents := m.configGetter.GetMaxAgentsPerSession()
 			keepCompleted := m.configGetter.GetKeepCompletedAgents()
 			session.SetAgentCleanupSettings(maxAgents, keepCompleted)
+			session.SetCompactThreshold(m.configGetter.GetAutoCompactThreshold())
 		}
 
 		m.sessions[session.ID] = session
+
+		// Stop whatever an interrupted session left running in the background
+		// so startup is not held up waiting for it to exit
+		if session.IsInterrupted() {
+			go func(session *Session) {
+				if err := session.StopOrphanedProcess(); err != nil {
+					fmt.Printf("Warning: failed to stop orphaned process for session %s: %v\n", session.ID, err)
+				}
+			}(session)
+		}
 	}
 
 	return nil
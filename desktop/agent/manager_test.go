@@ -475,9 +475,9 @@ func TestApproveAction(t *testing.T) {
 		}
 
 		err = m.ApproveAction(session.ID, "action-123")
-		// Expected to fail as approval is not supported in current implementation
+		// Expected to fail as no action is pending
 		if err == nil {
-			t.Error("expected error as approval is not supported")
+			t.Error("expected error for unknown action")
 		}
 	})
 }
@@ -501,9 +501,9 @@ func TestRejectAction(t *testing.T) {
 		}
 
 		err = m.RejectAction(session.ID, "action-123")
-		// Expected to fail as rejection is not supported in current implementation
+		// Expected to fail as no action is pending
 		if err == nil {
-			t.Error("expected error as rejection is not supported")
+			t.Error("expected error for unknown action")
 		}
 	})
 }
@@ -0,0 +1,346 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// logIndexStride is how many lines apart the log's offset index entries are
+	logIndexStride = 64
+
+	// logCompactMinSkip is how many trimmed lines a log must carry before it
+	// is compacted; it is also compacted only once they outnumber live lines
+	logCompactMinSkip = 256
+)
+
+// MessageLogInfo locates a session's committed messages in its log file. The
+// log holds one JSON message per line; lines[Skip:Lines] are the session's
+// messages in order. Bytes past Size are an incomplete write and are ignored.
+type MessageLogInfo struct {
+	File       string  `json:"file"`       // Log file name in the sessions directory
+	Generation int     `json:"generation"` // Incremented each time the log is rewritten
+	Skip       int     `json:"skip"`       // Leading lines dropped by trimming or clearing
+	Lines      int     `json:"lines"`      // Committed lines
+	Size       int64   `json:"size"`       // Committed bytes
+	Index      []int64 `json:"index"`      // Byte offset of every logIndexStride-th line
+}
+
+// MessageCount returns the number of live messages in the log
+func (l *MessageLogInfo) MessageCount() int {
+	return l.Lines - l.Skip
+}
+
+// writeMessageLog brings the session's log in line with messages. New
+// messages are appended; if earlier messages changed, or trimmed lines
+// dominate the log, it is rewritten under a new file name. It returns the name
+// of a log file that becomes obsolete once the header is written.
+// Note: This method expects the caller to hold s.saveMu
+func (s *Session) writeMessageLog(sessionsDir string, messages []Message) (string, error) {
+	lines := make([][]byte, len(messages))
+	ids := make([]string, len(messages))
+	hashes := make([]uint64, len(messages))
+	for i, msg := range messages {
+		line, err := json.Marshal(msg)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal message %s: %w", msg.ID, err)
+		}
+		lines[i] = line
+		ids[i] = msg.ID
+		hashes[i] = hashLine(line)
+	}
+
+	drop, appendFrom, ok := s.planLogAppend(ids, hashes)
+	if ok {
+		err := s.appendMessageLog(sessionsDir, drop, lines[appendFrom:])
+		if err == nil {
+			s.savedIDs = ids
+			s.savedHashes = hashes
+			if s.log.Skip < logCompactMinSkip || s.log.Skip < s.log.MessageCount() {
+				return "", nil
+			}
+		} else if !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+
+	return s.rewriteMessageLog(sessionsDir, lines, ids, hashes)
+}
+
+// planLogAppend compares messages with what the log already holds. It
+// returns how many leading logged messages were dropped and the index of the
+// first message to append, or ok=false if the log must be rewritten.
+// Note: This method expects the caller to hold s.saveMu
+func (s *Session) planLogAppend(ids []string, hashes []uint64) (drop, appendFrom int, ok bool) {
+	if s.log == nil || len(s.savedIDs) != s.log.MessageCount() {
+		return 0, 0, false
+	}
+
+	saved := s.savedIDs
+	if len(saved) == 0 {
+		return 0, 0, true
+	}
+	if len(ids) == 0 {
+		return len(saved), 0, true
+	}
+
+	// Messages are only ever dropped from the front
+	drop = len(saved)
+	for i, id := range saved {
+		if id == ids[0] {
+			drop = i
+			break
+		}
+	}
+
+	kept := len(saved) - drop
+	if kept > len(ids) {
+		return 0, 0, false
+	}
+	for j := 0; j < kept; j++ {
+		if ids[j] != saved[drop+j] || hashes[j] != s.savedHashes[drop+j] {
+			return 0, 0, false
+		}
+	}
+	return drop, kept, true
+}
+
+// appendMessageLog appends lines to the log and records drop leading lines
+// as skipped. Anything past the committed size is discarded first.
+// Note: This method expects the caller to hold s.saveMu
+func (s *Session) appendMessageLog(sessionsDir string, drop int, lines [][]byte) error {
+	logInfo := *s.log
+	logInfo.Skip += drop
+	logInfo.Index = append([]int64(nil), s.log.Index...)
+
+	if len(lines) > 0 {
+		f, err := os.OpenFile(filepath.Join(sessionsDir, logInfo.File), os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		if err := f.Truncate(logInfo.Size); err != nil {
+			return fmt.Errorf("failed to truncate session log: %w", err)
+		}
+
+		var buf bytes.Buffer
+		for _, line := range lines {
+			if logInfo.Lines%logIndexStride == 0 {
+				logInfo.Index = append(logInfo.Index, logInfo.Size+int64(buf.Len()))
+			}
+			buf.Write(line)
+			buf.WriteByte('\n')
+			logInfo.Lines++
+		}
+
+		if _, err := f.WriteAt(buf.Bytes(), logInfo.Size); err != nil {
+			return fmt.Errorf("failed to append to session log: %w", err)
+		}
+		if err := f.Sync(); err != nil {
+			return fmt.Errorf("failed to sync session log: %w", err)
+		}
+		logInfo.Size += int64(buf.Len())
+	}
+
+	s.log = &logInfo
+	return nil
+}
+
+// rewriteMessageLog writes all lines to a new log file
+// Note: This method expects the caller to hold s.saveMu
+func (s *Session) rewriteMessageLog(sessionsDir string, lines [][]byte, ids []string, hashes []uint64) (string, error) {
+	logInfo := MessageLogInfo{Generation: 1}
+	var obsolete string
+	if s.log != nil {
+		logInfo.Generation = s.log.Generation + 1
+		obsolete = s.log.File
+	}
+	logInfo.File = fmt.Sprintf("%s.%d.log", s.ID, logInfo.Generation)
+
+	var buf bytes.Buffer
+	for _, line := range lines {
+		if logInfo.Lines%logIndexStride == 0 {
+			logInfo.Index = append(logInfo.Index, int64(buf.Len()))
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+		logInfo.Lines++
+	}
+	logInfo.Size = int64(buf.Len())
+
+	if err := writeFileAtomic(filepath.Join(sessionsDir, logInfo.File), buf.Bytes()); err != nil {
+		return "", fmt.Errorf("failed to write session log: %w", err)
+	}
+
+	s.log = &logInfo
+	s.savedIDs = ids
+	s.savedHashes = hashes
+	return obsolete, nil
+}
+
+// ensureMessagesLoaded reads a persisted session's messages on first use
+func (s *Session) ensureMessagesLoaded() error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	s.mu.RLock()
+	onDisk := s.messagesOnDisk
+	s.mu.RUnlock()
+	if !onDisk {
+		return nil
+	}
+
+	sessionsDir, err := GetSessionsDir()
+	if err != nil {
+		return fmt.Errorf("failed to get sessions directory: %w", err)
+	}
+
+	messages, err := readMessageLog(sessionsDir, s.log, 0, s.log.MessageCount())
+	if err != nil {
+		return fmt.Errorf("failed to load messages for session %s: %w", s.ID, err)
+	}
+
+	ids := make([]string, len(messages))
+	hashes := make([]uint64, len(messages))
+	for i, msg := range messages {
+		line, _ := json.Marshal(msg)
+		ids[i] = msg.ID
+		hashes[i] = hashLine(line)
+	}
+	s.savedIDs = ids
+	s.savedHashes = hashes
+
+	s.mu.Lock()
+	s.Messages = append(messages, s.Messages...)
+	s.messagesOnDisk = false
+	s.mu.Unlock()
+
+	return nil
+}
+
+// GetMessagesPage returns up to limit messages starting at offset, plus the
+// total message count. Messages of a session that has not been used yet are
+// read from disk without loading the rest of the log.
+func (s *Session) GetMessagesPage(offset, limit int) ([]Message, int, error) {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	s.mu.RLock()
+	if !s.messagesOnDisk {
+		defer s.mu.RUnlock()
+		total := len(s.Messages)
+		start, end := pageBounds(offset, limit, total)
+		page := make([]Message, end-start)
+		copy(page, s.Messages[start:end])
+		return page, total, nil
+	}
+	s.mu.RUnlock()
+
+	total := s.log.MessageCount()
+	start, end := pageBounds(offset, limit, total)
+
+	sessionsDir, err := GetSessionsDir()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get sessions directory: %w", err)
+	}
+	page, err := readMessageLog(sessionsDir, s.log, start, end)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read messages for session %s: %w", s.ID, err)
+	}
+	return page, total, nil
+}
+
+// pageBounds clamps a page to [0, total)
+func pageBounds(offset, limit, total int) (int, int) {
+	start := offset
+	if start < 0 {
+		start = 0
+	}
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if limit < 0 || end > total {
+		end = total
+	}
+	return start, end
+}
+
+// readMessageLog reads live messages [start, end) from a log. It seeks to the
+// nearest index entry and decodes only the requested lines.
+func readMessageLog(sessionsDir string, logInfo *MessageLogInfo, start, end int) ([]Message, error) {
+	messages := []Message{}
+	if end <= start {
+		return messages, nil
+	}
+
+	f, err := os.Open(filepath.Join(sessionsDir, logInfo.File))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	first := logInfo.Skip + start
+	entry := first / logIndexStride
+	if entry >= len(logInfo.Index) {
+		return nil, fmt.Errorf("log index is missing line %d", first)
+	}
+	offset := logInfo.Index[entry]
+
+	reader := bufio.NewReader(io.NewSectionReader(f, offset, logInfo.Size-offset))
+	for line := entry * logIndexStride; line < logInfo.Skip+end; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err != nil {
+			return nil, fmt.Errorf("log ended at line %d: %w", line, err)
+		}
+		if line < first {
+			continue
+		}
+
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("failed to decode log line %d: %w", line, err)
+		}
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+// writeFileAtomic replaces a file by writing a synced temporary file and
+// renaming it into place
+func writeFileAtomic(filename string, data []byte) error {
+	tmp := filename + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+func hashLine(line []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(line)
+	return h.Sum64()
+}
@@ -0,0 +1,322 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// useTempSessionsDir points session persistence at a temporary directory
+func useTempSessionsDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	originalGetter := defaultSessionsDirGetter
+	t.Cleanup(func() { defaultSessionsDirGetter = originalGetter })
+	defaultSessionsDirGetter = func() (string, error) {
+		return dir, nil
+	}
+	return dir
+}
+
+func addTestMessages(s *Session, from, to int) {
+	for i := from; i < to; i++ {
+		s.Messages = append(s.Messages, Message{
+			ID:        fmt.Sprintf("msg-%03d", i),
+			Role:      "user",
+			Content:   fmt.Sprintf("message %d", i),
+			Timestamp: time.Date(2026, 1, 1, 0, 0, i, 0, time.UTC),
+		})
+	}
+}
+
+func readHeader(t *testing.T, dir, sessionID string) (SessionData, map[string]json.RawMessage) {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join(dir, sessionID+".json"))
+	if err != nil {
+		t.Fatalf("Failed to read header: %v", err)
+	}
+	var data SessionData
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &data); err != nil {
+		t.Fatalf("Failed to unmarshal header: %v", err)
+	}
+	json.Unmarshal(raw, &fields)
+	return data, fields
+}
+
+func TestSaveSession_AppendsToLog(t *testing.T) {
+	dir := useTempSessionsDir(t)
+
+	session := NewSession("append-session", "/tmp/test")
+	addTestMessages(session, 0, 3)
+	if err := SaveSession(session); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+
+	header, fields := readHeader(t, dir, session.ID)
+	if _, ok := fields["messages"]; ok {
+		t.Error("Header should not contain messages")
+	}
+	if header.Log == nil || header.Log.Lines != 3 || header.Log.Generation != 1 {
+		t.Fatalf("Unexpected log info: %+v", header.Log)
+	}
+	firstSize := header.Log.Size
+
+	addTestMessages(session, 3, 5)
+	if err := SaveSession(session); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+
+	header, _ = readHeader(t, dir, session.ID)
+	if header.Log.Generation != 1 || header.Log.Lines != 5 {
+		t.Fatalf("Expected appended log, got %+v", header.Log)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, header.Log.File))
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+	if int64(len(data)) != header.Log.Size || header.Log.Size <= firstSize {
+		t.Errorf("Log size %d does not match header %d", len(data), header.Log.Size)
+	}
+	if n := strings.Count(string(data), "\n"); n != 5 {
+		t.Errorf("Expected 5 log lines, got %d", n)
+	}
+}
+
+func TestSaveSession_RewritesChangedMessages(t *testing.T) {
+	dir := useTempSessionsDir(t)
+
+	session := NewSession("rewrite-session", "/tmp/test")
+	addTestMessages(session, 0, 3)
+	if err := SaveSession(session); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+	oldLog := session.log.File
+
+	session.Messages[1].Content = "edited"
+	if err := SaveSession(session); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+
+	header, _ := readHeader(t, dir, session.ID)
+	if header.Log.Generation != 2 || header.Log.Lines != 3 {
+		t.Fatalf("Expected rewritten log, got %+v", header.Log)
+	}
+	if _, err := os.Stat(filepath.Join(dir, oldLog)); !os.IsNotExist(err) {
+		t.Error("Old log should be removed after rewrite")
+	}
+
+	loaded, err := LoadSession(session.ID)
+	if err != nil {
+		t.Fatalf("LoadSession() error = %v", err)
+	}
+	if loaded.Messages[1].Content != "edited" {
+		t.Errorf("Expected edited message, got %q", loaded.Messages[1].Content)
+	}
+}
+
+func TestSaveSession_TrimAndCompact(t *testing.T) {
+	dir := useTempSessionsDir(t)
+
+	session := NewSession("trim-session", "/tmp/test")
+	addTestMessages(session, 0, 300)
+	if err := SaveSession(session); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+
+	// Dropping from the front only advances Skip
+	session.Messages = session.Messages[100:]
+	if err := SaveSession(session); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+	header, _ := readHeader(t, dir, session.ID)
+	if header.Log.Generation != 1 || header.Log.Skip != 100 || header.Log.MessageCount() != 200 {
+		t.Fatalf("Expected skipped lines, got skip=%d lines=%d gen=%d", header.Log.Skip, header.Log.Lines, header.Log.Generation)
+	}
+
+	loaded, err := LoadSession(session.ID)
+	if err != nil {
+		t.Fatalf("LoadSession() error = %v", err)
+	}
+	if len(loaded.Messages) != 200 || loaded.Messages[0].ID != "msg-100" {
+		t.Fatalf("Unexpected messages after trim: %d, first %s", len(loaded.Messages), loaded.Messages[0].ID)
+	}
+
+	// Once skipped lines dominate, the log is compacted
+	session.Messages = session.Messages[180:]
+	addTestMessages(session, 300, 310)
+	if err := SaveSession(session); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+	header, _ = readHeader(t, dir, session.ID)
+	if header.Log.Generation != 2 || header.Log.Skip != 0 || header.Log.Lines != 30 {
+		t.Fatalf("Expected compacted log, got skip=%d lines=%d gen=%d", header.Log.Skip, header.Log.Lines, header.Log.Generation)
+	}
+
+	loaded, err = LoadSession(session.ID)
+	if err != nil {
+		t.Fatalf("LoadSession() error = %v", err)
+	}
+	if len(loaded.Messages) != 30 || loaded.Messages[0].ID != "msg-280" || loaded.Messages[29].ID != "msg-309" {
+		t.Errorf("Unexpected messages after compaction: %d", len(loaded.Messages))
+	}
+}
+
+func TestSaveSession_ClearConversation(t *testing.T) {
+	useTempSessionsDir(t)
+
+	session := NewSession("clear-session", "/tmp/test")
+	addTestMessages(session, 0, 5)
+	if err := SaveSession(session); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+
+	session.Messages = []Message{}
+	addTestMessages(session, 5, 6)
+	if err := SaveSession(session); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+
+	loaded, err := LoadSession(session.ID)
+	if err != nil {
+		t.Fatalf("LoadSession() error = %v", err)
+	}
+	if len(loaded.Messages) != 1 || loaded.Messages[0].ID != "msg-005" {
+		t.Errorf("Expected only the new message, got %v", loaded.Messages)
+	}
+}
+
+func TestLoadSession_IgnoresIncompleteWrite(t *testing.T) {
+	dir := useTempSessionsDir(t)
+
+	session := NewSession("crash-session", "/tmp/test")
+	addTestMessages(session, 0, 2)
+	if err := SaveSession(session); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+
+	// Simulate a crash after a partial append, before the header was updated
+	f, err := os.OpenFile(filepath.Join(dir, session.log.File), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open log: %v", err)
+	}
+	f.WriteString(`{"id":"msg-partial","role":"us`)
+	f.Close()
+
+	loaded, err := LoadSession(session.ID)
+	if err != nil {
+		t.Fatalf("LoadSession() error = %v", err)
+	}
+	if len(loaded.Messages) != 2 {
+		t.Fatalf("Expected 2 committed messages, got %d", len(loaded.Messages))
+	}
+
+	// The next save overwrites the partial bytes
+	addTestMessages(loaded, 2, 3)
+	if err := SaveSession(loaded); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+	reloaded, err := LoadSession(session.ID)
+	if err != nil {
+		t.Fatalf("LoadSession() error = %v", err)
+	}
+	if len(reloaded.Messages) != 3 || reloaded.Messages[2].ID != "msg-002" {
+		t.Errorf("Unexpected messages after recovery: %v", reloaded.Messages)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, reloaded.log.File))
+	if strings.Contains(string(data), "msg-partial") {
+		t.Error("Partial write should have been truncated")
+	}
+}
+
+func TestLoadSession_MigratesLegacyFile(t *testing.T) {
+	dir := useTempSessionsDir(t)
+
+	legacy := SessionData{
+		ID:          "legacy-session",
+		ProjectPath: "/tmp/legacy",
+		Status:      SessionStatusIdle,
+		Messages: []Message{
+			{ID: "msg-1", Role: "user", Content: "Hello"},
+			{ID: "msg-2", Role: "assistant", Content: "Hi there!"},
+		},
+		Tasks:     []Task{},
+		CreatedAt: "2026-01-01T00:00:00Z",
+		UpdatedAt: "2026-01-02T00:00:00Z",
+		Model:     "sonnet",
+	}
+	raw, _ := json.MarshalIndent(legacy, "", "  ")
+	if err := os.WriteFile(filepath.Join(dir, legacy.ID+".json"), raw, 0644); err != nil {
+		t.Fatalf("Failed to write legacy session: %v", err)
+	}
+
+	loaded, err := LoadSession(legacy.ID)
+	if err != nil {
+		t.Fatalf("LoadSession() error = %v", err)
+	}
+	if len(loaded.Messages) != 2 || loaded.Messages[1].Content != "Hi there!" {
+		t.Fatalf("Unexpected messages: %v", loaded.Messages)
+	}
+
+	header, fields := readHeader(t, dir, legacy.ID)
+	if _, ok := fields["messages"]; ok {
+		t.Error("Migrated header should not contain messages")
+	}
+	if header.Log == nil || header.Log.MessageCount() != 2 {
+		t.Fatalf("Expected migrated log, got %+v", header.Log)
+	}
+	if header.UpdatedAt != legacy.UpdatedAt {
+		t.Errorf("UpdatedAt changed during migration: %s", header.UpdatedAt)
+	}
+}
+
+func TestLazySession_ReadsOnlyRequestedPage(t *testing.T) {
+	useTempSessionsDir(t)
+
+	session := NewSession("lazy-session", "/tmp/test")
+	addTestMessages(session, 0, 150)
+	if err := SaveSession(session); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+
+	sessions, err := LoadAllSessionsLazy()
+	if err != nil || len(sessions) != 1 {
+		t.Fatalf("LoadAllSessionsLazy() = %d sessions, err %v", len(sessions), err)
+	}
+	lazy := sessions[0]
+	if !lazy.messagesOnDisk || len(lazy.Messages) != 0 {
+		t.Fatal("Lazily loaded session should not hold messages")
+	}
+
+	page, total, err := lazy.GetMessagesPage(130, 50)
+	if err != nil {
+		t.Fatalf("GetMessagesPage() error = %v", err)
+	}
+	if total != 150 || len(page) != 20 || page[0].ID != "msg-130" || page[19].ID != "msg-149" {
+		t.Fatalf("Unexpected page: total=%d len=%d", total, len(page))
+	}
+	if !lazy.messagesOnDisk {
+		t.Error("Reading a page should not load the whole session")
+	}
+
+	// Saving an unopened session keeps its log
+	lazy.AddTag("kept")
+	if err := SaveSession(lazy); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+
+	messages := lazy.GetMessages()
+	if len(messages) != 150 || messages[64].ID != "msg-064" {
+		t.Fatalf("Expected all messages after first use, got %d", len(messages))
+	}
+
+	page, total, err = lazy.GetMessagesPage(100, 10)
+	if err != nil || total != 150 || len(page) != 10 || page[0].ID != "msg-100" {
+		t.Errorf("Unexpected in-memory page: total=%d len=%d err=%v", total, len(page), err)
+	}
+}
@@ -9,24 +9,41 @@ import (
 	"time"
 )
 
-// SessionData represents the persistable data of a session
+// SessionData represents the persistable data of a session. It is written as
+// the session's metadata header; messages live in a separate append-only log
+// described by Log, and run events in another (see runlog.go). Files written
+// before the logs existed have no Log and keep their messages and events
+// inline.
 type SessionData struct {
-	ID              string                 `json:"id"`
-	ProjectPath     string                 `json:"projectPath"`
-	Status          SessionStatus          `json:"status"`
-	Messages        []Message              `json:"messages"`
-	Tasks           []Task                 `json:"tasks"`
-	CreatedAt       string                 `json:"createdAt"`
-	UpdatedAt       string                 `json:"updatedAt"`
-	Model           string                 `json:"model"`
-	ConversationID  string                 `json:"conversationId"`
-	CurrentAgentID  string                 `json:"currentAgentId"`
-	Agents          map[string]*AgentInfo  `json:"agents"`
-	Tags            []string               `json:"tags,omitempty"`
-	IsFavorite      bool                   `json:"isFavorite,omitempty"`
-	Mode            string                 `json:"mode,omitempty"`
-	ModeConfig      map[string]interface{} `json:"modeConfig,omitempty"`
-	ReasoningEffort string                 `json:"reasoningEffort,omitempty"`
+	ID               string                 `json:"id"`
+	ProjectPath      string                 `json:"projectPath"`
+	Status           SessionStatus          `json:"status"`
+	Messages         []Message              `json:"messages,omitempty"`
+	Tasks            []Task                 `json:"tasks"`
+	CreatedAt        string                 `json:"createdAt"`
+	UpdatedAt        string                 `json:"updatedAt"`
+	Model            string                 `json:"model"`
+	ConversationID   string                 `json:"conversationId"`
+	CurrentAgentID   string                 `json:"currentAgentId"`
+	Agents           map[string]*AgentInfo  `json:"agents"`
+	Tags             []string               `json:"tags,omitempty"`
+	IsFavorite       bool                   `json:"isFavorite,omitempty"`
+	Mode             string                 `json:"mode,omitempty"`
+	ModeConfig       map[string]interface{} `json:"modeConfig,omitempty"`
+	ReasoningEffort  string                 `json:"reasoningEffort,omitempty"`
+	Log              *MessageLogInfo        `json:"log,omitempty"`
+	ContextTokens    int                    `json:"contextTokens,omitempty"`
+	ContextWindow    int                    `json:"contextWindow,omitempty"`
+	Compactions      int                    `json:"compactions,omitempty"`
+	CompactSummary   string                 `json:"compactSummary,omitempty"`
+	ProcessPID       int                    `json:"processPid,omitempty"`
+	ProcessCommand   string                 `json:"processCommand,omitempty"`
+	ParentID         string                 `json:"parentId,omitempty"`
+	ForkedFrom       string                 `json:"forkedFrom,omitempty"`
+	ForkConversation bool                   `json:"forkConversation,omitempty"`
+	ReadOnly         bool                   `json:"readOnly,omitempty"`
+	EventCount       int                    `json:"eventCount,omitempty"`
+	Events           []RunEvent             `json:"events,omitempty"`
 }
 
 // SessionsDirGetter is a function type for getting sessions directory (for testing)
@@ -56,53 +73,112 @@ func getSessionsDir() (string, error) {
 	return sessionsDir, nil
 }
 
-// SaveSession persists a session to disk
+// SaveSession persists a session to disk. Messages added since the last save
+// are appended to the session's log and the metadata header is replaced
+// atomically, so a crash mid-save leaves the previous state intact.
 func SaveSession(session *Session) error {
 	sessionsDir, err := GetSessionsDir()
 	if err != nil {
 		return fmt.Errorf("failed to get sessions directory: %w", err)
 	}
 
-	session.mu.RLock()
-	defer session.mu.RUnlock()
-
-	// Convert to persistable format
-	data := SessionData{
-		ID:              session.ID,
-		ProjectPath:     session.ProjectPath,
-		Status:          session.Status,
-		Messages:        session.Messages,
-		Tasks:           session.Tasks,
-		CreatedAt:       session.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt:       session.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		Model:           session.Model,
-		ConversationID:  session.conversationID,
-		CurrentAgentID:  session.currentAgentID,
-		Agents:          session.agents,
-		Tags:            session.Tags,
-		IsFavorite:      session.IsFavorite,
-		Mode:            session.Mode,
-		ModeConfig:      session.ModeConfig,
-		ReasoningEffort: session.ReasoningEffort,
-	}
-
-	// Marshal to JSON
+	session.saveMu.Lock()
+	defer session.saveMu.Unlock()
+
+	session.mu.Lock()
+	data := session.sessionData()
+	loaded := !session.messagesOnDisk
+	var messages []Message
+	if loaded {
+		messages = make([]Message, len(session.Messages))
+		copy(messages, session.Messages)
+	}
+	events := session.events
+	session.events = nil
+	session.mu.Unlock()
+
+	if err := session.appendEventLog(sessionsDir, events); err != nil {
+		session.mu.Lock()
+		session.events = append(events, session.events...)
+		session.mu.Unlock()
+		return err
+	}
+	data.EventCount = session.savedEvents
+
+	// Messages still on disk have not changed since they were loaded
+	var obsoleteLog string
+	if loaded {
+		obsoleteLog, err = session.writeMessageLog(sessionsDir, messages)
+		if err != nil {
+			return err
+		}
+	}
+	if session.log != nil {
+		logInfo := *session.log
+		data.Log = &logInfo
+	}
+
 	jsonData, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal session: %w", err)
 	}
 
-	// Write to file
 	filename := filepath.Join(sessionsDir, session.ID+".json")
-	if err := os.WriteFile(filename, jsonData, 0644); err != nil {
+	if err := writeFileAtomic(filename, jsonData); err != nil {
 		return fmt.Errorf("failed to write session file: %w", err)
 	}
 
+	// The header no longer references the pre-compaction log
+	if obsoleteLog != "" {
+		os.Remove(filepath.Join(sessionsDir, obsoleteLog))
+	}
+
+	indexSavedSession(data, messages, loaded)
+
 	return nil
 }
 
-// LoadSession loads a session from disk
+// sessionData returns the session's metadata for the header.
+// Note: This method expects the caller to hold s.mu lock
+func (s *Session) sessionData() SessionData {
+	return SessionData{
+		ID:               s.ID,
+		ProjectPath:      s.ProjectPath,
+		Status:           s.Status,
+		Tasks:            s.Tasks,
+		CreatedAt:        s.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:        s.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Model:            s.Model,
+		ConversationID:   s.conversationID,
+		CurrentAgentID:   s.currentAgentID,
+		Agents:           s.agents,
+		Tags:             s.Tags,
+		IsFavorite:       s.IsFavorite,
+		Mode:             s.Mode,
+		ModeConfig:       s.ModeConfig,
+		ReasoningEffort:  s.ReasoningEffort,
+		ContextTokens:    s.contextTokens,
+		ContextWindow:    s.contextWindow,
+		Compactions:      s.compactions,
+		CompactSummary:   s.compactSummary,
+		ProcessPID:       s.processPID,
+		ProcessCommand:   s.processCommand,
+		ParentID:         s.ParentID,
+		ForkedFrom:       s.ForkedFrom,
+		ForkConversation: s.forkConversation,
+		ReadOnly:         s.ReadOnly,
+	}
+}
+
+// LoadSession loads a session and all of its messages from disk
 func LoadSession(sessionID string) (*Session, error) {
+	return loadSession(sessionID, true)
+}
+
+// loadSession loads a session's metadata header. With withMessages the message
+// log is read as well; otherwise messages are read on first use. Sessions in
+// the old single-file format are migrated to the log format.
+func loadSession(sessionID string, withMessages bool) (*Session, error) {
 	sessionsDir, err := GetSessionsDir()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sessions directory: %w", err)
@@ -121,20 +197,38 @@ func LoadSession(sessionID string) (*Session, error) {
 
 	// Create session from persisted data
 	session := &Session{
-		ID:              data.ID,
-		ProjectPath:     data.ProjectPath,
-		Status:          data.Status,
-		Messages:        data.Messages,
-		Tasks:           data.Tasks,
-		Model:           data.Model,
-		ReasoningEffort: data.ReasoningEffort,
-		Mode:            data.Mode,
-		ModeConfig:      data.ModeConfig,
-		conversationID:  data.ConversationID,
-		currentAgentID:  data.CurrentAgentID,
-		agents:          data.Agents,
-		Tags:            data.Tags,
-		IsFavorite:      data.IsFavorite,
+		ID:               data.ID,
+		ProjectPath:      data.ProjectPath,
+		Status:           data.Status,
+		Messages:         data.Messages,
+		Tasks:            data.Tasks,
+		Model:            data.Model,
+		ReasoningEffort:  data.ReasoningEffort,
+		Mode:             data.Mode,
+		ModeConfig:       data.ModeConfig,
+		conversationID:   data.ConversationID,
+		currentAgentID:   data.CurrentAgentID,
+		agents:           data.Agents,
+		Tags:             data.Tags,
+		IsFavorite:       data.IsFavorite,
+		log:              data.Log,
+		messagesOnDisk:   data.Log != nil,
+		contextTokens:    data.ContextTokens,
+		contextWindow:    data.ContextWindow,
+		compactions:      data.Compactions,
+		compactSummary:   data.CompactSummary,
+		processPID:       data.ProcessPID,
+		processCommand:   data.ProcessCommand,
+		ParentID:         data.ParentID,
+		ForkedFrom:       data.ForkedFrom,
+		forkConversation: data.ForkConversation,
+		ReadOnly:         data.ReadOnly,
+		events:           data.Events,
+		savedEvents:      data.EventCount,
+	}
+
+	if session.Messages == nil {
+		session.Messages = []Message{}
 	}
 
 	// Initialize tags if nil
@@ -170,21 +264,46 @@ func LoadSession(sessionID string) (*Session, error) {
 		session.UpdatedAt = updatedAt
 	}
 
+	if data.Log == nil || len(data.Events) > 0 {
+		// Old format: rewrite as header plus message and event logs
+		if err := SaveSession(session); err != nil {
+			fmt.Printf("Warning: failed to migrate session %s: %v\n", sessionID, err)
+		}
+	}
+	if data.Log != nil && withMessages {
+		if err := session.ensureMessagesLoaded(); err != nil {
+			return nil, err
+		}
+	}
+
 	// Initialize context for the session (required for sending messages)
 	session.ctx, session.cancel = context.WithCancel(context.Background())
 
-	// Set status to idle if it was stopped/error/running (make session usable again).
-	// Sessions that were "running" at save time had their process die without
-	// transitioning — treat them as stale.
-	if session.Status == SessionStatusStopped || session.Status == SessionStatusError || session.Status == SessionStatusRunning {
+	// Set status to idle if it was stopped/error (make session usable again).
+	// Sessions that were running or waiting at save time were cut off when the
+	// app quit; they are marked interrupted so they can be resumed.
+	switch session.Status {
+	case SessionStatusStopped, SessionStatusError:
 		session.Status = SessionStatusIdle
+	case SessionStatusRunning, SessionStatusWaiting:
+		session.Status = SessionStatusInterrupted
 	}
 
 	return session, nil
 }
 
-// LoadAllSessions loads all persisted sessions
+// LoadAllSessions loads all persisted sessions with their messages
 func LoadAllSessions() ([]*Session, error) {
+	return loadAllSessions(true)
+}
+
+// LoadAllSessionsLazy loads the metadata of all persisted sessions. Messages
+// stay on disk until a session is first used.
+func LoadAllSessionsLazy() ([]*Session, error) {
+	return loadAllSessions(false)
+}
+
+func loadAllSessions(withMessages bool) ([]*Session, error) {
 	sessionsDir, err := GetSessionsDir()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sessions directory: %w", err)
@@ -206,7 +325,7 @@ func LoadAllSessions() ([]*Session, error) {
 		}
 
 		sessionID := entry.Name()[:len(entry.Name())-5] // Remove .json extension
-		session, err := LoadSession(sessionID)
+		session, err := loadSession(sessionID, withMessages)
 		if err != nil {
 			fmt.Printf("Warning: failed to load session %s: %v\n", sessionID, err)
 			continue
@@ -218,7 +337,8 @@ func LoadAllSessions() ([]*Session, error) {
 	return sessions, nil
 }
 
-// DeleteSessionFile removes a session file from disk
+// DeleteSessionFile removes a session's header, message logs and event log
+// from disk
 func DeleteSessionFile(sessionID string) error {
 	sessionsDir, err := GetSessionsDir()
 	if err != nil {
@@ -230,6 +350,19 @@ func DeleteSessionFile(sessionID string) error {
 		return fmt.Errorf("failed to delete session file: %w", err)
 	}
 
+	logs, _ := filepath.Glob(filepath.Join(sessionsDir, sessionID+".*.log"))
+	for _, logFile := range logs {
+		if err := os.Remove(logFile); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete session log: %w", err)
+		}
+	}
+
+	if index := loadedSearchIndex(); index != nil && index.sessionsDir == sessionsDir {
+		if err := index.RemoveSession(sessionID); err != nil {
+			fmt.Printf("Warning: failed to remove session %s from search index: %v\n", sessionID, err)
+		}
+	}
+
 	return nil
 }
 
@@ -240,11 +373,11 @@ func parseTimestamp(s string) (time.Time, error) {
 
 // GetArchivesDir returns the directory where archived messages are stored
 func GetArchivesDir() (string, error) {
-	homeDir, err := os.UserHomeDir()
+	sessionsDir, err := GetSessionsDir()
 	if err != nil {
 		return "", err
 	}
-	archivesDir := filepath.Join(homeDir, ".boatman", "sessions", "archives")
+	archivesDir := filepath.Join(sessionsDir, "archives")
 
 	// Create directory if it doesn't exist
 	if err := os.MkdirAll(archivesDir, 0755); err != nil {
@@ -288,30 +421,48 @@ func ArchiveMessages(sessionID string, messages []Message) error {
 		return fmt.Errorf("failed to write archive file: %w", err)
 	}
 
+	// Keep archived messages searchable
+	index, err := openSearchIndex()
+	if err == nil {
+		err = index.AddArchived(sessionID, messages)
+	}
+	if err != nil {
+		fmt.Printf("Warning: failed to index archived messages: %v\n", err)
+	}
+
 	return nil
 }
 
-// GetArchivedMessageCount returns the number of archived messages for a session
-func GetArchivedMessageCount(sessionID string) (int, error) {
+// LoadArchivedMessages returns the archived messages of a session
+func LoadArchivedMessages(sessionID string) ([]Message, error) {
 	archivesDir, err := GetArchivesDir()
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	filename := filepath.Join(archivesDir, sessionID+".json")
 	data, err := os.ReadFile(filename)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return 0, nil
+			return nil, nil
 		}
-		return 0, fmt.Errorf("failed to read archive file: %w", err)
+		return nil, fmt.Errorf("failed to read archive file: %w", err)
 	}
 
 	var messages []Message
 	if err := json.Unmarshal(data, &messages); err != nil {
-		return 0, fmt.Errorf("failed to unmarshal archive: %w", err)
+		return nil, fmt.Errorf("failed to unmarshal archive: %w", err)
 	}
 
+	return messages, nil
+}
+
+// GetArchivedMessageCount returns the number of archived messages for a session
+func GetArchivedMessageCount(sessionID string) (int, error) {
+	messages, err := LoadArchivedMessages(sessionID)
+	if err != nil {
+		return 0, err
+	}
 	return len(messages), nil
 }
 
@@ -339,7 +490,7 @@ type SessionStats struct {
 
 // GetSessionStats returns statistics about all sessions
 func GetSessionStats() (*SessionStats, error) {
-	sessions, err := LoadAllSessions()
+	sessions, err := LoadAllSessionsLazy()
 	if err != nil {
 		return nil, err
 	}
@@ -370,7 +521,7 @@ func GetSessionStats() (*SessionStats, error) {
 
 // CleanupOldSessions deletes sessions based on age and count limits
 func CleanupOldSessions(maxAgeDays, maxTotal int) (int, error) {
-	sessions, err := LoadAllSessions()
+	sessions, err := LoadAllSessionsLazy()
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,141 @@
+package agent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Postmortem drafts a Markdown postmortem for an incident from its alerts and
+// investigation timeline. Sections the timeline can't answer (root cause,
+// action items) are left as TODOs for whoever finishes the write-up.
+func (st *IncidentStore) Postmortem(id string) (string, error) {
+	inc, err := st.Get(id)
+	if err != nil {
+		return "", err
+	}
+	return renderPostmortem(inc, st.now()), nil
+}
+
+func renderPostmortem(inc Incident, now time.Time) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Postmortem: %s\n\n", inc.Title)
+	fmt.Fprintf(&b, "_Draft generated %s from the firefighter incident timeline. Review before sharing._\n\n", formatIncidentTime(now))
+
+	b.WriteString("## Summary\n\n")
+	if inc.Investigation != nil && inc.Investigation.Summary != "" {
+		b.WriteString(inc.Investigation.Summary + "\n\n")
+	} else {
+		b.WriteString("TODO: What happened, in two or three sentences.\n\n")
+	}
+
+	b.WriteString("## Impact\n\n")
+	fmt.Fprintf(&b, "- **Severity:** %s\n", inc.Severity)
+	fmt.Fprintf(&b, "- **Status:** %s\n", inc.Status)
+	fmt.Fprintf(&b, "- **First seen:** %s\n", formatIncidentTime(inc.FirstSeen))
+	if inc.Status == IncidentStatusResolved {
+		fmt.Fprintf(&b, "- **Resolved:** %s (%s after first seen)\n", formatIncidentTime(inc.ResolvedAt), formatIncidentDuration(inc.ResolvedAt.Sub(inc.FirstSeen)))
+	} else {
+		fmt.Fprintf(&b, "- **Open for:** %s\n", formatIncidentDuration(now.Sub(inc.FirstSeen)))
+	}
+	fmt.Fprintf(&b, "- **Alerts:** %s\n\n", alertCountsBySource(inc.Alerts))
+
+	b.WriteString("## Alerts\n\n")
+	b.WriteString("| Source | Alert | Severity | First seen | Last seen | Count |\n")
+	b.WriteString("|---|---|---|---|---|---|\n")
+	for _, alert := range inc.Alerts {
+		title := markdownCell(alert.Title)
+		if alert.URL != "" {
+			title = fmt.Sprintf("[%s](%s)", title, alert.URL)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %d |\n",
+			sourceLabel(alert.Source), title, alert.Severity,
+			formatIncidentTime(alert.FirstSeen), formatIncidentTime(alert.LastSeen), alert.Count)
+	}
+	b.WriteString("\n")
+
+	b.WriteString("## Timeline (UTC)\n\n")
+	for _, entry := range inc.Timeline {
+		fmt.Fprintf(&b, "- **%s** %s\n", formatIncidentTime(entry.Time), entry.Message)
+	}
+	b.WriteString("\n")
+
+	b.WriteString("## Investigation\n\n")
+	if inv := inc.Investigation; inv != nil {
+		if inv.WorktreePath != "" {
+			fmt.Fprintf(&b, "- **Worktree:** `%s`\n", inv.WorktreePath)
+		}
+		if inv.BranchName != "" {
+			fmt.Fprintf(&b, "- **Branch:** `%s`\n", inv.BranchName)
+		}
+		if inv.PRNumber != "" || inc.URL != "" {
+			pr := "#" + inv.PRNumber
+			if inv.PRNumber == "" {
+				pr = inc.URL
+			} else if inc.URL != "" {
+				pr = fmt.Sprintf("[#%s](%s)", inv.PRNumber, inc.URL)
+			}
+			fmt.Fprintf(&b, "- **Pull request:** %s\n", pr)
+		}
+		if inv.FixDescription != "" {
+			fmt.Fprintf(&b, "- **Fix:** %s\n", inv.FixDescription)
+		}
+		fmt.Fprintf(&b, "- **Started:** %s\n\n", formatIncidentTime(inv.StartedAt))
+	} else {
+		b.WriteString("No investigation was recorded.\n\n")
+	}
+
+	b.WriteString("## Root Cause\n\n")
+	b.WriteString("TODO: Why it happened, and why it wasn't caught earlier.\n\n")
+
+	b.WriteString("## Action Items\n\n")
+	b.WriteString("- [ ] TODO: Prevent a recurrence\n")
+	b.WriteString("- [ ] TODO: Detect it sooner\n")
+
+	return b.String()
+}
+
+// alertCountsBySource summarizes alerts as e.g. "3 Bugsnag, 1 Linear"
+func alertCountsBySource(alerts []Alert) string {
+	counts := map[string]int{}
+	for _, alert := range alerts {
+		counts[sourceLabel(alert.Source)] += alert.Count
+	}
+	if len(counts) == 0 {
+		return "none"
+	}
+	labels := make([]string, 0, len(counts))
+	for label := range counts {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	parts := make([]string, len(labels))
+	for i, label := range labels {
+		parts[i] = fmt.Sprintf("%d %s", counts[label], label)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatIncidentTime(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return t.UTC().Format("2006-01-02 15:04 MST")
+}
+
+func formatIncidentDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+}
+
+func markdownCell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+}
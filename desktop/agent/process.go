@@ -73,12 +73,7 @@ func (c *ClaudeCLI) BuildArgs(opts SessionOptions) []string {
 	}
 
 	// Set approval mode
-	switch opts.ApprovalMode {
-	case "auto-edit":
-		args = append(args, "--allowedTools", "Edit,Write")
-	case "full-auto":
-		args = append(args, "--dangerously-skip-permissions")
-	}
+	args = append(args, permissionArgs(opts)...)
 
 	// Add MCP configuration if provided
 	for _, mcp := range opts.MCPServers {
@@ -90,6 +85,28 @@ func (c *ClaudeCLI) BuildArgs(opts SessionOptions) []string {
 	return args
 }
 
+// permissionArgs returns the permission flags for an approval mode. When a
+// permission prompt tool is set, tool calls that are not allowed up front are
+// sent to it for a decision instead of being denied.
+func permissionArgs(opts SessionOptions) []string {
+	var args []string
+	switch opts.ApprovalMode {
+	case "full-auto":
+		return []string{"--dangerously-skip-permissions"}
+	case "auto-edit":
+		args = append(args, "--allowedTools", "Edit,Write")
+	}
+
+	if opts.PermissionPromptTool != "" {
+		if opts.PermissionMCPConfig != "" {
+			args = append(args, "--mcp-config", opts.PermissionMCPConfig)
+		}
+		args = append(args, "--permission-prompt-tool", opts.PermissionPromptTool)
+	}
+
+	return args
+}
+
 // SessionOptions configures a Claude CLI session
 type SessionOptions struct {
 	Model        string
@@ -97,6 +114,12 @@ type SessionOptions struct {
 	MCPServers   []MCPServerConfig
 	JSONOutput   bool
 	WorkingDir   string
+
+	// PermissionPromptTool is the MCP tool the CLI asks before running a tool
+	// call that needs approval, and PermissionMCPConfig is the --mcp-config
+	// JSON that provides it.
+	PermissionPromptTool string
+	PermissionMCPConfig  string
 }
 
 // MCPServerConfig represents MCP server configuration for CLI
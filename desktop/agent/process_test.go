@@ -197,6 +197,58 @@ func TestBuildArgs_ApprovalMode_Suggest(t *testing.T) {
 	}
 }
 
+func TestBuildArgs_PermissionPromptTool(t *testing.T) {
+	cli := NewClaudeCLI()
+
+	tests := []struct {
+		name     string
+		mode     string
+		expected []string
+	}{
+		{
+			name: "suggest",
+			mode: "suggest",
+			expected: []string{
+				"--mcp-config", `{"mcpServers":{}}`,
+				"--permission-prompt-tool", ApprovalToolName,
+			},
+		},
+		{
+			name: "auto-edit",
+			mode: "auto-edit",
+			expected: []string{
+				"--allowedTools", "Edit,Write",
+				"--mcp-config", `{"mcpServers":{}}`,
+				"--permission-prompt-tool", ApprovalToolName,
+			},
+		},
+		{
+			name:     "full-auto ignores prompt tool",
+			mode:     "full-auto",
+			expected: []string{"--dangerously-skip-permissions"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := cli.BuildArgs(SessionOptions{
+				ApprovalMode:         tt.mode,
+				PermissionPromptTool: ApprovalToolName,
+				PermissionMCPConfig:  `{"mcpServers":{}}`,
+			})
+
+			if len(args) != len(tt.expected) {
+				t.Fatalf("Expected %d args, got %d: %v", len(tt.expected), len(args), args)
+			}
+			for i, expected := range tt.expected {
+				if args[i] != expected {
+					t.Errorf("Expected arg[%d] = '%s', got '%s'", i, expected, args[i])
+				}
+			}
+		})
+	}
+}
+
 func TestBuildArgs_MCPServers(t *testing.T) {
 	cli := NewClaudeCLI()
 
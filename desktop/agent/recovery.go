@@ -0,0 +1,180 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Crash recovery. While a session runs a subprocess (the Claude CLI or the
+// boatman CLI) its PID is persisted with the session, so when the app quits
+// mid-run the next launch finds the session marked interrupted, stops the
+// orphaned process, and can resume the run.
+
+// resumeInterruptedPrompt continues a conversation that was cut off mid-run.
+const resumeInterruptedPrompt = "The previous run was interrupted before it finished. Continue where you left off."
+
+// orphanStopTimeout is how long an orphaned process gets to exit after
+// SIGTERM before it is killed.
+const orphanStopTimeout = 3 * time.Second
+
+// MarkProcessStarted records a subprocess running on the session's behalf
+// and marks the session running.
+func (s *Session) MarkProcessStarted(pid int, command string) {
+	s.mu.Lock()
+	if s.Status != SessionStatusRunning {
+		s.setStatus(SessionStatusRunning)
+	}
+	s.mu.Unlock()
+
+	s.trackProcess(pid, command)
+}
+
+// trackProcess records the session's subprocess and persists it so an
+// interrupted run can be recovered.
+func (s *Session) trackProcess(pid int, command string) {
+	s.mu.Lock()
+	s.processPID = pid
+	s.processCommand = command
+	s.mu.Unlock()
+
+	if err := SaveSession(s); err != nil {
+		fmt.Printf("Warning: failed to save session %s after starting %s: %v\n", s.ID, command, err)
+	}
+}
+
+// MarkProcessExited clears the subprocess recorded by MarkProcessStarted and
+// returns a running session to idle.
+func (s *Session) MarkProcessExited() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.processPID = 0
+	s.processCommand = ""
+	if s.Status == SessionStatusRunning {
+		s.setStatus(SessionStatusIdle)
+	}
+}
+
+// IsInterrupted reports whether the session was cut off mid-run by the app
+// quitting.
+func (s *Session) IsInterrupted() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Status == SessionStatusInterrupted
+}
+
+// StopOrphanedProcess stops the subprocess an interrupted session left
+// running, if it is still alive, and forgets it. The process is only stopped
+// if its command line still names the recorded command, so a reused PID is
+// left alone.
+func (s *Session) StopOrphanedProcess() error {
+	s.mu.RLock()
+	pid := s.processPID
+	command := s.processCommand
+	s.mu.RUnlock()
+
+	if pid == 0 {
+		return nil
+	}
+
+	var stopErr error
+	if commandLine := processCommandLine(pid); commandLine != "" && strings.Contains(commandLine, command) {
+		fmt.Printf("[recovery] Stopping orphaned %s process %d for session %s\n", command, pid, s.ID)
+		stopErr = stopProcess(pid)
+	}
+
+	s.mu.Lock()
+	s.processPID = 0
+	s.processCommand = ""
+	s.mu.Unlock()
+
+	if err := SaveSession(s); err != nil {
+		fmt.Printf("Warning: failed to save session %s after recovery: %v\n", s.ID, err)
+	}
+	return stopErr
+}
+
+// ResumeInterrupted continues an interrupted session. When the Claude
+// conversation had started it is resumed with a prompt to carry on;
+// otherwise the last user message is sent again.
+func (s *Session) ResumeInterrupted(authConfig AuthConfig) error {
+	if err := s.ensureMessagesLoaded(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if s.Status != SessionStatusInterrupted {
+		s.mu.Unlock()
+		return fmt.Errorf("session %s was not interrupted", s.ID)
+	}
+
+	prompt := resumeInterruptedPrompt
+	if s.conversationID == "" {
+		prompt = ""
+		for i := len(s.Messages) - 1; i >= 0; i-- {
+			if s.Messages[i].Role == "user" {
+				prompt = s.Messages[i].Content
+				break
+			}
+		}
+	}
+	if prompt == "" {
+		s.mu.Unlock()
+		return fmt.Errorf("session %s has nothing to resume", s.ID)
+	}
+
+	// SendMessage treats the session like any other idle one
+	s.setStatus(SessionStatusIdle)
+	s.mu.Unlock()
+
+	return s.SendMessage(prompt, authConfig)
+}
+
+// processCommandLine returns the command line of a running process, or an
+// empty string if there is no such process.
+func processCommandLine(pid int) string {
+	var out []byte
+	var err error
+	switch runtime.GOOS {
+	case "windows":
+		out, err = exec.Command("powershell", "-NoProfile", "-Command",
+			fmt.Sprintf("(Get-CimInstance Win32_Process -Filter 'ProcessId=%d').CommandLine", pid)).Output()
+	default:
+		out, err = exec.Command("ps", "-o", "args=", "-p", strconv.Itoa(pid)).Output()
+	}
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// stopProcess asks a process to exit and kills it if it has not exited
+// within orphanStopTimeout.
+func stopProcess(pid int) error {
+	if runtime.GOOS == "windows" {
+		return exec.Command("taskkill", "/PID", strconv.Itoa(pid), "/T", "/F").Run()
+	}
+
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	if err := proc.Signal(syscall.SIGTERM); err != nil {
+		return nil // already gone
+	}
+
+	deadline := time.Now().Add(orphanStopTimeout)
+	for time.Now().Before(deadline) {
+		if processCommandLine(pid) == "" {
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return proc.Kill()
+}
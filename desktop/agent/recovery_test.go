@@ -0,0 +1,158 @@
+package agent
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestLoadSession_MarksRunningInterrupted(t *testing.T) {
+	useTempSessionsDir(t)
+
+	for _, status := range []SessionStatus{SessionStatusRunning, SessionStatusWaiting} {
+		session := NewSession("test-interrupted", "/tmp/test")
+		session.conversationID = "conv-1"
+		session.trackProcess(4242, "claude")
+		session.Status = status
+		if err := SaveSession(session); err != nil {
+			t.Fatalf("SaveSession() error = %v", err)
+		}
+
+		loaded, err := LoadSession(session.ID)
+		if err != nil {
+			t.Fatalf("LoadSession() error = %v", err)
+		}
+		if loaded.Status != SessionStatusInterrupted {
+			t.Errorf("%s session loaded as %s, want %s", status, loaded.Status, SessionStatusInterrupted)
+		}
+		if loaded.processPID != 4242 || loaded.processCommand != "claude" || loaded.conversationID != "conv-1" {
+			t.Errorf("Recovery state not persisted: pid %d, command %q, conversation %q",
+				loaded.processPID, loaded.processCommand, loaded.conversationID)
+		}
+	}
+}
+
+func TestMarkProcessExited(t *testing.T) {
+	useTempSessionsDir(t)
+
+	session := NewSession("test-session", "/tmp/test")
+	session.MarkProcessStarted(4242, "boatman")
+	if session.Status != SessionStatusRunning {
+		t.Errorf("Status = %s after start, want %s", session.Status, SessionStatusRunning)
+	}
+
+	session.MarkProcessExited()
+	if session.Status != SessionStatusIdle || session.processPID != 0 {
+		t.Errorf("Status = %s, pid = %d after exit, want idle and no process", session.Status, session.processPID)
+	}
+}
+
+func startSleep(t *testing.T) (*exec.Cmd, chan error) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sleep and ps")
+	}
+
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sleep: %v", err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	t.Cleanup(func() { cmd.Process.Kill() })
+	return cmd, exited
+}
+
+func TestStopOrphanedProcess(t *testing.T) {
+	useTempSessionsDir(t)
+	cmd, exited := startSleep(t)
+
+	session := NewSession("test-session", "/tmp/test")
+	session.processPID = cmd.Process.Pid
+	session.processCommand = "sleep"
+
+	if err := session.StopOrphanedProcess(); err != nil {
+		t.Fatalf("StopOrphanedProcess() error = %v", err)
+	}
+
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Orphaned process was not stopped")
+	}
+	if session.processPID != 0 {
+		t.Errorf("Expected the process to be forgotten, got pid %d", session.processPID)
+	}
+}
+
+func TestStopOrphanedProcess_IgnoresReusedPID(t *testing.T) {
+	useTempSessionsDir(t)
+	cmd, exited := startSleep(t)
+
+	// The PID now belongs to a different program
+	session := NewSession("test-session", "/tmp/test")
+	session.processPID = cmd.Process.Pid
+	session.processCommand = "claude"
+
+	if err := session.StopOrphanedProcess(); err != nil {
+		t.Fatalf("StopOrphanedProcess() error = %v", err)
+	}
+
+	select {
+	case <-exited:
+		t.Fatal("Stopped a process the session did not start")
+	case <-time.After(200 * time.Millisecond):
+	}
+	if session.processPID != 0 {
+		t.Errorf("Expected the process to be forgotten, got pid %d", session.processPID)
+	}
+}
+
+func TestResumeInterrupted(t *testing.T) {
+	useTempSessionsDir(t)
+
+	session := NewSession("test-session", "/tmp/test")
+	if err := session.ResumeInterrupted(AuthConfig{}); err == nil {
+		t.Error("Expected an error resuming a session that was not interrupted")
+	}
+
+	session.Status = SessionStatusInterrupted
+	if err := session.ResumeInterrupted(AuthConfig{}); err == nil {
+		t.Error("Expected an error resuming a session with nothing to resume")
+	}
+
+	tests := []struct {
+		name           string
+		conversationID string
+		want           string
+	}{
+		{"continues the conversation", "conv-1", resumeInterruptedPrompt},
+		{"resends the last request", "", "Add a login page"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := NewSession("test-session", "/tmp/test")
+			session.Status = SessionStatusInterrupted
+			session.conversationID = tt.conversationID
+			session.Messages = []Message{
+				{ID: "m1", Role: "user", Content: "Add a login page"},
+				{ID: "m2", Role: "assistant", Content: "Working on it"},
+			}
+			// A cancelled context keeps the Claude CLI from starting
+			session.ctx, session.cancel = context.WithCancel(context.Background())
+			session.cancel()
+
+			if err := session.ResumeInterrupted(AuthConfig{}); err != nil {
+				t.Fatalf("ResumeInterrupted() error = %v", err)
+			}
+
+			messages := session.GetMessages()
+			if len(messages) < 3 || messages[2].Role != "user" || messages[2].Content != tt.want {
+				t.Errorf("Expected %q to be sent, got %+v", tt.want, messages)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxRunEvents caps the events kept per session; the oldest are dropped first.
+const maxRunEvents = 2000
+
+// RunEvent is a workflow event (agent started, task updated, ...) reported by
+// the boatman CLI during a run. Sessions keep them so exported bundles can
+// include the run's event log. Saving a session appends its new events to an
+// event log next to the header, one JSON event per line; the log is only read
+// back for export.
+type RunEvent struct {
+	Time time.Time              `json:"time"`
+	Type string                 `json:"type"`
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// RecordEvent appends a workflow event to the session's event log
+func (s *Session) RecordEvent(eventType string, data map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, RunEvent{Time: time.Now(), Type: eventType, Data: data})
+	if len(s.events) > maxRunEvents {
+		s.events = append([]RunEvent(nil), s.events[len(s.events)-maxRunEvents:]...)
+	}
+}
+
+// GetEvents returns the session's event log: the last maxRunEvents events,
+// saved or not
+func (s *Session) GetEvents() []RunEvent {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	var events []RunEvent
+	if sessionsDir, err := GetSessionsDir(); err == nil {
+		events, err = readEventLog(sessionsDir, s.ID)
+		if err != nil {
+			fmt.Printf("Warning: failed to read event log of session %s: %v\n", s.ID, err)
+		}
+	}
+
+	s.mu.RLock()
+	events = append(events, s.events...)
+	s.mu.RUnlock()
+
+	if len(events) > maxRunEvents {
+		events = events[len(events)-maxRunEvents:]
+	}
+	return events
+}
+
+// eventLogFile returns the name of a session's event log in the sessions
+// directory
+func eventLogFile(sessionID string) string {
+	return sessionID + ".events.log"
+}
+
+// appendEventLog appends events to the session's event log. Once the log
+// holds twice maxRunEvents it is rewritten with the last maxRunEvents.
+// Note: This method expects the caller to hold s.saveMu
+func (s *Session) appendEventLog(sessionsDir string, events []RunEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	filename := filepath.Join(sessionsDir, eventLogFile(s.ID))
+	if s.savedEvents+len(events) > 2*maxRunEvents {
+		saved, err := readEventLog(sessionsDir, s.ID)
+		if err != nil {
+			return err
+		}
+		return s.rewriteEventLog(filename, append(saved, events...))
+	}
+
+	data, err := marshalEvents(events)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open event log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("failed to append to event log: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync event log: %w", err)
+	}
+	s.savedEvents += len(events)
+	return nil
+}
+
+// rewriteEventLog replaces the event log with the last maxRunEvents events
+// Note: This method expects the caller to hold s.saveMu
+func (s *Session) rewriteEventLog(filename string, events []RunEvent) error {
+	if len(events) > maxRunEvents {
+		events = events[len(events)-maxRunEvents:]
+	}
+
+	data, err := marshalEvents(events)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filename, data); err != nil {
+		return fmt.Errorf("failed to write event log: %w", err)
+	}
+	s.savedEvents = len(events)
+	return nil
+}
+
+// marshalEvents encodes events as event log lines
+func marshalEvents(events []RunEvent) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, event := range events {
+		line, err := json.Marshal(event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s event: %w", event.Type, err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// readEventLog reads a session's saved events. A missing log has none, and a
+// line cut short by a crash is skipped.
+func readEventLog(sessionsDir, sessionID string) ([]RunEvent, error) {
+	f, err := os.Open(filepath.Join(sessionsDir, eventLogFile(sessionID)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []RunEvent
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var event RunEvent
+			if json.Unmarshal(line, &event) == nil {
+				events = append(events, event)
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	return events, nil
+}
+
+// ImportedSession is a session read from an exported bundle
+type ImportedSession struct {
+	SourceID    string
+	ProjectPath string
+	Mode        string
+	ModeConfig  map[string]interface{}
+	Model       string
+	Tags        []string
+	CreatedAt   time.Time
+	Messages    []Message
+	Tasks       []Task
+	Events      []RunEvent
+}
+
+// ImportSession adds an imported session. Imported sessions are read-only:
+// they can be browsed and searched, but not sent messages.
+func (m *Manager) ImportSession(imported ImportedSession) (*Session, error) {
+	session := NewSession(uuid.New().String(), imported.ProjectPath)
+	session.ReadOnly = true
+	session.Mode = imported.Mode
+	session.ModeConfig = imported.ModeConfig
+	session.Model = imported.Model
+	session.Tags = append(append([]string{}, imported.Tags...), "imported")
+	if !imported.CreatedAt.IsZero() {
+		session.CreatedAt = imported.CreatedAt
+	}
+	if imported.Messages != nil {
+		session.Messages = imported.Messages
+	}
+	if imported.Tasks != nil {
+		session.Tasks = imported.Tasks
+	}
+	session.events = imported.Events
+	if session.ModeConfig == nil {
+		session.ModeConfig = map[string]interface{}{}
+	}
+	session.ModeConfig["importedFrom"] = imported.SourceID
+
+	m.mu.Lock()
+	m.setupSessionHandlers(session, session.ID)
+	m.sessions[session.ID] = session
+	m.mu.Unlock()
+
+	if err := SaveSession(session); err != nil {
+		return nil, fmt.Errorf("failed to save imported session: %w", err)
+	}
+	return session, nil
+}
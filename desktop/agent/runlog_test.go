@@ -0,0 +1,150 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordEvent_CapsLog(t *testing.T) {
+	useTempSessionsDir(t)
+
+	session := NewSession("test", "/path/to/project")
+	for i := 0; i < maxRunEvents+10; i++ {
+		session.RecordEvent("progress", map[string]interface{}{"i": i})
+	}
+
+	events := session.GetEvents()
+	if len(events) != maxRunEvents {
+		t.Fatalf("Expected %d events, got %d", maxRunEvents, len(events))
+	}
+	if events[0].Data["i"] != 10 {
+		t.Errorf("Expected the oldest events to be dropped, first is %v", events[0].Data["i"])
+	}
+}
+
+func TestSaveSession_AppendsEventLog(t *testing.T) {
+	dir := useTempSessionsDir(t)
+
+	session := NewSession("events", "/path/to/project")
+	for i := 0; i < 3; i++ {
+		session.RecordEvent("progress", map[string]interface{}{"i": i})
+		if err := SaveSession(session); err != nil {
+			t.Fatalf("SaveSession() error = %v", err)
+		}
+	}
+
+	header, err := os.ReadFile(filepath.Join(dir, "events.json"))
+	if err != nil {
+		t.Fatalf("Failed to read header: %v", err)
+	}
+	if strings.Contains(string(header), `"events":`) {
+		t.Errorf("Events should not be written to the header:\n%s", header)
+	}
+	logData, err := os.ReadFile(filepath.Join(dir, eventLogFile("events")))
+	if err != nil {
+		t.Fatalf("Failed to read event log: %v", err)
+	}
+	if lines := strings.Count(string(logData), "\n"); lines != 3 {
+		t.Errorf("Expected 3 logged events, got %d", lines)
+	}
+
+	loaded, err := LoadSession("events")
+	if err != nil {
+		t.Fatalf("LoadSession() error = %v", err)
+	}
+	if len(loaded.events) != 0 {
+		t.Errorf("Events should stay on disk until exported, %d loaded", len(loaded.events))
+	}
+	loaded.RecordEvent("progress", map[string]interface{}{"i": 3})
+	events := loaded.GetEvents()
+	if len(events) != 4 || events[0].Data["i"] != float64(0) || events[3].Data["i"] != 3 {
+		t.Errorf("Unexpected events: %v", events)
+	}
+}
+
+func TestSaveSession_RewritesLongEventLog(t *testing.T) {
+	useTempSessionsDir(t)
+
+	session := NewSession("events", "/path/to/project")
+	for round := 0; round < 3; round++ {
+		for i := 0; i < maxRunEvents; i++ {
+			session.RecordEvent("progress", nil)
+		}
+		if err := SaveSession(session); err != nil {
+			t.Fatalf("SaveSession() error = %v", err)
+		}
+	}
+
+	if session.savedEvents != maxRunEvents {
+		t.Errorf("Expected the log to be cut to %d events, it holds %d", maxRunEvents, session.savedEvents)
+	}
+	if events := session.GetEvents(); len(events) != maxRunEvents {
+		t.Errorf("Expected %d events, got %d", maxRunEvents, len(events))
+	}
+}
+
+func TestLoadSession_MigratesInlineEvents(t *testing.T) {
+	dir := useTempSessionsDir(t)
+
+	old := `{"id": "old", "projectPath": "/p", "status": "idle", "tasks": [],
+		"createdAt": "2024-01-01T00:00:00Z", "updatedAt": "2024-01-01T00:00:00Z",
+		"log": {"file": "old.1.log", "generation": 1, "skip": 0, "lines": 0, "size": 0, "index": []},
+		"events": [{"time": "2024-01-01T00:00:00Z", "type": "agent_started"}]}`
+	if err := os.WriteFile(filepath.Join(dir, "old.json"), []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "old.1.log"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadSession("old")
+	if err != nil {
+		t.Fatalf("LoadSession() error = %v", err)
+	}
+	header, _ := os.ReadFile(filepath.Join(dir, "old.json"))
+	if strings.Contains(string(header), `"events":`) {
+		t.Errorf("Inline events should be moved to the event log:\n%s", header)
+	}
+	if events := loaded.GetEvents(); len(events) != 1 || events[0].Type != "agent_started" {
+		t.Errorf("Unexpected events after migration: %v", events)
+	}
+}
+
+func TestManager_ImportSession(t *testing.T) {
+	useTempSessionsDir(t)
+
+	manager := NewManager()
+	session, err := manager.ImportSession(ImportedSession{
+		SourceID:    "original",
+		ProjectPath: "/path/to/project",
+		Mode:        "boatmanmode",
+		Tags:        []string{"auth"},
+		Messages:    []Message{{ID: "m1", Role: "user", Content: "Fix the login bug"}},
+		Tasks:       []Task{{ID: "execute", Subject: "Execute", Status: "completed"}},
+		Events:      []RunEvent{{Type: "agent_started"}},
+	})
+	if err != nil {
+		t.Fatalf("ImportSession() error = %v", err)
+	}
+
+	if session.ID == "original" || !session.ReadOnly {
+		t.Errorf("Expected a new read-only session, got ID %q (read-only %v)", session.ID, session.ReadOnly)
+	}
+	if err := session.SendMessage("Keep going", AuthConfig{}); err == nil {
+		t.Error("Expected sending to an imported session to fail")
+	}
+
+	loaded, err := LoadSession(session.ID)
+	if err != nil {
+		t.Fatalf("LoadSession() error = %v", err)
+	}
+	if !loaded.ReadOnly || len(loaded.Messages) != 1 || len(loaded.Tasks) != 1 || len(loaded.GetEvents()) != 1 {
+		t.Errorf("Import not persisted: read-only %v, %d messages, %d tasks, %d events",
+			loaded.ReadOnly, len(loaded.Messages), len(loaded.Tasks), len(loaded.GetEvents()))
+	}
+	if loaded.ModeConfig["importedFrom"] != "original" || len(loaded.Tags) != 2 {
+		t.Errorf("Unexpected import details: %v, tags %v", loaded.ModeConfig, loaded.Tags)
+	}
+}
@@ -2,8 +2,13 @@ package agent
 
 import (
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
 )
 
 // SearchFilter represents search criteria for sessions
@@ -18,138 +23,500 @@ type SearchFilter struct {
 
 // SearchResult represents a search result
 type SearchResult struct {
-	Session     *Session
-	Score       int      // Relevance score
-	MatchReason []string // Why this session matched
+	Session      *Session // Session metadata; messages are not loaded
+	Score        int      // Relevance score
+	MatchReason  []string // Why this session matched
+	MessageCount int
+	Snippets     []SearchSnippet // Best matching passages, highest ranked first
 }
 
+// SearchSnippet is a passage of a matching message or tool call. Highlight
+// offsets are in UTF-16 code units so the frontend can slice Text directly.
+type SearchSnippet struct {
+	MessageID  string            `json:"messageId"`
+	Field      string            `json:"field"`
+	Archived   bool              `json:"archived"`
+	Timestamp  time.Time         `json:"timestamp"`
+	Text       string            `json:"text"`
+	Highlights []SearchHighlight `json:"highlights"`
+}
+
+// SearchHighlight marks a matched range in a snippet
+type SearchHighlight struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+const (
+	maxSnippetsPerResult = 3
+	snippetLeadIn        = 60
+	snippetLength        = 240
+)
+
 // SessionLoader is a function type for loading sessions (for testing)
 type SessionLoader func() ([]*Session, error)
 
 // defaultSessionLoader is the default implementation
-var defaultSessionLoader SessionLoader = LoadAllSessions
+var defaultSessionLoader SessionLoader = LoadAllSessionsLazy
 
-// SearchSessions searches all sessions based on filter criteria
+// SearchSessions searches all sessions based on filter criteria using the
+// persistent search index
 func SearchSessions(filter SearchFilter) ([]*SearchResult, error) {
-	return searchSessionsWithLoader(filter, defaultSessionLoader)
+	index, err := openSearchIndex()
+	if err != nil {
+		return nil, err
+	}
+	return index.Search(filter), nil
 }
 
-// searchSessionsWithLoader allows dependency injection for testing
+// searchSessionsWithLoader searches the given sessions with a temporary
+// in-memory index (for testing)
 func searchSessionsWithLoader(filter SearchFilter, loader SessionLoader) ([]*SearchResult, error) {
 	sessions, err := loader()
 	if err != nil {
 		return nil, err
 	}
 
-	var results []*SearchResult
-
+	index := newSearchIndex("", "")
+	byID := make(map[string]*Session)
 	for _, session := range sessions {
-		if matchesFilter(session, filter) {
-			score, reasons := scoreSession(session, filter)
-			results = append(results, &SearchResult{
-				Session:     session,
-				Score:       score,
-				MatchReason: reasons,
-			})
-		}
+		session.mu.RLock()
+		data := session.sessionData()
+		messages := make([]Message, len(session.Messages))
+		copy(messages, session.Messages)
+		session.mu.RUnlock()
+
+		index.replaceSession(data, messages, nil)
+		byID[session.ID] = session
 	}
 
-	// Sort by score (highest first)
-	for i := 0; i < len(results); i++ {
-		for j := i + 1; j < len(results); j++ {
-			if results[j].Score > results[i].Score {
-				results[i], results[j] = results[j], results[i]
-			}
+	results := index.Search(filter)
+	for _, result := range results {
+		result.Session = byID[result.Session.ID]
+	}
+	return results, nil
+}
+
+// Search returns the sessions matching filter, best match first. Besides free
+// text, filter.Query accepts "quoted phrases" and the field filters tag:,
+// project:, model:, after:, before:, and date: (YYYY-MM-DD or
+// YYYY-MM-DD..YYYY-MM-DD).
+func (ix *SearchIndex) Search(filter SearchFilter) []*SearchResult {
+	query := parseSearchQuery(filter.Query)
+
+	// termsWithPrefix may rebuild the term list
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	var matches map[*indexedSession]map[*indexDoc]*docMatch
+	if len(query.clauses) > 0 {
+		matches = ix.matchClauses(query.clauses)
+	}
+
+	var results []*SearchResult
+	for _, sess := range ix.sessions {
+		if !matchesFilter(sess, filter) || !query.matchesFields(sess) {
+			continue
 		}
+		docs := matches[sess]
+		if len(query.clauses) > 0 && docs == nil {
+			continue
+		}
+
+		score, reasons := scoreSession(sess, filter, docs)
+		results = append(results, &SearchResult{
+			Session:      sess.summary(),
+			Score:        score,
+			MatchReason:  reasons,
+			MessageCount: sess.meta.MessageCount,
+			Snippets:     snippetsFor(docs),
+		})
 	}
 
-	return results, nil
+	// Sort by score (highest first), then by recency
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Session.UpdatedAt.After(results[j].Session.UpdatedAt)
+	})
+
+	return results
 }
 
-// matchesFilter checks if a session matches the filter criteria
-func matchesFilter(session *Session, filter SearchFilter) bool {
-	session.mu.RLock()
-	defer session.mu.RUnlock()
+// summary returns a Session carrying the indexed metadata
+func (sess *indexedSession) summary() *Session {
+	return &Session{
+		ID:          sess.meta.ID,
+		ProjectPath: sess.meta.ProjectPath,
+		Status:      sess.meta.Status,
+		Messages:    []Message{},
+		Model:       sess.meta.Model,
+		Tags:        append([]string{}, sess.meta.Tags...),
+		IsFavorite:  sess.meta.IsFavorite,
+		CreatedAt:   sess.createdAt,
+		UpdatedAt:   sess.updatedAt,
+	}
+}
 
+// matchesFilter checks if a session matches the filter criteria
+func matchesFilter(sess *indexedSession, filter SearchFilter) bool {
 	// Filter by project path
-	if filter.ProjectPath != "" && session.ProjectPath != filter.ProjectPath {
+	if filter.ProjectPath != "" && sess.meta.ProjectPath != filter.ProjectPath {
 		return false
 	}
 
 	// Filter by favorite status
-	if filter.IsFavorite != nil && session.IsFavorite != *filter.IsFavorite {
+	if filter.IsFavorite != nil && sess.meta.IsFavorite != *filter.IsFavorite {
 		return false
 	}
 
 	// Filter by tags
-	if len(filter.Tags) > 0 {
-		hasAllTags := true
-		for _, filterTag := range filter.Tags {
-			found := false
-			for _, sessionTag := range session.Tags {
-				if strings.EqualFold(sessionTag, filterTag) {
-					found = true
-					break
+	for _, filterTag := range filter.Tags {
+		if !hasTag(sess, filterTag) {
+			return false
+		}
+	}
+
+	// Filter by date range
+	if !filter.FromDate.IsZero() && sess.updatedAt.Before(filter.FromDate) {
+		return false
+	}
+	if !filter.ToDate.IsZero() && sess.updatedAt.After(filter.ToDate) {
+		return false
+	}
+
+	return true
+}
+
+func hasTag(sess *indexedSession, tag string) bool {
+	for _, sessionTag := range sess.meta.Tags {
+		if strings.EqualFold(sessionTag, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// searchQuery is a parsed filter.Query
+type searchQuery struct {
+	clauses  []queryClause
+	tags     []string
+	projects []string
+	models   []string
+	from     time.Time
+	to       time.Time // Exclusive
+}
+
+// queryClause is a term or phrase every matching session must contain. A
+// single unquoted term also matches longer terms it is a prefix of.
+type queryClause struct {
+	terms  []string
+	prefix bool
+}
+
+// parseSearchQuery splits a query into text clauses and field filters
+func parseSearchQuery(raw string) searchQuery {
+	var query searchQuery
+	for _, part := range splitQuery(raw) {
+		field, value, hasField := strings.Cut(part.text, ":")
+		if hasField && !part.quoted && value != "" {
+			value = strings.Trim(value, `"`)
+			switch strings.ToLower(field) {
+			case "tag":
+				query.tags = append(query.tags, value)
+				continue
+			case "project":
+				query.projects = append(query.projects, strings.ToLower(value))
+				continue
+			case "model":
+				query.models = append(query.models, strings.ToLower(value))
+				continue
+			case "after", "before", "date":
+				if query.addDateFilter(strings.ToLower(field), value) {
+					continue
 				}
 			}
-			if !found {
-				hasAllTags = false
-				break
-			}
 		}
-		if !hasAllTags {
+
+		var terms []string
+		for _, tok := range tokenize(part.text) {
+			terms = append(terms, tok.term)
+		}
+		if len(terms) > 0 {
+			query.clauses = append(query.clauses, queryClause{
+				terms:  terms,
+				prefix: !part.quoted && len(terms) == 1,
+			})
+		}
+	}
+	return query
+}
+
+// addDateFilter narrows the query's date range. It reports false if value is
+// not a date, so the part is searched as text instead.
+func (q *searchQuery) addDateFilter(field, value string) bool {
+	from, to := value, value
+	if field == "date" {
+		if start, end, isRange := strings.Cut(value, ".."); isRange {
+			from, to = start, end
+		}
+	}
+
+	parse := func(s string) (time.Time, bool) {
+		t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+		return t, err == nil
+	}
+
+	switch field {
+	case "after":
+		t, ok := parse(value)
+		if !ok {
 			return false
 		}
+		q.narrow(t.AddDate(0, 0, 1), time.Time{})
+	case "before":
+		t, ok := parse(value)
+		if !ok {
+			return false
+		}
+		q.narrow(time.Time{}, t)
+	case "date":
+		var start, end time.Time
+		if from != "" {
+			t, ok := parse(from)
+			if !ok {
+				return false
+			}
+			start = t
+		}
+		if to != "" {
+			t, ok := parse(to)
+			if !ok {
+				return false
+			}
+			end = t.AddDate(0, 0, 1)
+		}
+		q.narrow(start, end)
+	}
+	return true
+}
+
+func (q *searchQuery) narrow(from, to time.Time) {
+	if !from.IsZero() && from.After(q.from) {
+		q.from = from
+	}
+	if !to.IsZero() && (q.to.IsZero() || to.Before(q.to)) {
+		q.to = to
 	}
+}
 
-	// Filter by date range
-	if !filter.FromDate.IsZero() && session.UpdatedAt.Before(filter.FromDate) {
+// matchesFields checks the query's field filters against a session
+func (q *searchQuery) matchesFields(sess *indexedSession) bool {
+	for _, tag := range q.tags {
+		if !hasTag(sess, tag) {
+			return false
+		}
+	}
+	for _, project := range q.projects {
+		if !strings.Contains(strings.ToLower(sess.meta.ProjectPath), project) {
+			return false
+		}
+	}
+	for _, model := range q.models {
+		if !strings.Contains(strings.ToLower(sess.meta.Model), model) {
+			return false
+		}
+	}
+	if !q.from.IsZero() && sess.updatedAt.Before(q.from) {
 		return false
 	}
-	if !filter.ToDate.IsZero() && session.UpdatedAt.After(filter.ToDate) {
+	if !q.to.IsZero() && !sess.updatedAt.Before(q.to) {
 		return false
 	}
+	return true
+}
+
+type queryPart struct {
+	text   string
+	quoted bool
+}
 
-	// If there's a query, check if it matches
-	if filter.Query != "" {
-		query := strings.ToLower(filter.Query)
+// splitQuery splits a query on whitespace, keeping quoted phrases together.
+// A quoted field value such as project:"my app" stays part of its field.
+func splitQuery(raw string) []queryPart {
+	var parts []queryPart
+	var current strings.Builder
+	inQuote := false
+	quotedPart := false
+
+	flush := func() {
+		if current.Len() > 0 {
+			parts = append(parts, queryPart{text: current.String(), quoted: quotedPart})
+		}
+		current.Reset()
+		quotedPart = false
+	}
 
-		// Search in messages
-		for _, msg := range session.Messages {
-			if strings.Contains(strings.ToLower(msg.Content), query) {
-				return true
+	for _, r := range raw {
+		switch {
+		case r == '"':
+			if inQuote {
+				inQuote = false
+				if !strings.Contains(current.String(), ":") {
+					flush()
+				} else {
+					current.WriteRune(r)
+				}
+				continue
+			}
+			inQuote = true
+			if current.Len() == 0 {
+				quotedPart = true
+			} else {
+				current.WriteRune(r)
 			}
+		case unicode.IsSpace(r) && !inQuote:
+			flush()
+		default:
+			current.WriteRune(r)
 		}
+	}
+	flush()
+	return parts
+}
 
-		// Search in project path
-		if strings.Contains(strings.ToLower(session.ProjectPath), query) {
-			return true
+// docMatch records how well a document matched the query
+type docMatch struct {
+	doc       *indexDoc
+	score     float64
+	positions map[int]bool // Matched token positions, for highlighting
+}
+
+// matchClauses finds the sessions containing every clause and scores their
+// matching documents with BM25.
+// Note: This method expects the caller to hold ix.mu lock
+func (ix *SearchIndex) matchClauses(clauses []queryClause) map[*indexedSession]map[*indexDoc]*docMatch {
+	const k1, b = 1.2, 0.75
+
+	avgLength := 1.0
+	if ix.docCount > 0 && ix.totalLength > 0 {
+		avgLength = float64(ix.totalLength) / float64(ix.docCount)
+	}
+
+	matches := make(map[*indexedSession]map[*indexDoc]*docMatch)
+	for i, clause := range clauses {
+		hits := ix.clauseHits(clause)
+		df := float64(len(hits))
+		idf := math.Log(1 + (float64(ix.docCount)-df+0.5)/(df+0.5))
+
+		matched := make(map[*indexedSession]bool)
+		for doc, positions := range hits {
+			sess := doc.session
+			if i > 0 && matches[sess] == nil {
+				continue
+			}
+			matched[sess] = true
+
+			tf := float64(len(positions) / len(clause.terms))
+			norm := tf + k1*(1-b+b*float64(doc.Length)/avgLength)
+			score := idf * tf * (k1 + 1) / norm * fieldWeight(doc)
+
+			docs := matches[sess]
+			if docs == nil {
+				docs = make(map[*indexDoc]*docMatch)
+				matches[sess] = docs
+			}
+			m := docs[doc]
+			if m == nil {
+				m = &docMatch{doc: doc, positions: make(map[int]bool)}
+				docs[doc] = m
+			}
+			m.score += score
+			for _, pos := range positions {
+				m.positions[pos] = true
+			}
 		}
 
-		// Search in tags
-		for _, tag := range session.Tags {
-			if strings.Contains(strings.ToLower(tag), query) {
-				return true
+		// Every clause must match somewhere in the session
+		for sess := range matches {
+			if !matched[sess] {
+				delete(matches, sess)
 			}
 		}
+	}
+	return matches
+}
 
-		// No match found
-		return false
+// clauseHits returns the documents matching a clause with the token positions
+// that matched.
+// Note: This method expects the caller to hold ix.mu lock
+func (ix *SearchIndex) clauseHits(clause queryClause) map[*indexDoc][]int {
+	hits := make(map[*indexDoc][]int)
+
+	if len(clause.terms) == 1 {
+		terms := clause.terms
+		if clause.prefix {
+			terms = ix.termsWithPrefix(clause.terms[0])
+		}
+		for _, term := range terms {
+			for doc := range ix.postings[term] {
+				hits[doc] = append(hits[doc], doc.Terms[term]...)
+			}
+		}
+		return hits
 	}
 
-	return true
+	// Phrase: the terms must appear at consecutive positions
+	for doc := range ix.postings[clause.terms[0]] {
+		for _, start := range doc.Terms[clause.terms[0]] {
+			found := true
+			for offset, term := range clause.terms[1:] {
+				if !containsInt(doc.Terms[term], start+offset+1) {
+					found = false
+					break
+				}
+			}
+			if found {
+				for offset := range clause.terms {
+					hits[doc] = append(hits[doc], start+offset)
+				}
+			}
+		}
+	}
+	return hits
 }
 
-// scoreSession calculates relevance score and match reasons
-func scoreSession(session *Session, filter SearchFilter) (int, []string) {
-	session.mu.RLock()
-	defer session.mu.RUnlock()
+func containsInt(sorted []int, value int) bool {
+	i := sort.SearchInts(sorted, value)
+	return i < len(sorted) && sorted[i] == value
+}
+
+// fieldWeight ranks matches in project paths and tags above message text, and
+// live messages above archived ones
+func fieldWeight(doc *indexDoc) float64 {
+	weight := 1.0
+	switch doc.Field {
+	case SearchFieldProject:
+		weight = 3
+	case SearchFieldTag:
+		weight = 2
+	case SearchFieldToolInput, SearchFieldToolOutput:
+		weight = 0.5
+	}
+	if doc.Archived {
+		weight *= 0.8
+	}
+	return weight
+}
 
+// scoreSession calculates relevance score and match reasons
+func scoreSession(sess *indexedSession, filter SearchFilter, docs map[*indexDoc]*docMatch) (int, []string) {
 	score := 0
 	var reasons []string
 
 	// Base score from recency
-	daysSinceUpdate := time.Since(session.UpdatedAt).Hours() / 24
+	daysSinceUpdate := time.Since(sess.updatedAt).Hours() / 24
 	if daysSinceUpdate < 1 {
 		score += 50
 	} else if daysSinceUpdate < 7 {
@@ -159,7 +526,7 @@ func scoreSession(session *Session, filter SearchFilter) (int, []string) {
 	}
 
 	// Boost for favorites
-	if session.IsFavorite {
+	if sess.meta.IsFavorite {
 		score += 20
 		reasons = append(reasons, "Favorite")
 	}
@@ -173,39 +540,47 @@ func scoreSession(session *Session, filter SearchFilter) (int, []string) {
 	}
 
 	// Query relevance
-	if filter.Query != "" {
-		query := strings.ToLower(filter.Query)
-		matchCount := 0
-
-		// Count message matches
-		for _, msg := range session.Messages {
-			if strings.Contains(strings.ToLower(msg.Content), query) {
-				matchCount++
+	if len(docs) > 0 {
+		relevance := 0.0
+		messageMatches, toolMatches, archivedMatches := 0, 0, 0
+		projectMatch := false
+		var tagMatch string
+
+		for doc, m := range docs {
+			relevance += m.score
+			switch {
+			case doc.Field == SearchFieldProject:
+				projectMatch = true
+			case doc.Field == SearchFieldTag:
+				tagMatch = doc.Text
+			case doc.Archived:
+				archivedMatches++
+			case doc.Field == SearchFieldContent:
+				messageMatches++
+			default:
+				toolMatches++
 			}
 		}
+		score += int(math.Round(relevance * 10))
 
-		if matchCount > 0 {
-			score += matchCount * 5
-			if matchCount == 1 {
-				reasons = append(reasons, "1 message match")
-			} else {
-				reasons = append(reasons, fmt.Sprintf("%d message matches", matchCount))
-			}
+		if messageMatches == 1 {
+			reasons = append(reasons, "1 message match")
+		} else if messageMatches > 1 {
+			reasons = append(reasons, fmt.Sprintf("%d message matches", messageMatches))
 		}
-
-		// Project path match
-		if strings.Contains(strings.ToLower(session.ProjectPath), query) {
-			score += 15
+		if toolMatches == 1 {
+			reasons = append(reasons, "1 tool call match")
+		} else if toolMatches > 1 {
+			reasons = append(reasons, fmt.Sprintf("%d tool call matches", toolMatches))
+		}
+		if archivedMatches > 0 {
+			reasons = append(reasons, fmt.Sprintf("%d archived matches", archivedMatches))
+		}
+		if projectMatch {
 			reasons = append(reasons, "Project path match")
 		}
-
-		// Tag match
-		for _, tag := range session.Tags {
-			if strings.Contains(strings.ToLower(tag), query) {
-				score += 10
-				reasons = append(reasons, "Tag match: "+tag)
-				break
-			}
+		if tagMatch != "" {
+			reasons = append(reasons, "Tag match: "+tagMatch)
 		}
 	}
 
@@ -216,6 +591,109 @@ func scoreSession(session *Session, filter SearchFilter) (int, []string) {
 	return score, reasons
 }
 
+// snippetsFor returns snippets from the best matching message documents
+func snippetsFor(docs map[*indexDoc]*docMatch) []SearchSnippet {
+	var ranked []*docMatch
+	for doc, m := range docs {
+		if doc.MessageID != "" {
+			ranked = append(ranked, m)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].doc.Timestamp.After(ranked[j].doc.Timestamp)
+	})
+	if len(ranked) > maxSnippetsPerResult {
+		ranked = ranked[:maxSnippetsPerResult]
+	}
+
+	snippets := []SearchSnippet{}
+	for _, m := range ranked {
+		snippets = append(snippets, makeSnippet(m))
+	}
+	return snippets
+}
+
+// makeSnippet cuts a window of text around the first match and highlights the
+// matched tokens inside it
+func makeSnippet(m *docMatch) SearchSnippet {
+	text := m.doc.Text
+	tokens := tokenize(text)
+
+	first := -1
+	for pos := range m.positions {
+		if pos < len(tokens) && (first < 0 || pos < first) {
+			first = pos
+		}
+	}
+
+	begin, end := 0, len(text)
+	if first >= 0 {
+		begin = tokens[first].start - snippetLeadIn
+		if begin <= 0 {
+			begin = 0
+		} else if i := strings.IndexAny(text[begin:tokens[first].start], " \t\n"); i >= 0 {
+			begin += i + 1
+		}
+		for begin > 0 && !utf8.RuneStart(text[begin]) {
+			begin++
+		}
+
+		end = begin + snippetLength
+		if end < tokens[first].end {
+			end = tokens[first].end
+		}
+		if end >= len(text) {
+			end = len(text)
+		} else {
+			if i := strings.LastIndexAny(text[tokens[first].end:end], " \t\n"); i >= 0 {
+				end = tokens[first].end + i
+			}
+			for end < len(text) && !utf8.RuneStart(text[end]) {
+				end++
+			}
+		}
+	}
+
+	snippet := SearchSnippet{
+		MessageID:  m.doc.MessageID,
+		Field:      m.doc.Field,
+		Archived:   m.doc.Archived,
+		Timestamp:  m.doc.Timestamp,
+		Text:       text[begin:end],
+		Highlights: []SearchHighlight{},
+	}
+
+	// Adjacent matched tokens, such as a phrase, form one highlight
+	for pos := 0; pos < len(tokens); pos++ {
+		tok := tokens[pos]
+		if !m.positions[pos] || tok.start < begin || tok.end > end {
+			continue
+		}
+		hlEnd := tok.end
+		for pos+1 < len(tokens) && m.positions[pos+1] && tokens[pos+1].end <= end {
+			pos++
+			hlEnd = tokens[pos].end
+		}
+		snippet.Highlights = append(snippet.Highlights, SearchHighlight{
+			Start: utf16Len(text[begin:tok.start]),
+			End:   utf16Len(text[begin:hlEnd]),
+		})
+	}
+
+	return snippet
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
 // GetAllTags returns all unique tags across all sessions
 func GetAllTags() ([]string, error) {
 	return getAllTagsWithLoader(defaultSessionLoader)
@@ -0,0 +1,794 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// searchIndexVersion is bumped whenever the on-disk index format changes;
+	// an index with another version is rebuilt from the session files
+	searchIndexVersion = 1
+
+	// maxIndexedText caps how much of a single message or tool call is indexed
+	maxIndexedText = 32 * 1024
+
+	// maxTermLength skips tokens that are unlikely to be searched for, such as
+	// hashes and encoded blobs
+	maxTermLength = 64
+
+	// segmentCompactMin is how many superseded records a session's segment must
+	// carry before it is rewritten
+	segmentCompactMin = 64
+)
+
+// Fields a search can match, reported in snippets
+const (
+	SearchFieldContent    = "content"
+	SearchFieldToolInput  = "toolInput"
+	SearchFieldToolOutput = "toolOutput"
+	SearchFieldProject    = "project"
+	SearchFieldTag        = "tag"
+)
+
+// SearchIndex is an inverted index over session messages, tool calls, tags, and
+// project paths. Each session's documents are kept in an append-only segment
+// file under <sessions>/index, with session metadata in sessions.json. The
+// index is loaded once and kept up to date as sessions are saved.
+type SearchIndex struct {
+	sessionsDir string
+	dir         string // Empty for an in-memory index
+
+	mu          sync.RWMutex
+	sessions    map[string]*indexedSession
+	postings    map[string]map[*indexDoc]struct{}
+	docCount    int
+	totalLength int
+	sortedTerms []string // Rebuilt on demand after terms change
+}
+
+// indexedSessionMeta is the part of a session's header the index filters and
+// ranks on
+type indexedSessionMeta struct {
+	ID           string        `json:"id"`
+	ProjectPath  string        `json:"projectPath"`
+	Tags         []string      `json:"tags,omitempty"`
+	Model        string        `json:"model,omitempty"`
+	IsFavorite   bool          `json:"isFavorite,omitempty"`
+	Status       SessionStatus `json:"status"`
+	CreatedAt    string        `json:"createdAt"`
+	UpdatedAt    string        `json:"updatedAt"`
+	MessageCount int           `json:"messageCount"`
+	Records      int           `json:"records"` // Lines in the session's segment file
+}
+
+type indexedSession struct {
+	meta      indexedSessionMeta
+	createdAt time.Time
+	updatedAt time.Time
+	docs      map[string]*indexDoc
+	fieldDocs []*indexDoc // Project path and tags, derived from meta
+	stale     bool        // Segment could not be read and must be rebuilt
+}
+
+// indexDoc is one searchable piece of text: a message's content, a tool call's
+// input or output, a project path, or a tag. Terms maps each term to its token
+// positions in Text.
+type indexDoc struct {
+	Key       string           `json:"k"`
+	MessageID string           `json:"m,omitempty"`
+	Field     string           `json:"f"`
+	Archived  bool             `json:"a,omitempty"`
+	Timestamp time.Time        `json:"t"`
+	Text      string           `json:"x"`
+	Terms     map[string][]int `json:"p"`
+	Length    int              `json:"n"`
+	Hash      uint64           `json:"h"`
+
+	session   *indexedSession
+	persisted bool // This version of the doc is in the segment file
+}
+
+// segmentRecord is one line of a session's segment file
+type segmentRecord struct {
+	Add    *indexDoc `json:"add,omitempty"`
+	Delete string    `json:"del,omitempty"`
+}
+
+// indexMetaFile is the layout of sessions.json
+type indexMetaFile struct {
+	Version  int                  `json:"version"`
+	Sessions []indexedSessionMeta `json:"sessions"`
+}
+
+var (
+	searchIndexMu sync.Mutex
+	searchIndex   *SearchIndex
+)
+
+// openSearchIndex returns the search index for the sessions directory, loading
+// it from disk on first use
+func openSearchIndex() (*SearchIndex, error) {
+	sessionsDir, err := GetSessionsDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions directory: %w", err)
+	}
+
+	searchIndexMu.Lock()
+	defer searchIndexMu.Unlock()
+
+	if searchIndex != nil && searchIndex.sessionsDir == sessionsDir {
+		return searchIndex, nil
+	}
+
+	index, err := loadSearchIndex(sessionsDir)
+	if err != nil {
+		return nil, err
+	}
+	searchIndex = index
+	return index, nil
+}
+
+// loadedSearchIndex returns the search index if it has already been loaded
+func loadedSearchIndex() *SearchIndex {
+	searchIndexMu.Lock()
+	defer searchIndexMu.Unlock()
+	return searchIndex
+}
+
+func newSearchIndex(sessionsDir, dir string) *SearchIndex {
+	return &SearchIndex{
+		sessionsDir: sessionsDir,
+		dir:         dir,
+		sessions:    make(map[string]*indexedSession),
+		postings:    make(map[string]map[*indexDoc]struct{}),
+	}
+}
+
+// loadSearchIndex reads the index for sessionsDir and brings it up to date
+// with the session files, reindexing any session that changed while the index
+// was not loaded
+func loadSearchIndex(sessionsDir string) (*SearchIndex, error) {
+	dir := filepath.Join(sessionsDir, "index")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create search index directory: %w", err)
+	}
+
+	ix := newSearchIndex(sessionsDir, dir)
+
+	var metaFile indexMetaFile
+	if raw, err := os.ReadFile(filepath.Join(dir, "sessions.json")); err == nil {
+		if err := json.Unmarshal(raw, &metaFile); err != nil || metaFile.Version != searchIndexVersion {
+			metaFile = indexMetaFile{}
+		}
+	}
+
+	for _, meta := range metaFile.Sessions {
+		sess := ix.session(meta.ID)
+		ix.setMeta(sess, meta)
+		if err := ix.readSegment(sess); err != nil {
+			fmt.Printf("Warning: search index for session %s is damaged, rebuilding: %v\n", meta.ID, err)
+			sess.stale = true
+		}
+	}
+
+	if err := ix.syncWithSessions(); err != nil {
+		return nil, err
+	}
+	return ix, nil
+}
+
+// syncWithSessions reindexes sessions whose header no longer matches the index
+// and drops sessions that were deleted
+func (ix *SearchIndex) syncWithSessions() error {
+	entries, err := os.ReadDir(ix.sessionsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read sessions directory: %w", err)
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	seen := make(map[string]bool)
+	changed := false
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		raw, err := os.ReadFile(filepath.Join(ix.sessionsDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var data SessionData
+		if err := json.Unmarshal(raw, &data); err != nil || data.ID == "" {
+			continue
+		}
+		seen[data.ID] = true
+
+		count := len(data.Messages)
+		if data.Log != nil {
+			count = data.Log.MessageCount()
+		}
+
+		sess := ix.sessions[data.ID]
+		if sess != nil && !sess.stale && sess.meta.UpdatedAt == data.UpdatedAt && sess.meta.MessageCount == count {
+			continue
+		}
+
+		messages := data.Messages
+		if data.Log != nil {
+			messages, err = readMessageLog(ix.sessionsDir, data.Log, 0, count)
+			if err != nil {
+				fmt.Printf("Warning: failed to index session %s: %v\n", data.ID, err)
+				continue
+			}
+		}
+		archived, err := LoadArchivedMessages(data.ID)
+		if err != nil {
+			fmt.Printf("Warning: failed to index archived messages of session %s: %v\n", data.ID, err)
+		}
+
+		if err := ix.replaceSession(data, messages, archived); err != nil {
+			return err
+		}
+		changed = true
+	}
+
+	for id := range ix.sessions {
+		if !seen[id] {
+			ix.dropSession(id)
+			changed = true
+		}
+	}
+
+	if changed {
+		return ix.writeMeta()
+	}
+	return nil
+}
+
+// UpdateSession indexes a saved session. Messages that changed since the last
+// update are reindexed and messages that are gone are dropped, unless they
+// were archived. Without messages only the session's metadata is updated.
+func (ix *SearchIndex) UpdateSession(data SessionData, messages []Message, withMessages bool) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	sess := ix.session(data.ID)
+	meta := metaFromData(data)
+	meta.Records = sess.meta.Records
+	if withMessages {
+		meta.MessageCount = len(messages)
+	} else if data.Log != nil {
+		meta.MessageCount = data.Log.MessageCount()
+	}
+
+	var records []segmentRecord
+	if withMessages {
+		live := make(map[string]bool)
+		for _, msg := range messages {
+			for _, doc := range messageDocs(msg, false) {
+				live[doc.Key] = true
+				existing := sess.docs[doc.Key]
+				if existing != nil && existing.Hash == doc.Hash && !existing.Archived {
+					if !existing.persisted {
+						existing.persisted = true
+						records = append(records, segmentRecord{Add: existing})
+					}
+					continue
+				}
+				doc.persisted = true
+				ix.addDoc(sess, doc)
+				records = append(records, segmentRecord{Add: doc})
+			}
+		}
+
+		for key, doc := range sess.docs {
+			if live[key] || doc.Archived {
+				continue
+			}
+			if doc.persisted {
+				records = append(records, segmentRecord{Delete: key})
+			}
+			ix.removeDoc(doc)
+		}
+	}
+
+	ix.setMeta(sess, meta)
+	if err := ix.writeRecords(sess, records); err != nil {
+		return err
+	}
+	return ix.writeMeta()
+}
+
+// AddArchived indexes messages moved to a session's archive
+func (ix *SearchIndex) AddArchived(sessionID string, messages []Message) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	sess := ix.session(sessionID)
+	var records []segmentRecord
+	for _, msg := range messages {
+		for _, doc := range messageDocs(msg, true) {
+			doc.persisted = true
+			ix.addDoc(sess, doc)
+			records = append(records, segmentRecord{Add: doc})
+		}
+	}
+
+	if err := ix.writeRecords(sess, records); err != nil {
+		return err
+	}
+	return ix.writeMeta()
+}
+
+// IndexMessage makes a message searchable as soon as it arrives. It is written
+// to disk when the session is next saved.
+func (ix *SearchIndex) IndexMessage(sessionID string, msg Message) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	sess := ix.session(sessionID)
+	for _, doc := range messageDocs(msg, false) {
+		if existing := sess.docs[doc.Key]; existing != nil && existing.Hash == doc.Hash {
+			continue
+		}
+		ix.addDoc(sess, doc)
+	}
+}
+
+// RemoveSession drops a deleted session from the index
+func (ix *SearchIndex) RemoveSession(sessionID string) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	if ix.sessions[sessionID] == nil {
+		return nil
+	}
+	ix.dropSession(sessionID)
+	return ix.writeMeta()
+}
+
+// replaceSession indexes a session from scratch and rewrites its segment.
+// Note: This method expects the caller to hold ix.mu lock
+func (ix *SearchIndex) replaceSession(data SessionData, messages, archived []Message) error {
+	if sess := ix.sessions[data.ID]; sess != nil {
+		for _, doc := range sess.docs {
+			ix.removeDoc(doc)
+		}
+	}
+
+	sess := ix.session(data.ID)
+	sess.stale = false
+	meta := metaFromData(data)
+	meta.MessageCount = len(messages)
+	ix.setMeta(sess, meta)
+
+	for _, msg := range archived {
+		for _, doc := range messageDocs(msg, true) {
+			ix.addDoc(sess, doc)
+		}
+	}
+	for _, msg := range messages {
+		for _, doc := range messageDocs(msg, false) {
+			ix.addDoc(sess, doc)
+		}
+	}
+
+	return ix.writeSegment(sess)
+}
+
+// dropSession removes a session and its segment file.
+// Note: This method expects the caller to hold ix.mu lock
+func (ix *SearchIndex) dropSession(sessionID string) {
+	sess := ix.sessions[sessionID]
+	for _, doc := range sess.docs {
+		ix.removeDoc(doc)
+	}
+	for _, doc := range sess.fieldDocs {
+		ix.removeDoc(doc)
+	}
+	delete(ix.sessions, sessionID)
+
+	if ix.dir != "" {
+		os.Remove(ix.segmentPath(sessionID))
+	}
+}
+
+// session returns the indexed session with the given ID, creating it if needed.
+// Note: This method expects the caller to hold ix.mu lock
+func (ix *SearchIndex) session(sessionID string) *indexedSession {
+	sess := ix.sessions[sessionID]
+	if sess == nil {
+		sess = &indexedSession{
+			meta: indexedSessionMeta{ID: sessionID},
+			docs: make(map[string]*indexDoc),
+		}
+		ix.sessions[sessionID] = sess
+	}
+	return sess
+}
+
+// setMeta updates a session's metadata and the documents derived from it.
+// Note: This method expects the caller to hold ix.mu lock
+func (ix *SearchIndex) setMeta(sess *indexedSession, meta indexedSessionMeta) {
+	for _, doc := range sess.fieldDocs {
+		ix.removeDoc(doc)
+	}
+	sess.fieldDocs = nil
+
+	sess.meta = meta
+	sess.createdAt, _ = parseTimestamp(meta.CreatedAt)
+	sess.updatedAt, _ = parseTimestamp(meta.UpdatedAt)
+
+	if meta.ProjectPath != "" {
+		doc := newIndexDoc(SearchFieldProject, SearchFieldProject, meta.ProjectPath)
+		ix.linkDoc(sess, doc)
+		sess.fieldDocs = append(sess.fieldDocs, doc)
+	}
+	for _, tag := range meta.Tags {
+		doc := newIndexDoc(SearchFieldTag+":"+tag, SearchFieldTag, tag)
+		ix.linkDoc(sess, doc)
+		sess.fieldDocs = append(sess.fieldDocs, doc)
+	}
+}
+
+// addDoc adds or replaces one of a session's message documents.
+// Note: This method expects the caller to hold ix.mu lock
+func (ix *SearchIndex) addDoc(sess *indexedSession, doc *indexDoc) {
+	if existing := sess.docs[doc.Key]; existing != nil {
+		ix.removeDoc(existing)
+	}
+	sess.docs[doc.Key] = doc
+	ix.linkDoc(sess, doc)
+}
+
+// linkDoc adds a document's terms to the postings.
+// Note: This method expects the caller to hold ix.mu lock
+func (ix *SearchIndex) linkDoc(sess *indexedSession, doc *indexDoc) {
+	doc.session = sess
+	for term := range doc.Terms {
+		docs := ix.postings[term]
+		if docs == nil {
+			docs = make(map[*indexDoc]struct{})
+			ix.postings[term] = docs
+			ix.sortedTerms = nil
+		}
+		docs[doc] = struct{}{}
+	}
+	ix.docCount++
+	ix.totalLength += doc.Length
+}
+
+// removeDoc removes a document from its session and the postings.
+// Note: This method expects the caller to hold ix.mu lock
+func (ix *SearchIndex) removeDoc(doc *indexDoc) {
+	if doc.session != nil && doc.session.docs[doc.Key] == doc {
+		delete(doc.session.docs, doc.Key)
+	}
+	for term := range doc.Terms {
+		docs := ix.postings[term]
+		delete(docs, doc)
+		if len(docs) == 0 {
+			delete(ix.postings, term)
+			ix.sortedTerms = nil
+		}
+	}
+	ix.docCount--
+	ix.totalLength -= doc.Length
+}
+
+// termsWithPrefix returns the indexed terms starting with prefix.
+// Note: This method expects the caller to hold ix.mu lock
+func (ix *SearchIndex) termsWithPrefix(prefix string) []string {
+	if ix.sortedTerms == nil {
+		ix.sortedTerms = make([]string, 0, len(ix.postings))
+		for term := range ix.postings {
+			ix.sortedTerms = append(ix.sortedTerms, term)
+		}
+		sort.Strings(ix.sortedTerms)
+	}
+
+	var terms []string
+	for i := sort.SearchStrings(ix.sortedTerms, prefix); i < len(ix.sortedTerms); i++ {
+		if !strings.HasPrefix(ix.sortedTerms[i], prefix) {
+			break
+		}
+		terms = append(terms, ix.sortedTerms[i])
+	}
+	return terms
+}
+
+func (ix *SearchIndex) segmentPath(sessionID string) string {
+	return filepath.Join(ix.dir, sessionID+".seg")
+}
+
+// readSegment replays a session's segment file.
+// Note: This method expects the caller to hold ix.mu lock
+func (ix *SearchIndex) readSegment(sess *indexedSession) error {
+	f, err := os.Open(ix.segmentPath(sess.meta.ID))
+	if os.IsNotExist(err) && sess.meta.Records == 0 {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	records := 0
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			break
+		}
+		var record segmentRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return fmt.Errorf("record %d: %w", records, err)
+		}
+		records++
+
+		if record.Add != nil {
+			record.Add.persisted = true
+			ix.addDoc(sess, record.Add)
+		} else if doc := sess.docs[record.Delete]; doc != nil {
+			ix.removeDoc(doc)
+		}
+	}
+
+	if records != sess.meta.Records {
+		return fmt.Errorf("expected %d records, found %d", sess.meta.Records, records)
+	}
+	return nil
+}
+
+// writeRecords appends records to a session's segment, or rewrites the
+// segment once superseded records dominate it.
+// Note: This method expects the caller to hold ix.mu lock
+func (ix *SearchIndex) writeRecords(sess *indexedSession, records []segmentRecord) error {
+	if ix.dir == "" || len(records) == 0 {
+		return nil
+	}
+
+	superseded := sess.meta.Records + len(records) - len(sess.docs)
+	if superseded >= segmentCompactMin && superseded > len(sess.docs) {
+		return ix.writeSegment(sess)
+	}
+
+	var buf bytes.Buffer
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal search index record: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	f, err := os.OpenFile(ix.segmentPath(sess.meta.ID), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open search index segment: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to append to search index segment: %w", err)
+	}
+
+	sess.meta.Records += len(records)
+	return nil
+}
+
+// writeSegment rewrites a session's segment with its current documents.
+// Note: This method expects the caller to hold ix.mu lock
+func (ix *SearchIndex) writeSegment(sess *indexedSession) error {
+	if ix.dir == "" {
+		return nil
+	}
+
+	keys := make([]string, 0, len(sess.docs))
+	for key := range sess.docs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	for _, key := range keys {
+		doc := sess.docs[key]
+		line, err := json.Marshal(segmentRecord{Add: doc})
+		if err != nil {
+			return fmt.Errorf("failed to marshal search index record: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+		doc.persisted = true
+	}
+
+	if err := writeFileAtomic(ix.segmentPath(sess.meta.ID), buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write search index segment: %w", err)
+	}
+	sess.meta.Records = len(keys)
+	return nil
+}
+
+// writeMeta replaces sessions.json.
+// Note: This method expects the caller to hold ix.mu lock
+func (ix *SearchIndex) writeMeta() error {
+	if ix.dir == "" {
+		return nil
+	}
+
+	metaFile := indexMetaFile{Version: searchIndexVersion}
+	for _, sess := range ix.sessions {
+		metaFile.Sessions = append(metaFile.Sessions, sess.meta)
+	}
+	sort.Slice(metaFile.Sessions, func(i, j int) bool {
+		return metaFile.Sessions[i].ID < metaFile.Sessions[j].ID
+	})
+
+	raw, err := json.Marshal(metaFile)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search index: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(ix.dir, "sessions.json"), raw); err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	return nil
+}
+
+func metaFromData(data SessionData) indexedSessionMeta {
+	return indexedSessionMeta{
+		ID:           data.ID,
+		ProjectPath:  data.ProjectPath,
+		Tags:         append([]string(nil), data.Tags...),
+		Model:        data.Model,
+		IsFavorite:   data.IsFavorite,
+		Status:       data.Status,
+		CreatedAt:    data.CreatedAt,
+		UpdatedAt:    data.UpdatedAt,
+		MessageCount: len(data.Messages),
+	}
+}
+
+// messageDocs splits a message into its searchable documents
+func messageDocs(msg Message, archived bool) []*indexDoc {
+	var docs []*indexDoc
+	add := func(field, text string) {
+		if strings.TrimSpace(text) == "" {
+			return
+		}
+		doc := newIndexDoc(msg.ID+"#"+field, field, text)
+		doc.MessageID = msg.ID
+		doc.Archived = archived
+		doc.Timestamp = msg.Timestamp
+		docs = append(docs, doc)
+	}
+
+	add(SearchFieldContent, msg.Content)
+	if msg.Metadata != nil {
+		if toolUse := msg.Metadata.ToolUse; toolUse != nil {
+			add(SearchFieldToolInput, toolUse.ToolName+" "+flattenToolInput(toolUse.Input))
+		}
+		if toolResult := msg.Metadata.ToolResult; toolResult != nil {
+			add(SearchFieldToolOutput, toolResult.Content)
+		}
+	}
+	return docs
+}
+
+func newIndexDoc(key, field, text string) *indexDoc {
+	if len(text) > maxIndexedText {
+		cut := maxIndexedText
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		text = text[:cut]
+	}
+
+	tokens := tokenize(text)
+	terms := make(map[string][]int)
+	for i, tok := range tokens {
+		terms[tok.term] = append(terms[tok.term], i)
+	}
+
+	return &indexDoc{
+		Key:    key,
+		Field:  field,
+		Text:   text,
+		Terms:  terms,
+		Length: len(tokens),
+		Hash:   hashLine([]byte(text)),
+	}
+}
+
+// flattenToolInput joins the values of a tool's JSON input, so searches match
+// commands and paths rather than JSON keys
+func flattenToolInput(input json.RawMessage) string {
+	var value interface{}
+	if err := json.Unmarshal(input, &value); err != nil {
+		return string(input)
+	}
+
+	var parts []string
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				walk(v[key])
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		case string:
+			parts = append(parts, v)
+		case float64, bool:
+			parts = append(parts, fmt.Sprint(v))
+		}
+	}
+	walk(value)
+	return strings.Join(parts, "\n")
+}
+
+// token is a lowercased word and its byte range in the source text
+type token struct {
+	term       string
+	start, end int
+}
+
+// tokenize splits text into lowercased runs of letters and digits. Tokens
+// longer than maxTermLength are dropped.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = appendToken(tokens, text, start, i)
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = appendToken(tokens, text, start, len(text))
+	}
+	return tokens
+}
+
+func appendToken(tokens []token, text string, start, end int) []token {
+	if utf8.RuneCountInString(text[start:end]) > maxTermLength {
+		return tokens
+	}
+	return append(tokens, token{term: strings.ToLower(text[start:end]), start: start, end: end})
+}
+
+// indexSavedSession updates the search index after a session is saved
+func indexSavedSession(data SessionData, messages []Message, withMessages bool) {
+	index, err := openSearchIndex()
+	if err == nil {
+		err = index.UpdateSession(data, messages, withMessages)
+	}
+	if err != nil {
+		fmt.Printf("Warning: failed to update search index for session %s: %v\n", data.ID, err)
+	}
+}
@@ -0,0 +1,360 @@
+package agent
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+// reloadSearchIndex drops the loaded index so the next search reads it from disk
+func reloadSearchIndex() {
+	searchIndexMu.Lock()
+	searchIndex = nil
+	searchIndexMu.Unlock()
+}
+
+func highlighted(snippet SearchSnippet) []string {
+	text := utf16.Encode([]rune(snippet.Text))
+	var parts []string
+	for _, hl := range snippet.Highlights {
+		parts = append(parts, string(utf16.Decode(text[hl.Start:hl.End])))
+	}
+	return parts
+}
+
+func saveIndexedSession(t *testing.T, id, projectPath string, messages ...Message) *Session {
+	t.Helper()
+	session := NewSession(id, projectPath)
+	session.Messages = append(session.Messages, messages...)
+	if err := SaveSession(session); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+	return session
+}
+
+func search(t *testing.T, query string) []*SearchResult {
+	t.Helper()
+	results, err := SearchSessions(SearchFilter{Query: query})
+	if err != nil {
+		t.Fatalf("SearchSessions(%q) error = %v", query, err)
+	}
+	return results
+}
+
+func TestSearchIndex_MessagesAndToolCalls(t *testing.T) {
+	useTempSessionsDir(t)
+
+	saveIndexedSession(t, "auth-session", "/work/api",
+		Message{ID: "m1", Role: "user", Content: "Fix the authentication bug in login"},
+		Message{ID: "m2", Role: "assistant", Content: "Running tests", Metadata: &MessageMetadata{
+			ToolUse: &ToolUse{ToolName: "Bash", ToolID: "t1", Input: json.RawMessage(`{"command":"go test ./handlers/..."}`)},
+		}},
+		Message{ID: "m3", Role: "system", Metadata: &MessageMetadata{
+			ToolResult: &ToolResult{ToolID: "t1", Content: "FAIL session_store_test.go:42"},
+		}},
+	)
+	saveIndexedSession(t, "other-session", "/work/web",
+		Message{ID: "m1", Role: "user", Content: "Authentication should log the bug"},
+	)
+
+	tests := []struct {
+		name      string
+		query     string
+		sessions  []string
+		field     string
+		highlight []string
+	}{
+		{"terms", "authentication bug", []string{"auth-session", "other-session"}, SearchFieldContent, nil},
+		{"phrase", `"authentication bug"`, []string{"auth-session"}, SearchFieldContent, []string{"authentication bug"}},
+		{"reversed phrase", `"bug authentication"`, nil, "", nil},
+		{"prefix", "authent", []string{"auth-session", "other-session"}, SearchFieldContent, nil},
+		{"tool input", "handlers", []string{"auth-session"}, SearchFieldToolInput, []string{"handlers"}},
+		{"tool output", "session_store_test", []string{"auth-session"}, SearchFieldToolOutput, []string{"session_store_test"}},
+		{"project path", "web", []string{"other-session"}, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := search(t, tt.query)
+			if len(results) != len(tt.sessions) {
+				t.Fatalf("Expected %d results, got %d", len(tt.sessions), len(results))
+			}
+			found := make(map[string]bool)
+			for _, result := range results {
+				found[result.Session.ID] = true
+			}
+			for _, id := range tt.sessions {
+				if !found[id] {
+					t.Errorf("Expected session %s in results", id)
+				}
+			}
+
+			if tt.field == "" {
+				return
+			}
+			var snippet *SearchSnippet
+			for _, result := range results {
+				if result.Session.ID == tt.sessions[0] && len(result.Snippets) > 0 {
+					snippet = &result.Snippets[0]
+				}
+			}
+			if snippet == nil {
+				t.Fatal("Expected a snippet")
+			}
+			if snippet.Field != tt.field {
+				t.Errorf("Expected snippet field %s, got %s", tt.field, snippet.Field)
+			}
+			if tt.highlight != nil {
+				got := highlighted(*snippet)
+				if len(got) != len(tt.highlight) || got[0] != tt.highlight[0] {
+					t.Errorf("Expected highlights %v, got %v", tt.highlight, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchIndex_FieldFilters(t *testing.T) {
+	useTempSessionsDir(t)
+
+	old := NewSession("old-session", "/work/boatman")
+	old.Model = "sonnet"
+	old.Tags = []string{"bug"}
+	old.Messages = []Message{{ID: "m1", Role: "user", Content: "Refactor the parser"}}
+	old.UpdatedAt = time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	if err := SaveSession(old); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+
+	recent := NewSession("recent-session", "/work/My App")
+	recent.Model = "opus"
+	recent.Tags = []string{"feature"}
+	recent.Messages = []Message{{ID: "m1", Role: "user", Content: "Refactor the renderer"}}
+	recent.UpdatedAt = time.Date(2026, 5, 2, 12, 0, 0, 0, time.Local)
+	if err := SaveSession(recent); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+
+	tests := []struct {
+		query    string
+		expected string // Empty for no results
+		count    int
+	}{
+		{"refactor tag:bug", "old-session", 1},
+		{"refactor tag:BUG", "old-session", 1},
+		{"tag:feature", "recent-session", 1},
+		{"project:boatman", "old-session", 1},
+		{`project:"my app" refactor`, "recent-session", 1},
+		{"model:opus", "recent-session", 1},
+		{"refactor model:haiku", "", 0},
+		{"after:2026-04-01", "recent-session", 1},
+		{"before:2026-04-01", "old-session", 1},
+		{"date:2026-03-10", "old-session", 1},
+		{"date:2026-03-01..2026-05-02", "", 2},
+		{"date:..2026-03-09", "", 0},
+		{"after:yesterday", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results := search(t, tt.query)
+			if len(results) != tt.count {
+				t.Fatalf("Expected %d results, got %d", tt.count, len(results))
+			}
+			if tt.expected != "" && results[0].Session.ID != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, results[0].Session.ID)
+			}
+		})
+	}
+}
+
+func TestSearchIndex_IncrementalAndPersistent(t *testing.T) {
+	dir := useTempSessionsDir(t)
+
+	session := saveIndexedSession(t, "live-session", "/work/api",
+		Message{ID: "m1", Role: "user", Content: "Investigate the flaky scheduler"},
+	)
+
+	// Messages are searchable as they arrive, before the next save
+	index, err := openSearchIndex()
+	if err != nil {
+		t.Fatalf("openSearchIndex() error = %v", err)
+	}
+	msg := Message{ID: "m2", Role: "assistant", Content: "The watchdog timer races with shutdown"}
+	session.Messages = append(session.Messages, msg)
+	index.IndexMessage(session.ID, msg)
+	if results := search(t, "watchdog"); len(results) != 1 {
+		t.Fatalf("Expected live message to be searchable, got %d results", len(results))
+	}
+
+	if err := SaveSession(session); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+	segment := filepath.Join(dir, "index", session.ID+".seg")
+	info, err := os.Stat(segment)
+	if err != nil {
+		t.Fatalf("Expected segment file: %v", err)
+	}
+
+	// Unchanged messages are not written again
+	if err := SaveSession(session); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+	if after, _ := os.Stat(segment); after.Size() != info.Size() {
+		t.Errorf("Segment grew from %d to %d without new messages", info.Size(), after.Size())
+	}
+
+	// Edited messages are reindexed
+	session.Messages[0].Content = "Investigate the flaky dispatcher"
+	if err := SaveSession(session); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+	if results := search(t, "scheduler"); len(results) != 0 {
+		t.Errorf("Expected edited text to be gone, got %d results", len(results))
+	}
+
+	// A reloaded index answers from its own files, not the session logs
+	reloadSearchIndex()
+	logs, _ := filepath.Glob(filepath.Join(dir, session.ID+".*.log"))
+	for _, logFile := range logs {
+		os.Remove(logFile)
+	}
+	for _, query := range []string{"dispatcher", "watchdog"} {
+		if results := search(t, query); len(results) != 1 {
+			t.Errorf("Expected %q to match after reload, got %d results", query, len(results))
+		}
+	}
+
+	// Deleted sessions drop out of the index
+	if err := DeleteSessionFile(session.ID); err != nil {
+		t.Fatalf("DeleteSessionFile() error = %v", err)
+	}
+	if results := search(t, "watchdog"); len(results) != 0 {
+		t.Errorf("Expected deleted session to be gone, got %d results", len(results))
+	}
+	if _, err := os.Stat(segment); !os.IsNotExist(err) {
+		t.Error("Expected segment file to be removed")
+	}
+}
+
+func TestSearchIndex_ArchivedMessages(t *testing.T) {
+	useTempSessionsDir(t)
+
+	session := NewSession("archive-session", "/work/api")
+	for _, content := range []string{"Migrate the billing tables", "Then update the invoices", "Finally clean up"} {
+		session.Messages = append(session.Messages, Message{ID: "msg-" + content[:4], Role: "user", Content: content})
+	}
+
+	session.mu.Lock()
+	session.TrimMessagesIfNeeded(1, true)
+	session.mu.Unlock()
+	if err := SaveSession(session); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+
+	results := search(t, "billing")
+	if len(results) != 1 || len(results[0].Snippets) != 1 || !results[0].Snippets[0].Archived {
+		t.Fatalf("Expected an archived match, got %+v", results)
+	}
+
+	// Archived messages survive a rebuild from the session files
+	reloadSearchIndex()
+	sessionsDir, _ := GetSessionsDir()
+	os.RemoveAll(filepath.Join(sessionsDir, "index"))
+	results = search(t, "invoices")
+	if len(results) != 1 || !results[0].Snippets[0].Archived {
+		t.Fatalf("Expected archived match after rebuild, got %d results", len(results))
+	}
+	if results[0].MessageCount != 1 {
+		t.Errorf("Expected 1 live message, got %d", results[0].MessageCount)
+	}
+}
+
+func TestSearchIndex_RebuildsDamagedSegment(t *testing.T) {
+	dir := useTempSessionsDir(t)
+
+	saveIndexedSession(t, "damaged-session", "/work/api",
+		Message{ID: "m1", Role: "user", Content: "Tune the connection pool"},
+	)
+	reloadSearchIndex()
+
+	segment := filepath.Join(dir, "index", "damaged-session.seg")
+	f, err := os.OpenFile(segment, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open segment: %v", err)
+	}
+	f.WriteString(`{"add":{"k":"m2#con`)
+	f.Close()
+
+	if results := search(t, "connection pool"); len(results) != 1 {
+		t.Fatalf("Expected rebuilt index to match, got %d results", len(results))
+	}
+	data, _ := os.ReadFile(segment)
+	var record segmentRecord
+	for _, line := range splitLines(data) {
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Errorf("Segment still has a damaged record: %s", line)
+		}
+	}
+}
+
+func TestSearchIndex_HighlightOffsetsAreUTF16(t *testing.T) {
+	useTempSessionsDir(t)
+
+	saveIndexedSession(t, "unicode-session", "/work/api",
+		Message{ID: "m1", Role: "user", Content: "Übung 🚀 ready to deploy the naïve build"},
+	)
+
+	results := search(t, "deploy naïve")
+	if len(results) != 1 || len(results[0].Snippets) != 1 {
+		t.Fatalf("Expected one snippet, got %+v", results)
+	}
+	got := highlighted(results[0].Snippets[0])
+	if len(got) != 2 || got[0] != "deploy" || got[1] != "naïve" {
+		t.Errorf("Expected highlights [deploy naïve], got %v", got)
+	}
+}
+
+func TestParseSearchQuery(t *testing.T) {
+	query := parseSearchQuery(`fix "race condition" tag:bug project:"my app" model:opus snake_case`)
+
+	if len(query.clauses) != 3 {
+		t.Fatalf("Expected 3 clauses, got %+v", query.clauses)
+	}
+	if !query.clauses[0].prefix || query.clauses[0].terms[0] != "fix" {
+		t.Errorf("Expected prefix term 'fix', got %+v", query.clauses[0])
+	}
+	if query.clauses[1].prefix || len(query.clauses[1].terms) != 2 {
+		t.Errorf("Expected phrase 'race condition', got %+v", query.clauses[1])
+	}
+	if query.clauses[2].prefix || len(query.clauses[2].terms) != 2 {
+		t.Errorf("Expected snake_case as a phrase, got %+v", query.clauses[2])
+	}
+	if len(query.tags) != 1 || query.tags[0] != "bug" {
+		t.Errorf("Expected tag filter, got %v", query.tags)
+	}
+	if len(query.projects) != 1 || query.projects[0] != "my app" {
+		t.Errorf("Expected project filter 'my app', got %v", query.projects)
+	}
+	if len(query.models) != 1 || query.models[0] != "opus" {
+		t.Errorf("Expected model filter, got %v", query.models)
+	}
+}
+
+func splitLines(data []byte) [][]byte {
+	var lines [][]byte
+	start := 0
+	for i, b := range data {
+		if b == '\n' {
+			lines = append(lines, data[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(data) {
+		lines = append(lines, data[start:])
+	}
+	return lines
+}
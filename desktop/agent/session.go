@@ -20,6 +20,9 @@ const (
 	SessionStatusWaiting SessionStatus = "waiting"
 	SessionStatusError   SessionStatus = "error"
 	SessionStatusStopped SessionStatus = "stopped"
+	// SessionStatusInterrupted marks a session that was running when the app
+	// quit; it can be resumed (see recovery.go)
+	SessionStatusInterrupted SessionStatus = "interrupted"
 )
 
 // Message represents a chat message
@@ -43,10 +46,11 @@ type AgentInfo struct {
 
 // MessageMetadata contains additional message information
 type MessageMetadata struct {
-	ToolUse    *ToolUse    `json:"toolUse,omitempty"`
-	ToolResult *ToolResult `json:"toolResult,omitempty"`
-	CostInfo   *CostInfo   `json:"costInfo,omitempty"`
-	Agent      *AgentInfo  `json:"agent,omitempty"`
+	ToolUse    *ToolUse        `json:"toolUse,omitempty"`
+	ToolResult *ToolResult     `json:"toolResult,omitempty"`
+	CostInfo   *CostInfo       `json:"costInfo,omitempty"`
+	Agent      *AgentInfo      `json:"agent,omitempty"`
+	Compaction *CompactionInfo `json:"compaction,omitempty"`
 }
 
 // ToolUse represents a tool invocation by the agent
@@ -94,6 +98,9 @@ type Session struct {
 	IsFavorite  bool                   `json:"isFavorite,omitempty"`
 	Mode        string                 `json:"mode"` // "standard", "firefighter", "boatmanmode"
 	ModeConfig  map[string]interface{} `json:"modeConfig,omitempty"`
+	ParentID    string                 `json:"parentId,omitempty"`   // Session this one was forked from
+	ForkedFrom  string                 `json:"forkedFrom,omitempty"` // Parent message the fork starts after
+	ReadOnly    bool                   `json:"readOnly,omitempty"`   // Imported from a bundle (see runlog.go)
 
 	mu             sync.RWMutex
 	ctx            context.Context
@@ -102,6 +109,9 @@ type Session struct {
 	onTask         func(Task)
 	onStatus       func(SessionStatus)
 	conversationID string
+	// forkConversation makes the next run fork conversationID rather than
+	// continue it (see fork.go)
+	forkConversation bool
 	currentAgentID  string // Tracks which agent is currently active
 	agents          map[string]*AgentInfo // All known agents in this session
 	toolIDToAgentID map[string]string     // Maps Task tool_use ID -> spawned agent ID
@@ -118,8 +128,46 @@ type Session struct {
 	// System prompt for the session (Claude CLI -s flag)
 	systemPrompt string
 
+	// Context window usage and compaction (see context.go). compactSummary
+	// seeds the next conversation after a compaction.
+	contextTokens    int
+	contextWindow    int
+	compactThreshold float64
+	compacting       bool
+	compactions      int
+	compactSummary   string
+	onContext        func(ContextUsage)
+	summarizer       func(conversationID string, authConfig AuthConfig) (string, error)
+
+	// Subprocess running on the session's behalf, persisted so an
+	// interrupted run can be recovered (see recovery.go)
+	processPID     int
+	processCommand string
+
+	// Workflow events reported during boatman runs and not saved yet (see
+	// runlog.go); savedEvents counts those in the session's event log
+	events      []RunEvent
+	savedEvents int
+
 	// Firefighter monitoring
 	firefighterMonitor *FirefighterMonitor
+	onIncident         func(Incident)
+	incidentScanMu     sync.Mutex // Serializes incident report scans (see incidentreport.go)
+
+	// Tool approvals
+	approvalServer *ApprovalServer
+	approvalPolicy ApprovalPolicy
+	onApproval     func(PendingAction)
+	pendingActions []*pendingApproval
+
+	// Persistence (see messagelog.go). saveMu serializes saves and guards
+	// the log state; messagesOnDisk is set while a loaded session's
+	// messages have not been read yet.
+	saveMu         sync.Mutex
+	log            *MessageLogInfo
+	savedIDs       []string
+	savedHashes    []uint64
+	messagesOnDisk bool
 }
 
 // NewSession creates a new agent session
@@ -171,6 +219,13 @@ func (s *Session) SetStatusHandler(handler func(SessionStatus)) {
 	s.onStatus = handler
 }
 
+// SetIncidentHandler sets the callback for firefighter incident updates
+func (s *Session) SetIncidentHandler(handler func(Incident)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onIncident = handler
+}
+
 // SetTrimSettings configures message trimming behavior
 func (s *Session) SetTrimSettings(maxMessages int, archive bool) {
 	s.mu.Lock()
@@ -241,6 +296,8 @@ func (s *Session) ClearConversation() {
 
 	s.Messages = []Message{}
 	s.conversationID = ""
+	s.compactSummary = ""
+	s.contextTokens = 0
 	s.UpdatedAt = time.Now()
 
 	// Emit a system message about the clear
@@ -261,6 +318,28 @@ func (s *Session) GetConversationID() string {
 	return s.conversationID
 }
 
+// WaitUntilSettled blocks until the session is neither running nor waiting on
+// an approval, and returns the status it settled in
+func (s *Session) WaitUntilSettled(ctx context.Context) (SessionStatus, error) {
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		s.mu.RLock()
+		status := s.Status
+		s.mu.RUnlock()
+		if status != SessionStatusRunning && status != SessionStatusWaiting {
+			return status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
 // Stop terminates the agent session
 func (s *Session) Stop() error {
 	s.mu.Lock()
@@ -276,6 +355,13 @@ func (s *Session) Stop() error {
 
 // SendMessage sends a user message to the agent
 func (s *Session) SendMessage(content string, authConfig AuthConfig) error {
+	s.mu.RLock()
+	readOnly := s.ReadOnly
+	s.mu.RUnlock()
+	if readOnly {
+		return fmt.Errorf("imported sessions are read-only")
+	}
+
 	// Handle slash commands before anything else
 	trimmed := strings.TrimSpace(content)
 	if strings.HasPrefix(trimmed, "/") {
@@ -379,6 +465,12 @@ func (s *Session) handleSlashCommand(cmd string, authConfig AuthConfig) error {
 		s.addSystemMessage(fmt.Sprintf("System prompt set: %s", prompt))
 		return nil
 
+	case "/compact":
+		if err := s.Compact(authConfig); err != nil {
+			s.addSystemMessage(fmt.Sprintf("Cannot compact: %v", err))
+		}
+		return nil
+
 	case "/status":
 		s.mu.RLock()
 		convID := s.conversationID
@@ -387,9 +479,11 @@ func (s *Session) handleSlashCommand(cmd string, authConfig AuthConfig) error {
 		projectPath := s.ProjectPath
 		msgCount := len(s.Messages)
 		sysPrompt := s.systemPrompt
+		usage := s.contextUsage()
 		s.mu.RUnlock()
 
 		status := fmt.Sprintf("Project: %s\nModel: %s\nEffort: %s\nMessages: %d", projectPath, model, effort, msgCount)
+		status += fmt.Sprintf("\nContext: %d / %d tokens (%.0f%%)", usage.Tokens, usage.Window, usage.Percent)
 		if convID != "" {
 			status += fmt.Sprintf("\nConversation: %s", convID)
 		}
@@ -402,6 +496,7 @@ func (s *Session) handleSlashCommand(cmd string, authConfig AuthConfig) error {
 	case "/help":
 		s.addSystemMessage(`Available commands:
 /clear    — Reset conversation (start fresh)
+/compact  — Summarize the conversation and continue in a fresh context
 /model    — Change model (sonnet, opus, haiku)
 /system   — Set or view system prompt
 /status   — Show session info
@@ -471,6 +566,15 @@ func (s *Session) runClaudeCommand(prompt string, authConfig AuthConfig) {
 	}
 	s.mu.RUnlock()
 
+	// The first prompt after a compaction, or in a fork that could not fork
+	// the conversation, carries the summary of the conversation it replaces
+	s.mu.Lock()
+	if s.compactSummary != "" && s.conversationID == "" {
+		actualPrompt = compactSeed(s.compactSummary) + actualPrompt
+		s.compactSummary = ""
+	}
+	s.mu.Unlock()
+
 	// Build command arguments
 	args := []string{
 		"-p", actualPrompt,
@@ -486,6 +590,9 @@ func (s *Session) runClaudeCommand(prompt string, authConfig AuthConfig) {
 	// Add conversation resume if we have one
 	if s.conversationID != "" {
 		args = append(args, "-r", s.conversationID)
+		if s.forkConversation {
+			args = append(args, "--fork-session")
+		}
 	}
 
 	if s.Model != "" {
@@ -505,43 +612,32 @@ func (s *Session) runClaudeCommand(prompt string, authConfig AuthConfig) {
 	// that needs unrestricted access to MCP tools (Datadog, Bugsnag, Linear)
 	s.mu.RLock()
 	isFirefighter := s.Mode == "firefighter"
+	approvalServer := s.approvalServer
 	s.mu.RUnlock()
 
 	if isFirefighter {
 		args = append(args, "--dangerously-skip-permissions")
+	} else if approvalServer != nil {
+		// Tool calls that need approval are sent to the approval server,
+		// which holds them until the user approves or rejects them
+		args = append(args, permissionArgs(SessionOptions{
+			ApprovalMode:         authConfig.ApprovalMode,
+			PermissionPromptTool: ApprovalToolName,
+			PermissionMCPConfig:  approvalServer.MCPConfig(s.ID),
+		})...)
+	} else if authConfig.ApprovalMode == "suggest" {
+		// Without an approval server there is nothing to answer permission
+		// prompts, so skip them rather than hang
+		args = append(args, "--dangerously-skip-permissions")
 	} else {
-		switch authConfig.ApprovalMode {
-		case "auto-edit":
-			// Allow Edit and Write tools without approval
-			args = append(args, "--allowedTools", "Edit,Write")
-		case "full-auto":
-			// Allow all tools without approval
-			args = append(args, "--dangerously-skip-permissions")
-		case "suggest":
-			// This is the default - require approval for everything
-			// We need to run without stream-json for this to work properly
-			// For now, we'll just use dangerously-skip-permissions to avoid hanging
-			args = append(args, "--dangerously-skip-permissions")
-		}
+		args = append(args, permissionArgs(SessionOptions{ApprovalMode: authConfig.ApprovalMode})...)
 	}
 
 	cmd := exec.CommandContext(s.ctx, "claude", args...)
 	cmd.Dir = s.ProjectPath
 
 	// Set environment variables based on auth method
-	if authConfig.Method == "google-cloud" {
-		if authConfig.GCPProjectID != "" {
-			cmd.Env = append(cmd.Environ(), "CLOUD_ML_PROJECT_ID="+authConfig.GCPProjectID)
-		}
-		if authConfig.GCPRegion != "" {
-			cmd.Env = append(cmd.Environ(), "CLOUD_ML_REGION="+authConfig.GCPRegion)
-		}
-	} else {
-		// Use Anthropic API key authentication
-		if authConfig.APIKey != "" {
-			cmd.Env = append(cmd.Environ(), "ANTHROPIC_API_KEY="+authConfig.APIKey)
-		}
-	}
+	applyAuthEnv(cmd, authConfig)
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -559,6 +655,7 @@ func (s *Session) runClaudeCommand(prompt string, authConfig AuthConfig) {
 		s.handleError(fmt.Errorf("failed to start claude: %w", err))
 		return
 	}
+	s.trackProcess(cmd.Process.Pid, "claude")
 
 	// Read stderr in background and show as system messages
 	go func() {
@@ -586,17 +683,42 @@ func (s *Session) runClaudeCommand(prompt string, authConfig AuthConfig) {
 
 	for scanner.Scan() {
 		line := scanner.Text()
+		s.mu.RLock()
+		previousConversation := s.conversationID
+		s.mu.RUnlock()
+
 		s.parseStreamLine(line, &responseBuilder, &currentMessageID)
+
+		// Persist the conversation ID as soon as it is known so an
+		// interrupted run can be resumed
+		s.mu.RLock()
+		startedConversation := s.conversationID != previousConversation && s.conversationID != ""
+		s.mu.RUnlock()
+		if startedConversation {
+			if err := SaveSession(s); err != nil {
+				fmt.Printf("Warning: failed to save session %s: %v\n", s.ID, err)
+			}
+		}
 	}
 
 	// Wait for command to finish
 	cmd.Wait()
 
+	s.mu.Lock()
+	s.processPID = 0
+	s.processCommand = ""
+	s.mu.Unlock()
+
 	// Flush any remaining response
 	if responseBuilder.Len() > 0 {
 		s.finalizeMessage(currentMessageID, responseBuilder.String())
 	}
 
+	// Compact before going idle so the next message starts in a fresh context
+	if s.ctx.Err() == nil && s.needsCompaction() {
+		s.compact(authConfig, true)
+	}
+
 	// Set status back to idle
 	s.mu.Lock()
 	if s.Status == SessionStatusRunning {
@@ -648,17 +770,18 @@ func (s *Session) parseStreamLine(line string, responseBuilder *strings.Builder,
 	switch eventType {
 	case "system":
 		// System message - extract conversation ID if present
+		s.mu.Lock()
 		if convID, ok := event["conversation_id"].(string); ok {
-			s.mu.Lock()
 			s.conversationID = convID
-			s.mu.Unlock()
+			s.forkConversation = false
 		}
-		// Also check in subtype or session_id
-		if sessionID, ok := event["session_id"].(string); ok && s.conversationID == "" {
-			s.mu.Lock()
+		// Also check in subtype or session_id. A forked conversation
+		// reports its new ID here.
+		if sessionID, ok := event["session_id"].(string); ok && (s.conversationID == "" || s.forkConversation) {
 			s.conversationID = sessionID
-			s.mu.Unlock()
+			s.forkConversation = false
 		}
+		s.mu.Unlock()
 
 	case "user":
 		// User message event - extract content and display in UI
@@ -749,6 +872,11 @@ func (s *Session) parseStreamLine(line string, responseBuilder *strings.Builder,
 						s.handleToolUse(blockMap)
 					}
 				}
+				// Each assistant event is one request, so its usage is the
+				// current size of the context
+				if usage, ok := message["usage"].(map[string]any); ok {
+					s.updateContextTokens(usage)
+				}
 				// In verbose format, each assistant event is a complete message.
 				// Finalize any accumulated text so it appears in the UI immediately.
 				if responseBuilder.Len() > 0 && *currentMessageID != "" {
@@ -804,6 +932,7 @@ func (s *Session) parseStreamLine(line string, responseBuilder *strings.Builder,
 
 		if startUsage != nil {
 			s.handleUsageInfo(startUsage, false)
+			s.updateContextTokens(startUsage)
 		}
 
 	case "message_delta":
@@ -841,11 +970,16 @@ func (s *Session) parseStreamLine(line string, responseBuilder *strings.Builder,
 			}
 		}
 
+		if eventType == "result" {
+			s.updateContextWindow(event)
+		}
+
 		// Extract conversation ID from result if present
 		if result, ok := event["result"].(map[string]any); ok {
 			if convID, ok := result["session_id"].(string); ok {
 				s.mu.Lock()
 				s.conversationID = convID
+				s.forkConversation = false
 				s.mu.Unlock()
 			}
 		}
@@ -922,20 +1056,12 @@ func (s *Session) handleError(err error) {
 	s.mu.Unlock()
 }
 
-// Approve approves a pending action
-func (s *Session) Approve(actionID string) error {
-	// For now, we don't support approval in print mode
-	// This would require a different approach with interactive mode
-	return fmt.Errorf("approval not supported in current mode")
-}
-
-// Reject rejects a pending action
-func (s *Session) Reject(actionID string) error {
-	return fmt.Errorf("rejection not supported in current mode")
-}
-
 // GetMessages returns a copy of all messages
 func (s *Session) GetMessages() []Message {
+	if err := s.ensureMessagesLoaded(); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	messages := make([]Message, len(s.Messages))
@@ -1866,40 +1992,33 @@ func (s *Session) GetFirefighterMonitorStatus() map[string]interface{} {
 
 // InvestigateLinearTicket triggers investigation for a specific Linear ticket
 func (s *Session) InvestigateLinearTicket(linearIssueID string) error {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	if s.Mode != "firefighter" {
-		return fmt.Errorf("not a firefighter session")
-	}
-
-	if s.firefighterMonitor == nil {
-		s.mu.RUnlock()
-		s.mu.Lock()
-		s.firefighterMonitor = NewFirefighterMonitor(s)
-		s.mu.Unlock()
-		s.mu.RLock()
+	monitor, err := s.getFirefighterMonitor()
+	if err != nil {
+		return err
 	}
-
-	return s.firefighterMonitor.InvestigateLinearTicket(linearIssueID)
+	return monitor.InvestigateLinearTicket(linearIssueID)
 }
 
 // InvestigateSlackAlert triggers investigation for a Slack alert
 func (s *Session) InvestigateSlackAlert(slackThreadID, alertMessage string) error {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	monitor, err := s.getFirefighterMonitor()
+	if err != nil {
+		return err
+	}
+	return monitor.InvestigateSlackAlert(slackThreadID, alertMessage)
+}
+
+// getFirefighterMonitor returns the session's monitor, creating it if needed.
+// The lock is released before the monitor sends messages to the session.
+func (s *Session) getFirefighterMonitor() (*FirefighterMonitor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	if s.Mode != "firefighter" {
-		return fmt.Errorf("not a firefighter session")
+		return nil, fmt.Errorf("not a firefighter session")
 	}
-
 	if s.firefighterMonitor == nil {
-		s.mu.RUnlock()
-		s.mu.Lock()
 		s.firefighterMonitor = NewFirefighterMonitor(s)
-		s.mu.Unlock()
-		s.mu.RLock()
 	}
-
-	return s.firefighterMonitor.InvestigateSlackAlert(slackThreadID, alertMessage)
+	return s.firefighterMonitor, nil
 }
@@ -203,6 +203,38 @@ func TestSessionStop(t *testing.T) {
 	})
 }
 
+// TestWaitUntilSettled tests waiting for a run to finish
+func TestWaitUntilSettled(t *testing.T) {
+	session := NewSession("test-session", "/path/to/project")
+	session.Status = SessionStatusRunning
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		session.mu.Lock()
+		session.setStatus(SessionStatusWaiting)
+		session.mu.Unlock()
+		time.Sleep(300 * time.Millisecond)
+		session.mu.Lock()
+		session.setStatus(SessionStatusIdle)
+		session.mu.Unlock()
+	}()
+
+	status, err := session.WaitUntilSettled(context.Background())
+	if err != nil {
+		t.Fatalf("WaitUntilSettled failed: %v", err)
+	}
+	if status != SessionStatusIdle {
+		t.Errorf("Expected Status %s, got %s", SessionStatusIdle, status)
+	}
+
+	session.Status = SessionStatusRunning
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := session.WaitUntilSettled(ctx); err == nil {
+		t.Error("Expected an error when the context ends first")
+	}
+}
+
 // TestSetHandlers tests handler setters
 func TestSetHandlers(t *testing.T) {
 	t.Run("set message handler", func(t *testing.T) {
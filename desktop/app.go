@@ -1,10 +1,12 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -15,12 +17,15 @@ import (
 	triageintegration "boatman/triage"
 	"boatman/config"
 	"boatman/diff"
+	"boatman/export"
 	gitpkg "boatman/git"
 	"boatman/harnessui"
 	"boatman/mcp"
 	"boatman/project"
 	"boatman/services"
 
+	"github.com/google/uuid"
+	"github.com/philjestin/boatman-ecosystem/harness/review"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
@@ -32,6 +37,7 @@ type App struct {
 	projectManager *project.ProjectManager
 	mcpManager     *mcp.Manager
 	brainService   *services.BrainService
+	approvalServer *agent.ApprovalServer
 	harnessRuns    map[string]context.CancelFunc
 	harnessMu      sync.Mutex
 }
@@ -83,6 +89,15 @@ func (a *App) startup(ctx context.Context) {
 	// Set config getter for memory management
 	a.agentManager.SetConfigGetter(a)
 
+	// Hold tool calls for approval according to project preferences
+	a.agentManager.SetApprovalPolicy(a)
+	if server, err := agent.NewApprovalServer(); err != nil {
+		runtime.LogWarningf(ctx, "Tool approvals unavailable: %v", err)
+	} else {
+		a.approvalServer = server
+		a.agentManager.SetApprovalServer(server)
+	}
+
 	// Initialize brain service
 	a.brainService.SetContext(ctx)
 
@@ -119,6 +134,9 @@ func (a *App) shutdown(ctx context.Context) {
 	// Save all sessions BEFORE stopping them, so we persist current status (not "stopped")
 	a.agentManager.SaveAllSessions()
 	a.agentManager.StopAllSessions()
+	if a.approvalServer != nil {
+		a.approvalServer.Close()
+	}
 }
 
 // =============================================================================
@@ -160,6 +178,9 @@ type AgentSessionInfo struct {
 	Model           string              `json:"model,omitempty"`
 	ReasoningEffort string              `json:"reasoningEffort,omitempty"`
 	Mode            string              `json:"mode,omitempty"`
+	ParentID        string              `json:"parentId,omitempty"`
+	ForkedFrom      string              `json:"forkedFrom,omitempty"`
+	ReadOnly        bool                `json:"readOnly,omitempty"`
 }
 
 // CreateAgentSession creates a new agent session
@@ -180,6 +201,51 @@ func (a *App) CreateAgentSession(projectPath string) (*AgentSessionInfo, error)
 	}, nil
 }
 
+// ForkSession creates a session that continues sessionID from messageID, so a
+// different follow-up can be tried from the same point. With withWorktree the
+// fork works in a new git worktree, on its own branch, so its file changes
+// don't collide with the parent's.
+func (a *App) ForkSession(sessionID, messageID string, withWorktree bool) (*AgentSessionInfo, error) {
+	var worktreePath, branch string
+	var repo *gitpkg.Repository
+	if withWorktree {
+		parent, err := a.agentManager.GetSession(sessionID)
+		if err != nil {
+			return nil, err
+		}
+		// The suffix keeps forks made within the same second apart
+		name := "fork-" + time.Now().Format("20060102-150405") + "-" + uuid.New().String()[:8]
+		worktreePath = filepath.Join(parent.ProjectPath, ".worktrees", name)
+		branch = "boatman/" + name
+		repo = gitpkg.NewRepository(parent.ProjectPath)
+		if err := repo.CreateWorktree(worktreePath, branch); err != nil {
+			return nil, err
+		}
+	}
+
+	fork, err := a.agentManager.ForkSession(sessionID, messageID, worktreePath)
+	if err != nil {
+		if repo != nil {
+			repo.RemoveWorktree(worktreePath)
+			repo.DeleteBranch(branch)
+		}
+		return nil, err
+	}
+
+	return &AgentSessionInfo{
+		ID:              fork.ID,
+		ProjectPath:     fork.ProjectPath,
+		Status:          fork.Status,
+		CreatedAt:       fork.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Tags:            fork.Tags,
+		Model:           fork.Model,
+		ReasoningEffort: fork.ReasoningEffort,
+		Mode:            fork.Mode,
+		ParentID:        fork.ParentID,
+		ForkedFrom:      fork.ForkedFrom,
+	}, nil
+}
+
 // CreateFirefighterSession creates a new firefighter agent session
 func (a *App) CreateFirefighterSession(projectPath string, scope string, slackChannels string) (*AgentSessionInfo, error) {
 	session, err := a.agentManager.CreateFirefighterSession(projectPath, scope, slackChannels)
@@ -249,6 +315,17 @@ func (a *App) RejectAgentAction(sessionID, actionID string) error {
 	return a.agentManager.RejectAction(sessionID, actionID)
 }
 
+// ApproveAgentActionAlways approves a pending action and always allows
+// matching tool calls in the session's project
+func (a *App) ApproveAgentActionAlways(sessionID, actionID string) error {
+	return a.agentManager.ApproveActionAlways(sessionID, actionID)
+}
+
+// GetPendingAgentActions returns the actions waiting for approval
+func (a *App) GetPendingAgentActions(sessionID string) ([]agent.PendingAction, error) {
+	return a.agentManager.GetPendingActions(sessionID)
+}
+
 // GetAgentMessages returns messages for a session
 func (a *App) GetAgentMessages(sessionID string) ([]agent.Message, error) {
 	return a.agentManager.GetSessionMessages(sessionID)
@@ -265,13 +342,6 @@ type MessagePage struct {
 
 // GetAgentMessagesPaginated returns a paginated list of messages for a session
 func (a *App) GetAgentMessagesPaginated(sessionID string, page, pageSize int) (*MessagePage, error) {
-	allMessages, err := a.agentManager.GetSessionMessages(sessionID)
-	if err != nil {
-		return nil, err
-	}
-
-	total := len(allMessages)
-
 	// Default page size
 	if pageSize <= 0 {
 		pageSize = 50
@@ -282,26 +352,14 @@ func (a *App) GetAgentMessagesPaginated(sessionID string, page, pageSize int) (*
 		page = 0
 	}
 
-	// Calculate start and end indices
+	// Only the requested page is read from disk
 	start := page * pageSize
-	if start >= total {
-		// Page is beyond available messages
-		return &MessagePage{
-			Messages: []agent.Message{},
-			Total:    total,
-			Page:     page,
-			PageSize: pageSize,
-			HasMore:  false,
-		}, nil
-	}
-
-	end := start + pageSize
-	if end > total {
-		end = total
+	messages, total, err := a.agentManager.GetSessionMessagesPage(sessionID, start, pageSize)
+	if err != nil {
+		return nil, err
 	}
 
-	messages := allMessages[start:end]
-	hasMore := end < total
+	hasMore := start+len(messages) < total
 
 	return &MessagePage{
 		Messages: messages,
@@ -332,6 +390,9 @@ func (a *App) ListAgentSessions() []AgentSessionInfo {
 			Model:           s.Model,
 			ReasoningEffort: s.ReasoningEffort,
 			Mode:            s.Mode,
+			ParentID:        s.ParentID,
+			ForkedFrom:      s.ForkedFrom,
+			ReadOnly:        s.ReadOnly,
 		}
 	}
 	return infos
@@ -427,11 +488,55 @@ func (a *App) GetGitDiff(projectPath, filePath string) (string, error) {
 	return repo.GetDiff(filePath)
 }
 
-// GetWorktreeDiff returns the diff of all changes on the worktree branch relative to the base branch.
-// This is used for boatman mode sessions where changes are committed in a worktree.
+// GetWorktreeDiff returns the diff of all changes on the worktree branch relative to its merge-base
+// with the base branch, including uncommitted ones. This is used for boatman mode sessions where
+// changes are committed in a worktree.
 func (a *App) GetWorktreeDiff(worktreePath, baseBranch string) (string, error) {
 	repo := gitpkg.NewRepository(worktreePath)
-	return repo.GetDiffAgainstBase(baseBranch)
+	return repo.GetDiffSinceBase(baseBranch)
+}
+
+// StageHunks stages selected hunks or line ranges of a file's unstaged
+// changes. repoPath can be the main checkout or an agent worktree.
+func (a *App) StageHunks(repoPath, filePath string, selections []diff.HunkSelection) error {
+	repo := gitpkg.NewRepository(repoPath)
+	return repo.StageHunks(filePath, selections)
+}
+
+// UnstageHunks moves selected hunks or line ranges of a file's staged changes
+// back out of the index
+func (a *App) UnstageHunks(repoPath, filePath string, selections []diff.HunkSelection) error {
+	repo := gitpkg.NewRepository(repoPath)
+	return repo.UnstageHunks(filePath, selections)
+}
+
+// DiscardHunks reverts selected hunks or line ranges of a file's unstaged
+// changes in the working tree
+func (a *App) DiscardHunks(repoPath, filePath string, selections []diff.HunkSelection) error {
+	repo := gitpkg.NewRepository(repoPath)
+	return repo.DiscardHunks(filePath, selections)
+}
+
+// CommitApprovedHunks commits only the approved files and hunks of the
+// working tree diff, leaving the rest uncommitted. It returns the commit hash.
+func (a *App) CommitApprovedHunks(repoPath string, diffs []diff.FileDiff, message string) (string, error) {
+	repo := gitpkg.NewRepository(repoPath)
+	return repo.CommitApprovedHunks(diffs, message)
+}
+
+// DiscardBranchHunks reverts selected hunks or line ranges of a worktree
+// branch's diff (see GetWorktreeDiff) in the worktree
+func (a *App) DiscardBranchHunks(worktreePath, baseBranch, filePath string, selections []diff.HunkSelection) error {
+	repo := gitpkg.NewRepository(worktreePath)
+	return repo.DiscardBranchHunks(baseBranch, filePath, selections)
+}
+
+// CommitApprovedBranchHunks commits the approved files and hunks of a
+// worktree branch's diff (see GetWorktreeDiff) as a new commit on the branch,
+// leaving the rest uncommitted in the worktree. It returns the commit hash.
+func (a *App) CommitApprovedBranchHunks(worktreePath, baseBranch string, diffs []diff.FileDiff, message string) (string, error) {
+	repo := gitpkg.NewRepository(worktreePath)
+	return repo.CommitApprovedBranchHunks(baseBranch, diffs, message)
 }
 
 // GetBoatmanModeSessionConfig returns the worktree path and base branch for a boatman mode session.
@@ -457,6 +562,82 @@ func (a *App) GetSideBySideDiff(fileDiff diff.FileDiff) []diff.SideBySideLine {
 	return diff.GenerateSideBySide(fileDiff)
 }
 
+// RequestDiffChanges sends the comments and unapproved hunks of a reviewed
+// diff back to a session's agent as review issues. Boatmanmode sessions are
+// resumed with the issues as their first review round; other sessions get
+// them as a message. When the agent finishes, its follow-up changes are
+// verified against each issue and a "diff:changes-verified" event reports
+// which were addressed. It returns the requested changes.
+func (a *App) RequestDiffChanges(sessionID string, diffs []diff.FileDiff) ([]diff.RequestedChange, error) {
+	session, err := a.agentManager.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := diff.RequestedChanges(diffs)
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("no changes to request: every hunk is approved and there are no comments")
+	}
+
+	// Snapshot the worktree so the follow-up can be diffed on its own
+	worktreePath := session.ProjectPath
+	isBoatmanMode := session.Mode == "boatmanmode"
+	if isBoatmanMode {
+		if path, _ := session.ModeConfig["worktreePath"].(string); path != "" {
+			worktreePath = path
+		}
+	}
+	repo := gitpkg.NewRepository(worktreePath)
+	snapshot, err := repo.Snapshot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot worktree: %w", err)
+	}
+	var oldDiff string
+	if isBoatmanMode {
+		baseBranch, _ := session.ModeConfig["baseBranch"].(string)
+		oldDiff, _ = repo.GetDiffAgainstBase(baseBranch)
+	} else {
+		oldDiff, _ = repo.GetDiff("")
+	}
+
+	verify := func(runErr error) {
+		payload := map[string]interface{}{
+			"sessionId": sessionID,
+		}
+		if newDiff, err := repo.GetDiffSince(snapshot); err != nil {
+			payload["error"] = fmt.Sprintf("failed to diff follow-up changes: %v", err)
+		} else if statuses, err := diff.VerifyRequestedChanges(a.ctx, worktreePath, changes, oldDiff, newDiff); err != nil {
+			payload["error"] = fmt.Sprintf("failed to verify changes: %v", err)
+		} else {
+			payload["statuses"] = statuses
+		}
+		if runErr != nil {
+			payload["runError"] = runErr.Error()
+		}
+		runtime.EventsEmit(a.ctx, "diff:changes-verified", payload)
+	}
+
+	if isBoatmanMode {
+		if err := a.resumeBoatmanModeExecution(sessionID, diff.Issues(changes), verify); err != nil {
+			return nil, err
+		}
+		return changes, nil
+	}
+
+	if err := a.agentManager.SendMessage(sessionID, diff.FormatRequestedChanges(changes)); err != nil {
+		return nil, err
+	}
+	go func() {
+		status, err := session.WaitUntilSettled(a.ctx)
+		if err == nil && status == agent.SessionStatusError {
+			err = fmt.Errorf("agent run ended with an error")
+		}
+		verify(err)
+	}()
+
+	return changes, nil
+}
+
 // =============================================================================
 // MCP Methods
 // =============================================================================
@@ -541,6 +722,15 @@ func (a *App) GetKeepCompletedAgents() bool {
 	return a.config.GetPreferences().KeepCompletedAgents
 }
 
+// GetAutoCompactThreshold returns the context fraction at which sessions compact
+func (a *App) GetAutoCompactThreshold() float64 {
+	threshold := a.config.GetPreferences().AutoCompactThreshold
+	if threshold < 0 || threshold > 1 {
+		return agent.DefaultCompactThreshold
+	}
+	return threshold
+}
+
 // GetMCPServerNames returns the names of configured MCP servers
 func (a *App) GetMCPServerNames() []string {
 	servers := a.config.GetMCPServers()
@@ -551,6 +741,25 @@ func (a *App) GetMCPServerNames() []string {
 	return names
 }
 
+// =============================================================================
+// Approval Policy Implementation (for agent.ApprovalPolicy interface)
+// =============================================================================
+
+// GetProjectApprovalMode returns the approval mode for a project
+func (a *App) GetProjectApprovalMode(projectPath string) string {
+	return string(a.config.GetEffectiveApprovalMode(projectPath))
+}
+
+// GetProjectAllowedTools returns the "always allow" rules for a project
+func (a *App) GetProjectAllowedTools(projectPath string) []string {
+	return a.config.GetProjectPreferences(projectPath).AllowedTools
+}
+
+// AddProjectAllowedTool remembers an "always allow" rule for a project
+func (a *App) AddProjectAllowedTool(projectPath, rule string) error {
+	return a.config.AddProjectAllowedTool(projectPath, rule)
+}
+
 // =============================================================================
 // Session Cleanup Methods
 // =============================================================================
@@ -592,16 +801,17 @@ type SearchSessionsRequest struct {
 
 // SearchSessionsResponse represents a search response
 type SearchSessionsResponse struct {
-	SessionID    string              `json:"sessionId"`
-	ProjectPath  string              `json:"projectPath"`
-	CreatedAt    string              `json:"createdAt"`
-	UpdatedAt    string              `json:"updatedAt"`
-	Tags         []string            `json:"tags"`
-	IsFavorite   bool                `json:"isFavorite"`
-	MessageCount int                 `json:"messageCount"`
-	Score        int                 `json:"score"`
-	MatchReasons []string            `json:"matchReasons"`
-	Status       agent.SessionStatus `json:"status"`
+	SessionID    string                `json:"sessionId"`
+	ProjectPath  string                `json:"projectPath"`
+	CreatedAt    string                `json:"createdAt"`
+	UpdatedAt    string                `json:"updatedAt"`
+	Tags         []string              `json:"tags"`
+	IsFavorite   bool                  `json:"isFavorite"`
+	MessageCount int                   `json:"messageCount"`
+	Score        int                   `json:"score"`
+	MatchReasons []string              `json:"matchReasons"`
+	Status       agent.SessionStatus   `json:"status"`
+	Snippets     []agent.SearchSnippet `json:"snippets"`
 }
 
 // SearchSessions searches sessions based on criteria
@@ -650,10 +860,11 @@ func (a *App) SearchSessions(req SearchSessionsRequest) ([]SearchSessionsRespons
 			UpdatedAt:    result.Session.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			Tags:         result.Session.GetTags(),
 			IsFavorite:   result.Session.IsFavorite,
-			MessageCount: len(result.Session.Messages),
+			MessageCount: result.MessageCount,
 			Score:        result.Score,
 			MatchReasons: result.MatchReason,
 			Status:       result.Session.Status,
+			Snippets:     result.Snippets,
 		}
 	}
 
@@ -749,6 +960,51 @@ func (a *App) GetFirefighterMonitorStatus(sessionID string) (map[string]interfac
 	return session.GetFirefighterMonitorStatus(), nil
 }
 
+// GetFirefighterIncidents returns the incidents tracked across firefighter
+// sessions, most recently updated first
+func (a *App) GetFirefighterIncidents() ([]agent.Incident, error) {
+	store, err := agent.OpenIncidentStore()
+	if err != nil {
+		return nil, err
+	}
+	return store.List(), nil
+}
+
+// ExportIncidentPostmortem saves a Markdown postmortem draft for an incident.
+// Returns the saved path, or "" if the user cancelled.
+func (a *App) ExportIncidentPostmortem(incidentID string) (string, error) {
+	store, err := agent.OpenIncidentStore()
+	if err != nil {
+		return "", err
+	}
+	doc, err := store.Postmortem(incidentID)
+	if err != nil {
+		return "", err
+	}
+
+	savePath, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export Postmortem",
+		DefaultFilename: fmt.Sprintf("postmortem-%s.md", time.Now().Format("2006-01-02")),
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Markdown Files", Pattern: "*.md"},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("save dialog error: %w", err)
+	}
+	if savePath == "" {
+		return "", nil // User cancelled
+	}
+	if !strings.HasSuffix(strings.ToLower(savePath), ".md") {
+		savePath += ".md"
+	}
+
+	if err := os.WriteFile(savePath, []byte(doc), 0644); err != nil {
+		return "", fmt.Errorf("failed to save postmortem: %w", err)
+	}
+	return savePath, nil
+}
+
 // =============================================================================
 // Google Cloud OAuth Authentication Methods
 // =============================================================================
@@ -875,6 +1131,17 @@ func (a *App) ExecuteLinearTicketWithBoatmanMode(linearAPIKey, ticketID, project
 // mode can be "ticket" or "prompt"
 // This function returns immediately and runs the execution in the background
 func (a *App) StreamBoatmanModeExecution(sessionID, input, mode, linearAPIKey, projectPath string) error {
+	return a.runBoatmanModeExecution(sessionID, input, mode, linearAPIKey, projectPath, nil, nil)
+}
+
+// runBoatmanModeExecution starts a boatmanmode run in the background. Resumed
+// runs address reviewIssues, if any, instead of running an initial review.
+// onDone, if set, is called with the run's error once it finishes.
+func (a *App) runBoatmanModeExecution(sessionID, input, mode, linearAPIKey, projectPath string, reviewIssues []review.Issue, onDone func(error)) error {
+	if session, err := a.agentManager.GetSession(sessionID); err == nil && session.ReadOnly {
+		return fmt.Errorf("imported sessions are read-only")
+	}
+
 	// Get auth config using the same mechanism as regular sessions
 	prefs := a.config.GetPreferences()
 	claudeAPIKey := prefs.APIKey
@@ -911,6 +1178,31 @@ func (a *App) StreamBoatmanModeExecution(sessionID, input, mode, linearAPIKey, p
 		}
 	}
 
+	// Write requested review issues for the resumed run to pick up.
+	var reviewIssuesFile string
+	if len(reviewIssues) > 0 {
+		issuesJSON, err := json.Marshal(reviewIssues)
+		if err != nil {
+			return fmt.Errorf("failed to encode review issues: %w", err)
+		}
+		tmpFile, err := os.CreateTemp("", "boatman-review-issues-*.json")
+		if err != nil {
+			return fmt.Errorf("failed to write review issues: %w", err)
+		}
+		tmpFile.Write(issuesJSON)
+		tmpFile.Close()
+		reviewIssuesFile = tmpFile.Name()
+		bmIntegration.SetReviewIssuesFile(reviewIssuesFile)
+	}
+
+	// Track the boatman process so the session can be recovered if the app
+	// quits mid-run
+	if sessionErr == nil && session != nil {
+		bmIntegration.SetOnStart(func(pid int) {
+			session.MarkProcessStarted(pid, "boatman")
+		})
+	}
+
 	// Run execution in background to avoid blocking the frontend
 	go func() {
 		defer func() {
@@ -987,11 +1279,17 @@ func (a *App) StreamBoatmanModeExecution(sessionID, input, mode, linearAPIKey, p
 
 		_, err := bmIntegration.StreamExecution(a.ctx, sessionID, input, mode, planFile, isResume, outputChan, onMessage)
 		close(outputChan)
+		if sessionErr == nil && session != nil {
+			session.MarkProcessExited()
+		}
 
-		// Clean up temp plan file.
+		// Clean up temp plan and review issue files.
 		if planFile != "" {
 			os.Remove(planFile)
 		}
+		if reviewIssuesFile != "" {
+			os.Remove(reviewIssuesFile)
+		}
 
 		if err != nil {
 			fmt.Printf("[boatmanmode] Execution error: %v\n", err)
@@ -1013,6 +1311,10 @@ func (a *App) StreamBoatmanModeExecution(sessionID, input, mode, linearAPIKey, p
 				fmt.Printf("[boatmanmode] Warning: failed to save session %s: %v\n", sessionID, saveErr)
 			}
 		}
+
+		if onDone != nil {
+			onDone(err)
+		}
 	}()
 
 	return nil
@@ -1021,6 +1323,12 @@ func (a *App) StreamBoatmanModeExecution(sessionID, input, mode, linearAPIKey, p
 // ResumeBoatmanModeExecution resumes a failed boatmanmode session from the review/refactor stage.
 // It sets the "resume" flag on the session's ModeConfig and re-runs StreamBoatmanModeExecution.
 func (a *App) ResumeBoatmanModeExecution(sessionID string) error {
+	return a.resumeBoatmanModeExecution(sessionID, nil, nil)
+}
+
+// resumeBoatmanModeExecution resumes a boatmanmode session, addressing
+// reviewIssues if any are given, and calls onDone when the run finishes.
+func (a *App) resumeBoatmanModeExecution(sessionID string, reviewIssues []review.Issue, onDone func(error)) error {
 	session, err := a.agentManager.GetSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("session not found: %w", err)
@@ -1043,8 +1351,34 @@ func (a *App) ResumeBoatmanModeExecution(sessionID string) error {
 	prefs := a.config.GetPreferences()
 	linearAPIKey := prefs.LinearAPIKey
 
-	// Re-run execution with resume flag (StreamBoatmanModeExecution reads ModeConfig["resume"])
-	return a.StreamBoatmanModeExecution(sessionID, input, mode, linearAPIKey, session.ProjectPath)
+	// Re-run execution with resume flag (runBoatmanModeExecution reads ModeConfig["resume"])
+	return a.runBoatmanModeExecution(sessionID, input, mode, linearAPIKey, session.ProjectPath, reviewIssues, onDone)
+}
+
+// ResumeInterruptedSession resumes a session that was cut off when the app quit
+// mid-run. Boatmanmode sessions resume from the review/refactor stage in their
+// existing worktree; other sessions continue their Claude conversation.
+func (a *App) ResumeInterruptedSession(sessionID string) error {
+	session, err := a.agentManager.GetSession(sessionID)
+	if err != nil {
+		return err
+	}
+	if !session.IsInterrupted() {
+		return fmt.Errorf("session %s was not interrupted", sessionID)
+	}
+
+	if session.Mode != "boatmanmode" {
+		return a.agentManager.ResumeSession(sessionID)
+	}
+
+	worktreePath, _ := session.ModeConfig["worktreePath"].(string)
+	if worktreePath == "" {
+		return fmt.Errorf("session was interrupted before its worktree was created; start the execution again")
+	}
+	if _, err := os.Stat(worktreePath); err != nil {
+		return fmt.Errorf("worktree %s is no longer available: %w", worktreePath, err)
+	}
+	return a.resumeBoatmanModeExecution(sessionID, nil, nil)
 }
 
 // HandleBoatmanModeEvent processes boatmanmode events and updates session state
@@ -1054,6 +1388,9 @@ func (a *App) HandleBoatmanModeEvent(sessionID string, eventType string, eventDa
 		return err
 	}
 
+	// Keep the run's event log for exported bundles
+	session.RecordEvent(eventType, eventData)
+
 	switch eventType {
 	case "agent_started":
 		// Create task for the agent
@@ -1465,6 +1802,167 @@ func (a *App) ExportTriagePDF(sessionID string) (string, error) {
 	return savePath, nil
 }
 
+// ExportSession exports a session as a "markdown" or "html" transcript, or as
+// a portable "bundle" with its diff and event log. Secrets are redacted.
+// Returns the saved path, or "" if the user cancelled.
+func (a *App) ExportSession(sessionID, format string) (string, error) {
+	session, err := a.agentManager.GetSession(sessionID)
+	if err != nil {
+		return "", fmt.Errorf("session not found: %w", err)
+	}
+
+	var ext, filterName string
+	switch format {
+	case "markdown":
+		ext, filterName = ".md", "Markdown Files"
+	case "html":
+		ext, filterName = ".html", "HTML Files"
+	case "bundle":
+		ext, filterName = export.BundleExtension, "Session Bundles"
+	default:
+		return "", fmt.Errorf("unknown export format: %s", format)
+	}
+
+	savePath, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export Session",
+		DefaultFilename: fmt.Sprintf("%s-%s%s", filepath.Base(session.ProjectPath), time.Now().Format("2006-01-02"), ext),
+		Filters: []runtime.FileFilter{
+			{DisplayName: filterName, Pattern: "*" + filepath.Ext(ext)},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("save dialog error: %w", err)
+	}
+	if savePath == "" {
+		return "", nil // User cancelled
+	}
+	if !strings.HasSuffix(strings.ToLower(savePath), ext) {
+		savePath += ext
+	}
+
+	bundle := export.New(session, a.sessionFinalDiff(session))
+	prefs := a.config.GetPreferences()
+	redactor := export.NewRedactor(prefs.APIKey, prefs.LinearAPIKey, prefs.DatadogAPIKey,
+		prefs.DatadogAppKey, prefs.BugsnagAPIKey, prefs.OktaClientSecret)
+	if err := bundle.Redact(redactor); err != nil {
+		return "", err
+	}
+
+	var data []byte
+	switch format {
+	case "markdown":
+		data = []byte(bundle.Markdown())
+	case "html":
+		html, err := bundle.HTML()
+		if err != nil {
+			return "", fmt.Errorf("failed to render transcript: %w", err)
+		}
+		data = []byte(html)
+	case "bundle":
+		var buf bytes.Buffer
+		if err := bundle.Write(&buf); err != nil {
+			return "", fmt.Errorf("failed to write bundle: %w", err)
+		}
+		data = buf.Bytes()
+	}
+
+	if err := os.WriteFile(savePath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to save export: %w", err)
+	}
+	return savePath, nil
+}
+
+// sessionFinalDiff returns the changes a session left behind: its worktree
+// branch for boatmanmode, otherwise the project's uncommitted changes.
+func (a *App) sessionFinalDiff(session *agent.Session) string {
+	if diff, ok := session.ModeConfig["finalDiff"].(string); ok {
+		return diff // Imported
+	}
+	if worktreePath, _ := session.ModeConfig["worktreePath"].(string); worktreePath != "" {
+		baseBranch, _ := session.ModeConfig["baseBranch"].(string)
+		diff, _ := gitpkg.NewRepository(worktreePath).GetDiffAgainstBase(baseBranch)
+		return diff
+	}
+	diff, _ := gitpkg.NewRepository(session.ProjectPath).GetDiffSince("HEAD")
+	return diff
+}
+
+// ImportSessionBundle asks for an exported bundle and loads it as a
+// read-only session. Returns nil if the user cancelled.
+func (a *App) ImportSessionBundle() (*AgentSessionInfo, error) {
+	path, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Import Session Bundle",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Session Bundles", Pattern: "*.zip"},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("open dialog error: %w", err)
+	}
+	if path == "" {
+		return nil, nil // User cancelled
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	bundle, err := export.Read(f, info.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	modeConfig := bundle.Session.ModeConfig
+	if modeConfig == nil {
+		modeConfig = map[string]interface{}{}
+	}
+	// The worktree belongs to the machine the bundle came from
+	delete(modeConfig, "worktreePath")
+	modeConfig["finalDiff"] = bundle.Diff
+
+	session, err := a.agentManager.ImportSession(agent.ImportedSession{
+		SourceID:    bundle.Session.ID,
+		ProjectPath: bundle.Session.ProjectPath,
+		Mode:        bundle.Session.Mode,
+		ModeConfig:  modeConfig,
+		Model:       bundle.Session.Model,
+		Tags:        bundle.Session.Tags,
+		CreatedAt:   bundle.Session.CreatedAt,
+		Messages:    bundle.Messages,
+		Tasks:       bundle.Tasks,
+		Events:      bundle.Events,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &AgentSessionInfo{
+		ID:          session.ID,
+		ProjectPath: session.ProjectPath,
+		Status:      session.Status,
+		CreatedAt:   session.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Tags:        session.Tags,
+		Model:       session.Model,
+		Mode:        session.Mode,
+		ReadOnly:    session.ReadOnly,
+	}, nil
+}
+
+// GetImportedSessionDiff returns the final diff stored with an imported session
+func (a *App) GetImportedSessionDiff(sessionID string) (string, error) {
+	session, err := a.agentManager.GetSession(sessionID)
+	if err != nil {
+		return "", err
+	}
+	diff, _ := session.ModeConfig["finalDiff"].(string)
+	return diff, nil
+}
+
 // =============================================================================
 // Utility Methods
 // =============================================================================
@@ -1633,6 +2131,21 @@ func (a *App) ClearSessionConversation(sessionID string) error {
 	return nil
 }
 
+// CompactSessionConversation summarizes the conversation for a session and
+// continues it in a fresh context, keeping the history.
+func (a *App) CompactSessionConversation(sessionID string) error {
+	return a.agentManager.CompactSession(sessionID)
+}
+
+// GetSessionContextUsage returns how much of the context window a session uses.
+func (a *App) GetSessionContextUsage(sessionID string) (agent.ContextUsage, error) {
+	session, err := a.agentManager.GetSession(sessionID)
+	if err != nil {
+		return agent.ContextUsage{}, err
+	}
+	return session.GetContextUsage(), nil
+}
+
 // GetSessionInfo returns session metadata for display.
 func (a *App) GetSessionInfo(sessionID string) (map[string]interface{}, error) {
 	session, err := a.agentManager.GetSession(sessionID)
@@ -19,6 +19,12 @@ type Integration struct {
 	repoPath        string
 	linearAPIKey    string
 	claudeAPIKey    string
+
+	// reviewIssuesFile, if set, is passed to resumed runs as --review-issues
+	reviewIssuesFile string
+
+	// onStart, if set, is called with the PID of the boatman process
+	onStart func(pid int)
 }
 
 // NewIntegration creates a new boatmanmode integration
@@ -47,6 +53,18 @@ func NewIntegration(linearAPIKey, claudeAPIKey, repoPath string) (*Integration,
 	}, nil
 }
 
+// SetReviewIssuesFile makes resumed executions address the review issues in
+// the given JSON file instead of running an initial review
+func (i *Integration) SetReviewIssuesFile(path string) {
+	i.reviewIssuesFile = path
+}
+
+// SetOnStart registers a callback that receives the PID of the boatman
+// process once a streamed execution starts
+func (i *Integration) SetOnStart(onStart func(pid int)) {
+	i.onStart = onStart
+}
+
 // ExecuteTicket runs the full boatmanmode workflow for a Linear ticket
 func (i *Integration) ExecuteTicket(ctx context.Context, ticketID string) (map[string]interface{}, error) {
 	cmd := exec.CommandContext(ctx, i.boatmanmodePath,
@@ -130,6 +148,9 @@ func (i *Integration) StreamExecution(ctx context.Context, sessionID string, inp
 	}
 	if resume {
 		args = append(args, "--resume")
+		if i.reviewIssuesFile != "" {
+			args = append(args, "--review-issues", i.reviewIssuesFile)
+		}
 	}
 	cmd := exec.CommandContext(ctx, i.boatmanmodePath, args...)
 	cmd.Dir = i.repoPath
@@ -164,6 +185,9 @@ func (i *Integration) StreamExecution(ctx context.Context, sessionID string, inp
 	}
 
 	fmt.Printf("[boatmanmode] Command started successfully, PID: %d\n", cmd.Process.Pid)
+	if i.onStart != nil {
+		i.onStart(cmd.Process.Pid)
+	}
 
 	// Stream stdout and parse JSON events
 	go func() {
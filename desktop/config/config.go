@@ -63,6 +63,10 @@ type UserPreferences struct {
 	MaxAgentsPerSession   int  `json:"maxAgentsPerSession"`
 	KeepCompletedAgents   bool `json:"keepCompletedAgents"`
 
+	// AutoCompactThreshold is the fraction of the model's context window at
+	// which a session's conversation is compacted automatically; 0 disables it
+	AutoCompactThreshold float64 `json:"autoCompactThreshold"`
+
 	// Firefighter/Observability settings
 	DatadogAPIKey string `json:"datadogAPIKey,omitempty"`
 	DatadogAppKey string `json:"datadogAppKey,omitempty"`
@@ -86,6 +90,10 @@ type ProjectPreferences struct {
 	ProjectPath  string       `json:"projectPath"`
 	ApprovalMode ApprovalMode `json:"approvalMode,omitempty"`
 	Model        string       `json:"model,omitempty"`
+
+	// AllowedTools are "always allow" rules for tool approvals, e.g. "Write"
+	// or "Bash(go test:*)". Matching tool calls skip the approval prompt.
+	AllowedTools []string `json:"allowedTools,omitempty"`
 }
 
 // Config manages application configuration
@@ -128,6 +136,7 @@ func NewConfig() (*Config, error) {
 			AutoCleanupSessions:   true,
 			MaxAgentsPerSession:   20,
 			KeepCompletedAgents:   false,
+			AutoCompactThreshold:  0.8,
 		},
 		projects: make(map[string]ProjectPreferences),
 	}
@@ -150,10 +159,12 @@ func (c *Config) load() error {
 		return err
 	}
 
+	// Settings missing from older config files keep their defaults
 	var saved struct {
 		Preferences UserPreferences              `json:"preferences"`
 		Projects    map[string]ProjectPreferences `json:"projects"`
 	}
+	saved.Preferences = c.preferences
 
 	if err := json.Unmarshal(data, &saved); err != nil {
 		return err
@@ -217,6 +228,35 @@ func (c *Config) SetProjectPreferences(prefs ProjectPreferences) error {
 	return c.Save()
 }
 
+// GetEffectiveApprovalMode returns the project's approval mode override, or
+// the global approval mode if the project has none.
+func (c *Config) GetEffectiveApprovalMode(projectPath string) ApprovalMode {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if p, ok := c.projects[projectPath]; ok && p.ApprovalMode != "" {
+		return p.ApprovalMode
+	}
+	return c.preferences.ApprovalMode
+}
+
+// AddProjectAllowedTool remembers an "always allow" rule for a project.
+// Adding a rule that already exists is a no-op.
+func (c *Config) AddProjectAllowedTool(projectPath, rule string) error {
+	c.mu.Lock()
+	p := c.projects[projectPath]
+	p.ProjectPath = projectPath
+	for _, existing := range p.AllowedTools {
+		if existing == rule {
+			c.mu.Unlock()
+			return nil
+		}
+	}
+	p.AllowedTools = append(p.AllowedTools, rule)
+	c.projects[projectPath] = p
+	c.mu.Unlock()
+	return c.Save()
+}
+
 // IsOnboardingCompleted checks if onboarding is done
 func (c *Config) IsOnboardingCompleted() bool {
 	c.mu.RLock()
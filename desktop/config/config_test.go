@@ -593,6 +593,85 @@ func TestSetProjectPreferences(t *testing.T) {
 	}
 }
 
+func TestGetEffectiveApprovalMode(t *testing.T) {
+	cfg, tempDir := setupTestConfig(t)
+	defer os.RemoveAll(tempDir)
+
+	cfg.preferences.ApprovalMode = ApprovalModeSuggest
+	cfg.projects["/with/override"] = ProjectPreferences{
+		ProjectPath:  "/with/override",
+		ApprovalMode: ApprovalModeFullAuto,
+	}
+	cfg.projects["/without/override"] = ProjectPreferences{
+		ProjectPath: "/without/override",
+		Model:       "opus",
+	}
+
+	tests := []struct {
+		path string
+		want ApprovalMode
+	}{
+		{"/with/override", ApprovalModeFullAuto},
+		{"/without/override", ApprovalModeSuggest},
+		{"/unknown", ApprovalModeSuggest},
+	}
+	for _, tt := range tests {
+		if got := cfg.GetEffectiveApprovalMode(tt.path); got != tt.want {
+			t.Errorf("GetEffectiveApprovalMode(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestAddProjectAllowedTool(t *testing.T) {
+	cfg, tempDir := setupTestConfig(t)
+	defer os.RemoveAll(tempDir)
+
+	cfg.projects["/test/path"] = ProjectPreferences{
+		ProjectPath: "/test/path",
+		Model:       "opus",
+	}
+
+	for _, rule := range []string{"Write", "Bash(go test:*)", "Write"} {
+		if err := cfg.AddProjectAllowedTool("/test/path", rule); err != nil {
+			t.Fatalf("AddProjectAllowedTool(%q) error = %v", rule, err)
+		}
+	}
+
+	prefs := cfg.GetProjectPreferences("/test/path")
+	if prefs.Model != "opus" {
+		t.Errorf("Expected Model to be preserved, got %v", prefs.Model)
+	}
+	if len(prefs.AllowedTools) != 2 {
+		t.Fatalf("Expected 2 allowed tools, got %v", prefs.AllowedTools)
+	}
+	if prefs.AllowedTools[0] != "Write" || prefs.AllowedTools[1] != "Bash(go test:*)" {
+		t.Errorf("Unexpected allowed tools: %v", prefs.AllowedTools)
+	}
+
+	// Rules for an unknown project create its preferences.
+	if err := cfg.AddProjectAllowedTool("/new/path", "Edit"); err != nil {
+		t.Fatalf("AddProjectAllowedTool() error = %v", err)
+	}
+	if got := cfg.GetProjectPreferences("/new/path"); got.ProjectPath != "/new/path" || len(got.AllowedTools) != 1 {
+		t.Errorf("Unexpected preferences for new project: %+v", got)
+	}
+
+	// Verify rules were saved to disk
+	data, err := os.ReadFile(cfg.configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	var saved struct {
+		Projects map[string]ProjectPreferences `json:"projects"`
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("Failed to unmarshal saved config: %v", err)
+	}
+	if len(saved.Projects["/test/path"].AllowedTools) != 2 {
+		t.Errorf("Expected saved allowed tools, got %v", saved.Projects["/test/path"].AllowedTools)
+	}
+}
+
 func TestConfigFilePath(t *testing.T) {
 	// Save original home dir
 	originalHome := os.Getenv("HOME")
@@ -734,3 +813,34 @@ func TestLoadPreferences_NilProjects(t *testing.T) {
 		t.Errorf("Expected projects map to be initialized after loading null projects")
 	}
 }
+
+func TestLoadPreferences_MissingFieldsKeepDefaults(t *testing.T) {
+	cfg, tempDir := setupTestConfig(t)
+	defer os.RemoveAll(tempDir)
+	cfg.preferences.AutoCompactThreshold = 0.8
+
+	// A config saved before autoCompactThreshold existed
+	data := []byte(`{
+		"preferences": {
+			"authMethod": "anthropic-api",
+			"approvalMode": "auto-edit",
+			"defaultModel": "opus"
+		},
+		"projects": {}
+	}`)
+
+	if err := os.WriteFile(cfg.configPath, data, 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	if err := cfg.load(); err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	if cfg.preferences.AutoCompactThreshold != 0.8 {
+		t.Errorf("AutoCompactThreshold = %v, want default 0.8", cfg.preferences.AutoCompactThreshold)
+	}
+	if cfg.preferences.DefaultModel != "opus" {
+		t.Errorf("DefaultModel = %q, want saved value opus", cfg.preferences.DefaultModel)
+	}
+}
@@ -25,6 +25,7 @@ type FileDiff struct {
 	IsNew    bool          `json:"isNew"`
 	IsDelete bool          `json:"isDelete"`
 	IsBinary bool          `json:"isBinary"`
+	Mode     string        `json:"mode,omitempty"` // File mode of a new or deleted file
 	Approved bool          `json:"approved,omitempty"`
 	Comments []DiffComment `json:"comments,omitempty"`
 }
@@ -46,6 +47,9 @@ type Line struct {
 	Content string   `json:"content"`
 	OldNum  int      `json:"oldNum,omitempty"`
 	NewNum  int      `json:"newNum,omitempty"`
+
+	// NoNewline marks the last line of a file that has no trailing newline
+	NoNewline bool `json:"noNewline,omitempty"`
 }
 
 // LineType represents the type of change for a line
@@ -57,11 +61,22 @@ const (
 	LineTypeDeletion LineType = "deletion"
 )
 
-// generateHunkID creates a unique identifier for a hunk
-func generateHunkID(filePath string, oldStart, newStart int) string {
-	input := fmt.Sprintf("%s:%d:%d", filePath, oldStart, newStart)
-	hash := sha256.Sum256([]byte(input))
-	return hex.EncodeToString(hash[:8]) // Use first 8 bytes for shorter IDs
+// generateHunkID creates a unique identifier for a hunk. The hunk's lines are
+// part of it, so a hunk whose content changes gets a new ID and selections
+// made against the old content no longer match.
+func generateHunkID(filePath string, hunk *Hunk) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s:%d:%d\n", filePath, hunk.OldStart, hunk.NewStart)
+	for _, line := range hunk.Lines {
+		fmt.Fprintf(h, "%s%s:%t\n", line.Type, line.Content, line.NoNewline)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8]) // Use first 8 bytes for shorter IDs
+}
+
+// appendHunk adds a fully parsed hunk to fd
+func appendHunk(fd *FileDiff, hunk *Hunk) {
+	hunk.ID = generateHunkID(fd.NewPath, hunk)
+	fd.Hunks = append(fd.Hunks, *hunk)
 }
 
 // ParseUnifiedDiff parses a unified diff string into FileDiffs
@@ -85,7 +100,7 @@ func ParseUnifiedDiff(diffText string) ([]FileDiff, error) {
 		if strings.HasPrefix(line, "diff --git") {
 			if currentDiff != nil {
 				if currentHunk != nil {
-					currentDiff.Hunks = append(currentDiff.Hunks, *currentHunk)
+					appendHunk(currentDiff, currentHunk)
 				}
 				diffs = append(diffs, *currentDiff)
 			}
@@ -131,10 +146,17 @@ func ParseUnifiedDiff(diffText string) ([]FileDiff, error) {
 			continue
 		}
 
+		if strings.HasPrefix(line, "new file mode ") {
+			currentDiff.Mode = strings.TrimPrefix(line, "new file mode ")
+			continue
+		}
+		if strings.HasPrefix(line, "deleted file mode ") {
+			currentDiff.Mode = strings.TrimPrefix(line, "deleted file mode ")
+			continue
+		}
+
 		// Skip metadata lines
-		if strings.HasPrefix(line, "new file mode") ||
-			strings.HasPrefix(line, "deleted file mode") ||
-			strings.HasPrefix(line, "index ") ||
+		if strings.HasPrefix(line, "index ") ||
 			strings.HasPrefix(line, "similarity index") ||
 			strings.HasPrefix(line, "rename from") ||
 			strings.HasPrefix(line, "rename to") {
@@ -144,12 +166,10 @@ func ParseUnifiedDiff(diffText string) ([]FileDiff, error) {
 		// Parse hunk header
 		if strings.HasPrefix(line, "@@") {
 			if currentHunk != nil {
-				currentDiff.Hunks = append(currentDiff.Hunks, *currentHunk)
+				appendHunk(currentDiff, currentHunk)
 			}
 
 			hunk := parseHunkHeader(line)
-			// Generate unique hunk ID
-			hunk.ID = generateHunkID(currentDiff.NewPath, hunk.OldStart, hunk.NewStart)
 			currentHunk = &hunk
 			oldLineNum = hunk.OldStart
 			newLineNum = hunk.NewStart
@@ -160,6 +180,14 @@ func ParseUnifiedDiff(diffText string) ([]FileDiff, error) {
 			continue
 		}
 
+		// "\ No newline at end of file" applies to the line before it
+		if strings.HasPrefix(line, "\\") {
+			if n := len(currentHunk.Lines); n > 0 {
+				currentHunk.Lines[n-1].NoNewline = true
+			}
+			continue
+		}
+
 		// Parse diff lines
 		if len(line) == 0 {
 			// Skip trailing empty lines
@@ -207,7 +235,7 @@ func ParseUnifiedDiff(diffText string) ([]FileDiff, error) {
 	// Don't forget the last diff
 	if currentDiff != nil {
 		if currentHunk != nil {
-			currentDiff.Hunks = append(currentDiff.Hunks, *currentHunk)
+			appendHunk(currentDiff, currentHunk)
 		}
 		diffs = append(diffs, *currentDiff)
 	}
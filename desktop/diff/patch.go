@@ -0,0 +1,181 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HunkSelection picks lines of one hunk to include in a patch. Start and End
+// index Hunk.Lines, end exclusive; a zero End selects through the end of the
+// hunk, so a selection with only HunkID set takes the whole hunk. A hunk's ID
+// covers its lines, so a selection made before the hunk changed no longer
+// finds it.
+type HunkSelection struct {
+	HunkID string `json:"hunkId"`
+	Start  int    `json:"start,omitempty"`
+	End    int    `json:"end,omitempty"`
+}
+
+// BuildPatch returns a patch holding only the selected changes of fd, for
+// `git apply`. A forward patch applies to the diff's old side (as when
+// staging); unselected additions are dropped and unselected deletions kept as
+// context. A reverse patch is for `git apply -R` against the new side (as when
+// discarding or unstaging), so unselected additions become context instead.
+func BuildPatch(fd FileDiff, selections []HunkSelection, reverse bool) (string, error) {
+	if fd.IsBinary {
+		return "", fmt.Errorf("cannot build a partial patch for binary file %s", fd.filePath())
+	}
+
+	selected := map[string][][2]int{}
+	for _, sel := range selections {
+		hunk := fd.hunkByID(sel.HunkID)
+		if hunk == nil {
+			return "", fmt.Errorf("hunk %s not found in %s", sel.HunkID, fd.filePath())
+		}
+		end := sel.End
+		if end == 0 {
+			end = len(hunk.Lines)
+		}
+		if sel.Start < 0 || end > len(hunk.Lines) || sel.Start >= end {
+			return "", fmt.Errorf("invalid line range %d-%d for hunk %s", sel.Start, end, sel.HunkID)
+		}
+		selected[sel.HunkID] = append(selected[sel.HunkID], [2]int{sel.Start, end})
+	}
+
+	isSelected := func(hunkID string, i int) bool {
+		for _, r := range selected[hunkID] {
+			if i >= r[0] && i < r[1] {
+				return true
+			}
+		}
+		return false
+	}
+
+	type patchHunk struct {
+		oldStart, oldLines, newStart, newLines int
+		lines                                  []string
+	}
+	var hunks []patchHunk
+	delta := 0
+	for _, h := range fd.Hunks {
+		if _, ok := selected[h.ID]; !ok {
+			continue
+		}
+
+		ph := patchHunk{}
+		changed := false
+		for i, line := range h.Lines {
+			prefix := " "
+			switch line.Type {
+			case LineTypeAddition:
+				if isSelected(h.ID, i) {
+					prefix = "+"
+					changed = true
+				} else if !reverse {
+					continue
+				}
+			case LineTypeDeletion:
+				if isSelected(h.ID, i) {
+					prefix = "-"
+					changed = true
+				} else if reverse {
+					continue
+				}
+			}
+
+			if prefix != "+" {
+				ph.oldLines++
+			}
+			if prefix != "-" {
+				ph.newLines++
+			}
+			ph.lines = append(ph.lines, prefix+line.Content)
+			if line.NoNewline {
+				ph.lines = append(ph.lines, `\ No newline at end of file`)
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		// The side the patch applies to keeps its line numbers; the other
+		// side shifts by what earlier hunks in this patch add or remove
+		if reverse {
+			ph.newStart = h.NewStart
+			ph.oldStart = hunkStart(firstLine(h.NewStart, h.NewLines)-delta, ph.oldLines)
+		} else {
+			ph.oldStart = h.OldStart
+			ph.newStart = hunkStart(firstLine(h.OldStart, h.OldLines)+delta, ph.newLines)
+		}
+		delta += ph.newLines - ph.oldLines
+		hunks = append(hunks, ph)
+	}
+
+	if len(hunks) == 0 {
+		return "", fmt.Errorf("no changes selected in %s", fd.filePath())
+	}
+
+	// Unselected lines can turn a whole-file creation or deletion into an
+	// edit of an existing file
+	isNew, isDelete := fd.IsNew, fd.IsDelete
+	for _, h := range hunks {
+		if h.oldLines > 0 {
+			isNew = false
+		}
+		if h.newLines > 0 {
+			isDelete = false
+		}
+	}
+
+	path := fd.filePath()
+	mode := fd.Mode
+	if mode == "" {
+		mode = "100644"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("diff --git a/%s b/%s\n", path, path))
+	switch {
+	case isNew:
+		sb.WriteString(fmt.Sprintf("new file mode %s\n--- /dev/null\n+++ b/%s\n", mode, path))
+	case isDelete:
+		sb.WriteString(fmt.Sprintf("deleted file mode %s\n--- a/%s\n+++ /dev/null\n", mode, path))
+	default:
+		sb.WriteString(fmt.Sprintf("--- a/%s\n+++ b/%s\n", path, path))
+	}
+	for _, h := range hunks {
+		sb.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", h.oldStart, h.oldLines, h.newStart, h.newLines))
+		for _, line := range h.lines {
+			sb.WriteString(line + "\n")
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// firstLine returns the first line a hunk side covers. An empty side's start
+// is the line before it, so the hunk begins on the next one.
+func firstLine(start, count int) int {
+	if count == 0 {
+		return start + 1
+	}
+	return start
+}
+
+// hunkStart is the inverse of firstLine, for writing a hunk header
+func hunkStart(first, count int) int {
+	if count == 0 {
+		return first - 1
+	}
+	return first
+}
+
+// hunkByID returns the hunk with the given ID
+func (fd *FileDiff) hunkByID(id string) *Hunk {
+	for i := range fd.Hunks {
+		if fd.Hunks[i].ID == id {
+			return &fd.Hunks[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+const patchDiff = `diff --git a/list.txt b/list.txt
+--- a/list.txt
++++ b/list.txt
+@@ -1,4 +1,4 @@
+ one
+-two
++TWO
+ three
+ four
+@@ -10,3 +10,4 @@
+ ten
++ten and a half
+ eleven
+ twelve
+`
+
+func parsePatchDiff(t *testing.T, text string) FileDiff {
+	t.Helper()
+	diffs, err := ParseUnifiedDiff(text)
+	if err != nil || len(diffs) != 1 {
+		t.Fatalf("ParseUnifiedDiff() = %d diffs, error = %v", len(diffs), err)
+	}
+	return diffs[0]
+}
+
+func TestBuildPatch_SingleHunk(t *testing.T) {
+	fd := parsePatchDiff(t, patchDiff)
+
+	patch, err := BuildPatch(fd, []HunkSelection{{HunkID: fd.Hunks[1].ID}}, false)
+	if err != nil {
+		t.Fatalf("BuildPatch() error = %v", err)
+	}
+
+	want := `diff --git a/list.txt b/list.txt
+--- a/list.txt
++++ b/list.txt
+@@ -10,3 +10,4 @@
+ ten
++ten and a half
+ eleven
+ twelve
+`
+	if patch != want {
+		t.Errorf("BuildPatch() =\n%s\nwant\n%s", patch, want)
+	}
+}
+
+func TestBuildPatch_ShiftsLaterHunks(t *testing.T) {
+	diffText := `diff --git a/list.txt b/list.txt
+--- a/list.txt
++++ b/list.txt
+@@ -1,3 +1,5 @@
+ one
++new a
++new b
+ two
+ three
+@@ -10,3 +12,4 @@
+ ten
++ten and a half
+ eleven
+ twelve
+`
+	fd := parsePatchDiff(t, diffText)
+
+	// Dropping the first hunk's additions moves the second hunk up
+	patch, err := BuildPatch(fd, []HunkSelection{
+		{HunkID: fd.Hunks[0].ID, Start: 1, End: 2},
+		{HunkID: fd.Hunks[1].ID},
+	}, false)
+	if err != nil {
+		t.Fatalf("BuildPatch() error = %v", err)
+	}
+	if !strings.Contains(patch, "@@ -1,3 +1,4 @@\n one\n+new a\n two\n three\n") {
+		t.Errorf("Expected partial first hunk, got:\n%s", patch)
+	}
+	if !strings.Contains(patch, "@@ -10,3 +11,4 @@") {
+		t.Errorf("Expected second hunk shifted by one line, got:\n%s", patch)
+	}
+}
+
+func TestBuildPatch_LineRanges(t *testing.T) {
+	fd := parsePatchDiff(t, patchDiff)
+	hunk := fd.Hunks[0]
+
+	// Only the deletion: forward keeps the unselected addition out
+	forward, err := BuildPatch(fd, []HunkSelection{{HunkID: hunk.ID, Start: 1, End: 2}}, false)
+	if err != nil {
+		t.Fatalf("BuildPatch() error = %v", err)
+	}
+	if !strings.Contains(forward, "@@ -1,4 +1,3 @@\n one\n-two\n three\n four\n") {
+		t.Errorf("Unexpected forward patch:\n%s", forward)
+	}
+
+	// Only the addition, in reverse: the unselected deletion is left out and
+	// the hunk is positioned by the new side
+	reverse, err := BuildPatch(fd, []HunkSelection{{HunkID: hunk.ID, Start: 2, End: 3}}, true)
+	if err != nil {
+		t.Fatalf("BuildPatch() error = %v", err)
+	}
+	if !strings.Contains(reverse, "@@ -1,3 +1,4 @@\n one\n+TWO\n three\n four\n") {
+		t.Errorf("Unexpected reverse patch:\n%s", reverse)
+	}
+}
+
+func TestBuildPatch_NewFile(t *testing.T) {
+	diffText := `diff --git a/new.txt b/new.txt
+new file mode 100755
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1,3 @@
++first
++second
++third
+\ No newline at end of file
+`
+	fd := parsePatchDiff(t, diffText)
+	if fd.Mode != "100755" || !fd.Hunks[0].Lines[2].NoNewline {
+		t.Fatalf("Expected mode and missing newline to be parsed, got %+v", fd)
+	}
+
+	whole, err := BuildPatch(fd, []HunkSelection{{HunkID: fd.Hunks[0].ID}}, false)
+	if err != nil {
+		t.Fatalf("BuildPatch() error = %v", err)
+	}
+	if !strings.Contains(whole, "new file mode 100755\n--- /dev/null\n+++ b/new.txt\n@@ -0,0 +1,3 @@\n") ||
+		!strings.HasSuffix(whole, "+third\n\\ No newline at end of file\n") {
+		t.Errorf("Unexpected new file patch:\n%s", whole)
+	}
+
+	// Discarding part of a new file edits it instead of deleting it
+	partial, err := BuildPatch(fd, []HunkSelection{{HunkID: fd.Hunks[0].ID, Start: 1, End: 2}}, true)
+	if err != nil {
+		t.Fatalf("BuildPatch() error = %v", err)
+	}
+	if strings.Contains(partial, "/dev/null") || !strings.Contains(partial, "@@ -1,2 +1,3 @@\n first\n+second\n third\n") {
+		t.Errorf("Unexpected partial new file patch:\n%s", partial)
+	}
+}
+
+func TestBuildPatch_Errors(t *testing.T) {
+	fd := parsePatchDiff(t, patchDiff)
+
+	tests := []struct {
+		name       string
+		selections []HunkSelection
+	}{
+		{"unknown hunk", []HunkSelection{{HunkID: "missing"}}},
+		{"bad range", []HunkSelection{{HunkID: fd.Hunks[0].ID, Start: 3, End: 2}}},
+		{"range past end", []HunkSelection{{HunkID: fd.Hunks[0].ID, End: 99}}},
+		{"context only", []HunkSelection{{HunkID: fd.Hunks[0].ID, Start: 0, End: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := BuildPatch(fd, tt.selections, false); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+
+	binary := FileDiff{NewPath: "image.png", IsBinary: true}
+	if _, err := BuildPatch(binary, nil, false); err == nil {
+		t.Error("Expected an error for a binary file")
+	}
+}
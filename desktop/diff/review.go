@@ -0,0 +1,233 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/philjestin/boatman-ecosystem/harness/diffverify"
+	"github.com/philjestin/boatman-ecosystem/harness/review"
+)
+
+// maxIssueCodeLines caps how much of a hunk is quoted in a review issue
+const maxIssueCodeLines = 20
+
+// RequestedChange is a review issue raised from the diff view, linked back to
+// the comment or hunk it came from
+type RequestedChange struct {
+	ID        string       `json:"id"` // Comment ID, or hunk ID for an uncommented hunk
+	CommentID string       `json:"commentId,omitempty"`
+	HunkID    string       `json:"hunkId,omitempty"`
+	Issue     review.Issue `json:"issue"`
+}
+
+// ChangeStatus reports whether the agent's follow-up addressed a requested change
+type ChangeStatus struct {
+	ID        string `json:"id"`
+	Addressed bool   `json:"addressed"`
+	Evidence  string `json:"evidence,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// RequestedChanges converts the review state of diffs into review issues.
+// Approved files and hunks are skipped. Every comment on the rest becomes an
+// issue at its line; an unapproved hunk without comments becomes one issue
+// covering the hunk. Comments outside any hunk apply to the whole file.
+func RequestedChanges(diffs []FileDiff) []RequestedChange {
+	changes := []RequestedChange{}
+
+	for _, fd := range diffs {
+		if fd.Approved {
+			continue
+		}
+		path := fd.filePath()
+
+		comments := make([]DiffComment, len(fd.Comments))
+		copy(comments, fd.Comments)
+		sort.SliceStable(comments, func(i, j int) bool {
+			return comments[i].LineNum < comments[j].LineNum
+		})
+
+		commented := map[string]bool{}
+		for _, c := range comments {
+			if strings.TrimSpace(c.Content) == "" {
+				continue
+			}
+			hunk := fd.hunkFor(c)
+			if hunk != nil && hunk.Approved {
+				continue
+			}
+
+			change := RequestedChange{
+				ID:        c.ID,
+				CommentID: c.ID,
+				Issue: review.Issue{
+					Severity:    "major",
+					File:        path,
+					Line:        c.LineNum,
+					Description: strings.TrimSpace(c.Content),
+				},
+			}
+			if hunk != nil {
+				change.HunkID = hunk.ID
+				change.Issue.Code = hunk.code()
+				commented[hunk.ID] = true
+			}
+			changes = append(changes, change)
+		}
+
+		for _, h := range fd.Hunks {
+			if h.Approved || commented[h.ID] {
+				continue
+			}
+			start, end := h.newRange()
+			changes = append(changes, RequestedChange{
+				ID:     h.ID,
+				HunkID: h.ID,
+				Issue: review.Issue{
+					Severity:    "minor",
+					File:        path,
+					Line:        start,
+					Description: fmt.Sprintf("The change to lines %d-%d was not approved", start, end),
+					Suggestion:  "Rework or revert this change",
+					Code:        h.code(),
+				},
+			})
+		}
+	}
+
+	return changes
+}
+
+// Issues returns the review issues of changes, in order
+func Issues(changes []RequestedChange) []review.Issue {
+	issues := make([]review.Issue, len(changes))
+	for i, c := range changes {
+		issues[i] = c.Issue
+	}
+	return issues
+}
+
+// FormatRequestedChanges renders changes as a message asking the agent to
+// address them
+func FormatRequestedChanges(changes []RequestedChange) string {
+	var sb strings.Builder
+	sb.WriteString("Please address the following review comments on your changes. ")
+	sb.WriteString("Edit the code in place and keep unrelated work as it is.\n")
+
+	for i, c := range changes {
+		issue := c.Issue
+		location := issue.File
+		if issue.Line > 0 {
+			location = fmt.Sprintf("%s:%d", issue.File, issue.Line)
+		}
+		sb.WriteString(fmt.Sprintf("\n%d. %s (%s)\n   %s\n", i+1, location, issue.Severity, issue.Description))
+		if issue.Suggestion != "" {
+			sb.WriteString(fmt.Sprintf("   Suggestion: %s\n", issue.Suggestion))
+		}
+		if issue.Code != "" {
+			sb.WriteString("   ```diff\n")
+			for _, line := range strings.Split(issue.Code, "\n") {
+				sb.WriteString("   " + line + "\n")
+			}
+			sb.WriteString("   ```\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// VerifyRequestedChanges checks which changes the diff produced since they
+// were requested (newDiff) addresses, relative to the diff the reviewer saw
+// (oldDiff)
+func VerifyRequestedChanges(ctx context.Context, worktreePath string, changes []RequestedChange, oldDiff, newDiff string) ([]ChangeStatus, error) {
+	verifier := diffverify.New(worktreePath)
+	result, err := verifier.Verify(ctx, Issues(changes), oldDiff, newDiff)
+	if err != nil {
+		return nil, err
+	}
+
+	// Results carry the original issue, not its position, so match them back
+	// in order; identical issues resolve to their changes first to last
+	pending := map[review.Issue][]int{}
+	for i, c := range changes {
+		pending[c.Issue] = append(pending[c.Issue], i)
+	}
+	statuses := make([]ChangeStatus, len(changes))
+	for i, c := range changes {
+		statuses[i] = ChangeStatus{ID: c.ID}
+	}
+	take := func(issue review.Issue) *ChangeStatus {
+		queue := pending[issue]
+		if len(queue) == 0 {
+			return nil
+		}
+		pending[issue] = queue[1:]
+		return &statuses[queue[0]]
+	}
+
+	for _, addressed := range result.AddressedIssues {
+		if status := take(addressed.Original); status != nil {
+			status.Addressed = true
+			status.Evidence = addressed.FixEvidence
+		}
+	}
+	for _, unaddressed := range result.UnaddressedIssues {
+		if status := take(unaddressed.Original); status != nil {
+			status.Reason = unaddressed.Reason
+		}
+	}
+
+	return statuses, nil
+}
+
+// filePath returns the path a diff applies to, preferring the new path
+func (fd *FileDiff) filePath() string {
+	if fd.NewPath != "" && fd.NewPath != "/dev/null" {
+		return fd.NewPath
+	}
+	return fd.OldPath
+}
+
+// hunkFor returns the hunk a comment belongs to, by ID or by line
+func (fd *FileDiff) hunkFor(c DiffComment) *Hunk {
+	if c.HunkID != "" {
+		return fd.hunkByID(c.HunkID)
+	}
+	for i := range fd.Hunks {
+		start, end := fd.Hunks[i].newRange()
+		if c.LineNum >= start && c.LineNum <= end {
+			return &fd.Hunks[i]
+		}
+	}
+	return nil
+}
+
+// newRange returns the first and last new-file lines a hunk covers
+func (h *Hunk) newRange() (int, int) {
+	if h.NewLines == 0 {
+		return h.NewStart, h.NewStart
+	}
+	return h.NewStart, h.NewStart + h.NewLines - 1
+}
+
+// code renders a hunk's lines in unified diff form for quoting in an issue
+func (h *Hunk) code() string {
+	var lines []string
+	for i, line := range h.Lines {
+		if i == maxIssueCodeLines {
+			lines = append(lines, fmt.Sprintf("... (%d more lines)", len(h.Lines)-i))
+			break
+		}
+		prefix := " "
+		switch line.Type {
+		case LineTypeAddition:
+			prefix = "+"
+		case LineTypeDeletion:
+			prefix = "-"
+		}
+		lines = append(lines, prefix+line.Content)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,148 @@
+package diff
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const reviewDiff = `diff --git a/app.go b/app.go
+--- a/app.go
++++ b/app.go
+@@ -1,3 +1,4 @@
+ package main
++
++import "fmt"
+-import "os"
+@@ -20,2 +21,3 @@
+ func main() {
++	fmt.Println("debug")
+ }
+diff --git a/old.go b/old.go
+deleted file mode 100644
+--- a/old.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package main
+-func old() {}
+`
+
+func parseReviewDiff(t *testing.T) []FileDiff {
+	t.Helper()
+	diffs, err := ParseUnifiedDiff(reviewDiff)
+	if err != nil || len(diffs) != 2 {
+		t.Fatalf("ParseUnifiedDiff() = %d diffs, error = %v", len(diffs), err)
+	}
+	return diffs
+}
+
+func TestRequestedChanges_CommentsAndHunks(t *testing.T) {
+	diffs := parseReviewDiff(t)
+	diffs[0].Comments = []DiffComment{
+		{ID: "c2", LineNum: 22, Content: "Remove the debug print"},
+		{ID: "c1", LineNum: 3, HunkID: diffs[0].Hunks[0].ID, Content: "Why drop os?"},
+		{ID: "c3", LineNum: 2, Content: "   "},
+	}
+
+	changes := RequestedChanges(diffs)
+	if len(changes) != 3 {
+		t.Fatalf("Expected 3 changes, got %d: %+v", len(changes), changes)
+	}
+
+	// Comments come first, by line
+	if changes[0].ID != "c1" || changes[0].HunkID != diffs[0].Hunks[0].ID {
+		t.Errorf("Unexpected first change: %+v", changes[0])
+	}
+	if changes[1].ID != "c2" || changes[1].HunkID != diffs[0].Hunks[1].ID {
+		t.Errorf("Comment should be matched to its hunk by line: %+v", changes[1])
+	}
+	issue := changes[1].Issue
+	if issue.File != "app.go" || issue.Line != 22 || issue.Severity != "major" || issue.Description != "Remove the debug print" {
+		t.Errorf("Unexpected issue: %+v", issue)
+	}
+	if !strings.Contains(issue.Code, `+	fmt.Println("debug")`) {
+		t.Errorf("Issue should quote the hunk, got %q", issue.Code)
+	}
+
+	// The deleted file has no comments, so its unapproved hunk is an issue
+	deleted := changes[2]
+	if deleted.CommentID != "" || deleted.ID != diffs[1].Hunks[0].ID {
+		t.Errorf("Expected a hunk change, got %+v", deleted)
+	}
+	if deleted.Issue.File != "old.go" || deleted.Issue.Severity != "minor" {
+		t.Errorf("Unexpected hunk issue: %+v", deleted.Issue)
+	}
+}
+
+func TestRequestedChanges_SkipsApproved(t *testing.T) {
+	diffs := parseReviewDiff(t)
+	diffs[0].Hunks[0].Approved = true
+	diffs[0].Comments = []DiffComment{
+		{ID: "approved-hunk", LineNum: 2, Content: "Fine as is"},
+		{ID: "file-level", LineNum: 100, Content: "Add a test"},
+	}
+	diffs[1].Approved = true
+
+	changes := RequestedChanges(diffs)
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %+v", changes)
+	}
+	if changes[0].ID != "file-level" || changes[0].HunkID != "" || changes[0].Issue.Code != "" {
+		t.Errorf("Comment outside hunks should apply to the file: %+v", changes[0])
+	}
+	if changes[1].ID != diffs[0].Hunks[1].ID {
+		t.Errorf("Expected the unapproved hunk, got %+v", changes[1])
+	}
+}
+
+func TestFormatRequestedChanges(t *testing.T) {
+	diffs := parseReviewDiff(t)
+	diffs[0].Comments = []DiffComment{{ID: "c1", LineNum: 22, Content: "Remove the debug print"}}
+	diffs[1].Approved = true
+
+	msg := FormatRequestedChanges(RequestedChanges(diffs))
+	for _, want := range []string{
+		"1. app.go:22 (major)",
+		"Remove the debug print",
+		"2. app.go:1 (minor)",
+		"Suggestion: Rework or revert this change",
+		"```diff",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Message missing %q:\n%s", want, msg)
+		}
+	}
+}
+
+func TestVerifyRequestedChanges(t *testing.T) {
+	diffs := parseReviewDiff(t)
+	diffs[0].Hunks[0].Approved = true
+	diffs[0].Comments = []DiffComment{{ID: "c1", LineNum: 22, Content: "Remove the debug print"}}
+	changes := RequestedChanges(diffs)
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %+v", changes)
+	}
+
+	// The follow-up only touches app.go
+	followUp := `diff --git a/app.go b/app.go
+--- a/app.go
++++ b/app.go
+@@ -21,3 +21,2 @@
+ func main() {
+-	fmt.Println("debug")
+ }
+`
+	statuses, err := VerifyRequestedChanges(context.Background(), t.TempDir(), changes, reviewDiff, followUp)
+	if err != nil {
+		t.Fatalf("VerifyRequestedChanges() error = %v", err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("Expected a status per change, got %+v", statuses)
+	}
+	if statuses[0].ID != "c1" || !statuses[0].Addressed || statuses[0].Evidence == "" {
+		t.Errorf("Comment should be addressed: %+v", statuses[0])
+	}
+	if statuses[1].ID != diffs[1].Hunks[0].ID || statuses[1].Addressed || statuses[1].Reason == "" {
+		t.Errorf("Untouched file should not be addressed: %+v", statuses[1])
+	}
+}
@@ -0,0 +1,273 @@
+// Package export writes sessions out as transcripts and portable bundles for
+// sharing, and reads bundles back in.
+//
+// A bundle is a zip archive holding:
+//
+//	bundle.json   session details, messages (with tool calls), tasks and costs
+//	final.diff    the changes the session left behind
+//	events.jsonl  the run's event log, one event per line
+//
+// Everything in a bundle has been through a Redactor.
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"boatman/agent"
+)
+
+// BundleVersion is the bundle format written by this version of the app
+const BundleVersion = 1
+
+// BundleExtension is the file extension for bundles
+const BundleExtension = ".boatman.zip"
+
+const (
+	bundleFile = "bundle.json"
+	diffFile   = "final.diff"
+	eventsFile = "events.jsonl"
+)
+
+// SessionInfo describes the exported session
+type SessionInfo struct {
+	ID          string                 `json:"id"`
+	ProjectPath string                 `json:"projectPath"`
+	Mode        string                 `json:"mode,omitempty"`
+	ModeConfig  map[string]interface{} `json:"modeConfig,omitempty"`
+	Model       string                 `json:"model,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+	CreatedAt   time.Time              `json:"createdAt"`
+	ParentID    string                 `json:"parentId,omitempty"`
+}
+
+// Costs totals the token usage and cost reported over the session
+type Costs struct {
+	InputTokens  int     `json:"inputTokens"`
+	OutputTokens int     `json:"outputTokens"`
+	TotalCost    float64 `json:"totalCost"`
+}
+
+// Bundle is an exported session
+type Bundle struct {
+	Version    int             `json:"version"`
+	ExportedAt time.Time       `json:"exportedAt"`
+	Session    SessionInfo     `json:"session"`
+	Messages   []agent.Message `json:"messages"`
+	Tasks      []agent.Task    `json:"tasks"`
+	Costs      Costs           `json:"costs"`
+
+	// Stored in their own files
+	Diff   string           `json:"-"`
+	Events []agent.RunEvent `json:"-"`
+}
+
+// New builds a bundle from a session and the diff of the changes it made.
+// The event log holds the session's recorded workflow events along with its
+// tool calls, in time order.
+func New(session *agent.Session, diff string) *Bundle {
+	messages := session.GetMessages()
+
+	b := &Bundle{
+		Version:    BundleVersion,
+		ExportedAt: time.Now(),
+		Session: SessionInfo{
+			ID:          session.ID,
+			ProjectPath: session.ProjectPath,
+			Mode:        session.Mode,
+			ModeConfig:  session.ModeConfig,
+			Model:       session.Model,
+			Tags:        session.GetTags(),
+			CreatedAt:   session.CreatedAt,
+			ParentID:    session.ParentID,
+		},
+		Messages: messages,
+		Tasks:    session.GetTasks(),
+		Diff:     diff,
+		Events:   eventLog(session.GetEvents(), messages),
+	}
+
+	for _, msg := range messages {
+		if msg.Metadata != nil && msg.Metadata.CostInfo != nil {
+			b.Costs.InputTokens += msg.Metadata.CostInfo.InputTokens
+			b.Costs.OutputTokens += msg.Metadata.CostInfo.OutputTokens
+			b.Costs.TotalCost += msg.Metadata.CostInfo.TotalCost
+		}
+	}
+	return b
+}
+
+// eventLog merges recorded events with the tool calls in messages
+func eventLog(events []agent.RunEvent, messages []agent.Message) []agent.RunEvent {
+	log := append([]agent.RunEvent(nil), events...)
+	for _, msg := range messages {
+		if msg.Metadata == nil {
+			continue
+		}
+		if use := msg.Metadata.ToolUse; use != nil {
+			log = append(log, agent.RunEvent{Time: msg.Timestamp, Type: "tool_use", Data: map[string]interface{}{
+				"toolName": use.ToolName,
+				"toolId":   use.ToolID,
+			}})
+		}
+		if result := msg.Metadata.ToolResult; result != nil {
+			log = append(log, agent.RunEvent{Time: msg.Timestamp, Type: "tool_result", Data: map[string]interface{}{
+				"toolId":  result.ToolID,
+				"isError": result.IsError,
+			}})
+		}
+	}
+	sort.SliceStable(log, func(i, j int) bool { return log[i].Time.Before(log[j].Time) })
+	return log
+}
+
+// Redact removes secrets from everything in the bundle
+func (b *Bundle) Redact(r *Redactor) error {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	var redacted Bundle
+	if err := json.Unmarshal([]byte(r.String(string(data))), &redacted); err != nil {
+		return fmt.Errorf("failed to redact bundle: %w", err)
+	}
+
+	redacted.Events, err = redactEvents(r, b.Events)
+	if err != nil {
+		return err
+	}
+	redacted.Diff = r.String(b.Diff)
+
+	*b = redacted
+	return nil
+}
+
+// redactEvents redacts each event through its JSON form
+func redactEvents(r *Redactor, events []agent.RunEvent) ([]agent.RunEvent, error) {
+	redacted := make([]agent.RunEvent, len(events))
+	for i, event := range events {
+		line, err := json.Marshal(event)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(r.String(string(line))), &redacted[i]); err != nil {
+			return nil, fmt.Errorf("failed to redact event: %w", err)
+		}
+	}
+	return redacted, nil
+}
+
+// Write writes the bundle as a zip archive
+func (b *Bundle) Write(w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeZipFile(zw, bundleFile, data); err != nil {
+		return err
+	}
+	if err := writeZipFile(zw, diffFile, []byte(b.Diff)); err != nil {
+		return err
+	}
+
+	var events bytes.Buffer
+	for _, event := range b.Events {
+		line, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		events.Write(line)
+		events.WriteByte('\n')
+	}
+	if err := writeZipFile(zw, eventsFile, events.Bytes()); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func writeZipFile(zw *zip.Writer, name string, data []byte) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	return err
+}
+
+// Read reads a bundle written by Write
+func Read(r io.ReaderAt, size int64) (*Bundle, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("not a session bundle: %w", err)
+	}
+
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	if files[bundleFile] == nil {
+		return nil, fmt.Errorf("not a session bundle: missing %s", bundleFile)
+	}
+
+	data, err := readZipFile(files[bundleFile])
+	if err != nil {
+		return nil, err
+	}
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", bundleFile, err)
+	}
+	if b.Version > BundleVersion {
+		return nil, fmt.Errorf("bundle version %d is newer than this app supports (%d)", b.Version, BundleVersion)
+	}
+
+	if f := files[diffFile]; f != nil {
+		diff, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		b.Diff = string(diff)
+	}
+
+	if f := files[eventsFile]; f != nil {
+		data, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+		for scanner.Scan() {
+			if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+				continue
+			}
+			var event agent.RunEvent
+			if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", eventsFile, err)
+			}
+			b.Events = append(b.Events, event)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &b, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
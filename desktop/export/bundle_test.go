@@ -0,0 +1,131 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"boatman/agent"
+)
+
+const testSecret = "lin_api_abcdefghijklmnopqrstuvwxyz0123456789"
+
+func newTestSession() *agent.Session {
+	start := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	session := agent.NewSession("session-1", "/path/to/project")
+	session.Mode = "boatmanmode"
+	session.Model = "opus"
+	session.Messages = []agent.Message{
+		{ID: "m1", Role: "user", Content: "Fix the login bug using key " + testSecret, Timestamp: start},
+		{ID: "m2", Role: "assistant", Content: "Reading the handler", Timestamp: start.Add(time.Second), Metadata: &agent.MessageMetadata{
+			ToolUse: &agent.ToolUse{ToolName: "Read", ToolID: "t1", Input: json.RawMessage(`{"file_path":"login.go"}`)},
+		}},
+		{ID: "m3", Role: "assistant", Content: "package login", Timestamp: start.Add(2 * time.Second), Metadata: &agent.MessageMetadata{
+			ToolResult: &agent.ToolResult{ToolID: "t1", Content: "package login"},
+		}},
+		{ID: "m4", Role: "assistant", Content: "Fixed <the> bug", Timestamp: start.Add(3 * time.Second), Metadata: &agent.MessageMetadata{
+			CostInfo: &agent.CostInfo{InputTokens: 100, OutputTokens: 50, TotalCost: 0.25},
+		}},
+	}
+	session.Tasks = []agent.Task{
+		{ID: "execute", Subject: "Execute", Status: "completed", Metadata: map[string]interface{}{"files_changed": 1.0}},
+	}
+	session.RecordEvent("agent_started", map[string]interface{}{"name": "planner"})
+	return session
+}
+
+func TestBundle_RoundTrip(t *testing.T) {
+	b := New(newTestSession(), "+LINEAR_API_KEY="+testSecret+"\n")
+	if err := b.Redact(NewRedactor()); err != nil {
+		t.Fatalf("Redact() error = %v", err)
+	}
+
+	if b.Costs.TotalCost != 0.25 || b.Costs.InputTokens != 100 {
+		t.Errorf("Unexpected costs: %+v", b.Costs)
+	}
+	if len(b.Events) != 3 {
+		t.Errorf("Expected the recorded event and two tool events, got %+v", b.Events)
+	}
+
+	var buf bytes.Buffer
+	if err := b.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte(testSecret)) {
+		t.Error("Bundle contains a secret")
+	}
+
+	read, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if read.Session.ID != "session-1" || read.Session.Mode != "boatmanmode" || len(read.Messages) != 4 {
+		t.Errorf("Session not preserved: %+v, %d messages", read.Session, len(read.Messages))
+	}
+	if use := read.Messages[1].Metadata.ToolUse; use == nil || use.ToolName != "Read" {
+		t.Errorf("Tool call not preserved: %+v", read.Messages[1].Metadata)
+	}
+	if len(read.Tasks) != 1 || read.Tasks[0].Metadata["files_changed"] != 1.0 {
+		t.Errorf("Task metadata not preserved: %+v", read.Tasks)
+	}
+	if read.Diff != "+LINEAR_API_KEY="+Redacted+"\n" {
+		t.Errorf("Diff = %q", read.Diff)
+	}
+	if len(read.Events) != 3 || read.Events[0].Type != "tool_use" || read.Events[2].Type != "agent_started" {
+		t.Errorf("Events not preserved: %+v", read.Events)
+	}
+}
+
+func TestRead_Errors(t *testing.T) {
+	if _, err := Read(strings.NewReader("not a zip"), 9); err == nil {
+		t.Error("Expected an error reading a non-zip file")
+	}
+
+	b := New(newTestSession(), "")
+	b.Version = BundleVersion + 1
+	var buf bytes.Buffer
+	if err := b.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if _, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len())); err == nil {
+		t.Error("Expected an error reading a newer bundle")
+	}
+}
+
+func TestBundle_Transcripts(t *testing.T) {
+	b := New(newTestSession(), "+fixed\n")
+	if err := b.Redact(NewRedactor()); err != nil {
+		t.Fatalf("Redact() error = %v", err)
+	}
+
+	markdown := b.Markdown()
+	for _, want := range []string{"# boatmanmode session: project", "### User", "### Tool: Read", `"file_path": "login.go"`, "- [x] Execute (completed)", "$0.2500", "```diff\n+fixed\n```"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Markdown missing %q:\n%s", want, markdown)
+		}
+	}
+
+	html, err := b.HTML()
+	if err != nil {
+		t.Fatalf("HTML() error = %v", err)
+	}
+	if !strings.Contains(html, "Fixed &lt;the&gt; bug") || strings.Contains(html, "<the>") {
+		t.Error("HTML content not escaped")
+	}
+
+	for name, transcript := range map[string]string{"markdown": markdown, "html": html} {
+		if strings.Contains(transcript, testSecret) {
+			t.Errorf("%s transcript contains a secret", name)
+		}
+	}
+}
+
+func TestFence_LongerThanContent(t *testing.T) {
+	got := fence("", "a ``` b")
+	if !strings.HasPrefix(got, "````\n") || !strings.HasSuffix(got, "\n````\n") {
+		t.Errorf("fence() = %q", got)
+	}
+}
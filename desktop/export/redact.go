@@ -0,0 +1,70 @@
+package export
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Redacted replaces secrets in exported text
+const Redacted = "[REDACTED]"
+
+// minSecretLength is the shortest configured secret that is redacted by
+// value; shorter values would match ordinary text.
+const minSecretLength = 8
+
+// secretPatterns match credentials by their well-known shapes
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`sk-ant-[A-Za-z0-9_\-]{20,}`),                                         // Anthropic
+	regexp.MustCompile(`sk-[A-Za-z0-9_\-]{32,}`),                                             // OpenAI-style
+	regexp.MustCompile(`gh[pousr]_[A-Za-z0-9]{36,}`),                                         // GitHub
+	regexp.MustCompile(`github_pat_[A-Za-z0-9_]{40,}`),                                       // GitHub fine-grained
+	regexp.MustCompile(`lin_(api|oauth)_[A-Za-z0-9]{32,}`),                                   // Linear
+	regexp.MustCompile(`xox[abprs]-[A-Za-z0-9\-]{10,}`),                                      // Slack
+	regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`),                                        // AWS access key
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]{10,}\.[A-Za-z0-9_\-]{10,}\.[A-Za-z0-9_\-]{10,}`), // JWT
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+}
+
+// assignmentPattern matches a secret-looking name assigned a value, as in
+// `API_KEY=...`, `"access_token": "..."` or `Authorization: Bearer ...`. The
+// name is kept and the value redacted.
+var assignmentPattern = regexp.MustCompile(
+	`(?i)\b((?:[a-z0-9]+[_-])*(?:api[_-]?key|secret|token|password|passwd|credentials?|authorization)\\?["']?\s*[:=]\s*\\?["']?(?:bearer\s+|basic\s+)?)([^\s"'\\,;}]{8,})`)
+
+// Redactor removes secrets from text before it leaves the machine: values
+// configured in the app, and anything shaped like a credential.
+type Redactor struct {
+	secrets []string
+}
+
+// NewRedactor returns a Redactor that also removes the given secret values
+func NewRedactor(secrets ...string) *Redactor {
+	r := &Redactor{}
+	for _, secret := range secrets {
+		if secret = strings.TrimSpace(secret); len(secret) >= minSecretLength {
+			r.secrets = append(r.secrets, secret)
+		}
+	}
+	// Longest first, so a secret containing another is removed whole
+	sort.Slice(r.secrets, func(i, j int) bool { return len(r.secrets[i]) > len(r.secrets[j]) })
+	return r
+}
+
+// String returns s with secrets replaced by Redacted
+func (r *Redactor) String(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, Redacted)
+	}
+	for _, pattern := range secretPatterns {
+		s = pattern.ReplaceAllString(s, Redacted)
+	}
+	return assignmentPattern.ReplaceAllStringFunc(s, func(match string) string {
+		parts := assignmentPattern.FindStringSubmatch(match)
+		// Numbers are counts and IDs, not secrets
+		if strings.HasPrefix(parts[2], "[REDACTED") || strings.Trim(parts[2], "0123456789") == "" {
+			return match
+		}
+		return parts[1] + Redacted
+	})
+}
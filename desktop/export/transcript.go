@@ -0,0 +1,225 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"strings"
+
+	"boatman/agent"
+)
+
+// Markdown renders the bundle as a Markdown transcript
+func (b *Bundle) Markdown() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# %s\n\n", b.title())
+	for _, field := range b.details() {
+		fmt.Fprintf(&sb, "- **%s:** %s\n", field[0], field[1])
+	}
+
+	if len(b.Tasks) > 0 {
+		sb.WriteString("\n## Tasks\n\n")
+		for _, task := range b.Tasks {
+			check := " "
+			if task.Status == "completed" {
+				check = "x"
+			}
+			fmt.Fprintf(&sb, "- [%s] %s (%s)\n", check, task.Subject, task.Status)
+			if len(task.Metadata) > 0 {
+				sb.WriteString("\n" + indent(fence("json", taskMetadata(task)), "  ") + "\n")
+			}
+		}
+	}
+
+	sb.WriteString("\n## Transcript\n")
+	for _, msg := range b.Messages {
+		fmt.Fprintf(&sb, "\n### %s · %s\n\n", speaker(msg), msg.Timestamp.Format("15:04:05"))
+		switch {
+		case msg.Metadata != nil && msg.Metadata.ToolUse != nil:
+			if content := strings.TrimSpace(msg.Content); content != "" {
+				sb.WriteString(content + "\n\n")
+			}
+			sb.WriteString(fence("json", toolInput(msg.Metadata.ToolUse)))
+		case msg.Metadata != nil && msg.Metadata.ToolResult != nil:
+			if msg.Metadata.ToolResult.IsError {
+				sb.WriteString("**Error**\n\n")
+			}
+			sb.WriteString(fence("", msg.Content))
+		default:
+			sb.WriteString(strings.TrimSpace(msg.Content) + "\n")
+		}
+	}
+
+	if strings.TrimSpace(b.Diff) != "" {
+		sb.WriteString("\n## Final diff\n\n")
+		sb.WriteString(fence("diff", b.Diff))
+	}
+
+	return sb.String()
+}
+
+// HTML renders the bundle as a standalone HTML transcript
+func (b *Bundle) HTML() (string, error) {
+	type entry struct {
+		Speaker string
+		Time    string
+		Class   string
+		Content string
+		Code    string
+	}
+
+	var entries []entry
+	for _, msg := range b.Messages {
+		e := entry{Speaker: speaker(msg), Time: msg.Timestamp.Format("15:04:05"), Class: msg.Role, Content: strings.TrimSpace(msg.Content)}
+		switch {
+		case msg.Metadata != nil && msg.Metadata.ToolUse != nil:
+			e.Class = "tool"
+			e.Code = toolInput(msg.Metadata.ToolUse)
+		case msg.Metadata != nil && msg.Metadata.ToolResult != nil:
+			e.Class = "tool-result"
+			if msg.Metadata.ToolResult.IsError {
+				e.Class = "tool-error"
+			}
+			e.Code, e.Content = e.Content, ""
+		}
+		entries = append(entries, e)
+	}
+
+	type task struct {
+		Subject  string
+		Status   string
+		Metadata string
+	}
+
+	var tasks []task
+	for _, t := range b.Tasks {
+		tasks = append(tasks, task{Subject: t.Subject, Status: t.Status, Metadata: taskMetadata(t)})
+	}
+
+	var buf bytes.Buffer
+	err := transcriptTemplate.Execute(&buf, map[string]interface{}{
+		"Title":   b.title(),
+		"Details": b.details(),
+		"Tasks":   tasks,
+		"Entries": entries,
+		"Diff":    strings.TrimSpace(b.Diff),
+	})
+	return buf.String(), err
+}
+
+func (b *Bundle) title() string {
+	mode := b.Session.Mode
+	if mode == "" {
+		mode = "Claude"
+	}
+	return fmt.Sprintf("%s session: %s", mode, filepath.Base(b.Session.ProjectPath))
+}
+
+// details lists the session's header fields as label, value pairs
+func (b *Bundle) details() [][2]string {
+	details := [][2]string{
+		{"Session", b.Session.ID},
+		{"Project", b.Session.ProjectPath},
+	}
+	if b.Session.Model != "" {
+		details = append(details, [2]string{"Model", b.Session.Model})
+	}
+	details = append(details,
+		[2]string{"Started", b.Session.CreatedAt.Format("2006-01-02 15:04")},
+		[2]string{"Exported", b.ExportedAt.Format("2006-01-02 15:04")},
+		[2]string{"Cost", fmt.Sprintf("$%.4f (%d input, %d output tokens)", b.Costs.TotalCost, b.Costs.InputTokens, b.Costs.OutputTokens)},
+	)
+	if len(b.Session.Tags) > 0 {
+		details = append(details, [2]string{"Tags", strings.Join(b.Session.Tags, ", ")})
+	}
+	return details
+}
+
+func speaker(msg agent.Message) string {
+	switch {
+	case msg.Metadata != nil && msg.Metadata.ToolUse != nil:
+		return "Tool: " + msg.Metadata.ToolUse.ToolName
+	case msg.Metadata != nil && msg.Metadata.ToolResult != nil:
+		return "Tool result"
+	case msg.Role == "user":
+		return "User"
+	case msg.Role == "system":
+		return "System"
+	default:
+		return "Claude"
+	}
+}
+
+func toolInput(use *agent.ToolUse) string {
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, use.Input, "", "  "); err != nil {
+		return string(use.Input)
+	}
+	return pretty.String()
+}
+
+// taskMetadata formats a task's phase metadata, or returns "" if it has none
+func taskMetadata(task agent.Task) string {
+	if len(task.Metadata) == 0 {
+		return ""
+	}
+	data, err := json.MarshalIndent(task.Metadata, "", "  ")
+	if err != nil {
+		return fmt.Sprint(task.Metadata)
+	}
+	return string(data)
+}
+
+func indent(text, prefix string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	return prefix + strings.Join(lines, "\n"+prefix)
+}
+
+// fence wraps text in a code fence long enough not to be closed by the text
+func fence(lang, text string) string {
+	marker := "```"
+	for strings.Contains(text, marker) {
+		marker += "`"
+	}
+	return marker + lang + "\n" + strings.TrimRight(text, "\n") + "\n" + marker + "\n"
+}
+
+var transcriptTemplate = template.Must(template.New("transcript").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; max-width: 960px; margin: 2rem auto; padding: 0 1rem; color: #1e293b; }
+  dl { display: grid; grid-template-columns: max-content 1fr; gap: 0.25rem 1rem; font-size: 0.9rem; }
+  dt { font-weight: 600; }
+  .message { border: 1px solid #e2e8f0; border-radius: 8px; padding: 0.75rem 1rem; margin: 0.75rem 0; }
+  .message header { font-size: 0.8rem; font-weight: 600; color: #64748b; margin-bottom: 0.5rem; }
+  .user { background: #eff6ff; border-color: #bfdbfe; }
+  .system { font-size: 0.85rem; color: #64748b; }
+  .tool { background: #fffbeb; border-color: #fde68a; }
+  .tool-error { background: #fef2f2; border-color: #fecaca; }
+  .content { white-space: pre-wrap; }
+  pre { background: #0f172a; color: #e2e8f0; padding: 0.75rem; border-radius: 6px; overflow-x: auto; font-size: 0.8rem; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<dl>{{range .Details}}<dt>{{index . 0}}</dt><dd>{{index . 1}}</dd>{{end}}</dl>
+{{if .Tasks}}<h2>Tasks</h2>
+<ul>{{range .Tasks}}<li>{{.Subject}} ({{.Status}}){{if .Metadata}}<pre>{{.Metadata}}</pre>{{end}}</li>{{end}}</ul>{{end}}
+<h2>Transcript</h2>
+{{range .Entries}}<div class="message {{.Class}}">
+  <header>{{.Speaker}} · {{.Time}}</header>
+  {{if .Content}}<div class="content">{{.Content}}</div>{{end}}
+  {{if .Code}}<pre>{{.Code}}</pre>{{end}}
+</div>
+{{end}}
+{{if .Diff}}<h2>Final diff</h2>
+<pre>{{.Diff}}</pre>{{end}}
+</body>
+</html>
+`))
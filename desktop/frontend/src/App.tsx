@@ -56,18 +56,24 @@ function App() {
     createTriageSession,
     executeTriageTicket,
     resumeSession,
+    resumeInterruptedSession,
+    forkSession,
+    exportSession,
+    importSessionBundle,
     getTriageResult,
     deleteSession,
     stopSession,
     selectSession,
     sendMessage,
     approveAction,
+    approveActionAlways,
     rejectAction,
     loadMessagesPaginated,
     toggleFirefighterMonitoring,
     isMonitoringActive,
     setSessionModel,
     setSessionReasoningEffort,
+    compactSession,
   } = useAgent();
 
   const {
@@ -100,11 +106,21 @@ function App() {
     sideBySideData,
     loadDiffs,
     loadWorktreeDiffs,
+    loadImportedDiffs,
     loadSideBySide,
     acceptFile,
     rejectFile,
     acceptAll,
     rejectAll,
+    updateComments,
+    approveHunk,
+    rejectHunk,
+    changeRequest,
+    requestChanges,
+    dismissChangeRequest,
+    stageHunk,
+    discardHunk,
+    commitApproved,
   } = useDiff();
 
   // Update available projects for search
@@ -113,11 +129,15 @@ function App() {
     setProjects(projectPaths);
   }, [projects, setProjects]);
 
-  // Load diffs when active project changes or when switching to diff tab
+  // Load diffs when active project changes or when switching to diff tab,
+  // and again once the agent has addressed requested changes
+  const changeRequestState = changeRequest?.state;
   useEffect(() => {
-    if (activeProject && activeTab === 'diff') {
-      // For boatman mode sessions, diff against base branch in the worktree
-      if (activeSession?.mode === 'boatmanmode' && activeSession.modeConfig?.worktreePath) {
+    if (activeProject && activeTab === 'diff' && changeRequestState !== 'pending') {
+      // Imported sessions show the diff they were exported with
+      if (activeSession?.readOnly) {
+        loadImportedDiffs(activeSession.id);
+      } else if (activeSession?.mode === 'boatmanmode' && activeSession.modeConfig?.worktreePath) {
         const worktreePath = activeSession.modeConfig.worktreePath as string;
         const baseBranch = (activeSession.modeConfig.baseBranch as string) || 'main';
         loadWorktreeDiffs(worktreePath, baseBranch);
@@ -125,7 +145,18 @@ function App() {
         loadDiffs(activeProject.path);
       }
     }
-  }, [activeProject, activeTab, activeSession, loadDiffs, loadWorktreeDiffs]);
+  }, [activeProject, activeTab, activeSession, loadDiffs, loadWorktreeDiffs, loadImportedDiffs, changeRequestState]);
+
+  // Where the loaded diff's hunks live: the project's working tree, or a
+  // boatman mode session's worktree, whose diff is its branch against the
+  // merge-base. Branch diffs can't be staged, but approved hunks are committed
+  // on top of the branch. Imported diffs come from another machine.
+  const worktreePath = activeSession?.mode === 'boatmanmode' ? (activeSession.modeConfig?.worktreePath as string | undefined) : undefined;
+  const stagingTarget: { path: string; baseBranch?: string } | null = !activeProject || activeSession?.readOnly
+    ? null
+    : worktreePath
+      ? { path: worktreePath, baseBranch: (activeSession?.modeConfig?.baseBranch as string) || 'main' }
+      : { path: activeProject.path };
 
   // Dismiss error after 5 seconds
   useEffect(() => {
@@ -220,6 +251,20 @@ function App() {
     }
   };
 
+  // Handle resuming a session interrupted by the app quitting
+  const handleResumeInterrupted = async () => {
+    if (activeSession) {
+      await resumeInterruptedSession(activeSession.id);
+    }
+  };
+
+  // Handle forking the active session from a message
+  const handleForkSession = async (messageId: string, withWorktree: boolean) => {
+    if (activeSession) {
+      await forkSession(activeSession.id, messageId, withWorktree);
+    }
+  };
+
   // Handle project open
   const handleOpenProject = async () => {
     await selectAndOpenProject();
@@ -239,17 +284,27 @@ function App() {
     }
   };
 
+  // Oldest tool call waiting for approval in the active session
+  const pendingAction = activeSession?.pendingActions?.[0];
+
   // Handle approval
   const handleApprove = async () => {
     if (activeSession) {
-      await approveAction(activeSession.id, '');
+      await approveAction(activeSession.id, pendingAction?.id ?? '');
+    }
+  };
+
+  // Handle "always allow"
+  const handleAlwaysAllow = async () => {
+    if (activeSession && pendingAction) {
+      await approveActionAlways(activeSession.id, pendingAction.id);
     }
   };
 
   // Handle rejection
   const handleReject = async () => {
     if (activeSession) {
-      await rejectAction(activeSession.id, '');
+      await rejectAction(activeSession.id, pendingAction?.id ?? '');
     }
   };
 
@@ -325,6 +380,13 @@ function App() {
     }
   };
 
+  // Handle manual compaction from the context meter
+  const handleCompact = async () => {
+    if (activeSession) {
+      await compactSession(activeSession.id);
+    }
+  };
+
   // Handle toggle firefighter monitoring
   const handleToggleMonitoring = async (active: boolean) => {
     if (activeSession) {
@@ -345,7 +407,7 @@ function App() {
     );
   }
 
-  const isWaitingForApproval = activeSession?.status === 'waiting';
+  const isWaitingForApproval = pendingAction !== undefined;
   const hasActiveSession = activeSession !== null;
 
   // Get pagination info for active session
@@ -453,6 +515,8 @@ function App() {
           onToggleFavorite={handleToggleFavorite}
           onAddTag={handleAddTag}
           onRemoveTag={handleRemoveTag}
+          onExportSession={exportSession}
+          onImportBundle={importSessionBundle}
           isOpen={sidebarOpen}
         />
 
@@ -552,14 +616,19 @@ function App() {
                 status={activeSession.status}
                 onSendMessage={handleSendMessage}
                 onStop={handleStopSession}
+                onResumeInterrupted={handleResumeInterrupted}
+                onFork={!activeSession.readOnly ? handleForkSession : undefined}
                 hasMoreMessages={currentPagination?.hasMore ?? false}
                 onLoadMore={handleLoadMore}
-                monitoringActive={monitoringActive}
+                monitoringActive={!activeSession.readOnly ? monitoringActive : undefined}
                 onToggleMonitoring={handleToggleMonitoring}
                 model={activeSession.model}
                 reasoningEffort={activeSession.reasoningEffort}
                 onModelChange={handleModelChange}
                 onReasoningEffortChange={handleReasoningEffortChange}
+                contextUsage={!activeSession.readOnly ? activeSession.contextUsage : undefined}
+                onCompact={handleCompact}
+                readOnly={activeSession.readOnly}
               />
             )}
             {hasActiveSession && activeTab === 'chat' && activeSession.mode !== 'firefighter' && activeSession.mode !== 'triage' && (
@@ -568,15 +637,22 @@ function App() {
                 status={activeSession.status}
                 onSendMessage={handleSendMessage}
                 onStop={handleStopSession}
-                onResume={handleResumeSession}
+                onResume={!activeSession.readOnly ? handleResumeSession : undefined}
+                onResumeInterrupted={handleResumeInterrupted}
+                // Boatman mode runs its own agents, so there is no conversation to fork
+                onFork={activeSession.mode !== 'boatmanmode' && !activeSession.readOnly ? handleForkSession : undefined}
                 hasMoreMessages={currentPagination?.hasMore ?? false}
                 onLoadMore={handleLoadMore}
                 model={activeSession.model}
                 reasoningEffort={activeSession.reasoningEffort}
                 onModelChange={handleModelChange}
                 onReasoningEffortChange={handleReasoningEffortChange}
+                // Boatman mode runs its own agents, so there is no conversation to compact
+                contextUsage={activeSession.mode !== 'boatmanmode' && !activeSession.readOnly ? activeSession.contextUsage : undefined}
+                onCompact={handleCompact}
                 projectPath={activeSession.projectPath}
                 mode={activeSession.mode}
+                readOnly={activeSession.readOnly}
               />
             )}
             {hasActiveSession && activeTab === 'tasks' && (
@@ -595,6 +671,15 @@ function App() {
                 onReject={rejectFile}
                 onAcceptAll={acceptAll}
                 onRejectAll={rejectAll}
+                onUpdateComments={updateComments}
+                onApproveHunk={approveHunk}
+                onRejectHunk={rejectHunk}
+                onRequestChanges={!activeSession.readOnly ? () => requestChanges(activeSession.id) : undefined}
+                changeRequest={changeRequest?.sessionId === activeSession?.id ? changeRequest : null}
+                onDismissChangeRequest={dismissChangeRequest}
+                onStageHunk={stagingTarget && !stagingTarget.baseBranch ? (filePath, hunkId) => stageHunk(stagingTarget.path, filePath, hunkId) : undefined}
+                onDiscardHunk={stagingTarget ? (filePath, hunkId) => discardHunk(stagingTarget.path, filePath, hunkId, stagingTarget.baseBranch) : undefined}
+                onCommitApproved={stagingTarget ? (message) => commitApproved(stagingTarget.path, message, stagingTarget.baseBranch) : undefined}
               />
             )}
             {activeTab === 'harness' && <HarnessView />}
@@ -608,6 +693,9 @@ function App() {
         visible={isWaitingForApproval}
         onApprove={handleApprove}
         onReject={handleReject}
+        onAlwaysAllow={handleAlwaysAllow}
+        action={pendingAction}
+        queued={(activeSession?.pendingActions?.length ?? 1) - 1}
       />
     </div>
   );
@@ -1,14 +1,26 @@
-import { Check, X, AlertTriangle, FileEdit, Terminal } from 'lucide-react';
+import { Check, CheckCheck, X, AlertTriangle, FileEdit, Terminal } from 'lucide-react';
+import type { PendingAction } from '../../types';
 
 interface ApprovalBarProps {
   visible: boolean;
   onApprove: () => void;
   onReject: () => void;
+  onAlwaysAllow?: () => void;
+  action?: PendingAction;
+  queued?: number;
   actionType?: 'edit' | 'bash' | 'other';
   actionDescription?: string;
   filePath?: string;
 }
 
+const EDIT_TOOLS = ['Edit', 'MultiEdit', 'Write', 'NotebookEdit'];
+
+function getToolActionType(toolName: string): ApprovalBarProps['actionType'] {
+  if (toolName === 'Bash') return 'bash';
+  if (EDIT_TOOLS.includes(toolName)) return 'edit';
+  return 'other';
+}
+
 function getActionIcon(type: ApprovalBarProps['actionType']) {
   switch (type) {
     case 'edit':
@@ -35,12 +47,20 @@ export function ApprovalBar({
   visible,
   onApprove,
   onReject,
-  actionType = 'other',
+  onAlwaysAllow,
+  action,
+  queued = 0,
+  actionType,
   actionDescription,
   filePath,
 }: ApprovalBarProps) {
   if (!visible) return null;
 
+  const type = actionType ?? (action ? getToolActionType(action.toolName) : 'other');
+  const label = action && type === 'other' ? action.toolName : getActionLabel(type);
+  const target = filePath ?? (type === 'edit' ? action?.summary : undefined);
+  const description = actionDescription ?? (type !== 'edit' ? action?.summary : undefined);
+
   return (
     <div className="fixed bottom-0 left-0 right-0 z-50 animate-in slide-in-from-bottom">
       <div className="bg-slate-800 border-t border-slate-600 shadow-lg">
@@ -49,21 +69,32 @@ export function ApprovalBar({
             {/* Action info */}
             <div className="flex items-center gap-3">
               <div className="flex items-center justify-center w-10 h-10 rounded-lg bg-yellow-500/20 text-yellow-500">
-                {getActionIcon(actionType)}
+                {getActionIcon(type)}
               </div>
               <div>
                 <div className="flex items-center gap-2">
                   <span className="text-sm font-medium text-slate-100">
-                    {getActionLabel(actionType)} requires approval
+                    {label} requires approval
                   </span>
-                  {filePath && (
+                  {target && (
                     <code className="text-xs px-1.5 py-0.5 bg-slate-700 rounded text-slate-300">
-                      {filePath}
+                      {target}
                     </code>
                   )}
+                  {queued > 0 && (
+                    <span className="text-xs text-slate-500">+{queued} more</span>
+                  )}
                 </div>
-                {actionDescription && (
-                  <p className="text-xs text-slate-400 mt-0.5">{actionDescription}</p>
+                {description && (
+                  <p className="text-xs text-slate-400 mt-0.5 font-mono break-all">{description}</p>
+                )}
+                {action && (
+                  <details className="mt-1">
+                    <summary className="text-xs text-slate-500 cursor-pointer">Show input</summary>
+                    <pre className="mt-1 max-h-40 overflow-auto text-xs p-2 bg-slate-900 rounded text-slate-300">
+                      {JSON.stringify(action.input, null, 2)}
+                    </pre>
+                  </details>
                 )}
               </div>
             </div>
@@ -77,6 +108,16 @@ export function ApprovalBar({
                 <X className="w-4 h-4" />
                 Reject
               </button>
+              {onAlwaysAllow && action && (
+                <button
+                  onClick={onAlwaysAllow}
+                  title={`Always allow ${action.rule} in this project`}
+                  className="flex items-center gap-2 px-4 py-2 text-sm text-green-500 hover:bg-green-500/10 rounded-lg transition-colors"
+                >
+                  <CheckCheck className="w-4 h-4" />
+                  Always allow
+                </button>
+              )}
               <button
                 onClick={onApprove}
                 className="flex items-center gap-2 px-4 py-2 text-sm bg-green-500 text-white rounded-lg hover:bg-green-600 transition-colors"
@@ -4,7 +4,7 @@ import { InputArea } from './InputArea';
 import { AgentLogsPanel } from './AgentLogsPanel';
 import { ChatHeader } from './ChatHeader';
 import { Loader2, StopCircle, ArrowDown, RotateCcw } from 'lucide-react';
-import type { Message, SessionStatus } from '../../types';
+import type { Message, SessionStatus, ContextUsage } from '../../types';
 
 interface ChatViewProps {
   messages: Message[];
@@ -12,6 +12,8 @@ interface ChatViewProps {
   onSendMessage: (content: string) => void;
   onStop?: () => void;
   onResume?: () => void;
+  onResumeInterrupted?: () => void;
+  onFork?: (messageId: string, withWorktree: boolean) => void;
   isLoading?: boolean;
   hasMoreMessages?: boolean;
   onLoadMore?: () => void;
@@ -20,8 +22,11 @@ interface ChatViewProps {
   reasoningEffort?: string;
   onModelChange?: (model: string) => void;
   onReasoningEffortChange?: (effort: string) => void;
+  contextUsage?: ContextUsage;
+  onCompact?: () => void;
   projectPath?: string;
   mode?: string;
+  readOnly?: boolean;
 }
 
 export function ChatView({
@@ -30,6 +35,8 @@ export function ChatView({
   onSendMessage,
   onStop,
   onResume,
+  onResumeInterrupted,
+  onFork,
   isLoading = false,
   hasMoreMessages = false,
   onLoadMore,
@@ -38,8 +45,11 @@ export function ChatView({
   reasoningEffort,
   onModelChange,
   onReasoningEffortChange,
+  contextUsage,
+  onCompact,
   projectPath,
   mode,
+  readOnly = false,
 }: ChatViewProps) {
   const messagesEndRef = useRef<HTMLDivElement>(null);
   const scrollContainerRef = useRef<HTMLDivElement>(null);
@@ -82,13 +92,15 @@ export function ChatView({
         return 'An error occurred';
       case 'stopped':
         return 'Session stopped';
+      case 'interrupted':
+        return onResumeInterrupted ? null : 'Session interrupted';
       default:
         return null;
     }
   };
 
   const statusMessage = getStatusMessage();
-  const isInputDisabled = status === 'running' || isLoading;
+  const isInputDisabled = status === 'running' || isLoading || readOnly;
 
   return (
     <div className="flex flex-col h-full">
@@ -147,7 +159,12 @@ export function ChatView({
             )}
 
             {messages.map((message) => (
-              <MessageBubble key={message.id} message={message} />
+              <MessageBubble
+                key={message.id}
+                message={message}
+                // Forking from a running session would copy a half-finished turn
+                onFork={status !== 'running' && status !== 'waiting' ? onFork : undefined}
+              />
             ))}
             <div ref={messagesEndRef} />
           </div>
@@ -182,8 +199,26 @@ export function ChatView({
         </div>
       )}
 
+      {/* Resume banner for sessions cut off when the app quit */}
+      {onResumeInterrupted && status === 'interrupted' && (
+        <div className="flex items-center justify-center gap-3 py-3 px-4 bg-amber-900/30 border-y border-amber-700/30">
+          <span className="text-sm text-amber-300">
+            {mode === 'boatmanmode'
+              ? 'Boatman was interrupted when the app closed. Resume in the existing worktree?'
+              : 'Claude was interrupted when the app closed. Resume where it left off?'}
+          </span>
+          <button
+            onClick={onResumeInterrupted}
+            className="flex items-center gap-1.5 px-3 py-1.5 text-sm font-medium text-amber-100 bg-amber-600 hover:bg-amber-500 rounded-md transition-colors"
+          >
+            <RotateCcw className="w-3.5 h-3.5" />
+            Resume
+          </button>
+        </div>
+      )}
+
       {/* Resume banner for non-running boatmanmode sessions */}
-      {onResume && status !== 'running' && mode === 'boatmanmode' && messages.length > 0 && (
+      {onResume && status !== 'running' && status !== 'interrupted' && mode === 'boatmanmode' && messages.length > 0 && (
         <div className="flex items-center justify-center gap-3 py-3 px-4 bg-amber-900/30 border-y border-amber-700/30">
           <span className="text-sm text-amber-300">Execution stopped. You can resume from the review/refactor stage.</span>
           <button
@@ -206,7 +241,9 @@ export function ChatView({
         status={status}
         disabled={isInputDisabled}
         placeholder={
-          status === 'waiting'
+          readOnly
+            ? 'Imported session (read-only)'
+            : status === 'waiting'
             ? 'Waiting for approval...'
             : status === 'running'
             ? 'Claude is thinking...'
@@ -216,6 +253,8 @@ export function ChatView({
         reasoningEffort={reasoningEffort}
         onModelChange={onModelChange}
         onReasoningEffortChange={onReasoningEffortChange}
+        contextUsage={contextUsage}
+        onCompact={onCompact}
       />
     </div>
   );
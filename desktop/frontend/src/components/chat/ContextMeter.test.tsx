@@ -0,0 +1,48 @@
+import { describe, it, expect, vi } from 'vitest';
+import { render, screen, fireEvent } from '@testing-library/react';
+import { ContextMeter } from './ContextMeter';
+import type { ContextUsage } from '../../types';
+
+describe('ContextMeter', () => {
+  const usage = (overrides: Partial<ContextUsage> = {}): ContextUsage => ({
+    sessionId: 'session-1',
+    tokens: 50000,
+    window: 200000,
+    percent: 25,
+    threshold: 0.8,
+    compactions: 0,
+    compacting: false,
+    ...overrides,
+  });
+
+  it('shows tokens used out of the window', () => {
+    render(<ContextMeter usage={usage()} />);
+
+    expect(screen.getByText('50.0k / 200.0k')).toBeInTheDocument();
+    expect(screen.getByTestId('context-threshold')).toBeInTheDocument();
+  });
+
+  it('offers to compact only near the threshold', () => {
+    const onCompact = vi.fn();
+    const { rerender } = render(<ContextMeter usage={usage()} onCompact={onCompact} />);
+    expect(screen.queryByText('Compact')).not.toBeInTheDocument();
+
+    rerender(<ContextMeter usage={usage({ tokens: 150000, percent: 75 })} onCompact={onCompact} />);
+    fireEvent.click(screen.getByText('Compact'));
+    expect(onCompact).toHaveBeenCalledTimes(1);
+  });
+
+  it('shows progress while compacting', () => {
+    render(<ContextMeter usage={usage({ percent: 85, compacting: true })} onCompact={vi.fn()} />);
+
+    expect(screen.getByText('Compacting...')).toBeInTheDocument();
+    expect(screen.queryByText('Compact')).not.toBeInTheDocument();
+  });
+
+  it('hides the threshold marker when automatic compaction is off', () => {
+    render(<ContextMeter usage={usage({ threshold: 0 })} />);
+
+    expect(screen.queryByTestId('context-threshold')).not.toBeInTheDocument();
+    expect(screen.getByTestId('context-meter').getAttribute('title')).toContain('Automatic compaction is off');
+  });
+});
@@ -0,0 +1,75 @@
+import { Gauge, Loader2 } from 'lucide-react';
+import type { ContextUsage } from '../../types';
+
+interface ContextMeterProps {
+  usage: ContextUsage;
+  onCompact?: () => void;
+  disabled?: boolean;
+}
+
+// Formats a token count compactly, e.g. 12400 -> "12.4k"
+function formatTokens(tokens: number): string {
+  if (tokens >= 1_000_000) return `${(tokens / 1_000_000).toFixed(1)}M`;
+  if (tokens >= 1_000) return `${(tokens / 1_000).toFixed(1)}k`;
+  return `${tokens}`;
+}
+
+export function ContextMeter({ usage, onCompact, disabled = false }: ContextMeterProps) {
+  const percent = Math.min(100, Math.max(0, usage.percent));
+  const thresholdPercent = usage.threshold > 0 ? usage.threshold * 100 : null;
+  const nearLimit = thresholdPercent !== null ? percent >= thresholdPercent - 10 : percent >= 70;
+
+  const barColor =
+    thresholdPercent !== null && percent >= thresholdPercent
+      ? 'bg-red-500'
+      : nearLimit
+      ? 'bg-amber-500'
+      : 'bg-blue-500';
+
+  const title = [
+    `${usage.tokens.toLocaleString()} of ${usage.window.toLocaleString()} context tokens`,
+    thresholdPercent !== null
+      ? `Compacts automatically at ${thresholdPercent.toFixed(0)}%`
+      : 'Automatic compaction is off',
+    usage.compactions > 0 ? `Compacted ${usage.compactions} time${usage.compactions === 1 ? '' : 's'}` : null,
+  ]
+    .filter(Boolean)
+    .join('\n');
+
+  return (
+    <div className="flex items-center gap-2 text-xs text-slate-400" title={title} data-testid="context-meter">
+      <Gauge className="w-3 h-3" />
+      <div className="relative w-24 h-1.5 bg-slate-700 rounded-full overflow-hidden">
+        <div className={`h-full ${barColor} transition-all`} style={{ width: `${percent}%` }} />
+        {thresholdPercent !== null && (
+          <div
+            className="absolute top-0 h-full w-px bg-slate-300"
+            style={{ left: `${thresholdPercent}%` }}
+            data-testid="context-threshold"
+          />
+        )}
+      </div>
+      <span className="font-mono">
+        {formatTokens(usage.tokens)} / {formatTokens(usage.window)}
+      </span>
+      {usage.compacting ? (
+        <span className="flex items-center gap-1 text-amber-400">
+          <Loader2 className="w-3 h-3 animate-spin" />
+          Compacting...
+        </span>
+      ) : (
+        onCompact &&
+        nearLimit && (
+          <button
+            type="button"
+            onClick={onCompact}
+            disabled={disabled}
+            className="px-2 py-0.5 rounded-full border border-slate-600 text-slate-300 hover:border-slate-500 hover:text-slate-100 transition-colors disabled:opacity-50 disabled:cursor-not-allowed"
+          >
+            Compact
+          </button>
+        )
+      )}
+    </div>
+  );
+}
@@ -1,8 +1,9 @@
 import { useState, useRef, useEffect, KeyboardEvent } from 'react';
 import { Send, Square, Paperclip, Loader2, Bot, Zap } from 'lucide-react';
 import { PillDropdown } from './PillDropdown';
+import { ContextMeter } from './ContextMeter';
 import { MODEL_OPTIONS, REASONING_EFFORT_OPTIONS } from '../../types';
-import type { SessionStatus } from '../../types';
+import type { SessionStatus, ContextUsage } from '../../types';
 
 interface InputAreaProps {
   onSend: (message: string) => void;
@@ -14,6 +15,8 @@ interface InputAreaProps {
   reasoningEffort?: string;
   onModelChange?: (model: string) => void;
   onReasoningEffortChange?: (effort: string) => void;
+  contextUsage?: ContextUsage;
+  onCompact?: () => void;
 }
 
 export function InputArea({
@@ -26,6 +29,8 @@ export function InputArea({
   reasoningEffort,
   onModelChange,
   onReasoningEffortChange,
+  contextUsage,
+  onCompact,
 }: InputAreaProps) {
   const [message, setMessage] = useState('');
   const textareaRef = useRef<HTMLTextAreaElement>(null);
@@ -118,6 +123,9 @@ export function InputArea({
               icon={Zap}
             />
           )}
+          {contextUsage && (
+            <ContextMeter usage={contextUsage} onCompact={onCompact} disabled={disabled || status === 'running'} />
+          )}
           <p className="ml-auto text-xs text-slate-500">
             <kbd className="px-1.5 py-0.5 bg-slate-700 rounded text-slate-400">Enter</kbd> send{' '}
             <kbd className="px-1.5 py-0.5 bg-slate-700 rounded text-slate-400">Shift+Enter</kbd> new line
@@ -1,12 +1,13 @@
 import { memo, useState } from 'react';
 import ReactMarkdown from 'react-markdown';
 import remarkGfm from 'remark-gfm';
-import { User, Bot, Wrench, AlertCircle, ChevronDown, ChevronRight } from 'lucide-react';
+import { User, Bot, Wrench, AlertCircle, ChevronDown, ChevronRight, GitBranch, FolderGit2 } from 'lucide-react';
 import { CodeBlock } from './CodeBlock';
 import type { Message } from '../../types';
 
 interface MessageBubbleProps {
   message: Message;
+  onFork?: (messageId: string, withWorktree: boolean) => void;
 }
 
 function getLanguageFromClassName(className?: string): string {
@@ -15,13 +16,15 @@ function getLanguageFromClassName(className?: string): string {
   return match ? match[1] : 'text';
 }
 
-export const MessageBubble = memo(function MessageBubble({ message }: MessageBubbleProps) {
+export const MessageBubble = memo(function MessageBubble({ message, onFork }: MessageBubbleProps) {
   const [showTokenDetails, setShowTokenDetails] = useState(false);
+  const [showSummary, setShowSummary] = useState(false);
   const isUser = message.role === 'user';
   const isSystem = message.role === 'system';
   const isToolUse = message.metadata?.toolUse;
   const isToolResult = message.metadata?.toolResult;
   const hasCostInfo = message.metadata?.costInfo;
+  const compaction = message.metadata?.compaction;
 
   // Debug logging
   console.log('[MessageBubble] Rendering message:', {
@@ -62,7 +65,7 @@ export const MessageBubble = memo(function MessageBubble({ message }: MessageBub
   };
 
   return (
-    <div id={`message-${message.id}`} className={`flex gap-3 px-4 py-3 ${isUser ? 'flex-row-reverse' : ''}`}>
+    <div id={`message-${message.id}`} className={`group flex gap-3 px-4 py-3 ${isUser ? 'flex-row-reverse' : ''}`}>
       <div
         className={`flex-shrink-0 w-8 h-8 rounded-lg flex items-center justify-center ${
           isUser ? 'bg-blue-500/20' : 'bg-slate-700'
@@ -86,6 +89,26 @@ export const MessageBubble = memo(function MessageBubble({ message }: MessageBub
           <span className="text-xs text-slate-500">
             {new Date(message.timestamp).toLocaleTimeString()}
           </span>
+          {onFork && !isSystem && !isToolUse && !isToolResult && (
+            <span className="flex items-center gap-1 opacity-0 group-hover:opacity-100 transition-opacity">
+              <button
+                onClick={() => onFork(message.id, false)}
+                className="p-0.5 text-slate-500 hover:text-slate-300"
+                title="Fork from here"
+                aria-label="Fork from here"
+              >
+                <GitBranch className="w-3 h-3" />
+              </button>
+              <button
+                onClick={() => onFork(message.id, true)}
+                className="p-0.5 text-slate-500 hover:text-slate-300"
+                title="Fork from here in a new worktree"
+                aria-label="Fork from here in a new worktree"
+              >
+                <FolderGit2 className="w-3 h-3" />
+              </button>
+            </span>
+          )}
         </div>
         <div
           className={`inline-block text-left rounded-lg border px-4 py-3 ${getBubbleStyles()}`}
@@ -199,6 +222,29 @@ export const MessageBubble = memo(function MessageBubble({ message }: MessageBub
               )}
             </div>
           )}
+          {compaction && (
+            <div className="mt-2 pt-2 border-t border-slate-700/50">
+              <button
+                onClick={() => setShowSummary(!showSummary)}
+                className="flex items-center gap-2 text-xs text-slate-500 hover:text-slate-400 transition-colors w-full text-left"
+              >
+                {showSummary ? (
+                  <ChevronDown className="w-3 h-3" />
+                ) : (
+                  <ChevronRight className="w-3 h-3" />
+                )}
+                <span>
+                  Summary carried into the new conversation
+                  {compaction.method === 'compressed' ? ' (compressed without the model)' : ''}
+                </span>
+              </button>
+              {showSummary && (
+                <pre className="mt-2 pl-5 text-xs text-slate-400 whitespace-pre-wrap font-sans">
+                  {compaction.summary}
+                </pre>
+              )}
+            </div>
+          )}
         </div>
       </div>
     </div>
@@ -1,12 +1,14 @@
 import { useState, useMemo } from 'react';
-import { Check, X, Columns, Rows, File } from 'lucide-react';
+import { Check, X, Columns, Rows, File, MessageSquare, Plus, Undo2, GitCommit } from 'lucide-react';
 import { FileTree } from './FileTree';
 import { DiffLine, SideBySideLine } from './DiffLine';
 import { DiffCommentThread } from './DiffCommentThread';
 import { BatchApprovalBar } from './BatchApprovalBar';
 import { DiffSummaryCard } from './DiffSummaryCard';
+import { RequestedChangesPanel } from './RequestedChangesPanel';
 import { calculateDiffSummary, generateCommentId } from '../../utils/diffUtils';
 import { diff } from '../../../wailsjs/go/models';
+import type { DiffChangeRequest } from '../../hooks/useDiff';
 
 interface DiffViewProps {
   diffs: diff.FileDiff[];
@@ -18,6 +20,12 @@ interface DiffViewProps {
   onUpdateComments?: (filePath: string, comments: diff.DiffComment[]) => void;
   onApproveHunk?: (filePath: string, hunkId: string) => void;
   onRejectHunk?: (filePath: string, hunkId: string) => void;
+  onRequestChanges?: () => void;
+  changeRequest?: DiffChangeRequest | null;
+  onDismissChangeRequest?: () => void;
+  onStageHunk?: (filePath: string, hunkId: string) => void;
+  onDiscardHunk?: (filePath: string, hunkId: string) => void;
+  onCommitApproved?: (message: string) => void;
 }
 
 type ViewMode = 'unified' | 'split';
@@ -32,6 +40,12 @@ export function DiffView({
   onUpdateComments,
   onApproveHunk,
   onRejectHunk,
+  onRequestChanges,
+  changeRequest,
+  onDismissChangeRequest,
+  onStageHunk,
+  onDiscardHunk,
+  onCommitApproved,
 }: DiffViewProps) {
   const [selectedFile, setSelectedFile] = useState<string | null>(
     diffs.length > 0 ? diffs[0].newPath || diffs[0].oldPath : null
@@ -39,6 +53,7 @@ export function DiffView({
   const [viewMode, setViewMode] = useState<ViewMode>('unified');
   const [selectedFiles, setSelectedFiles] = useState<Set<string>>(new Set());
   const [showComments, setShowComments] = useState<Record<string, boolean>>({});
+  const [commitMessage, setCommitMessage] = useState<string | null>(null);
 
   const selectedDiff = diffs.find(
     (d) => (d.newPath || d.oldPath) === selectedFile
@@ -100,6 +115,21 @@ export function DiffView({
     onUpdateComments(selectedFile, updatedComments);
   };
 
+  const hasApproved = diffs.some((d) => d.approved || d.hunks.some((h) => h.approved));
+
+  const handleCommitApproved = () => {
+    if (!onCommitApproved || !commitMessage?.trim()) return;
+    onCommitApproved(commitMessage.trim());
+    setCommitMessage(null);
+  };
+
+  const handleDiscardHunk = (filePath: string, hunkId: string) => {
+    if (!confirm('Discard this change from the working tree? This cannot be undone.')) {
+      return;
+    }
+    onDiscardHunk?.(filePath, hunkId);
+  };
+
   const toggleCommentThread = (lineKey: string) => {
     setShowComments((prev) => ({
       ...prev,
@@ -136,6 +166,49 @@ export function DiffView({
           </button>
         </div>
         <div className="flex items-center gap-2">
+          {onCommitApproved && commitMessage !== null && (
+            <>
+              <input
+                autoFocus
+                value={commitMessage}
+                onChange={(e) => setCommitMessage(e.target.value)}
+                onKeyDown={(e) => {
+                  if (e.key === 'Enter') handleCommitApproved();
+                  if (e.key === 'Escape') setCommitMessage(null);
+                }}
+                placeholder="Commit message"
+                className="w-64 px-2 py-1 text-sm bg-slate-800 border border-slate-700 rounded text-slate-100 placeholder-slate-500 focus:outline-none focus:border-blue-500"
+              />
+              <button
+                onClick={handleCommitApproved}
+                disabled={!commitMessage.trim()}
+                className="px-3 py-1.5 text-sm bg-blue-500 text-white rounded hover:bg-blue-600 transition-colors disabled:opacity-50 disabled:cursor-not-allowed"
+              >
+                Commit
+              </button>
+            </>
+          )}
+          {onCommitApproved && commitMessage === null && (
+            <button
+              onClick={() => setCommitMessage('')}
+              disabled={!hasApproved}
+              title={hasApproved ? undefined : 'Approve files or hunks to commit them'}
+              className="flex items-center gap-1.5 px-3 py-1.5 text-sm text-blue-400 hover:bg-blue-500/10 rounded transition-colors disabled:opacity-50 disabled:cursor-not-allowed"
+            >
+              <GitCommit className="w-4 h-4" />
+              Commit Approved
+            </button>
+          )}
+          {onRequestChanges && (
+            <button
+              onClick={onRequestChanges}
+              disabled={changeRequest?.state === 'pending'}
+              className="flex items-center gap-1.5 px-3 py-1.5 text-sm text-amber-500 hover:bg-amber-500/10 rounded transition-colors disabled:opacity-50 disabled:cursor-not-allowed"
+            >
+              <MessageSquare className="w-4 h-4" />
+              Request Changes
+            </button>
+          )}
           {onRejectAll && (
             <button
               onClick={onRejectAll}
@@ -175,6 +248,9 @@ export function DiffView({
 
         {/* Diff content */}
         <div className="flex-1 flex flex-col overflow-hidden">
+          {changeRequest && (
+            <RequestedChangesPanel request={changeRequest} onDismiss={onDismissChangeRequest} />
+          )}
           {selectedDiff ? (
             <>
               {/* File header */}
@@ -240,18 +316,81 @@ export function DiffView({
                   <div className="min-w-max">
                     {selectedDiff.hunks.map((hunk, hunkIndex) => (
                       <div key={hunkIndex}>
-                        <div className="px-4 py-1 bg-slate-800 text-xs text-slate-400 font-mono">
-                          @@ -{hunk.oldStart},{hunk.oldLines} +{hunk.newStart},{hunk.newLines} @@
+                        <div className="flex items-center justify-between px-4 py-1 bg-slate-800 text-xs text-slate-400 font-mono">
+                          <span>
+                            @@ -{hunk.oldStart},{hunk.oldLines} +{hunk.newStart},{hunk.newLines} @@
+                          </span>
+                          {hunk.id && selectedFile && (onApproveHunk || onRejectHunk || onStageHunk || onDiscardHunk) && (
+                            <div className="flex items-center gap-1 font-sans">
+                              {onDiscardHunk && (
+                                <button
+                                  onClick={() => handleDiscardHunk(selectedFile, hunk.id!)}
+                                  className="flex items-center gap-1 px-2 py-0.5 text-slate-400 hover:text-red-500 hover:bg-red-500/10 rounded transition-colors"
+                                >
+                                  <Undo2 className="w-3 h-3" />
+                                  Discard
+                                </button>
+                              )}
+                              {onStageHunk && (
+                                <button
+                                  onClick={() => onStageHunk(selectedFile, hunk.id!)}
+                                  className="flex items-center gap-1 px-2 py-0.5 text-slate-400 hover:text-blue-400 hover:bg-blue-500/10 rounded transition-colors"
+                                >
+                                  <Plus className="w-3 h-3" />
+                                  Stage
+                                </button>
+                              )}
+                              {hunk.approved ? (
+                                <button
+                                  onClick={() => onRejectHunk?.(selectedFile, hunk.id!)}
+                                  className="flex items-center gap-1 px-2 py-0.5 text-green-500 hover:bg-green-500/10 rounded transition-colors"
+                                >
+                                  <Check className="w-3 h-3" />
+                                  Approved
+                                </button>
+                              ) : (
+                                <button
+                                  onClick={() => onApproveHunk?.(selectedFile, hunk.id!)}
+                                  className="flex items-center gap-1 px-2 py-0.5 text-slate-400 hover:text-green-500 hover:bg-green-500/10 rounded transition-colors"
+                                >
+                                  <Check className="w-3 h-3" />
+                                  Approve hunk
+                                </button>
+                              )}
+                            </div>
+                          )}
                         </div>
-                        {hunk.lines.map((line, lineIndex) => (
-                          <DiffLine
-                            key={`${hunkIndex}-${lineIndex}`}
-                            type={line.type as any}
-                            content={line.content}
-                            oldNum={line.oldNum}
-                            newNum={line.newNum}
-                          />
-                        ))}
+                        {hunk.lines.map((line, lineIndex) => {
+                          const lineNum = line.newNum || line.oldNum || 0;
+                          const lineKey = `${hunk.id}:${lineNum}`;
+                          const hasComments = (selectedDiff.comments || []).some(
+                            (c) => c.lineNum === lineNum && c.hunkId === hunk.id
+                          );
+                          return (
+                            <div key={`${hunkIndex}-${lineIndex}`}>
+                              <div
+                                onClick={onUpdateComments ? () => toggleCommentThread(lineKey) : undefined}
+                                className={onUpdateComments ? 'cursor-pointer' : undefined}
+                              >
+                                <DiffLine
+                                  type={line.type as any}
+                                  content={line.content}
+                                  oldNum={line.oldNum}
+                                  newNum={line.newNum}
+                                />
+                              </div>
+                              {onUpdateComments && (showComments[lineKey] || hasComments) && (
+                                <DiffCommentThread
+                                  comments={selectedDiff.comments || []}
+                                  lineNum={lineNum}
+                                  hunkId={hunk.id}
+                                  onAddComment={(content) => handleAddComment(lineNum, hunk.id, content)}
+                                  onDeleteComment={handleDeleteComment}
+                                />
+                              )}
+                            </div>
+                          );
+                        })}
                       </div>
                     ))}
                   </div>
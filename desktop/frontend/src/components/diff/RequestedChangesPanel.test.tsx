@@ -0,0 +1,82 @@
+import { describe, it, expect, vi } from 'vitest';
+import { render, screen, fireEvent } from '@testing-library/react';
+import { RequestedChangesPanel } from './RequestedChangesPanel';
+import type { DiffChangeRequest } from '../../hooks/useDiff';
+import { diff, review } from '../../../wailsjs/go/models';
+
+const changes = [
+  new diff.RequestedChange({
+    id: 'c1',
+    commentId: 'c1',
+    issue: new review.Issue({
+      severity: 'major',
+      file: 'app.go',
+      line: 22,
+      description: 'Remove the debug print',
+    }),
+  }),
+  new diff.RequestedChange({
+    id: 'hunk-1',
+    hunkId: 'hunk-1',
+    issue: new review.Issue({
+      severity: 'minor',
+      file: 'old.go',
+      line: 1,
+      description: 'The change to lines 1-1 was not approved',
+    }),
+  }),
+];
+
+function makeRequest(overrides: Partial<DiffChangeRequest> = {}): DiffChangeRequest {
+  return {
+    sessionId: 'session-1',
+    changes,
+    statuses: {},
+    state: 'pending',
+    ...overrides,
+  };
+}
+
+describe('RequestedChangesPanel', () => {
+  it('should list requested changes while waiting for the agent', () => {
+    render(<RequestedChangesPanel request={makeRequest()} onDismiss={vi.fn()} />);
+
+    expect(screen.getByText(/Waiting for the agent to address 2 requested changes/)).toBeInTheDocument();
+    expect(screen.getByText('Remove the debug print')).toBeInTheDocument();
+    expect(screen.getByText('app.go:22')).toBeInTheDocument();
+    expect(screen.queryByLabelText('Dismiss requested changes')).not.toBeInTheDocument();
+  });
+
+  it('should show which changes were addressed', () => {
+    render(
+      <RequestedChangesPanel
+        request={makeRequest({
+          state: 'verified',
+          statuses: {
+            c1: { id: 'c1', addressed: true, evidence: 'Found related changes' },
+            'hunk-1': { id: 'hunk-1', addressed: false, reason: 'File old.go was not modified' },
+          },
+        })}
+      />
+    );
+
+    expect(screen.getByText('1 of 2 requested changes addressed')).toBeInTheDocument();
+    expect(screen.getByLabelText('Addressed')).toBeInTheDocument();
+    expect(screen.getByLabelText('Not addressed')).toBeInTheDocument();
+    expect(screen.getByText('File old.go was not modified')).toBeInTheDocument();
+  });
+
+  it('should show errors and allow dismissing once verified', () => {
+    const onDismiss = vi.fn();
+    render(
+      <RequestedChangesPanel
+        request={makeRequest({ state: 'error', error: 'failed to verify changes' })}
+        onDismiss={onDismiss}
+      />
+    );
+
+    expect(screen.getByText('failed to verify changes')).toBeInTheDocument();
+    fireEvent.click(screen.getByLabelText('Dismiss requested changes'));
+    expect(onDismiss).toHaveBeenCalled();
+  });
+});
@@ -0,0 +1,78 @@
+import { CheckCircle2, XCircle, Loader2, X } from 'lucide-react';
+import type { DiffChangeRequest } from '../../hooks/useDiff';
+
+interface RequestedChangesPanelProps {
+  request: DiffChangeRequest;
+  onDismiss?: () => void;
+}
+
+export function RequestedChangesPanel({ request, onDismiss }: RequestedChangesPanelProps) {
+  const addressedCount = request.changes.filter((c) => request.statuses[c.id]?.addressed).length;
+
+  return (
+    <div className="border-b border-slate-700 bg-slate-900 px-4 py-3">
+      <div className="flex items-center justify-between mb-2">
+        <span className="text-sm font-medium text-slate-200">
+          {request.state === 'pending'
+            ? `Waiting for the agent to address ${request.changes.length} requested change${
+                request.changes.length === 1 ? '' : 's'
+              }...`
+            : `${addressedCount} of ${request.changes.length} requested changes addressed`}
+        </span>
+        {onDismiss && request.state !== 'pending' && (
+          <button
+            onClick={onDismiss}
+            aria-label="Dismiss requested changes"
+            className="text-slate-400 hover:text-slate-200 transition-colors"
+          >
+            <X className="w-4 h-4" />
+          </button>
+        )}
+      </div>
+
+      {request.error && (
+        <div className="mb-2 text-xs text-red-400">{request.error}</div>
+      )}
+
+      <ul className="space-y-1 max-h-48 overflow-auto">
+        {request.changes.map((change) => {
+          const status = request.statuses[change.id];
+          const issue = change.issue;
+          return (
+            <li key={change.id} className="flex items-start gap-2 text-sm">
+              {request.state === 'pending' || !status ? (
+                <Loader2
+                  className={`w-4 h-4 mt-0.5 flex-shrink-0 text-slate-400 ${
+                    request.state === 'pending' ? 'animate-spin' : ''
+                  }`}
+                />
+              ) : status.addressed ? (
+                <CheckCircle2
+                  className="w-4 h-4 mt-0.5 flex-shrink-0 text-green-500"
+                  aria-label="Addressed"
+                />
+              ) : (
+                <XCircle
+                  className="w-4 h-4 mt-0.5 flex-shrink-0 text-red-500"
+                  aria-label="Not addressed"
+                />
+              )}
+              <div className="flex-1 min-w-0">
+                <div className="text-slate-200">
+                  <span className="font-mono text-xs text-slate-400 mr-2">
+                    {issue.file}
+                    {issue.line ? `:${issue.line}` : ''}
+                  </span>
+                  {issue.description}
+                </div>
+                {status && (status.evidence || status.reason) && (
+                  <div className="text-xs text-slate-500">{status.evidence || status.reason}</div>
+                )}
+              </div>
+            </li>
+          );
+        })}
+      </ul>
+    </div>
+  );
+}
@@ -3,8 +3,8 @@ import { ChatView } from '../chat/ChatView';
 import { IncidentCanvas } from './IncidentCanvas';
 import { FirefighterMonitor } from './FirefighterMonitor';
 import { ChevronLeft, ChevronRight, X } from 'lucide-react';
-import { parseIncidentsFromMessages } from '../../utils/parseIncidents';
-import type { Message, SessionStatus } from '../../types';
+import { useIncidents } from '../../hooks/useIncidents';
+import type { Message, SessionStatus, ContextUsage } from '../../types';
 
 interface FirefighterViewProps {
   sessionId: string;
@@ -12,6 +12,8 @@ interface FirefighterViewProps {
   status: SessionStatus;
   onSendMessage: (content: string) => void;
   onStop?: () => void;
+  onResumeInterrupted?: () => void;
+  onFork?: (messageId: string, withWorktree: boolean) => void;
   isLoading?: boolean;
   hasMoreMessages?: boolean;
   onLoadMore?: () => void;
@@ -22,6 +24,9 @@ interface FirefighterViewProps {
   reasoningEffort?: string;
   onModelChange?: (model: string) => void;
   onReasoningEffortChange?: (effort: string) => void;
+  contextUsage?: ContextUsage;
+  onCompact?: () => void;
+  readOnly?: boolean;
 }
 
 export function FirefighterView({
@@ -30,6 +35,8 @@ export function FirefighterView({
   status,
   onSendMessage,
   onStop,
+  onResumeInterrupted,
+  onFork,
   isLoading,
   hasMoreMessages,
   onLoadMore,
@@ -40,11 +47,14 @@ export function FirefighterView({
   reasoningEffort,
   onModelChange,
   onReasoningEffortChange,
+  contextUsage,
+  onCompact,
+  readOnly,
 }: FirefighterViewProps) {
   const [showSidebar, setShowSidebar] = useState(true);
   const [selectedIncidentId, setSelectedIncidentId] = useState<string | null>(null);
 
-  const incidents = useMemo(() => parseIncidentsFromMessages(messages), [messages]);
+  const { incidents, exportPostmortem } = useIncidents(sessionId, messages);
 
   const selectedIncident = useMemo(
     () => selectedIncidentId ? incidents.find(i => i.id === selectedIncidentId) ?? null : null,
@@ -104,6 +114,7 @@ export function FirefighterView({
                 selectedId={selectedIncidentId}
                 onSelect={handleSelectIncident}
                 onInvestigate={handleInvestigate}
+                onExportPostmortem={exportPostmortem}
               />
             </div>
           </div>
@@ -145,6 +156,8 @@ export function FirefighterView({
             status={status}
             onSendMessage={onSendMessage}
             onStop={onStop}
+            onResumeInterrupted={onResumeInterrupted}
+            onFork={onFork}
             isLoading={isLoading}
             hasMoreMessages={selectedIncidentId ? false : hasMoreMessages}
             onLoadMore={onLoadMore}
@@ -153,6 +166,9 @@ export function FirefighterView({
             reasoningEffort={reasoningEffort}
             onModelChange={onModelChange}
             onReasoningEffortChange={onReasoningEffortChange}
+            contextUsage={contextUsage}
+            onCompact={onCompact}
+            readOnly={readOnly}
           />
         </div>
       </div>
@@ -8,9 +8,10 @@ interface IncidentCanvasProps {
   selectedId: string | null;
   onSelect: (id: string) => void;
   onInvestigate: (id: string) => void;
+  onExportPostmortem?: (id: string) => void;
 }
 
-export function IncidentCanvas({ incidents, selectedId, onSelect, onInvestigate }: IncidentCanvasProps) {
+export function IncidentCanvas({ incidents, selectedId, onSelect, onInvestigate, onExportPostmortem }: IncidentCanvasProps) {
   const [showResolved, setShowResolved] = useState(false);
 
   const active = incidents.filter(i => !['resolved', 'failed'].includes(i.status));
@@ -41,6 +42,7 @@ export function IncidentCanvas({ incidents, selectedId, onSelect, onInvestigate
           isSelected={selectedId === incident.id}
           onSelect={onSelect}
           onInvestigate={onInvestigate}
+          onExportPostmortem={onExportPostmortem}
         />
       ))}
 
@@ -63,6 +65,7 @@ export function IncidentCanvas({ incidents, selectedId, onSelect, onInvestigate
                   isSelected={selectedId === incident.id}
                   onSelect={onSelect}
                   onInvestigate={onInvestigate}
+                  onExportPostmortem={onExportPostmortem}
                 />
               ))}
             </div>
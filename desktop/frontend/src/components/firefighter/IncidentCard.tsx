@@ -1,4 +1,4 @@
-import { Flame, AlertCircle, Monitor, MessageSquare, PlayCircle, Clock } from 'lucide-react';
+import { Flame, AlertCircle, Monitor, MessageSquare, PlayCircle, Clock, FileText } from 'lucide-react';
 import type { Incident } from '../../types';
 
 interface IncidentCardProps {
@@ -6,6 +6,7 @@ interface IncidentCardProps {
   isSelected: boolean;
   onSelect: (id: string) => void;
   onInvestigate: (id: string) => void;
+  onExportPostmortem?: (id: string) => void;
 }
 
 const severityConfig: Record<Incident['severity'], { border: string; text: string; bg: string; label: string }> = {
@@ -43,10 +44,11 @@ function timeAgo(timestamp: string): string {
   return `${Math.floor(hours / 24)}d ago`;
 }
 
-export function IncidentCard({ incident, isSelected, onSelect, onInvestigate }: IncidentCardProps) {
-  const sev = severityConfig[incident.severity];
-  const stat = statusConfig[incident.status];
+export function IncidentCard({ incident, isSelected, onSelect, onInvestigate, onExportPostmortem }: IncidentCardProps) {
+  const sev = severityConfig[incident.severity] ?? severityConfig.medium;
+  const stat = statusConfig[incident.status] ?? statusConfig.new;
   const canInvestigate = incident.status === 'new';
+  const recentTimeline = (incident.timeline ?? []).slice(-3);
 
   return (
     <div
@@ -79,8 +81,23 @@ export function IncidentCard({ incident, isSelected, onSelect, onInvestigate }:
       <div className="flex items-center gap-2 text-xs text-slate-500">
         {incident.linearId && <span>{incident.linearId}</span>}
         <span>{incident.messageIds.length} message{incident.messageIds.length !== 1 ? 's' : ''}</span>
+        {incident.alertCount !== undefined && (
+          <span>{incident.alertCount} alert{incident.alertCount !== 1 ? 's' : ''}</span>
+        )}
+        {incident.prNumber && <span>PR #{incident.prNumber}</span>}
       </div>
 
+      {/* Latest timeline entries */}
+      {recentTimeline.length > 0 && (
+        <ul className="mt-2 space-y-0.5 border-l border-slate-700 pl-2">
+          {recentTimeline.map((entry, i) => (
+            <li key={i} className="text-xs text-slate-400 truncate" title={entry.message}>
+              <span className="text-slate-500">{timeAgo(entry.time)}</span> {entry.message}
+            </li>
+          ))}
+        </ul>
+      )}
+
       {/* Investigate button */}
       {canInvestigate && (
         <button
@@ -94,6 +111,21 @@ export function IncidentCard({ incident, isSelected, onSelect, onInvestigate }:
           Investigate
         </button>
       )}
+
+      {/* Postmortem export */}
+      {incident.persisted && onExportPostmortem && (
+        <button
+          onClick={(e) => {
+            e.stopPropagation();
+            onExportPostmortem(incident.id);
+          }}
+          className="mt-2 flex items-center gap-1.5 px-3 py-1.5 text-xs bg-slate-700 text-slate-200 rounded-md hover:bg-slate-600 transition-colors"
+          title="Export a postmortem draft"
+        >
+          <FileText className="w-3 h-3" />
+          Postmortem
+        </button>
+      )}
     </div>
   );
 }
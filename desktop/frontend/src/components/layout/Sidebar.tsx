@@ -13,12 +13,17 @@ import {
   StopCircle,
   MoreVertical,
   Tag,
+  GitBranch,
+  Lock,
+  Download,
+  Upload,
 } from 'lucide-react';
 import type { Project, AgentSession, SessionStatus } from '../../types';
 import { FirefighterBadge } from '../firefighter/FirefighterBadge';
 import { BoatmanModeBadge } from '../boatmanmode/BoatmanModeBadge';
 import { TriageBadge } from '../triage/TriageBadge';
 import { ClaudeBadge } from '../chat/ClaudeBadge';
+import { buildSessionTree } from '../../utils/sessionTree';
 
 interface SidebarProps {
   projects: Project[];
@@ -33,6 +38,8 @@ interface SidebarProps {
   onToggleFavorite?: (sessionId: string) => void;
   onAddTag?: (sessionId: string, tag: string) => void;
   onRemoveTag?: (sessionId: string, tag: string) => void;
+  onExportSession?: (sessionId: string, format: 'markdown' | 'html' | 'bundle') => void;
+  onImportBundle?: () => void;
   isOpen: boolean;
 }
 
@@ -46,6 +53,8 @@ function getStatusColor(status: SessionStatus): string {
       return 'text-red-500';
     case 'stopped':
       return 'text-slate-400';
+    case 'interrupted':
+      return 'text-amber-500';
     default:
       return 'text-slate-500';
   }
@@ -75,6 +84,8 @@ export function Sidebar({
   onToggleFavorite,
   onAddTag,
   onRemoveTag,
+  onExportSession,
+  onImportBundle,
   isOpen,
 }: SidebarProps) {
   const [projectsExpanded, setProjectsExpanded] = useState(true);
@@ -140,6 +151,19 @@ export function Sidebar({
             )}
             Sessions
           </button>
+          {onImportBundle && (
+            <button
+              onClick={(e) => {
+                e.stopPropagation();
+                onImportBundle();
+              }}
+              className="px-2 py-2 text-xs text-slate-500 hover:text-slate-200 transition-colors"
+              aria-label="Import session bundle"
+              title="Import session bundle"
+            >
+              <Upload className="w-3 h-3" />
+            </button>
+          )}
           {onDeleteAllSessions && sessions.length > 0 && (
             <button
               onClick={(e) => {
@@ -159,8 +183,8 @@ export function Sidebar({
             {sessions.length === 0 ? (
               <p className="px-2 py-1 text-xs text-slate-500">No active sessions</p>
             ) : (
-              [...sessions].sort((a, b) => new Date(b.createdAt).getTime() - new Date(a.createdAt).getTime()).map((session) => (
-                <div key={session.id} className="relative">
+              buildSessionTree(sessions).map(({ session, depth }) => (
+                <div key={session.id} className="relative" style={{ marginLeft: depth * 12 }}>
                   <div
                     className={`group flex items-start gap-2 px-2 py-1.5 rounded-md transition-colors cursor-pointer ${
                       activeSessionId === session.id
@@ -172,6 +196,16 @@ export function Sidebar({
                     <span className={`${getStatusColor(session.status)} mt-0.5`}>
                       {getStatusIcon(session.status)}
                     </span>
+                    {session.parentId && (
+                      <span className="text-slate-500 mt-0.5" title="Forked session">
+                        <GitBranch className="w-3 h-3" />
+                      </span>
+                    )}
+                    {session.readOnly && (
+                      <span className="text-slate-500 mt-0.5" title="Imported session (read-only)">
+                        <Lock className="w-3 h-3" />
+                      </span>
+                    )}
                     <div className="flex-1 min-w-0">
                       <div className="flex items-center gap-1">
                         <p
@@ -338,6 +372,29 @@ export function Sidebar({
                             <Tag className="w-3 h-3" />
                             Add Tag
                           </button>
+                          {onExportSession && (
+                            <>
+                              <div className="my-1 border-t border-slate-700" />
+                              {([
+                                ['markdown', 'Export as Markdown'],
+                                ['html', 'Export as HTML'],
+                                ['bundle', 'Export Bundle'],
+                              ] as const).map(([format, label]) => (
+                                <button
+                                  key={format}
+                                  onClick={(e) => {
+                                    e.stopPropagation();
+                                    onExportSession(session.id, format);
+                                    setMenuOpenSession(null);
+                                  }}
+                                  className="w-full flex items-center gap-2 px-2 py-1.5 text-xs text-slate-300 hover:bg-slate-700 rounded transition-colors"
+                                >
+                                  <Download className="w-3 h-3" />
+                                  {label}
+                                </button>
+                              ))}
+                            </>
+                          )}
                         </>
                       )}
                     </div>
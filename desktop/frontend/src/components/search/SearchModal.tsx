@@ -129,6 +129,7 @@ export function SearchModal({
             onClear={handleClear}
             onToggleFilters={() => setShowFilters(!showFilters)}
             showFilters={showFilters}
+            placeholder='Search sessions... ("exact phrase", tag:, project:, model:, after:YYYY-MM-DD)'
             autoFocus
           />
         </div>
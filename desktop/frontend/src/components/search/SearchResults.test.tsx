@@ -267,6 +267,67 @@ describe('SearchResults', () => {
     });
   });
 
+  describe('Snippets', () => {
+    const mockResults: SearchResult[] = [
+      {
+        sessionId: 'session-1',
+        projectPath: '/path/to/project1',
+        createdAt: '2024-01-15T10:00:00Z',
+        updatedAt: '2024-01-15T15:30:00Z',
+        tags: [],
+        isFavorite: false,
+        messageCount: 10,
+        score: 12,
+        matchReasons: ['1 message match'],
+        status: 'idle',
+        snippets: [
+          {
+            messageId: 'msg-1',
+            field: 'content',
+            archived: false,
+            text: 'Fix the authentication bug in login',
+            highlights: [{ start: 8, end: 26 }],
+          },
+          {
+            messageId: 'msg-2',
+            field: 'toolInput',
+            archived: true,
+            text: 'Bash go test ./auth/...',
+            highlights: [{ start: 15, end: 19 }],
+          },
+        ],
+      },
+    ];
+
+    it('should highlight matched ranges', () => {
+      const { container } = render(
+        <SearchResults
+          results={mockResults}
+          onSelectSession={mockOnSelectSession}
+          isLoading={false}
+        />
+      );
+
+      const marks = container.querySelectorAll('mark');
+      expect(marks).toHaveLength(2);
+      expect(marks[0]).toHaveTextContent('authentication bug');
+      expect(marks[1]).toHaveTextContent('auth');
+    });
+
+    it('should label tool call and archived snippets', () => {
+      render(
+        <SearchResults
+          results={mockResults}
+          onSelectSession={mockOnSelectSession}
+          isLoading={false}
+        />
+      );
+
+      expect(screen.getByText('Tool call:')).toBeInTheDocument();
+      expect(screen.getByLabelText('Archived')).toBeInTheDocument();
+    });
+  });
+
   describe('User interactions', () => {
     const mockResults: SearchResult[] = [
       {
@@ -1,4 +1,18 @@
-import { Star, Folder, MessageSquare, Calendar } from 'lucide-react';
+import type { ReactNode } from 'react';
+import { Star, Folder, MessageSquare, Calendar, Archive } from 'lucide-react';
+
+export interface SearchHighlight {
+  start: number;
+  end: number;
+}
+
+export interface SearchSnippet {
+  messageId: string;
+  field: string;
+  archived: boolean;
+  text: string;
+  highlights: SearchHighlight[];
+}
 
 export interface SearchResult {
   sessionId: string;
@@ -11,6 +25,7 @@ export interface SearchResult {
   score: number;
   matchReasons: string[];
   status: string;
+  snippets?: SearchSnippet[];
 }
 
 interface SearchResultsProps {
@@ -123,6 +138,15 @@ function SearchResultItem({ result, onSelect }: SearchResultItemProps) {
         </div>
       )}
 
+      {/* Snippets */}
+      {result.snippets && result.snippets.length > 0 && (
+        <div className="space-y-1 mb-2">
+          {result.snippets.map((snippet) => (
+            <SnippetText key={`${snippet.messageId}-${snippet.field}`} snippet={snippet} />
+          ))}
+        </div>
+      )}
+
       {/* Footer */}
       <div className="flex items-center gap-4 text-xs text-slate-500">
         <div className="flex items-center gap-1">
@@ -143,6 +167,48 @@ function SearchResultItem({ result, onSelect }: SearchResultItemProps) {
   );
 }
 
+function SnippetText({ snippet }: { snippet: SearchSnippet }) {
+  const parts: ReactNode[] = [];
+  let last = 0;
+  snippet.highlights.forEach((hl, i) => {
+    if (hl.start > last) {
+      parts.push(<span key={`t${i}`}>{snippet.text.slice(last, hl.start)}</span>);
+    }
+    parts.push(
+      <mark key={`h${i}`} className="bg-yellow-500/30 text-yellow-200 rounded-sm">
+        {snippet.text.slice(hl.start, hl.end)}
+      </mark>
+    );
+    last = hl.end;
+  });
+  if (last < snippet.text.length) {
+    parts.push(<span key="rest">{snippet.text.slice(last)}</span>);
+  }
+
+  return (
+    <p className="text-xs text-slate-400 line-clamp-2 break-words">
+      {snippet.archived && (
+        <Archive className="inline w-3 h-3 mr-1 text-slate-500" aria-label="Archived" />
+      )}
+      {snippet.field !== 'content' && (
+        <span className="mr-1 text-slate-500">{getFieldLabel(snippet.field)}</span>
+      )}
+      {parts}
+    </p>
+  );
+}
+
+function getFieldLabel(field: string): string {
+  switch (field) {
+    case 'toolInput':
+      return 'Tool call:';
+    case 'toolOutput':
+      return 'Tool output:';
+    default:
+      return '';
+  }
+}
+
 function getRelativeTime(date: Date): string {
   const now = new Date();
   const diffMs = now.getTime() - date.getTime();
@@ -169,6 +235,8 @@ function getStatusColor(status: string): string {
       return 'bg-red-500/20 text-red-400 border border-red-500/30';
     case 'stopped':
       return 'bg-slate-600/20 text-slate-400 border border-slate-600/30';
+    case 'interrupted':
+      return 'bg-amber-500/20 text-amber-400 border border-amber-500/30';
     default:
       return 'bg-slate-600/20 text-slate-400 border border-slate-600/30';
   }
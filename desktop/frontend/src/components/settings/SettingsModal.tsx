@@ -595,6 +595,35 @@ function MemorySettings({
           </label>
         </div>
       </div>
+
+      <div className="pt-6 border-t border-slate-700">
+        <h3 className="text-sm font-medium text-slate-100 mb-2">Context Window</h3>
+        <p className="text-xs text-slate-400 mb-4">
+          Long conversations are summarized into a fresh context before they hit the model's limit
+        </p>
+
+        <div>
+          <label className="block text-sm text-slate-300 mb-2">
+            Auto-Compact Threshold (%)
+          </label>
+          <input
+            type="number"
+            min="0"
+            max="95"
+            value={Math.round((preferences.autoCompactThreshold ?? 0.8) * 100)}
+            onChange={(e) =>
+              onChange({
+                ...preferences,
+                autoCompactThreshold: Math.min(95, Math.max(0, parseInt(e.target.value) || 0)) / 100,
+              })
+            }
+            className="w-full px-4 py-2 bg-slate-800 border border-slate-700 rounded-lg text-sm text-slate-100 focus:outline-none focus:border-blue-500"
+          />
+          <p className="text-xs text-slate-500 mt-1">
+            Compact when a session uses this much of the context window; 0 turns it off (default: 80). Use /compact to compact at any time.
+          </p>
+        </div>
+      </div>
     </div>
   );
 }
@@ -374,6 +374,11 @@ export function TriageView({ sessionId, status, getTriageResult, onExecuteTicket
               {(triageResult.stats.totalTokensUsed / 1000).toFixed(1)}K tokens | ${triageResult.stats.totalCostUsd.toFixed(4)}
             </span>
           )}
+          {(triageResult.stats.cacheHits ?? 0) > 0 && (
+            <span>
+              {triageResult.stats.cacheHits} cached | ${(triageResult.stats.cacheSavedUsd ?? 0).toFixed(4)} saved
+            </span>
+          )}
           <button
             onClick={async () => {
               try {
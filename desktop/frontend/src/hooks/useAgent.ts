@@ -1,6 +1,6 @@
 import { useEffect, useCallback } from 'react';
 import { useStore } from '../store';
-import type { AgentSession, Message, Task, SessionStatus, BoatmanModeEventPayload, TriageEventPayload, TriageOptions } from '../types';
+import type { AgentSession, Message, Task, PendingAction, SessionStatus, ContextUsage, BoatmanModeEventPayload, TriageEventPayload, TriageOptions } from '../types';
 
 // Import Wails bindings (will be generated)
 import {
@@ -12,10 +12,12 @@ import {
   DeleteAgentSession,
   SendAgentMessage,
   ApproveAgentAction,
+  ApproveAgentActionAlways,
   RejectAgentAction,
   GetAgentMessages,
   GetAgentMessagesPaginated,
   GetAgentTasks,
+  GetPendingAgentActions,
   ListAgentSessions,
   StartFirefighterMonitoring,
   StopFirefighterMonitoring,
@@ -28,8 +30,14 @@ import {
   GetTriageResult,
   ExecuteTriageTicket,
   ResumeBoatmanModeExecution,
+  ResumeInterruptedSession,
   SetSessionModel,
   SetSessionReasoningEffort,
+  GetSessionContextUsage,
+  CompactSessionConversation,
+  ForkSession,
+  ExportSession,
+  ImportSessionBundle,
 } from '../../wailsjs/go/main/App';
 import { EventsOn, EventsOff } from '../../wailsjs/runtime/runtime';
 
@@ -42,12 +50,14 @@ export function useAgent() {
     removeSession,
     setActiveSession,
     updateSessionStatus,
+    updateContextUsage,
     addMessage,
     setMessages,
     appendMessages,
     setMessagePagination,
     updateTask,
     setTasks,
+    updatePendingAction,
     setLoading,
     setError,
   } = useStore();
@@ -69,6 +79,15 @@ export function useAgent() {
       updateSessionStatus(data.sessionId, data.status);
     };
 
+    const contextHandler = (usage: ContextUsage) => {
+      updateContextUsage(usage.sessionId, usage);
+    };
+
+    const approvalHandler = (data: { sessionId: string; action: PendingAction }) => {
+      console.log('[FRONTEND] Received approval event:', data);
+      updatePendingAction(data.sessionId, data.action);
+    };
+
     const boatmanModeEventHandler = async (data: BoatmanModeEventPayload) => {
       console.log('[FRONTEND] Received boatmanmode event:', data);
       try {
@@ -144,6 +163,8 @@ export function useAgent() {
     EventsOn('agent:message', messageHandler);
     EventsOn('agent:task', taskHandler);
     EventsOn('agent:status', statusHandler);
+    EventsOn('agent:approval', approvalHandler);
+    EventsOn('agent:context', contextHandler);
     EventsOn('boatmanmode:event', boatmanModeEventHandler);
     EventsOn('boatmanmode:output', boatmanOutputHandler);
     EventsOn('boatmanmode:error', boatmanErrorHandler);
@@ -157,6 +178,8 @@ export function useAgent() {
       EventsOff('agent:message');
       EventsOff('agent:task');
       EventsOff('agent:status');
+      EventsOff('agent:approval');
+      EventsOff('agent:context');
       EventsOff('boatmanmode:event');
       EventsOff('boatmanmode:output');
       EventsOff('boatmanmode:error');
@@ -165,7 +188,7 @@ export function useAgent() {
       EventsOff('triage:error');
       EventsOff('triage:complete');
     };
-  }, [addMessage, updateTask, updateSessionStatus]);
+  }, [addMessage, updateTask, updateSessionStatus, updatePendingAction, updateContextUsage]);
 
   // Load existing sessions on mount
   useEffect(() => {
@@ -193,6 +216,9 @@ export function useAgent() {
             model: info.model || 'sonnet',
             reasoningEffort: info.reasoningEffort || 'medium',
             mode: info.mode || '',
+            parentId: info.parentId,
+            forkedFrom: info.forkedFrom,
+            readOnly: info.readOnly,
           });
         });
       } catch (err) {
@@ -363,6 +389,15 @@ export function useAgent() {
     }
   }, [setError]);
 
+  // Approve an action and always allow matching tool calls in the project
+  const approveActionAlways = useCallback(async (sessionId: string, actionId: string) => {
+    try {
+      await ApproveAgentActionAlways(sessionId, actionId);
+    } catch (err) {
+      setError('Failed to approve action');
+    }
+  }, [setError]);
+
   // Reject an action
   const rejectAction = useCallback(async (sessionId: string, actionId: string) => {
     try {
@@ -422,11 +457,112 @@ export function useAgent() {
     }
   }, [setTasks]);
 
+  // Load tool calls waiting for approval
+  const loadPendingActions = useCallback(async (sessionId: string) => {
+    try {
+      const actions = await GetPendingAgentActions(sessionId);
+      (actions as unknown as PendingAction[]).forEach((action) => updatePendingAction(sessionId, action));
+    } catch (err) {
+      console.error('Failed to load pending actions:', err);
+    }
+  }, [updatePendingAction]);
+
+  // Load how much of the context window a session uses
+  const loadContextUsage = useCallback(async (sessionId: string) => {
+    try {
+      const usage = await GetSessionContextUsage(sessionId);
+      updateContextUsage(sessionId, usage as ContextUsage);
+    } catch (err) {
+      console.error('Failed to load context usage:', err);
+    }
+  }, [updateContextUsage]);
+
   // Select a session
   const selectSession = useCallback(async (sessionId: string) => {
     setActiveSession(sessionId);
-    await Promise.all([loadMessages(sessionId), loadTasks(sessionId)]);
-  }, [setActiveSession, loadMessages, loadTasks]);
+    await Promise.all([
+      loadMessages(sessionId),
+      loadTasks(sessionId),
+      loadPendingActions(sessionId),
+      loadContextUsage(sessionId),
+    ]);
+  }, [setActiveSession, loadMessages, loadTasks, loadPendingActions, loadContextUsage]);
+
+  // Fork a session from a message into a new session, optionally in its own worktree
+  const forkSession = useCallback(async (sessionId: string, messageId: string, withWorktree: boolean): Promise<string | null> => {
+    try {
+      const info = await ForkSession(sessionId, messageId, withWorktree);
+      addSession({
+        id: info.id,
+        projectPath: info.projectPath,
+        status: info.status as SessionStatus,
+        createdAt: info.createdAt,
+        messages: [],
+        tasks: [],
+        tags: info.tags || [],
+        model: info.model || 'sonnet',
+        reasoningEffort: info.reasoningEffort || 'medium',
+        mode: info.mode || '',
+        parentId: info.parentId,
+        forkedFrom: info.forkedFrom,
+      });
+      await selectSession(info.id);
+      return info.id;
+    } catch (err) {
+      console.error('Failed to fork session:', err);
+      setError(`Failed to fork session: ${err}`);
+      return null;
+    }
+  }, [addSession, selectSession, setError]);
+
+  // Export a session as a Markdown or HTML transcript or a portable bundle.
+  // Returns the saved path, or null if cancelled or failed.
+  const exportSession = useCallback(async (sessionId: string, format: 'markdown' | 'html' | 'bundle'): Promise<string | null> => {
+    try {
+      const path = await ExportSession(sessionId, format);
+      return path || null;
+    } catch (err) {
+      console.error('Failed to export session:', err);
+      setError(`Failed to export session: ${err}`);
+      return null;
+    }
+  }, [setError]);
+
+  // Import an exported bundle as a read-only session
+  const importSessionBundle = useCallback(async (): Promise<string | null> => {
+    try {
+      const info = await ImportSessionBundle();
+      if (!info) return null; // Cancelled
+      addSession({
+        id: info.id,
+        projectPath: info.projectPath,
+        status: info.status as SessionStatus,
+        createdAt: info.createdAt,
+        messages: [],
+        tasks: [],
+        tags: info.tags || [],
+        model: info.model || 'sonnet',
+        mode: info.mode || '',
+        readOnly: info.readOnly,
+      });
+      await selectSession(info.id);
+      return info.id;
+    } catch (err) {
+      console.error('Failed to import session bundle:', err);
+      setError(`Failed to import session bundle: ${err}`);
+      return null;
+    }
+  }, [addSession, selectSession, setError]);
+
+  // Summarize a session's conversation and continue it in a fresh context
+  const compactSession = useCallback(async (sessionId: string) => {
+    try {
+      await CompactSessionConversation(sessionId);
+    } catch (err) {
+      console.error('Failed to compact conversation:', err);
+      setError(`Failed to compact conversation: ${err}`);
+    }
+  }, [setError]);
 
   // Get active session
   const activeSession = sessions.find((s) => s.id === activeSessionId) ?? null;
@@ -560,6 +696,17 @@ export function useAgent() {
     }
   }, [updateSessionStatus, addMessage, setError]);
 
+  // Resume a session that was interrupted when the app quit mid-run
+  const resumeInterruptedSession = useCallback(async (sessionId: string) => {
+    try {
+      updateSessionStatus(sessionId, 'running');
+      await ResumeInterruptedSession(sessionId);
+    } catch (err) {
+      setError('Failed to resume session: ' + err);
+      updateSessionStatus(sessionId, 'interrupted');
+    }
+  }, [updateSessionStatus, setError]);
+
   // Get triage result for a session
   const getTriageResult = useCallback(async (sessionId: string) => {
     try {
@@ -591,6 +738,10 @@ export function useAgent() {
     createTriageSession,
     executeTriageTicket,
     resumeSession,
+    resumeInterruptedSession,
+    forkSession,
+    exportSession,
+    importSessionBundle,
     getTriageResult,
     startSession,
     stopSession,
@@ -598,11 +749,13 @@ export function useAgent() {
     selectSession,
     sendMessage,
     approveAction,
+    approveActionAlways,
     rejectAction,
     loadMessagesPaginated,
     toggleFirefighterMonitoring,
     isMonitoringActive,
     setSessionModel: setModel,
     setSessionReasoningEffort: setReasoningEffort,
+    compactSession,
   };
 }
@@ -1,13 +1,70 @@
-import { useState, useCallback } from 'react';
-import { GetGitDiff, GetWorktreeDiff, ParseDiff, GetSideBySideDiff } from '../../wailsjs/go/main/App';
+import { useState, useCallback, useEffect } from 'react';
+import {
+  GetGitDiff,
+  GetWorktreeDiff,
+  GetImportedSessionDiff,
+  ParseDiff,
+  GetSideBySideDiff,
+  RequestDiffChanges,
+  StageHunks,
+  DiscardHunks,
+  DiscardBranchHunks,
+  CommitApprovedHunks,
+  CommitApprovedBranchHunks,
+} from '../../wailsjs/go/main/App';
 import { diff } from '../../wailsjs/go/models';
+import { EventsOn, EventsOff } from '../../wailsjs/runtime/runtime';
 import { useStore } from '../store';
+import type { DiffChangeStatus } from '../types';
+
+// Changes requested from the diff view and, once the agent is done, whether
+// each was addressed
+export interface DiffChangeRequest {
+  sessionId: string;
+  changes: diff.RequestedChange[];
+  statuses: Record<string, DiffChangeStatus>;
+  state: 'pending' | 'verified' | 'error';
+  error?: string;
+}
 
 export function useDiff() {
   const [diffs, setDiffs] = useState<diff.FileDiff[]>([]);
   const [sideBySideData, setSideBySideData] = useState<Record<string, diff.SideBySideLine[]>>({});
+  const [changeRequest, setChangeRequest] = useState<DiffChangeRequest | null>(null);
   const { setError } = useStore();
 
+  // Subscribe to verification of requested changes
+  useEffect(() => {
+    const verifiedHandler = (data: {
+      sessionId: string;
+      statuses?: DiffChangeStatus[];
+      error?: string;
+      runError?: string;
+    }) => {
+      setChangeRequest((prev) => {
+        if (!prev || prev.sessionId !== data.sessionId) {
+          return prev;
+        }
+        const statuses: Record<string, DiffChangeStatus> = {};
+        (data.statuses || []).forEach((status) => {
+          statuses[status.id] = status;
+        });
+        return {
+          ...prev,
+          statuses,
+          state: data.error ? 'error' : 'verified',
+          error: data.error || data.runError,
+        };
+      });
+    };
+
+    EventsOn('diff:changes-verified', verifiedHandler);
+
+    return () => {
+      EventsOff('diff:changes-verified');
+    };
+  }, []);
+
   // Load diffs from git status
   const loadDiffs = useCallback(async (projectPath: string) => {
     try {
@@ -27,7 +84,7 @@ export function useDiff() {
     }
   }, [setError]);
 
-  // Load diffs from a worktree (for boatman mode - changes are committed, so diff against base branch)
+  // Load diffs from a worktree (for boatman mode - changes are committed, so diff against the merge-base with the base branch)
   const loadWorktreeDiffs = useCallback(async (worktreePath: string, baseBranch: string) => {
     try {
       const diffText = await GetWorktreeDiff(worktreePath, baseBranch || 'main');
@@ -45,6 +102,24 @@ export function useDiff() {
     }
   }, [setError]);
 
+  // Load the final diff stored with an imported session
+  const loadImportedDiffs = useCallback(async (sessionId: string) => {
+    try {
+      const diffText = await GetImportedSessionDiff(sessionId);
+
+      if (diffText && diffText.trim()) {
+        const parsedDiffs = await ParseDiff(diffText);
+        setDiffs(parsedDiffs);
+      } else {
+        setDiffs([]);
+      }
+    } catch (err) {
+      console.error('Failed to load imported diffs:', err);
+      setError('Failed to load imported changes');
+      setDiffs([]);
+    }
+  }, [setError]);
+
   // Load side-by-side data for a specific file
   const loadSideBySide = useCallback(async (fileDiff: diff.FileDiff) => {
     try {
@@ -149,11 +224,86 @@ export function useDiff() {
     );
   }, []);
 
+  // Send comments and unapproved hunks back to the session's agent
+  const requestChanges = useCallback(async (sessionId: string) => {
+    try {
+      const changes = await RequestDiffChanges(sessionId, diffs);
+      setChangeRequest({
+        sessionId,
+        changes: changes || [],
+        statuses: {},
+        state: 'pending',
+      });
+    } catch (err) {
+      console.error('Failed to request changes:', err);
+      setError(`Failed to request changes: ${err}`);
+    }
+  }, [diffs, setError]);
+
+  const dismissChangeRequest = useCallback(() => {
+    setChangeRequest(null);
+  }, []);
+
+  // Stage a single hunk of a file's unstaged changes
+  const stageHunk = useCallback(async (repoPath: string, filePath: string, hunkId: string) => {
+    try {
+      await StageHunks(repoPath, filePath, [new diff.HunkSelection({ hunkId })]);
+      await loadDiffs(repoPath);
+    } catch (err) {
+      console.error('Failed to stage hunk:', err);
+      setError(`Failed to stage hunk: ${err}`);
+    }
+  }, [loadDiffs, setError]);
+
+  // Reload after changing a working tree; with baseBranch, an agent worktree
+  // whose diff is against the merge-base with it
+  const reloadDiffs = useCallback(async (repoPath: string, baseBranch?: string) => {
+    if (baseBranch) {
+      await loadWorktreeDiffs(repoPath, baseBranch);
+    } else {
+      await loadDiffs(repoPath);
+    }
+  }, [loadDiffs, loadWorktreeDiffs]);
+
+  // Revert a single hunk in the working tree
+  const discardHunk = useCallback(async (repoPath: string, filePath: string, hunkId: string, baseBranch?: string) => {
+    try {
+      const selections = [new diff.HunkSelection({ hunkId })];
+      if (baseBranch) {
+        await DiscardBranchHunks(repoPath, baseBranch, filePath, selections);
+      } else {
+        await DiscardHunks(repoPath, filePath, selections);
+      }
+      await reloadDiffs(repoPath, baseBranch);
+    } catch (err) {
+      console.error('Failed to discard hunk:', err);
+      setError(`Failed to discard hunk: ${err}`);
+    }
+  }, [reloadDiffs, setError]);
+
+  // Commit only the approved files and hunks, leaving the rest in the working
+  // tree. For an agent worktree the commit goes on top of its branch.
+  const commitApproved = useCallback(async (repoPath: string, message: string, baseBranch?: string) => {
+    try {
+      const hash = baseBranch
+        ? await CommitApprovedBranchHunks(repoPath, baseBranch, diffs, message)
+        : await CommitApprovedHunks(repoPath, diffs, message);
+      await reloadDiffs(repoPath, baseBranch);
+      return hash;
+    } catch (err) {
+      console.error('Failed to commit approved changes:', err);
+      setError(`Failed to commit approved changes: ${err}`);
+      return null;
+    }
+  }, [diffs, reloadDiffs, setError]);
+
   return {
     diffs,
     sideBySideData,
+    changeRequest,
     loadDiffs,
     loadWorktreeDiffs,
+    loadImportedDiffs,
     loadSideBySide,
     acceptFile,
     rejectFile,
@@ -162,5 +312,10 @@ export function useDiff() {
     updateComments,
     approveHunk,
     rejectHunk,
+    requestChanges,
+    dismissChangeRequest,
+    stageHunk,
+    discardHunk,
+    commitApproved,
   };
 }
@@ -0,0 +1,70 @@
+import { useState, useEffect, useMemo, useCallback } from 'react';
+import { GetFirefighterIncidents, ExportIncidentPostmortem } from '../../wailsjs/go/main/App';
+import { EventsOn } from '../../wailsjs/runtime/runtime';
+import { agent } from '../../wailsjs/go/models';
+import { parseIncidentsFromMessages, mergeStoredIncidents } from '../utils/parseIncidents';
+import type { Incident, IncidentTimelineEntry, Message } from '../types';
+
+function toIncident(stored: agent.Incident): Incident {
+  return {
+    id: stored.id,
+    source: stored.source as Incident['source'],
+    severity: stored.severity as Incident['severity'],
+    title: stored.title,
+    description: stored.description,
+    status: stored.status as Incident['status'],
+    firstSeen: stored.firstSeen,
+    lastUpdated: stored.lastUpdated,
+    messageIds: stored.messageIds || [],
+    linearId: stored.linearId,
+    slackThread: stored.slackThread,
+    url: stored.url,
+    prNumber: stored.prNumber,
+    persisted: true,
+    alertCount: (stored.alerts || []).reduce((sum, alert) => sum + alert.count, 0),
+    timeline: (stored.timeline || []) as IncidentTimelineEntry[],
+  };
+}
+
+// useIncidents combines the incidents stored for a firefighter session with
+// any the store doesn't track that can be parsed from its messages
+export function useIncidents(sessionId: string, messages: Message[]) {
+  const [stored, setStored] = useState<Incident[]>([]);
+
+  useEffect(() => {
+    let cancelled = false;
+    GetFirefighterIncidents()
+      .then((incidents) => {
+        if (cancelled) return;
+        setStored((incidents || [])
+          .filter(i => (i.sessionIds || []).includes(sessionId))
+          .map(toIncident));
+      })
+      .catch((error) => console.error('Failed to load incidents:', error));
+
+    const unsubscribe = EventsOn('firefighter:incident', (incident: agent.Incident) => {
+      if (!(incident.sessionIds || []).includes(sessionId)) return;
+      setStored(prev => [toIncident(incident), ...prev.filter(i => i.id !== incident.id)]);
+    });
+
+    return () => {
+      cancelled = true;
+      unsubscribe();
+    };
+  }, [sessionId]);
+
+  const incidents = useMemo(
+    () => mergeStoredIncidents(stored, parseIncidentsFromMessages(messages)),
+    [stored, messages],
+  );
+
+  const exportPostmortem = useCallback(async (incidentId: string) => {
+    try {
+      await ExportIncidentPostmortem(incidentId);
+    } catch (error) {
+      console.error('Failed to export postmortem:', error);
+    }
+  }, []);
+
+  return { incidents, exportPostmortem };
+}
@@ -50,6 +50,7 @@ export function useSearch() {
           score: r.score || 0,
           matchReasons: r.matchReasons || [],
           status: r.status || 'idle',
+          snippets: r.snippets || [],
         }));
       } catch (error) {
         console.error('Search failed:', error);
@@ -4,9 +4,11 @@ import type {
   AgentSession,
   Message,
   Task,
+  PendingAction,
   Project,
   UserPreferences,
   SessionStatus,
+  ContextUsage,
 } from '../types';
 
 // =============================================================================
@@ -31,6 +33,7 @@ interface AgentActions {
   removeSession: (sessionId: string) => void;
   setActiveSession: (sessionId: string | null) => void;
   updateSessionStatus: (sessionId: string, status: SessionStatus) => void;
+  updateContextUsage: (sessionId: string, usage: ContextUsage) => void;
 
   // Messages
   addMessage: (sessionId: string, message: Message) => void;
@@ -43,6 +46,9 @@ interface AgentActions {
   // Tasks
   updateTask: (sessionId: string, task: Task) => void;
   setTasks: (sessionId: string, tasks: Task[]) => void;
+
+  // Approvals
+  updatePendingAction: (sessionId: string, action: PendingAction) => void;
 }
 
 // =============================================================================
@@ -176,6 +182,17 @@ export const useStore = create<Store>()(
             'updateSessionStatus'
           ),
 
+        updateContextUsage: (sessionId, contextUsage) =>
+          set(
+            (state) => ({
+              sessions: state.sessions.map((s) =>
+                s.id === sessionId ? { ...s, contextUsage } : s
+              ),
+            }),
+            false,
+            'updateContextUsage'
+          ),
+
         addMessage: (sessionId, message) => {
           console.log('[STORE] addMessage called:', { sessionId, message });
           set(
@@ -265,6 +282,23 @@ export const useStore = create<Store>()(
             'setTasks'
           ),
 
+        updatePendingAction: (sessionId, action) =>
+          set(
+            (state) => ({
+              sessions: state.sessions.map((s) => {
+                if (s.id !== sessionId) return s;
+                // Resolved actions leave the queue; pending ones are added
+                const others = (s.pendingActions || []).filter((a) => a.id !== action.id);
+                return {
+                  ...s,
+                  pendingActions: action.status === 'pending' ? [...others, action] : others,
+                };
+              }),
+            }),
+            false,
+            'updatePendingAction'
+          ),
+
         // =============================================================================
         // Project Actions
         // =============================================================================
@@ -2,7 +2,7 @@
 // Agent Types
 // =============================================================================
 
-export type SessionStatus = 'idle' | 'running' | 'waiting' | 'error' | 'stopped';
+export type SessionStatus = 'idle' | 'running' | 'waiting' | 'error' | 'stopped' | 'interrupted';
 
 export interface Message {
   id: string;
@@ -25,6 +25,7 @@ export interface MessageMetadata {
   toolResult?: ToolResult;
   costInfo?: CostInfo;
   agent?: AgentInfo;
+  compaction?: CompactionInfo;
 }
 
 export interface ToolUse {
@@ -45,6 +46,25 @@ export interface CostInfo {
   totalCost: number;
 }
 
+// Marks where a session's conversation was summarized into a fresh context
+export interface CompactionInfo {
+  summary: string;
+  tokensBefore: number;
+  method: 'model' | 'compressed';
+  automatic: boolean;
+}
+
+// How much of the model's context window a session's conversation uses
+export interface ContextUsage {
+  sessionId: string;
+  tokens: number;
+  window: number;
+  percent: number;
+  threshold: number; // 0 when automatic compaction is off
+  compactions: number;
+  compacting: boolean;
+}
+
 export interface Task {
   id: string;
   subject: string;
@@ -53,6 +73,18 @@ export interface Task {
   metadata?: Record<string, any>;
 }
 
+// A tool call held until the user approves or rejects it
+export interface PendingAction {
+  id: string;
+  toolName: string;
+  toolUseId?: string;
+  input: Record<string, any>;
+  summary: string;
+  rule: string;
+  status: 'pending' | 'approved' | 'rejected';
+  requestedAt: string;
+}
+
 export interface AgentSession {
   id: string;
   projectPath: string;
@@ -66,6 +98,11 @@ export interface AgentSession {
   modeConfig?: Record<string, any>;
   model?: string;
   reasoningEffort?: string;
+  pendingActions?: PendingAction[];
+  contextUsage?: ContextUsage;
+  parentId?: string; // Session this one was forked from
+  forkedFrom?: string; // Parent message the fork starts after
+  readOnly?: boolean; // Imported from a bundle; can be browsed but not continued
 }
 
 export const MODEL_OPTIONS = [
@@ -166,6 +203,14 @@ export interface DiffComment {
   author?: string;
 }
 
+// Whether the agent's follow-up addressed a change requested from the diff view
+export interface DiffChangeStatus {
+  id: string;
+  addressed: boolean;
+  evidence?: string;
+  reason?: string;
+}
+
 export interface DiffSummary {
   totalFiles: number;
   filesAdded: number;
@@ -223,6 +268,7 @@ export interface UserPreferences {
   autoCleanupSessions?: boolean;
   maxAgentsPerSession?: number;
   keepCompletedAgents?: boolean;
+  autoCompactThreshold?: number;
 
   // Firefighter/Observability settings
   datadogAPIKey?: string;
@@ -338,6 +384,17 @@ export interface Incident {
   slackThread?: string;
   url?: string;
   prNumber?: string;
+  // Set for incidents from the incident store, which persists across restarts
+  persisted?: boolean;
+  alertCount?: number;
+  timeline?: IncidentTimelineEntry[];
+}
+
+export interface IncidentTimelineEntry {
+  time: string;
+  kind: 'alert' | 'status' | 'worktree' | 'pr' | 'summary' | 'note';
+  status?: Incident['status'];
+  message: string;
 }
 
 // =============================================================================
@@ -427,6 +484,8 @@ export interface TriageStats {
   clusterCount: number;
   totalTokensUsed: number;
   totalCostUsd: number;
+  cacheHits?: number;
+  cacheSavedUsd?: number;
 }
 
 export interface TriageResult {
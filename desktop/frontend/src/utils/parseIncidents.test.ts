@@ -0,0 +1,45 @@
+import { describe, it, expect } from 'vitest';
+import { mergeStoredIncidents } from './parseIncidents';
+import type { Incident } from '../types';
+
+function incident(id: string, title: string, extra: Partial<Incident> = {}): Incident {
+  return {
+    id,
+    source: 'bugsnag',
+    severity: 'medium',
+    title,
+    description: '',
+    status: 'new',
+    firstSeen: '2026-03-02T09:00:00Z',
+    lastUpdated: '2026-03-02T09:00:00Z',
+    messageIds: [],
+    ...extra,
+  };
+}
+
+describe('mergeStoredIncidents', () => {
+  it('drops parsed incidents the store already tracks', () => {
+    const stored = [
+      incident('a', 'Crash on login', { persisted: true }),
+      incident('b', 'Checkout fails', { linearId: 'ENG-12', persisted: true }),
+    ];
+    const parsed = [
+      incident('inc-crash', 'crash on login'),
+      incident('inc-eng-12', 'Checkout fails for EU users', { linearId: 'eng-12' }),
+      incident('inc-disk', 'Disk full on worker'),
+    ];
+
+    const merged = mergeStoredIncidents(stored, parsed);
+
+    expect(merged.map(i => i.id).sort()).toEqual(['a', 'b', 'inc-disk']);
+  });
+
+  it('sorts active incidents first, by severity', () => {
+    const merged = mergeStoredIncidents(
+      [incident('resolved', 'Old', { status: 'resolved', severity: 'urgent' })],
+      [incident('low', 'Low', { severity: 'low' }), incident('high', 'High', { severity: 'high' })],
+    );
+
+    expect(merged.map(i => i.id)).toEqual(['high', 'low', 'resolved']);
+  });
+});
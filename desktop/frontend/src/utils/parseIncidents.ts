@@ -295,7 +295,22 @@ export function parseIncidentsFromMessages(messages: Message[]): Incident[] {
     }
   }
 
-  // Sort: active first (by severity), then resolved
+  return sortIncidents(Array.from(incidentMap.values()));
+}
+
+// Combine stored incidents with those parsed from messages. Parsed incidents
+// the store already tracks (same Linear ticket or title) are dropped.
+export function mergeStoredIncidents(stored: Incident[], parsed: Incident[]): Incident[] {
+  const linearIds = new Set(stored.filter(i => i.linearId).map(i => i.linearId!.toUpperCase()));
+  const titles = new Set(stored.map(i => i.title.toLowerCase()));
+  const untracked = parsed.filter(i =>
+    !(i.linearId && linearIds.has(i.linearId.toUpperCase())) && !titles.has(i.title.toLowerCase()),
+  );
+  return sortIncidents([...stored, ...untracked]);
+}
+
+// Sort: active first (by severity), then resolved
+function sortIncidents(incidents: Incident[]): Incident[] {
   const statusOrder: Record<Incident['status'], number> = {
     new: 0, investigating: 1, fixing: 2, testing: 3, resolved: 4, failed: 5,
   };
@@ -303,7 +318,7 @@ export function parseIncidentsFromMessages(messages: Message[]): Incident[] {
     urgent: 0, high: 1, medium: 2, low: 3,
   };
 
-  return Array.from(incidentMap.values()).sort((a, b) => {
+  return incidents.sort((a, b) => {
     const statusDiff = statusOrder[a.status] - statusOrder[b.status];
     if (statusDiff !== 0) return statusDiff;
     return severityOrder[a.severity] - severityOrder[b.severity];
@@ -0,0 +1,53 @@
+import { describe, it, expect } from 'vitest';
+import { buildSessionTree } from './sessionTree';
+import type { AgentSession } from '../types';
+
+function session(id: string, createdAt: string, parentId?: string): AgentSession {
+  return {
+    id,
+    projectPath: '/project',
+    status: 'idle',
+    createdAt,
+    messages: [],
+    tasks: [],
+    parentId,
+  };
+}
+
+describe('buildSessionTree', () => {
+  it('lists sessions without forks newest first', () => {
+    const tree = buildSessionTree([
+      session('old', '2026-01-01T10:00:00Z'),
+      session('new', '2026-01-02T10:00:00Z'),
+    ]);
+
+    expect(tree.map((n) => [n.session.id, n.depth])).toEqual([
+      ['new', 0],
+      ['old', 0],
+    ]);
+  });
+
+  it('nests forks under their parent', () => {
+    const tree = buildSessionTree([
+      session('parent', '2026-01-01T10:00:00Z'),
+      session('other', '2026-01-03T10:00:00Z'),
+      session('fork-a', '2026-01-02T10:00:00Z', 'parent'),
+      session('fork-b', '2026-01-04T10:00:00Z', 'parent'),
+      session('fork-of-fork', '2026-01-05T10:00:00Z', 'fork-a'),
+    ]);
+
+    expect(tree.map((n) => [n.session.id, n.depth])).toEqual([
+      ['other', 0],
+      ['parent', 0],
+      ['fork-b', 1],
+      ['fork-a', 1],
+      ['fork-of-fork', 2],
+    ]);
+  });
+
+  it('lists a fork whose parent was deleted as a root', () => {
+    const tree = buildSessionTree([session('orphan', '2026-01-01T10:00:00Z', 'deleted')]);
+
+    expect(tree).toEqual([{ session: expect.objectContaining({ id: 'orphan' }), depth: 0 }]);
+  });
+});
@@ -0,0 +1,41 @@
+import type { AgentSession } from '../types';
+
+export interface SessionTreeNode {
+  session: AgentSession;
+  depth: number;
+}
+
+const newestFirst = (a: AgentSession, b: AgentSession) =>
+  new Date(b.createdAt).getTime() - new Date(a.createdAt).getTime();
+
+/**
+ * Orders sessions as a tree: each fork follows its parent, one level deeper.
+ * Roots and siblings are listed newest first. A fork whose parent has been
+ * deleted is listed as a root.
+ */
+export function buildSessionTree(sessions: AgentSession[]): SessionTreeNode[] {
+  const ids = new Set(sessions.map((s) => s.id));
+  const children = new Map<string, AgentSession[]>();
+  const roots: AgentSession[] = [];
+
+  for (const session of sessions) {
+    if (session.parentId && ids.has(session.parentId)) {
+      const siblings = children.get(session.parentId) ?? [];
+      siblings.push(session);
+      children.set(session.parentId, siblings);
+    } else {
+      roots.push(session);
+    }
+  }
+
+  const nodes: SessionTreeNode[] = [];
+  const visit = (session: AgentSession, depth: number) => {
+    nodes.push({ session, depth });
+    for (const child of (children.get(session.id) ?? []).sort(newestFirst)) {
+      visit(child, depth + 1);
+    }
+  };
+  roots.sort(newestFirst).forEach((root) => visit(root, 0));
+
+  return nodes;
+}
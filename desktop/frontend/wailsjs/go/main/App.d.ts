@@ -12,10 +12,14 @@ import {triage} from '../models';
 
 export function AddMCPServer(arg1:mcp.Server):Promise<void>;
 
+export function AddProjectAllowedTool(arg1:string,arg2:string):Promise<void>;
+
 export function AddSessionTag(arg1:string,arg2:string):Promise<void>;
 
 export function ApproveAgentAction(arg1:string,arg2:string):Promise<void>;
 
+export function ApproveAgentActionAlways(arg1:string,arg2:string):Promise<void>;
+
 export function AutoDistillBrains():Promise<Array<services.AutoDistillResult>>;
 
 export function CheckClaudeCLI():Promise<boolean>;
@@ -24,6 +28,12 @@ export function CleanupOldSessions():Promise<number>;
 
 export function ClearSessionConversation(arg1:string):Promise<void>;
 
+export function CommitApprovedBranchHunks(arg1:string,arg2:string,arg3:Array<diff.FileDiff>,arg4:string):Promise<string>;
+
+export function CommitApprovedHunks(arg1:string,arg2:Array<diff.FileDiff>,arg3:string):Promise<string>;
+
+export function CompactSessionConversation(arg1:string):Promise<void>;
+
 export function CompleteOnboarding():Promise<void>;
 
 export function CreateAgentSession(arg1:string):Promise<main.AgentSessionInfo>;
@@ -36,14 +46,24 @@ export function CreateTriageSession(arg1:string):Promise<main.AgentSessionInfo>;
 
 export function DeleteAgentSession(arg1:string):Promise<void>;
 
+export function DiscardBranchHunks(arg1:string,arg2:string,arg3:string,arg4:Array<diff.HunkSelection>):Promise<void>;
+
+export function DiscardHunks(arg1:string,arg2:string,arg3:Array<diff.HunkSelection>):Promise<void>;
+
 export function ExecuteLinearTicketWithBoatmanMode(arg1:string,arg2:string,arg3:string):Promise<void>;
 
 export function ExecuteTriageTicket(arg1:string,arg2:string):Promise<main.AgentSessionInfo>;
 
+export function ExportIncidentPostmortem(arg1:string):Promise<string>;
+
+export function ExportSession(arg1:string,arg2:string):Promise<string>;
+
 export function ExportTriagePDF(arg1:string):Promise<string>;
 
 export function FetchLinearTicketsForBoatmanMode(arg1:string,arg2:string):Promise<Array<Record<string, any>>>;
 
+export function ForkSession(arg1:string,arg2:string,arg3:boolean):Promise<main.AgentSessionInfo>;
+
 export function GCloudGetAvailableProjects():Promise<Array<string>>;
 
 export function GCloudLogin():Promise<void>;
@@ -76,6 +96,8 @@ export function GetBrainDirs():Promise<Array<string>>;
 
 export function GetClaudeCLIVersion():Promise<string>;
 
+export function GetFirefighterIncidents():Promise<Array<agent.Incident>>;
+
 export function GetFirefighterMonitorStatus(arg1:string):Promise<Record<string, any>>;
 
 export function GetGCloudAuthInfo():Promise<Record<string, any>>;
@@ -84,6 +106,8 @@ export function GetGitDiff(arg1:string,arg2:string):Promise<string>;
 
 export function GetGitStatus(arg1:string):Promise<main.GitStatus>;
 
+export function GetImportedSessionDiff(arg1:string):Promise<string>;
+
 export function GetKeepCompletedAgents():Promise<boolean>;
 
 export function GetMCPPresets():Promise<Array<mcp.Server>>;
@@ -102,12 +126,20 @@ export function GetMaxTotalSessions():Promise<number>;
 
 export function GetOktaAccessToken(arg1:string,arg2:string,arg3:string):Promise<string>;
 
+export function GetPendingAgentActions(arg1:string):Promise<Array<agent.PendingAction>>;
+
 export function GetPreferences():Promise<config.UserPreferences>;
 
 export function GetProject(arg1:string):Promise<project.Project>;
 
+export function GetProjectAllowedTools(arg1:string):Promise<Array<string>>;
+
+export function GetProjectApprovalMode(arg1:string):Promise<string>;
+
 export function GetRecentProjects(arg1:number):Promise<Array<project.Project>>;
 
+export function GetSessionContextUsage(arg1:string):Promise<agent.ContextUsage>;
+
 export function GetSessionInfo(arg1:string):Promise<Record<string, any>>;
 
 export function GetSessionStats():Promise<Record<string, any>>;
@@ -128,6 +160,8 @@ export function HandleBoatmanModeEvent(arg1:string,arg2:string,arg3:Record<strin
 
 export function HandleTriageEvent(arg1:string,arg2:string,arg3:Record<string, any>):Promise<void>;
 
+export function ImportSessionBundle():Promise<main.AgentSessionInfo>;
+
 export function InvestigateLinearTicket(arg1:string,arg2:string):Promise<void>;
 
 export function InvestigateSlackAlert(arg1:string,arg2:string,arg3:string):Promise<void>;
@@ -172,8 +206,12 @@ export function RemoveProject(arg1:string):Promise<void>;
 
 export function RemoveSessionTag(arg1:string,arg2:string):Promise<void>;
 
+export function RequestDiffChanges(arg1:string,arg2:Array<diff.FileDiff>):Promise<Array<diff.RequestedChange>>;
+
 export function ResumeBoatmanModeExecution(arg1:string):Promise<void>;
 
+export function ResumeInterruptedSession(arg1:string):Promise<void>;
+
 export function RunHarness(arg1:string,arg2:harnessui.RunRequest):Promise<void>;
 
 export function ScaffoldHarness(arg1:harnessui.ScaffoldRequest):Promise<harnessui.ScaffoldResponse>;
@@ -200,6 +238,8 @@ export function SetSessionReasoningEffort(arg1:string,arg2:string):Promise<void>
 
 export function SetSessionSystemPrompt(arg1:string,arg2:string):Promise<void>;
 
+export function StageHunks(arg1:string,arg2:string,arg3:Array<diff.HunkSelection>):Promise<void>;
+
 export function StartAgentSession(arg1:string):Promise<void>;
 
 export function StartFirefighterMonitoring(arg1:string):Promise<void>;
@@ -214,6 +254,8 @@ export function StreamBoatmanModeExecution(arg1:string,arg2:string,arg3:string,a
 
 export function StreamTriageExecution(arg1:string,arg2:triage.TriageOptions,arg3:string,arg4:string):Promise<void>;
 
+export function UnstageHunks(arg1:string,arg2:string,arg3:Array<diff.HunkSelection>):Promise<void>;
+
 export function UpdateMCPServer(arg1:mcp.Server):Promise<void>;
 
 export function ValidateBrain(arg1:string):Promise<services.BrainValidationResult>;
@@ -6,6 +6,10 @@ export function AddMCPServer(arg1) {
   return window['go']['main']['App']['AddMCPServer'](arg1);
 }
 
+export function AddProjectAllowedTool(arg1, arg2) {
+  return window['go']['main']['App']['AddProjectAllowedTool'](arg1, arg2);
+}
+
 export function AddSessionTag(arg1, arg2) {
   return window['go']['main']['App']['AddSessionTag'](arg1, arg2);
 }
@@ -14,6 +18,10 @@ export function ApproveAgentAction(arg1, arg2) {
   return window['go']['main']['App']['ApproveAgentAction'](arg1, arg2);
 }
 
+export function ApproveAgentActionAlways(arg1, arg2) {
+  return window['go']['main']['App']['ApproveAgentActionAlways'](arg1, arg2);
+}
+
 export function AutoDistillBrains() {
   return window['go']['main']['App']['AutoDistillBrains']();
 }
@@ -30,6 +38,18 @@ export function ClearSessionConversation(arg1) {
   return window['go']['main']['App']['ClearSessionConversation'](arg1);
 }
 
+export function CommitApprovedBranchHunks(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['CommitApprovedBranchHunks'](arg1, arg2, arg3, arg4);
+}
+
+export function CommitApprovedHunks(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CommitApprovedHunks'](arg1, arg2, arg3);
+}
+
+export function CompactSessionConversation(arg1) {
+  return window['go']['main']['App']['CompactSessionConversation'](arg1);
+}
+
 export function CompleteOnboarding() {
   return window['go']['main']['App']['CompleteOnboarding']();
 }
@@ -54,6 +74,14 @@ export function DeleteAgentSession(arg1) {
   return window['go']['main']['App']['DeleteAgentSession'](arg1);
 }
 
+export function DiscardBranchHunks(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['DiscardBranchHunks'](arg1, arg2, arg3, arg4);
+}
+
+export function DiscardHunks(arg1, arg2, arg3) {
+  return window['go']['main']['App']['DiscardHunks'](arg1, arg2, arg3);
+}
+
 export function ExecuteLinearTicketWithBoatmanMode(arg1, arg2, arg3) {
   return window['go']['main']['App']['ExecuteLinearTicketWithBoatmanMode'](arg1, arg2, arg3);
 }
@@ -62,6 +90,14 @@ export function ExecuteTriageTicket(arg1, arg2) {
   return window['go']['main']['App']['ExecuteTriageTicket'](arg1, arg2);
 }
 
+export function ExportIncidentPostmortem(arg1) {
+  return window['go']['main']['App']['ExportIncidentPostmortem'](arg1);
+}
+
+export function ExportSession(arg1, arg2) {
+  return window['go']['main']['App']['ExportSession'](arg1, arg2);
+}
+
 export function ExportTriagePDF(arg1) {
   return window['go']['main']['App']['ExportTriagePDF'](arg1);
 }
@@ -70,6 +106,10 @@ export function FetchLinearTicketsForBoatmanMode(arg1, arg2) {
   return window['go']['main']['App']['FetchLinearTicketsForBoatmanMode'](arg1, arg2);
 }
 
+export function ForkSession(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ForkSession'](arg1, arg2, arg3);
+}
+
 export function GCloudGetAvailableProjects() {
   return window['go']['main']['App']['GCloudGetAvailableProjects']();
 }
@@ -134,6 +174,10 @@ export function GetClaudeCLIVersion() {
   return window['go']['main']['App']['GetClaudeCLIVersion']();
 }
 
+export function GetFirefighterIncidents() {
+  return window['go']['main']['App']['GetFirefighterIncidents']();
+}
+
 export function GetFirefighterMonitorStatus(arg1) {
   return window['go']['main']['App']['GetFirefighterMonitorStatus'](arg1);
 }
@@ -150,6 +194,10 @@ export function GetGitStatus(arg1) {
   return window['go']['main']['App']['GetGitStatus'](arg1);
 }
 
+export function GetImportedSessionDiff(arg1) {
+  return window['go']['main']['App']['GetImportedSessionDiff'](arg1);
+}
+
 export function GetKeepCompletedAgents() {
   return window['go']['main']['App']['GetKeepCompletedAgents']();
 }
@@ -186,6 +234,10 @@ export function GetOktaAccessToken(arg1, arg2, arg3) {
   return window['go']['main']['App']['GetOktaAccessToken'](arg1, arg2, arg3);
 }
 
+export function GetPendingAgentActions(arg1) {
+  return window['go']['main']['App']['GetPendingAgentActions'](arg1);
+}
+
 export function GetPreferences() {
   return window['go']['main']['App']['GetPreferences']();
 }
@@ -194,10 +246,22 @@ export function GetProject(arg1) {
   return window['go']['main']['App']['GetProject'](arg1);
 }
 
+export function GetProjectAllowedTools(arg1) {
+  return window['go']['main']['App']['GetProjectAllowedTools'](arg1);
+}
+
+export function GetProjectApprovalMode(arg1) {
+  return window['go']['main']['App']['GetProjectApprovalMode'](arg1);
+}
+
 export function GetRecentProjects(arg1) {
   return window['go']['main']['App']['GetRecentProjects'](arg1);
 }
 
+export function GetSessionContextUsage(arg1) {
+  return window['go']['main']['App']['GetSessionContextUsage'](arg1);
+}
+
 export function GetSessionInfo(arg1) {
   return window['go']['main']['App']['GetSessionInfo'](arg1);
 }
@@ -238,6 +302,10 @@ export function HandleTriageEvent(arg1, arg2, arg3) {
   return window['go']['main']['App']['HandleTriageEvent'](arg1, arg2, arg3);
 }
 
+export function ImportSessionBundle() {
+  return window['go']['main']['App']['ImportSessionBundle']();
+}
+
 export function InvestigateLinearTicket(arg1, arg2) {
   return window['go']['main']['App']['InvestigateLinearTicket'](arg1, arg2);
 }
@@ -326,10 +394,18 @@ export function RemoveSessionTag(arg1, arg2) {
   return window['go']['main']['App']['RemoveSessionTag'](arg1, arg2);
 }
 
+export function RequestDiffChanges(arg1, arg2) {
+  return window['go']['main']['App']['RequestDiffChanges'](arg1, arg2);
+}
+
 export function ResumeBoatmanModeExecution(arg1) {
   return window['go']['main']['App']['ResumeBoatmanModeExecution'](arg1);
 }
 
+export function ResumeInterruptedSession(arg1) {
+  return window['go']['main']['App']['ResumeInterruptedSession'](arg1);
+}
+
 export function RunHarness(arg1, arg2) {
   return window['go']['main']['App']['RunHarness'](arg1, arg2);
 }
@@ -382,6 +458,10 @@ export function SetSessionSystemPrompt(arg1, arg2) {
   return window['go']['main']['App']['SetSessionSystemPrompt'](arg1, arg2);
 }
 
+export function StageHunks(arg1, arg2, arg3) {
+  return window['go']['main']['App']['StageHunks'](arg1, arg2, arg3);
+}
+
 export function StartAgentSession(arg1) {
   return window['go']['main']['App']['StartAgentSession'](arg1);
 }
@@ -410,6 +490,10 @@ export function StreamTriageExecution(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['StreamTriageExecution'](arg1, arg2, arg3, arg4);
 }
 
+export function UnstageHunks(arg1, arg2, arg3) {
+  return window['go']['main']['App']['UnstageHunks'](arg1, arg2, arg3);
+}
+
 export function UpdateMCPServer(arg1) {
   return window['go']['main']['App']['UpdateMCPServer'](arg1);
 }
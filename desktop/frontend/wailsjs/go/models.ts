@@ -41,6 +41,60 @@ export namespace agent {
 		    return a;
 		}
 	}
+	export class Alert {
+	    id: string;
+	    source: string;
+	    severity: string;
+	    title: string;
+	    description: string;
+	    // Go type: time
+	    firstSeen: any;
+	    // Go type: time
+	    lastSeen: any;
+	    count: number;
+	    url: string;
+	    linearId?: string;
+	    slackThread?: string;
+	    fingerprint: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Alert(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.source = source["source"];
+	        this.severity = source["severity"];
+	        this.title = source["title"];
+	        this.description = source["description"];
+	        this.firstSeen = this.convertValues(source["firstSeen"], null);
+	        this.lastSeen = this.convertValues(source["lastSeen"], null);
+	        this.count = source["count"];
+	        this.url = source["url"];
+	        this.linearId = source["linearId"];
+	        this.slackThread = source["slackThread"];
+	        this.fingerprint = source["fingerprint"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class CostInfo {
 	    inputTokens: number;
 	    outputTokens: number;
@@ -89,11 +143,30 @@ export namespace agent {
 	        this.input = source["input"];
 	    }
 	}
+	export class CompactionInfo {
+	    summary: string;
+	    tokensBefore: number;
+	    method: string;
+	    automatic: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CompactionInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.summary = source["summary"];
+	        this.tokensBefore = source["tokensBefore"];
+	        this.method = source["method"];
+	        this.automatic = source["automatic"];
+	    }
+	}
 	export class MessageMetadata {
 	    toolUse?: ToolUse;
 	    toolResult?: ToolResult;
 	    costInfo?: CostInfo;
 	    agent?: AgentInfo;
+	    compaction?: CompactionInfo;
 	
 	    static createFrom(source: any = {}) {
 	        return new MessageMetadata(source);
@@ -105,8 +178,165 @@ export namespace agent {
 	        this.toolResult = this.convertValues(source["toolResult"], ToolResult);
 	        this.costInfo = this.convertValues(source["costInfo"], CostInfo);
 	        this.agent = this.convertValues(source["agent"], AgentInfo);
+	        this.compaction = this.convertValues(source["compaction"], CompactionInfo);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TimelineEntry {
+	    // Go type: time
+	    time: any;
+	    kind: string;
+	    status?: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TimelineEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = this.convertValues(source["time"], null);
+	        this.kind = source["kind"];
+	        this.status = source["status"];
+	        this.message = source["message"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Investigation {
+	    id: string;
+	    alertId: string;
+	    linearIssueId?: string;
+	    status: string;
+	    worktreePath?: string;
+	    branchName?: string;
+	    prNumber?: string;
+	    // Go type: time
+	    startedAt: any;
+	    // Go type: time
+	    completedAt?: any;
+	    summary?: string;
+	    fixDescription?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Investigation(source);
 	    }
 	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.alertId = source["alertId"];
+	        this.linearIssueId = source["linearIssueId"];
+	        this.status = source["status"];
+	        this.worktreePath = source["worktreePath"];
+	        this.branchName = source["branchName"];
+	        this.prNumber = source["prNumber"];
+	        this.startedAt = this.convertValues(source["startedAt"], null);
+	        this.completedAt = this.convertValues(source["completedAt"], null);
+	        this.summary = source["summary"];
+	        this.fixDescription = source["fixDescription"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Incident {
+	    id: string;
+	    title: string;
+	    description: string;
+	    source: string;
+	    severity: string;
+	    status: string;
+	    alerts: Alert[];
+	    timeline: TimelineEntry[];
+	    investigation?: Investigation;
+	    linearId?: string;
+	    slackThread?: string;
+	    url?: string;
+	    prNumber?: string;
+	    sessionIds: string[];
+	    messageIds: string[];
+	    // Go type: time
+	    firstSeen: any;
+	    // Go type: time
+	    lastUpdated: any;
+	    // Go type: time
+	    resolvedAt?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Incident(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.title = source["title"];
+	        this.description = source["description"];
+	        this.source = source["source"];
+	        this.severity = source["severity"];
+	        this.status = source["status"];
+	        this.alerts = this.convertValues(source["alerts"], Alert);
+	        this.timeline = this.convertValues(source["timeline"], TimelineEntry);
+	        this.investigation = this.convertValues(source["investigation"], Investigation);
+	        this.linearId = source["linearId"];
+	        this.slackThread = source["slackThread"];
+	        this.url = source["url"];
+	        this.prNumber = source["prNumber"];
+	        this.sessionIds = source["sessionIds"];
+	        this.messageIds = source["messageIds"];
+	        this.firstSeen = this.convertValues(source["firstSeen"], null);
+	        this.lastUpdated = this.convertValues(source["lastUpdated"], null);
+	        this.resolvedAt = this.convertValues(source["resolvedAt"], null);
+	    }
+
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
 		    if (!a) {
 		        return a;
@@ -164,7 +394,131 @@ export namespace agent {
 		    return a;
 		}
 	}
+	export class ContextUsage {
+	    sessionId: string;
+	    tokens: number;
+	    window: number;
+	    percent: number;
+	    threshold: number;
+	    compactions: number;
+	    compacting: boolean;
 	
+	    static createFrom(source: any = {}) {
+	        return new ContextUsage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sessionId = source["sessionId"];
+	        this.tokens = source["tokens"];
+	        this.window = source["window"];
+	        this.percent = source["percent"];
+	        this.threshold = source["threshold"];
+	        this.compactions = source["compactions"];
+	        this.compacting = source["compacting"];
+	    }
+	}
+	
+	export class PendingAction {
+	    id: string;
+	    toolName: string;
+	    toolUseId?: string;
+	    input: number[];
+	    summary: string;
+	    rule: string;
+	    status: string;
+	    // Go type: time
+	    requestedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new PendingAction(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.toolName = source["toolName"];
+	        this.toolUseId = source["toolUseId"];
+	        this.input = source["input"];
+	        this.summary = source["summary"];
+	        this.rule = source["rule"];
+	        this.status = source["status"];
+	        this.requestedAt = this.convertValues(source["requestedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SearchHighlight {
+	    start: number;
+	    end: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchHighlight(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.start = source["start"];
+	        this.end = source["end"];
+	    }
+	}
+	export class SearchSnippet {
+	    messageId: string;
+	    field: string;
+	    archived: boolean;
+	    // Go type: time
+	    timestamp: any;
+	    text: string;
+	    highlights: SearchHighlight[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchSnippet(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.messageId = source["messageId"];
+	        this.field = source["field"];
+	        this.archived = source["archived"];
+	        this.timestamp = this.convertValues(source["timestamp"], null);
+	        this.text = source["text"];
+	        this.highlights = this.convertValues(source["highlights"], SearchHighlight);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class Task {
 	    id: string;
 	    subject: string;
@@ -229,6 +583,7 @@ export namespace config {
 	    autoCleanupSessions: boolean;
 	    maxAgentsPerSession: number;
 	    keepCompletedAgents: boolean;
+	    autoCompactThreshold: number;
 	    datadogAPIKey?: string;
 	    datadogAppKey?: string;
 	    datadogSite?: string;
@@ -262,6 +617,7 @@ export namespace config {
 	        this.autoCleanupSessions = source["autoCleanupSessions"];
 	        this.maxAgentsPerSession = source["maxAgentsPerSession"];
 	        this.keepCompletedAgents = source["keepCompletedAgents"];
+	        this.autoCompactThreshold = source["autoCompactThreshold"];
 	        this.datadogAPIKey = source["datadogAPIKey"];
 	        this.datadogAppKey = source["datadogAppKey"];
 	        this.datadogSite = source["datadogSite"];
@@ -323,6 +679,7 @@ export namespace diff {
 	    content: string;
 	    oldNum?: number;
 	    newNum?: number;
+	    noNewline?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new Line(source);
@@ -334,6 +691,7 @@ export namespace diff {
 	        this.content = source["content"];
 	        this.oldNum = source["oldNum"];
 	        this.newNum = source["newNum"];
+	        this.noNewline = source["noNewline"];
 	    }
 	}
 	export class Hunk {
@@ -385,6 +743,7 @@ export namespace diff {
 	    isNew: boolean;
 	    isDelete: boolean;
 	    isBinary: boolean;
+	    mode?: string;
 	    approved?: boolean;
 	    comments?: DiffComment[];
 	
@@ -400,6 +759,7 @@ export namespace diff {
 	        this.isNew = source["isNew"];
 	        this.isDelete = source["isDelete"];
 	        this.isBinary = source["isBinary"];
+	        this.mode = source["mode"];
 	        this.approved = source["approved"];
 	        this.comments = this.convertValues(source["comments"], DiffComment);
 	    }
@@ -423,7 +783,59 @@ export namespace diff {
 		}
 	}
 	
+	export class HunkSelection {
+	    hunkId: string;
+	    start?: number;
+	    end?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HunkSelection(source);
+	    }
 	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hunkId = source["hunkId"];
+	        this.start = source["start"];
+	        this.end = source["end"];
+	    }
+	}
+	
+	export class RequestedChange {
+	    id: string;
+	    commentId?: string;
+	    hunkId?: string;
+	    issue: review.Issue;
+	
+	    static createFrom(source: any = {}) {
+	        return new RequestedChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.commentId = source["commentId"];
+	        this.hunkId = source["hunkId"];
+	        this.issue = this.convertValues(source["issue"], review.Issue);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class SideBySideLine {
 	    leftNum?: number;
 	    leftContent?: string;
@@ -544,6 +956,9 @@ export namespace main {
 	    model?: string;
 	    reasoningEffort?: string;
 	    mode?: string;
+	    parentId?: string;
+	    forkedFrom?: string;
+	    readOnly?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new AgentSessionInfo(source);
@@ -560,6 +975,9 @@ export namespace main {
 	        this.model = source["model"];
 	        this.reasoningEffort = source["reasoningEffort"];
 	        this.mode = source["mode"];
+	        this.parentId = source["parentId"];
+	        this.forkedFrom = source["forkedFrom"];
+	        this.readOnly = source["readOnly"];
 	    }
 	}
 	export class GitStatus {
@@ -655,6 +1073,7 @@ export namespace main {
 	    score: number;
 	    matchReasons: string[];
 	    status: string;
+	    snippets: agent.SearchSnippet[];
 	
 	    static createFrom(source: any = {}) {
 	        return new SearchSessionsResponse(source);
@@ -672,7 +1091,26 @@ export namespace main {
 	        this.score = source["score"];
 	        this.matchReasons = source["matchReasons"];
 	        this.status = source["status"];
+	        this.snippets = this.convertValues(source["snippets"], agent.SearchSnippet);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 
 }
@@ -771,6 +1209,33 @@ export namespace project {
 
 }
 
+export namespace review {
+	
+	export class Issue {
+	    severity: string;
+	    file: string;
+	    line?: number;
+	    description: string;
+	    suggestion?: string;
+	    code?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Issue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.severity = source["severity"];
+	        this.file = source["file"];
+	        this.line = source["line"];
+	        this.description = source["description"];
+	        this.suggestion = source["suggestion"];
+	        this.code = source["code"];
+	    }
+	}
+
+}
+
 export namespace services {
 	
 	export class AutoDistillResult {
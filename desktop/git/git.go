@@ -116,6 +116,30 @@ func (r *Repository) GetDiffAgainstBase(baseBranch string) (string, error) {
 	return string(output), nil
 }
 
+// MergeBase returns the commit the current branch forked from baseBranch at
+func (r *Repository) MergeBase(baseBranch string) (string, error) {
+	if baseBranch == "" {
+		baseBranch = "main"
+	}
+	cmd := exec.Command("git", "merge-base", baseBranch, "HEAD")
+	cmd.Dir = r.path
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the merge-base with %s: %w", baseBranch, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetDiffSinceBase returns the diff of the current branch's changes relative
+// to its merge-base with baseBranch, committed or not
+func (r *Repository) GetDiffSinceBase(baseBranch string) (string, error) {
+	mergeBase, err := r.MergeBase(baseBranch)
+	if err != nil {
+		return "", err
+	}
+	return r.GetDiffSince(mergeBase)
+}
+
 // GetStagedDiff returns the diff for staged changes
 func (r *Repository) GetStagedDiff() (string, error) {
 	cmd := exec.Command("git", "diff", "--cached")
@@ -127,6 +151,41 @@ func (r *Repository) GetStagedDiff() (string, error) {
 	return string(output), nil
 }
 
+// Snapshot records the current state of tracked files without touching the
+// working tree or index, and returns a commit to diff against later. A clean
+// tree snapshots to HEAD.
+func (r *Repository) Snapshot() (string, error) {
+	cmd := exec.Command("git", "stash", "create")
+	cmd.Dir = r.path
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	if ref := strings.TrimSpace(string(output)); ref != "" {
+		return ref, nil
+	}
+
+	cmd = exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = r.path
+	output, err = cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetDiffSince returns the diff between a commit (such as one returned by
+// Snapshot) and the working tree
+func (r *Repository) GetDiffSince(ref string) (string, error) {
+	cmd := exec.Command("git", "diff", ref)
+	cmd.Dir = r.path
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
 // StageFile stages a file
 func (r *Repository) StageFile(filePath string) error {
 	cmd := exec.Command("git", "add", filePath)
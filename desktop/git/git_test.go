@@ -526,6 +526,49 @@ func TestGetStagedDiff(t *testing.T) {
 	}
 }
 
+func TestSnapshotAndGetDiffSince(t *testing.T) {
+	repoPath, cleanup := createTestRepo(t)
+	defer cleanup()
+
+	createFile(t, repoPath, "file.txt", "original\n")
+	commitChanges(t, repoPath, "Initial commit")
+
+	repo := NewRepository(repoPath)
+
+	// A clean tree snapshots to HEAD
+	clean, err := repo.Snapshot()
+	if err != nil || clean == "" {
+		t.Fatalf("Snapshot() = %q, error = %v", clean, err)
+	}
+
+	createFile(t, repoPath, "file.txt", "first edit\n")
+	snapshot, err := repo.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if snapshot == clean {
+		t.Error("Snapshot of a dirty tree should differ from HEAD")
+	}
+
+	// The snapshot must leave the working tree alone
+	status, _ := repo.GetStatus()
+	if len(status.Modified) != 1 {
+		t.Fatalf("Snapshot() changed the working tree: %+v", status)
+	}
+
+	createFile(t, repoPath, "file.txt", "second edit\n")
+	diff, err := repo.GetDiffSince(snapshot)
+	if err != nil {
+		t.Fatalf("GetDiffSince() error = %v", err)
+	}
+	if !strings.Contains(diff, "-first edit") || !strings.Contains(diff, "+second edit") {
+		t.Errorf("Diff should only show changes since the snapshot, got:\n%s", diff)
+	}
+	if strings.Contains(diff, "original") {
+		t.Error("Diff should not include changes made before the snapshot")
+	}
+}
+
 func TestStageFile(t *testing.T) {
 	repoPath, cleanup := createTestRepo(t)
 	defer cleanup()
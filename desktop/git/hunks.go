@@ -0,0 +1,237 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"boatman/diff"
+)
+
+// StageHunks stages the selected hunks or line ranges of a file's unstaged
+// changes. Selections refer to hunks of the current `git diff` of the file.
+func (r *Repository) StageHunks(filePath string, selections []diff.HunkSelection) error {
+	return r.applySelection(filePath, selections, []string{"diff"}, false, "--cached")
+}
+
+// UnstageHunks moves the selected hunks or line ranges of a file's staged
+// changes back out of the index. Selections refer to hunks of the current
+// `git diff --cached` of the file.
+func (r *Repository) UnstageHunks(filePath string, selections []diff.HunkSelection) error {
+	return r.applySelection(filePath, selections, []string{"diff", "--cached"}, true, "--cached", "-R")
+}
+
+// DiscardHunks reverts the selected hunks or line ranges of a file's unstaged
+// changes in the working tree
+func (r *Repository) DiscardHunks(filePath string, selections []diff.HunkSelection) error {
+	return r.applySelection(filePath, selections, []string{"diff"}, true, "-R")
+}
+
+// DiscardBranchHunks reverts the selected hunks or line ranges of the current
+// branch's changes in the working tree. Selections refer to hunks of the
+// branch's diff against its merge-base with baseBranch (see GetDiffSinceBase);
+// reverting a committed hunk leaves the revert uncommitted.
+func (r *Repository) DiscardBranchHunks(baseBranch, filePath string, selections []diff.HunkSelection) error {
+	mergeBase, err := r.MergeBase(baseBranch)
+	if err != nil {
+		return err
+	}
+	return r.applySelection(filePath, selections, []string{"diff", mergeBase}, true, "-R")
+}
+
+// CommitApprovedHunks commits the approved files and hunks of diffs, which
+// must describe the repository's unstaged changes, and nothing else. The index
+// must be clean so the commit cannot pick up unrelated staged work; if the
+// commit fails, the index is reset. It returns the new commit's hash.
+func (r *Repository) CommitApprovedHunks(diffs []diff.FileDiff, message string) (string, error) {
+	top, err := r.topLevel()
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := runGit(top, "", "diff", "--cached", "--quiet"); err != nil {
+		return "", fmt.Errorf("the index already has staged changes; commit or unstage them first")
+	}
+
+	staged, err := r.stageApproved(diffs, []string{"diff"})
+	if err != nil {
+		r.resetIndex(top, staged)
+		return "", err
+	}
+	if len(staged) == 0 {
+		return "", fmt.Errorf("nothing to commit: no files or hunks are approved")
+	}
+
+	hash, err := r.commitIndex(top, message)
+	if err != nil {
+		r.resetIndex(top, staged)
+		return "", err
+	}
+	return hash, nil
+}
+
+// CommitApprovedBranchHunks commits the approved files and hunks of diffs as a
+// new commit on the current branch, as used for agent worktrees whose changes
+// are already committed. diffs must describe the branch's changes against its
+// merge-base with baseBranch (see GetDiffSinceBase). The new commit holds the
+// merge-base plus the approved changes: everything else is taken out of the
+// branch and left uncommitted in the working tree. The index must be clean; if
+// the commit fails, it is reset. It returns the new commit's hash.
+func (r *Repository) CommitApprovedBranchHunks(baseBranch string, diffs []diff.FileDiff, message string) (string, error) {
+	top, err := r.topLevel()
+	if err != nil {
+		return "", err
+	}
+	mergeBase, err := r.MergeBase(baseBranch)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := runGit(top, "", "diff", "--cached", "--quiet"); err != nil {
+		return "", fmt.Errorf("the index already has staged changes; commit or unstage them first")
+	}
+
+	// Stage the approved changes on top of the merge-base; the working tree
+	// keeps every change
+	if _, err := runGit(top, "", "read-tree", mergeBase); err != nil {
+		return "", fmt.Errorf("failed to read the merge-base into the index: %w", err)
+	}
+	staged, err := r.stageApproved(diffs, []string{"diff", mergeBase})
+	if err == nil && len(staged) == 0 {
+		err = fmt.Errorf("nothing to commit: no files or hunks are approved")
+	}
+	if err == nil {
+		if _, diffErr := runGit(top, "", "diff", "--cached", "--quiet"); diffErr == nil {
+			err = fmt.Errorf("nothing to commit: the branch holds exactly the approved changes")
+		}
+	}
+	if err != nil {
+		runGit(top, "", "reset", "-q")
+		return "", err
+	}
+
+	hash, err := r.commitIndex(top, message)
+	if err != nil {
+		runGit(top, "", "reset", "-q")
+		return "", err
+	}
+	return hash, nil
+}
+
+// stageApproved stages the approved files and hunks of diffs, whose hunks
+// come from `git <diffArgs>`. It returns the paths staged, including those
+// staged before an error.
+func (r *Repository) stageApproved(diffs []diff.FileDiff, diffArgs []string) ([]string, error) {
+	var staged []string
+	for _, fd := range diffs {
+		var selections []diff.HunkSelection
+		for _, h := range fd.Hunks {
+			if fd.Approved || h.Approved {
+				selections = append(selections, diff.HunkSelection{HunkID: h.ID})
+			}
+		}
+		if len(selections) == 0 {
+			continue
+		}
+
+		path := fd.NewPath
+		if fd.IsDelete {
+			path = fd.OldPath
+		}
+		if err := r.applySelection(path, selections, diffArgs, false, "--cached"); err != nil {
+			return staged, err
+		}
+		staged = append(staged, path)
+	}
+	return staged, nil
+}
+
+// commitIndex commits the index and returns the new commit's hash
+func (r *Repository) commitIndex(top, message string) (string, error) {
+	if _, err := runGit(top, "", "commit", "-m", message); err != nil {
+		return "", fmt.Errorf("failed to commit approved hunks: %w", err)
+	}
+
+	hash, err := runGit(top, "", "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(hash), nil
+}
+
+// applySelection builds a patch of the selected changes from the file's
+// current diff and applies it with `git apply`. Paths in diffs are relative to
+// the top of the working tree, so everything runs from there; this also holds
+// for linked worktrees, whose index is their own.
+func (r *Repository) applySelection(filePath string, selections []diff.HunkSelection, diffArgs []string, reverse bool, applyArgs ...string) error {
+	if len(selections) == 0 {
+		return fmt.Errorf("no hunks selected")
+	}
+
+	top, err := r.topLevel()
+	if err != nil {
+		return err
+	}
+
+	// Pin the output format so user diff settings cannot break the patch
+	args := append(append([]string{}, diffArgs...), "--no-color", "--no-ext-diff", "--src-prefix=a/", "--dst-prefix=b/", "--", filePath)
+	output, err := runGit(top, "", args...)
+	if err != nil {
+		return fmt.Errorf("failed to diff %s: %w", filePath, err)
+	}
+	fileDiffs, err := diff.ParseUnifiedDiff(output)
+	if err != nil {
+		return err
+	}
+	if len(fileDiffs) != 1 {
+		return fmt.Errorf("no changes to %s; reload the diff", filePath)
+	}
+
+	patch, err := diff.BuildPatch(fileDiffs[0], selections, reverse)
+	if err != nil {
+		return fmt.Errorf("%w; reload the diff", err)
+	}
+
+	args = append([]string{"apply", "--whitespace=nowarn"}, applyArgs...)
+	if _, err := runGit(top, patch, append(args, "-")...); err != nil {
+		return fmt.Errorf("failed to apply patch to %s: %w", filePath, err)
+	}
+	return nil
+}
+
+// resetIndex restores the index entries of paths to HEAD
+func (r *Repository) resetIndex(top string, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	runGit(top, "", append([]string{"reset", "-q", "--"}, paths...)...)
+}
+
+// topLevel returns the root of the working tree containing the repository path
+func (r *Repository) topLevel() (string, error) {
+	output, err := runGit(r.path, "", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// runGit runs git in dir with stdin as input, and includes stderr in errors
+func runGit(dir, stdin string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return string(output), nil
+}
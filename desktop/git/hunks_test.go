@@ -0,0 +1,321 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"boatman/diff"
+)
+
+const hunksOriginal = "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\ntwelve\n"
+
+// setupHunkRepo commits a twelve-line file and changes it in two separate hunks
+func setupHunkRepo(t *testing.T) (string, func()) {
+	t.Helper()
+	repoPath, cleanup := createTestRepo(t)
+	createFile(t, repoPath, "list.txt", hunksOriginal)
+	commitChanges(t, repoPath, "Initial commit")
+
+	changed := strings.Replace(hunksOriginal, "two\n", "TWO\n", 1)
+	changed = strings.Replace(changed, "eleven\n", "eleven\neleven and a half\n", 1)
+	createFile(t, repoPath, "list.txt", changed)
+	return repoPath, cleanup
+}
+
+func fileHunks(t *testing.T, repo *Repository, staged bool) diff.FileDiff {
+	t.Helper()
+	var text string
+	var err error
+	if staged {
+		text, err = repo.GetStagedDiff()
+	} else {
+		text, err = repo.GetDiff("")
+	}
+	if err != nil {
+		t.Fatalf("Failed to get diff: %v", err)
+	}
+	diffs, err := diff.ParseUnifiedDiff(text)
+	if err != nil || len(diffs) != 1 {
+		t.Fatalf("Expected one file diff, got %d (err %v):\n%s", len(diffs), err, text)
+	}
+	return diffs[0]
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestStageAndUnstageHunks(t *testing.T) {
+	repoPath, cleanup := setupHunkRepo(t)
+	defer cleanup()
+
+	repo := NewRepository(repoPath)
+	fd := fileHunks(t, repo, false)
+	if len(fd.Hunks) != 2 {
+		t.Fatalf("Expected 2 hunks, got %d", len(fd.Hunks))
+	}
+
+	if err := repo.StageHunks("list.txt", []diff.HunkSelection{{HunkID: fd.Hunks[1].ID}}); err != nil {
+		t.Fatalf("StageHunks() error = %v", err)
+	}
+
+	staged, _ := repo.GetStagedDiff()
+	if !strings.Contains(staged, "+eleven and a half") || strings.Contains(staged, "TWO") {
+		t.Errorf("Only the second hunk should be staged:\n%s", staged)
+	}
+	unstaged, _ := repo.GetDiff("")
+	if !strings.Contains(unstaged, "+TWO") || strings.Contains(unstaged, "eleven and a half") {
+		t.Errorf("Only the first hunk should remain unstaged:\n%s", unstaged)
+	}
+
+	stagedFd := fileHunks(t, repo, true)
+	if err := repo.UnstageHunks("list.txt", []diff.HunkSelection{{HunkID: stagedFd.Hunks[0].ID}}); err != nil {
+		t.Fatalf("UnstageHunks() error = %v", err)
+	}
+	if staged, _ := repo.GetStagedDiff(); staged != "" {
+		t.Errorf("Expected nothing staged, got:\n%s", staged)
+	}
+}
+
+func TestStageHunks_LineRange(t *testing.T) {
+	repoPath, cleanup := setupHunkRepo(t)
+	defer cleanup()
+
+	repo := NewRepository(repoPath)
+	fd := fileHunks(t, repo, false)
+
+	// Stage just the deletion of "two" from the first hunk
+	var deletion int
+	for i, line := range fd.Hunks[0].Lines {
+		if line.Type == diff.LineTypeDeletion {
+			deletion = i
+		}
+	}
+	sel := diff.HunkSelection{HunkID: fd.Hunks[0].ID, Start: deletion, End: deletion + 1}
+	if err := repo.StageHunks("list.txt", []diff.HunkSelection{sel}); err != nil {
+		t.Fatalf("StageHunks() error = %v", err)
+	}
+
+	staged, _ := repo.GetStagedDiff()
+	if !strings.Contains(staged, "-two") || strings.Contains(staged, "+TWO") {
+		t.Errorf("Only the deleted line should be staged:\n%s", staged)
+	}
+}
+
+func TestDiscardHunks(t *testing.T) {
+	repoPath, cleanup := setupHunkRepo(t)
+	defer cleanup()
+
+	repo := NewRepository(repoPath)
+	fd := fileHunks(t, repo, false)
+
+	if err := repo.DiscardHunks("list.txt", []diff.HunkSelection{{HunkID: fd.Hunks[0].ID}}); err != nil {
+		t.Fatalf("DiscardHunks() error = %v", err)
+	}
+
+	content := readFile(t, filepath.Join(repoPath, "list.txt"))
+	if strings.Contains(content, "TWO") || !strings.Contains(content, "two\n") {
+		t.Errorf("First hunk should be discarded:\n%s", content)
+	}
+	if !strings.Contains(content, "eleven and a half") {
+		t.Errorf("Second hunk should be kept:\n%s", content)
+	}
+
+	// A stale hunk ID is rejected
+	if err := repo.DiscardHunks("list.txt", []diff.HunkSelection{{HunkID: fd.Hunks[0].ID}}); err == nil {
+		t.Error("Expected an error for a hunk that no longer exists")
+	}
+}
+
+func TestDiscardHunks_ChangedSinceLoad(t *testing.T) {
+	repoPath, cleanup := setupHunkRepo(t)
+	defer cleanup()
+
+	repo := NewRepository(repoPath)
+	fd := fileHunks(t, repo, false)
+
+	// Edit inside the first hunk after the diff was loaded; its line numbers
+	// stay the same but the selection no longer matches what was shown
+	path := filepath.Join(repoPath, "list.txt")
+	edited := strings.Replace(readFile(t, path), "TWO\n", "TWO\nthree and a bit\n", 1)
+	createFile(t, repoPath, "list.txt", edited)
+
+	err := repo.DiscardHunks("list.txt", []diff.HunkSelection{{HunkID: fd.Hunks[0].ID}})
+	if err == nil || !strings.Contains(err.Error(), "reload the diff") {
+		t.Fatalf("Expected a reload error for a changed hunk, got %v", err)
+	}
+	if content := readFile(t, path); content != edited {
+		t.Errorf("File should be untouched:\n%s", content)
+	}
+}
+
+func TestCommitApprovedHunks(t *testing.T) {
+	repoPath, cleanup := setupHunkRepo(t)
+	defer cleanup()
+
+	repo := NewRepository(repoPath)
+	fd := fileHunks(t, repo, false)
+	fd.Hunks[0].Approved = true
+
+	hash, err := repo.CommitApprovedHunks([]diff.FileDiff{fd}, "Accept the first change")
+	if err != nil {
+		t.Fatalf("CommitApprovedHunks() error = %v", err)
+	}
+	if hash == "" {
+		t.Error("Expected a commit hash")
+	}
+
+	commits, _ := repo.GetCommitHistory(1)
+	if len(commits) != 1 || commits[0].Message != "Accept the first change" {
+		t.Errorf("Unexpected history: %+v", commits)
+	}
+	show, _ := runGit(repoPath, "", "show", "HEAD")
+	if !strings.Contains(show, "+TWO") || strings.Contains(show, "eleven and a half") {
+		t.Errorf("Commit should only hold the approved hunk:\n%s", show)
+	}
+	if unstaged, _ := repo.GetDiff(""); !strings.Contains(unstaged, "+eleven and a half") {
+		t.Errorf("Unapproved hunk should remain in the working tree:\n%s", unstaged)
+	}
+
+	// Nothing approved
+	fd = fileHunks(t, repo, false)
+	if _, err := repo.CommitApprovedHunks([]diff.FileDiff{fd}, "Nothing"); err == nil {
+		t.Error("Expected an error when nothing is approved")
+	}
+
+	// Unrelated staged changes block the commit
+	createFile(t, repoPath, "other.txt", "other\n")
+	repo.StageFile("other.txt")
+	fd.Approved = true
+	if _, err := repo.CommitApprovedHunks([]diff.FileDiff{fd}, "Blocked"); err == nil {
+		t.Error("Expected an error when the index has staged changes")
+	}
+}
+
+func TestHunks_LinkedWorktree(t *testing.T) {
+	repoPath, cleanup := setupHunkRepo(t)
+	defer cleanup()
+	commitChanges(t, repoPath, "Main checkout changes")
+
+	// Agents work in linked worktrees, which have their own index
+	worktreePath := filepath.Join(t.TempDir(), "agent-worktree")
+	cmd := exec.Command("git", "worktree", "add", "-b", "agent", worktreePath)
+	cmd.Dir = repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to add worktree: %v: %s", err, output)
+	}
+
+	changed := strings.Replace(readFile(t, filepath.Join(worktreePath, "list.txt")), "five\n", "FIVE\n", 1)
+	changed = strings.Replace(changed, "twelve\n", "twelve\nthirteen\n", 1)
+	createFile(t, worktreePath, "list.txt", changed)
+
+	// Operate from a subdirectory to check paths resolve from the top level
+	os.Mkdir(filepath.Join(worktreePath, "sub"), 0755)
+	repo := NewRepository(filepath.Join(worktreePath, "sub"))
+	fd := fileHunks(t, repo, false)
+	if len(fd.Hunks) != 2 {
+		t.Fatalf("Expected 2 hunks, got %d", len(fd.Hunks))
+	}
+	fd.Hunks[1].Approved = true
+
+	if _, err := repo.CommitApprovedHunks([]diff.FileDiff{fd}, "Accept agent change"); err != nil {
+		t.Fatalf("CommitApprovedHunks() error = %v", err)
+	}
+
+	show, _ := runGit(worktreePath, "", "show", "HEAD")
+	if !strings.Contains(show, "+thirteen") || strings.Contains(show, "FIVE") {
+		t.Errorf("Worktree commit should only hold the approved hunk:\n%s", show)
+	}
+	if status, _ := NewRepository(repoPath).GetStatus(); len(status.Modified) != 0 {
+		t.Errorf("Main checkout should be untouched: %+v", status)
+	}
+}
+
+func TestBranchHunks(t *testing.T) {
+	repoPath, cleanup := createTestRepo(t)
+	defer cleanup()
+	createFile(t, repoPath, "list.txt", hunksOriginal)
+	commitChanges(t, repoPath, "Initial commit")
+	baseBranch, err := NewRepository(repoPath).GetCurrentBranch()
+	if err != nil {
+		t.Fatalf("GetCurrentBranch() error = %v", err)
+	}
+
+	// Boatman mode agents commit their work on a worktree branch
+	worktreePath := filepath.Join(t.TempDir(), "agent-worktree")
+	cmd := exec.Command("git", "worktree", "add", "-b", "agent", worktreePath)
+	cmd.Dir = repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to add worktree: %v: %s", err, output)
+	}
+	changed := strings.Replace(hunksOriginal, "two\n", "TWO\n", 1)
+	changed = strings.Replace(changed, "five\n", "FIVE\n", 1)
+	changed = strings.Replace(changed, "twelve\n", "twelve\nthirteen\n", 1)
+	createFile(t, worktreePath, "list.txt", changed)
+	commitChanges(t, worktreePath, "Agent changes")
+
+	repo := NewRepository(worktreePath)
+	branchHunks := func() diff.FileDiff {
+		t.Helper()
+		text, err := repo.GetDiffSinceBase(baseBranch)
+		if err != nil {
+			t.Fatalf("GetDiffSinceBase() error = %v", err)
+		}
+		diffs, err := diff.ParseUnifiedDiff(text)
+		if err != nil || len(diffs) != 1 {
+			t.Fatalf("Expected one file diff, got %d (err %v):\n%s", len(diffs), err, text)
+		}
+		return diffs[0]
+	}
+
+	fd := branchHunks()
+	if len(fd.Hunks) != 2 {
+		t.Fatalf("Expected 2 hunks, got %d", len(fd.Hunks))
+	}
+	fd.Hunks[0].Approved = true
+	if _, err := repo.CommitApprovedBranchHunks(baseBranch, []diff.FileDiff{fd}, "Keep the approved change"); err != nil {
+		t.Fatalf("CommitApprovedBranchHunks() error = %v", err)
+	}
+
+	parent, _ := runGit(worktreePath, "", "log", "-1", "--format=%s", "HEAD~1")
+	if strings.TrimSpace(parent) != "Agent changes" {
+		t.Errorf("Expected a new commit on the agent branch, parent is %q", parent)
+	}
+	branchDiff, _ := runGit(worktreePath, "", "diff", baseBranch+"...HEAD")
+	if !strings.Contains(branchDiff, "+FIVE") || strings.Contains(branchDiff, "thirteen") {
+		t.Errorf("Branch should hold only the approved hunk:\n%s", branchDiff)
+	}
+	if unstaged, _ := repo.GetDiff(""); !strings.Contains(unstaged, "+thirteen") {
+		t.Errorf("Unapproved hunk should remain in the working tree:\n%s", unstaged)
+	}
+
+	// The unapproved hunk is still part of the branch diff, and can be discarded
+	fd = branchHunks()
+	if len(fd.Hunks) != 2 {
+		t.Fatalf("Expected 2 hunks, got %d", len(fd.Hunks))
+	}
+	if err := repo.DiscardBranchHunks(baseBranch, "list.txt", []diff.HunkSelection{{HunkID: fd.Hunks[1].ID}}); err != nil {
+		t.Fatalf("DiscardBranchHunks() error = %v", err)
+	}
+	if status, _ := repo.GetStatus(); len(status.Modified) != 0 {
+		t.Errorf("Working tree should match the new commit: %+v", status)
+	}
+
+	// Nothing left to change
+	fd = branchHunks()
+	fd.Approved = true
+	if _, err := repo.CommitApprovedBranchHunks(baseBranch, []diff.FileDiff{fd}, "Empty"); err == nil {
+		t.Error("Expected an error when the branch already holds the approved changes")
+	}
+	if staged, _ := repo.GetStagedDiff(); staged != "" {
+		t.Errorf("Index should be reset after a failed commit:\n%s", staged)
+	}
+}
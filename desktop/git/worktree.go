@@ -0,0 +1,99 @@
+package git
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CreateWorktree checks out a new branch at HEAD in a worktree at path. The
+// repository's uncommitted changes, including untracked files, are copied
+// over so the worktree starts from the same files. If copying them fails,
+// the worktree and branch are removed again.
+func (r *Repository) CreateWorktree(path, branch string) (err error) {
+	snapshot, err := r.Snapshot()
+	if err != nil {
+		return fmt.Errorf("failed to snapshot working tree: %w", err)
+	}
+	head, err := runGit(r.path, "", "rev-parse", "HEAD")
+	if err != nil {
+		return err
+	}
+	untracked, err := runGit(r.path, "", "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return err
+	}
+
+	if _, err := runGit(r.path, "", "worktree", "add", "-b", branch, path, "HEAD"); err != nil {
+		return fmt.Errorf("failed to create worktree: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			r.RemoveWorktree(path)
+			r.DeleteBranch(branch)
+		}
+	}()
+
+	if snapshot != strings.TrimSpace(head) {
+		if _, err := runGit(path, "", "stash", "apply", snapshot); err != nil {
+			return fmt.Errorf("failed to copy uncommitted changes to worktree: %w", err)
+		}
+	}
+
+	top, err := r.topLevel()
+	if err != nil {
+		return err
+	}
+	for _, name := range strings.Split(untracked, "\x00") {
+		// Nested repositories (such as other worktrees) are listed as directories
+		if name == "" || strings.HasSuffix(name, "/") {
+			continue
+		}
+		if err := copyFile(filepath.Join(top, name), filepath.Join(path, name)); err != nil {
+			return fmt.Errorf("failed to copy %s to worktree: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// RemoveWorktree removes a worktree created by CreateWorktree, discarding any
+// changes in it. The branch is kept.
+func (r *Repository) RemoveWorktree(path string) error {
+	_, err := runGit(r.path, "", "worktree", "remove", "--force", path)
+	return err
+}
+
+// DeleteBranch deletes a branch, whether or not it has been merged.
+func (r *Repository) DeleteBranch(branch string) error {
+	_, err := runGit(r.path, "", "branch", "-D", branch)
+	return err
+}
+
+// copyFile copies a file, keeping its permissions
+func copyFile(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
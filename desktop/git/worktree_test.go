@@ -0,0 +1,61 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCreateWorktree(t *testing.T) {
+	repoPath, cleanup := createTestRepo(t)
+	defer cleanup()
+
+	createFile(t, repoPath, "app.txt", "original\n")
+	createFile(t, repoPath, ".gitignore", "ignored.txt\n")
+	commitChanges(t, repoPath, "Initial commit")
+
+	// Uncommitted work the fork should start from
+	createFile(t, repoPath, "app.txt", "changed\n")
+	createFile(t, repoPath, "new.txt", "untracked\n")
+	createFile(t, repoPath, "ignored.txt", "ignored\n")
+
+	repo := NewRepository(repoPath)
+	worktreePath := filepath.Join(repoPath, ".worktrees", "fork-1")
+	if err := repo.CreateWorktree(worktreePath, "fork/one"); err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+
+	if got := readFile(t, filepath.Join(worktreePath, "app.txt")); got != "changed\n" {
+		t.Errorf("Tracked change not copied, got %q", got)
+	}
+	if got := readFile(t, filepath.Join(worktreePath, "new.txt")); got != "untracked\n" {
+		t.Errorf("Untracked file not copied, got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, "ignored.txt")); !os.IsNotExist(err) {
+		t.Errorf("Ignored file should not be copied, stat error = %v", err)
+	}
+
+	branch, err := NewRepository(worktreePath).GetCurrentBranch()
+	if err != nil || branch != "fork/one" {
+		t.Errorf("Worktree branch = %q (err %v), want fork/one", branch, err)
+	}
+
+	// The original checkout is untouched
+	if got := readFile(t, filepath.Join(repoPath, "app.txt")); got != "changed\n" {
+		t.Errorf("Original checkout changed, got %q", got)
+	}
+
+	if err := repo.RemoveWorktree(worktreePath); err != nil {
+		t.Fatalf("RemoveWorktree() error = %v", err)
+	}
+	if _, err := os.Stat(worktreePath); !os.IsNotExist(err) {
+		t.Errorf("Worktree still exists, stat error = %v", err)
+	}
+
+	if err := repo.DeleteBranch("fork/one"); err != nil {
+		t.Fatalf("DeleteBranch() error = %v", err)
+	}
+	if _, err := runGit(repoPath, "", "rev-parse", "--verify", "fork/one"); err == nil {
+		t.Errorf("Branch fork/one still exists")
+	}
+}
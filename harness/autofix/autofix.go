@@ -0,0 +1,209 @@
+// Package autofix runs the formatters and autofixers a repository is
+// already configured for (gofmt/goimports, prettier, eslint --fix,
+// rubocop -a, ruff --fix) on the files an agent changed, so formatting and
+// mechanical fixes never reach a reviewer.
+//
+// Fixers run in order, each on the files it handles, and the files are
+// compared before and after each run to record which tool changed which
+// files. Tools that are not installed are skipped and noted in the result.
+package autofix
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/philjestin/boatman-ecosystem/harness/internal/toolrun"
+)
+
+// Fixer describes a formatter or autofixer and how to run it.
+type Fixer struct {
+	Name string
+
+	// Extensions are the file extensions the fixer rewrites.
+	Extensions []string
+
+	// Detect reports whether the repository at workDir is configured for the fixer.
+	Detect func(workDir string) bool
+
+	// Command returns the command line that fixes files (relative to workDir)
+	// in place.
+	Command func(workDir string, files []string) []string
+}
+
+// CommandFunc runs a command in dir. A non-zero exit status is not an error
+// on its own, since autofixers exit non-zero when problems remain that they
+// can't fix.
+type CommandFunc func(ctx context.Context, dir string, args []string) error
+
+// Change records the files one fixer changed.
+type Change struct {
+	Fixer string
+	Files []string
+}
+
+// Result is the outcome of a Fix.
+type Result struct {
+	Changes []Change // Fixers that changed files, in the order they ran
+	Ran     []string // Fixers that ran
+	Skipped []string // Fixers that could not be run, with the reason
+}
+
+// FilesChanged returns every file a fixer changed, in the order they were
+// first changed.
+func (r *Result) FilesChanged() []string {
+	seen := map[string]bool{}
+	var files []string
+	for _, c := range r.Changes {
+		for _, f := range c.Files {
+			if !seen[f] {
+				seen[f] = true
+				files = append(files, f)
+			}
+		}
+	}
+	return files
+}
+
+// Summary describes the result in one line, e.g.
+// "gofmt: 2 files, prettier: 1 file".
+func (r *Result) Summary() string {
+	var summary string
+	switch {
+	case len(r.Ran) == 0:
+		summary = "No fixers ran"
+	case len(r.Changes) == 0:
+		summary = fmt.Sprintf("%s: no changes", strings.Join(r.Ran, ", "))
+	default:
+		parts := make([]string, len(r.Changes))
+		for i, c := range r.Changes {
+			noun := "files"
+			if len(c.Files) == 1 {
+				noun = "file"
+			}
+			parts[i] = fmt.Sprintf("%s: %d %s", c.Fixer, len(c.Files), noun)
+		}
+		summary = strings.Join(parts, ", ")
+	}
+	if len(r.Skipped) > 0 {
+		summary += "; skipped " + strings.Join(r.Skipped, ", ")
+	}
+	return summary
+}
+
+// Autofixer runs fixers over changed files.
+type Autofixer struct {
+	workDir string
+	fixers  []Fixer
+	run     CommandFunc
+}
+
+// New creates an Autofixer using the fixers the repository at workDir is
+// configured for. If names are given, only the fixers with those names are
+// considered.
+func New(workDir string, names ...string) *Autofixer {
+	only := map[string]bool{}
+	for _, n := range names {
+		only[n] = true
+	}
+
+	var detected []Fixer
+	for _, f := range DefaultFixers() {
+		if len(only) > 0 && !only[f.Name] {
+			continue
+		}
+		if f.Detect(workDir) {
+			detected = append(detected, f)
+		}
+	}
+	return NewWithFixers(workDir, detected...)
+}
+
+// NewWithFixers creates an Autofixer that runs the given fixers without
+// detecting them.
+func NewWithFixers(workDir string, fixers ...Fixer) *Autofixer {
+	return &Autofixer{
+		workDir: workDir,
+		fixers:  fixers,
+		run:     runCommand,
+	}
+}
+
+// SetCommandFunc replaces how fixer commands are run.
+func (a *Autofixer) SetCommandFunc(run CommandFunc) {
+	a.run = run
+}
+
+// Fixers returns the names of the fixers the Autofixer runs.
+func (a *Autofixer) Fixers() []string {
+	names := make([]string, len(a.fixers))
+	for i, f := range a.fixers {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// Fix runs the fixers on the given files (relative to the work directory).
+// Files that no longer exist are left out. Fixers that fail to run are
+// skipped and noted in the result.
+func (a *Autofixer) Fix(ctx context.Context, files []string) (*Result, error) {
+	result := &Result{}
+
+	for _, f := range a.fixers {
+		targets := toolrun.FilesFor(a.workDir, files, f.Extensions)
+		if len(targets) == 0 {
+			continue
+		}
+
+		before := a.hashFiles(targets)
+		if err := a.run(ctx, a.workDir, f.Command(a.workDir, targets)); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s (%v)", f.Name, err))
+			continue
+		}
+		result.Ran = append(result.Ran, f.Name)
+
+		after := a.hashFiles(targets)
+		var changed []string
+		for _, t := range targets {
+			if before[t] != after[t] {
+				changed = append(changed, t)
+			}
+		}
+		if len(changed) > 0 {
+			result.Changes = append(result.Changes, Change{Fixer: f.Name, Files: changed})
+		}
+	}
+
+	return result, nil
+}
+
+// hashFiles returns a content hash of each file; unreadable files hash empty
+func (a *Autofixer) hashFiles(files []string) map[string][sha256.Size]byte {
+	hashes := make(map[string][sha256.Size]byte, len(files))
+	for _, f := range files {
+		data, err := os.ReadFile(filepath.Join(a.workDir, f))
+		if err != nil {
+			continue
+		}
+		hashes[f] = sha256.Sum256(data)
+	}
+	return hashes
+}
+
+// runCommand runs args in dir. Exit errors are ignored: autofixers exit
+// non-zero when problems remain, and the files are compared either way.
+func runCommand(ctx context.Context, dir string, args []string) error {
+	var stderr bytes.Buffer
+	err := toolrun.Run(ctx, dir, args, nil, &stderr)
+	if err != nil && !toolrun.Exited(err) {
+		return toolrun.Failure(err, stderr.String())
+	}
+	return nil
+}
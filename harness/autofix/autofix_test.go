@@ -0,0 +1,140 @@
+package autofix
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/philjestin/boatman-ecosystem/harness/internal/testutil"
+)
+
+func fixer(name string, extensions ...string) Fixer {
+	return Fixer{
+		Name:       name,
+		Extensions: extensions,
+		Detect:     func(string) bool { return true },
+		Command: func(_ string, files []string) []string {
+			return append([]string{name}, files...)
+		},
+	}
+}
+
+func TestFix(t *testing.T) {
+	dir := t.TempDir()
+	testutil.WriteFiles(t, dir, map[string]string{
+		"src/app.ts":    "const a = 1\n",
+		"src/util.ts":   "export const b = 2;\n",
+		"lib/user.rb":   "class User; end\n",
+		"tool.py":       "import os\n",
+		"docs/about.md": "# About\n",
+	})
+
+	var commands []string
+	a := NewWithFixers(dir, fixer("eslint", ".ts"), fixer("prettier", ".ts", ".md"), fixer("rubocop", ".rb"), fixer("ruff", ".py"))
+	a.SetCommandFunc(func(_ context.Context, dir string, args []string) error {
+		commands = append(commands, strings.Join(args, " "))
+		switch args[0] {
+		case "eslint":
+			return os.WriteFile(filepath.Join(dir, "src/app.ts"), []byte("const a = 1;\n"), 0644)
+		case "prettier":
+			// Rewriting a file with the same content is not a change
+			if err := os.WriteFile(filepath.Join(dir, "src/util.ts"), []byte("export const b = 2;\n"), 0644); err != nil {
+				return err
+			}
+			return os.WriteFile(filepath.Join(dir, "src/app.ts"), []byte("const a = 1;\n\n"), 0644)
+		case "rubocop":
+			return os.WriteFile(filepath.Join(dir, "lib/user.rb"), []byte("class User\nend\n"), 0644)
+		default:
+			return errors.New("executable file not found in $PATH")
+		}
+	})
+
+	result, err := a.Fix(context.Background(), []string{"src/app.ts", "src/util.ts", "lib/user.rb", "tool.py", "deleted.ts"})
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+
+	wantCommands := []string{"eslint src/app.ts src/util.ts", "prettier src/app.ts src/util.ts", "rubocop lib/user.rb", "ruff tool.py"}
+	if !reflect.DeepEqual(commands, wantCommands) {
+		t.Errorf("Expected only changed files to be fixed, got %q", commands)
+	}
+	wantChanges := []Change{
+		{Fixer: "eslint", Files: []string{"src/app.ts"}},
+		{Fixer: "prettier", Files: []string{"src/app.ts"}},
+		{Fixer: "rubocop", Files: []string{"lib/user.rb"}},
+	}
+	if !reflect.DeepEqual(result.Changes, wantChanges) {
+		t.Errorf("Changes = %+v, want %+v", result.Changes, wantChanges)
+	}
+	if got := result.FilesChanged(); !reflect.DeepEqual(got, []string{"src/app.ts", "lib/user.rb"}) {
+		t.Errorf("FilesChanged() = %v", got)
+	}
+	if got, want := result.Summary(), "eslint: 1 file, prettier: 1 file, rubocop: 1 file; skipped ruff (executable file not found in $PATH)"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
+
+func TestFixNoChanges(t *testing.T) {
+	dir := t.TempDir()
+	testutil.WriteFiles(t, dir, map[string]string{"main.go": "package main\n"})
+
+	a := NewWithFixers(dir, fixer("gofmt", ".go"))
+	a.SetCommandFunc(func(context.Context, string, []string) error { return nil })
+
+	result, err := a.Fix(context.Background(), []string{"main.go"})
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	if len(result.Changes) != 0 || result.Summary() != "gofmt: no changes" {
+		t.Errorf("Expected no changes, got %+v", result)
+	}
+}
+
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+	testutil.WriteFiles(t, dir, map[string]string{
+		"go.mod":       "module example.com/app\n",
+		".prettierrc":  "{}\n",
+		".rubocop.yml": "AllCops: {}\n",
+	})
+
+	goFixer := "gofmt"
+	if hasGoimports() {
+		goFixer = "goimports"
+	}
+	if got, want := New(dir).Fixers(), []string{goFixer, "prettier", "rubocop"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Fixers() = %v, want %v", got, want)
+	}
+	if got := New(dir, "rubocop", "ruff").Fixers(); !reflect.DeepEqual(got, []string{"rubocop"}) {
+		t.Errorf("Expected only the named fixers that are detected, got %v", got)
+	}
+}
+
+func TestGofmt(t *testing.T) {
+	if _, err := exec.LookPath("gofmt"); err != nil {
+		t.Skip("gofmt not installed")
+	}
+	dir := t.TempDir()
+	testutil.WriteFiles(t, dir, map[string]string{
+		"go.mod":  "module example.com/app\n",
+		"main.go": "package main\nfunc main() {\nprintln( 1 )\n}\n",
+		"ok.go":   "package main\n",
+	})
+
+	result, err := NewWithFixers(dir, Gofmt()).Fix(context.Background(), []string{"main.go", "ok.go"})
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	if !reflect.DeepEqual(result.Changes, []Change{{Fixer: "gofmt", Files: []string{"main.go"}}}) {
+		t.Errorf("Unexpected changes %+v", result.Changes)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "main.go"))
+	if !strings.Contains(string(data), "\tprintln(1)\n") {
+		t.Errorf("Expected main.go to be formatted, got %q", data)
+	}
+}
@@ -0,0 +1,136 @@
+package autofix
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/philjestin/boatman-ecosystem/harness/review/lint"
+)
+
+// DefaultFixers returns the supported fixers in the order they run:
+// goimports (or gofmt), eslint --fix, prettier, rubocop -a and ruff --fix.
+// Prettier runs after eslint so its formatting wins.
+func DefaultFixers() []Fixer {
+	return []Fixer{Goimports(), Gofmt(), ESLint(), Prettier(), RuboCop(), Ruff()}
+}
+
+// Goimports formats Go files and fixes their imports. It is used in Go
+// modules when goimports is installed.
+func Goimports() Fixer {
+	return Fixer{
+		Name:       "goimports",
+		Extensions: []string{".go"},
+		Detect: func(workDir string) bool {
+			return goModule(workDir) && hasGoimports()
+		},
+		Command: func(workDir string, files []string) []string {
+			return append([]string{"goimports", "-w"}, files...)
+		},
+	}
+}
+
+// Gofmt formats Go files. It is used in Go modules when goimports is not
+// installed.
+func Gofmt() Fixer {
+	return Fixer{
+		Name:       "gofmt",
+		Extensions: []string{".go"},
+		Detect: func(workDir string) bool {
+			return goModule(workDir) && !hasGoimports()
+		},
+		Command: func(workDir string, files []string) []string {
+			return append([]string{"gofmt", "-w"}, files...)
+		},
+	}
+}
+
+// ESLint applies eslint's fixes to JavaScript and TypeScript files, in
+// repositories configured for eslint.
+func ESLint() Fixer {
+	l := lint.ESLint()
+	return Fixer{
+		Name:       "eslint",
+		Extensions: l.Extensions,
+		Detect:     l.Detect,
+		Command: func(workDir string, files []string) []string {
+			return append([]string{"npx", "--no-install", "eslint", "--fix"}, files...)
+		},
+	}
+}
+
+// Prettier formats files in repositories that configure or depend on
+// prettier.
+func Prettier() Fixer {
+	return Fixer{
+		Name: "prettier",
+		Extensions: []string{".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx", ".vue",
+			".css", ".scss", ".less", ".html", ".json", ".md", ".yaml", ".yml", ".graphql"},
+		Detect: func(workDir string) bool {
+			for _, name := range []string{
+				".prettierrc", ".prettierrc.json", ".prettierrc.yml", ".prettierrc.yaml", ".prettierrc.json5",
+				".prettierrc.js", ".prettierrc.cjs", ".prettierrc.mjs", ".prettierrc.toml",
+				"prettier.config.js", "prettier.config.cjs", "prettier.config.mjs",
+			} {
+				if fileExists(filepath.Join(workDir, name)) {
+					return true
+				}
+			}
+			// A "prettier" key or dependency in package.json
+			pkg, _ := os.ReadFile(filepath.Join(workDir, "package.json"))
+			return bytes.Contains(pkg, []byte(`"prettier"`))
+		},
+		Command: func(workDir string, files []string) []string {
+			return append([]string{"npx", "--no-install", "prettier", "--write", "--ignore-unknown"}, files...)
+		},
+	}
+}
+
+// RuboCop applies rubocop's safe corrections to Ruby files, through Bundler
+// when the Gemfile includes it.
+func RuboCop() Fixer {
+	l := lint.RuboCop()
+	return Fixer{
+		Name:       "rubocop",
+		Extensions: l.Extensions,
+		Detect:     l.Detect,
+		Command: func(workDir string, files []string) []string {
+			args := []string{"rubocop", "-a", "--force-exclusion", "--format", "quiet"}
+			gemfile, _ := os.ReadFile(filepath.Join(workDir, "Gemfile"))
+			if bytes.Contains(gemfile, []byte("rubocop")) {
+				args = append([]string{"bundle", "exec"}, args...)
+			}
+			return append(args, files...)
+		},
+	}
+}
+
+// Ruff applies ruff's fixes to Python files, in repositories configured for
+// ruff.
+func Ruff() Fixer {
+	l := lint.Ruff()
+	return Fixer{
+		Name:       "ruff",
+		Extensions: l.Extensions,
+		Detect:     l.Detect,
+		Command: func(workDir string, files []string) []string {
+			return append([]string{"ruff", "check", "--fix", "--force-exclude", "--quiet"}, files...)
+		},
+	}
+}
+
+func goModule(workDir string) bool {
+	return fileExists(filepath.Join(workDir, "go.mod")) || fileExists(filepath.Join(workDir, "go.work"))
+}
+
+// hasGoimports reports whether goimports is on the PATH
+func hasGoimports() bool {
+	_, err := exec.LookPath("goimports")
+	return err == nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
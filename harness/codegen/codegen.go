@@ -0,0 +1,213 @@
+// Package codegen keeps generated code out of an agent's hands. It detects
+// generated files from their markers:
+//
+//   - a "Code generated ... DO NOT EDIT." or "@generated" header comment
+//   - a linguist-generated attribute in the repository's .gitattributes
+//   - the usual generated file names (*.pb.go, *_pb.ts, wailsjs/go/, ...)
+//   - patterns configured for the repository
+//
+// and reruns the generators whose source-of-truth files (.proto files,
+// GraphQL schemas, Go methods bound by Wails) an agent changed, so generated
+// code is regenerated rather than edited by hand.
+package codegen
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/philjestin/boatman-ecosystem/harness/internal/udiff"
+)
+
+// DefaultPatterns match the file names common code generators write.
+var DefaultPatterns = []string{
+	"*.pb.go", "*_pb.ts", "*_pb.js", "*_pb2.py", "*_pb2_grpc.py",
+	"*.gen.go", "*.gen.ts", "*.generated.*",
+	"*.graphql.ts", "*.graphql.d.ts",
+	"**/generated/**", "**/wailsjs/go/**",
+}
+
+// headerLines is how many lines of a file are searched for a generated
+// header
+const headerLines = 20
+
+// generatedHeader matches the comment line generators write at the top of
+// their output: Go's "// Code generated ... DO NOT EDIT." in any comment
+// style, or a comment starting with "@generated".
+var generatedHeader = regexp.MustCompile(`^\s*(?://|#|--|;|/?\*+|<!--)\s*(?:Code generated .* DO NOT EDIT\.?|@generated\b)`)
+
+// attribute is a .gitattributes line that sets or unsets linguist-generated
+type attribute struct {
+	pattern   *regexp.Regexp
+	generated bool
+}
+
+// Detector reports whether files in a work directory are generated.
+type Detector struct {
+	workDir    string
+	patterns   []*regexp.Regexp
+	attributes []attribute
+
+	mu      sync.Mutex
+	headers map[string]bool // file -> has a generated header
+}
+
+// NewDetector creates a Detector for the repository at workDir, using the
+// default patterns, the given patterns and workDir's .gitattributes.
+// Patterns follow .gitattributes: one without a slash matches a file name
+// in any directory, and "**" matches any number of directories.
+func NewDetector(workDir string, patterns ...string) *Detector {
+	d := &Detector{
+		workDir:    workDir,
+		attributes: readAttributes(filepath.Join(workDir, ".gitattributes")),
+		headers:    map[string]bool{},
+	}
+	for _, p := range append(append([]string{}, DefaultPatterns...), patterns...) {
+		if re := compileGlob(p); re != nil {
+			d.patterns = append(d.patterns, re)
+		}
+	}
+	return d
+}
+
+// IsGenerated reports whether file (relative to the work directory) is
+// generated. An explicit linguist-generated attribute wins; otherwise a
+// file is generated if it matches a pattern or has a generated header.
+func (d *Detector) IsGenerated(file string) bool {
+	file = udiff.CleanPath(file)
+	if file == "" {
+		return false
+	}
+
+	// The last matching line wins, as in git
+	for i := len(d.attributes) - 1; i >= 0; i-- {
+		if d.attributes[i].pattern.MatchString(file) {
+			return d.attributes[i].generated
+		}
+	}
+	for _, re := range d.patterns {
+		if re.MatchString(file) {
+			return true
+		}
+	}
+	return d.hasHeader(file)
+}
+
+// Split separates files into those written by hand and those generated.
+func (d *Detector) Split(files []string) (source, generated []string) {
+	for _, f := range files {
+		if d.IsGenerated(f) {
+			generated = append(generated, f)
+		} else {
+			source = append(source, f)
+		}
+	}
+	return source, generated
+}
+
+// hasHeader reports whether file starts with a generated header comment.
+// Files that can't be read have none. Results are cached per file.
+func (d *Detector) hasHeader(file string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if found, ok := d.headers[file]; ok {
+		return found
+	}
+
+	f, err := os.Open(filepath.Join(d.workDir, file))
+	if err != nil {
+		return false // Not cached: the file may be created later
+	}
+	defer f.Close()
+
+	found := false
+	scanner := bufio.NewScanner(f)
+	for n := 0; n < headerLines && scanner.Scan(); n++ {
+		if generatedHeader.MatchString(scanner.Text()) {
+			found = true
+			break
+		}
+	}
+	d.headers[file] = found
+	return found
+}
+
+// readAttributes returns the linguist-generated lines of a .gitattributes
+// file, in order
+func readAttributes(path string) []attribute {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var attrs []attribute
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		for _, attr := range fields[1:] {
+			var generated bool
+			switch attr {
+			case "linguist-generated", "linguist-generated=true":
+				generated = true
+			case "-linguist-generated", "!linguist-generated", "linguist-generated=false":
+				generated = false
+			default:
+				continue
+			}
+			if re := compileGlob(fields[0]); re != nil {
+				attrs = append(attrs, attribute{pattern: re, generated: generated})
+			}
+		}
+	}
+	return attrs
+}
+
+// compileGlob converts a .gitattributes-style pattern to a regular
+// expression matching slash-separated paths relative to the work
+// directory. It supports "*", "?" and "**"; nil means the pattern is empty.
+func compileGlob(pattern string) *regexp.Regexp {
+	p := strings.TrimSpace(pattern)
+	if strings.HasSuffix(p, "/") {
+		p += "**"
+	}
+	if p == "" {
+		return nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	// A pattern without a slash matches in any directory
+	if !strings.Contains(p, "/") {
+		sb.WriteString("(?:.*/)?")
+	}
+	p = strings.TrimPrefix(p, "/")
+
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case p[i] == '*':
+			sb.WriteString("[^/]*")
+		case p[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+	sb.WriteString("$")
+
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return nil
+	}
+	return re
+}
@@ -0,0 +1,287 @@
+package codegen
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestIsGenerated(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".gitattributes": "# Generated clients\n" +
+			"clients/** linguist-generated=true\n" +
+			"clients/handwritten.ts -linguist-generated\n" +
+			"*.pb.go linguist-generated=false\n" +
+			"*.md text\n",
+		"mocks/store.go":         "// Code generated by MockGen. DO NOT EDIT.\n// Source: store.go\n\npackage mocks\n",
+		"schema/types.ts":        "/* eslint-disable */\n/**\n * @generated by graphql-codegen\n */\nexport type A = string;\n",
+		"db/query.sql.py":        "#!/usr/bin/env python\n# Code generated by sqlc. DO NOT EDIT.\n",
+		"app/store.go":           "package app\n\n// Notes for readers: a \"Code generated ... DO NOT EDIT.\" header marks generated code.\n",
+		"clients/api.ts":         "export const api = 1;\n",
+		"clients/handwritten.ts": "export const b = 2;\n",
+	})
+
+	d := NewDetector(dir, "vendor/**", "*.snap")
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{"mocks/store.go", true},          // Go header
+		{"schema/types.ts", true},         // @generated in a block comment
+		{"db/query.sql.py", true},         // header after a shebang
+		{"app/store.go", false},           // marker text that isn't the header
+		{"clients/api.ts", true},          // .gitattributes
+		{"clients/handwritten.ts", false}, // unset by a later line
+		{"api/v1/service.pb.go", false},   // .gitattributes overrides the default pattern
+		{"web/src/user_pb.ts", true},      // default pattern
+		{"desktop/frontend/wailsjs/go/main/App.js", true},
+		{"src/generated/models.ts", true},
+		{"generated/models.ts", true},
+		{"src/api.generated.ts", true},
+		{"vendor/lib/lib.go", true},       // configured
+		{"ui/__snapshots__/a.snap", true}, // configured, any directory
+		{"app/vendor/lib.go", false},      // configured pattern is anchored
+		{"missing.go", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := d.IsGenerated(tt.file); got != tt.want {
+			t.Errorf("IsGenerated(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+
+	source, generated := d.Split([]string{"app/store.go", "mocks/store.go", "web/src/user_pb.ts"})
+	if !reflect.DeepEqual(source, []string{"app/store.go"}) || !reflect.DeepEqual(generated, []string{"mocks/store.go", "web/src/user_pb.ts"}) {
+		t.Errorf("Split = %v, %v", source, generated)
+	}
+}
+
+func TestCompileGlob(t *testing.T) {
+	tests := []struct {
+		pattern, file string
+		want          bool
+	}{
+		{"*.pb.go", "a.pb.go", true},
+		{"*.pb.go", "api/v1/a.pb.go", true},
+		{"*.pb.go", "a.pb.go.orig", false},
+		{"/*.go", "main.go", true},
+		{"/*.go", "pkg/main.go", false},
+		{"desktop/*.go", "desktop/app.go", true},
+		{"desktop/*.go", "desktop/services/auth.go", false},
+		{"**/generated/**", "a/b/generated/c/d.ts", true},
+		{"**/generated/**", "generated/d.ts", true},
+		{"gen/", "gen/x/y.go", true},
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file10.txt", false},
+		{"a+b.txt", "a+b.txt", true},
+	}
+	for _, tt := range tests {
+		re := compileGlob(tt.pattern)
+		if re == nil {
+			t.Fatalf("compileGlob(%q) = nil", tt.pattern)
+		}
+		if got := re.MatchString(tt.file); got != tt.want {
+			t.Errorf("%q matching %q = %v, want %v", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+	if compileGlob("  ") != nil {
+		t.Error("expected no pattern for a blank line")
+	}
+}
+
+func TestFilterDiff(t *testing.T) {
+	diff := "diff --git a/api/user.proto b/api/user.proto\n" +
+		"--- a/api/user.proto\n+++ b/api/user.proto\n@@ -1 +1,2 @@\n message User {}\n+message Team {}\n" +
+		"diff --git a/api/user.pb.go b/api/user.pb.go\n" +
+		"--- a/api/user.pb.go\n+++ b/api/user.pb.go\n@@ -1 +1,2 @@\n package api\n+type Team struct{}\n" +
+		"diff --git a/old_pb.ts b/old_pb.ts\ndeleted file mode 100644\n" +
+		"--- a/old_pb.ts\n+++ /dev/null\n@@ -1 +0,0 @@\n-export {}\n" +
+		"diff --git a/README.md b/README.md\n" +
+		"--- a/README.md\n+++ b/README.md\n@@ -1 +1 @@\n-old\n+new\n"
+
+	d := NewDetector(t.TempDir())
+	filtered, omitted := d.FilterDiff(diff)
+	if !reflect.DeepEqual(omitted, []string{"api/user.pb.go", "old_pb.ts"}) {
+		t.Errorf("omitted = %v", omitted)
+	}
+	if strings.Contains(filtered, "pb.go") || strings.Contains(filtered, "old_pb.ts") {
+		t.Errorf("generated sections kept:\n%s", filtered)
+	}
+	if !strings.Contains(filtered, "+message Team {}") || !strings.Contains(filtered, "+new") {
+		t.Errorf("source sections dropped:\n%s", filtered)
+	}
+}
+
+func TestDefaultGenerators(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"buf.gen.yaml":              "version: v2\n",
+		"package.json":              `{"devDependencies": {"@graphql-codegen/cli": "^5.0.0"}}`,
+		"desktop/wails.json":        "{}\n",
+		"desktop/app.go":            "package main\n",
+		"services/api/package.json": "{}\n",
+	})
+
+	var names, dirs []string
+	for _, g := range DefaultGenerators(dir) {
+		names = append(names, g.Name)
+		dirs = append(dirs, g.Dir)
+	}
+	if !reflect.DeepEqual(names, []string{"buf", "graphql-codegen", "wails"}) {
+		t.Errorf("generators = %v", names)
+	}
+	if dirs[2] != "desktop" {
+		t.Errorf("wails dir = %q, want desktop", dirs[2])
+	}
+
+	if got := DefaultGenerators(t.TempDir()); len(got) != 0 {
+		t.Errorf("expected no generators in an empty repository, got %v", got)
+	}
+
+	r := New(dir)
+	if got := r.Affected([]string{"desktop/app.go", "README.md"}); !reflect.DeepEqual(got, []string{"wails"}) {
+		t.Errorf("Affected = %v, want [wails]", got)
+	}
+	if got := r.Outputs(); !reflect.DeepEqual(got, []string{"desktop/frontend/wailsjs/**"}) {
+		t.Errorf("Outputs = %v", got)
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func gitRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	runGit(t, dir, "config", "user.name", "Test")
+	writeFiles(t, dir, files)
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "-m", "initial")
+	return dir
+}
+
+func TestRegenerate(t *testing.T) {
+	dir := gitRepo(t, map[string]string{
+		"api/user.proto": "message User {}\n",
+		"api/user.pb.go": "// Code generated by protoc-gen-go. DO NOT EDIT.\npackage api\n",
+		"schema.graphql": "type Query { a: Int }\n",
+	})
+	// A source change and an unrelated change the snapshot must not count
+	writeFiles(t, dir, map[string]string{
+		"api/user.proto": "message User {}\nmessage Team {}\n",
+		"notes.txt":      "todo\n",
+	})
+
+	var commands []string
+	r := NewWithGenerators(dir,
+		Generator{Name: "protoc", Sources: []string{"*.proto"}, Dir: "api", Command: []string{"protoc", "--go_out=."}},
+		Generator{Name: "graphql", Sources: []string{"*.graphql"}, Command: []string{"graphql-codegen"}},
+		Generator{Name: "broken", Sources: []string{"api/*.proto"}, Command: []string{"broken"}},
+	)
+	r.SetCommandFunc(func(_ context.Context, cmdDir string, args []string) error {
+		commands = append(commands, filepath.Base(cmdDir)+": "+strings.Join(args, " "))
+		switch args[0] {
+		case "protoc":
+			writeFiles(t, dir, map[string]string{
+				"api/user.pb.go": "// Code generated by protoc-gen-go. DO NOT EDIT.\npackage api\n\ntype Team struct{}\n",
+				"api/team.pb.go": "// Code generated by protoc-gen-go. DO NOT EDIT.\npackage api\n",
+			})
+			return nil
+		case "broken":
+			return errors.New("exit status 1: protoc-gen-foo: program not found")
+		}
+		return nil
+	})
+
+	if got := r.Affected([]string{"api/user.proto"}); !reflect.DeepEqual(got, []string{"protoc", "broken"}) {
+		t.Errorf("Affected = %v", got)
+	}
+
+	result, err := r.Regenerate(context.Background(), []string{"api/user.proto", "notes.txt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantCommands := []string{"api: protoc --go_out=.", filepath.Base(dir) + ": broken"}
+	if !reflect.DeepEqual(commands, wantCommands) {
+		t.Errorf("commands = %v, want %v", commands, wantCommands)
+	}
+	want := []Run{{Generator: "protoc", Sources: []string{"api/user.proto"}, Files: []string{"api/team.pb.go", "api/user.pb.go"}}}
+	if !reflect.DeepEqual(result.Runs, want) {
+		t.Errorf("Runs = %+v, want %+v", result.Runs, want)
+	}
+	if got := result.Summary(); got != "protoc: 2 files; failed broken (exit status 1: protoc-gen-foo: program not found)" {
+		t.Errorf("Summary = %q", got)
+	}
+	if got := result.FilesChanged(); !reflect.DeepEqual(got, []string{"api/team.pb.go", "api/user.pb.go"}) {
+		t.Errorf("FilesChanged = %v", got)
+	}
+
+	if got := (&Result{}).Summary(); got != "No generators ran" {
+		t.Errorf("empty Summary = %q", got)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	dir := gitRepo(t, map[string]string{
+		"api/user.pb.go": "package api\n",
+		"main.go":        "package main\n",
+	})
+	writeFiles(t, dir, map[string]string{
+		"api/user.pb.go": "package api\n\n// hand edit\n",
+		"api/team.pb.go": "package api\n",
+		"main.go":        "package main\n\nfunc main() {}\n",
+	})
+	runGit(t, dir, "add", "-A")
+
+	if err := Restore(context.Background(), dir, []string{"api/user.pb.go", "api/team.pb.go"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(dir, "api/user.pb.go")); string(data) != "package api\n" {
+		t.Errorf("user.pb.go not restored: %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "api/team.pb.go")); !os.IsNotExist(err) {
+		t.Errorf("team.pb.go not removed: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "main.go")); !strings.Contains(string(data), "func main") {
+		t.Error("main.go should keep its change")
+	}
+
+	cmd := exec.Command("git", "status", "--porcelain")
+	cmd.Dir = dir
+	out, _ := cmd.Output()
+	if strings.TrimSpace(string(out)) != "M  main.go" {
+		t.Errorf("git status = %q, want only main.go staged", out)
+	}
+}
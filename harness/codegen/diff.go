@@ -0,0 +1,22 @@
+package codegen
+
+import (
+	"strings"
+
+	"github.com/philjestin/boatman-ecosystem/harness/internal/udiff"
+)
+
+// FilterDiff removes the generated files' sections from a git diff. It
+// returns the rest of the diff and the generated files it left out.
+func (d *Detector) FilterDiff(diff string) (string, []string) {
+	var sb strings.Builder
+	var omitted []string
+	for _, s := range udiff.Parse(diff) {
+		if s.Path != "" && d.IsGenerated(s.Path) {
+			omitted = append(omitted, s.Path)
+			continue
+		}
+		sb.WriteString(s.Text)
+	}
+	return sb.String(), omitted
+}
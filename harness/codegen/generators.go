@@ -0,0 +1,121 @@
+package codegen
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+)
+
+// Generator maps source-of-truth files to the command that regenerates the
+// code generated from them.
+type Generator struct {
+	Name string
+
+	// Sources are the patterns of the files the code is generated from,
+	// relative to the work directory. A change to one runs the generator.
+	Sources []string
+
+	// Outputs are the patterns of the files the generator writes, relative
+	// to the work directory. They are generated files even without a
+	// marker.
+	Outputs []string
+
+	// Dir is the directory the command runs in, relative to the work
+	// directory. Empty runs it in the work directory.
+	Dir string
+
+	// Command is the command line that regenerates the code.
+	Command []string
+}
+
+// DefaultGenerators returns the generators the repository at workDir is
+// configured for: buf for protobuf, GraphQL Code Generator for GraphQL
+// schemas and the Wails bindings of each Wails app in workDir or a
+// directory directly below it.
+func DefaultGenerators(workDir string) []Generator {
+	var generators []Generator
+	if anyExists(workDir, "buf.gen.yaml", "buf.gen.yml") {
+		generators = append(generators, Buf())
+	}
+	if hasGraphQLCodegen(workDir) {
+		generators = append(generators, GraphQLCodegen())
+	}
+	for _, dir := range wailsApps(workDir) {
+		generators = append(generators, Wails(dir))
+	}
+	return generators
+}
+
+// Buf regenerates protobuf code with buf generate after a .proto file
+// changes.
+func Buf() Generator {
+	return Generator{
+		Name:    "buf",
+		Sources: []string{"*.proto"},
+		Command: []string{"buf", "generate"},
+	}
+}
+
+// GraphQLCodegen regenerates GraphQL types and operations with GraphQL Code
+// Generator after a schema or document changes.
+func GraphQLCodegen() Generator {
+	return Generator{
+		Name:    "graphql-codegen",
+		Sources: []string{"*.graphql", "*.gql"},
+		Command: []string{"npx", "--no-install", "graphql-codegen"},
+	}
+}
+
+// Wails regenerates the frontend bindings of the Wails app in dir after its
+// Go code changes.
+func Wails(dir string) Generator {
+	prefix := "/" // Only the app's own package, not every Go file below it
+	if dir != "" && dir != "." {
+		prefix = filepath.ToSlash(dir) + "/"
+	}
+	return Generator{
+		Name:    "wails",
+		Sources: []string{prefix + "*.go"},
+		Outputs: []string{prefix + "frontend/wailsjs/**"},
+		Dir:     dir,
+		Command: []string{"wails", "generate", "module"},
+	}
+}
+
+// hasGraphQLCodegen reports whether the repository has a GraphQL Code
+// Generator config, or depends on its CLI
+func hasGraphQLCodegen(workDir string) bool {
+	if anyExists(workDir, "codegen.yml", "codegen.yaml", "codegen.json", "codegen.ts", "codegen.js") {
+		return true
+	}
+	pkg, _ := os.ReadFile(filepath.Join(workDir, "package.json"))
+	return bytes.Contains(pkg, []byte(`"@graphql-codegen/cli"`))
+}
+
+// wailsApps returns the directories, relative to workDir, that hold a
+// wails.json: workDir itself or a directory directly below it
+func wailsApps(workDir string) []string {
+	if anyExists(workDir, "wails.json") {
+		return []string{"."}
+	}
+	entries, err := os.ReadDir(workDir)
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, e := range entries {
+		if e.IsDir() && anyExists(filepath.Join(workDir, e.Name()), "wails.json") {
+			dirs = append(dirs, e.Name())
+		}
+	}
+	return dirs
+}
+
+func anyExists(dir string, names ...string) bool {
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
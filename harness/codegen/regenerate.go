@@ -0,0 +1,310 @@
+package codegen
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/philjestin/boatman-ecosystem/harness/internal/udiff"
+)
+
+// CommandFunc runs a generator command in dir.
+type CommandFunc func(ctx context.Context, dir string, args []string) error
+
+// Run records one generator run: the changed sources that triggered it and
+// the files it changed.
+type Run struct {
+	Generator string
+	Sources   []string
+	Files     []string
+}
+
+// Result is the outcome of a Regenerate.
+type Result struct {
+	Runs   []Run    // Generators that ran, in order
+	Failed []string // Generators that failed, with the reason
+}
+
+// FilesChanged returns every file a generator changed, in the order they
+// were first changed.
+func (r *Result) FilesChanged() []string {
+	seen := map[string]bool{}
+	var files []string
+	for _, run := range r.Runs {
+		for _, f := range run.Files {
+			if !seen[f] {
+				seen[f] = true
+				files = append(files, f)
+			}
+		}
+	}
+	return files
+}
+
+// Summary describes the result in one line, e.g.
+// "buf: 3 files, wails: no changes; failed graphql-codegen (exit status 1)".
+func (r *Result) Summary() string {
+	var parts []string
+	for _, run := range r.Runs {
+		switch len(run.Files) {
+		case 0:
+			parts = append(parts, run.Generator+": no changes")
+		case 1:
+			parts = append(parts, run.Generator+": 1 file")
+		default:
+			parts = append(parts, fmt.Sprintf("%s: %d files", run.Generator, len(run.Files)))
+		}
+	}
+	summary := strings.Join(parts, ", ")
+	if summary == "" {
+		summary = "No generators ran"
+	}
+	if len(r.Failed) > 0 {
+		summary += "; failed " + strings.Join(r.Failed, ", ")
+	}
+	return summary
+}
+
+// Regenerator reruns code generators when their sources change.
+type Regenerator struct {
+	workDir    string
+	generators []Generator
+	sources    [][]*regexp.Regexp // per generator
+	run        CommandFunc
+}
+
+// New creates a Regenerator using the generators the repository at workDir
+// is configured for, followed by the given ones.
+func New(workDir string, generators ...Generator) *Regenerator {
+	return NewWithGenerators(workDir, append(DefaultGenerators(workDir), generators...)...)
+}
+
+// NewWithGenerators creates a Regenerator that runs the given generators
+// without detecting any.
+func NewWithGenerators(workDir string, generators ...Generator) *Regenerator {
+	r := &Regenerator{
+		workDir:    workDir,
+		generators: generators,
+		run:        runCommand,
+	}
+	for _, g := range generators {
+		var patterns []*regexp.Regexp
+		for _, s := range g.Sources {
+			if re := compileGlob(s); re != nil {
+				patterns = append(patterns, re)
+			}
+		}
+		r.sources = append(r.sources, patterns)
+	}
+	return r
+}
+
+// SetCommandFunc replaces how generator commands are run.
+func (r *Regenerator) SetCommandFunc(run CommandFunc) {
+	r.run = run
+}
+
+// Generators returns the names of the Regenerator's generators.
+func (r *Regenerator) Generators() []string {
+	names := make([]string, len(r.generators))
+	for i, g := range r.generators {
+		names[i] = g.Name
+	}
+	return names
+}
+
+// Outputs returns the output patterns of every generator, for a Detector.
+func (r *Regenerator) Outputs() []string {
+	var outputs []string
+	for _, g := range r.generators {
+		outputs = append(outputs, g.Outputs...)
+	}
+	return outputs
+}
+
+// Affected returns the names of the generators with a source among files.
+func (r *Regenerator) Affected(files []string) []string {
+	var names []string
+	for i, g := range r.generators {
+		if len(r.sourcesOf(i, files)) > 0 {
+			names = append(names, g.Name)
+		}
+	}
+	return names
+}
+
+// Regenerate runs each generator with a source among the changed files
+// (relative to the work directory), which must be a git work tree, and
+// records the files each one changed. Generators that fail are noted in
+// the result and skipped.
+func (r *Regenerator) Regenerate(ctx context.Context, changed []string) (*Result, error) {
+	result := &Result{}
+	for i, g := range r.generators {
+		sources := r.sourcesOf(i, changed)
+		if len(sources) == 0 {
+			continue
+		}
+
+		before, err := snapshot(ctx, r.workDir)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.run(ctx, filepath.Join(r.workDir, g.Dir), g.Command); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			result.Failed = append(result.Failed, fmt.Sprintf("%s (%v)", g.Name, err))
+			continue
+		}
+		after, err := snapshot(ctx, r.workDir)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Runs = append(result.Runs, Run{Generator: g.Name, Sources: sources, Files: changedBetween(before, after)})
+	}
+	return result, nil
+}
+
+// sourcesOf returns the files that are sources of generator i
+func (r *Regenerator) sourcesOf(i int, files []string) []string {
+	var sources []string
+	for _, f := range files {
+		f = udiff.CleanPath(f)
+		for _, re := range r.sources[i] {
+			if re.MatchString(f) {
+				sources = append(sources, f)
+				break
+			}
+		}
+	}
+	return sources
+}
+
+// Restore discards the changes to files in workDir, a git work tree: files
+// in HEAD are checked out from it and files that aren't are removed. It is
+// used to undo hand edits to generated files before they are regenerated.
+func Restore(ctx context.Context, workDir string, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	out, err := git(ctx, workDir, append([]string{"ls-tree", "-r", "-z", "--name-only", "HEAD", "--"}, files...)...)
+	if err != nil {
+		return err
+	}
+	tracked := map[string]bool{}
+	for _, f := range strings.Split(out, "\x00") {
+		if f != "" {
+			tracked[f] = true
+		}
+	}
+
+	var checkout []string
+	for _, f := range files {
+		if tracked[udiff.CleanPath(f)] {
+			checkout = append(checkout, f)
+			continue
+		}
+		if _, err := git(ctx, workDir, "rm", "-q", "--cached", "--ignore-unmatch", "--", f); err != nil {
+			return err
+		}
+		if err := os.Remove(filepath.Join(workDir, f)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", f, err)
+		}
+	}
+	if len(checkout) > 0 {
+		if _, err := git(ctx, workDir, append([]string{"checkout", "HEAD", "--"}, checkout...)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshot hashes every file git reports as changed or untracked in dir;
+// deleted files hash empty
+func snapshot(ctx context.Context, dir string) (map[string][sha256.Size]byte, error) {
+	out, err := git(ctx, dir, "status", "--porcelain", "-z", "-uall")
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := map[string][sha256.Size]byte{}
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		// A rename's original path follows it as its own entry
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++
+		}
+		file := entry[3:]
+		var hash [sha256.Size]byte
+		if data, err := os.ReadFile(filepath.Join(dir, file)); err == nil {
+			hash = sha256.Sum256(data)
+		}
+		hashes[file] = hash
+	}
+	return hashes, nil
+}
+
+// changedBetween returns the files whose snapshot differs, sorted. A file
+// in only one snapshot was changed or restored to HEAD in between.
+func changedBetween(before, after map[string][sha256.Size]byte) []string {
+	var files []string
+	for f, h := range after {
+		if b, ok := before[f]; !ok || b != h {
+			files = append(files, f)
+		}
+	}
+	for f := range before {
+		if _, ok := after[f]; !ok {
+			files = append(files, f)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+// runCommand runs args in dir. A failure's error includes the last line
+// of output, where generators report what went wrong.
+func runCommand(ctx context.Context, dir string, args []string) error {
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := lastLine(strings.TrimSpace(string(out))); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+func lastLine(s string) string {
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
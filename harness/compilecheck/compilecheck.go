@@ -0,0 +1,228 @@
+// Package compilecheck runs a project's own build or type check over changed
+// files (go build, tsc --noEmit, ruby -c, python -m py_compile) and reports
+// the errors in a form that can be handed straight back to the model that
+// wrote the code.
+//
+// A build check costs seconds, while a review or refactor prompt costs a
+// model call; harnesses run it between execution and review so that only code
+// that compiles is reviewed.
+package compilecheck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/philjestin/boatman-ecosystem/harness/internal/toolrun"
+	"github.com/philjestin/boatman-ecosystem/harness/review"
+)
+
+// maxUnparsedLines caps the output kept when a compiler fails without
+// errors the parser recognizes.
+const maxUnparsedLines = 20
+
+// Error is one error reported by a compiler.
+type Error struct {
+	Compiler string
+	File     string // Relative to the work directory; empty when the error has no location
+	Line     int
+	Column   int
+	Message  string
+}
+
+// String formats the error the way compilers do: file:line:column: message.
+func (e Error) String() string {
+	switch {
+	case e.File == "":
+		return e.Message
+	case e.Line == 0:
+		return fmt.Sprintf("%s: %s", e.File, e.Message)
+	case e.Column == 0:
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+	default:
+		return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+	}
+}
+
+// Compiler describes a build or type-check tool and how to run it.
+type Compiler struct {
+	Name string
+
+	// Extensions are the file extensions that trigger the compiler.
+	Extensions []string
+
+	// Detect reports whether the compiler applies to the repository at workDir.
+	Detect func(workDir string) bool
+
+	// Commands returns the command lines that check files (relative to
+	// workDir). Most compilers check everything in one command; some need one
+	// command per file.
+	Commands func(workDir string, files []string) [][]string
+
+	// Parse extracts errors from the compiler's output.
+	Parse func(output []byte) []Error
+}
+
+// CommandFunc runs a command in dir and returns its combined output. failed
+// reports a non-zero exit status; err means the command could not be run.
+type CommandFunc func(ctx context.Context, dir string, args []string) (output []byte, failed bool, err error)
+
+// Result is the outcome of a check.
+type Result struct {
+	Passed  bool
+	Errors  []Error
+	Ran     []string // Compilers that ran
+	Skipped []string // Compilers that could not be run, with the reason
+}
+
+// Issues converts the errors into critical review issues.
+func (r *Result) Issues() []review.Issue {
+	issues := make([]review.Issue, len(r.Errors))
+	for i, e := range r.Errors {
+		issues[i] = review.Issue{
+			Severity:    "critical",
+			File:        e.File,
+			Line:        e.Line,
+			Description: fmt.Sprintf("%s (%s)", e.Message, e.Compiler),
+			Code:        "compile",
+		}
+	}
+	return issues
+}
+
+// Format lists up to maxErrors errors, one per line, for a repair prompt.
+// maxErrors <= 0 lists them all.
+func (r *Result) Format(maxErrors int) string {
+	var sb strings.Builder
+	for i, e := range r.Errors {
+		if maxErrors > 0 && i == maxErrors {
+			fmt.Fprintf(&sb, "... and %d more errors\n", len(r.Errors)-maxErrors)
+			break
+		}
+		sb.WriteString(e.String())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Summary describes the result in one line.
+func (r *Result) Summary() string {
+	var summary string
+	switch {
+	case len(r.Ran) == 0:
+		summary = "No compilers ran"
+	case r.Passed:
+		summary = fmt.Sprintf("%s: OK", strings.Join(r.Ran, ", "))
+	default:
+		summary = fmt.Sprintf("%s: %d errors", strings.Join(r.Ran, ", "), len(r.Errors))
+	}
+	if len(r.Skipped) > 0 {
+		summary += "; skipped " + strings.Join(r.Skipped, ", ")
+	}
+	return summary
+}
+
+// Checker runs compilers over changed files.
+type Checker struct {
+	workDir   string
+	compilers []Compiler
+	run       CommandFunc
+}
+
+// New creates a Checker using the compilers that apply to the repository at
+// workDir.
+func New(workDir string) *Checker {
+	var detected []Compiler
+	for _, c := range DefaultCompilers() {
+		if c.Detect(workDir) {
+			detected = append(detected, c)
+		}
+	}
+	return NewWithCompilers(workDir, detected...)
+}
+
+// NewWithCompilers creates a Checker that runs the given compilers without
+// detecting them.
+func NewWithCompilers(workDir string, compilers ...Compiler) *Checker {
+	return &Checker{
+		workDir:   workDir,
+		compilers: compilers,
+		run:       runCommand,
+	}
+}
+
+// SetCommandFunc replaces how compiler commands are run.
+func (c *Checker) SetCommandFunc(run CommandFunc) {
+	c.run = run
+}
+
+// Compilers returns the names of the compilers the checker runs.
+func (c *Checker) Compilers() []string {
+	names := make([]string, len(c.compilers))
+	for i, comp := range c.compilers {
+		names[i] = comp.Name
+	}
+	return names
+}
+
+// Check runs the compilers that apply to files (relative to the work
+// directory). Errors are reported wherever they are, not only in the changed
+// files, since a change can break the code that uses it. Compilers that
+// can't be run are skipped and noted in the result.
+func (c *Checker) Check(ctx context.Context, files []string) (*Result, error) {
+	result := &Result{}
+
+	for _, comp := range c.compilers {
+		targets := toolrun.FilesFor(c.workDir, files, comp.Extensions)
+		if len(targets) == 0 {
+			continue
+		}
+
+		ran := true
+		for _, args := range comp.Commands(c.workDir, targets) {
+			output, failed, err := c.run(ctx, c.workDir, args)
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if err != nil {
+				result.Skipped = append(result.Skipped, fmt.Sprintf("%s (%v)", comp.Name, err))
+				ran = false
+				break
+			}
+
+			errs := comp.Parse(output)
+			if failed && len(errs) == 0 {
+				// Failed without errors the parser knows: report the output itself
+				errs = []Error{{Message: toolrun.FirstLines(string(output), maxUnparsedLines)}}
+			}
+			for _, e := range errs {
+				e.Compiler = comp.Name
+				if e.File != "" {
+					e.File = toolrun.RelPath(c.workDir, e.File)
+				}
+				result.Errors = append(result.Errors, e)
+			}
+		}
+		if ran {
+			result.Ran = append(result.Ran, comp.Name)
+		}
+	}
+
+	result.Passed = len(result.Errors) == 0
+	return result, nil
+}
+
+// runCommand runs args in dir and returns stdout and stderr together, since
+// compilers report errors on either.
+func runCommand(ctx context.Context, dir string, args []string) ([]byte, bool, error) {
+	var output bytes.Buffer
+	err := toolrun.Run(ctx, dir, args, &output, &output)
+	if toolrun.Exited(err) {
+		return output.Bytes(), true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return output.Bytes(), false, nil
+}
@@ -0,0 +1,151 @@
+package compilecheck
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/philjestin/boatman-ecosystem/harness/internal/testutil"
+)
+
+func TestNew_DetectsCompilers(t *testing.T) {
+	dir := t.TempDir()
+	testutil.WriteFiles(t, dir, map[string]string{"go.mod": "module example.com/app\n"})
+
+	got := New(dir).Compilers()
+	want := []string{"go build", "ruby -c", "py_compile"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compilers() = %v, want %v", got, want)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	testutil.WriteFiles(t, dir, map[string]string{
+		"pkg/server.go":   "package pkg\n",
+		"app/user.rb":     "class User\n",
+		"app/post.rb":     "class Post; end\n",
+		"scripts/tool.py": "print(1)\n",
+		"README.md":       "# App\n",
+	})
+
+	var commands []string
+	c := NewWithCompilers(dir, Go(), Ruby(), Python())
+	c.SetCommandFunc(func(ctx context.Context, _ string, args []string) ([]byte, bool, error) {
+		cmd := strings.Join(args, " ")
+		commands = append(commands, cmd)
+		switch {
+		case cmd == "go build ./...":
+			return []byte("# example.com/app/pkg\npkg/server.go:3:2: undefined: handler\n" + dir + "/cmd/main.go:8:10: too many arguments in call to pkg.Serve\n"), true, nil
+		case strings.HasSuffix(cmd, "app/user.rb"):
+			return []byte("app/user.rb:2: syntax error, unexpected end-of-input, expecting `end'\n"), true, nil
+		case strings.HasSuffix(cmd, "app/post.rb"):
+			return []byte("Syntax OK\n"), false, nil
+		default:
+			return nil, false, errors.New("executable file not found in $PATH")
+		}
+	})
+
+	result, err := c.Check(context.Background(), []string{"pkg/server.go", "app/user.rb", "app/post.rb", "scripts/tool.py", "README.md", "deleted.go"})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if len(commands) != 4 {
+		t.Errorf("Expected go build, ruby -c for each Ruby file, and py_compile, got %q", commands)
+	}
+	if result.Passed {
+		t.Error("Expected the check to fail")
+	}
+	want := []string{
+		"pkg/server.go:3:2: undefined: handler",
+		"cmd/main.go:8:10: too many arguments in call to pkg.Serve",
+		"app/user.rb:2: syntax error, unexpected end-of-input, expecting `end'",
+	}
+	if got := strings.Split(strings.TrimSpace(result.Format(0)), "\n"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+	if !reflect.DeepEqual(result.Ran, []string{"go build", "ruby -c"}) {
+		t.Errorf("Unexpected compilers ran: %v", result.Ran)
+	}
+	if len(result.Skipped) != 1 || !strings.HasPrefix(result.Skipped[0], "py_compile (executable file not found") {
+		t.Errorf("Expected py_compile to be skipped, got %v", result.Skipped)
+	}
+	if got := result.Summary(); got != "go build, ruby -c: 3 errors; skipped "+result.Skipped[0] {
+		t.Errorf("Unexpected summary %q", got)
+	}
+
+	issues := result.Issues()
+	if issues[0].Severity != "critical" || issues[0].Line != 3 || issues[0].Description != "undefined: handler (go build)" {
+		t.Errorf("Unexpected issue %+v", issues[0])
+	}
+}
+
+func TestCheckUnparsedFailure(t *testing.T) {
+	dir := t.TempDir()
+	testutil.WriteFiles(t, dir, map[string]string{"main.go": "package main\n"})
+
+	c := NewWithCompilers(dir, Go())
+	c.SetCommandFunc(func(context.Context, string, []string) ([]byte, bool, error) {
+		return []byte("go: updates to go.mod needed; to update it:\n\tgo mod tidy\n"), true, nil
+	})
+
+	result, err := c.Check(context.Background(), []string{"main.go"})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if result.Passed || len(result.Errors) != 1 || !strings.HasPrefix(result.Errors[0].Message, "go: updates to go.mod needed") {
+		t.Errorf("Expected the raw output as an error, got %+v", result)
+	}
+}
+
+func TestCheckPasses(t *testing.T) {
+	dir := t.TempDir()
+	testutil.WriteFiles(t, dir, map[string]string{"main.go": "package main\n"})
+
+	c := NewWithCompilers(dir, Go())
+	c.SetCommandFunc(func(context.Context, string, []string) ([]byte, bool, error) { return nil, false, nil })
+
+	result, err := c.Check(context.Background(), []string{"main.go"})
+	if err != nil || !result.Passed || result.Summary() != "go build: OK" {
+		t.Errorf("Expected a passing check, got %+v, %v", result, err)
+	}
+}
+
+func TestFormatLimit(t *testing.T) {
+	result := &Result{Errors: []Error{{Message: "a"}, {Message: "b"}, {Message: "c"}}}
+	if got := result.Format(2); got != "a\nb\n... and 1 more errors\n" {
+		t.Errorf("Format(2) = %q", got)
+	}
+}
+
+func TestParseTSC(t *testing.T) {
+	output := "src/app.ts(12,5): error TS2322: Type 'string' is not assignable to type 'number'.\nFound 1 error.\n"
+	want := []Error{{File: "src/app.ts", Line: 12, Column: 5, Message: "TS2322: Type 'string' is not assignable to type 'number'."}}
+	if got := parseTSC([]byte(output)); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTSC() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseRuby(t *testing.T) {
+	output := "ruby: app/user.rb:5: syntax errors found (SyntaxError)\n  4 | def name\n> 5 | \n    | ^ unexpected end-of-input\napp/user.rb:1: warning: mismatched indentations\n"
+	want := []Error{{File: "app/user.rb", Line: 5, Message: "syntax errors found (SyntaxError)"}}
+	if got := parseRuby([]byte(output)); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseRuby() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParsePython(t *testing.T) {
+	output := "  File \"app/views.py\", line 12\n    def index(request:\n             ^\nSyntaxError: '(' was never closed\n" +
+		"Sorry: IndentationError: unexpected indent (tool.py, line 3)\n" +
+		"  File \"tool.py\", line 3\n    x = 1\nIndentationError: unexpected indent\n"
+	want := []Error{
+		{File: "app/views.py", Line: 12, Message: "SyntaxError: '(' was never closed"},
+		{File: "tool.py", Line: 3, Message: "IndentationError: unexpected indent"},
+	}
+	if got := parsePython([]byte(output)); !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePython() = %+v, want %+v", got, want)
+	}
+}
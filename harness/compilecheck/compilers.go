@@ -0,0 +1,165 @@
+package compilecheck
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultCompilers returns the supported compilers: go build, tsc, ruby -c
+// and python -m py_compile.
+func DefaultCompilers() []Compiler {
+	return []Compiler{Go(), TypeScript(), Ruby(), Python()}
+}
+
+// Go builds every package in the module with go build ./..., so packages
+// that use a changed package are checked too.
+func Go() Compiler {
+	return Compiler{
+		Name:       "go build",
+		Extensions: []string{".go"},
+		Detect: func(workDir string) bool {
+			return fileExists(workDir, "go.mod") || fileExists(workDir, "go.work")
+		},
+		Commands: func(workDir string, files []string) [][]string {
+			return [][]string{{"go", "build", "./..."}}
+		},
+		Parse: parseGo,
+	}
+}
+
+// TypeScript type-checks the project with tsc --noEmit.
+func TypeScript() Compiler {
+	return Compiler{
+		Name:       "tsc",
+		Extensions: []string{".ts", ".tsx"},
+		Detect: func(workDir string) bool {
+			return fileExists(workDir, "tsconfig.json")
+		},
+		Commands: func(workDir string, files []string) [][]string {
+			return [][]string{{"npx", "--no-install", "tsc", "--noEmit", "--pretty", "false", "-p", "tsconfig.json"}}
+		},
+		Parse: parseTSC,
+	}
+}
+
+// Ruby checks the syntax of each changed Ruby file with ruby -c, which only
+// checks the first file it is given.
+func Ruby() Compiler {
+	return Compiler{
+		Name:       "ruby -c",
+		Extensions: []string{".rb", ".rake"},
+		Detect:     func(workDir string) bool { return true },
+		Commands: func(workDir string, files []string) [][]string {
+			cmds := make([][]string, len(files))
+			for i, f := range files {
+				cmds[i] = []string{"ruby", "-c", f}
+			}
+			return cmds
+		},
+		Parse: parseRuby,
+	}
+}
+
+// Python byte-compiles the changed Python files with python -m py_compile.
+func Python() Compiler {
+	return Compiler{
+		Name:       "py_compile",
+		Extensions: []string{".py"},
+		Detect:     func(workDir string) bool { return true },
+		Commands: func(workDir string, files []string) [][]string {
+			python := "python3"
+			if _, err := exec.LookPath(python); err != nil {
+				python = "python"
+			}
+			return [][]string{append([]string{python, "-m", "py_compile"}, files...)}
+		},
+		Parse: parsePython,
+	}
+}
+
+// pkg/server.go:12:5: undefined: handler
+var goErrorPattern = regexp.MustCompile(`^(.+?\.go):(\d+)(?::(\d+))?: (.*)$`)
+
+func parseGo(output []byte) []Error {
+	var errs []Error
+	for _, line := range strings.Split(string(output), "\n") {
+		m := goErrorPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		lineNum, _ := strconv.Atoi(m[2])
+		col, _ := strconv.Atoi(m[3])
+		errs = append(errs, Error{File: m[1], Line: lineNum, Column: col, Message: m[4]})
+	}
+	return errs
+}
+
+// src/app.ts(12,5): error TS2322: Type 'string' is not assignable to type 'number'.
+var tscErrorPattern = regexp.MustCompile(`^(.+?)\((\d+),(\d+)\): error (TS\d+): (.*)$`)
+
+func parseTSC(output []byte) []Error {
+	var errs []Error
+	for _, line := range strings.Split(string(output), "\n") {
+		m := tscErrorPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		lineNum, _ := strconv.Atoi(m[2])
+		col, _ := strconv.Atoi(m[3])
+		errs = append(errs, Error{File: m[1], Line: lineNum, Column: col, Message: m[4] + ": " + m[5]})
+	}
+	return errs
+}
+
+// app/models/user.rb:12: syntax error, unexpected end-of-input
+// ruby: app/models/user.rb:12: syntax errors found (SyntaxError)
+var rubyErrorPattern = regexp.MustCompile(`^(?:ruby: )?(.+?\.(?:rb|rake)):(\d+): (.*)$`)
+
+func parseRuby(output []byte) []Error {
+	var errs []Error
+	for _, line := range strings.Split(string(output), "\n") {
+		m := rubyErrorPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil || strings.HasPrefix(m[3], "warning:") {
+			continue
+		}
+		lineNum, _ := strconv.Atoi(m[2])
+		errs = append(errs, Error{File: m[1], Line: lineNum, Message: m[3]})
+	}
+	return errs
+}
+
+// py_compile reports each error as a short traceback: a location line such as
+// `File "app/views.py", line 12`, the source line, and the exception, such as
+// `SyntaxError: '(' was never closed`.
+var (
+	pythonLocationPattern = regexp.MustCompile(`^File "(.+?)", line (\d+)`)
+	pythonErrorPattern    = regexp.MustCompile(`^(?:Sorry: )?(\w+(?:Error|Exception)): (.*)$`)
+)
+
+func parsePython(output []byte) []Error {
+	var errs []Error
+	var current *Error
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if m := pythonLocationPattern.FindStringSubmatch(line); m != nil {
+			lineNum, _ := strconv.Atoi(m[2])
+			current = &Error{File: m[1], Line: lineNum}
+			continue
+		}
+		if m := pythonErrorPattern.FindStringSubmatch(line); m != nil && current != nil {
+			current.Message = m[1] + ": " + m[2]
+			errs = append(errs, *current)
+			current = nil
+		}
+	}
+	return errs
+}
+
+func fileExists(workDir, name string) bool {
+	_, err := os.Stat(filepath.Join(workDir, name))
+	return err == nil
+}
@@ -9,6 +9,10 @@
 // Core packages:
 //
 //   - review: Canonical review types and the Reviewer interface
+//   - review/lint: Reviewers backed by golangci-lint, eslint, tsc, rubocop and ruff
+//   - review/report: SARIF and GitHub pull request review exports of review results
+//   - review/chunk: Chunked, concurrent review of large diffs with a cross-cutting summary pass
+//   - review/vote: Self-consistency voting across independent reviews
 //   - checkpoint: Progress saving with git integration
 //   - memory: Cross-session learning (patterns, preferences, issues)
 //   - cost: Token usage and cost tracking
@@ -18,4 +22,8 @@
 //   - diffverify: Diff verification against review issues
 //   - contextpin: File dependency tracking and pinning
 //   - testrunner: Test framework detection and execution
+//   - compilecheck: Fast build and typecheck of changed files
+//   - autofix: Formatters and autofixers run on changed files
+//   - scope: Classification of changed files against the plan (scope creep)
+//   - codegen: Generated file detection and regeneration from changed sources
 package harness
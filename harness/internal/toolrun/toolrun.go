@@ -0,0 +1,103 @@
+// Package toolrun holds what the packages that run a repository's own tools
+// on changed files (review/lint, compilecheck, autofix) have in common:
+// picking the files a tool handles, running it, and normalizing the paths it
+// reports.
+package toolrun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// FilesFor returns the files (relative to workDir) that have one of the
+// extensions and still exist as regular files.
+func FilesFor(workDir string, files, extensions []string) []string {
+	var targets []string
+	for _, f := range files {
+		if !HasExtension(f, extensions) {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(workDir, f)); err != nil || info.IsDir() {
+			continue
+		}
+		targets = append(targets, f)
+	}
+	return targets
+}
+
+// HasExtension reports whether file has one of the extensions.
+func HasExtension(file string, extensions []string) bool {
+	ext := filepath.Ext(file)
+	for _, e := range extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// RelPath returns a path a tool reported relative to workDir, with forward
+// slashes. Relative paths are only cleaned.
+func RelPath(workDir, path string) string {
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(workDir, path); err == nil {
+			path = rel
+		} else if abs, aerr := filepath.Abs(workDir); aerr == nil {
+			if rel, err := filepath.Rel(abs, path); err == nil {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+// Run runs args in dir, writing its output to stdout and stderr (either may
+// be nil to discard it, or the same writer to combine them). A non-zero exit
+// status is returned as an error Exited recognizes.
+func Run(ctx context.Context, dir string, args []string, stdout, stderr io.Writer) error {
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = dir
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// Exited reports whether err is a tool's non-zero exit status, rather than
+// a failure to run it.
+func Exited(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr)
+}
+
+// Failure annotates a failed run's error with the first line of what the
+// tool wrote to stderr, if anything.
+func Failure(err error, stderr string) error {
+	if msg := strings.TrimSpace(stderr); msg != "" {
+		return fmt.Errorf("%w: %s", err, FirstLine(msg))
+	}
+	return err
+}
+
+// FirstLine returns the first line of s.
+func FirstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// FirstLines returns the first n lines of s, followed by "..." if there are
+// more.
+func FirstLines(s string, n int) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	if len(lines) > n {
+		lines = append(lines[:n], "...")
+	}
+	return strings.Join(lines, "\n")
+}
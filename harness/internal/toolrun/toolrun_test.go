@@ -0,0 +1,75 @@
+package toolrun
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/philjestin/boatman-ecosystem/harness/internal/testutil"
+)
+
+func TestFilesFor(t *testing.T) {
+	dir := t.TempDir()
+	testutil.WriteFiles(t, dir, map[string]string{
+		"a.go":       "package a\n",
+		"b.ts":       "export {}\n",
+		"dir.go/x":   "not a Go file\n",
+		"sub/c.go":   "package sub\n",
+		"sub/readme": "hi\n",
+	})
+
+	got := FilesFor(dir, []string{"a.go", "b.ts", "dir.go", "sub/c.go", "deleted.go", "sub/readme"}, []string{".go"})
+	if want := []string{"a.go", "sub/c.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FilesFor = %v, want %v", got, want)
+	}
+}
+
+func TestRelPath(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		filepath.Join(dir, "pkg", "a.go"): "pkg/a.go",
+		"./pkg/../pkg/b.go":               "pkg/b.go",
+		"c.go":                            "c.go",
+	}
+	for in, want := range tests {
+		if got := RelPath(dir, in); got != want {
+			t.Errorf("RelPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not installed")
+	}
+	ctx := context.Background()
+
+	var out bytes.Buffer
+	err := Run(ctx, t.TempDir(), []string{"sh", "-c", "echo out; echo err >&2; exit 3"}, &out, &out)
+	if !Exited(err) {
+		t.Fatalf("expected an exit error, got %v", err)
+	}
+	if got := out.String(); got != "out\nerr\n" {
+		t.Errorf("combined output = %q", got)
+	}
+
+	err = Run(ctx, t.TempDir(), []string{"no-such-tool-xyz"}, nil, nil)
+	if err == nil || Exited(err) {
+		t.Fatalf("expected a failure to run, got %v", err)
+	}
+
+	failure := Failure(errors.New("exit status 1"), "\nfirst problem\nsecond problem\n")
+	if failure.Error() != "exit status 1: first problem" {
+		t.Errorf("Failure = %q", failure)
+	}
+	if got := FirstLines("a\nb\nc\n", 2); got != "a\nb\n..." {
+		t.Errorf("FirstLines = %q", got)
+	}
+	if got := FirstLine("x\ny"); got != "x" {
+		t.Errorf("FirstLine = %q", got)
+	}
+}
@@ -0,0 +1,196 @@
+// Package udiff splits unified diffs (git diff, gh pr diff, diff -u) into
+// per-file sections. It is the one diff parser the harness packages share.
+package udiff
+
+import (
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Section is one file's part of a diff.
+type Section struct {
+	Path    string // Path after the change; the old path for a deleted file
+	OldPath string // Path before the change; differs from Path for renames
+	Deleted bool   // The change deletes the file
+	Text    string // The section's lines, headers included
+	Lines   int    // Changed (added or removed) lines
+	Size    int    // Non-empty lines, headers included
+}
+
+// Parse splits a diff into per-file sections. Git diffs are split at
+// "diff --git" headers; plain unified diffs at each "---"/"+++" pair. Lines
+// inside a hunk are never taken for headers, so an added "++ x" or removed
+// "-- x" line doesn't start or rename a section. Text before the first file
+// is dropped.
+func Parse(diff string) []Section {
+	gitDiff := strings.HasPrefix(diff, "diff --git ") || strings.Contains(diff, "\ndiff --git ")
+
+	var sections []Section
+	var current *Section
+	var text strings.Builder
+
+	finish := func() {
+		if current != nil {
+			current.Text = text.String()
+			if current.OldPath == "" {
+				current.OldPath = current.Path
+			}
+			sections = append(sections, *current)
+		}
+		text.Reset()
+	}
+
+	// Lines left in the current hunk, from its "@@ -a,b +c,d @@" header. In
+	// a git diff, or after a header without counts, the hunk stays open until
+	// the next file: hunk lines can't start with "diff --git", and hand-edited
+	// diffs often have counts that are off.
+	oldLeft, newLeft := 0, 0
+	openHunk := false
+	inHunk := func() bool { return openHunk || oldLeft > 0 || newLeft > 0 }
+
+	lines := strings.SplitAfter(diff, "\n")
+	for i, line := range lines {
+		bare := strings.TrimRight(line, "\n")
+		var startsFile bool
+		if gitDiff {
+			startsFile = strings.HasPrefix(bare, "diff --git ")
+		} else {
+			startsFile = !inHunk() && strings.HasPrefix(bare, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ")
+		}
+		if startsFile {
+			finish()
+			current = &Section{}
+			oldLeft, newLeft, openHunk = 0, 0, false
+		}
+		if current == nil {
+			continue // Preamble before the first file
+		}
+
+		text.WriteString(line)
+		if bare != "" {
+			current.Size++
+		}
+
+		if inHunk() {
+			switch {
+			case strings.HasPrefix(bare, "@@"):
+				// A new hunk; handled below
+			case strings.HasPrefix(bare, "+"):
+				current.Lines++
+				newLeft--
+				continue
+			case strings.HasPrefix(bare, "-"):
+				current.Lines++
+				oldLeft--
+				continue
+			case strings.HasPrefix(bare, " "), bare == "":
+				oldLeft--
+				newLeft--
+				continue
+			default:
+				continue // "\ No newline at end of file"
+			}
+		}
+
+		switch {
+		case strings.HasPrefix(bare, "@@"):
+			var ok bool
+			oldLeft, newLeft, ok = HunkCounts(bare)
+			openHunk = gitDiff || !ok
+		case strings.HasPrefix(bare, "diff --git "):
+			if a, b, ok := gitPaths(bare); ok {
+				current.OldPath, current.Path = a, b
+			}
+		case strings.HasPrefix(bare, "+++ "):
+			if p := HeaderPath(bare[4:]); p != "" {
+				current.Path = p
+			} else {
+				current.Deleted = true
+			}
+		case strings.HasPrefix(bare, "--- "):
+			if p := HeaderPath(bare[4:]); p != "" {
+				current.OldPath = p
+				if current.Path == "" {
+					current.Path = p
+				}
+			}
+		case strings.HasPrefix(bare, "rename from "):
+			current.OldPath = strings.TrimPrefix(bare, "rename from ")
+		case strings.HasPrefix(bare, "rename to "):
+			current.Path = strings.TrimPrefix(bare, "rename to ")
+		case strings.HasPrefix(bare, "deleted file mode"):
+			current.Deleted = true
+		}
+	}
+	finish()
+
+	return sections
+}
+
+// HunkCounts returns the old and new line counts of a "@@ -a,b +c,d @@"
+// header; a missing count is 1.
+func HunkCounts(header string) (oldCount, newCount int, ok bool) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, false
+	}
+	count := func(r string) (int, bool) {
+		_, n, found := strings.Cut(r[1:], ",")
+		if !found {
+			return 1, true
+		}
+		c, err := strconv.Atoi(n)
+		return c, err == nil
+	}
+	oldCount, ok1 := count(fields[1])
+	newCount, ok2 := count(fields[2])
+	return oldCount, newCount, ok1 && ok2
+}
+
+// HunkStart returns the first new-file line of a "@@ -a,b +c,d @@" header.
+func HunkStart(header string) int {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0
+	}
+	start, _, _ := strings.Cut(fields[2][1:], ",")
+	n, _ := strconv.Atoi(start)
+	return n
+}
+
+// HeaderPath returns the path of a "---" or "+++" line, without the
+// "---"/"+++" prefix: the a/ or b/ prefix and any timestamp are dropped, and
+// /dev/null is "".
+func HeaderPath(s string) string {
+	if i := strings.IndexByte(s, '\t'); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimSpace(s)
+	if s == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(s, "a/") || strings.HasPrefix(s, "b/") {
+		s = s[2:]
+	}
+	return s
+}
+
+// CleanPath normalizes a path relative to the repository root: forward
+// slashes, cleaned, without a leading "./". A blank path is "".
+func CleanPath(p string) string {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return ""
+	}
+	return strings.TrimPrefix(path.Clean(strings.ReplaceAll(p, "\\", "/")), "./")
+}
+
+// gitPaths parses "diff --git a/<old> b/<new>"
+func gitPaths(header string) (string, string, bool) {
+	a, b, ok := strings.Cut(strings.TrimPrefix(header, "diff --git "), " b/")
+	if !ok {
+		return "", "", false
+	}
+	return strings.TrimPrefix(a, "a/"), b, true
+}
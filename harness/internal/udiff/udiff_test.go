@@ -0,0 +1,94 @@
+package udiff
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func summarize(sections []Section) []string {
+	var got []string
+	for _, s := range sections {
+		got = append(got, fmt.Sprintf("%s<-%s:%d deleted=%v", s.Path, s.OldPath, s.Lines, s.Deleted))
+	}
+	return got
+}
+
+func TestParseGitDiff(t *testing.T) {
+	diff := "diff --git a/pkg/a.go b/pkg/a.go\nindex 1..2 100644\n--- a/pkg/a.go\n+++ b/pkg/a.go\n" +
+		"@@ -1,2 +1,2 @@\n-package a\n+package a // changed\n context\n" +
+		"diff --git a/old.rb b/old.rb\ndeleted file mode 100644\n--- a/old.rb\n+++ /dev/null\n@@ -1 +0,0 @@\n-puts 1\n" +
+		"diff --git a/web/app.ts b/web/app.ts\nnew file mode 100644\n--- /dev/null\n+++ b/web/app.ts\n@@ -0,0 +1 @@\n+export const x = 1\n" +
+		"diff --git a/src/old.go b/src/new.go\nsimilarity index 100%\nrename from src/old.go\nrename to src/new.go\n"
+
+	sections := Parse(diff)
+	want := []string{
+		"pkg/a.go<-pkg/a.go:2 deleted=false",
+		"old.rb<-old.rb:1 deleted=true",
+		"web/app.ts<-web/app.ts:1 deleted=false",
+		"src/new.go<-src/old.go:0 deleted=false",
+	}
+	if got := summarize(sections); !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %v, want %v", got, want)
+	}
+	if sections[0].Text[:len("diff --git")] != "diff --git" || sections[0].Size != 8 {
+		t.Errorf("unexpected first section (size %d):\n%s", sections[0].Size, sections[0].Text)
+	}
+}
+
+func TestParsePlainDiff(t *testing.T) {
+	diff := "preamble\n--- a/one.txt\t2024-01-01\n+++ b/one.txt\t2024-01-02\n@@ -1 +1 @@\n-old\n+new\n" +
+		"--- /dev/null\n+++ b/two/new.txt\n@@ -0,0 +1 @@\n+added\n" +
+		"--- a/gone.txt\n+++ /dev/null\n@@ -1 +0,0 @@\n-bye\n"
+
+	want := []string{
+		"one.txt<-one.txt:2 deleted=false",
+		"two/new.txt<-two/new.txt:1 deleted=false",
+		"gone.txt<-gone.txt:1 deleted=true",
+	}
+	if got := summarize(Parse(diff)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %v, want %v", got, want)
+	}
+}
+
+func TestParseIgnoresHeadersInHunks(t *testing.T) {
+	// An added "++ b/x" line reads as "+++ b/x", a removed "-- a/y" as "--- a/y"
+	git := "diff --git a/notes.md b/notes.md\n--- a/notes.md\n+++ b/notes.md\n@@ -1,2 +1,2 @@\n" +
+		"--- a/y\n+++ b/x\n context\n" +
+		"diff --git a/next.go b/next.go\n--- a/next.go\n+++ b/next.go\n@@ -1 +1 @@\n-old\n+new\n"
+	plain := "--- a/notes.md\n+++ b/notes.md\n@@ -1,2 +1,2 @@\n--- a/y\n+++ b/x\n context\n" +
+		"--- a/next.go\n+++ b/next.go\n@@ -1 +1 @@\n-old\n+new\n"
+
+	want := []string{"notes.md<-notes.md:2 deleted=false", "next.go<-next.go:2 deleted=false"}
+	for name, diff := range map[string]string{"git": git, "plain": plain} {
+		if got := summarize(Parse(diff)); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Parse() = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestHunkHeaders(t *testing.T) {
+	if o, n, ok := HunkCounts("@@ -3,4 +5 @@ func main() {"); !ok || o != 4 || n != 1 {
+		t.Errorf("HunkCounts = %d, %d, %v", o, n, ok)
+	}
+	if _, _, ok := HunkCounts("@@ bogus"); ok {
+		t.Error("expected a malformed header to fail")
+	}
+	if got := HunkStart("@@ -3,4 +12,7 @@"); got != 12 {
+		t.Errorf("HunkStart = %d, want 12", got)
+	}
+}
+
+func TestPaths(t *testing.T) {
+	if got := HeaderPath("b/pkg/a.go\t2024-01-01"); got != "pkg/a.go" {
+		t.Errorf("HeaderPath = %q", got)
+	}
+	if got := HeaderPath("/dev/null"); got != "" {
+		t.Errorf("HeaderPath(/dev/null) = %q", got)
+	}
+	for in, want := range map[string]string{"./pkg//a.go": "pkg/a.go", `pkg\b.go`: "pkg/b.go", " ": ""} {
+		if got := CleanPath(in); got != want {
+			t.Errorf("CleanPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package review
+
+import (
+	"context"
+	"strings"
+)
+
+// Chain returns a Reviewer that runs reviewers in order and stops at the first
+// one that fails, so cheap deterministic reviewers (linters, type checkers)
+// can block a change before an expensive one (an LLM) is spent on it.
+//
+// When every reviewer passes, the result is the last reviewer's, with the
+// issues the earlier ones reported ahead of its own.
+func Chain(reviewers ...Reviewer) Reviewer {
+	return chain(reviewers)
+}
+
+type chain []Reviewer
+
+func (c chain) Review(ctx context.Context, diff string, context string) (*ReviewResult, error) {
+	var earlier []Issue
+	var summaries []string
+	var result *ReviewResult
+	for _, reviewer := range c {
+		var err error
+		result, err = reviewer.Review(ctx, diff, context)
+		if err != nil {
+			return nil, err
+		}
+		if !result.Passed {
+			return result, nil
+		}
+		earlier = append(earlier, result.Issues...)
+		if result.Summary != "" {
+			summaries = append(summaries, result.Summary)
+		}
+	}
+	if result == nil {
+		return &ReviewResult{Passed: true, Score: 100}, nil
+	}
+
+	merged := *result
+	merged.Issues = earlier
+	merged.Summary = strings.Join(summaries, "\n")
+	return &merged, nil
+}
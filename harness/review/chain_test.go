@@ -0,0 +1,70 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type stubReviewer struct {
+	result *ReviewResult
+	err    error
+	calls  int
+}
+
+func (s *stubReviewer) Review(ctx context.Context, diff string, context string) (*ReviewResult, error) {
+	s.calls++
+	return s.result, s.err
+}
+
+func TestChain_StopsAtFirstFailure(t *testing.T) {
+	lint := &stubReviewer{result: &ReviewResult{Passed: false, Summary: "lint failed", Issues: []Issue{{Severity: "major"}}}}
+	llm := &stubReviewer{result: &ReviewResult{Passed: true}}
+
+	result, err := Chain(lint, llm).Review(context.Background(), "diff", "ctx")
+	if err != nil {
+		t.Fatalf("Review() error = %v", err)
+	}
+
+	if result.Passed || result.Summary != "lint failed" {
+		t.Errorf("Expected the lint failure, got %+v", result)
+	}
+	if llm.calls != 0 {
+		t.Error("Expected the LLM reviewer not to run")
+	}
+}
+
+func TestChain_MergesPassingResults(t *testing.T) {
+	lint := &stubReviewer{result: &ReviewResult{Passed: true, Summary: "eslint: 1 issue", Issues: []Issue{{Severity: "minor", File: "a.js"}}}}
+	llm := &stubReviewer{result: &ReviewResult{Passed: true, Score: 90, Summary: "Looks good", Issues: []Issue{{Severity: "minor", File: "b.js"}}, Guidance: "ship it"}}
+
+	result, err := Chain(lint, llm).Review(context.Background(), "diff", "ctx")
+	if err != nil {
+		t.Fatalf("Review() error = %v", err)
+	}
+
+	if !result.Passed || result.Score != 90 || result.Guidance != "ship it" {
+		t.Errorf("Expected the last reviewer's verdict, got %+v", result)
+	}
+	if len(result.Issues) != 2 || result.Issues[0].File != "a.js" {
+		t.Errorf("Expected issues from both reviewers, got %+v", result.Issues)
+	}
+	if result.Summary != "eslint: 1 issue\nLooks good" {
+		t.Errorf("Unexpected summary %q", result.Summary)
+	}
+	if len(llm.result.Issues) != 1 {
+		t.Error("Chain modified the reviewer's result")
+	}
+}
+
+func TestChain_ReturnsErrors(t *testing.T) {
+	failing := &stubReviewer{err: errors.New("boom")}
+	llm := &stubReviewer{result: &ReviewResult{Passed: true}}
+
+	if _, err := Chain(failing, llm).Review(context.Background(), "diff", "ctx"); err == nil {
+		t.Error("Expected an error")
+	}
+	if llm.calls != 0 {
+		t.Error("Expected the chain to stop at the error")
+	}
+}
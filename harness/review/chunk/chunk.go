@@ -0,0 +1,358 @@
+// Package chunk provides a review.Reviewer that reviews large diffs in
+// parts. A single prompt over a large diff is either truncated or reviewed
+// shallowly; the chunking reviewer instead splits the diff into chunks of
+// related files, reviews them concurrently, runs a cross-cutting summary pass
+// over the whole change, and merges the results:
+//
+//   - Issues are de-duplicated with issuetracker, so a problem reported by
+//     several passes appears once.
+//   - Score is the chunk scores weighted by changed lines, blended with the
+//     summary pass score.
+//   - The review passes only if every pass does.
+package chunk
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/philjestin/boatman-ecosystem/harness/contextpin"
+	"github.com/philjestin/boatman-ecosystem/harness/internal/udiff"
+	"github.com/philjestin/boatman-ecosystem/harness/issuetracker"
+	"github.com/philjestin/boatman-ecosystem/harness/review"
+)
+
+// Defaults for Options.
+const (
+	DefaultMaxLines         = 800
+	DefaultConcurrency      = 4
+	DefaultSummaryWeight    = 0.2
+	DefaultSummaryFileLines = 40
+)
+
+// Options configures a Reviewer.
+type Options struct {
+	// MaxLines is the most diff lines per chunk. Diffs up to MaxLines are
+	// reviewed in a single pass.
+	MaxLines int
+
+	// Concurrency is how many chunks are reviewed at once.
+	Concurrency int
+
+	// Graph groups files that depend on each other into the same chunk. Nil
+	// groups by directory only.
+	Graph Graph
+
+	// Summary reviews the change as a whole for cross-cutting issues. Nil
+	// uses the chunk reviewer.
+	Summary review.Reviewer
+
+	// SummaryWeight is the summary pass's share of the Score, from 0 to 1.
+	SummaryWeight float64
+
+	// SummaryFileLines is how many lines of each file's diff the summary
+	// pass sees; it works from the chunk reviews, not the full diff.
+	SummaryFileLines int
+}
+
+func (o Options) withDefaults() Options {
+	if o.MaxLines <= 0 {
+		o.MaxLines = DefaultMaxLines
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultConcurrency
+	}
+	if o.SummaryWeight <= 0 || o.SummaryWeight > 1 {
+		o.SummaryWeight = DefaultSummaryWeight
+	}
+	if o.SummaryFileLines <= 0 {
+		o.SummaryFileLines = DefaultSummaryFileLines
+	}
+	return o
+}
+
+// Reviewer reviews large diffs in chunks with another reviewer.
+type Reviewer struct {
+	inner review.Reviewer
+	opts  Options
+}
+
+// New creates a chunking Reviewer around inner.
+func New(inner review.Reviewer, opts Options) *Reviewer {
+	return &Reviewer{inner: inner, opts: opts.withDefaults()}
+}
+
+// NewForWorkDir creates a chunking Reviewer that groups files using the
+// import graph of the changed files in workDir.
+func NewForWorkDir(inner review.Reviewer, workDir string, opts Options) *Reviewer {
+	r := New(inner, opts)
+	if r.opts.Graph == nil {
+		r.opts.Graph = &lazyGraph{pinner: contextpin.New(workDir)}
+	}
+	return r
+}
+
+// Review reviews diff, in chunks when it is larger than MaxLines.
+func (r *Reviewer) Review(ctx context.Context, diff string, reviewContext string) (*review.ReviewResult, error) {
+	if countLines(diff) <= r.opts.MaxLines {
+		return r.inner.Review(ctx, diff, reviewContext)
+	}
+
+	if g, ok := r.opts.Graph.(*lazyGraph); ok {
+		g.analyze(udiff.Parse(diff))
+	}
+	chunks := Split(diff, r.opts.Graph, r.opts.MaxLines)
+	if len(chunks) <= 1 {
+		return r.inner.Review(ctx, diff, reviewContext)
+	}
+
+	results, err := r.reviewChunks(ctx, chunks, reviewContext)
+	if err != nil {
+		return nil, err
+	}
+
+	summaryReviewer := r.opts.Summary
+	if summaryReviewer == nil {
+		summaryReviewer = r.inner
+	}
+	summary, err := summaryReviewer.Review(ctx, outline(diff, r.opts.SummaryFileLines), summaryContext(reviewContext, chunks, results))
+	if err != nil {
+		return nil, fmt.Errorf("summary review: %w", err)
+	}
+
+	return merge(chunks, results, summary, r.opts.SummaryWeight), nil
+}
+
+// reviewChunks reviews the chunks concurrently, returning results in chunk order
+func (r *Reviewer) reviewChunks(ctx context.Context, chunks []Chunk, reviewContext string) ([]*review.ReviewResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*review.ReviewResult, len(chunks))
+	errs := make([]error, len(chunks))
+	sem := make(chan struct{}, r.opts.Concurrency)
+	var wg sync.WaitGroup
+
+	for i, c := range chunks {
+		wg.Add(1)
+		go func(i int, c Chunk) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+
+			result, err := r.inner.Review(ctx, c.Diff, chunkContext(reviewContext, chunks, i))
+			if err == nil && result == nil {
+				err = fmt.Errorf("no result")
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("review of part %d (%s): %w", i+1, strings.Join(c.Files, ", "), err)
+				cancel() // One failed part fails the review; stop the rest
+				return
+			}
+			results[i] = result
+		}(i, c)
+	}
+	wg.Wait()
+
+	// Report the part that failed rather than the parts it cancelled
+	for _, err := range errs {
+		if err != nil && err != context.Canceled {
+			return nil, err
+		}
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+func chunkContext(reviewContext string, chunks []Chunk, i int) string {
+	var sb strings.Builder
+	sb.WriteString(reviewContext)
+	fmt.Fprintf(&sb, "\n\n## Review scope\n\nThis change is too large to review at once, so it is reviewed in %d parts. "+
+		"This is part %d, covering:\n", len(chunks), i+1)
+	for _, f := range chunks[i].Files {
+		fmt.Fprintf(&sb, "- %s\n", f)
+	}
+	sb.WriteString("\nOther parts cover the remaining files:\n")
+	for j, c := range chunks {
+		if j == i {
+			continue
+		}
+		for _, f := range c.Files {
+			fmt.Fprintf(&sb, "- %s\n", f)
+		}
+	}
+	sb.WriteString("\nReview only the files in this part. Don't report problems that depend on code in other parts; " +
+		"a separate pass checks how the parts fit together.\n")
+	return sb.String()
+}
+
+func summaryContext(reviewContext string, chunks []Chunk, results []*review.ReviewResult) string {
+	var sb strings.Builder
+	sb.WriteString(reviewContext)
+	fmt.Fprintf(&sb, "\n\n## Review scope\n\nThis change was reviewed in %d parts; their findings are below. "+
+		"The diff shows only the start of each file's changes. Look for cross-cutting problems the parts can't see: "+
+		"changes that are inconsistent between files, callers or tests that weren't updated, missing pieces "+
+		"(migrations, configuration, documentation), and contract mismatches between components. "+
+		"Don't repeat the issues already found.\n", len(chunks))
+	for i, c := range chunks {
+		res := results[i]
+		verdict := "passed"
+		if !res.Passed {
+			verdict = "failed"
+		}
+		fmt.Fprintf(&sb, "\n### Part %d (%s): %s, score %d\n\n", i+1, strings.Join(c.Files, ", "), verdict, res.Score)
+		if s := strings.TrimSpace(res.Summary); s != "" {
+			sb.WriteString(s)
+			sb.WriteString("\n")
+		}
+		for _, issue := range res.Issues {
+			loc := issue.File
+			if issue.Line > 0 {
+				loc = fmt.Sprintf("%s:%d", issue.File, issue.Line)
+			}
+			fmt.Fprintf(&sb, "- [%s] %s: %s\n", issue.Severity, loc, issue.Description)
+		}
+	}
+	return sb.String()
+}
+
+// outline keeps the first lines of each file's diff so the summary pass sees
+// the shape of the whole change without the full size
+func outline(diff string, fileLines int) string {
+	var sb strings.Builder
+	for _, f := range udiff.Parse(diff) {
+		lines := strings.SplitAfter(f.Text, "\n")
+		if len(lines) > fileLines {
+			omitted := countLines(strings.Join(lines[fileLines:], ""))
+			lines = append(lines[:fileLines:fileLines], fmt.Sprintf("... (%d more lines)\n", omitted))
+		}
+		for _, l := range lines {
+			sb.WriteString(l)
+		}
+		if !strings.HasSuffix(sb.String(), "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// merge combines the chunk and summary results
+func merge(chunks []Chunk, results []*review.ReviewResult, summary *review.ReviewResult, summaryWeight float64) *review.ReviewResult {
+	merged := &review.ReviewResult{Passed: summary.Passed}
+
+	var all []review.Issue
+	var weighted, weights float64
+	var guidance []string
+	praise := map[string]bool{}
+	for i, res := range results {
+		merged.Passed = merged.Passed && res.Passed
+		all = append(all, res.Issues...)
+
+		// Weight by changed lines so a one-line chunk can't outweigh the bulk of the change
+		w := float64(chunks[i].Lines)
+		if w < 1 {
+			w = 1
+		}
+		weighted += w * float64(res.Score)
+		weights += w
+
+		for _, p := range res.Praise {
+			if !praise[p] {
+				praise[p] = true
+				merged.Praise = append(merged.Praise, p)
+			}
+		}
+		if g := strings.TrimSpace(res.Guidance); g != "" {
+			guidance = append(guidance, g)
+		}
+	}
+	all = append(all, summary.Issues...)
+	for _, p := range summary.Praise {
+		if !praise[p] {
+			praise[p] = true
+			merged.Praise = append(merged.Praise, p)
+		}
+	}
+	if g := strings.TrimSpace(summary.Guidance); g != "" {
+		guidance = append([]string{g}, guidance...)
+	}
+
+	merged.Issues = dedupe(all)
+	chunkScore := weighted / weights
+	merged.Score = int(math.Round((1-summaryWeight)*chunkScore + summaryWeight*float64(summary.Score)))
+	merged.Guidance = strings.Join(guidance, "\n\n")
+
+	// The verdict needs every pass, so it is only as certain as the least certain one
+	for _, res := range append([]*review.ReviewResult{summary}, results...) {
+		if res.Confidence > 0 && (merged.Confidence == 0 || res.Confidence < merged.Confidence) {
+			merged.Confidence = res.Confidence
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(strings.TrimSpace(summary.Summary))
+	fmt.Fprintf(&sb, "\n\nReviewed in %d parts:\n", len(chunks))
+	for i, res := range results {
+		fmt.Fprintf(&sb, "- Part %d (%d files, score %d): %s\n", i+1, len(chunks[i].Files), res.Score, firstLine(res.Summary))
+	}
+	merged.Summary = strings.TrimSpace(sb.String())
+
+	return merged
+}
+
+// dedupe drops issues the tracker considers the same as an earlier one
+func dedupe(issues []review.Issue) []review.Issue {
+	tracked := issuetracker.New().Track(issues)
+
+	seen := map[string]bool{}
+	var unique []review.Issue
+	for i, t := range tracked {
+		if seen[t.ID] {
+			continue
+		}
+		seen[t.ID] = true
+		unique = append(unique, issues[i])
+	}
+	return unique
+}
+
+// lazyGraph is the import graph of a work directory, analyzed for each
+// diff's changed files before it is split
+type lazyGraph struct {
+	pinner *contextpin.ContextPinner
+}
+
+func (g *lazyGraph) analyze(files []udiff.Section) {
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Path
+	}
+	g.pinner.AnalyzeFiles(paths)
+}
+
+func (g *lazyGraph) GetDependencies(file string) []string {
+	return g.pinner.GetDependencies(file)
+}
+
+func countLines(s string) int {
+	return strings.Count(strings.TrimRight(s, "\n"), "\n") + 1
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,147 @@
+package chunk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/philjestin/boatman-ecosystem/harness/review"
+)
+
+// stubReviewer returns a result per file prefix and records its calls
+type stubReviewer struct {
+	mu      sync.Mutex
+	calls   []string // review contexts
+	active  int32
+	peak    int32
+	results func(diff, reviewContext string) (*review.ReviewResult, error)
+}
+
+func (s *stubReviewer) Review(ctx context.Context, diff, reviewContext string) (*review.ReviewResult, error) {
+	n := atomic.AddInt32(&s.active, 1)
+	defer atomic.AddInt32(&s.active, -1)
+	for {
+		peak := atomic.LoadInt32(&s.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(&s.peak, peak, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	s.mu.Lock()
+	s.calls = append(s.calls, reviewContext)
+	s.mu.Unlock()
+	return s.results(diff, reviewContext)
+}
+
+func TestReviewSmallDiffIsSinglePass(t *testing.T) {
+	stub := &stubReviewer{results: func(diff, _ string) (*review.ReviewResult, error) {
+		return &review.ReviewResult{Passed: true, Score: 90}, nil
+	}}
+
+	result, err := New(stub, Options{MaxLines: 100}).Review(context.Background(), fileDiff("a/one.go", 10), "ticket")
+	if err != nil {
+		t.Fatalf("Review() error = %v", err)
+	}
+	if len(stub.calls) != 1 || stub.calls[0] != "ticket" {
+		t.Errorf("Expected one pass with the original context, got %q", stub.calls)
+	}
+	if result.Score != 90 {
+		t.Errorf("Expected the inner result, got %+v", result)
+	}
+}
+
+func TestReviewChunks(t *testing.T) {
+	diff := fileDiff("a/one.go", 30) + fileDiff("b/two.go", 10) + fileDiff("c/three.go", 10)
+
+	stub := &stubReviewer{results: func(diff, reviewContext string) (*review.ReviewResult, error) {
+		switch {
+		case strings.Contains(reviewContext, "reviewed in 3 parts; their findings"):
+			return &review.ReviewResult{
+				Passed: true, Score: 50, Summary: "Callers of one.go weren't updated",
+				Issues: []review.Issue{
+					{Severity: "major", File: "a/one.go", Line: 3, Description: "Nil pointer dereference in handler"},
+					{Severity: "major", File: "c/three.go", Description: "Caller still passes the old argument"},
+				},
+				Guidance: "Update the callers",
+			}, nil
+		case strings.HasPrefix(diff, "diff --git a/a/one.go"):
+			return &review.ReviewResult{
+				Passed: false, Score: 60, Summary: "Handler bug",
+				Issues: []review.Issue{{Severity: "major", File: "a/one.go", Line: 3, Description: "Nil pointer dereference in handler"}},
+				Praise: []string{"Clear names"},
+			}, nil
+		default:
+			return &review.ReviewResult{Passed: true, Score: 100, Summary: "Looks good", Praise: []string{"Clear names"}}, nil
+		}
+	}}
+
+	r := New(stub, Options{MaxLines: 20, Concurrency: 2, SummaryWeight: 0.5})
+	result, err := r.Review(context.Background(), diff, "ticket")
+	if err != nil {
+		t.Fatalf("Review() error = %v", err)
+	}
+
+	if len(stub.calls) != 4 {
+		t.Fatalf("Expected 3 parts and a summary pass, got %d calls", len(stub.calls))
+	}
+	if stub.peak > 2 {
+		t.Errorf("Expected at most 2 concurrent reviews, got %d", stub.peak)
+	}
+	for _, c := range stub.calls[:3] {
+		if !strings.HasPrefix(c, "ticket\n") || !strings.Contains(c, "reviewed in 3 parts. This is part") {
+			t.Errorf("Unexpected part context:\n%s", c)
+		}
+	}
+
+	if result.Passed {
+		t.Error("Expected the review to fail when a part fails")
+	}
+	if len(result.Issues) != 2 {
+		t.Errorf("Expected the repeated issue to be de-duplicated, got %+v", result.Issues)
+	}
+	// Parts weigh 30, 10 and 10 lines: (30*60 + 10*100 + 10*100) / 50 = 76,
+	// blended half and half with the summary score of 50
+	if result.Score != 63 {
+		t.Errorf("Expected a weighted score of 63, got %d", result.Score)
+	}
+	if len(result.Praise) != 1 {
+		t.Errorf("Expected praise to be de-duplicated, got %q", result.Praise)
+	}
+	if !strings.HasPrefix(result.Summary, "Callers of one.go weren't updated") || !strings.Contains(result.Summary, "Part 1 (1 files, score 60): Handler bug") {
+		t.Errorf("Unexpected summary:\n%s", result.Summary)
+	}
+	if result.Guidance != "Update the callers" {
+		t.Errorf("Unexpected guidance %q", result.Guidance)
+	}
+}
+
+func TestReviewChunkError(t *testing.T) {
+	diff := fileDiff("a/one.go", 30) + fileDiff("b/two.go", 30)
+	stub := &stubReviewer{results: func(diff, _ string) (*review.ReviewResult, error) {
+		if strings.HasPrefix(diff, "diff --git a/b/two.go") {
+			return nil, errors.New("rate limited")
+		}
+		return &review.ReviewResult{Passed: true, Score: 100}, nil
+	}}
+
+	_, err := New(stub, Options{MaxLines: 40}).Review(context.Background(), diff, "")
+	if err == nil || !strings.Contains(err.Error(), "part 2 (b/two.go): rate limited") {
+		t.Errorf("Expected the failing part's error, got %v", err)
+	}
+}
+
+func TestOutline(t *testing.T) {
+	out := outline(fileDiff("a/one.go", 50)+fileDiff("b/two.go", 2), 10)
+
+	if !strings.Contains(out, "+line 5\n... (44 more lines)\n") {
+		t.Errorf("Expected a truncated first file, got:\n%s", out)
+	}
+	if !strings.Contains(out, "+line 1\n") || !strings.Contains(out, "+++ b/b/two.go") {
+		t.Errorf("Expected the second file in full, got:\n%s", out)
+	}
+}
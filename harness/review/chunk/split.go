@@ -0,0 +1,151 @@
+package chunk
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/philjestin/boatman-ecosystem/harness/internal/udiff"
+)
+
+// Chunk is a part of a diff that is reviewed on its own.
+type Chunk struct {
+	Files []string // Paths of the files in the chunk, in diff order
+	Diff  string   // The files' sections of the diff
+	Lines int      // Changed (added or removed) lines
+}
+
+// Graph reports the files a file depends on. *contextpin.ContextPinner
+// implements it once the changed files have been analyzed.
+type Graph interface {
+	GetDependencies(file string) []string
+}
+
+// Split divides a unified diff into chunks of at most maxLines diff lines.
+//
+// Files are grouped by directory, and directories are merged when a file in
+// one depends on a changed file in another (according to graph, which may be
+// nil), so related changes are reviewed together. Groups are packed into
+// chunks in diff order. A group larger than maxLines is split between files,
+// and a single file larger than maxLines becomes a chunk of its own.
+func Split(diff string, graph Graph, maxLines int) []Chunk {
+	files := udiff.Parse(diff)
+	if len(files) == 0 {
+		return nil
+	}
+
+	groups := groupFiles(files, graph)
+
+	var chunks []Chunk
+	var current []udiff.Section
+	currentSize := 0
+	flush := func() {
+		if len(current) > 0 {
+			chunks = append(chunks, newChunk(current))
+			current, currentSize = nil, 0
+		}
+	}
+
+	for _, group := range groups {
+		groupSize := 0
+		for _, f := range group {
+			groupSize += f.Size
+		}
+
+		if groupSize > maxLines {
+			// Too big to keep together: pack its files one by one
+			for _, f := range group {
+				if currentSize > 0 && currentSize+f.Size > maxLines {
+					flush()
+				}
+				current = append(current, f)
+				currentSize += f.Size
+			}
+			continue
+		}
+
+		if currentSize > 0 && currentSize+groupSize > maxLines {
+			flush()
+		}
+		current = append(current, group...)
+		currentSize += groupSize
+	}
+	flush()
+
+	return chunks
+}
+
+func newChunk(files []udiff.Section) Chunk {
+	c := Chunk{}
+	var sb strings.Builder
+	for _, f := range files {
+		c.Files = append(c.Files, f.Path)
+		c.Lines += f.Lines
+		sb.WriteString(f.Text)
+		if !strings.HasSuffix(f.Text, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	c.Diff = sb.String()
+	return c
+}
+
+// groupFiles groups sections by directory, merging directories linked by
+// dependencies between changed files. Groups keep diff order.
+func groupFiles(files []udiff.Section, graph Graph) [][]udiff.Section {
+	parent := map[string]string{}
+	var find func(string) string
+	find = func(dir string) string {
+		if p, ok := parent[dir]; ok && p != dir {
+			root := find(p)
+			parent[dir] = root
+			return root
+		}
+		parent[dir] = dir
+		return dir
+	}
+	union := func(a, b string) {
+		ra, rb := find(a), find(b)
+		if ra != rb {
+			parent[rb] = ra
+		}
+	}
+
+	dirOf := make(map[string]string, len(files))
+	for _, f := range files {
+		dir := filepath.Dir(f.Path)
+		dirOf[f.Path] = dir
+		find(dir)
+	}
+	if graph != nil {
+		for _, f := range files {
+			for _, dep := range graph.GetDependencies(f.Path) {
+				if depDir, changed := dirOf[dep]; changed {
+					union(dirOf[f.Path], depDir)
+				}
+			}
+		}
+	}
+
+	order := map[string]int{}
+	byRoot := map[string][]udiff.Section{}
+	for i, f := range files {
+		root := find(dirOf[f.Path])
+		if _, ok := order[root]; !ok {
+			order[root] = i
+		}
+		byRoot[root] = append(byRoot[root], f)
+	}
+
+	roots := make([]string, 0, len(byRoot))
+	for root := range byRoot {
+		roots = append(roots, root)
+	}
+	sort.Slice(roots, func(i, j int) bool { return order[roots[i]] < order[roots[j]] })
+
+	groups := make([][]udiff.Section, len(roots))
+	for i, root := range roots {
+		groups[i] = byRoot[root]
+	}
+	return groups
+}
@@ -0,0 +1,101 @@
+package chunk
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fileDiff builds a git diff section adding n lines to path
+func fileDiff(path string, n int) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n@@ -0,0 +1,%d @@\n", path, path, path, path, n)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&sb, "+line %d\n", i)
+	}
+	return sb.String()
+}
+
+type stubGraph map[string][]string
+
+func (g stubGraph) GetDependencies(file string) []string { return g[file] }
+
+func chunkFiles(chunks []Chunk) [][]string {
+	files := make([][]string, len(chunks))
+	for i, c := range chunks {
+		files[i] = c.Files
+	}
+	return files
+}
+
+func TestSplitGroupsByDirectory(t *testing.T) {
+	diff := fileDiff("api/handler.go", 10) +
+		fileDiff("store/db.go", 10) +
+		fileDiff("api/routes.go", 10) +
+		fileDiff("store/cache.go", 10)
+
+	chunks := Split(diff, nil, 30)
+
+	want := [][]string{{"api/handler.go", "api/routes.go"}, {"store/db.go", "store/cache.go"}}
+	if got := chunkFiles(chunks); !reflect.DeepEqual(got, want) {
+		t.Errorf("Split() files = %v, want %v", got, want)
+	}
+	if chunks[0].Lines != 20 {
+		t.Errorf("Expected 20 changed lines in the first chunk, got %d", chunks[0].Lines)
+	}
+	if !strings.HasPrefix(chunks[1].Diff, "diff --git a/store/db.go") || !strings.Contains(chunks[1].Diff, "+++ b/store/cache.go") {
+		t.Errorf("Unexpected second chunk diff:\n%s", chunks[1].Diff)
+	}
+}
+
+func TestSplitKeepsDependenciesTogether(t *testing.T) {
+	diff := fileDiff("api/handler.go", 10) +
+		fileDiff("docs/readme.md", 10) +
+		fileDiff("store/db.go", 10)
+	graph := stubGraph{"api/handler.go": {"store/db.go", "fmt"}}
+
+	chunks := Split(diff, graph, 30)
+
+	want := [][]string{{"api/handler.go", "store/db.go"}, {"docs/readme.md"}}
+	if got := chunkFiles(chunks); !reflect.DeepEqual(got, want) {
+		t.Errorf("Split() files = %v, want %v", got, want)
+	}
+}
+
+func TestSplitPacksAndSplitsLargeGroups(t *testing.T) {
+	diff := fileDiff("a/one.go", 5) +
+		fileDiff("b/two.go", 5) +
+		fileDiff("c/big1.go", 20) +
+		fileDiff("c/big2.go", 30) +
+		fileDiff("d/huge.go", 100)
+
+	// Sizes include the 4 header lines: 9, 9, 24, 34 and 104
+	chunks := Split(diff, nil, 50)
+
+	want := [][]string{{"a/one.go", "b/two.go", "c/big1.go"}, {"c/big2.go"}, {"d/huge.go"}}
+	if got := chunkFiles(chunks); !reflect.DeepEqual(got, want) {
+		t.Errorf("Split() files = %v, want %v", got, want)
+	}
+}
+
+func TestSplitPlainUnifiedDiff(t *testing.T) {
+	diff := "--- a/one.txt\t2024-01-01\n+++ b/one.txt\t2024-01-02\n@@ -1 +1 @@\n-old\n+new\n" +
+		"--- /dev/null\n+++ b/two/new.txt\n@@ -0,0 +1 @@\n+added\n"
+
+	chunks := Split(diff, nil, 5)
+
+	want := [][]string{{"one.txt"}, {"two/new.txt"}}
+	if got := chunkFiles(chunks); !reflect.DeepEqual(got, want) {
+		t.Errorf("Split() files = %v, want %v", got, want)
+	}
+	if chunks[0].Lines != 2 || chunks[1].Lines != 1 {
+		t.Errorf("Unexpected changed lines: %d, %d", chunks[0].Lines, chunks[1].Lines)
+	}
+}
+
+func TestSplitEmpty(t *testing.T) {
+	if chunks := Split("", nil, 10); chunks != nil {
+		t.Errorf("Expected no chunks, got %v", chunks)
+	}
+}
@@ -0,0 +1,260 @@
+// Package lint provides review.Reviewer implementations backed by the static
+// analysis tools a repository is already configured for (golangci-lint,
+// eslint, rubocop, ruff, tsc). Only the files a diff touches are linted, and
+// each tool's findings are mapped onto review severities the same way:
+//
+//   - critical: the code does not parse or type-check
+//   - major: an error the tool would fail CI on
+//   - minor: a warning or style convention
+//
+// Critical and major findings fail the review, so running a lint Reviewer
+// ahead of an LLM reviewer (see review.Chain) blocks deterministic problems
+// without spending a model call on them.
+package lint
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/philjestin/boatman-ecosystem/harness/internal/toolrun"
+	"github.com/philjestin/boatman-ecosystem/harness/internal/udiff"
+	"github.com/philjestin/boatman-ecosystem/harness/review"
+)
+
+// Severity levels reported by linters, from most to least severe.
+const (
+	LevelFatal   = "fatal"   // Parse and type errors
+	LevelError   = "error"   // Rule violations the tool fails on
+	LevelWarning = "warning" // Warnings and conventions
+)
+
+// Finding is one problem reported by a linter.
+type Finding struct {
+	File    string // Relative to the work directory
+	Line    int
+	Rule    string // The tool's rule code, e.g. "errcheck", "no-unused-vars", "F401"
+	Level   string // LevelFatal, LevelError or LevelWarning
+	Message string
+	Fix     string // Suggested fix, if the tool offers one
+}
+
+// Linter describes a static analysis tool and how to run it.
+type Linter struct {
+	Name string
+
+	// Extensions are the file extensions the linter checks.
+	Extensions []string
+
+	// Detect reports whether the repository at workDir is configured for the linter.
+	Detect func(workDir string) bool
+
+	// Command returns the command line that lints files (relative to workDir).
+	Command func(workDir string, files []string) []string
+
+	// Parse converts the linter's output into findings.
+	Parse func(output []byte) ([]Finding, error)
+}
+
+// CommandFunc runs a command in dir and returns its standard output. A
+// non-zero exit status is not an error on its own, since linters exit non-zero
+// when they find problems.
+type CommandFunc func(ctx context.Context, dir string, args []string) ([]byte, error)
+
+// Reviewer runs linters over the files a diff changes.
+type Reviewer struct {
+	workDir string
+	linters []Linter
+	run     CommandFunc
+}
+
+// New creates a Reviewer using the linters the repository at workDir is
+// configured for.
+func New(workDir string) *Reviewer {
+	var detected []Linter
+	for _, l := range DefaultLinters() {
+		if l.Detect(workDir) {
+			detected = append(detected, l)
+		}
+	}
+	return NewWithLinters(workDir, detected...)
+}
+
+// NewWithLinters creates a Reviewer that runs the given linters without
+// detecting them.
+func NewWithLinters(workDir string, linters ...Linter) *Reviewer {
+	return &Reviewer{
+		workDir: workDir,
+		linters: linters,
+		run:     runCommand,
+	}
+}
+
+// SetCommandFunc replaces how linter commands are run.
+func (r *Reviewer) SetCommandFunc(run CommandFunc) {
+	r.run = run
+}
+
+// Linters returns the names of the linters the reviewer runs.
+func (r *Reviewer) Linters() []string {
+	names := make([]string, len(r.linters))
+	for i, l := range r.linters {
+		names[i] = l.Name
+	}
+	return names
+}
+
+// Review lints the files changed in diff.
+func (r *Reviewer) Review(ctx context.Context, diff string, _ string) (*review.ReviewResult, error) {
+	return r.ReviewFiles(ctx, ChangedFiles(diff))
+}
+
+// ReviewFiles lints the given files (relative to the work directory).
+// Linters that are not installed or whose output can't be read are skipped
+// and noted in the summary rather than failing the review.
+func (r *Reviewer) ReviewFiles(ctx context.Context, files []string) (*review.ReviewResult, error) {
+	var issues []review.Issue
+	var ran, skipped []string
+
+	for _, l := range r.linters {
+		targets := toolrun.FilesFor(r.workDir, files, l.Extensions)
+		if len(targets) == 0 {
+			continue
+		}
+
+		output, err := r.run(ctx, r.workDir, l.Command(r.workDir, targets))
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			skipped = append(skipped, fmt.Sprintf("%s (%v)", l.Name, err))
+			continue
+		}
+		findings, err := l.Parse(output)
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s (%v)", l.Name, err))
+			continue
+		}
+
+		ran = append(ran, l.Name)
+		wanted := make(map[string]bool, len(targets))
+		for _, f := range targets {
+			wanted[f] = true
+		}
+		for _, f := range findings {
+			f.File = toolrun.RelPath(r.workDir, f.File)
+			// Some tools lint whole packages or projects; keep only the changed files
+			if !wanted[f.File] {
+				continue
+			}
+			issues = append(issues, toIssue(l.Name, f))
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].File != issues[j].File {
+			return issues[i].File < issues[j].File
+		}
+		return issues[i].Line < issues[j].Line
+	})
+	return buildResult(issues, ran, skipped), nil
+}
+
+// Severity maps a linter level to a review severity.
+func Severity(level string) string {
+	switch level {
+	case LevelFatal:
+		return "critical"
+	case LevelError:
+		return "major"
+	default:
+		return "minor"
+	}
+}
+
+func toIssue(linter string, f Finding) review.Issue {
+	source := linter
+	if f.Rule != "" {
+		source += " " + f.Rule
+	}
+	return review.Issue{
+		Severity:    Severity(f.Level),
+		File:        f.File,
+		Line:        f.Line,
+		Description: fmt.Sprintf("%s (%s)", f.Message, source),
+		Suggestion:  f.Fix,
+		Code:        f.Rule,
+	}
+}
+
+func buildResult(issues []review.Issue, ran, skipped []string) *review.ReviewResult {
+	score := 100
+	blocking := 0
+	for _, issue := range issues {
+		switch issue.Severity {
+		case "critical":
+			score -= 20
+			blocking++
+		case "major":
+			score -= 10
+			blocking++
+		default:
+			score -= 2
+		}
+	}
+	if score < 0 {
+		score = 0
+	}
+
+	var summary string
+	switch {
+	case len(ran) == 0:
+		summary = "No linters ran"
+	case len(issues) == 0:
+		summary = fmt.Sprintf("%s: no issues", strings.Join(ran, ", "))
+	default:
+		summary = fmt.Sprintf("%s: %d issues (%d blocking)", strings.Join(ran, ", "), len(issues), blocking)
+	}
+	if len(skipped) > 0 {
+		summary += "; skipped " + strings.Join(skipped, ", ")
+	}
+
+	result := &review.ReviewResult{
+		Passed:  blocking == 0,
+		Score:   score,
+		Summary: summary,
+		Issues:  issues,
+	}
+	if blocking > 0 {
+		result.Guidance = "Fix the linter errors before anything else; they are reported by the project's own static analysis configuration."
+	}
+	return result
+}
+
+// ChangedFiles returns the files a unified diff adds or modifies, relative to
+// the repository root. Deleted files are left out.
+func ChangedFiles(diff string) []string {
+	var files []string
+	seen := map[string]bool{}
+	for _, s := range udiff.Parse(diff) {
+		if s.Deleted || s.Path == "" || seen[s.Path] {
+			continue
+		}
+		seen[s.Path] = true
+		files = append(files, s.Path)
+	}
+	return files
+}
+
+// runCommand runs args in dir. Exit errors are ignored when the tool wrote
+// output, since linters exit non-zero when they report problems.
+func runCommand(ctx context.Context, dir string, args []string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	err := toolrun.Run(ctx, dir, args, &stdout, &stderr)
+	if err != nil && (!toolrun.Exited(err) || stdout.Len() == 0) {
+		return nil, toolrun.Failure(err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
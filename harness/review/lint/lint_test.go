@@ -0,0 +1,245 @@
+package lint
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/philjestin/boatman-ecosystem/harness/internal/testutil"
+)
+
+func TestChangedFiles(t *testing.T) {
+	diff := `diff --git a/pkg/a.go b/pkg/a.go
+--- a/pkg/a.go
++++ b/pkg/a.go
+@@ -1 +1 @@
+-package a
++package a // changed
+diff --git a/old.rb b/old.rb
+deleted file mode 100644
+--- a/old.rb
++++ /dev/null
+@@ -1 +0,0 @@
+-puts 1
+diff --git a/web/app.ts b/web/app.ts
+new file mode 100644
+--- /dev/null
++++ b/web/app.ts
+@@ -0,0 +1 @@
++export const x = 1
+`
+	got := ChangedFiles(diff)
+	want := []string{"pkg/a.go", "web/app.ts"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ChangedFiles() = %v, want %v", got, want)
+	}
+}
+
+func TestNew_DetectsConfiguredLinters(t *testing.T) {
+	dir := t.TempDir()
+	testutil.WriteFiles(t, dir, map[string]string{
+		".golangci.yml":  "linters:\n  enable: [errcheck]\n",
+		"tsconfig.json":  "{}",
+		"package.json":   `{"name": "app", "eslintConfig": {"extends": "react-app"}}`,
+		"pyproject.toml": "[project]\nname = \"app\"\n",
+	})
+
+	got := New(dir).Linters()
+	want := []string{"golangci-lint", "eslint", "tsc"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Linters() = %v, want %v", got, want)
+	}
+}
+
+func TestGolangciLint_Command(t *testing.T) {
+	dir := t.TempDir()
+	testutil.WriteFiles(t, dir, map[string]string{".golangci.yml": "linters:\n  enable: [errcheck]\n"})
+
+	args := GolangciLint().Command(dir, []string{"main.go", "pkg/a.go", "pkg/b.go"})
+	want := []string{"golangci-lint", "run", "--out-format=json", ".", "./pkg"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("Command() = %v, want %v", args, want)
+	}
+
+	testutil.WriteFiles(t, dir, map[string]string{".golangci.yml": "version: \"2\"\nlinters:\n  default: standard\n"})
+	args = GolangciLint().Command(dir, []string{"main.go"})
+	if args[2] != "--output.json.path=stdout" {
+		t.Errorf("Expected v2 output flags, got %v", args)
+	}
+}
+
+func TestParsers(t *testing.T) {
+	tests := []struct {
+		name   string
+		parse  func([]byte) ([]Finding, error)
+		output string
+		want   []Finding
+	}{
+		{
+			name:  "golangci-lint",
+			parse: parseGolangciLint,
+			output: `{"Issues":[
+				{"FromLinter":"errcheck","Text":"Error return value is not checked","Severity":"","Pos":{"Filename":"pkg/a.go","Line":12}},
+				{"FromLinter":"typecheck","Text":"undefined: foo","Pos":{"Filename":"pkg/b.go","Line":3}},
+				{"FromLinter":"godox","Text":"TODO found","Severity":"warning","Pos":{"Filename":"pkg/a.go","Line":1}}
+			],"Report":{}}`,
+			want: []Finding{
+				{File: "pkg/a.go", Line: 12, Rule: "errcheck", Level: LevelError, Message: "Error return value is not checked"},
+				{File: "pkg/b.go", Line: 3, Rule: "typecheck", Level: LevelFatal, Message: "undefined: foo"},
+				{File: "pkg/a.go", Line: 1, Rule: "godox", Level: LevelWarning, Message: "TODO found"},
+			},
+		},
+		{
+			name:  "eslint",
+			parse: parseESLint,
+			output: `[{"filePath":"/repo/src/a.js","messages":[
+				{"ruleId":"no-unused-vars","severity":2,"message":"'x' is defined but never used.","line":4},
+				{"ruleId":"eqeqeq","severity":1,"message":"Expected '==='.","line":9},
+				{"ruleId":null,"severity":2,"fatal":true,"message":"Parsing error: Unexpected token","line":20}
+			]}]`,
+			want: []Finding{
+				{File: "/repo/src/a.js", Line: 4, Rule: "no-unused-vars", Level: LevelError, Message: "'x' is defined but never used."},
+				{File: "/repo/src/a.js", Line: 9, Rule: "eqeqeq", Level: LevelWarning, Message: "Expected '==='."},
+				{File: "/repo/src/a.js", Line: 20, Level: LevelFatal, Message: "Parsing error: Unexpected token"},
+			},
+		},
+		{
+			name:  "tsc",
+			parse: parseTSC,
+			output: `src/app.ts(12,5): error TS2322: Type 'string' is not assignable to type 'number'.
+Found 1 error.`,
+			want: []Finding{
+				{File: "src/app.ts", Line: 12, Rule: "TS2322", Level: LevelFatal, Message: "Type 'string' is not assignable to type 'number'."},
+			},
+		},
+		{
+			name:  "rubocop",
+			parse: parseRuboCop,
+			output: `{"metadata":{},"files":[{"path":"app/models/user.rb","offenses":[
+				{"severity":"convention","message":"Style/StringLiterals: Prefer single-quoted strings.","cop_name":"Style/StringLiterals","correctable":true,"location":{"start_line":3,"line":3}},
+				{"severity":"error","message":"Lint/Syntax: unexpected end","cop_name":"Lint/Syntax","location":{"line":9}}
+			]}]}`,
+			want: []Finding{
+				{File: "app/models/user.rb", Line: 3, Rule: "Style/StringLiterals", Level: LevelWarning, Message: "Prefer single-quoted strings.", Fix: "Autocorrectable with rubocop -a"},
+				{File: "app/models/user.rb", Line: 9, Rule: "Lint/Syntax", Level: LevelError, Message: "unexpected end"},
+			},
+		},
+		{
+			name:  "ruff",
+			parse: parseRuff,
+			output: `[
+				{"code":"F401","message":"` + "`os`" + ` imported but unused","filename":"/repo/app.py","location":{"row":1,"column":8},"fix":{"message":"Remove unused import: ` + "`os`" + `"}},
+				{"code":null,"message":"SyntaxError: Expected an expression","filename":"/repo/app.py","location":{"row":7,"column":1},"fix":null}
+			]`,
+			want: []Finding{
+				{File: "/repo/app.py", Line: 1, Rule: "F401", Level: LevelError, Message: "`os` imported but unused", Fix: "Remove unused import: `os`"},
+				{File: "/repo/app.py", Line: 7, Level: LevelFatal, Message: "SyntaxError: Expected an expression"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parse([]byte(tt.output))
+			if err != nil {
+				t.Fatalf("parse error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parse() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsers_RejectGarbage(t *testing.T) {
+	if _, err := parseESLint([]byte("npm ERR! could not determine executable to run")); err == nil {
+		t.Error("Expected an error for output that isn't JSON")
+	}
+}
+
+func TestReviewer_ReviewsChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	testutil.WriteFiles(t, dir, map[string]string{
+		"src/a.js":   "let x = 1\n",
+		"src/b.ts":   "export const y: number = 'no'\n",
+		"README.md":  "docs\n",
+		"src/old.js": "",
+	})
+	os.Remove(filepath.Join(dir, "src/old.js"))
+
+	eslint := ESLint()
+	tsc := TypeScript()
+	reviewer := NewWithLinters(dir, eslint, tsc)
+
+	var commands [][]string
+	reviewer.SetCommandFunc(func(ctx context.Context, workDir string, args []string) ([]byte, error) {
+		commands = append(commands, args)
+		if args[2] == "eslint" {
+			return []byte(`[{"filePath":"` + filepath.Join(dir, "src/a.js") + `","messages":[
+				{"ruleId":"prefer-const","severity":1,"message":"'x' is never reassigned.","line":1}]}]`), nil
+		}
+		// tsc reports on the whole project, including unchanged files
+		return []byte("src/b.ts(1,14): error TS2322: Type 'string' is not assignable to type 'number'.\n" +
+			"src/untouched.ts(3,1): error TS2304: Cannot find name 'z'.\n"), nil
+	})
+
+	result, err := reviewer.ReviewFiles(context.Background(), []string{"src/a.js", "src/b.ts", "README.md", "src/old.js"})
+	if err != nil {
+		t.Fatalf("ReviewFiles() error = %v", err)
+	}
+
+	if len(commands) != 2 || strings.Join(commands[0][5:], " ") != "src/a.js src/b.ts" {
+		t.Errorf("Unexpected commands: %v", commands)
+	}
+	if result.Passed {
+		t.Error("Expected the type error to fail the review")
+	}
+	if len(result.Issues) != 2 {
+		t.Fatalf("Expected 2 issues on changed files, got %+v", result.Issues)
+	}
+	if issue := result.Issues[0]; issue.File != "src/a.js" || issue.Severity != "minor" || issue.Code != "prefer-const" {
+		t.Errorf("Unexpected eslint issue: %+v", issue)
+	}
+	if issue := result.Issues[1]; issue.File != "src/b.ts" || issue.Line != 1 || issue.Severity != "critical" ||
+		!strings.Contains(issue.Description, "(tsc TS2322)") {
+		t.Errorf("Unexpected tsc issue: %+v", issue)
+	}
+	if result.Summary != "eslint, tsc: 2 issues (1 blocking)" {
+		t.Errorf("Unexpected summary %q", result.Summary)
+	}
+}
+
+func TestReviewer_SkipsUnavailableLinters(t *testing.T) {
+	dir := t.TempDir()
+	testutil.WriteFiles(t, dir, map[string]string{"app.py": "import os\n"})
+
+	reviewer := NewWithLinters(dir, Ruff())
+	reviewer.SetCommandFunc(func(ctx context.Context, workDir string, args []string) ([]byte, error) {
+		return nil, errors.New("executable file not found in $PATH")
+	})
+
+	result, err := reviewer.ReviewFiles(context.Background(), []string{"app.py"})
+	if err != nil {
+		t.Fatalf("ReviewFiles() error = %v", err)
+	}
+	if !result.Passed || !strings.Contains(result.Summary, "skipped ruff") {
+		t.Errorf("Expected ruff to be skipped, got %+v", result)
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	for level, want := range map[string]string{
+		LevelFatal:   "critical",
+		LevelError:   "major",
+		LevelWarning: "minor",
+		"":           "minor",
+	} {
+		if got := Severity(level); got != want {
+			t.Errorf("Severity(%q) = %q, want %q", level, got, want)
+		}
+	}
+}
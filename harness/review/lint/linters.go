@@ -0,0 +1,365 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLinters returns the supported linters: golangci-lint, eslint,
+// rubocop, ruff and tsc.
+func DefaultLinters() []Linter {
+	return []Linter{GolangciLint(), ESLint(), TypeScript(), RuboCop(), Ruff()}
+}
+
+// GolangciLint runs golangci-lint on the packages of the changed Go files.
+func GolangciLint() Linter {
+	return Linter{
+		Name:       "golangci-lint",
+		Extensions: []string{".go"},
+		Detect: func(workDir string) bool {
+			return configFile(workDir, ".golangci.yml", ".golangci.yaml", ".golangci.toml", ".golangci.json") != ""
+		},
+		Command: func(workDir string, files []string) []string {
+			args := []string{"golangci-lint", "run"}
+			if golangciV2(workDir) {
+				args = append(args, "--output.json.path=stdout", "--output.text.path=")
+			} else {
+				args = append(args, "--out-format=json")
+			}
+			// golangci-lint lints packages, not files
+			return append(args, packageDirs(files)...)
+		},
+		Parse: parseGolangciLint,
+	}
+}
+
+// ESLint runs eslint on the changed JavaScript and TypeScript files.
+func ESLint() Linter {
+	return Linter{
+		Name:       "eslint",
+		Extensions: []string{".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx"},
+		Detect: func(workDir string) bool {
+			if configFile(workDir,
+				"eslint.config.js", "eslint.config.mjs", "eslint.config.cjs", "eslint.config.ts",
+				".eslintrc", ".eslintrc.js", ".eslintrc.cjs", ".eslintrc.json", ".eslintrc.yml", ".eslintrc.yaml") != "" {
+				return true
+			}
+			pkg, _ := os.ReadFile(filepath.Join(workDir, "package.json"))
+			return bytes.Contains(pkg, []byte(`"eslintConfig"`))
+		},
+		Command: func(workDir string, files []string) []string {
+			return append([]string{"npx", "--no-install", "eslint", "--format", "json"}, files...)
+		},
+		Parse: parseESLint,
+	}
+}
+
+// TypeScript type-checks the project with tsc --noEmit. tsc can't check
+// single files against the project's configuration, so the whole project is
+// checked and errors are filtered to the changed files.
+func TypeScript() Linter {
+	return Linter{
+		Name:       "tsc",
+		Extensions: []string{".ts", ".tsx"},
+		Detect: func(workDir string) bool {
+			return configFile(workDir, "tsconfig.json") != ""
+		},
+		Command: func(workDir string, files []string) []string {
+			return []string{"npx", "--no-install", "tsc", "--noEmit", "--pretty", "false", "-p", "tsconfig.json"}
+		},
+		Parse: parseTSC,
+	}
+}
+
+// RuboCop runs rubocop on the changed Ruby files, through Bundler when the
+// Gemfile includes it.
+func RuboCop() Linter {
+	return Linter{
+		Name:       "rubocop",
+		Extensions: []string{".rb", ".rake"},
+		Detect: func(workDir string) bool {
+			return configFile(workDir, ".rubocop.yml") != ""
+		},
+		Command: func(workDir string, files []string) []string {
+			args := []string{"rubocop", "--format", "json", "--force-exclusion"}
+			gemfile, _ := os.ReadFile(filepath.Join(workDir, "Gemfile"))
+			if bytes.Contains(gemfile, []byte("rubocop")) {
+				args = append([]string{"bundle", "exec"}, args...)
+			}
+			return append(args, files...)
+		},
+		Parse: parseRuboCop,
+	}
+}
+
+// Ruff runs ruff check on the changed Python files.
+func Ruff() Linter {
+	return Linter{
+		Name:       "ruff",
+		Extensions: []string{".py", ".pyi"},
+		Detect: func(workDir string) bool {
+			if configFile(workDir, "ruff.toml", ".ruff.toml") != "" {
+				return true
+			}
+			pyproject, _ := os.ReadFile(filepath.Join(workDir, "pyproject.toml"))
+			return bytes.Contains(pyproject, []byte("[tool.ruff"))
+		},
+		Command: func(workDir string, files []string) []string {
+			return append([]string{"ruff", "check", "--output-format", "json", "--force-exclude"}, files...)
+		},
+		Parse: parseRuff,
+	}
+}
+
+func parseGolangciLint(output []byte) ([]Finding, error) {
+	var report struct {
+		Issues []struct {
+			FromLinter string
+			Text       string
+			Severity   string
+			Pos        struct {
+				Filename string
+				Line     int
+			}
+			Replacement *struct {
+				NewLines []string
+			}
+		}
+	}
+	if err := json.Unmarshal(firstJSON(output), &report); err != nil {
+		return nil, err
+	}
+
+	findings := make([]Finding, 0, len(report.Issues))
+	for _, issue := range report.Issues {
+		level := LevelError
+		switch {
+		case issue.FromLinter == "typecheck":
+			level = LevelFatal
+		case issue.Severity == "warning" || issue.Severity == "info":
+			level = LevelWarning
+		}
+		f := Finding{
+			File:    issue.Pos.Filename,
+			Line:    issue.Pos.Line,
+			Rule:    issue.FromLinter,
+			Level:   level,
+			Message: issue.Text,
+		}
+		if issue.Replacement != nil && len(issue.Replacement.NewLines) > 0 {
+			f.Fix = "Replace with: " + strings.Join(issue.Replacement.NewLines, "\n")
+		}
+		findings = append(findings, f)
+	}
+	return findings, nil
+}
+
+func parseESLint(output []byte) ([]Finding, error) {
+	var results []struct {
+		FilePath string `json:"filePath"`
+		Messages []struct {
+			RuleID   string `json:"ruleId"`
+			Severity int    `json:"severity"`
+			Fatal    bool   `json:"fatal"`
+			Message  string `json:"message"`
+			Line     int    `json:"line"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(firstJSON(output), &results); err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for _, result := range results {
+		for _, msg := range result.Messages {
+			level := LevelWarning
+			switch {
+			case msg.Fatal:
+				level = LevelFatal
+			case msg.Severity == 2:
+				level = LevelError
+			}
+			findings = append(findings, Finding{
+				File:    result.FilePath,
+				Line:    msg.Line,
+				Rule:    msg.RuleID,
+				Level:   level,
+				Message: msg.Message,
+			})
+		}
+	}
+	return findings, nil
+}
+
+// src/app.ts(12,5): error TS2322: Type 'string' is not assignable to type 'number'.
+var tscLinePattern = regexp.MustCompile(`^(.+?)\((\d+),\d+\): (error|warning) (TS\d+): (.*)$`)
+
+func parseTSC(output []byte) ([]Finding, error) {
+	var findings []Finding
+	for _, line := range strings.Split(string(output), "\n") {
+		m := tscLinePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		lineNum, _ := strconv.Atoi(m[2])
+		level := LevelFatal
+		if m[3] == "warning" {
+			level = LevelWarning
+		}
+		findings = append(findings, Finding{
+			File:    m[1],
+			Line:    lineNum,
+			Rule:    m[4],
+			Level:   level,
+			Message: m[5],
+		})
+	}
+	return findings, nil
+}
+
+func parseRuboCop(output []byte) ([]Finding, error) {
+	var report struct {
+		Files []struct {
+			Path     string `json:"path"`
+			Offenses []struct {
+				Severity    string `json:"severity"`
+				Message     string `json:"message"`
+				CopName     string `json:"cop_name"`
+				Correctable bool   `json:"correctable"`
+				Location    struct {
+					Line      int `json:"line"`
+					StartLine int `json:"start_line"`
+				} `json:"location"`
+			} `json:"offenses"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(firstJSON(output), &report); err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for _, file := range report.Files {
+		for _, offense := range file.Offenses {
+			level := LevelWarning
+			switch offense.Severity {
+			case "fatal":
+				level = LevelFatal
+			case "error":
+				level = LevelError
+			}
+			line := offense.Location.StartLine
+			if line == 0 {
+				line = offense.Location.Line
+			}
+			f := Finding{
+				File:    file.Path,
+				Line:    line,
+				Rule:    offense.CopName,
+				Level:   level,
+				Message: strings.TrimPrefix(offense.Message, offense.CopName+": "),
+			}
+			if offense.Correctable {
+				f.Fix = "Autocorrectable with rubocop -a"
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
+
+func parseRuff(output []byte) ([]Finding, error) {
+	var results []struct {
+		Code     *string `json:"code"`
+		Message  string  `json:"message"`
+		Filename string  `json:"filename"`
+		Location struct {
+			Row int `json:"row"`
+		} `json:"location"`
+		Fix *struct {
+			Message string `json:"message"`
+		} `json:"fix"`
+	}
+	if err := json.Unmarshal(firstJSON(output), &results); err != nil {
+		return nil, err
+	}
+
+	findings := make([]Finding, 0, len(results))
+	for _, result := range results {
+		code := ""
+		if result.Code != nil {
+			code = *result.Code
+		}
+		// ruff has no severities: syntax errors (no code, or E999 before
+		// ruff 0.5) are fatal, and any other finding fails ruff check
+		level := LevelError
+		if code == "" || code == "E999" {
+			level = LevelFatal
+		}
+		f := Finding{
+			File:    result.Filename,
+			Line:    result.Location.Row,
+			Rule:    code,
+			Level:   level,
+			Message: result.Message,
+		}
+		if result.Fix != nil {
+			f.Fix = result.Fix.Message
+		}
+		findings = append(findings, f)
+	}
+	return findings, nil
+}
+
+// firstJSON skips anything a tool printed before its JSON report (npx and
+// Bundler notices, for example)
+func firstJSON(output []byte) []byte {
+	output = bytes.TrimSpace(output)
+	if i := bytes.IndexAny(output, "[{"); i > 0 {
+		output = output[i:]
+	}
+	if len(output) == 0 {
+		return []byte("null")
+	}
+	return output
+}
+
+func configFile(workDir string, names ...string) string {
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(workDir, name)); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+var golangciV2Pattern = regexp.MustCompile(`(?m)^\s*"?version"?\s*[:=]\s*["']?2`)
+
+// golangciV2 reports whether the golangci-lint configuration is for v2, which
+// changed the output flags
+func golangciV2(workDir string) bool {
+	name := configFile(workDir, ".golangci.yml", ".golangci.yaml", ".golangci.toml", ".golangci.json")
+	if name == "" {
+		return false
+	}
+	data, _ := os.ReadFile(filepath.Join(workDir, name))
+	return golangciV2Pattern.Match(data)
+}
+
+func packageDirs(files []string) []string {
+	seen := map[string]bool{}
+	for _, f := range files {
+		seen["./"+filepath.ToSlash(filepath.Dir(f))] = true
+	}
+	dirs := make([]string, 0, len(seen))
+	for dir := range seen {
+		dirs = append(dirs, strings.TrimSuffix(dir, "/."))
+	}
+	sort.Strings(dirs)
+	return dirs
+}
@@ -0,0 +1,117 @@
+package report
+
+import (
+	"strings"
+
+	"github.com/philjestin/boatman-ecosystem/harness/internal/udiff"
+)
+
+// DiffMap maps file lines to their positions in a unified diff, so review
+// issues (reported against lines of the changed files) can be anchored to the
+// diff a pull request shows.
+type DiffMap struct {
+	files []*fileDiff
+}
+
+type fileDiff struct {
+	path    string      // Path after the change
+	oldPath string      // Path before the change (differs for renames)
+	lines   map[int]int // New-file line -> diff position
+}
+
+// ParseDiffMap indexes a unified diff (as produced by git diff or gh pr diff).
+//
+// Positions follow GitHub's definition: the line just below a file's first
+// "@@" hunk header is position 1, and counting continues through later hunk
+// headers until the next file.
+func ParseDiffMap(diff string) *DiffMap {
+	m := &DiffMap{}
+	for _, s := range udiff.Parse(diff) {
+		f := &fileDiff{path: s.Path, oldPath: s.OldPath, lines: map[int]int{}}
+		m.files = append(m.files, f)
+
+		position := 0
+		newLine := 0
+		inHunk := false
+		for _, line := range strings.Split(s.Text, "\n") {
+			switch {
+			case strings.HasPrefix(line, "@@"):
+				if inHunk {
+					position++ // Later hunk headers count as diff lines
+				}
+				inHunk = true
+				newLine = udiff.HunkStart(line)
+			case !inHunk || line == "":
+				// File headers, or the section's trailing newline
+			default:
+				position++
+				switch line[0] {
+				case '+', ' ':
+					f.lines[newLine] = position
+					newLine++
+				}
+			}
+		}
+	}
+	return m
+}
+
+// Position returns the diff position of a line of a changed file, and the
+// file's path after the change. ok is false when the file is not in the diff
+// or the line is outside its hunks.
+func (m *DiffMap) Position(file string, line int) (path string, position int, ok bool) {
+	f := m.file(file)
+	if f == nil || line <= 0 {
+		return "", 0, false
+	}
+	position, ok = f.lines[line]
+	if !ok {
+		return "", 0, false
+	}
+	return f.path, position, true
+}
+
+// Path returns the path a file has after the diff, following renames, or the
+// cleaned path itself if the diff doesn't touch the file.
+func (m *DiffMap) Path(file string) string {
+	if f := m.file(file); f != nil {
+		return f.path
+	}
+	return cleanPath(file)
+}
+
+// Files returns the paths of the files in the diff.
+func (m *DiffMap) Files() []string {
+	paths := make([]string, len(m.files))
+	for i, f := range m.files {
+		paths[i] = f.path
+	}
+	return paths
+}
+
+func (m *DiffMap) file(name string) *fileDiff {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "./")
+	// Match the path as given first, so directories named a or b still work
+	for _, candidate := range []string{name, cleanPath(name)} {
+		for _, f := range m.files {
+			if f.path == candidate {
+				return f
+			}
+		}
+		for _, f := range m.files {
+			if f.oldPath == candidate {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+func cleanPath(path string) string {
+	path = udiff.CleanPath(path)
+	if strings.HasPrefix(path, "a/") || strings.HasPrefix(path, "b/") {
+		// Reviewers sometimes copy paths straight from the diff headers
+		path = path[2:]
+	}
+	return path
+}
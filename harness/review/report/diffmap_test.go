@@ -0,0 +1,103 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testDiff = `diff --git a/pkg/server.go b/pkg/server.go
+index 1111111..2222222 100644
+--- a/pkg/server.go
++++ b/pkg/server.go
+@@ -10,6 +10,7 @@ func Start() {
+ 	mux := http.NewServeMux()
+ 	mux.HandleFunc("/", index)
+-	mux.HandleFunc("/old", old)
++	mux.HandleFunc("/new", handler)
++	mux.HandleFunc("/health", health)
+ 	srv := &http.Server{Handler: mux}
+ 	return srv.ListenAndServe()
+ }
+@@ -40,3 +41,4 @@ func health(w http.ResponseWriter, r *http.Request) {
+ 	w.WriteHeader(http.StatusOK)
++	w.Write([]byte("ok"))
+ }
+\ No newline at end of file
+diff --git a/lib/old_name.rb b/lib/new_name.rb
+similarity index 90%
+rename from lib/old_name.rb
+rename to lib/new_name.rb
+--- a/lib/old_name.rb
++++ b/lib/new_name.rb
+@@ -1,2 +1,2 @@
+-class OldName
++class NewName
+ end
+diff --git a/web/app.ts b/web/app.ts
+new file mode 100644
+--- /dev/null
++++ b/web/app.ts
+@@ -0,0 +1,2 @@
++export const a = 1
++export const b = 2
+`
+
+func TestParseDiffMap_Positions(t *testing.T) {
+	m := ParseDiffMap(testDiff)
+
+	if got, want := m.Files(), []string{"pkg/server.go", "lib/new_name.rb", "web/app.ts"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Files() = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		file     string
+		line     int
+		wantPath string
+		wantPos  int
+		wantOK   bool
+	}{
+		{"pkg/server.go", 10, "pkg/server.go", 1, true},  // Context line
+		{"pkg/server.go", 12, "pkg/server.go", 4, true},  // Added line, after the removed one
+		{"pkg/server.go", 13, "pkg/server.go", 5, true},  // Added line
+		{"pkg/server.go", 16, "pkg/server.go", 8, true},  // Last line of the first hunk
+		{"pkg/server.go", 41, "pkg/server.go", 10, true}, // Second hunk header counts as position 9
+		{"pkg/server.go", 42, "pkg/server.go", 11, true},
+		{"pkg/server.go", 30, "", 0, false}, // Between hunks
+		{"./pkg/server.go", 13, "pkg/server.go", 5, true},
+		{"b/pkg/server.go", 13, "pkg/server.go", 5, true},
+		{"lib/new_name.rb", 1, "lib/new_name.rb", 2, true},
+		{"lib/old_name.rb", 2, "lib/new_name.rb", 3, true}, // Old path of a rename
+		{"web/app.ts", 2, "web/app.ts", 2, true},
+		{"README.md", 1, "", 0, false},
+	}
+	for _, tt := range tests {
+		path, pos, ok := m.Position(tt.file, tt.line)
+		if path != tt.wantPath || pos != tt.wantPos || ok != tt.wantOK {
+			t.Errorf("Position(%q, %d) = (%q, %d, %v), want (%q, %d, %v)",
+				tt.file, tt.line, path, pos, ok, tt.wantPath, tt.wantPos, tt.wantOK)
+		}
+	}
+}
+
+func TestDiffMap_Path(t *testing.T) {
+	m := ParseDiffMap(testDiff)
+	for file, want := range map[string]string{
+		"lib/old_name.rb": "lib/new_name.rb",
+		"./web/app.ts":    "web/app.ts",
+		"docs/guide.md":   "docs/guide.md",
+	} {
+		if got := m.Path(file); got != want {
+			t.Errorf("Path(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestParseDiffMap_Empty(t *testing.T) {
+	m := ParseDiffMap("")
+	if len(m.Files()) != 0 {
+		t.Errorf("Expected no files, got %v", m.Files())
+	}
+	if _, _, ok := m.Position("a.go", 1); ok {
+		t.Error("Expected no position in an empty diff")
+	}
+}
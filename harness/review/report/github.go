@@ -0,0 +1,166 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/philjestin/boatman-ecosystem/harness/review"
+)
+
+// Pull request review events accepted by GitHub.
+const (
+	EventComment        = "COMMENT"
+	EventRequestChanges = "REQUEST_CHANGES"
+	EventApprove        = "APPROVE"
+)
+
+// PullRequestReview is the payload of GitHub's "create a review for a pull
+// request" endpoint (POST /repos/{owner}/{repo}/pulls/{number}/reviews).
+type PullRequestReview struct {
+	CommitID string          `json:"commit_id,omitempty"`
+	Body     string          `json:"body"`
+	Event    string          `json:"event"`
+	Comments []ReviewComment `json:"comments,omitempty"`
+}
+
+// ReviewComment is an inline comment anchored to a position in the pull
+// request's diff.
+type ReviewComment struct {
+	Path     string `json:"path"`
+	Position int    `json:"position"`
+	Body     string `json:"body"`
+}
+
+// GitHubOptions configures NewPullRequestReview.
+type GitHubOptions struct {
+	// CommitID is the head commit the review applies to. GitHub uses the
+	// pull request's latest commit when empty.
+	CommitID string
+
+	// Approve submits passing reviews as approvals instead of comments.
+	// GitHub rejects approvals of your own pull requests.
+	Approve bool
+
+	// ToolName names the reviewer in the review body. Defaults to "ScottBott".
+	ToolName string
+}
+
+// JSON encodes the review payload.
+func (r *PullRequestReview) JSON() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// NewPullRequestReview converts a review result into a GitHub pull request
+// review. diff must be the pull request's diff (gh pr diff): issues on lines
+// it shows become inline comments at their diff positions, and the rest are
+// listed in the review body. Failed reviews request changes.
+func NewPullRequestReview(result *review.ReviewResult, diff string, opts GitHubOptions) *PullRequestReview {
+	toolName := opts.ToolName
+	if toolName == "" {
+		toolName = "ScottBott"
+	}
+
+	event := EventComment
+	switch {
+	case !result.Passed:
+		event = EventRequestChanges
+	case opts.Approve:
+		event = EventApprove
+	}
+
+	m := ParseDiffMap(diff)
+	var comments []ReviewComment
+	var unanchored []review.Issue
+	for _, issue := range result.Issues {
+		path, pos, ok := m.Position(issue.File, issue.Line)
+		if !ok {
+			unanchored = append(unanchored, issue)
+			continue
+		}
+		comments = append(comments, ReviewComment{
+			Path:     path,
+			Position: pos,
+			Body:     commentBody(issue),
+		})
+	}
+
+	return &PullRequestReview{
+		CommitID: opts.CommitID,
+		Body:     reviewBody(toolName, result, len(comments), unanchored, m),
+		Event:    event,
+		Comments: comments,
+	}
+}
+
+func commentBody(issue review.Issue) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s **%s**", severityIcon(issue.Severity), capitalize(severityOrDefault(issue.Severity)))
+	if issue.Code != "" {
+		fmt.Fprintf(&sb, " `%s`", issue.Code)
+	}
+	sb.WriteString(": ")
+	sb.WriteString(strings.TrimSpace(issue.Description))
+	if issue.Suggestion != "" {
+		sb.WriteString("\n\n**Suggestion:** ")
+		sb.WriteString(strings.TrimSpace(issue.Suggestion))
+	}
+	return sb.String()
+}
+
+func reviewBody(toolName string, result *review.ReviewResult, inline int, unanchored []review.Issue, m *DiffMap) string {
+	var sb strings.Builder
+
+	verdict := "✅ Passed"
+	if !result.Passed {
+		verdict = "❌ Changes requested"
+	}
+	fmt.Fprintf(&sb, "## %s review: %s (score %d/100)\n\n", toolName, verdict, result.Score)
+	if summary := strings.TrimSpace(result.Summary); summary != "" {
+		sb.WriteString(summary)
+		sb.WriteString("\n\n")
+	}
+
+	if inline > 0 {
+		fmt.Fprintf(&sb, "%d issue(s) are commented inline.\n\n", inline)
+	}
+
+	if len(unanchored) > 0 {
+		sb.WriteString("### Other issues\n\n")
+		for _, issue := range unanchored {
+			fmt.Fprintf(&sb, "- %s **%s**", severityIcon(issue.Severity), capitalize(severityOrDefault(issue.Severity)))
+			if issue.File != "" {
+				loc := m.Path(issue.File)
+				if issue.Line > 0 {
+					loc = fmt.Sprintf("%s:%d", loc, issue.Line)
+				}
+				fmt.Fprintf(&sb, " `%s`", loc)
+			}
+			fmt.Fprintf(&sb, ": %s", strings.TrimSpace(issue.Description))
+			if issue.Suggestion != "" {
+				fmt.Fprintf(&sb, " _Suggestion: %s_", strings.TrimSpace(issue.Suggestion))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	if guidance := strings.TrimSpace(result.Guidance); guidance != "" && !result.Passed {
+		sb.WriteString("### Guidance\n\n")
+		sb.WriteString(guidance)
+		sb.WriteString("\n")
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+func severityIcon(severity string) string {
+	switch severity {
+	case "critical":
+		return "🔴"
+	case "major":
+		return "🟠"
+	default:
+		return "🟡"
+	}
+}
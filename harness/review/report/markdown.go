@@ -0,0 +1,82 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/philjestin/boatman-ecosystem/harness/review"
+)
+
+// Markdown renders a review result as a Markdown report, with issues
+// grouped by severity.
+func Markdown(result *review.ReviewResult, title string) string {
+	var sb strings.Builder
+
+	verdict := "✅ Passed"
+	if !result.Passed {
+		verdict = "❌ Changes requested"
+	}
+	if title == "" {
+		title = "Review"
+	}
+	fmt.Fprintf(&sb, "# %s\n\n**%s** (score %d/100", title, verdict, result.Score)
+	if result.Confidence > 0 {
+		fmt.Fprintf(&sb, ", %.0f%% agreement", result.Confidence*100)
+	}
+	sb.WriteString(")\n\n")
+	if summary := strings.TrimSpace(result.Summary); summary != "" {
+		sb.WriteString(summary)
+		sb.WriteString("\n\n")
+	}
+
+	for _, severity := range []string{"critical", "major", "minor"} {
+		var issues []review.Issue
+		for _, issue := range result.Issues {
+			if severityOrDefault(issue.Severity) == severity {
+				issues = append(issues, issue)
+			}
+		}
+		if len(issues) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "## %s %s (%d)\n\n", severityIcon(severity), capitalize(severity), len(issues))
+		for _, issue := range issues {
+			sb.WriteString("- ")
+			if loc := issueLocation(issue); loc != "" {
+				fmt.Fprintf(&sb, "`%s`: ", loc)
+			}
+			sb.WriteString(strings.TrimSpace(issue.Description))
+			if issue.Suggestion != "" {
+				fmt.Fprintf(&sb, "\n  _Suggestion: %s_", strings.TrimSpace(issue.Suggestion))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(result.Praise) > 0 {
+		sb.WriteString("## 👍 What's good\n\n")
+		for _, p := range result.Praise {
+			fmt.Fprintf(&sb, "- %s\n", p)
+		}
+		sb.WriteString("\n")
+	}
+
+	if guidance := strings.TrimSpace(result.Guidance); guidance != "" {
+		sb.WriteString("## Guidance\n\n")
+		sb.WriteString(guidance)
+		sb.WriteString("\n")
+	}
+
+	return strings.TrimSpace(sb.String()) + "\n"
+}
+
+func issueLocation(issue review.Issue) string {
+	if issue.File == "" {
+		return ""
+	}
+	if issue.Line > 0 {
+		return fmt.Sprintf("%s:%d", cleanPath(issue.File), issue.Line)
+	}
+	return cleanPath(issue.File)
+}
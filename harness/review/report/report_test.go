@@ -0,0 +1,193 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/philjestin/boatman-ecosystem/harness/issuetracker"
+	"github.com/philjestin/boatman-ecosystem/harness/review"
+)
+
+func TestSARIF(t *testing.T) {
+	issues := []review.Issue{
+		{Severity: "critical", File: "pkg/server.go", Line: 13, Description: "Handler is never registered", Suggestion: "Register it"},
+		{Severity: "major", File: "lib/old_name.rb", Line: 1, Description: "Missing spec", Code: "Rails/Spec"},
+		{Severity: "minor", File: "pkg/server.go", Description: "Naming"},
+		{Severity: "critical", Description: "No tests at all"}, // No file: left out
+	}
+
+	log := SARIF(issues, Options{Diff: testDiff, ToolVersion: "1.2.3"})
+
+	if log.Version != "2.1.0" || log.Schema != SARIFSchema || len(log.Runs) != 1 {
+		t.Fatalf("Unexpected log header: %+v", log)
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "ScottBott" || run.Tool.Driver.Version != "1.2.3" {
+		t.Errorf("Unexpected driver: %+v", run.Tool.Driver)
+	}
+	if len(run.Results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(run.Results))
+	}
+
+	first := run.Results[0]
+	if first.Level != "error" || first.RuleID != "review/critical" || first.Locations[0].PhysicalLocation.Region.StartLine != 13 {
+		t.Errorf("Unexpected first result: %+v", first)
+	}
+	if !strings.Contains(first.Message.Text, "Suggestion: Register it") {
+		t.Errorf("Expected the suggestion in the message, got %q", first.Message.Text)
+	}
+
+	second := run.Results[1]
+	if second.Level != "warning" || second.RuleID != "Rails/Spec" {
+		t.Errorf("Unexpected second result: %+v", second)
+	}
+	if uri := second.Locations[0].PhysicalLocation.ArtifactLocation.URI; uri != "lib/new_name.rb" {
+		t.Errorf("Expected the renamed path, got %q", uri)
+	}
+
+	third := run.Results[2]
+	if third.Level != "note" || third.Locations[0].PhysicalLocation.Region != nil {
+		t.Errorf("Unexpected third result: %+v", third)
+	}
+
+	if len(run.Tool.Driver.Rules) != 3 {
+		t.Errorf("Expected 3 rules, got %+v", run.Tool.Driver.Rules)
+	}
+	for _, r := range run.Results {
+		if run.Tool.Driver.Rules[r.RuleIndex].ID != r.RuleID {
+			t.Errorf("Result %q points at rule %d", r.RuleID, r.RuleIndex)
+		}
+		if r.PartialFingerprints["boatmanIssue/v1"] == "" {
+			t.Errorf("Result %q has no fingerprint", r.RuleID)
+		}
+	}
+
+	data, err := log.JSON()
+	if err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded["$schema"] != SARIFSchema {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+}
+
+func TestSARIFFromHistory(t *testing.T) {
+	history := issuetracker.NewIssueHistory()
+	persistent := review.Issue{Severity: "major", File: "a.go", Line: 3, Description: "Error from Close is ignored"}
+	fixed := review.Issue{Severity: "critical", File: "b.go", Line: 7, Description: "Nil map write in the cache"}
+	history.RecordIteration([]review.Issue{persistent, fixed})
+	history.RecordIteration([]review.Issue{persistent, {Severity: "minor", File: "c.go", Description: "Typo in the comment"}})
+
+	tracker := history.GetTracker()
+	tracked := append(tracker.GetUnaddressedIssues(), tracker.GetAddressedIssues()...)
+	log := SARIFFromHistory(tracked, Options{})
+
+	states := map[string]string{}
+	for _, r := range log.Runs[0].Results {
+		states[r.Locations[0].PhysicalLocation.ArtifactLocation.URI] = r.BaselineState
+		if r.PartialFingerprints["boatmanIssue/v1"] == "" {
+			t.Errorf("Result for %+v has no fingerprint", r.Locations)
+		}
+	}
+	want := map[string]string{"a.go": "unchanged", "b.go": "absent", "c.go": "new"}
+	for file, state := range want {
+		if states[file] != state {
+			t.Errorf("baselineState(%s) = %q, want %q", file, states[file], state)
+		}
+	}
+}
+
+func TestNewPullRequestReview(t *testing.T) {
+	result := &review.ReviewResult{
+		Passed:   false,
+		Score:    55,
+		Summary:  "The health endpoint is incomplete.",
+		Guidance: "Register the handler first.",
+		Issues: []review.Issue{
+			{Severity: "critical", File: "pkg/server.go", Line: 13, Description: "health is undefined", Suggestion: "Add the handler"},
+			{Severity: "minor", File: "lib/old_name.rb", Line: 1, Description: "Rename the spec too", Code: "naming"},
+			{Severity: "major", File: "pkg/server.go", Line: 30, Description: "Timeouts are not set"},
+			{Severity: "major", Description: "No tests were added"},
+		},
+	}
+
+	r := NewPullRequestReview(result, testDiff, GitHubOptions{CommitID: "abc123"})
+
+	if r.Event != EventRequestChanges || r.CommitID != "abc123" {
+		t.Errorf("Unexpected review: event %q, commit %q", r.Event, r.CommitID)
+	}
+	if len(r.Comments) != 2 {
+		t.Fatalf("Expected 2 inline comments, got %+v", r.Comments)
+	}
+	if c := r.Comments[0]; c.Path != "pkg/server.go" || c.Position != 5 ||
+		!strings.Contains(c.Body, "**Critical**: health is undefined") || !strings.Contains(c.Body, "**Suggestion:** Add the handler") {
+		t.Errorf("Unexpected first comment: %+v", c)
+	}
+	if c := r.Comments[1]; c.Path != "lib/new_name.rb" || c.Position != 2 || !strings.Contains(c.Body, "`naming`") {
+		t.Errorf("Unexpected second comment: %+v", c)
+	}
+
+	for _, want := range []string{
+		"ScottBott review: ❌ Changes requested (score 55/100)",
+		"The health endpoint is incomplete.",
+		"2 issue(s) are commented inline.",
+		"`pkg/server.go:30`: Timeouts are not set",
+		"**Major**: No tests were added",
+		"Register the handler first.",
+	} {
+		if !strings.Contains(r.Body, want) {
+			t.Errorf("Expected body to contain %q, got:\n%s", want, r.Body)
+		}
+	}
+
+	data, err := r.JSON()
+	if err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"event":"REQUEST_CHANGES"`) || !strings.Contains(string(data), `"position":5`) {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+}
+
+func TestNewPullRequestReview_Passed(t *testing.T) {
+	result := &review.ReviewResult{Passed: true, Score: 92, Summary: "Looks good"}
+
+	if r := NewPullRequestReview(result, testDiff, GitHubOptions{}); r.Event != EventComment || len(r.Comments) != 0 {
+		t.Errorf("Expected a plain comment review, got %+v", r)
+	}
+	if r := NewPullRequestReview(result, testDiff, GitHubOptions{Approve: true}); r.Event != EventApprove {
+		t.Errorf("Expected an approval, got %q", r.Event)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	result := &review.ReviewResult{
+		Passed:   false,
+		Score:    60,
+		Summary:  "Two problems.",
+		Praise:   []string{"Clear naming"},
+		Guidance: "Fix the nil check first.",
+		Issues: []review.Issue{
+			{Severity: "minor", File: "./a.go", Description: "Long function"},
+			{Severity: "critical", File: "b.go", Line: 4, Description: "Nil dereference", Suggestion: "Check err first"},
+		},
+	}
+
+	md := Markdown(result, "Review of feature/login")
+	for _, want := range []string{
+		"# Review of feature/login\n\n**❌ Changes requested** (score 60/100)",
+		"## 🔴 Critical (1)\n\n- `b.go:4`: Nil dereference\n  _Suggestion: Check err first_",
+		"## 🟡 Minor (1)\n\n- `a.go`: Long function",
+		"- Clear naming",
+		"## Guidance\n\nFix the nil check first.",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Expected Markdown to contain %q, got:\n%s", want, md)
+		}
+	}
+	if strings.Index(md, "Critical") > strings.Index(md, "Minor") {
+		t.Error("Expected critical issues before minor ones")
+	}
+}
@@ -0,0 +1,304 @@
+// Package report exports review results for other tools: SARIF 2.1.0 logs
+// for code-scanning upload, GitHub pull request reviews with comments
+// anchored to the lines of the pull request's diff, and Markdown reports.
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/philjestin/boatman-ecosystem/harness/issuetracker"
+	"github.com/philjestin/boatman-ecosystem/harness/review"
+)
+
+// SARIFSchema and SARIFVersion identify the SARIF format the exporter writes.
+const (
+	SARIFSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	SARIFVersion = "2.1.0"
+)
+
+// Options configures the exporters.
+type Options struct {
+	// ToolName names the reviewer in the SARIF log. Defaults to "ScottBott".
+	ToolName string
+
+	// ToolVersion is the reviewer's version, if known.
+	ToolVersion string
+
+	// InformationURI links to the reviewer's documentation.
+	InformationURI string
+
+	// Diff is the final unified diff. When set, issue paths follow renames
+	// in the diff so they match the reviewed revision.
+	Diff string
+}
+
+func (o Options) toolName() string {
+	if o.ToolName != "" {
+		return o.ToolName
+	}
+	return "ScottBott"
+}
+
+// SARIFLog is a SARIF 2.1.0 log with the subset of properties the exporter
+// writes.
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun is a single run of the reviewer.
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the reviewer.
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver is the reviewer and the rules its results refer to.
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []SARIFRule `json:"rules,omitempty"`
+}
+
+// SARIFRule is a kind of issue the reviewer reports.
+type SARIFRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     *SARIFMessage      `json:"shortDescription,omitempty"`
+	DefaultConfiguration *SARIFRuleDefaults `json:"defaultConfiguration,omitempty"`
+}
+
+// SARIFRuleDefaults holds a rule's default level.
+type SARIFRuleDefaults struct {
+	Level string `json:"level"`
+}
+
+// SARIFResult is one reported issue.
+type SARIFResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             SARIFMessage      `json:"message"`
+	Locations           []SARIFLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+	BaselineState       string            `json:"baselineState,omitempty"`
+	Properties          map[string]any    `json:"properties,omitempty"`
+}
+
+// SARIFMessage is a plain-text and Markdown message.
+type SARIFMessage struct {
+	Text     string `json:"text"`
+	Markdown string `json:"markdown,omitempty"`
+}
+
+// SARIFLocation points a result at a file and line.
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+// SARIFPhysicalLocation is a location in a file of the repository.
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+// SARIFArtifactLocation is a path relative to the repository root.
+type SARIFArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+// SARIFRegion is the line an issue was reported on.
+type SARIFRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// JSON encodes the log, indented for readability.
+func (l *SARIFLog) JSON() ([]byte, error) {
+	return json.MarshalIndent(l, "", "  ")
+}
+
+// SARIF converts review issues into a SARIF log. Issues without a file can't
+// be located in SARIF and are left out.
+func SARIF(issues []review.Issue, opts Options) *SARIFLog {
+	b := newSARIFBuilder(opts)
+	for _, issue := range issues {
+		b.add(issue, fingerprint(issue), "", nil)
+	}
+	return b.log()
+}
+
+// SARIFFromHistory converts issues tracked across review iterations into a
+// SARIF log. Each result records how its issue evolved: baselineState is
+// "absent" for addressed issues, "new" for issues reported once, and
+// "unchanged" for issues that persisted across iterations. The tracked ID is
+// used as the fingerprint so code scanning can follow issues between uploads.
+func SARIFFromHistory(tracked []issuetracker.TrackedIssue, opts Options) *SARIFLog {
+	tracked = append([]issuetracker.TrackedIssue(nil), tracked...)
+	sort.SliceStable(tracked, func(i, j int) bool {
+		if tracked[i].FirstSeen != tracked[j].FirstSeen {
+			return tracked[i].FirstSeen < tracked[j].FirstSeen
+		}
+		return tracked[i].ID < tracked[j].ID
+	})
+
+	b := newSARIFBuilder(opts)
+	for _, t := range tracked {
+		state := "unchanged"
+		switch {
+		case t.Addressed:
+			state = "absent"
+		case t.TimesReported <= 1:
+			state = "new"
+		}
+		props := map[string]any{
+			"firstSeenIteration": t.FirstSeen,
+			"lastSeenIteration":  t.LastSeen,
+			"timesReported":      t.TimesReported,
+		}
+		if t.Addressed {
+			props["addressedIteration"] = t.AddressedAt
+		}
+		id := t.ID
+		if id == "" {
+			id = fingerprint(t.Issue)
+		}
+		b.add(t.Issue, id, state, props)
+	}
+	return b.log()
+}
+
+type sarifBuilder struct {
+	opts    Options
+	diff    *DiffMap
+	rules   []SARIFRule
+	ruleIdx map[string]int
+	results []SARIFResult
+}
+
+func newSARIFBuilder(opts Options) *sarifBuilder {
+	return &sarifBuilder{
+		opts:    opts,
+		diff:    ParseDiffMap(opts.Diff),
+		ruleIdx: map[string]int{},
+		results: []SARIFResult{},
+	}
+}
+
+func (b *sarifBuilder) add(issue review.Issue, fp, state string, props map[string]any) {
+	if strings.TrimSpace(issue.File) == "" {
+		return
+	}
+	level := sarifLevel(issue.Severity)
+	ruleID := issue.Code
+	if ruleID == "" {
+		ruleID = "review/" + severityOrDefault(issue.Severity)
+	}
+	idx, ok := b.ruleIdx[ruleID]
+	if !ok {
+		idx = len(b.rules)
+		b.ruleIdx[ruleID] = idx
+		rule := SARIFRule{ID: ruleID, DefaultConfiguration: &SARIFRuleDefaults{Level: level}}
+		if issue.Code == "" {
+			rule.ShortDescription = &SARIFMessage{Text: fmt.Sprintf("%s code review issue", capitalize(severityOrDefault(issue.Severity)))}
+		}
+		b.rules = append(b.rules, rule)
+	}
+
+	loc := SARIFPhysicalLocation{
+		ArtifactLocation: SARIFArtifactLocation{URI: b.diff.Path(issue.File), URIBaseID: "%SRCROOT%"},
+	}
+	if issue.Line > 0 {
+		loc.Region = &SARIFRegion{StartLine: issue.Line}
+	}
+
+	b.results = append(b.results, SARIFResult{
+		RuleID:    ruleID,
+		RuleIndex: idx,
+		Level:     level,
+		Message: SARIFMessage{
+			Text:     issueText(issue),
+			Markdown: issueMarkdown(issue),
+		},
+		Locations:           []SARIFLocation{{PhysicalLocation: loc}},
+		PartialFingerprints: map[string]string{"boatmanIssue/v1": fp},
+		BaselineState:       state,
+		Properties:          props,
+	})
+}
+
+func (b *sarifBuilder) log() *SARIFLog {
+	return &SARIFLog{
+		Schema:  SARIFSchema,
+		Version: SARIFVersion,
+		Runs: []SARIFRun{{
+			Tool: SARIFTool{Driver: SARIFDriver{
+				Name:           b.opts.toolName(),
+				Version:        b.opts.ToolVersion,
+				InformationURI: b.opts.InformationURI,
+				Rules:          b.rules,
+			}},
+			Results: b.results,
+		}},
+	}
+}
+
+// sarifLevel maps a review severity to a SARIF level.
+func sarifLevel(severity string) string {
+	switch severity {
+	case "critical":
+		return "error"
+	case "major":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+func severityOrDefault(severity string) string {
+	switch severity {
+	case "critical", "major", "minor":
+		return severity
+	default:
+		return "minor"
+	}
+}
+
+// fingerprint identifies an untracked issue by its location and description
+func fingerprint(issue review.Issue) string {
+	sum := sha256.Sum256([]byte(cleanPath(issue.File) + "\x00" + issue.Code + "\x00" + strings.TrimSpace(issue.Description)))
+	return hex.EncodeToString(sum[:8])
+}
+
+func issueText(issue review.Issue) string {
+	text := strings.TrimSpace(issue.Description)
+	if issue.Suggestion != "" {
+		text += "\nSuggestion: " + strings.TrimSpace(issue.Suggestion)
+	}
+	return text
+}
+
+func issueMarkdown(issue review.Issue) string {
+	md := strings.TrimSpace(issue.Description)
+	if issue.Suggestion != "" {
+		md += "\n\n**Suggestion:** " + strings.TrimSpace(issue.Suggestion)
+	}
+	return md
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
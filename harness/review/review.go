@@ -23,6 +23,10 @@ type ReviewResult struct {
 	Issues   []Issue  `json:"issues"`
 	Praise   []string `json:"praise,omitempty"`
 	Guidance string   `json:"guidance,omitempty"`
+
+	// Confidence is how strongly independent reviews agreed with the
+	// verdict, from 0 to 1. Zero means agreement wasn't measured.
+	Confidence float64 `json:"confidence,omitempty"`
 }
 
 // Reviewer is the interface for pluggable code review backends.
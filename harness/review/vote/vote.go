@@ -0,0 +1,172 @@
+// Package vote provides a review.Reviewer that decides by self-consistency
+// voting. A single review's verdict on a borderline change can flip between
+// runs; the voting reviewer runs several independent reviews (possibly with
+// different models) and combines them:
+//
+//   - The change passes if a majority of the reviews pass it.
+//   - Only issues raised by a quorum of the reviews are kept. Issues are
+//     matched across reviews with issuetracker's similarity matching.
+//   - Confidence is the share of reviews that agree with the verdict.
+package vote
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/philjestin/boatman-ecosystem/harness/issuetracker"
+	"github.com/philjestin/boatman-ecosystem/harness/review"
+)
+
+// Options configures a Reviewer.
+type Options struct {
+	// Quorum is how many reviews must raise an issue for it to be kept.
+	// Zero means a majority of the reviews that completed.
+	Quorum int
+}
+
+// Reviewer runs independent reviews and decides by vote.
+type Reviewer struct {
+	voters []review.Reviewer
+	opts   Options
+}
+
+// New creates a voting Reviewer. Each voter reviews the change once, so
+// passing the same reviewer several times samples it several times.
+func New(voters []review.Reviewer, opts Options) *Reviewer {
+	return &Reviewer{voters: voters, opts: opts}
+}
+
+// Review runs the voters concurrently and tallies their reviews. Reviews that
+// fail are left out of the vote; it is an error only if all of them fail.
+func (r *Reviewer) Review(ctx context.Context, diff string, reviewContext string) (*review.ReviewResult, error) {
+	if len(r.voters) == 0 {
+		return nil, fmt.Errorf("no reviewers to vote")
+	}
+
+	results := make([]*review.ReviewResult, len(r.voters))
+	errs := make([]error, len(r.voters))
+	var wg sync.WaitGroup
+	for i, v := range r.voters {
+		wg.Add(1)
+		go func(i int, v review.Reviewer) {
+			defer wg.Done()
+			results[i], errs[i] = v.Review(ctx, diff, reviewContext)
+		}(i, v)
+	}
+	wg.Wait()
+
+	var ballots []*review.ReviewResult
+	var firstErr error
+	for i, res := range results {
+		switch {
+		case errs[i] != nil:
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+		case res != nil:
+			ballots = append(ballots, res)
+		}
+	}
+	if len(ballots) == 0 {
+		if firstErr == nil {
+			firstErr = fmt.Errorf("no results")
+		}
+		return nil, fmt.Errorf("all %d reviews failed: %w", len(r.voters), firstErr)
+	}
+
+	return Tally(ballots, r.opts.Quorum), nil
+}
+
+// Tally combines independent reviews of the same change. The verdict is the
+// majority's (a tie fails), Score is the mean score, and only issues raised
+// by at least quorum reviews are kept; quorum <= 0 means a majority. Summary,
+// guidance and praise come from the reviews that agree with the verdict.
+func Tally(ballots []*review.ReviewResult, quorum int) *review.ReviewResult {
+	n := len(ballots)
+	if n == 0 {
+		return &review.ReviewResult{}
+	}
+	if quorum <= 0 {
+		quorum = n/2 + 1
+	}
+	if quorum > n {
+		quorum = n
+	}
+
+	passVotes, scoreSum := 0, 0
+	for _, b := range ballots {
+		if b.Passed {
+			passVotes++
+		}
+		scoreSum += b.Score
+	}
+	passed := passVotes*2 > n
+	agreeing := n - passVotes
+	if passed {
+		agreeing = passVotes
+	}
+
+	result := &review.ReviewResult{
+		Passed:     passed,
+		Score:      int(math.Round(float64(scoreSum) / float64(n))),
+		Confidence: float64(agreeing) / float64(n),
+	}
+
+	// Match issues across reviews. All reviews are tracked as one iteration,
+	// so an issue raised by several reviews maps to the same tracked ID.
+	tracker := issuetracker.New()
+	votes := map[string]int{}
+	first := map[string]review.Issue{}
+	var order []string
+	for _, b := range ballots {
+		raised := map[string]bool{}
+		for i, t := range tracker.Track(b.Issues) {
+			if raised[t.ID] {
+				continue // The same issue twice in one review is one vote
+			}
+			raised[t.ID] = true
+			if _, ok := first[t.ID]; !ok {
+				first[t.ID] = b.Issues[i]
+				order = append(order, t.ID)
+			}
+			votes[t.ID]++
+		}
+	}
+	for _, id := range order {
+		if votes[id] >= quorum {
+			result.Issues = append(result.Issues, first[id])
+		}
+	}
+
+	praise := map[string]bool{}
+	for _, b := range ballots {
+		if b.Passed != passed {
+			continue
+		}
+		if result.Summary == "" {
+			result.Summary = strings.TrimSpace(b.Summary)
+		}
+		if result.Guidance == "" {
+			result.Guidance = b.Guidance
+		}
+		for _, p := range b.Praise {
+			if !praise[p] {
+				praise[p] = true
+				result.Praise = append(result.Praise, p)
+			}
+		}
+	}
+
+	verdict := "passed"
+	if !passed {
+		verdict = "failed"
+	}
+	note := fmt.Sprintf("%d of %d reviews %s the change; %d of %d distinct issues were raised by at least %d reviews.",
+		agreeing, n, verdict, len(result.Issues), len(order), quorum)
+	result.Summary = strings.TrimSpace(result.Summary + "\n\n" + note)
+
+	return result
+}
@@ -0,0 +1,109 @@
+package vote
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/philjestin/boatman-ecosystem/harness/review"
+)
+
+type stubReviewer struct {
+	result *review.ReviewResult
+	err    error
+}
+
+func (s stubReviewer) Review(ctx context.Context, diff, reviewContext string) (*review.ReviewResult, error) {
+	return s.result, s.err
+}
+
+var (
+	nilDeref = review.Issue{Severity: "major", File: "api/handler.go", Line: 12, Description: "Possible nil pointer dereference of the user"}
+	// The same problem, worded differently and reported on another line
+	nilDerefAgain = review.Issue{Severity: "major", File: "api/handler.go", Line: 14, Description: "Possible nil pointer dereference of user"}
+	naming        = review.Issue{Severity: "minor", File: "api/handler.go", Description: "Rename the helper to describe what it returns"}
+	missingTest   = review.Issue{Severity: "major", File: "api/handler_test.go", Description: "No test covers the error path"}
+)
+
+func TestTally(t *testing.T) {
+	ballots := []*review.ReviewResult{
+		{Passed: false, Score: 60, Summary: "Nil dereference", Guidance: "Check the user", Issues: []review.Issue{nilDeref, naming}},
+		{Passed: true, Score: 80, Summary: "Fine", Issues: []review.Issue{nilDerefAgain}, Praise: []string{"Small change"}},
+		{Passed: false, Score: 55, Summary: "Needs work", Issues: []review.Issue{nilDeref, missingTest}, Praise: []string{"Clear names"}},
+	}
+
+	result := Tally(ballots, 0)
+
+	if result.Passed {
+		t.Error("Expected the majority to fail the change")
+	}
+	if result.Score != 65 {
+		t.Errorf("Expected the mean score 65, got %d", result.Score)
+	}
+	if result.Confidence < 0.66 || result.Confidence > 0.67 {
+		t.Errorf("Expected confidence 2/3, got %f", result.Confidence)
+	}
+	if len(result.Issues) != 1 || result.Issues[0] != nilDeref {
+		t.Errorf("Expected only the issue a majority raised, got %+v", result.Issues)
+	}
+	if !strings.HasPrefix(result.Summary, "Nil dereference") || !strings.Contains(result.Summary, "2 of 3 reviews failed the change; 1 of 3 distinct issues") {
+		t.Errorf("Unexpected summary %q", result.Summary)
+	}
+	if result.Guidance != "Check the user" {
+		t.Errorf("Expected guidance from an agreeing review, got %q", result.Guidance)
+	}
+	if len(result.Praise) != 1 || result.Praise[0] != "Clear names" {
+		t.Errorf("Expected praise from the agreeing reviews only, got %q", result.Praise)
+	}
+}
+
+func TestTallyQuorum(t *testing.T) {
+	ballots := []*review.ReviewResult{
+		{Passed: true, Score: 90, Issues: []review.Issue{naming, naming}},
+		{Passed: true, Score: 90, Issues: []review.Issue{missingTest}},
+		{Passed: true, Score: 90},
+	}
+
+	if result := Tally(ballots, 1); len(result.Issues) != 2 {
+		t.Errorf("Expected a quorum of 1 to keep every issue, got %+v", result.Issues)
+	}
+	// An issue repeated within one review is still one vote
+	if result := Tally(ballots, 2); len(result.Issues) != 0 {
+		t.Errorf("Expected no issue to reach a quorum of 2, got %+v", result.Issues)
+	}
+	if result := Tally(ballots, 0); !result.Passed || result.Confidence != 1 {
+		t.Errorf("Expected a unanimous pass, got %+v", result)
+	}
+}
+
+func TestTallyTieFails(t *testing.T) {
+	result := Tally([]*review.ReviewResult{{Passed: true}, {Passed: false}}, 0)
+	if result.Passed || result.Confidence != 0.5 {
+		t.Errorf("Expected a tie to fail with confidence 0.5, got %+v", result)
+	}
+}
+
+func TestReviewSkipsFailedReviews(t *testing.T) {
+	r := New([]review.Reviewer{
+		stubReviewer{err: errors.New("timeout")},
+		stubReviewer{result: &review.ReviewResult{Passed: true, Score: 90}},
+		stubReviewer{result: &review.ReviewResult{Passed: true, Score: 70}},
+	}, Options{})
+
+	result, err := r.Review(context.Background(), "diff", "")
+	if err != nil {
+		t.Fatalf("Review() error = %v", err)
+	}
+	if !result.Passed || result.Score != 80 || result.Confidence != 1 {
+		t.Errorf("Expected a vote over the two completed reviews, got %+v", result)
+	}
+}
+
+func TestReviewAllFail(t *testing.T) {
+	r := New([]review.Reviewer{stubReviewer{err: errors.New("timeout")}, stubReviewer{err: errors.New("rate limited")}}, Options{})
+
+	if _, err := r.Review(context.Background(), "diff", ""); err == nil || !strings.Contains(err.Error(), "all 2 reviews failed: timeout") {
+		t.Errorf("Expected an error when every review fails, got %v", err)
+	}
+}
@@ -2,7 +2,10 @@ package runner
 
 import (
 	"context"
+	"os/exec"
 
+	"github.com/philjestin/boatman-ecosystem/harness/autofix"
+	"github.com/philjestin/boatman-ecosystem/harness/compilecheck"
 	"github.com/philjestin/boatman-ecosystem/harness/testrunner"
 )
 
@@ -39,3 +42,70 @@ func (t *TestRunnerTester) Test(ctx context.Context, req *Request, changedFiles
 		Coverage:    tr.Coverage,
 	}, nil
 }
+
+// AutofixFixer adapts harness/autofix.Autofixer to the Fixer interface. It
+// fixes files in place; when they change and the work directory is a git
+// repository, the diff is refreshed from "git diff HEAD".
+type AutofixFixer struct {
+	workDir   string
+	autofixer *autofix.Autofixer
+}
+
+// NewAutofixFixer creates a Fixer backed by the autofix package, using the
+// fixers detected in workDir, limited to names if any are given.
+func NewAutofixFixer(workDir string, names ...string) *AutofixFixer {
+	return &AutofixFixer{
+		workDir:   workDir,
+		autofixer: autofix.New(workDir, names...),
+	}
+}
+
+// Fix implements the Fixer interface by delegating to autofix.Autofixer.
+func (f *AutofixFixer) Fix(ctx context.Context, req *Request, changedFiles []string) (*FixResult, error) {
+	ar, err := f.autofixer.Fix(ctx, changedFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	fr := &FixResult{}
+	for _, c := range ar.Changes {
+		fr.Changes = append(fr.Changes, FixChange{Fixer: c.Fixer, Files: c.Files})
+	}
+	if len(fr.Changes) > 0 {
+		cmd := exec.CommandContext(ctx, "git", "diff", "HEAD")
+		cmd.Dir = f.workDir
+		if out, err := cmd.Output(); err == nil {
+			fr.Diff = string(out)
+		}
+	}
+	return fr, nil
+}
+
+// CompileChecker adapts harness/compilecheck.Checker to the Compiler interface.
+type CompileChecker struct {
+	checker   *compilecheck.Checker
+	maxErrors int
+}
+
+// NewCompileChecker creates a Compiler backed by the compilecheck package,
+// using the compilers detected in workDir.
+func NewCompileChecker(workDir string) *CompileChecker {
+	return &CompileChecker{
+		checker:   compilecheck.New(workDir),
+		maxErrors: 30,
+	}
+}
+
+// Compile implements the Compiler interface by delegating to compilecheck.Checker.
+func (c *CompileChecker) Compile(ctx context.Context, req *Request, changedFiles []string) (*CompileResult, error) {
+	cr, err := c.checker.Check(ctx, changedFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CompileResult{
+		Passed: cr.Passed,
+		Output: cr.Format(c.maxErrors),
+		Issues: cr.Issues(),
+	}, nil
+}
@@ -14,6 +14,7 @@ type Config struct {
 	TestBeforeReview    bool   // Run tests before each review. Default: true
 	FailOnTestFailure   bool   // Treat test failure as review issue. Default: true
 	SkipPlanningOnError bool   // Continue without plan if Planner errors. Default: true
+	CompileRepairRounds int    // Repair attempts per iteration when the Compiler fails. Default: 3
 	CheckpointDir       string // Empty = no checkpointing
 	ResumeFrom          string // Checkpoint ID to resume from. Empty = fresh start
 }
@@ -25,6 +26,7 @@ func DefaultConfig() Config {
 		TestBeforeReview:    true,
 		FailOnTestFailure:   true,
 		SkipPlanningOnError: true,
+		CompileRepairRounds: 3,
 	}
 }
 
@@ -66,6 +68,7 @@ type Result struct {
 	FilesChanged []string
 	ReviewResult *review.ReviewResult
 	TestResult   *TestResult
+	Fixes        []FixChange // Changes made by the Fixer across the run
 	CostTracker  *cost.Tracker
 	IssueStats   *issuetracker.IssueStats
 	Duration     time.Duration
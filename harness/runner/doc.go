@@ -1,8 +1,8 @@
 // Package runner provides a composable pipeline for AI agent harnesses.
 //
 // The Runner orchestrates a role-based execute-test-review-refactor loop.
-// Two roles are required (Developer and review.Reviewer); two are optional
-// (Tester and Planner). The runner never calls an LLM directly — all model
+// Two roles are required (Developer and review.Reviewer); four are optional
+// (Fixer, Compiler, Tester and Planner). The runner never calls an LLM directly — all model
 // interaction happens inside role implementations, making it model-agnostic.
 //
 // # Minimal usage
@@ -17,11 +17,13 @@
 //
 // # Optional features
 //
-// Use functional options to add a Tester, Planner, cost tracker, checkpoint
-// manager, or lifecycle hooks:
+// Use functional options to add a Fixer, Compiler, Tester, Planner, cost
+// tracker, checkpoint manager, or lifecycle hooks:
 //
 //	r := runner.New(dev, rev,
 //	    runner.WithPlanner(planner),
+//	    runner.WithFixer(runner.NewAutofixFixer("/repo")),
+//	    runner.WithCompiler(runner.NewCompileChecker("/repo")),
 //	    runner.WithTester(runner.NewTestRunnerTester("/repo")),
 //	    runner.WithMaxIterations(5),
 //	    runner.WithHooks(runner.Hooks{
@@ -32,12 +34,19 @@
 // # Pipeline flow
 //
 //  1. Plan (if Planner set) — produces a Plan for the Developer.
-//  2. Execute — Developer.Execute produces initial code changes.
+//  2. Execute — Developer.Execute produces initial code changes, then the
+//     Fixer (if set) formats and autofixes the changed files.
 //  3. Review loop (1..MaxIterations):
-//     a. Test (if Tester set and TestBeforeReview) — runs tests.
-//     b. Review — Reviewer.Review evaluates the diff.
-//     c. If passed, break.
-//     d. Refactor — Developer.Refactor addresses review issues.
+//     a. Compile (if Compiler set) — builds or typechecks the changed files.
+//     While they don't compile, the Developer repairs them (Repairer.Repair
+//     if implemented, otherwise Refactor), up to CompileRepairRounds times.
+//     If they still don't compile, the compile errors stand in for the
+//     review and steps b and c are skipped.
+//     b. Test (if Tester set and TestBeforeReview) — runs tests.
+//     c. Review — Reviewer.Review evaluates the diff.
+//     d. If passed, break.
+//     e. Refactor — Developer.Refactor addresses review issues, then the
+//     Fixer (if set) runs again.
 //  4. Finalize — return Result with status, metrics, and history.
 //
 // # Primitive integration
@@ -13,6 +13,8 @@ import (
 type Hooks struct {
 	OnPlanComplete      func(ctx context.Context, plan *Plan, err error)
 	OnExecuteComplete   func(ctx context.Context, result *ExecuteResult, err error)
+	OnFixComplete       func(ctx context.Context, result *FixResult, iteration int)
+	OnCompileComplete   func(ctx context.Context, result *CompileResult, iteration int)
 	OnTestComplete      func(ctx context.Context, result *TestResult, iteration int)
 	OnReviewComplete    func(ctx context.Context, result *review.ReviewResult, iteration int)
 	OnRefactorComplete  func(ctx context.Context, result *RefactorResult, iteration int)
@@ -34,6 +36,18 @@ func (h *Hooks) callOnExecuteComplete(ctx context.Context, result *ExecuteResult
 	}
 }
 
+func (h *Hooks) callOnFixComplete(ctx context.Context, result *FixResult, iteration int) {
+	if h != nil && h.OnFixComplete != nil {
+		h.OnFixComplete(ctx, result, iteration)
+	}
+}
+
+func (h *Hooks) callOnCompileComplete(ctx context.Context, result *CompileResult, iteration int) {
+	if h != nil && h.OnCompileComplete != nil {
+		h.OnCompileComplete(ctx, result, iteration)
+	}
+}
+
 func (h *Hooks) callOnTestComplete(ctx context.Context, result *TestResult, iteration int) {
 	if h != nil && h.OnTestComplete != nil {
 		h.OnTestComplete(ctx, result, iteration)
@@ -45,6 +45,25 @@ type TestResult struct {
 	Coverage    float64
 }
 
+// CompileResult is the outcome of a fast build or typecheck.
+type CompileResult struct {
+	Passed bool
+	Output string         // Compiler errors, formatted for a repair prompt
+	Issues []review.Issue // One issue per compiler error
+}
+
+// FixChange records the files one formatter or autofixer changed.
+type FixChange struct {
+	Fixer string
+	Files []string
+}
+
+// FixResult is what a Fixer returns after formatting and autofixing.
+type FixResult struct {
+	Changes []FixChange // Files each fixer changed, in the order they ran
+	Diff    string      // The change after fixing; empty keeps the current diff
+}
+
 // Developer implements code changes. Execute is called once,
 // then Refactor zero or more times based on review feedback.
 type Developer interface {
@@ -53,6 +72,26 @@ type Developer interface {
 		guidance string, prevResult *ExecuteResult) (*RefactorResult, error)
 }
 
+// Repairer is implemented by Developers that can fix compile errors with a
+// cheaper, more targeted prompt than Refactor. Optional; Developers that don't
+// implement it are asked to Refactor with the compile errors as issues.
+type Repairer interface {
+	Repair(ctx context.Context, req *Request, compile *CompileResult,
+		prevResult *ExecuteResult) (*RefactorResult, error)
+}
+
+// Fixer runs formatters and autofixers on the changed files after Execute
+// and each Refactor, so formatting never reaches the Reviewer. Optional.
+type Fixer interface {
+	Fix(ctx context.Context, req *Request, changedFiles []string) (*FixResult, error)
+}
+
+// Compiler builds or typechecks the changed files. Optional. Changes that
+// don't compile are repaired before they are tested or reviewed.
+type Compiler interface {
+	Compile(ctx context.Context, req *Request, changedFiles []string) (*CompileResult, error)
+}
+
 // Tester runs tests and reports results. Optional.
 type Tester interface {
 	Test(ctx context.Context, req *Request, changedFiles []string) (*TestResult, error)
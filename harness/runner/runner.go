@@ -16,6 +16,8 @@ type Runner struct {
 	developer    Developer
 	reviewer     review.Reviewer
 	tester       Tester
+	fixer        Fixer
+	compiler     Compiler
 	planner      Planner
 	config       Config
 	costTracker  *cost.Tracker
@@ -32,6 +34,18 @@ func WithTester(t Tester) Option {
 	return func(r *Runner) { r.tester = t }
 }
 
+// WithFixer adds a Fixer to the pipeline. It runs after Execute and each
+// Refactor.
+func WithFixer(f Fixer) Option {
+	return func(r *Runner) { r.fixer = f }
+}
+
+// WithCompiler adds a Compiler to the pipeline. Changes are compiled before
+// each test and review, and repaired until they compile.
+func WithCompiler(c Compiler) Option {
+	return func(r *Runner) { r.compiler = c }
+}
+
 // WithPlanner adds a Planner to the pipeline.
 func WithPlanner(p Planner) Option {
 	return func(r *Runner) { r.planner = p }
@@ -76,7 +90,8 @@ func New(dev Developer, rev review.Reviewer, opts ...Option) *Runner {
 	return r
 }
 
-// Run executes the full pipeline: plan → execute → (test → review → refactor)*.
+// Run executes the full pipeline: plan → execute → (compile → test → review → refactor)*,
+// with an autofix pass after each execute and refactor.
 func (r *Runner) Run(ctx context.Context, req *Request) (*Result, error) {
 	start := time.Now()
 	result := &Result{
@@ -120,7 +135,7 @@ func (r *Runner) Run(ctx context.Context, req *Request) (*Result, error) {
 		return result, nil
 	}
 
-	currentExec := asExecuteResult(execOut)
+	currentExec := r.fix(ctx, req, "autofix", 0, result, asExecuteResult(execOut))
 	currentFiles := currentExec.FilesChanged
 	currentDiff := currentExec.Diff
 
@@ -138,48 +153,77 @@ func (r *Runner) Run(ctx context.Context, req *Request) (*Result, error) {
 			return result, nil
 		}
 
-		// 3a. Test (optional)
-		if r.tester != nil && r.config.TestBeforeReview {
-			testOut, tStepRec, tErr := r.runStep(ctx, fmt.Sprintf("test_%d", i), func() (any, error) {
-				return r.tester.Test(ctx, req, currentFiles)
-			})
-			result.Steps = append(result.Steps, tStepRec)
-
-			tr := asTestResult(testOut)
-			r.hooks.callOnTestComplete(ctx, tr, i)
-
-			if tErr == nil && tr != nil {
-				result.TestResult = tr
-				if r.config.FailOnTestFailure && !tr.Passed {
-					// Synthesize a review issue for the test failure
-					testIssue := review.Issue{
-						Severity:    "critical",
-						Description: fmt.Sprintf("Tests failed: %s", formatFailedTests(tr.FailedTests)),
-						Suggestion:  "Fix the failing tests before proceeding.",
-					}
-					// Run review with test failure context
-					currentDiff = augmentDiffWithTestFailure(currentDiff, tr)
-					_ = testIssue // used below via review
+		// 3a. Compile and repair (optional)
+		var rr *review.ReviewResult
+		if r.compiler != nil {
+			cr, repaired, cErr := r.compileAndRepair(ctx, req, i, result, currentExec, currentFiles)
+			if cErr != nil {
+				result.Status = StatusError
+				result.Error = fmt.Errorf("repair failed on iteration %d: %w", i, cErr)
+				result.Duration = time.Since(start)
+				return result, nil
+			}
+			if repaired != nil {
+				currentExec = repaired
+				currentFiles = repaired.FilesChanged
+				currentDiff = repaired.Diff
+			}
+			if cr != nil && !cr.Passed {
+				// Code that doesn't compile isn't worth testing or reviewing;
+				// its compile errors are the review
+				rr = &review.ReviewResult{
+					Passed:   false,
+					Summary:  "The changes do not compile.",
+					Issues:   cr.Issues,
+					Guidance: "Fix the compile errors before anything else:\n\n" + cr.Output,
 				}
 			}
-
-			r.checkpointStep(checkpoint.StepTesting)
 		}
 
-		// 3b. Review
-		revOut, rStepRec, rErr := r.runStep(ctx, fmt.Sprintf("review_%d", i), func() (any, error) {
-			return r.reviewer.Review(ctx, currentDiff, req.Description)
-		})
-		result.Steps = append(result.Steps, rStepRec)
+		if rr == nil {
+			// 3b. Test (optional)
+			if r.tester != nil && r.config.TestBeforeReview {
+				testOut, tStepRec, tErr := r.runStep(ctx, fmt.Sprintf("test_%d", i), func() (any, error) {
+					return r.tester.Test(ctx, req, currentFiles)
+				})
+				result.Steps = append(result.Steps, tStepRec)
+
+				tr := asTestResult(testOut)
+				r.hooks.callOnTestComplete(ctx, tr, i)
+
+				if tErr == nil && tr != nil {
+					result.TestResult = tr
+					if r.config.FailOnTestFailure && !tr.Passed {
+						// Synthesize a review issue for the test failure
+						testIssue := review.Issue{
+							Severity:    "critical",
+							Description: fmt.Sprintf("Tests failed: %s", formatFailedTests(tr.FailedTests)),
+							Suggestion:  "Fix the failing tests before proceeding.",
+						}
+						// Run review with test failure context
+						currentDiff = augmentDiffWithTestFailure(currentDiff, tr)
+						_ = testIssue // used below via review
+					}
+				}
 
-		rr := asReviewResult(revOut)
-		r.hooks.callOnReviewComplete(ctx, rr, i)
+				r.checkpointStep(checkpoint.StepTesting)
+			}
 
-		if rErr != nil {
-			result.Status = StatusError
-			result.Error = fmt.Errorf("review failed on iteration %d: %w", i, rErr)
-			result.Duration = time.Since(start)
-			return result, nil
+			// 3c. Review
+			revOut, rStepRec, rErr := r.runStep(ctx, fmt.Sprintf("review_%d", i), func() (any, error) {
+				return r.reviewer.Review(ctx, currentDiff, req.Description)
+			})
+			result.Steps = append(result.Steps, rStepRec)
+
+			rr = asReviewResult(revOut)
+			r.hooks.callOnReviewComplete(ctx, rr, i)
+
+			if rErr != nil {
+				result.Status = StatusError
+				result.Error = fmt.Errorf("review failed on iteration %d: %w", i, rErr)
+				result.Duration = time.Since(start)
+				return result, nil
+			}
 		}
 
 		result.ReviewResult = rr
@@ -191,7 +235,7 @@ func (r *Runner) Run(ctx context.Context, req *Request) (*Result, error) {
 
 		r.checkpointStep(checkpoint.StepReview)
 
-		// 3c. Check pass
+		// 3d. Check pass
 		if rr != nil && rr.Passed {
 			// Also check test result if we have one
 			if result.TestResult == nil || result.TestResult.Passed || !r.config.FailOnTestFailure {
@@ -204,7 +248,7 @@ func (r *Runner) Run(ctx context.Context, req *Request) (*Result, error) {
 
 		r.hooks.callOnIterationComplete(ctx, i, false)
 
-		// 3d. Refactor (if not last iteration)
+		// 3e. Refactor (if not last iteration)
 		if i < r.config.MaxIterations {
 			var issues []review.Issue
 			var guidance string
@@ -229,14 +273,14 @@ func (r *Runner) Run(ctx context.Context, req *Request) (*Result, error) {
 			}
 
 			if rref != nil {
-				currentFiles = rref.FilesChanged
-				currentDiff = rref.Diff
 				// Update currentExec to reflect latest state for next refactor
-				currentExec = &ExecuteResult{
+				currentExec = r.fix(ctx, req, fmt.Sprintf("autofix_%d", i), i, result, &ExecuteResult{
 					FilesChanged: rref.FilesChanged,
 					Diff:         rref.Diff,
 					Summary:      rref.Summary,
-				}
+				})
+				currentFiles = currentExec.FilesChanged
+				currentDiff = currentExec.Diff
 			}
 
 			r.checkpointStep(checkpoint.StepRefactor)
@@ -258,6 +302,87 @@ func (r *Runner) Run(ctx context.Context, req *Request) (*Result, error) {
 	return result, nil
 }
 
+// fix runs the Fixer on the changed files and returns the changes with its
+// fixes folded in. A Fixer that fails leaves the changes as they are.
+func (r *Runner) fix(ctx context.Context, req *Request, name string, iteration int, result *Result, exec *ExecuteResult) *ExecuteResult {
+	if r.fixer == nil || exec == nil {
+		return exec
+	}
+
+	out, stepRec, err := r.runStep(ctx, name, func() (any, error) {
+		return r.fixer.Fix(ctx, req, exec.FilesChanged)
+	})
+	result.Steps = append(result.Steps, stepRec)
+
+	fr := asFixResult(out)
+	r.hooks.callOnFixComplete(ctx, fr, iteration)
+	if err != nil || fr == nil {
+		return exec
+	}
+
+	result.Fixes = append(result.Fixes, fr.Changes...)
+	fixed := &ExecuteResult{FilesChanged: exec.FilesChanged, Diff: exec.Diff, Summary: exec.Summary}
+	for _, c := range fr.Changes {
+		fixed.FilesChanged = mergeFiles(fixed.FilesChanged, c.Files)
+	}
+	if fr.Diff != "" {
+		fixed.Diff = fr.Diff
+	}
+	return fixed
+}
+
+// compileAndRepair compiles the current changes and, while they don't
+// compile, has the Developer repair them, up to CompileRepairRounds times. It
+// returns the last compile result (nil if the Compiler could not run) and the
+// repaired changes (nil if there were no repairs).
+func (r *Runner) compileAndRepair(ctx context.Context, req *Request, iteration int, result *Result,
+	exec *ExecuteResult, files []string) (*CompileResult, *ExecuteResult, error) {
+	var repaired *ExecuteResult
+	for round := 0; ; round++ {
+		name := fmt.Sprintf("compile_%d", iteration)
+		if round > 0 {
+			name = fmt.Sprintf("compile_%d_%d", iteration, round)
+		}
+		out, stepRec, err := r.runStep(ctx, name, func() (any, error) {
+			return r.compiler.Compile(ctx, req, files)
+		})
+		result.Steps = append(result.Steps, stepRec)
+
+		cr := asCompileResult(out)
+		r.hooks.callOnCompileComplete(ctx, cr, iteration)
+
+		// A Compiler that can't run doesn't block the pipeline; the tests
+		// and review will catch what it would have
+		if err != nil || cr == nil || cr.Passed || round >= r.config.CompileRepairRounds || ctx.Err() != nil {
+			return cr, repaired, nil
+		}
+
+		refOut, refStepRec, refErr := r.runStep(ctx, fmt.Sprintf("repair_%d_%d", iteration, round+1), func() (any, error) {
+			if rep, ok := r.developer.(Repairer); ok {
+				return rep.Repair(ctx, req, cr, exec)
+			}
+			return r.developer.Refactor(ctx, req, cr.Issues,
+				"Fix only these compile errors; make no other changes.\n\n"+cr.Output, exec)
+		})
+		result.Steps = append(result.Steps, refStepRec)
+		if refErr != nil {
+			return cr, repaired, refErr
+		}
+
+		if rref := asRefactorResult(refOut); rref != nil {
+			// A repair touches a few files; the change as a whole is still
+			// every file changed so far
+			files = mergeFiles(files, rref.FilesChanged)
+			diff := rref.Diff
+			if diff == "" {
+				diff = exec.Diff
+			}
+			exec = &ExecuteResult{FilesChanged: files, Diff: diff, Summary: exec.Summary}
+			repaired = exec
+		}
+	}
+}
+
 // runStep executes fn, records timing, and fires step hooks.
 func (r *Runner) runStep(_ context.Context, name string, fn func() (any, error)) (any, StepRecord, error) {
 	r.hooks.callOnStepStart(name)
@@ -304,6 +429,22 @@ func asRefactorResult(v any) *RefactorResult {
 	return r
 }
 
+func asFixResult(v any) *FixResult {
+	if v == nil {
+		return nil
+	}
+	f, _ := v.(*FixResult)
+	return f
+}
+
+func asCompileResult(v any) *CompileResult {
+	if v == nil {
+		return nil
+	}
+	c, _ := v.(*CompileResult)
+	return c
+}
+
 func asTestResult(v any) *TestResult {
 	if v == nil {
 		return nil
@@ -320,6 +461,22 @@ func asReviewResult(v any) *review.ReviewResult {
 	return r
 }
 
+// mergeFiles returns the files in a followed by those in b that aren't in a.
+func mergeFiles(a, b []string) []string {
+	seen := make(map[string]bool, len(a))
+	merged := append([]string(nil), a...)
+	for _, f := range a {
+		seen[f] = true
+	}
+	for _, f := range b {
+		if !seen[f] {
+			seen[f] = true
+			merged = append(merged, f)
+		}
+	}
+	return merged
+}
+
 // formatFailedTests returns a summary string of failed test names.
 func formatFailedTests(names []string) string {
 	if len(names) == 0 {
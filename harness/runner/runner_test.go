@@ -72,6 +72,44 @@ func (m *mockTester) Test(_ context.Context, _ *Request, _ []string) (*TestResul
 	return m.result, m.err
 }
 
+type mockCompiler struct {
+	results []*CompileResult
+	call    int
+	files   [][]string
+}
+
+func (m *mockCompiler) Compile(_ context.Context, _ *Request, changedFiles []string) (*CompileResult, error) {
+	m.files = append(m.files, changedFiles)
+	if m.call < len(m.results) {
+		r := m.results[m.call]
+		m.call++
+		return r, nil
+	}
+	return &CompileResult{Passed: true}, nil
+}
+
+type mockFixer struct {
+	files [][]string
+}
+
+func (m *mockFixer) Fix(_ context.Context, _ *Request, changedFiles []string) (*FixResult, error) {
+	m.files = append(m.files, changedFiles)
+	return &FixResult{
+		Changes: []FixChange{{Fixer: "gofmt", Files: []string{"main.go", "main_test.go"}}},
+		Diff:    "diff --git a/main.go (formatted)",
+	}, nil
+}
+
+type mockRepairer struct {
+	mockDeveloper
+	repairs int
+}
+
+func (m *mockRepairer) Repair(_ context.Context, _ *Request, _ *CompileResult, _ *ExecuteResult) (*RefactorResult, error) {
+	m.repairs++
+	return &RefactorResult{FilesChanged: []string{"util.go"}, Diff: "diff --git a/main.go (repaired)"}, nil
+}
+
 // --- helpers ---
 
 func simpleRequest() *Request {
@@ -323,6 +361,135 @@ func TestWithTester(t *testing.T) {
 	}
 }
 
+func TestFixerRunsAfterExecuteAndRefactor(t *testing.T) {
+	dev := &mockDeveloper{}
+	rev := &mockReviewer{
+		results: []*review.ReviewResult{
+			{Passed: false, Issues: []review.Issue{{Severity: "major", Description: "missing check"}}},
+			{Passed: true},
+		},
+	}
+	fixer := &mockFixer{}
+
+	var steps []string
+	r := New(dev, rev, WithFixer(fixer), WithHooks(Hooks{
+		OnStepStart: func(name string) { steps = append(steps, name) },
+	}))
+	result, err := r.Run(context.Background(), simpleRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"execute", "autofix", "review_1", "refactor_1", "autofix_1", "review_2"}
+	if len(steps) != len(want) {
+		t.Fatalf("expected steps %v, got %v", want, steps)
+	}
+	for i := range want {
+		if steps[i] != want[i] {
+			t.Errorf("expected steps %v, got %v", want, steps)
+			break
+		}
+	}
+	if len(result.Fixes) != 2 {
+		t.Errorf("expected both fixes to be recorded, got %v", result.Fixes)
+	}
+	if result.FinalDiff != "diff --git a/main.go (formatted)" {
+		t.Errorf("expected the fixed diff, got %q", result.FinalDiff)
+	}
+	if len(result.FilesChanged) != 2 || result.FilesChanged[1] != "main_test.go" {
+		t.Errorf("expected the fixed files to be added, got %v", result.FilesChanged)
+	}
+}
+
+func compileFailure() *CompileResult {
+	return &CompileResult{
+		Passed: false,
+		Output: "main.go:3:2: undefined: handler",
+		Issues: []review.Issue{{Severity: "critical", File: "main.go", Line: 3, Description: "undefined: handler"}},
+	}
+}
+
+func TestCompileRepairedBeforeReview(t *testing.T) {
+	dev := &mockRepairer{}
+	rev := &mockReviewer{}
+	compiler := &mockCompiler{results: []*CompileResult{compileFailure()}}
+
+	var steps []string
+	r := New(dev, rev, WithCompiler(compiler), WithHooks(Hooks{
+		OnStepStart: func(name string) { steps = append(steps, name) },
+	}))
+	result, err := r.Run(context.Background(), simpleRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Status != StatusPassed {
+		t.Errorf("expected StatusPassed, got %v", result.Status)
+	}
+	if dev.repairs != 1 {
+		t.Errorf("expected 1 repair, got %d", dev.repairs)
+	}
+	want := []string{"execute", "compile_1", "repair_1_1", "compile_1_1", "review_1"}
+	if len(steps) != len(want) {
+		t.Fatalf("expected steps %v, got %v", want, steps)
+	}
+	for i := range want {
+		if steps[i] != want[i] {
+			t.Errorf("expected steps %v, got %v", want, steps)
+			break
+		}
+	}
+	if result.FinalDiff != "diff --git a/main.go (repaired)" {
+		t.Errorf("expected the repaired diff, got %q", result.FinalDiff)
+	}
+	if len(result.FilesChanged) != 2 || result.FilesChanged[1] != "util.go" {
+		t.Errorf("expected the repaired file to be added, got %v", result.FilesChanged)
+	}
+	if got := compiler.files[1]; len(got) != 2 {
+		t.Errorf("expected the recheck to compile all changed files, got %v", got)
+	}
+}
+
+func TestCompileFailureSkipsReview(t *testing.T) {
+	var refactorIssues []review.Issue
+	dev := &mockDeveloper{
+		refactorFn: func(_ context.Context, _ *Request, issues []review.Issue, _ string, _ *ExecuteResult) (*RefactorResult, error) {
+			refactorIssues = issues
+			return &RefactorResult{FilesChanged: []string{"main.go"}, Diff: "diff"}, nil
+		},
+	}
+	rev := &mockReviewer{}
+	compiler := &mockCompiler{results: []*CompileResult{compileFailure(), compileFailure()}}
+
+	cfg := DefaultConfig()
+	cfg.MaxIterations = 1
+	cfg.CompileRepairRounds = 1
+	var compiles int
+	r := New(dev, rev, WithCompiler(compiler), WithConfig(cfg), WithHooks(Hooks{
+		OnCompileComplete: func(_ context.Context, _ *CompileResult, _ int) { compiles++ },
+	}))
+	result, err := r.Run(context.Background(), simpleRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Status != StatusMaxIterations {
+		t.Errorf("expected StatusMaxIterations, got %v", result.Status)
+	}
+	if rev.call != 0 {
+		t.Errorf("expected code that doesn't compile not to be reviewed, got %d reviews", rev.call)
+	}
+	if compiles != 2 {
+		t.Errorf("expected 2 compiles, got %d", compiles)
+	}
+	if len(refactorIssues) != 1 || refactorIssues[0].Description != "undefined: handler" {
+		t.Errorf("expected the developer without Repair to refactor the compile errors, got %v", refactorIssues)
+	}
+	if result.ReviewResult == nil || result.ReviewResult.Passed || len(result.ReviewResult.Issues) != 1 {
+		t.Errorf("expected a failing review with the compile errors, got %+v", result.ReviewResult)
+	}
+}
+
 func TestWithPlanner(t *testing.T) {
 	dev := &mockDeveloper{
 		executeFn: func(_ context.Context, _ *Request, plan *Plan) (*ExecuteResult, error) {
@@ -433,4 +600,7 @@ func TestDefaultConfig(t *testing.T) {
 	if !cfg.SkipPlanningOnError {
 		t.Error("expected SkipPlanningOnError=true")
 	}
+	if cfg.CompileRepairRounds != 3 {
+		t.Errorf("expected CompileRepairRounds=3, got %d", cfg.CompileRepairRounds)
+	}
 }
@@ -0,0 +1,305 @@
+// Package scope checks a change against the plan it was made from. Each
+// changed file is classified as:
+//
+//   - planned: the plan names the file, or a directory it is in
+//   - adjacent: a direct dependency or dependent of a planned file (per the
+//     import graph), or the test of a planned file
+//   - unplanned: anything else
+//
+// Unplanned changes above a threshold become review issues, and they can be
+// reverted before review.
+package scope
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/philjestin/boatman-ecosystem/harness/internal/udiff"
+	"github.com/philjestin/boatman-ecosystem/harness/review"
+)
+
+// Class is how a changed file relates to the plan.
+type Class string
+
+const (
+	Planned   Class = "planned"
+	Adjacent  Class = "adjacent"
+	Unplanned Class = "unplanned"
+)
+
+// Plan is what the change was expected to touch.
+type Plan struct {
+	Files []string // Files the plan expects to change, create or delete
+	Dirs  []string // Directories the plan focuses on
+}
+
+// IsEmpty reports whether the plan names nothing to compare against.
+func (p Plan) IsEmpty() bool {
+	return len(p.Files) == 0 && len(p.Dirs) == 0
+}
+
+// Graph reports the direct dependencies of files. *contextpin.ContextPinner
+// implements it once the changed and planned files have been analyzed.
+type Graph interface {
+	GetDependencies(file string) []string
+	GetDependents(file string) []string
+}
+
+// File is one changed file and its classification.
+type File struct {
+	Path     string
+	Class    Class
+	Reason   string // Why a file is adjacent, e.g. "imported by api/handler.go"
+	Lines    int    // Changed (added or removed) lines
+	Reverted bool   // The change was reverted by Revert
+}
+
+// Report classifies the files a diff changes.
+type Report struct {
+	Files []File
+}
+
+// Classify classifies the files changed in diff against plan. graph may be
+// nil, in which case no file is adjacent by dependency.
+func Classify(diff string, plan Plan, graph Graph) *Report {
+	planned := map[string]bool{}
+	stems := map[string]string{} // test stem -> planned file
+	for _, f := range plan.Files {
+		f = udiff.CleanPath(f)
+		if f == "" {
+			continue
+		}
+		planned[f] = true
+		stems[stem(f)] = f
+	}
+	var dirs []string
+	for _, d := range plan.Dirs {
+		if d = udiff.CleanPath(d); d != "" && d != "." {
+			dirs = append(dirs, d+"/")
+		}
+	}
+
+	report := &Report{}
+	for _, s := range udiff.Parse(diff) {
+		f := File{Path: s.Path, Lines: s.Lines}
+		switch {
+		case planned[s.Path] || inDirs(s.Path, dirs):
+			f.Class = Planned
+		default:
+			f.Class = Unplanned
+			if reason := adjacency(s.Path, planned, graph); reason != "" {
+				f.Class, f.Reason = Adjacent, reason
+			} else if p, ok := stems[stem(s.Path)]; ok && isTest(s.Path) {
+				f.Class, f.Reason = Adjacent, "test of "+p
+			}
+		}
+		report.Files = append(report.Files, f)
+	}
+	return report
+}
+
+// adjacency returns why file is a direct dependency or dependent of a
+// planned file, or "" if it isn't
+func adjacency(file string, planned map[string]bool, graph Graph) string {
+	if graph == nil {
+		return ""
+	}
+	for _, dep := range graph.GetDependencies(file) {
+		if planned[udiff.CleanPath(dep)] {
+			return "imports " + udiff.CleanPath(dep)
+		}
+	}
+	for _, dep := range graph.GetDependents(file) {
+		if planned[udiff.CleanPath(dep)] {
+			return "imported by " + udiff.CleanPath(dep)
+		}
+	}
+	return ""
+}
+
+// Count returns how many files are in class c, reverted or not.
+func (r *Report) Count(c Class) int {
+	n := 0
+	for _, f := range r.Files {
+		if f.Class == c {
+			n++
+		}
+	}
+	return n
+}
+
+// Unplanned returns the unplanned files that have not been reverted.
+func (r *Report) Unplanned() []File {
+	var files []File
+	for _, f := range r.Files {
+		if f.Class == Unplanned && !f.Reverted {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// Issues returns a review issue for each unplanned file that has not been
+// reverted, if there are more than maxUnplanned of them; otherwise nil.
+func (r *Report) Issues(maxUnplanned int) []review.Issue {
+	unplanned := r.Unplanned()
+	if len(unplanned) <= maxUnplanned {
+		return nil
+	}
+
+	issues := make([]review.Issue, len(unplanned))
+	for i, f := range unplanned {
+		issues[i] = review.Issue{
+			Severity:    "major",
+			File:        f.Path,
+			Description: fmt.Sprintf("Unplanned change (%d lines): the plan doesn't include this file and it isn't a dependency of a planned file", f.Lines),
+			Suggestion:  "Revert this change unless the task requires it.",
+			Code:        "scope",
+		}
+	}
+	return issues
+}
+
+// Summary describes the report in one line, e.g.
+// "3 planned, 1 adjacent, 2 unplanned (1 reverted)".
+func (r *Report) Summary() string {
+	reverted := 0
+	for _, f := range r.Files {
+		if f.Reverted {
+			reverted++
+		}
+	}
+	s := fmt.Sprintf("%d planned, %d adjacent, %d unplanned", r.Count(Planned), r.Count(Adjacent), r.Count(Unplanned))
+	if reverted > 0 {
+		s += fmt.Sprintf(" (%d reverted)", reverted)
+	}
+	return s
+}
+
+// Markdown lists the adjacent and unplanned files for a pull request body.
+// It returns "" when there are no changed files.
+func (r *Report) Markdown() string {
+	if len(r.Files) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Changed files: %s\n", r.Summary())
+	for _, f := range r.Files {
+		switch {
+		case f.Class == Adjacent:
+			fmt.Fprintf(&sb, "- Adjacent: `%s` (%s)\n", f.Path, f.Reason)
+		case f.Reverted:
+			fmt.Fprintf(&sb, "- Unplanned, reverted: `%s`\n", f.Path)
+		case f.Class == Unplanned:
+			fmt.Fprintf(&sb, "- Unplanned: `%s` (%d lines)\n", f.Path, f.Lines)
+		}
+	}
+	return sb.String()
+}
+
+// Revert reverts the unplanned files' changes in workDir by applying their
+// part of diff in reverse, and marks them reverted. diff must be the work
+// directory's diff against HEAD. It returns the files it reverted.
+func (r *Report) Revert(ctx context.Context, workDir, diff string) ([]string, error) {
+	var files []string
+	for _, f := range r.Unplanned() {
+		files = append(files, f.Path)
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	patch := FilterDiff(diff, files)
+	// Staged changes must be reverted in the index too
+	if err := gitApplyReverse(ctx, workDir, patch, "--index"); err != nil {
+		if err2 := gitApplyReverse(ctx, workDir, patch); err2 != nil {
+			return nil, err2
+		}
+	}
+
+	reverted := map[string]bool{}
+	for _, f := range files {
+		reverted[f] = true
+	}
+	for i := range r.Files {
+		if reverted[r.Files[i].Path] {
+			r.Files[i].Reverted = true
+		}
+	}
+	return files, nil
+}
+
+// FilterDiff returns the parts of diff that change the given files.
+func FilterDiff(diff string, files []string) string {
+	wanted := map[string]bool{}
+	for _, f := range files {
+		wanted[udiff.CleanPath(f)] = true
+	}
+
+	var sb strings.Builder
+	for _, s := range udiff.Parse(diff) {
+		if wanted[s.Path] {
+			sb.WriteString(s.Text)
+			if !strings.HasSuffix(s.Text, "\n") {
+				sb.WriteString("\n")
+			}
+		}
+	}
+	return sb.String()
+}
+
+func gitApplyReverse(ctx context.Context, dir, patch string, flags ...string) error {
+	args := append([]string{"apply", "-R", "--whitespace=nowarn"}, flags...)
+	cmd := exec.CommandContext(ctx, "git", append(args, "-")...)
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(patch)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to revert unplanned changes: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func inDirs(file string, dirs []string) bool {
+	for _, d := range dirs {
+		if strings.HasPrefix(file, d) {
+			return true
+		}
+	}
+	return false
+}
+
+// testAffixes are the markers that make a file name a test's
+var testAffixes = []string{"_test", ".test", ".spec", "_spec", "test_"}
+
+func isTest(file string) bool {
+	base := path.Base(file)
+	base = strings.TrimSuffix(base, path.Ext(base))
+	for _, a := range testAffixes {
+		if strings.HasSuffix(base, a) || (a == "test_" && strings.HasPrefix(base, a)) {
+			return true
+		}
+	}
+	return false
+}
+
+// stem is a file's base name without its extension or test affixes, so a
+// test and the file it tests share a stem
+func stem(file string) string {
+	base := path.Base(file)
+	base = strings.TrimSuffix(base, path.Ext(base))
+	for _, a := range testAffixes {
+		if a == "test_" {
+			base = strings.TrimPrefix(base, a)
+		} else {
+			base = strings.TrimSuffix(base, a)
+		}
+	}
+	return base
+}
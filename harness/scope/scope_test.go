@@ -0,0 +1,153 @@
+package scope
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type stubGraph struct {
+	deps       map[string][]string
+	dependents map[string][]string
+}
+
+func (g stubGraph) GetDependencies(file string) []string { return g.deps[file] }
+func (g stubGraph) GetDependents(file string) []string   { return g.dependents[file] }
+
+// fileDiff returns a git diff section that adds n lines to path
+func fileDiff(path string, n int) string {
+	var sb strings.Builder
+	sb.WriteString("diff --git a/" + path + " b/" + path + "\n")
+	sb.WriteString("--- a/" + path + "\n+++ b/" + path + "\n")
+	sb.WriteString("@@ -1,1 +1,2 @@\n context\n")
+	for i := 0; i < n; i++ {
+		sb.WriteString("+line\n")
+	}
+	return sb.String()
+}
+
+func TestClassify(t *testing.T) {
+	diff := fileDiff("api/handler.go", 10) +
+		fileDiff("api/handler_test.go", 5) +
+		fileDiff("auth/token.go", 3) +
+		fileDiff("web/src/login.tsx", 4) +
+		fileDiff("billing/invoice.go", 2) +
+		"diff --git a/old/legacy.go b/old/legacy.go\ndeleted file mode 100644\n--- a/old/legacy.go\n+++ /dev/null\n@@ -1,2 +0,0 @@\n-package old\n-var x = 1\n"
+	plan := Plan{
+		Files: []string{"./api/handler.go", "old/legacy.go"},
+		Dirs:  []string{"web/src/"},
+	}
+	graph := stubGraph{deps: map[string][]string{"auth/token.go": nil}, dependents: map[string][]string{"auth/token.go": {"api/handler.go"}}}
+
+	report := Classify(diff, plan, graph)
+
+	want := []File{
+		{Path: "api/handler.go", Class: Planned, Lines: 10},
+		{Path: "api/handler_test.go", Class: Adjacent, Reason: "test of api/handler.go", Lines: 5},
+		{Path: "auth/token.go", Class: Adjacent, Reason: "imported by api/handler.go", Lines: 3},
+		{Path: "web/src/login.tsx", Class: Planned, Lines: 4},
+		{Path: "billing/invoice.go", Class: Unplanned, Lines: 2},
+		{Path: "old/legacy.go", Class: Planned, Lines: 2},
+	}
+	if !reflect.DeepEqual(report.Files, want) {
+		t.Errorf("Classify() =\n%+v\nwant\n%+v", report.Files, want)
+	}
+	if got := report.Summary(); got != "3 planned, 2 adjacent, 1 unplanned" {
+		t.Errorf("Summary() = %q", got)
+	}
+}
+
+func TestIssuesThreshold(t *testing.T) {
+	report := Classify(fileDiff("a.go", 1)+fileDiff("b.go", 1)+fileDiff("planned.go", 1), Plan{Files: []string{"planned.go"}}, nil)
+
+	if issues := report.Issues(2); issues != nil {
+		t.Errorf("Expected no issues at the threshold, got %v", issues)
+	}
+	issues := report.Issues(1)
+	if len(issues) != 2 || issues[0].File != "a.go" || issues[0].Severity != "major" || issues[0].Code != "scope" {
+		t.Errorf("Expected an issue per unplanned file, got %+v", issues)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	report := &Report{Files: []File{
+		{Path: "api/handler.go", Class: Planned},
+		{Path: "auth/token.go", Class: Adjacent, Reason: "imported by api/handler.go"},
+		{Path: "billing/invoice.go", Class: Unplanned, Lines: 2},
+		{Path: "README.md", Class: Unplanned, Lines: 1, Reverted: true},
+	}}
+
+	want := "Changed files: 1 planned, 1 adjacent, 2 unplanned (1 reverted)\n" +
+		"- Adjacent: `auth/token.go` (imported by api/handler.go)\n" +
+		"- Unplanned: `billing/invoice.go` (2 lines)\n" +
+		"- Unplanned, reverted: `README.md`\n"
+	if got := report.Markdown(); got != want {
+		t.Errorf("Markdown() =\n%s\nwant\n%s", got, want)
+	}
+	if (&Report{}).Markdown() != "" {
+		t.Error("Expected no markdown for an empty report")
+	}
+}
+
+func TestFilterDiff(t *testing.T) {
+	diff := fileDiff("a.go", 1) + fileDiff("b.go", 2)
+	if got := FilterDiff(diff, []string{"b.go"}); got != fileDiff("b.go", 2) {
+		t.Errorf("FilterDiff() = %q", got)
+	}
+}
+
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return string(out)
+}
+
+func TestRevert(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git(t, dir, "init", "-q")
+	git(t, dir, "config", "user.email", "test@example.com")
+	git(t, dir, "config", "user.name", "Test")
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("planned.go", "package app\n")
+	write("other.go", "package app\n")
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-qm", "initial")
+
+	write("planned.go", "package app\n\nvar A = 1\n")
+	write("other.go", "package app\n\nvar B = 2\n")
+	write("extra.go", "package app\n")
+	git(t, dir, "add", ".")
+	diff := git(t, dir, "diff", "HEAD")
+
+	report := Classify(diff, Plan{Files: []string{"planned.go"}}, nil)
+	reverted, err := report.Revert(context.Background(), dir, diff)
+	if err != nil {
+		t.Fatalf("Revert() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(reverted, []string{"extra.go", "other.go"}) {
+		t.Errorf("Expected the unplanned files to be reverted, got %v", reverted)
+	}
+	if got := git(t, dir, "diff", "HEAD", "--name-only"); got != "planned.go\n" {
+		t.Errorf("Expected only the planned change to remain, got %q", got)
+	}
+	if len(report.Unplanned()) != 0 || report.Issues(0) != nil {
+		t.Error("Expected reverted files not to count as unplanned")
+	}
+}